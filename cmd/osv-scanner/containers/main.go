@@ -0,0 +1,151 @@
+package containers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/google/osv-scanner/internal/output"
+	"github.com/google/osv-scanner/pkg/osvscanner"
+	"github.com/google/osv-scanner/pkg/reporter"
+	"golang.org/x/term"
+
+	"github.com/urfave/cli/v2"
+)
+
+func Command(stdout, stderr io.Writer, r *reporter.Reporter) *cli.Command {
+	return &cli.Command{
+		Name:        "containers",
+		Usage:       "[EXPERIMENTAL] scans the containers that are running on this host",
+		Description: "[EXPERIMENTAL] lists the containers that are running through the Docker daemon or containerd, and scans the packages of the operating system and of the languages of the artifacts in the filesystem of each of them",
+		Flags: []cli.Flag{
+			&cli.StringSliceFlag{
+				Name:  "runtime",
+				Usage: fmt.Sprintf("the container runtimes to scan the running containers of; value can be: %s", strings.Join(osvscanner.ContainerRuntimes(), ", ")),
+				Value: cli.NewStringSlice("docker"),
+				Action: func(context *cli.Context, runtimes []string) error {
+					for _, runtime := range runtimes {
+						if !slices.Contains(osvscanner.ContainerRuntimes(), runtime) {
+							return fmt.Errorf("unsupported container runtime \"%s\" - must be one of: %s", runtime, strings.Join(osvscanner.ContainerRuntimes(), ", "))
+						}
+					}
+
+					return nil
+				},
+			},
+			&cli.StringFlag{
+				Name:      "config",
+				Usage:     "set/override config file",
+				TakesFile: true,
+			},
+			&cli.StringFlag{
+				Name:    "format",
+				Aliases: []string{"f"},
+				Usage:   fmt.Sprintf("sets the output format; value can be: %s", strings.Join(reporter.Format(), ", ")),
+				Value:   "table",
+				Action: func(context *cli.Context, s string) error {
+					if slices.Contains(reporter.Format(), s) {
+						return nil
+					}
+
+					return fmt.Errorf("unsupported output format \"%s\" - must be one of: %s", s, strings.Join(reporter.Format(), ", "))
+				},
+			},
+			&cli.StringFlag{
+				Name:      "output",
+				Usage:     "saves the result to the given file path",
+				TakesFile: true,
+			},
+			&cli.StringFlag{
+				Name:  "verbosity",
+				Usage: fmt.Sprintf("specify the level of information that should be provided during runtime; value can be: %s", strings.Join(reporter.VerbosityLevels(), ", ")),
+				Value: "info",
+			},
+			&cli.BoolFlag{
+				Name:  "experimental-local-db",
+				Usage: "checks for vulnerabilities using local databases",
+			},
+			&cli.BoolFlag{
+				Name:  "experimental-offline",
+				Usage: "checks for vulnerabilities using local databases that are already cached",
+			},
+			&cli.StringFlag{
+				Name:   "experimental-local-db-path",
+				Usage:  "sets the path that local databases should be stored",
+				Hidden: true,
+			},
+			&cli.BoolFlag{
+				Name:  "experimental-all-packages",
+				Usage: "when json output is selected, prints all packages",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			var err error
+			*r, err = action(c, stdout, stderr)
+
+			return err
+		},
+	}
+}
+
+func action(context *cli.Context, stdout, stderr io.Writer) (reporter.Reporter, error) {
+	format := context.String("format")
+
+	termWidth := 0
+	var err error
+	if outputPath := context.String("output"); outputPath != "" { // Output is definitely a file
+		stdout, err = os.Create(outputPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create output file: %w", err)
+		}
+	} else if stdoutAsFile, ok := stdout.(*os.File); ok { // Output might be a terminal
+		termWidth, _, err = term.GetSize(int(stdoutAsFile.Fd()))
+		if err != nil { // If output is not a terminal,
+			termWidth = 0
+		}
+	}
+
+	verbosityLevel, err := reporter.ParseVerbosityLevel(context.String("verbosity"))
+	if err != nil {
+		return nil, err
+	}
+	r, err := reporter.New(format, stdout, stderr, verbosityLevel, termWidth)
+	if err != nil {
+		return r, err
+	}
+
+	if context.NArg() != 0 {
+		return r, fmt.Errorf(
+			"expected no arguments, as every running container is scanned, but got %d %s",
+			context.NArg(),
+			output.Form(context.NArg(), "argument", "arguments"),
+		)
+	}
+
+	vulnResult, err := osvscanner.DoScan(osvscanner.ScannerActions{
+		ConfigOverridePath: context.String("config"),
+		ExperimentalScannerActions: osvscanner.ExperimentalScannerActions{
+			LocalDBPath:    context.String("experimental-local-db-path"),
+			CompareLocally: context.Bool("experimental-local-db"),
+			CompareOffline: context.Bool("experimental-offline"),
+			// SBOMs should include every package, not just those with vulnerabilities
+			ShowAllPackages: context.Bool("experimental-all-packages") ||
+				slices.Contains([]string{"cyclonedx", "spdx-json", "spdx-tag-value"}, format),
+			ContainerRuntimes: context.StringSlice("runtime"),
+		},
+	}, r)
+
+	if err != nil && !errors.Is(err, osvscanner.VulnerabilitiesFoundErr) {
+		return r, err
+	}
+
+	if errPrint := r.PrintResult(&vulnResult); errPrint != nil {
+		return r, fmt.Errorf("failed to write output: %w", errPrint)
+	}
+
+	// This may be nil.
+	return r, err
+}
@@ -0,0 +1,177 @@
+package diff
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/google/osv-scanner/internal/ci"
+	"github.com/google/osv-scanner/internal/output"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/osvscanner"
+	"github.com/google/osv-scanner/pkg/reporter"
+	"golang.org/x/term"
+
+	"github.com/urfave/cli/v2"
+)
+
+var formats = []string{"table", "json"}
+
+func Command(stdout, stderr io.Writer, r *reporter.Reporter) *cli.Command {
+	return &cli.Command{
+		Name:        "diff",
+		Usage:       "[EXPERIMENTAL] compares the packages and vulnerabilities of two SBOMs or project directories",
+		Description: "[EXPERIMENTAL] compares the packages and vulnerabilities of two SBOMs or project directories, reporting the packages that were added, removed, or changed and the vulnerabilities that were introduced or fixed",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:      "config",
+				Usage:     "set/override config file",
+				TakesFile: true,
+			},
+			&cli.StringFlag{
+				Name:    "format",
+				Aliases: []string{"f"},
+				Usage:   fmt.Sprintf("sets the output format; value can be: %s", strings.Join(formats, ", ")),
+				Value:   "table",
+				Action: func(context *cli.Context, s string) error {
+					if slices.Contains(formats, s) {
+						return nil
+					}
+
+					return fmt.Errorf("unsupported output format \"%s\" - must be one of: %s", s, strings.Join(formats, ", "))
+				},
+			},
+			&cli.StringFlag{
+				Name:      "output",
+				Usage:     "saves the result to the given file path",
+				TakesFile: true,
+			},
+			&cli.BoolFlag{
+				Name:    "recursive",
+				Aliases: []string{"r"},
+				Usage:   "check subdirectories of directories",
+				Value:   false,
+			},
+			&cli.StringFlag{
+				Name:  "verbosity",
+				Usage: fmt.Sprintf("specify the level of information that should be provided during runtime; value can be: %s", strings.Join(reporter.VerbosityLevels(), ", ")),
+				Value: "info",
+			},
+			&cli.BoolFlag{
+				Name:  "experimental-local-db",
+				Usage: "checks for vulnerabilities using local databases",
+			},
+			&cli.BoolFlag{
+				Name:  "experimental-offline",
+				Usage: "checks for vulnerabilities using local databases that are already cached",
+			},
+			&cli.StringFlag{
+				Name:   "experimental-local-db-path",
+				Usage:  "sets the path that local databases should be stored",
+				Hidden: true,
+			},
+		},
+		ArgsUsage: "<old sbom or directory> <new sbom or directory>",
+		Action: func(c *cli.Context) error {
+			var err error
+			*r, err = action(c, stdout, stderr)
+
+			return err
+		},
+	}
+}
+
+// scan scans the packages of an SBOM, or of a directory if the path is one
+func scan(context *cli.Context, path string, r reporter.Reporter) (models.VulnerabilityResults, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return models.VulnerabilityResults{}, fmt.Errorf("could not scan %s: %w", path, err)
+	}
+
+	actions := osvscanner.ScannerActions{
+		Recursive:          context.Bool("recursive"),
+		ConfigOverridePath: context.String("config"),
+		// The commit that a repository is at is not one of its packages
+		SkipGit: true,
+		ExperimentalScannerActions: osvscanner.ExperimentalScannerActions{
+			LocalDBPath:    context.String("experimental-local-db-path"),
+			CompareLocally: context.Bool("experimental-local-db"),
+			CompareOffline: context.Bool("experimental-offline"),
+			// Packages without vulnerabilities can still be added, removed, or changed
+			ShowAllPackages: true,
+		},
+	}
+
+	if info.IsDir() {
+		actions.DirectoryPaths = []string{path}
+	} else {
+		actions.SBOMPaths = []string{path}
+	}
+
+	vulnResult, err := osvscanner.DoScan(actions, r)
+	if err != nil && !errors.Is(err, osvscanner.VulnerabilitiesFoundErr) {
+		return vulnResult, err
+	}
+
+	return vulnResult, nil
+}
+
+func action(context *cli.Context, stdout, stderr io.Writer) (reporter.Reporter, error) {
+	verbosityLevel, err := reporter.ParseVerbosityLevel(context.String("verbosity"))
+	if err != nil {
+		return nil, err
+	}
+
+	// Diagnostics are written to stderr, to not interfere with the diff written to stdout
+	r := reporter.NewJSONReporter(stdout, stderr, verbosityLevel)
+
+	if context.NArg() != 2 {
+		return r, fmt.Errorf(
+			"expected an old and a new SBOM or directory to compare, but got %d %s",
+			context.NArg(),
+			output.Form(context.NArg(), "argument", "arguments"),
+		)
+	}
+
+	oldVulns, err := scan(context, context.Args().Get(0), r)
+	if err != nil {
+		return r, err
+	}
+
+	newVulns, err := scan(context, context.Args().Get(1), r)
+	if err != nil {
+		return r, err
+	}
+
+	termWidth := 0
+	if outputPath := context.String("output"); outputPath != "" { // Output is definitely a file
+		stdout, err = os.Create(outputPath)
+		if err != nil {
+			return r, fmt.Errorf("failed to create output file: %w", err)
+		}
+	} else if stdoutAsFile, ok := stdout.(*os.File); ok { // Output might be a terminal
+		termWidth, _, err = term.GetSize(int(stdoutAsFile.Fd()))
+		if err != nil { // If output is not a terminal,
+			termWidth = 0
+		}
+	}
+
+	diff := ci.DiffPackages(oldVulns, newVulns)
+
+	if context.String("format") == "json" {
+		if errPrint := output.PrintPackageDiffJSON(diff, stdout); errPrint != nil {
+			return r, fmt.Errorf("failed to write output: %w", errPrint)
+		}
+	} else {
+		output.PrintPackageDiffTable(diff, stdout, termWidth)
+	}
+
+	if len(diff.IntroducedVulns) > 0 {
+		return r, osvscanner.VulnerabilitiesFoundErr
+	}
+
+	return r, nil
+}
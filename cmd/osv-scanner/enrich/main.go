@@ -0,0 +1,117 @@
+package enrich
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/google/osv-scanner/internal/output"
+	"github.com/google/osv-scanner/pkg/osvscanner"
+	"github.com/google/osv-scanner/pkg/reporter"
+
+	"github.com/urfave/cli/v2"
+)
+
+func Command(stdout, stderr io.Writer, r *reporter.Reporter) *cli.Command {
+	return &cli.Command{
+		Name:        "enrich",
+		Usage:       "[EXPERIMENTAL] scans an SBOM and writes it back with the vulnerabilities of its packages embedded in it",
+		Description: "[EXPERIMENTAL] scans the packages of a CycloneDX BOM or SPDX 2 document, and writes the same document back with the vulnerabilities that were found embedded in it",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:      "config",
+				Usage:     "set/override config file",
+				TakesFile: true,
+			},
+			&cli.StringFlag{
+				Name:      "output",
+				Usage:     "saves the enriched SBOM to the given file path",
+				TakesFile: true,
+			},
+			&cli.StringFlag{
+				Name:  "verbosity",
+				Usage: fmt.Sprintf("specify the level of information that should be provided during runtime; value can be: %s", strings.Join(reporter.VerbosityLevels(), ", ")),
+				Value: "info",
+			},
+			&cli.BoolFlag{
+				Name:  "experimental-local-db",
+				Usage: "checks for vulnerabilities using local databases",
+			},
+			&cli.BoolFlag{
+				Name:  "experimental-offline",
+				Usage: "checks for vulnerabilities using local databases that are already cached",
+			},
+			&cli.StringFlag{
+				Name:   "experimental-local-db-path",
+				Usage:  "sets the path that local databases should be stored",
+				Hidden: true,
+			},
+		},
+		ArgsUsage: "<sbom>",
+		Action: func(c *cli.Context) error {
+			var err error
+			*r, err = action(c, stdout, stderr)
+
+			return err
+		},
+	}
+}
+
+func action(context *cli.Context, stdout, stderr io.Writer) (reporter.Reporter, error) {
+	verbosityLevel, err := reporter.ParseVerbosityLevel(context.String("verbosity"))
+	if err != nil {
+		return nil, err
+	}
+
+	// Diagnostics are written to stderr, to not interfere with the SBOM written to stdout
+	r := reporter.NewJSONReporter(stdout, stderr, verbosityLevel)
+
+	if context.NArg() != 1 {
+		return r, fmt.Errorf(
+			"expected one SBOM to enrich, but got %d %s",
+			context.NArg(),
+			output.Form(context.NArg(), "argument", "arguments"),
+		)
+	}
+
+	path := context.Args().First()
+
+	vulnResult, err := osvscanner.DoScan(osvscanner.ScannerActions{
+		SBOMPaths:          []string{path},
+		ConfigOverridePath: context.String("config"),
+		ExperimentalScannerActions: osvscanner.ExperimentalScannerActions{
+			LocalDBPath:    context.String("experimental-local-db-path"),
+			CompareLocally: context.Bool("experimental-local-db"),
+			CompareOffline: context.Bool("experimental-offline"),
+		},
+	}, r)
+
+	// The vulnerabilities that were found are embedded in the SBOM rather than failing the command
+	if err != nil && !errors.Is(err, osvscanner.VulnerabilitiesFoundErr) {
+		return r, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return r, err
+	}
+	defer file.Close()
+
+	if outputPath := context.String("output"); outputPath != "" {
+		outputFile, errCreate := os.Create(outputPath)
+		if errCreate != nil {
+			return r, fmt.Errorf("failed to create output file: %w", errCreate)
+		}
+		defer outputFile.Close()
+
+		stdout = outputFile
+	}
+
+	if errEnrich := output.EnrichSBOM(file, &vulnResult, stdout); errEnrich != nil {
+		return r, fmt.Errorf("failed to enrich %s: %w", path, errEnrich)
+	}
+
+	return r, nil
+}
@@ -1,16 +1,26 @@
 package fix
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 
+	"deps.dev/util/resolve"
 	"github.com/google/osv-scanner/internal/remediation"
+	"github.com/google/osv-scanner/internal/remediation/baseimage"
+	"github.com/google/osv-scanner/internal/resolution"
 	"github.com/google/osv-scanner/internal/resolution/client"
+	"github.com/google/osv-scanner/internal/resolution/datasource"
 	"github.com/google/osv-scanner/internal/resolution/lockfile"
 	"github.com/google/osv-scanner/internal/resolution/manifest"
+	"github.com/google/osv-scanner/internal/resolution/util"
+	"github.com/google/osv-scanner/pkg/config"
 	"github.com/google/osv-scanner/pkg/depsdev"
+	lf "github.com/google/osv-scanner/pkg/lockfile"
 	"github.com/google/osv-scanner/pkg/reporter"
 	"github.com/urfave/cli/v2"
 	"golang.org/x/term"
@@ -22,6 +32,13 @@ const (
 	autoModeCategory = "non-interactive options:" // intentionally lowercase to force it to sort after the other categories
 )
 
+var (
+	// ErrUnfixableVulns is returned when some vulnerabilities could not be fixed.
+	ErrUnfixableVulns = errors.New("some vulnerabilities could not be fixed")
+	// ErrStrictUnfixableVulns is returned in strict mode when vulnerabilities at or above the strict severity could not be fixed.
+	ErrStrictUnfixableVulns = errors.New("vulnerabilities at or above the strict severity could not be fixed")
+)
+
 type osvFixOptions struct {
 	remediation.RemediationOptions
 	Client     client.ResolutionClient
@@ -30,6 +47,9 @@ type osvFixOptions struct {
 	Lockfile   string
 	LockfileRW lockfile.LockfileIO
 	RelockCmd  string
+	Reporter   reporter.Reporter
+	// VulnSources is set if the vulnerabilities are found from multiple sources
+	VulnSources client.VulnSourceClient
 }
 
 func Command(stdout, stderr io.Writer, r *reporter.Reporter) *cli.Command {
@@ -50,22 +70,120 @@ func Command(stdout, stderr io.Writer, r *reporter.Reporter) *cli.Command {
 				Usage:     "lockfile to remediate vulnerabilities in",
 				TakesFile: true,
 			},
+			&cli.StringFlag{
+				Name:      "dockerfile",
+				Usage:     "Dockerfile to suggest a newer base image for, to remediate vulnerabilities in OS packages (requires docker and --allow-image-run)",
+				TakesFile: true,
+			},
+			&cli.BoolFlag{
+				Name:  "allow-image-run",
+				Usage: "allow running the Dockerfile's base image and candidate replacement images with docker, to list their OS packages",
+			},
+			&cli.StringSliceFlag{
+				Name:  "build-arg",
+				Usage: "value of a Dockerfile build argument used in the base image, as NAME=VALUE",
+			},
+			&cli.StringFlag{
+				Name:      "config",
+				Usage:     "set/override config file, used for pinned package versions",
+				TakesFile: true,
+			},
 			&cli.StringFlag{
 				Name:  "data-source",
-				Usage: "source to fetch package information from; value can be: deps.dev, native",
+				Usage: "source to fetch package information from; value can be: deps.dev, native, offline",
 				Value: "deps.dev",
 				Action: func(ctx *cli.Context, s string) error {
-					if s != "deps.dev" && s != "native" {
-						return fmt.Errorf("unsupported data-source \"%s\" - must be one of: deps.dev, native", s)
+					if s != "deps.dev" && s != "native" && s != "offline" {
+						return fmt.Errorf("unsupported data-source \"%s\" - must be one of: deps.dev, native, offline", s)
 					}
 
 					return nil
 				},
 			},
+			&cli.StringFlag{
+				Name:      "offline-snapshot",
+				Usage:     "path to the package metadata snapshot to use with --data-source=offline",
+				TakesFile: true,
+			},
+			&cli.StringFlag{
+				Name:      "record",
+				Usage:     "record all package and vulnerability information used to a file, to reproduce the run later with --replay",
+				TakesFile: true,
+			},
+			&cli.StringFlag{
+				Name:      "replay",
+				Usage:     "use only the package and vulnerability information recorded by a previous run with --record",
+				TakesFile: true,
+			},
+			&cli.BoolFlag{
+				Name:  "offline-vulnerabilities",
+				Usage: "checks for vulnerabilities using the local OSV databases that have already been downloaded, instead of the OSV API",
+			},
+			&cli.BoolFlag{
+				Name:  "local-vulnerabilities",
+				Usage: "checks for vulnerabilities using the local OSV databases that have already been downloaded, falling back to the OSV API for ecosystems without one",
+			},
+			&cli.StringFlag{
+				Name:   "local-db-path",
+				Usage:  "sets the path that local databases are stored",
+				Hidden: true,
+			},
+			&cli.StringSliceFlag{
+				Name:  "registry",
+				Usage: "mirror registry to use with --data-source=native, as ECOSYSTEM=URL where ECOSYSTEM is one of: npm, pypi, maven, go, cargo, nuget",
+			},
+			&cli.StringFlag{
+				Name:      "registry-cert",
+				Usage:     "PEM client certificate to authenticate to mirror registries with",
+				TakesFile: true,
+			},
+			&cli.StringFlag{
+				Name:      "registry-key",
+				Usage:     "PEM private key of the --registry-cert client certificate",
+				TakesFile: true,
+			},
+			&cli.StringFlag{
+				Name:      "registry-ca",
+				Usage:     "PEM CA certificates to trust when connecting to mirror registries",
+				TakesFile: true,
+			},
+			&cli.BoolFlag{
+				Name:  "verify-integrity",
+				Usage: "check that the integrity hashes in the lockfile match the registry's published packages before using it, failing if any do not match",
+			},
+			&cli.StringFlag{
+				Name:      "graph-output",
+				Usage:     "write the resolved dependency graph, with vulnerable packages highlighted, to a file instead of remediating; the format is GraphML if the file ends in .graphml, otherwise Graphviz DOT",
+				TakesFile: true,
+			},
+			&cli.StringFlag{
+				Name:      "diff-base",
+				Usage:     "compare the dependency graph of the lockfile against this earlier version of it, reporting the changed packages and vulnerabilities instead of remediating",
+				TakesFile: true,
+			},
+			&cli.StringSliceFlag{
+				Name:  "what-if",
+				Usage: "preview the changed packages and vulnerabilities if the manifest required a different version of a direct dependency, as PACKAGE@REQUIREMENT, instead of remediating",
+			},
+			&cli.StringFlag{
+				Name:  "verbosity",
+				Usage: fmt.Sprintf("specify the level of information that should be provided during runtime; value can be: %s", strings.Join(reporter.VerbosityLevels(), ", ")),
+				Value: "info",
+			},
 			&cli.StringFlag{
 				Name:  "relock-cmd",
 				Usage: "command to run to regenerate lockfile on disk after changing the manifest",
 			},
+			&cli.StringFlag{
+				Name:      "batch",
+				Usage:     "root directory of a monorepo to find and remediate all manifests and lockfiles in, printing a consolidated plan",
+				TakesFile: true,
+			},
+			&cli.StringFlag{
+				Name:      "batch-output",
+				Usage:     "directory to write the per-project patch files to in batch mode",
+				TakesFile: true,
+			},
 
 			&cli.BoolFlag{
 				Name:  "non-interactive",
@@ -75,7 +193,7 @@ func Command(stdout, stderr io.Writer, r *reporter.Reporter) *cli.Command {
 			&cli.StringFlag{
 				Category: autoModeCategory,
 				Name:     "strategy",
-				Usage:    "remediation approach to use; value can be: in-place, relock",
+				Usage:    "remediation approach to use; value can be: in-place, relock, override",
 				Value:    "relock",
 				Action: func(ctx *cli.Context, s string) error {
 					if !ctx.Bool("non-interactive") {
@@ -91,19 +209,48 @@ func Command(stdout, stderr io.Writer, r *reporter.Reporter) *cli.Command {
 						if !ctx.IsSet("manifest") {
 							return fmt.Errorf("relock strategy requires manifest file")
 						}
+					case "override":
+						if !ctx.IsSet("manifest") {
+							return fmt.Errorf("override strategy requires manifest file")
+						}
 					default:
-						return fmt.Errorf("unsupported strategy \"%s\" - must be one of: in-place, relock", s)
+						return fmt.Errorf("unsupported strategy \"%s\" - must be one of: in-place, relock, override", s)
 					}
 
 					return nil
 				},
 			},
+			&cli.BoolFlag{
+				Category: autoModeCategory,
+				Name:     "strict",
+				Usage:    "fail if any vulnerabilities at or above the strict-severity cannot be fixed",
+			},
+			&cli.Float64Flag{
+				Category:    autoModeCategory,
+				Name:        "strict-severity",
+				Usage:       "minimum CVSS score of unfixable vulnerabilities to fail on in strict mode; vulnerabilities of unknown severity always fail",
+				Value:       0,
+				DefaultText: "0.0",
+			},
 			&cli.IntFlag{
 				Category: autoModeCategory,
 				Name:     "apply-top",
 				Usage:    "apply the top N patches",
 				Value:    -1,
 			},
+			&cli.StringFlag{
+				Category: autoModeCategory,
+				Name:     "summary-format",
+				Usage:    "format of the before/after vulnerability summary written after applying patches; value can be: text, json",
+				Value:    "text",
+				Action: func(ctx *cli.Context, s string) error {
+					if s != "text" && s != "json" {
+						return fmt.Errorf("unsupported summary-format \"%s\" - must be one of: text, json", s)
+					}
+
+					return nil
+				},
+			},
 
 			&cli.BoolFlag{
 				// TODO: allow for finer control e.g. specific packages, major/minor/patch
@@ -116,6 +263,16 @@ func Command(stdout, stderr io.Writer, r *reporter.Reporter) *cli.Command {
 				Name:     "disallow-package-upgrades",
 				Usage:    "list of packages to disallow version changes",
 			},
+			&cli.BoolFlag{
+				Category: upgradeCategory,
+				Name:     "allow-prerelease",
+				Usage:    "allow upgrading to prerelease versions if no other fix is available",
+			},
+			&cli.BoolFlag{
+				Category: upgradeCategory,
+				Name:     "ignore-engines",
+				Usage:    "allow upgrading to versions with runtime engine requirements (e.g. engines.node) incompatible with the project",
+			},
 
 			&cli.IntFlag{
 				Category: vulnCategory,
@@ -145,39 +302,113 @@ func Command(stdout, stderr io.Writer, r *reporter.Reporter) *cli.Command {
 				Name:     "ignore-dev",
 				Usage:    "ignore vulnerabilities affecting only development dependencies",
 			},
+			&cli.BoolFlag{
+				Category: vulnCategory,
+				Name:     "epss",
+				Usage:    "prioritize fixing vulnerabilities with a high EPSS exploit probability (queries api.first.org)",
+			},
+		},
+		Action: func(ctx *cli.Context) error {
+			return action(ctx, stdout, stderr)
 		},
-		Action: action,
 	}
 }
 
-func action(ctx *cli.Context) error {
+func action(ctx *cli.Context, stdout, stderr io.Writer) (err error) {
+	if ctx.IsSet("dockerfile") {
+		return baseImageAction(ctx, stdout, stderr)
+	}
+
+	batch := ctx.IsSet("batch")
+
 	// The Action on strategy isn't run when using the default values. Check if the manifest is set.
-	if ctx.Bool("non-interactive") && ctx.String("strategy") == "relock" && !ctx.IsSet("manifest") {
+	if !batch && ctx.Bool("non-interactive") && ctx.String("strategy") == "relock" && !ctx.IsSet("manifest") {
 		return fmt.Errorf("relock strategy requires manifest file")
 	}
 
-	if !ctx.IsSet("manifest") && !ctx.IsSet("lockfile") {
+	if !batch && !ctx.IsSet("manifest") && !ctx.IsSet("lockfile") {
 		return fmt.Errorf("manifest or lockfile is required")
 	}
 
+	verbosityLevel, err := reporter.ParseVerbosityLevel(ctx.String("verbosity"))
+	if err != nil {
+		return err
+	}
+
 	opts := osvFixOptions{
 		RemediationOptions: remediation.RemediationOptions{
-			IgnoreVulns:   ctx.StringSlice("ignore-vulns"),
-			ExplicitVulns: ctx.StringSlice("vulns"),
-			DevDeps:       !ctx.Bool("ignore-dev"),
-			MinSeverity:   ctx.Float64("min-severity"),
-			MaxDepth:      ctx.Int("max-depth"),
-			AvoidPkgs:     ctx.StringSlice("disallow-package-upgrades"),
-			AllowMajor:    !ctx.Bool("disallow-major-upgrades"),
+			IgnoreVulns:     ctx.StringSlice("ignore-vulns"),
+			ExplicitVulns:   ctx.StringSlice("vulns"),
+			DevDeps:         !ctx.Bool("ignore-dev"),
+			MinSeverity:     ctx.Float64("min-severity"),
+			MaxDepth:        ctx.Int("max-depth"),
+			AvoidPkgs:       ctx.StringSlice("disallow-package-upgrades"),
+			AllowMajor:      !ctx.Bool("disallow-major-upgrades"),
+			AllowPrerelease: ctx.Bool("allow-prerelease"),
+			IgnoreEngines:   ctx.Bool("ignore-engines"),
+			VerifyIntegrity: ctx.Bool("verify-integrity"),
 		},
 		Manifest:  ctx.String("manifest"),
 		Lockfile:  ctx.String("lockfile"),
 		RelockCmd: ctx.String("relock-cmd"),
+		// Diagnostics are written to stderr, to not interfere with the plan written to stdout
+		Reporter: reporter.NewJSONReporter(stdout, stderr, verbosityLevel),
 		Client: client.ResolutionClient{
 			VulnerabilityClient: client.NewOSVClient(),
 		},
 	}
 
+	switch {
+	case ctx.Bool("offline-vulnerabilities"):
+		opts.Client.VulnerabilityClient = client.NewOSVOfflineClient(ctx.String("local-db-path"))
+	case ctx.Bool("local-vulnerabilities"):
+		hybrid := client.NewOSVHybridClient(client.NewOSVOfflineClient(ctx.String("local-db-path")), opts.Client.VulnerabilityClient)
+		opts.Client.VulnerabilityClient = hybrid
+		opts.VulnSources = hybrid
+	}
+
+	var workDir string
+	// Prefer to use the manifest's directory if available.
+	switch {
+	case batch:
+		workDir = ctx.String("batch")
+	case opts.Manifest != "":
+		workDir = filepath.Dir(opts.Manifest)
+	default:
+		workDir = filepath.Dir(opts.Lockfile)
+	}
+
+	if !opts.IgnoreEngines && !batch {
+		// The project's runtime engine requirements are read from its package.json, if it has one
+		// TODO: determine ecosystem from manifest/lockfile
+		engines, diag, ok := remediation.ProjectEngines(workDir)
+		opts.Engines = engines
+		if !ok {
+			opts.Reporter.Warnf("%s\n", diag)
+		}
+	}
+
+	// Pinned package versions are read from the osv-scanner config file
+	configManager := config.ConfigManager{
+		DefaultConfig: config.Config{},
+		ConfigMap:     make(map[string]config.Config),
+	}
+	if ctx.IsSet("config") {
+		if err := configManager.UseOverride(ctx.String("config")); err != nil {
+			return fmt.Errorf("failed to read config file: %w", err)
+		}
+	}
+	cfg := configManager.Get(&reporter.VoidReporter{}, workDir)
+	opts.PinnedVersions = cfg.PinnedVersionConstraints()
+	if err := remediation.CheckPins(opts.PinnedVersions); err != nil {
+		return fmt.Errorf("invalid config file: %w", err)
+	}
+
+	registries, err := registryConfigs(ctx)
+	if err != nil {
+		return err
+	}
+
 	switch ctx.String("data-source") {
 	case "deps.dev":
 		cl, err := client.NewDepsDevClient(depsdev.DepsdevAPI)
@@ -186,21 +417,53 @@ func action(ctx *cli.Context) error {
 		}
 		opts.Client.DependencyClient = cl
 	case "native":
-		// TODO: determine ecosystem & client from manifest/lockfile
-		var workDir string
-		// Prefer to use the manifest's directory if available.
-		if opts.Manifest != "" {
-			workDir = filepath.Dir(opts.Manifest)
+		var cl client.DependencyClient
+		if batch {
+			// Projects of any ecosystem can be found in batch mode, so use the registry of each package's ecosystem
+			cl, err = nativeSystemClient(workDir, registries)
 		} else {
-			workDir = filepath.Dir(opts.Lockfile)
+			cl, err = nativeClient(workDir, opts.Manifest, opts.Lockfile, registries)
+		}
+		if err != nil {
+			return err
+		}
+		opts.Client.DependencyClient = cl
+	case "offline":
+		if !ctx.IsSet("offline-snapshot") {
+			return errors.New("--offline-snapshot is required with --data-source=offline")
 		}
-		cl, err := client.NewNpmRegistryClient(workDir)
+		cl, err := client.NewOfflineClient(ctx.String("offline-snapshot"))
 		if err != nil {
 			return err
 		}
 		opts.Client.DependencyClient = cl
 	}
 
+	switch {
+	case ctx.IsSet("replay"):
+		rc, err := client.NewReplayClient(ctx.String("replay"))
+		if err != nil {
+			return err
+		}
+		opts.Client = rc.ResolutionClient()
+	case ctx.IsSet("record"):
+		rec := client.NewRecordClient(opts.Client)
+		opts.Client = rec.ResolutionClient()
+		defer func() {
+			if saveErr := rec.Save(ctx.String("record")); saveErr != nil && err == nil {
+				err = fmt.Errorf("failed to save recording: %w", saveErr)
+			}
+		}()
+	}
+
+	if ctx.Bool("epss") {
+		opts.Client.VulnerabilityClient = client.NewEPSSClient(opts.Client.VulnerabilityClient)
+	}
+
+	if batch {
+		return batchAction(ctx, stdout, opts)
+	}
+
 	if opts.Manifest != "" {
 		rw, err := manifest.GetManifestIO(opts.Manifest)
 		if err != nil {
@@ -214,8 +477,414 @@ func action(ctx *cli.Context) error {
 		if err != nil {
 			return err
 		}
-		opts.LockfileRW = rw
+		// Patched versions are fetched from the same registry mirror as the rest of the fix
+		opts.LockfileRW = lockfile.WithNpmRegistry(rw, registries["npm"])
+		if opts.VerifyIntegrity {
+			if err := verifyIntegrity(ctx, opts, opts.Lockfile); err != nil {
+				return err
+			}
+		}
+	}
+
+	if ctx.IsSet("graph-output") {
+		return graphAction(ctx, opts)
+	}
+
+	if ctx.IsSet("diff-base") {
+		return diffAction(ctx, stdout, opts)
+	}
+
+	if ctx.IsSet("what-if") {
+		return whatIfAction(ctx, stdout, opts)
+	}
+
+	if !ctx.Bool("non-interactive") {
+		// TODO: interactive mode
+		return fmt.Errorf("not implemented")
+	}
+
+	return autoAction(ctx, stdout, opts)
+}
+
+// graphAction resolves the dependency graph of the manifest, or reads it from the lockfile,
+// and writes it to the graph output file.
+func graphAction(ctx *cli.Context, opts osvFixOptions) error {
+	var res *resolution.ResolutionResult
+	if opts.Lockfile != "" {
+		f, err := lf.OpenLocalDepFile(opts.Lockfile)
+		if err != nil {
+			return err
+		}
+		g, err := opts.LockfileRW.Read(f)
+		f.Close()
+		if err != nil {
+			return err
+		}
+		res, err = resolution.ResolveGraph(ctx.Context, opts.Client, g)
+		if err != nil {
+			return err
+		}
+	} else {
+		f, err := lf.OpenLocalDepFile(opts.Manifest)
+		if err != nil {
+			return err
+		}
+		m, err := opts.ManifestRW.Read(f)
+		f.Close()
+		if err != nil {
+			return err
+		}
+		opts.Client.PreFetch(ctx.Context, m.Requirements, opts.Manifest)
+		res, err = resolution.Resolve(ctx.Context, opts.Client, m)
+		if err != nil {
+			return err
+		}
+	}
+	res.FilterVulns(opts.MatchVuln)
+	for _, d := range res.Diagnostics {
+		opts.Reporter.Verbosef("%s\n", d)
+	}
+	reportVulnSources(opts, res)
+
+	path := ctx.String("graph-output")
+	out, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	if filepath.Ext(path) == ".graphml" {
+		return res.WriteGraphML(out)
+	}
+
+	return res.WriteDOT(out)
+}
+
+// diffAction compares the dependency graphs and vulnerabilities of the diff base and the lockfile,
+// writing the differences to stdout.
+func diffAction(ctx *cli.Context, stdout io.Writer, opts osvFixOptions) error {
+	if opts.Lockfile == "" {
+		return errors.New("--diff-base requires --lockfile")
+	}
+
+	readLockfile := func(path string) (*resolution.ResolutionResult, error) {
+		f, err := lf.OpenLocalDepFile(path)
+		if err != nil {
+			return nil, err
+		}
+		g, err := opts.LockfileRW.Read(f)
+		f.Close()
+		if err != nil {
+			return nil, err
+		}
+		res, err := resolution.ResolveGraph(ctx.Context, opts.Client, g)
+		if err != nil {
+			return nil, err
+		}
+		res.FilterVulns(opts.MatchVuln)
+		for _, d := range res.Diagnostics {
+			opts.Reporter.Verbosef("%s: %s\n", path, d)
+		}
+		reportVulnSources(opts, res)
+
+		return res, nil
+	}
+
+	if opts.VerifyIntegrity {
+		if err := verifyIntegrity(ctx, opts, ctx.String("diff-base")); err != nil {
+			return err
+		}
+	}
+	base, err := readLockfile(ctx.String("diff-base"))
+	if err != nil {
+		return err
+	}
+	head, err := readLockfile(opts.Lockfile)
+	if err != nil {
+		return err
+	}
+
+	return base.DiffGraphs(head).WriteJSON(stdout)
+}
+
+// whatIfAction resolves the manifest with the --what-if requirement changes applied,
+// writing the differences to the current resolution to stdout.
+func whatIfAction(ctx *cli.Context, stdout io.Writer, opts osvFixOptions) error {
+	if opts.Manifest == "" {
+		return errors.New("--what-if requires --manifest")
+	}
+
+	f, err := lf.OpenLocalDepFile(opts.Manifest)
+	if err != nil {
+		return err
+	}
+	m, err := opts.ManifestRW.Read(f)
+	f.Close()
+	if err != nil {
+		return err
+	}
+	changes, err := parseWhatIf(ctx.StringSlice("what-if"), m.Root.System)
+	if err != nil {
+		return err
+	}
+
+	opts.Client.PreFetch(ctx.Context, m.Requirements, opts.Manifest)
+	res, err := resolution.Resolve(ctx.Context, opts.Client, m)
+	if err != nil {
+		return err
+	}
+	diff, err := remediation.PreviewChanges(ctx.Context, opts.Client, res, changes, opts.RemediationOptions)
+	if err != nil {
+		return err
+	}
+	for _, d := range diff.New.Diagnostics {
+		opts.Reporter.Verbosef("%s\n", d)
+	}
+
+	return diff.Original.DiffGraphs(diff.New).WriteJSON(stdout)
+}
+
+// parseWhatIf parses the --what-if values into requirement changes of the manifest's direct dependencies.
+func parseWhatIf(specs []string, sys resolve.System) ([]remediation.RequirementChange, error) {
+	changes := make([]remediation.RequirementChange, 0, len(specs))
+	for _, spec := range specs {
+		// the name of a scoped npm package also starts with an @
+		idx := strings.LastIndex(spec, "@")
+		if idx <= 0 || idx == len(spec)-1 {
+			return nil, fmt.Errorf("invalid --what-if %q - must be PACKAGE@REQUIREMENT", spec)
+		}
+		changes = append(changes, remediation.RequirementChange{
+			Pkg:        resolve.PackageKey{System: sys, Name: spec[:idx]},
+			NewRequire: spec[idx+1:],
+		})
+	}
+
+	return changes, nil
+}
+
+// verifyIntegrity checks the integrity hashes of the lockfile at path against the registry,
+// reporting any mismatched packages as errors.
+func verifyIntegrity(ctx *cli.Context, opts osvFixOptions, path string) error {
+	diags, err := remediation.CheckLockfileIntegrity(ctx.Context, opts.Client.DependencyClient, opts.LockfileRW, path)
+	for _, d := range diags {
+		switch d.Kind { //nolint:exhaustive
+		case resolution.DiagnosticIntegrityMismatch:
+			opts.Reporter.Errorf("%s: %s\n", path, d)
+		case resolution.DiagnosticIntegrityUnverifiable:
+			opts.Reporter.Warnf("%s: %s\n", path, d)
+		default:
+			opts.Reporter.Verbosef("%s: %s\n", path, d)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	return nil
+}
+
+// reportVulnSources reports where each of the vulnerable packages' vulnerabilities were found,
+// if they were found from multiple sources.
+func reportVulnSources(opts osvFixOptions, res *resolution.ResolutionResult) {
+	if opts.VulnSources == nil {
+		return
+	}
+	for _, v := range res.Vulns {
+		var reported []resolve.VersionKey
+		for _, chain := range append(slices.Clone(v.ProblemChains), v.NonProblemChains...) {
+			vk, _ := chain.EndDependency()
+			if slices.Contains(reported, vk) {
+				continue
+			}
+			reported = append(reported, vk)
+			if src, ok := opts.VulnSources.VulnSource(vk); ok {
+				opts.Reporter.Verbosef("%s in %s@%s: found in %s\n", v.Vulnerability.ID, vk.Name, vk.Version, src)
+			}
+		}
+	}
+}
+
+// registryConfigs parses the --registry flags into the registry configuration of each ecosystem.
+// The TLS options apply to all registries, including the default public ones.
+func registryConfigs(ctx *cli.Context) (map[string]datasource.RegistryConfig, error) {
+	base := datasource.RegistryConfig{
+		CertFile: ctx.String("registry-cert"),
+		KeyFile:  ctx.String("registry-key"),
+		CAFile:   ctx.String("registry-ca"),
+	}
+	configs := make(map[string]datasource.RegistryConfig)
+	for _, eco := range []string{"npm", "pypi", "maven", "go", "cargo", "nuget"} {
+		configs[eco] = base
+	}
+	for _, r := range ctx.StringSlice("registry") {
+		eco, url, ok := strings.Cut(r, "=")
+		eco = strings.ToLower(eco)
+		if _, known := configs[eco]; !ok || !known || url == "" {
+			return nil, fmt.Errorf("invalid --registry %q - must be ECOSYSTEM=URL where ECOSYSTEM is one of: npm, pypi, maven, go, cargo, nuget", r)
+		}
+		cfg := base
+		cfg.URL = url
+		configs[eco] = cfg
+	}
+
+	return configs, nil
+}
+
+// nativeClient creates the client for the native registry of the manifest or lockfile's ecosystem.
+func nativeClient(workDir, manifestPath, lockfilePath string, registries map[string]datasource.RegistryConfig) (client.DependencyClient, error) {
+	switch {
+	case filepath.Base(manifestPath) == "go.mod":
+		return client.NewGoProxyClient(registries["go"])
+	case filepath.Base(manifestPath) == "Cargo.toml" || filepath.Base(lockfilePath) == "Cargo.lock":
+		return client.NewCratesIndexClient(registries["cargo"])
+	case manifest.IsNuGetProjectFile(manifestPath) || filepath.Base(lockfilePath) == "packages.lock.json":
+		return client.NewNuGetClient(registries["nuget"])
+	case filepath.Base(manifestPath) == "pom.xml":
+		return client.NewMavenRegistryClient(registries["maven"])
+	case filepath.Base(manifestPath) == "requirements.txt" || filepath.Base(manifestPath) == "pyproject.toml":
+		return client.NewPyPIClient(registries["pypi"])
+	default:
+		return client.NewNpmRegistryClient(workDir, registries["npm"])
+	}
+}
+
+// nativeSystemClient creates a client that queries the native registry of each package's ecosystem.
+func nativeSystemClient(workDir string, registries map[string]datasource.RegistryConfig) (client.DependencyClient, error) {
+	clients := make(map[resolve.System]client.DependencyClient)
+	var err error
+	if clients[resolve.NPM], err = client.NewNpmRegistryClient(workDir, registries["npm"]); err != nil {
+		return nil, err
+	}
+	if clients[util.Go], err = client.NewGoProxyClient(registries["go"]); err != nil {
+		return nil, err
+	}
+	if clients[util.Cargo], err = client.NewCratesIndexClient(registries["cargo"]); err != nil {
+		return nil, err
+	}
+	if clients[util.NuGet], err = client.NewNuGetClient(registries["nuget"]); err != nil {
+		return nil, err
+	}
+	if clients[resolve.Maven], err = client.NewMavenRegistryClient(registries["maven"]); err != nil {
+		return nil, err
+	}
+	if clients[util.PyPI], err = client.NewPyPIClient(registries["pypi"]); err != nil {
+		return nil, err
+	}
+
+	return client.NewSystemClient(clients), nil
+}
+
+// batchAction remediates every project found under the batch root directory,
+// writing the consolidated plan to stdout and the per-project plans to the batch output directory.
+func batchAction(ctx *cli.Context, stdout io.Writer, opts osvFixOptions) error {
+	root := ctx.String("batch")
+	projects, err := remediation.DiscoverProjects(root)
+	if err != nil {
+		return err
+	}
+	if len(projects) == 0 {
+		return fmt.Errorf("no manifests or lockfiles found in %s", root)
+	}
+
+	plan := remediation.ComputeBatchPlan(ctx.Context, opts.Client, projects, opts.RemediationOptions)
+	for _, p := range plan.Projects {
+		for _, d := range p.Diagnostics {
+			opts.Reporter.Verbosef("%s: %s\n", p.Dir, d)
+		}
+	}
+	if err := plan.WriteJSON(stdout); err != nil {
+		return err
+	}
+
+	if out := ctx.String("batch-output"); out != "" {
+		if err := plan.WriteProjectFiles(root, out); err != nil {
+			return err
+		}
+	}
+
+	for _, p := range plan.Projects {
+		if p.Error != "" {
+			return fmt.Errorf("failed to remediate %s: %s", p.Dir, p.Error)
+		}
+	}
+
+	var unfixable []float64
+	for _, adv := range plan.Advisories {
+		if len(adv.Unfixable) > 0 {
+			unfixable = append(unfixable, adv.Severity)
+		}
+	}
+
+	return unfixableErr(ctx, unfixable)
+}
+
+// baseImageAction suggests replacements for the base image of a Dockerfile that fix vulnerabilities in its OS packages.
+func baseImageAction(ctx *cli.Context, stdout, stderr io.Writer) error {
+	if !ctx.Bool("allow-image-run") {
+		return errors.New("suggesting a base image runs the current and candidate images with docker to list their packages, pass --allow-image-run to allow this")
+	}
+	buildArgs := make(map[string]string)
+	for _, arg := range ctx.StringSlice("build-arg") {
+		name, value, ok := strings.Cut(arg, "=")
+		if !ok {
+			return fmt.Errorf("invalid --build-arg %q - must be NAME=VALUE", arg)
+		}
+		buildArgs[name] = value
+	}
+
+	f, err := os.Open(ctx.String("dockerfile"))
+	if err != nil {
+		return err
+	}
+	base, err := baseimage.ParseDockerfileBase(f, buildArgs)
+	f.Close()
+	if errors.Is(err, baseimage.ErrScratchBase) {
+		fmt.Fprintf(stdout, "The final stage is built FROM scratch, there is no base image to change\n")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	res, err := baseimage.SuggestCandidates(ctx.Context, baseimage.DockerClient{AllowRun: true}, base)
+	if err != nil {
+		return err
+	}
+	for _, d := range res.Diagnostics {
+		fmt.Fprintf(stderr, "%s\n", d)
+	}
+	candidates := res.Candidates
+	if len(candidates) == 0 {
+		fmt.Fprintf(stdout, "No newer base image found that fixes vulnerabilities in %s\n", base)
+		return nil
+	}
+
+	best := candidates[0]
+	fmt.Fprintf(stdout, "Suggested base image change:\n  FROM %s\n  %s\n", base, best.FromLine())
+	for _, c := range candidates {
+		fmt.Fprintf(stdout, "%s: fixes %d, remaining %d, introduces %d vulnerabilities\n",
+			c.Image, len(c.Eliminated), len(c.Remaining), len(c.Introduced))
+	}
+
+	return nil
+}
+
+// unfixableErr determines the result of the fix command from the severities of the vulnerabilities that could not be fixed.
+// In strict mode, it is ErrStrictUnfixableVulns if any of them are at or above the strict severity.
+func unfixableErr(ctx *cli.Context, unfixableSeverities []float64) error {
+	if len(unfixableSeverities) == 0 {
+		return nil
+	}
+
+	if ctx.Bool("strict") {
+		minSeverity := ctx.Float64("strict-severity")
+		for _, sev := range unfixableSeverities {
+			// unknown severities are negative, and are always considered severe enough to fail on
+			if sev < 0 || sev >= minSeverity {
+				return ErrStrictUnfixableVulns
+			}
+		}
 	}
 
-	return fmt.Errorf("not implemented")
+	return ErrUnfixableVulns
 }
@@ -0,0 +1,91 @@
+package fix
+
+import (
+	"errors"
+	"flag"
+	"reflect"
+	"testing"
+
+	"deps.dev/util/resolve"
+	"github.com/google/osv-scanner/internal/remediation"
+	"github.com/urfave/cli/v2"
+)
+
+func TestUnfixableErr(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		strict     bool
+		severities []float64
+		want       error
+	}{
+		{
+			name:       "nothing unfixable",
+			strict:     true,
+			severities: nil,
+			want:       nil,
+		},
+		{
+			name:       "unfixable without strict mode",
+			strict:     false,
+			severities: []float64{9.8, -1},
+			want:       ErrUnfixableVulns,
+		},
+		{
+			name:       "strict mode with only vulnerabilities below the strict severity",
+			strict:     true,
+			severities: []float64{3.1, 6.9},
+			want:       ErrUnfixableVulns,
+		},
+		{
+			name:       "strict mode with a vulnerability at the strict severity",
+			strict:     true,
+			severities: []float64{3.1, 7.0},
+			want:       ErrStrictUnfixableVulns,
+		},
+		{
+			name:       "strict mode with a vulnerability of unknown severity",
+			strict:     true,
+			severities: []float64{-1},
+			want:       ErrStrictUnfixableVulns,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			set := flag.NewFlagSet("fix", flag.ContinueOnError)
+			set.Bool("strict", tt.strict, "")
+			set.Float64("strict-severity", 7.0, "")
+			ctx := cli.NewContext(cli.NewApp(), set, nil)
+
+			if got := unfixableErr(ctx, tt.severities); !errors.Is(got, tt.want) {
+				t.Errorf("unfixableErr() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseWhatIf(t *testing.T) {
+	t.Parallel()
+
+	got, err := parseWhatIf([]string{"lodash@^4.17.21", "@babel/core@~7.24.0"}, resolve.NPM)
+	if err != nil {
+		t.Fatalf("parseWhatIf() error = %v", err)
+	}
+	want := []remediation.RequirementChange{
+		{Pkg: resolve.PackageKey{System: resolve.NPM, Name: "lodash"}, NewRequire: "^4.17.21"},
+		{Pkg: resolve.PackageKey{System: resolve.NPM, Name: "@babel/core"}, NewRequire: "~7.24.0"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseWhatIf() = %v, want %v", got, want)
+	}
+
+	for _, spec := range []string{"lodash", "@babel/core", "lodash@"} {
+		if _, err := parseWhatIf([]string{spec}, resolve.NPM); err == nil {
+			t.Errorf("parseWhatIf(%q) succeeded, want error", spec)
+		}
+	}
+}
@@ -0,0 +1,364 @@
+package fix
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"deps.dev/util/resolve"
+	"github.com/google/osv-scanner/internal/remediation"
+	"github.com/google/osv-scanner/internal/resolution"
+	"github.com/google/osv-scanner/internal/resolution/client"
+	"github.com/google/osv-scanner/internal/resolution/lockfile"
+	"github.com/google/osv-scanner/internal/resolution/manifest"
+	"github.com/google/osv-scanner/internal/utility/severity"
+	lf "github.com/google/osv-scanner/pkg/lockfile"
+	"github.com/urfave/cli/v2"
+)
+
+// autoResult is the outcome of non-interactive remediation.
+type autoResult struct {
+	Before    []resolution.ResolutionVuln  // vulnerabilities found before applying the patches
+	After     []resolution.ResolutionVuln  // vulnerabilities found after applying the patches
+	Unfixable []resolution.ResolutionVuln  // vulnerabilities that cannot be fixed with the strategy
+	Changes   int                          // number of dependency version changes made
+	Upgrades  []remediation.UpgradeSummary // the version changes made, if they are known
+	EPSS      map[string]float64           // the EPSS scores of the vulnerabilities, if they are known
+}
+
+// autoAction remediates the manifest or lockfile without user input, using the chosen strategy
+// and applying the top --apply-top patches, then writes a summary of the changes to stdout.
+func autoAction(ctx *cli.Context, stdout io.Writer, opts osvFixOptions) error {
+	maxUpgrades := ctx.Int("apply-top")
+
+	var res autoResult
+	var err error
+	switch ctx.String("strategy") {
+	case "in-place":
+		res, err = autoInPlace(ctx.Context, opts, maxUpgrades)
+	case "relock":
+		res, err = autoRelock(ctx.Context, opts, maxUpgrades)
+	case "override":
+		res, err = autoOverride(ctx.Context, opts, maxUpgrades)
+	default:
+		err = fmt.Errorf("unsupported strategy %q", ctx.String("strategy"))
+	}
+	if err != nil {
+		return err
+	}
+
+	summary := remediation.ComputeSummary(res.Before, res.After, res.Changes)
+	summary.Upgrades = res.Upgrades
+	summary.EPSS = res.EPSS
+	if ctx.String("summary-format") == "json" {
+		err = summary.WriteJSON(stdout)
+	} else {
+		err = summary.WriteText(stdout)
+	}
+	if err != nil {
+		return err
+	}
+
+	return unfixableErr(ctx, vulnSeverities(res.Unfixable))
+}
+
+// vulnSeverities returns the highest CVSS score of each vulnerability, or -1 if its severity is unknown.
+func vulnSeverities(vulns []resolution.ResolutionVuln) []float64 {
+	scores := make([]float64, len(vulns))
+	for i, v := range vulns {
+		scores[i] = -1
+		for _, sev := range v.Vulnerability.Severity {
+			if score, _, _ := severity.CalculateScore(sev); score > scores[i] {
+				scores[i] = score
+			}
+		}
+	}
+
+	return scores
+}
+
+// autoInPlace patches the vulnerable versions in the lockfile, without changing the manifest.
+func autoInPlace(ctx context.Context, opts osvFixOptions, maxUpgrades int) (autoResult, error) {
+	opts.Reporter.Infof("Scanning %s...\n", opts.Lockfile)
+	f, err := lf.OpenLocalDepFile(opts.Lockfile)
+	if err != nil {
+		return autoResult{}, err
+	}
+	g, err := opts.LockfileRW.Read(f)
+	f.Close()
+	if err != nil {
+		return autoResult{}, err
+	}
+	before, err := scanLockfile(ctx, opts)
+	if err != nil {
+		return autoResult{}, err
+	}
+
+	res, err := remediation.ComputeInPlacePatches(ctx, opts.Client, g, opts.RemediationOptions)
+	if err != nil {
+		return autoResult{}, err
+	}
+	for _, d := range res.Diagnostics {
+		opts.Reporter.Verbosef("%s\n", d)
+	}
+
+	patches := chooseInPlacePatches(res.Patches, maxUpgrades)
+	result := autoResult{
+		Before:    before,
+		After:     before,
+		Unfixable: slices.Clone(res.Unfixable),
+		Changes:   len(patches),
+		Upgrades:  remediation.InPlaceUpgrades(patches),
+		EPSS:      res.EPSS,
+	}
+	for _, b := range res.Bundled {
+		result.Unfixable = append(result.Unfixable, b.Vuln)
+		opts.Reporter.Infof("%s@%s is bundled in %s@%s, upgrade %s to fix %s\n",
+			b.Pkg.Name, b.Version, b.Parent.Name, b.Parent.Version, b.Parent.Name, b.Vuln.Vulnerability.ID)
+	}
+	for _, u := range res.Unmanageable {
+		result.Unfixable = append(result.Unfixable, u.Vuln)
+		opts.Reporter.Infof("%s@%s is installed from %s and cannot be changed, so %s cannot be fixed\n",
+			u.Pkg.Name, u.Version, u.Source, u.Vuln.Vulnerability.ID)
+	}
+	for _, pm := range res.PartialMitigations {
+		opts.Reporter.Infof("%s\n", pm.Explanation)
+	}
+	for _, alt := range res.Alternatives {
+		opts.Reporter.Infof("%s\n", alt.Explanation)
+	}
+
+	for _, p := range patches {
+		opts.Reporter.Infof("UPGRADED-PACKAGE: %s,%s,%s\n", p.Pkg.Name, p.OrigVersion, p.NewVersion)
+	}
+	opts.Reporter.Infof("UNFIXABLE-VULNS: %d\n", countVulnIDs(result.Unfixable))
+
+	if len(patches) == 0 {
+		return result, nil
+	}
+
+	opts.Reporter.Infof("Rewriting %s...\n", opts.Lockfile)
+	depPatches := make([]lockfile.DependencyPatch, len(patches))
+	for i, p := range patches {
+		depPatches[i] = p.DependencyPatch
+	}
+	if err := lockfile.Overwrite(opts.LockfileRW, opts.Lockfile, depPatches); err != nil {
+		return autoResult{}, err
+	}
+	// Scan the rewritten lockfile to find the vulnerabilities that are actually left
+	result.After, err = scanLockfile(ctx, opts)
+	if err != nil {
+		return autoResult{}, err
+	}
+
+	return result, nil
+}
+
+// scanLockfile finds the vulnerabilities matching the options in the lockfile on disk.
+func scanLockfile(ctx context.Context, opts osvFixOptions) ([]resolution.ResolutionVuln, error) {
+	f, err := lf.OpenLocalDepFile(opts.Lockfile)
+	if err != nil {
+		return nil, err
+	}
+	g, err := opts.LockfileRW.Read(f)
+	f.Close()
+	if err != nil {
+		return nil, err
+	}
+	res, err := resolution.ResolveGraph(ctx, opts.Client, g)
+	if err != nil {
+		return nil, err
+	}
+	res.FilterVulns(opts.MatchVuln)
+
+	return res.Vulns, nil
+}
+
+// chooseInPlacePatches picks the first maxUpgrades (or all, if negative) of the patches, which are sorted by priority.
+// Only the first patch of each vulnerable package version is used.
+func chooseInPlacePatches(patches []remediation.InPlacePatch, maxUpgrades int) []remediation.InPlacePatch {
+	var chosen []remediation.InPlacePatch
+	seen := make(map[resolve.PackageKey][]string)
+	for _, p := range patches {
+		if maxUpgrades >= 0 && len(chosen) >= maxUpgrades {
+			break
+		}
+		if slices.Contains(seen[p.Pkg], p.OrigVersion) {
+			continue
+		}
+		seen[p.Pkg] = append(seen[p.Pkg], p.OrigVersion)
+		chosen = append(chosen, p)
+	}
+
+	return chosen
+}
+
+// autoRelock relaxes the requirements in the manifest, then runs the relock command to regenerate the lockfile.
+func autoRelock(ctx context.Context, opts osvFixOptions, maxUpgrades int) (autoResult, error) {
+	return autoManifest(ctx, opts, maxUpgrades, remediation.ComputeRelaxPatches)
+}
+
+// autoOverride forces the versions of the vulnerable (including indirect) dependencies in the manifest
+// e.g. with npm "overrides", then runs the relock command to regenerate the lockfile.
+func autoOverride(ctx context.Context, opts osvFixOptions, maxUpgrades int) (autoResult, error) {
+	return autoManifest(ctx, opts, maxUpgrades, remediation.ComputeOverridePatches)
+}
+
+type computeManifestPatchesFunc func(context.Context, client.ResolutionClient, *resolution.ResolutionResult, remediation.RemediationOptions) ([]resolution.ResolutionDiff, error)
+
+// autoManifest remediates the manifest with the patches computed by computeFn.
+func autoManifest(ctx context.Context, opts osvFixOptions, maxUpgrades int, computeFn computeManifestPatchesFunc) (autoResult, error) {
+	opts.Reporter.Infof("Resolving %s...\n", opts.Manifest)
+	res, err := resolveManifest(ctx, opts)
+	if err != nil {
+		return autoResult{}, err
+	}
+	for _, d := range res.Diagnostics {
+		opts.Reporter.Verbosef("%s\n", d)
+	}
+
+	diffs, err := computeFn(ctx, opts.Client, res, opts.RemediationOptions)
+	if err != nil {
+		return autoResult{}, err
+	}
+
+	patch := chooseManifestPatches(diffs, maxUpgrades)
+	patch.Manifest = &res.Manifest
+	// Vulnerabilities are unfixable if none of the possible patches remove them
+	result := autoResult{
+		Before:  res.Vulns,
+		After:   res.Vulns,
+		Changes: len(patch.Deps) + len(patch.Overrides),
+	}
+	for _, v := range res.Vulns {
+		if !slices.ContainsFunc(diffs, func(d resolution.ResolutionDiff) bool {
+			return slices.ContainsFunc(d.RemovedVulns, func(rv resolution.ResolutionVuln) bool { return rv.Vulnerability.ID == v.Vulnerability.ID })
+		}) {
+			result.Unfixable = append(result.Unfixable, v)
+		}
+	}
+
+	for _, d := range patch.Deps {
+		opts.Reporter.Infof("UPGRADED-PACKAGE: %s,%s,%s\n", d.Pkg.Name, d.OrigRequire, d.NewRequire)
+	}
+	for _, o := range patch.Overrides {
+		opts.Reporter.Infof("OVERRIDDEN-PACKAGE: %s,%s,%s\n", o.Pkg.Name, o.OrigRequire, o.NewRequire)
+	}
+	opts.Reporter.Infof("UNFIXABLE-VULNS: %d\n", countVulnIDs(result.Unfixable))
+
+	if len(patch.Deps) == 0 && len(patch.Overrides) == 0 {
+		return result, nil
+	}
+
+	opts.Reporter.Infof("Rewriting %s...\n", opts.Manifest)
+	if err := manifest.Overwrite(opts.ManifestRW, opts.Manifest, patch); err != nil {
+		return autoResult{}, err
+	}
+
+	if opts.RelockCmd != "" {
+		if err := relock(ctx, opts); err != nil {
+			return autoResult{}, err
+		}
+	}
+	// Resolve the rewritten manifest to find the vulnerabilities that are actually left
+	newRes, err := resolveManifest(ctx, opts)
+	if err != nil {
+		return autoResult{}, err
+	}
+	result.After = newRes.Vulns
+
+	return result, nil
+}
+
+// resolveManifest resolves the manifest on disk, keeping only the vulnerabilities matching the options.
+func resolveManifest(ctx context.Context, opts osvFixOptions) (*resolution.ResolutionResult, error) {
+	f, err := lf.OpenLocalDepFile(opts.Manifest)
+	if err != nil {
+		return nil, err
+	}
+	m, err := opts.ManifestRW.Read(f)
+	f.Close()
+	if err != nil {
+		return nil, err
+	}
+	opts.Client.PreFetch(ctx, m.Requirements, opts.Manifest)
+	res, err := resolution.Resolve(ctx, opts.Client, m)
+	if err != nil {
+		return nil, err
+	}
+	res.FilterVulns(opts.MatchVuln)
+
+	return res, nil
+}
+
+// chooseManifestPatches merges the first maxUpgrades (or all, if negative) of the patches, which are sorted by priority,
+// skipping any that conflict with the patches already chosen.
+func chooseManifestPatches(diffs []resolution.ResolutionDiff, maxUpgrades int) manifest.ManifestPatch {
+	var patch manifest.ManifestPatch
+	chosen := 0
+	// conflicts checks if any of the changes are to a requirement already changed differently
+	conflicts := func(existing, changes []manifest.DependencyPatch) bool {
+		for _, c := range changes {
+			if slices.ContainsFunc(existing, func(e manifest.DependencyPatch) bool {
+				return e.Pkg == c.Pkg && e.ManifestFile == c.ManifestFile && e.NewRequire != c.NewRequire
+			}) {
+				return true
+			}
+		}
+
+		return false
+	}
+	merge := func(existing, changes []manifest.DependencyPatch) []manifest.DependencyPatch {
+		for _, c := range changes {
+			if !slices.ContainsFunc(existing, func(e manifest.DependencyPatch) bool { return e.Pkg == c.Pkg && e.ManifestFile == c.ManifestFile }) {
+				existing = append(existing, c)
+			}
+		}
+
+		return existing
+	}
+
+	for _, d := range diffs {
+		if maxUpgrades >= 0 && chosen >= maxUpgrades {
+			break
+		}
+		if conflicts(patch.Deps, d.Deps) || conflicts(patch.Overrides, d.Overrides) {
+			continue
+		}
+		patch.Deps = merge(patch.Deps, d.Deps)
+		patch.Overrides = merge(patch.Overrides, d.Overrides)
+		chosen++
+	}
+
+	return patch
+}
+
+// relock runs the relock command in the manifest's directory to regenerate the lockfile.
+func relock(ctx context.Context, opts osvFixOptions) error {
+	opts.Reporter.Infof("Running %q...\n", opts.RelockCmd)
+	parts := strings.Fields(opts.RelockCmd)
+	//nolint:gosec // the command is provided by the user
+	cmd := exec.CommandContext(ctx, parts[0], parts[1:]...)
+	cmd.Dir = filepath.Dir(opts.Manifest)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		opts.Reporter.Errorf("%s\n", out)
+		return fmt.Errorf("relock command failed: %w", err)
+	}
+	opts.Reporter.Verbosef("%s\n", out)
+
+	return nil
+}
+
+func countVulnIDs(vulns []resolution.ResolutionVuln) int {
+	ids := make(map[string]struct{})
+	for _, v := range vulns {
+		ids[v.Vulnerability.ID] = struct{}{}
+	}
+
+	return len(ids)
+}
@@ -0,0 +1,186 @@
+package image
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/google/osv-scanner/internal/image"
+	"github.com/google/osv-scanner/internal/output"
+	"github.com/google/osv-scanner/pkg/osvscanner"
+	"github.com/google/osv-scanner/pkg/reporter"
+	"golang.org/x/term"
+
+	"github.com/urfave/cli/v2"
+)
+
+func Command(stdout, stderr io.Writer, r *reporter.Reporter) *cli.Command {
+	return &cli.Command{
+		Name:        "image",
+		Usage:       "[EXPERIMENTAL] scans images in a registry without needing a Docker daemon",
+		Description: "[EXPERIMENTAL] pulls the layers of images from their registry, using the credentials that Docker is logged in with, and scans the packages of their operating system and of the languages of the artifacts in them",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:      "config",
+				Usage:     "set/override config file",
+				TakesFile: true,
+			},
+			&cli.StringFlag{
+				Name:    "format",
+				Aliases: []string{"f"},
+				Usage:   fmt.Sprintf("sets the output format; value can be: %s", strings.Join(reporter.Format(), ", ")),
+				Value:   "table",
+				Action: func(context *cli.Context, s string) error {
+					if slices.Contains(reporter.Format(), s) {
+						return nil
+					}
+
+					return fmt.Errorf("unsupported output format \"%s\" - must be one of: %s", s, strings.Join(reporter.Format(), ", "))
+				},
+			},
+			&cli.StringFlag{
+				Name:      "output",
+				Usage:     "saves the result to the given file path",
+				TakesFile: true,
+			},
+			&cli.StringFlag{
+				Name:  "verbosity",
+				Usage: fmt.Sprintf("specify the level of information that should be provided during runtime; value can be: %s", strings.Join(reporter.VerbosityLevels(), ", ")),
+				Value: "info",
+			},
+			&cli.BoolFlag{
+				Name:  "experimental-local-db",
+				Usage: "checks for vulnerabilities using local databases",
+			},
+			&cli.BoolFlag{
+				Name:  "experimental-offline",
+				Usage: "checks for vulnerabilities using local databases that are already cached",
+			},
+			&cli.StringFlag{
+				Name:   "experimental-local-db-path",
+				Usage:  "sets the path that local databases should be stored",
+				Hidden: true,
+			},
+			&cli.StringSliceFlag{
+				Name:  "platform",
+				Usage: "scans images for the given platforms, like linux/arm64, or \"all\" for every platform that they are built for, and reports the differences between them",
+				Action: func(context *cli.Context, platforms []string) error {
+					for _, platform := range platforms {
+						if platform == "all" {
+							continue
+						}
+
+						if _, err := image.ParsePlatform(platform); err != nil {
+							return err
+						}
+					}
+
+					return nil
+				},
+			},
+			&cli.StringSliceFlag{
+				Name:      "dockerfile",
+				Usage:     "scans the images that the stages of the given Dockerfile are built from, and the packages that it installs at an exact version",
+				TakesFile: true,
+			},
+			&cli.StringSliceFlag{
+				Name:      "archive",
+				Usage:     "scans an image that has been exported to a tarball by \"docker save\", or to an OCI image layout in a directory or tarball, without a registry",
+				TakesFile: true,
+			},
+			&cli.StringSliceFlag{
+				Name:      "firmware",
+				Usage:     "scans the root filesystem of the firmware of an embedded device, which is a squashfs filesystem (extracted with unsquashfs) or a cpio archive like an initramfs",
+				TakesFile: true,
+			},
+			&cli.StringFlag{
+				Name:      "attestation-key",
+				Usage:     "scans the SBOMs that are attested to for images in their registry instead of their filesystem, if the attestations are signed by the public key in this file",
+				TakesFile: true,
+			},
+			&cli.BoolFlag{
+				Name:  "hide-base-image-vulns",
+				Usage: "hides the vulnerabilities of the packages that were introduced by the base image of an image, to only show those of the layers that it adds",
+			},
+			&cli.BoolFlag{
+				Name:  "experimental-all-packages",
+				Usage: "when json output is selected, prints all packages",
+			},
+		},
+		ArgsUsage: "<image reference> [image reference...]",
+		Action: func(c *cli.Context) error {
+			var err error
+			*r, err = action(c, stdout, stderr)
+
+			return err
+		},
+	}
+}
+
+func action(context *cli.Context, stdout, stderr io.Writer) (reporter.Reporter, error) {
+	format := context.String("format")
+
+	termWidth := 0
+	var err error
+	if outputPath := context.String("output"); outputPath != "" { // Output is definitely a file
+		stdout, err = os.Create(outputPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create output file: %w", err)
+		}
+	} else if stdoutAsFile, ok := stdout.(*os.File); ok { // Output might be a terminal
+		termWidth, _, err = term.GetSize(int(stdoutAsFile.Fd()))
+		if err != nil { // If output is not a terminal,
+			termWidth = 0
+		}
+	}
+
+	verbosityLevel, err := reporter.ParseVerbosityLevel(context.String("verbosity"))
+	if err != nil {
+		return nil, err
+	}
+	r, err := reporter.New(format, stdout, stderr, verbosityLevel, termWidth)
+	if err != nil {
+		return r, err
+	}
+
+	if context.NArg() == 0 && len(context.StringSlice("dockerfile")) == 0 && len(context.StringSlice("archive")) == 0 && len(context.StringSlice("firmware")) == 0 {
+		return r, fmt.Errorf(
+			"expected at least one image to scan, but got %d %s",
+			context.NArg(),
+			output.Form(context.NArg(), "argument", "arguments"),
+		)
+	}
+
+	vulnResult, err := osvscanner.DoScan(osvscanner.ScannerActions{
+		ConfigOverridePath:     context.String("config"),
+		SBOMAttestationKeyPath: context.String("attestation-key"),
+		ExperimentalScannerActions: osvscanner.ExperimentalScannerActions{
+			LocalDBPath:    context.String("experimental-local-db-path"),
+			CompareLocally: context.Bool("experimental-local-db"),
+			CompareOffline: context.Bool("experimental-offline"),
+			// SBOMs should include every package, not just those with vulnerabilities
+			ShowAllPackages: context.Bool("experimental-all-packages") ||
+				slices.Contains([]string{"cyclonedx", "spdx-json", "spdx-tag-value"}, format),
+			ImageReferences:    context.Args().Slice(),
+			ImagePlatforms:     context.StringSlice("platform"),
+			Dockerfiles:        context.StringSlice("dockerfile"),
+			ImageArchives:      context.StringSlice("archive"),
+			FirmwareImages:     context.StringSlice("firmware"),
+			HideBaseImageVulns: context.Bool("hide-base-image-vulns"),
+		},
+	}, r)
+
+	if err != nil && !errors.Is(err, osvscanner.VulnerabilitiesFoundErr) {
+		return r, err
+	}
+
+	if errPrint := r.PrintResult(&vulnResult); errPrint != nil {
+		return r, fmt.Errorf("failed to write output: %w", errPrint)
+	}
+
+	// This may be nil.
+	return r, err
+}
@@ -6,6 +6,12 @@ import (
 	"os"
 	"slices"
 
+	"github.com/google/osv-scanner/cmd/osv-scanner/containers"
+	"github.com/google/osv-scanner/cmd/osv-scanner/diff"
+	"github.com/google/osv-scanner/cmd/osv-scanner/enrich"
+	"github.com/google/osv-scanner/cmd/osv-scanner/fix"
+	"github.com/google/osv-scanner/cmd/osv-scanner/image"
+	"github.com/google/osv-scanner/cmd/osv-scanner/k8s"
 	"github.com/google/osv-scanner/cmd/osv-scanner/scan"
 	"github.com/google/osv-scanner/internal/version"
 	"github.com/google/osv-scanner/pkg/osv"
@@ -41,7 +47,12 @@ func run(args []string, stdout, stderr io.Writer) int {
 		DefaultCommand: "scan",
 		Commands: []*cli.Command{
 			scan.Command(stdout, stderr, &r),
-			// fix.Command(stdout, stderr, &r), // TODO: Uncomment when implemented
+			diff.Command(stdout, stderr, &r),
+			enrich.Command(stdout, stderr, &r),
+			image.Command(stdout, stderr, &r),
+			containers.Command(stdout, stderr, &r),
+			k8s.Command(stdout, stderr, &r),
+			fix.Command(stdout, stderr, &r),
 		},
 	}
 
@@ -57,6 +68,10 @@ func run(args []string, stdout, stderr io.Writer) int {
 		case errors.Is(err, osvscanner.NoPackagesFoundErr):
 			r.Errorf("No package sources found, --help for usage information.\n")
 			return 128
+		case errors.Is(err, fix.ErrUnfixableVulns):
+			return 2
+		case errors.Is(err, fix.ErrStrictUnfixableVulns):
+			return 3
 		}
 		r.Errorf("%v\n", err)
 	}
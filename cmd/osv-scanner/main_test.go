@@ -169,6 +169,12 @@ func TestRun(t *testing.T) {
 			args: []string{"", "./fixtures/locks-many-with-invalid"},
 			exit: 127,
 		},
+		// binary bun.lockb files are skipped with a warning rather than failing the scan
+		{
+			name: "binary bun lockfiles are skipped",
+			args: []string{"", "./fixtures/locks-bun-binary"},
+			exit: 128,
+		},
 		// only the files in the given directories are checked by default (no recursion)
 		{
 			name: "only the files in the given directories are checked by default (no recursion)",
@@ -604,6 +610,48 @@ func TestRun_SubCommands(t *testing.T) {
 			args: []string{"", "scan", "--recursive", "./fixtures/locks-one-with-nested"},
 			exit: 0,
 		},
+		// diff without both of the things to compare
+		{
+			name: "diff with one argument",
+			args: []string{"", "diff", "./fixtures/sbom-insecure/alpine.cdx.xml"},
+			exit: 127,
+		},
+		// enrich without an SBOM to enrich
+		{
+			name: "enrich without an sbom",
+			args: []string{"", "enrich"},
+			exit: 127,
+		},
+		// image without an image to scan
+		{
+			name: "image without an image",
+			args: []string{"", "image"},
+			exit: 127,
+		},
+		// containers with a runtime that is not supported
+		{
+			name: "containers with an unsupported runtime",
+			args: []string{"", "containers", "--runtime", "podman"},
+			exit: 127,
+		},
+		// k8s with an argument, as it scans the workloads of the cluster
+		{
+			name: "k8s with an argument",
+			args: []string{"", "k8s", "default"},
+			exit: 127,
+		},
+		// fix with vulnerabilities that cannot be fixed
+		{
+			name: "fix with unfixable vulnerabilities",
+			args: []string{"", "fix", "--non-interactive", "--strategy", "in-place", "-L", "./fixtures/fix-unfixable/package-lock.json", "--replay", "./fixtures/fix-unfixable/recording.json"},
+			exit: 2,
+		},
+		// fix in strict mode with unfixable vulnerabilities at or above the strict severity
+		{
+			name: "fix with unfixable vulnerabilities in strict mode",
+			args: []string{"", "fix", "--non-interactive", "--strategy", "in-place", "--strict", "--strict-severity", "7", "-L", "./fixtures/fix-unfixable/package-lock.json", "--replay", "./fixtures/fix-unfixable/recording.json"},
+			exit: 3,
+		},
 		// TODO: add tests for other future subcommands
 	}
 	for _, tt := range tests {
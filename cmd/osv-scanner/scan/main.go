@@ -8,6 +8,7 @@ import (
 	"slices"
 	"strings"
 
+	"github.com/google/osv-scanner/pkg/lockfile"
 	"github.com/google/osv-scanner/pkg/osvscanner"
 	"github.com/google/osv-scanner/pkg/reporter"
 	"github.com/google/osv-scanner/pkg/spdx"
@@ -40,6 +41,16 @@ func Command(stdout, stderr io.Writer, r *reporter.Reporter) *cli.Command {
 				Usage:     "scan sbom file on this path",
 				TakesFile: true,
 			},
+			&cli.StringFlag{
+				Name:      "sbom-attestation-key",
+				Usage:     "verify that the sbom files are in-toto attestations signed by the public key in this file before scanning the sboms that they attest to",
+				TakesFile: true,
+			},
+			&cli.StringSliceFlag{
+				Name:      "purl-file",
+				Usage:     "scan the package URLs listed one per line in the file on this path, or standard input if it is -",
+				TakesFile: true,
+			},
 			&cli.StringFlag{
 				Name:      "config",
 				Usage:     "set/override config file",
@@ -126,6 +137,19 @@ func Command(stdout, stderr io.Writer, r *reporter.Reporter) *cli.Command {
 				Name:  "experimental-licenses",
 				Usage: "report on licenses based on an allowlist",
 			},
+			&cli.StringFlag{
+				Name:  "experimental-maven-registry",
+				Usage: "URL of the Maven repository to fetch parent poms and BOMs of pom.xml files from, instead of Maven Central",
+			},
+			&cli.BoolFlag{
+				Name:  "experimental-resolve-package-json",
+				Usage: "scan package.json files that do not have a lockfile by resolving their dependencies from the npm registry",
+			},
+			&cli.StringFlag{
+				Name:      "experimental-extractor-plugins",
+				Usage:     "path to a TOML file configuring commands to extract packages from formats that are not supported natively",
+				TakesFile: true,
+			},
 		},
 		ArgsUsage: "[directory1 directory2...]",
 		Action: func(c *cli.Context) error {
@@ -165,6 +189,9 @@ func action(context *cli.Context, stdout, stderr io.Writer) (reporter.Reporter,
 	if context.Bool("experimental-licenses-summary") && context.IsSet("experimental-licenses") {
 		return nil, fmt.Errorf("--experimental-licenses-summary and --experimental-licenses flags cannot be set")
 	}
+	if context.Bool("experimental-resolve-package-json") && context.Bool("experimental-offline") {
+		return nil, fmt.Errorf("--experimental-resolve-package-json and --experimental-offline flags cannot be set")
+	}
 	allowlist := context.StringSlice("experimental-licenses")
 	if context.IsSet("experimental-licenses") {
 		if len(allowlist) == 0 ||
@@ -176,6 +203,19 @@ func action(context *cli.Context, stdout, stderr io.Writer) (reporter.Reporter,
 		}
 	}
 
+	if pluginsPath := context.String("experimental-extractor-plugins"); pluginsPath != "" {
+		plugins, errPlugins := lockfile.LoadExecExtractors(pluginsPath)
+		if errPlugins != nil {
+			return nil, errPlugins
+		}
+
+		for name, plugin := range plugins {
+			if err = lockfile.RegisterExtractor(name, plugin); err != nil {
+				return nil, fmt.Errorf("could not register extractor plugin: %w", err)
+			}
+		}
+	}
+
 	verbosityLevel, err := reporter.ParseVerbosityLevel(context.String("verbosity"))
 	if err != nil {
 		return nil, err
@@ -196,6 +236,7 @@ func action(context *cli.Context, stdout, stderr io.Writer) (reporter.Reporter,
 	vulnResult, err := osvscanner.DoScan(osvscanner.ScannerActions{
 		LockfilePaths:        context.StringSlice("lockfile"),
 		SBOMPaths:            context.StringSlice("sbom"),
+		PURLFilePaths:        context.StringSlice("purl-file"),
 		DockerContainerNames: context.StringSlice("docker"),
 		Recursive:            context.Bool("recursive"),
 		SkipGit:              context.Bool("skip-git"),
@@ -203,6 +244,9 @@ func action(context *cli.Context, stdout, stderr io.Writer) (reporter.Reporter,
 		ConfigOverridePath:   context.String("config"),
 		DirectoryPaths:       context.Args().Slice(),
 		CallAnalysisStates:   callAnalysisStates,
+
+		SBOMAttestationKeyPath: context.String("sbom-attestation-key"),
+
 		ExperimentalScannerActions: osvscanner.ExperimentalScannerActions{
 			LocalDBPath:    context.String("experimental-local-db-path"),
 			CompareLocally: context.Bool("experimental-local-db"),
@@ -211,10 +255,14 @@ func action(context *cli.Context, stdout, stderr io.Writer) (reporter.Reporter,
 			// packages to appear in the json as
 			// every package has a license - even
 			// if it's just the UNKNOWN license.
+			// SBOMs should include every package, not just those with vulnerabilities
 			ShowAllPackages: context.Bool("experimental-all-packages") ||
-				context.Bool("experimental-licenses-summary"),
+				context.Bool("experimental-licenses-summary") ||
+				slices.Contains([]string{"cyclonedx", "spdx-json", "spdx-tag-value"}, format),
 			ScanLicensesSummary:   context.Bool("experimental-licenses-summary"),
 			ScanLicensesAllowlist: context.StringSlice("experimental-licenses"),
+			MavenRegistry:         context.String("experimental-maven-registry"),
+			ResolvePackageJSON:    context.Bool("experimental-resolve-package-json"),
 		},
 	}, r)
 
@@ -0,0 +1,191 @@
+package ci
+
+import (
+	"slices"
+	"sort"
+
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+// PackageChange is a package that was added, removed, or changed between two scans,
+// along with the vulnerabilities that it introduced or fixed
+type PackageChange struct {
+	Name       string `json:"name"`
+	Ecosystem  string `json:"ecosystem"`
+	OldVersion string `json:"old_version,omitempty"`
+	NewVersion string `json:"new_version,omitempty"`
+	// IntroducedVulns are the vulnerabilities of the new version that the old version does not have
+	IntroducedVulns []string `json:"introduced_vulnerabilities,omitempty"`
+	// FixedVulns are the vulnerabilities of the old version that the new version does not have
+	FixedVulns []string `json:"fixed_vulnerabilities,omitempty"`
+}
+
+// PackageDiff is the difference between the packages of two scans, and their vulnerabilities
+type PackageDiff struct {
+	Added   []PackageChange `json:"added"`
+	Removed []PackageChange `json:"removed"`
+	Changed []PackageChange `json:"changed"`
+	// IntroducedVulns are the vulnerabilities that are only found by the new scan
+	IntroducedVulns []string `json:"introduced_vulnerabilities"`
+	// FixedVulns are the vulnerabilities that are only found by the old scan
+	FixedVulns []string `json:"fixed_vulnerabilities"`
+}
+
+type diffedPackage struct {
+	name      string
+	ecosystem string
+}
+
+// packageVersions returns the versions of each package in the results, and the
+// vulnerabilities of each of those versions, regardless of the source they are in
+func packageVersions(res models.VulnerabilityResults) map[diffedPackage]map[string][]string {
+	versions := make(map[diffedPackage]map[string][]string)
+
+	for _, ps := range res.Results {
+		for _, pv := range ps.Packages {
+			pkg := diffedPackage{name: pv.Package.Name, ecosystem: pv.Package.Ecosystem}
+
+			version := pv.Package.Version
+			if version == "" {
+				version = pv.Package.Commit
+			}
+
+			if versions[pkg] == nil {
+				versions[pkg] = make(map[string][]string)
+			}
+
+			vulns := versions[pkg][version]
+			for _, v := range pv.Vulnerabilities {
+				if !slices.Contains(vulns, v.ID) {
+					vulns = append(vulns, v.ID)
+				}
+			}
+
+			versions[pkg][version] = vulns
+		}
+	}
+
+	return versions
+}
+
+// subtractVulns returns the vulnerabilities in a that are not in b, sorted by their ID
+func subtractVulns(a, b []string) []string {
+	var vulns []string
+
+	for _, id := range a {
+		if !slices.Contains(b, id) {
+			vulns = append(vulns, id)
+		}
+	}
+
+	slices.Sort(vulns)
+
+	return slices.Compact(vulns)
+}
+
+// onlyIn returns the keys of a that are not keys of b, sorted
+func onlyIn(a, b map[string][]string) []string {
+	var keys []string
+
+	for key := range a {
+		if _, ok := b[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// DiffPackages will return the packages that were added to, removed from, or changed in
+// `newRes` compared to `oldRes`, along with the vulnerabilities that were introduced and fixed.
+//
+// Packages are compared by their ecosystem and name regardless of the source that they are
+// in, as the sources of two different builds of a project are often at different paths.
+// When the versions of a package change, they are paired up in order, with any versions
+// that are left over reported as being added or removed.
+func DiffPackages(oldRes, newRes models.VulnerabilityResults) PackageDiff {
+	diff := PackageDiff{
+		Added:   []PackageChange{},
+		Removed: []PackageChange{},
+		Changed: []PackageChange{},
+	}
+
+	oldPkgs := packageVersions(oldRes)
+	newPkgs := packageVersions(newRes)
+
+	pkgs := make([]diffedPackage, 0, len(oldPkgs)+len(newPkgs))
+	for pkg := range oldPkgs {
+		pkgs = append(pkgs, pkg)
+	}
+	for pkg := range newPkgs {
+		if _, ok := oldPkgs[pkg]; !ok {
+			pkgs = append(pkgs, pkg)
+		}
+	}
+
+	sort.Slice(pkgs, func(i, j int) bool {
+		if pkgs[i].ecosystem == pkgs[j].ecosystem {
+			return pkgs[i].name < pkgs[j].name
+		}
+
+		return pkgs[i].ecosystem < pkgs[j].ecosystem
+	})
+
+	var oldVulns, newVulns []string
+
+	for _, pkg := range pkgs {
+		oldVersions := onlyIn(oldPkgs[pkg], newPkgs[pkg])
+		newVersions := onlyIn(newPkgs[pkg], oldPkgs[pkg])
+
+		for _, vulns := range oldPkgs[pkg] {
+			oldVulns = append(oldVulns, vulns...)
+		}
+		for _, vulns := range newPkgs[pkg] {
+			newVulns = append(newVulns, vulns...)
+		}
+
+		for i := 0; i < max(len(oldVersions), len(newVersions)); i++ {
+			change := PackageChange{
+				Name:      pkg.name,
+				Ecosystem: pkg.ecosystem,
+			}
+
+			var before, after []string
+
+			if i < len(oldVersions) {
+				change.OldVersion = oldVersions[i]
+				before = oldPkgs[pkg][change.OldVersion]
+			}
+			if i < len(newVersions) {
+				change.NewVersion = newVersions[i]
+				after = newPkgs[pkg][change.NewVersion]
+			}
+
+			change.IntroducedVulns = subtractVulns(after, before)
+			change.FixedVulns = subtractVulns(before, after)
+
+			switch {
+			case change.OldVersion == "":
+				diff.Added = append(diff.Added, change)
+			case change.NewVersion == "":
+				diff.Removed = append(diff.Removed, change)
+			default:
+				diff.Changed = append(diff.Changed, change)
+			}
+		}
+	}
+
+	diff.IntroducedVulns = subtractVulns(newVulns, oldVulns)
+	diff.FixedVulns = subtractVulns(oldVulns, newVulns)
+
+	if diff.IntroducedVulns == nil {
+		diff.IntroducedVulns = []string{}
+	}
+	if diff.FixedVulns == nil {
+		diff.FixedVulns = []string{}
+	}
+
+	return diff
+}
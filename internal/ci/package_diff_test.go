@@ -0,0 +1,157 @@
+package ci_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/internal/ci"
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+func packageVulns(ecosystem, name, version string, ids ...string) models.PackageVulns {
+	pv := models.PackageVulns{
+		Package: models.PackageInfo{Name: name, Version: version, Ecosystem: ecosystem},
+	}
+
+	for _, id := range ids {
+		pv.Vulnerabilities = append(pv.Vulnerabilities, models.Vulnerability{ID: id})
+	}
+
+	return pv
+}
+
+func TestDiffPackages(t *testing.T) {
+	t.Parallel()
+
+	oldRes := models.VulnerabilityResults{
+		Results: []models.PackageSource{
+			{
+				Source: models.SourceInfo{Path: "/v1/bom.cdx.json", Type: "sbom"},
+				Packages: []models.PackageVulns{
+					packageVulns("npm", "express", "4.17.1", "GHSA-rv95-896h-c2vc"),
+					packageVulns("npm", "lodash", "4.17.20", "GHSA-35jh-r3h4-6jhm", "GHSA-29mw-wpgm-hmr9"),
+					packageVulns("npm", "left-pad", "1.3.0"),
+					packageVulns("Go", "golang.org/x/net", "0.7.0"),
+				},
+			},
+		},
+	}
+
+	newRes := models.VulnerabilityResults{
+		Results: []models.PackageSource{
+			{
+				Source: models.SourceInfo{Path: "/v2/bom.cdx.json", Type: "sbom"},
+				Packages: []models.PackageVulns{
+					packageVulns("npm", "express", "4.17.1", "GHSA-rv95-896h-c2vc"),
+					packageVulns("npm", "lodash", "4.17.21"),
+					packageVulns("npm", "minimist", "1.2.5", "GHSA-xvch-5gv4-984h"),
+				},
+			},
+			{
+				Source: models.SourceInfo{Path: "/v2/go.mod", Type: "lockfile"},
+				Packages: []models.PackageVulns{
+					packageVulns("Go", "golang.org/x/net", "0.7.0"),
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		oldRes models.VulnerabilityResults
+		newRes models.VulnerabilityResults
+		want   ci.PackageDiff
+	}{
+		{
+			name:   "same results",
+			oldRes: oldRes,
+			newRes: oldRes,
+			want: ci.PackageDiff{
+				Added:           []ci.PackageChange{},
+				Removed:         []ci.PackageChange{},
+				Changed:         []ci.PackageChange{},
+				IntroducedVulns: []string{},
+				FixedVulns:      []string{},
+			},
+		},
+		{
+			name:   "new build",
+			oldRes: oldRes,
+			newRes: newRes,
+			want: ci.PackageDiff{
+				Added: []ci.PackageChange{
+					{
+						Name:            "minimist",
+						Ecosystem:       "npm",
+						NewVersion:      "1.2.5",
+						IntroducedVulns: []string{"GHSA-xvch-5gv4-984h"},
+					},
+				},
+				Removed: []ci.PackageChange{
+					{
+						Name:       "left-pad",
+						Ecosystem:  "npm",
+						OldVersion: "1.3.0",
+					},
+				},
+				Changed: []ci.PackageChange{
+					{
+						Name:       "lodash",
+						Ecosystem:  "npm",
+						OldVersion: "4.17.20",
+						NewVersion: "4.17.21",
+						FixedVulns: []string{"GHSA-29mw-wpgm-hmr9", "GHSA-35jh-r3h4-6jhm"},
+					},
+				},
+				IntroducedVulns: []string{"GHSA-xvch-5gv4-984h"},
+				FixedVulns:      []string{"GHSA-29mw-wpgm-hmr9", "GHSA-35jh-r3h4-6jhm"},
+			},
+		},
+		{
+			name:   "old build",
+			oldRes: newRes,
+			newRes: oldRes,
+			want: ci.PackageDiff{
+				Added: []ci.PackageChange{
+					{
+						Name:       "left-pad",
+						Ecosystem:  "npm",
+						NewVersion: "1.3.0",
+					},
+				},
+				Removed: []ci.PackageChange{
+					{
+						Name:       "minimist",
+						Ecosystem:  "npm",
+						OldVersion: "1.2.5",
+						FixedVulns: []string{"GHSA-xvch-5gv4-984h"},
+					},
+				},
+				Changed: []ci.PackageChange{
+					{
+						Name:            "lodash",
+						Ecosystem:       "npm",
+						OldVersion:      "4.17.21",
+						NewVersion:      "4.17.20",
+						IntroducedVulns: []string{"GHSA-29mw-wpgm-hmr9", "GHSA-35jh-r3h4-6jhm"},
+					},
+				},
+				IntroducedVulns: []string{"GHSA-29mw-wpgm-hmr9", "GHSA-35jh-r3h4-6jhm"},
+				FixedVulns:      []string{"GHSA-xvch-5gv4-984h"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := ci.DiffPackages(tt.oldRes, tt.newRes)
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("DiffPackages() (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
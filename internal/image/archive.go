@@ -0,0 +1,298 @@
+package image
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+
+	securejoin "github.com/cyphar/filepath-securejoin"
+)
+
+const (
+	// annotationRefName is the tag that an image in an OCI image layout was exported with
+	annotationRefName = "org.opencontainers.image.ref.name"
+	// annotationContainerdName is the full reference that Docker and containerd export an image with
+	annotationContainerdName = "io.containerd.image.name"
+)
+
+var ErrUnsupportedArchive = errors.New("not a docker save tarball or an OCI image layout")
+
+// ArchivedImage is an image that was read from an archive, along with what it was exported as
+type ArchivedImage struct {
+	*Image
+	// Name is the reference or tag that the image was exported with, which is empty if it was not tagged
+	Name string
+}
+
+// archive opens the files of an exported image, which is either a directory or a tarball of one
+type archive interface {
+	open(name string) (io.ReadCloser, error)
+}
+
+// dirArchive is a directory that an image was exported to, such as an OCI image layout
+type dirArchive string
+
+func (d dirArchive) open(name string) (io.ReadCloser, error) {
+	p, err := securejoin.SecureJoin(string(d), name)
+	if err != nil {
+		return nil, err
+	}
+
+	return os.Open(p)
+}
+
+// tarArchive is a tarball that an image was exported to, such as one created by `docker save`,
+// which is read from the start each time that a file is opened as tarballs cannot be seeked
+// into, though the content of the files before it is skipped over if it is not compressed
+type tarArchive string
+
+type tarFile struct {
+	io.Reader
+	io.Closer
+}
+
+func (t tarArchive) open(name string) (io.ReadCloser, error) {
+	f, err := os.Open(string(t))
+	if err != nil {
+		return nil, err
+	}
+
+	var r io.Reader = f
+
+	magic := make([]byte, 2)
+	if _, err := f.ReadAt(magic, 0); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		r, err = gzip.NewReader(f)
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+	}
+
+	tr := tar.NewReader(r)
+	name = path.Clean(name)
+
+	for {
+		header, err := tr.Next()
+		if err != nil {
+			f.Close()
+
+			if errors.Is(err, io.EOF) {
+				return nil, fmt.Errorf("%s is not in %s: %w", name, t, fs.ErrNotExist)
+			}
+
+			return nil, err
+		}
+
+		if header.Typeflag == tar.TypeReg && path.Clean(header.Name) == name {
+			return tarFile{Reader: tr, Closer: f}, nil
+		}
+	}
+}
+
+// hasArchiveFile returns whether the archive has the file, which is false if it cannot be read
+func hasArchiveFile(a archive, name string) bool {
+	f, err := a.open(name)
+	if err != nil {
+		return false
+	}
+	f.Close()
+
+	return true
+}
+
+// readArchiveJSON parses a JSON file of an archive
+func readArchiveJSON(a archive, name string, v any) error {
+	f, err := a.open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := json.NewDecoder(f).Decode(v); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// blobPath returns where a blob is in an OCI image layout, like "blobs/sha256/<hex>"
+func blobPath(digest string) string {
+	return "blobs/" + strings.Replace(digest, ":", "/", 1)
+}
+
+// archivedImage returns an image that was read from an archive, with the configuration that says which
+// platform it is for and how its layers were built, and whose blobs are opened from the archive
+func archivedImage(p string, cfg config, layers []Layer, blobs func(digest string) (io.ReadCloser, error)) *Image {
+	img := &Image{
+		Platform: Platform{OS: cfg.OS, Architecture: cfg.Architecture, Variant: cfg.Variant},
+		Layers:   layers,
+		archive:  p,
+		blobs:    blobs,
+	}
+
+	if commands := layerCommands(cfg, len(layers)); commands != nil {
+		for i := range img.Layers {
+			img.Layers[i].Command = commands[i]
+		}
+	}
+
+	return img
+}
+
+// readOCIManifest reads the manifest of an image in an OCI image layout, choosing the manifest
+// for the platform if it is an index, and returns nil if the manifest is not of an image
+func readOCIManifest(a archive, p string, desc descriptor, platform Platform) (*Image, error) {
+	var m manifest
+	if err := readArchiveJSON(a, blobPath(desc.Digest), &m); err != nil {
+		return nil, err
+	}
+
+	if m.isIndex() {
+		chosen := chooseManifest(m.Manifests, platform)
+		if chosen == nil {
+			return nil, fmt.Errorf("%w %s: %s", ErrNoMatchingPlatform, platform, p)
+		}
+
+		return readOCIManifest(a, p, *chosen, platform)
+	}
+
+	if m.Config.Digest == "" {
+		return nil, nil
+	}
+
+	var cfg config
+	if err := readArchiveJSON(a, blobPath(m.Config.Digest), &cfg); err != nil {
+		return nil, err
+	}
+
+	layers := make([]Layer, 0, len(m.Layers))
+	for _, layer := range m.Layers {
+		layers = append(layers, Layer{Digest: layer.Digest, MediaType: layer.MediaType, Size: layer.Size})
+	}
+
+	img := archivedImage(p, cfg, layers, func(digest string) (io.ReadCloser, error) {
+		return a.open(blobPath(digest))
+	})
+	img.Digest = desc.Digest
+	img.Annotations = m.Annotations
+
+	return img, nil
+}
+
+// readOCILayout reads the images of an OCI image layout, which Docker also includes in the tarballs
+// that it saves since Docker 25. Manifests that are for other platforms are skipped, as are those that
+// are not of an image, like the attestations that BuildKit adds with a platform of "unknown/unknown"
+func readOCILayout(a archive, p string, platform Platform) ([]ArchivedImage, error) {
+	var index manifest
+	if err := readArchiveJSON(a, "index.json", &index); err != nil {
+		return nil, err
+	}
+
+	var images []ArchivedImage
+
+	for _, desc := range index.Manifests {
+		if desc.Platform != nil && chooseManifest([]descriptor{desc}, platform) == nil {
+			continue
+		}
+
+		img, err := readOCIManifest(a, p, desc, platform)
+		if err != nil {
+			return nil, err
+		}
+
+		if img == nil {
+			continue
+		}
+
+		name := desc.Annotations[annotationContainerdName]
+		if name == "" {
+			name = desc.Annotations[annotationRefName]
+		}
+
+		images = append(images, ArchivedImage{Image: img, Name: name})
+	}
+
+	return images, nil
+}
+
+// dockerArchiveManifest is an image in the manifest.json of a tarball created by `docker save`
+type dockerArchiveManifest struct {
+	Config   string   `json:"Config"`
+	RepoTags []string `json:"RepoTags"`
+	// Layers are the paths of the uncompressed tarballs of the layers in the archive
+	Layers []string `json:"Layers"`
+}
+
+// readDockerArchive reads the images of a tarball created by `docker save` before Docker 25, whose layers
+// are not named after their digest, so they are identified by the digests that their configuration has
+func readDockerArchive(a archive, p string) ([]ArchivedImage, error) {
+	var manifests []dockerArchiveManifest
+	if err := readArchiveJSON(a, "manifest.json", &manifests); err != nil {
+		return nil, err
+	}
+
+	images := make([]ArchivedImage, 0, len(manifests))
+
+	for _, m := range manifests {
+		var cfg config
+		if err := readArchiveJSON(a, m.Config, &cfg); err != nil {
+			return nil, err
+		}
+
+		if len(cfg.RootFS.DiffIDs) != len(m.Layers) {
+			return nil, fmt.Errorf("%s has %d layers, but its configuration has %d", p, len(m.Layers), len(cfg.RootFS.DiffIDs))
+		}
+
+		paths := make(map[string]string, len(m.Layers))
+		layers := make([]Layer, 0, len(m.Layers))
+		for i, layer := range m.Layers {
+			paths[cfg.RootFS.DiffIDs[i]] = layer
+			layers = append(layers, Layer{Digest: cfg.RootFS.DiffIDs[i]})
+		}
+
+		img := archivedImage(p, cfg, layers, func(digest string) (io.ReadCloser, error) {
+			return a.open(paths[digest])
+		})
+
+		name := ""
+		if len(m.RepoTags) > 0 {
+			name = m.RepoTags[0]
+		}
+
+		images = append(images, ArchivedImage{Image: img, Name: name})
+	}
+
+	return images, nil
+}
+
+// ReadArchive reads the images that have been exported to the path, which is either a tarball created by
+// `docker save`, or an OCI image layout in a directory or a tarball like that which BuildKit exports,
+// choosing the manifest for the platform of images that are built for more than one. The layers of the
+// images are read from the archive when they are extracted, so the archive has to be kept until then
+func ReadArchive(p string, platform Platform) ([]ArchivedImage, error) {
+	info, err := os.Stat(p)
+	if err != nil {
+		return nil, err
+	}
+
+	var a archive = tarArchive(p)
+	if info.IsDir() {
+		a = dirArchive(p)
+	}
+
+	switch {
+	case hasArchiveFile(a, "index.json"):
+		return readOCILayout(a, p, platform)
+	case hasArchiveFile(a, "manifest.json"):
+		return readDockerArchive(a, p)
+	}
+
+	return nil, fmt.Errorf("%s is %w", p, ErrUnsupportedArchive)
+}
@@ -0,0 +1,263 @@
+package image_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/osv-scanner/internal/image"
+)
+
+// tarball returns a tarball of the files, in the order that they are given
+func tarball(t *testing.T, files ...[2]string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+
+	for _, file := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: file[0], Typeflag: tar.TypeReg, Size: int64(len(file[1])), Mode: 0o644}); err != nil {
+			t.Fatalf("failed to write tarball: %v", err)
+		}
+
+		if _, err := tw.Write([]byte(file[1])); err != nil {
+			t.Fatalf("failed to write tarball: %v", err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		t.Fatalf("failed to write tarball: %v", err)
+	}
+
+	return buf.Bytes()
+}
+
+func mustJSON(t *testing.T, v any) string {
+	t.Helper()
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("failed to marshal JSON: %v", err)
+	}
+
+	return string(b)
+}
+
+// layerAndConfig returns a layer that writes the dpkg status file, and the configuration of an image of it
+func layerAndConfig(t *testing.T) (string, string) {
+	t.Helper()
+
+	layer := string(tarball(t, [2]string{"var/lib/dpkg/status", "Package: curl\nStatus: install ok installed\nVersion: 7.88.1-10+deb12u4\n"}))
+
+	config := mustJSON(t, map[string]any{
+		"architecture": "amd64",
+		"os":           "linux",
+		"history":      []any{map[string]any{"created_by": "RUN /bin/sh -c apt-get install -y curl # buildkit"}},
+		"rootfs":       map[string]any{"type": "layers", "diff_ids": []string{digestOf([]byte(layer))}},
+	})
+
+	return layer, config
+}
+
+// writeOCILayout writes an OCI image layout of an image that is built for linux/amd64 and linux/arm64 to the
+// directory, like BuildKit exports, and returns the files of the layout relative to it
+func writeOCILayout(t *testing.T, dir string) [][2]string {
+	t.Helper()
+
+	layer, config := layerAndConfig(t)
+
+	manifest := mustJSON(t, map[string]any{
+		"schemaVersion": 2,
+		"mediaType":     "application/vnd.oci.image.manifest.v1+json",
+		"config": map[string]any{
+			"mediaType": "application/vnd.oci.image.config.v1+json",
+			"digest":    digestOf([]byte(config)),
+			"size":      len(config),
+		},
+		"layers": []any{map[string]any{
+			"mediaType": "application/vnd.oci.image.layer.v1.tar",
+			"digest":    digestOf([]byte(layer)),
+			"size":      len(layer),
+		}},
+	})
+
+	index := mustJSON(t, map[string]any{
+		"schemaVersion": 2,
+		"mediaType":     "application/vnd.oci.image.index.v1+json",
+		"manifests": []any{
+			map[string]any{
+				"mediaType": "application/vnd.oci.image.manifest.v1+json",
+				"digest":    "sha256:0000000000000000000000000000000000000000000000000000000000000000",
+				"platform":  map[string]string{"os": "linux", "architecture": "arm64"},
+			},
+			map[string]any{
+				"mediaType": "application/vnd.oci.image.manifest.v1+json",
+				"digest":    digestOf([]byte(manifest)),
+				"platform":  map[string]string{"os": "linux", "architecture": "amd64"},
+			},
+		},
+	})
+
+	layout := mustJSON(t, map[string]any{
+		"schemaVersion": 2,
+		"mediaType":     "application/vnd.oci.image.index.v1+json",
+		"manifests": []any{map[string]any{
+			"mediaType":   "application/vnd.oci.image.index.v1+json",
+			"digest":      digestOf([]byte(index)),
+			"annotations": map[string]string{"org.opencontainers.image.ref.name": "1.0"},
+		}},
+	})
+
+	files := [][2]string{
+		{"oci-layout", `{"imageLayoutVersion":"1.0.0"}`},
+		{"index.json", layout},
+	}
+	for _, blob := range []string{index, manifest, config, layer} {
+		files = append(files, [2]string{"blobs/sha256/" + strings.TrimPrefix(digestOf([]byte(blob)), "sha256:"), blob})
+	}
+
+	for _, file := range files {
+		p := filepath.Join(dir, filepath.FromSlash(file[0]))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatalf("failed to write layout: %v", err)
+		}
+		if err := os.WriteFile(p, []byte(file[1]), 0o600); err != nil {
+			t.Fatalf("failed to write layout: %v", err)
+		}
+	}
+
+	return files
+}
+
+// expectArchivedImage checks that the archive has one image with the name, and that it extracts to the dpkg status file
+func expectArchivedImage(t *testing.T, images []image.ArchivedImage, name string) {
+	t.Helper()
+
+	if len(images) != 1 {
+		t.Fatalf("ReadArchive() returned %d images, want 1", len(images))
+	}
+
+	if images[0].Name != name {
+		t.Errorf("ReadArchive() Name = %q, want %q", images[0].Name, name)
+	}
+
+	if got, want := images[0].Platform.String(), "linux/amd64"; got != want {
+		t.Errorf("ReadArchive() Platform = %q, want %q", got, want)
+	}
+
+	if got, want := images[0].Layers[0].Command, "RUN apt-get install -y curl"; got != want {
+		t.Errorf("ReadArchive() layer command = %q, want %q", got, want)
+	}
+
+	dir := t.TempDir()
+	if err := image.NewClient().Extract(context.Background(), images[0].Image, dir, nil); err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "var", "lib", "dpkg", "status"))
+	if err != nil {
+		t.Fatalf("Extract() did not write the layer: %v", err)
+	}
+
+	if !strings.Contains(string(content), "Package: curl") {
+		t.Errorf("Extract() wrote %q to var/lib/dpkg/status", content)
+	}
+}
+
+func TestReadArchive_DockerSave(t *testing.T) {
+	t.Parallel()
+
+	layer, config := layerAndConfig(t)
+
+	archive := tarball(t,
+		[2]string{"manifest.json", mustJSON(t, []any{map[string]any{
+			"Config":   "3f2a.json",
+			"RepoTags": []string{"app:1.0"},
+			"Layers":   []string{"a1b2/layer.tar"},
+		}})},
+		[2]string{"3f2a.json", config},
+		[2]string{"a1b2/layer.tar", layer},
+	)
+
+	p := filepath.Join(t.TempDir(), "app.tar")
+	if err := os.WriteFile(p, archive, 0o600); err != nil {
+		t.Fatalf("failed to write archive: %v", err)
+	}
+
+	images, err := image.ReadArchive(p, image.Platform{OS: "linux", Architecture: "amd64"})
+	if err != nil {
+		t.Fatalf("ReadArchive() error = %v", err)
+	}
+
+	expectArchivedImage(t, images, "app:1.0")
+}
+
+func TestReadArchive_OCILayoutDirectory(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeOCILayout(t, dir)
+
+	images, err := image.ReadArchive(dir, image.Platform{OS: "linux", Architecture: "amd64"})
+	if err != nil {
+		t.Fatalf("ReadArchive() error = %v", err)
+	}
+
+	expectArchivedImage(t, images, "1.0")
+}
+
+func TestReadArchive_OCILayoutTarball(t *testing.T) {
+	t.Parallel()
+
+	files := writeOCILayout(t, t.TempDir())
+
+	var compressed bytes.Buffer
+	gz := gzip.NewWriter(&compressed)
+	_, _ = gz.Write(tarball(t, files...))
+	_ = gz.Close()
+
+	p := filepath.Join(t.TempDir(), "app.tar.gz")
+	if err := os.WriteFile(p, compressed.Bytes(), 0o600); err != nil {
+		t.Fatalf("failed to write archive: %v", err)
+	}
+
+	images, err := image.ReadArchive(p, image.Platform{OS: "linux", Architecture: "amd64"})
+	if err != nil {
+		t.Fatalf("ReadArchive() error = %v", err)
+	}
+
+	expectArchivedImage(t, images, "1.0")
+}
+
+func TestReadArchive_NoMatchingPlatform(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeOCILayout(t, dir)
+
+	_, err := image.ReadArchive(dir, image.Platform{OS: "windows", Architecture: "amd64"})
+	if !errors.Is(err, image.ErrNoMatchingPlatform) {
+		t.Errorf("ReadArchive() error = %v, want %v", err, image.ErrNoMatchingPlatform)
+	}
+}
+
+func TestReadArchive_Unsupported(t *testing.T) {
+	t.Parallel()
+
+	p := filepath.Join(t.TempDir(), "files.tar")
+	if err := os.WriteFile(p, tarball(t, [2]string{"etc/passwd", "root"}), 0o600); err != nil {
+		t.Fatalf("failed to write archive: %v", err)
+	}
+
+	_, err := image.ReadArchive(p, image.DefaultPlatform())
+	if !errors.Is(err, image.ErrUnsupportedArchive) {
+		t.Errorf("ReadArchive() error = %v, want %v", err, image.ErrUnsupportedArchive)
+	}
+}
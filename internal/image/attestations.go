@@ -0,0 +1,135 @@
+package image
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	mediaTypeDSSEEnvelope = "application/vnd.dsse.envelope.v1+json"
+	// mediaTypeSigstoreBundle is the prefix of the media types of sigstore bundles, which end with their version
+	mediaTypeSigstoreBundle = "application/vnd.dev.sigstore.bundle"
+)
+
+// cosignAttestationTag returns the tag that cosign attaches the attestations of the image with the digest under
+func cosignAttestationTag(digest string) string {
+	return strings.Replace(digest, ":", "-", 1) + ".att"
+}
+
+// readBlob fetches all of the content of a blob of the repository of the image
+func (c *Client) readBlob(ctx context.Context, ref Reference, digest string) ([]byte, error) {
+	blob, err := c.fetchBlob(ctx, ref, digest)
+	if err != nil {
+		return nil, err
+	}
+	defer blob.Close()
+
+	return io.ReadAll(blob)
+}
+
+// envelopes returns the DSSE envelopes in the layers of a manifest of attestations, which are either
+// the envelopes themselves or sigstore bundles that have one in place of a signature of a blob
+func (c *Client) envelopes(ctx context.Context, ref Reference, m manifest) ([][]byte, error) {
+	var envelopes [][]byte
+
+	for _, layer := range m.Layers {
+		switch {
+		case layer.MediaType == mediaTypeDSSEEnvelope:
+			envelope, err := c.readBlob(ctx, ref, layer.Digest)
+			if err != nil {
+				return nil, err
+			}
+
+			envelopes = append(envelopes, envelope)
+		case strings.HasPrefix(layer.MediaType, mediaTypeSigstoreBundle):
+			content, err := c.readBlob(ctx, ref, layer.Digest)
+			if err != nil {
+				return nil, err
+			}
+
+			var bundle struct {
+				DSSEEnvelope json.RawMessage `json:"dsseEnvelope"`
+			}
+			if err := json.Unmarshal(content, &bundle); err != nil {
+				return nil, fmt.Errorf("failed to parse sigstore bundle of %s: %w", ref, err)
+			}
+
+			if len(bundle.DSSEEnvelope) > 0 {
+				envelopes = append(envelopes, bundle.DSSEEnvelope)
+			}
+		}
+	}
+
+	return envelopes, nil
+}
+
+// referrers lists the manifests that refer to the manifest with the digest with the
+// referrers API of OCI registries, which is empty if the registry does not support it
+func (c *Client) referrers(ctx context.Context, ref Reference, digest string) ([]descriptor, error) {
+	resp, err := c.get(ctx, ref, "referrers/"+digest, []string{mediaTypeOCIIndex})
+	if errors.Is(err, errNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var index manifest
+	if err := json.NewDecoder(resp.Body).Decode(&index); err != nil {
+		return nil, fmt.Errorf("failed to parse referrers of %s: %w", ref, err)
+	}
+
+	return index.Manifests, nil
+}
+
+// Attestations returns the DSSE envelopes of the attestations that are attached to the manifest with
+// the digest in the registry of the image, either by cosign under a tag that is named after the digest,
+// or as referrers of the manifest like cosign does with sigstore bundles. The envelopes are returned
+// as they are, without their signatures being verified, and it is not an error for there to be none
+func (c *Client) Attestations(ctx context.Context, ref Reference, digest string) ([][]byte, error) {
+	var envelopes [][]byte
+
+	m, _, err := c.fetchManifest(ctx, ref, cosignAttestationTag(digest))
+	if err != nil && !errors.Is(err, errNotFound) {
+		return nil, err
+	}
+
+	if err == nil {
+		found, err := c.envelopes(ctx, ref, m)
+		if err != nil {
+			return nil, err
+		}
+
+		envelopes = append(envelopes, found...)
+	}
+
+	referrers, err := c.referrers(ctx, ref, digest)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, desc := range referrers {
+		if desc.ArtifactType != mediaTypeDSSEEnvelope && !strings.HasPrefix(desc.ArtifactType, mediaTypeSigstoreBundle) {
+			continue
+		}
+
+		m, _, err := c.fetchManifest(ctx, ref, desc.Digest)
+		if err != nil {
+			return nil, err
+		}
+
+		found, err := c.envelopes(ctx, ref, m)
+		if err != nil {
+			return nil, err
+		}
+
+		envelopes = append(envelopes, found...)
+	}
+
+	return envelopes, nil
+}
@@ -0,0 +1,76 @@
+package image_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/internal/image"
+)
+
+func TestClient_Attestations(t *testing.T) {
+	t.Parallel()
+
+	server := newRegistry(t)
+
+	ref, err := image.ParseReference(strings.TrimPrefix(server.URL, "http://") + "/org/app:1.0")
+	if err != nil {
+		t.Fatalf("ParseReference() error = %v", err)
+	}
+
+	client := image.NewClient()
+	client.Scheme = "http"
+
+	img, err := client.Pull(context.Background(), ref, image.Platform{OS: "linux", Architecture: "amd64"})
+	if err != nil {
+		t.Fatalf("Pull() error = %v", err)
+	}
+
+	if img.IndexDigest == "" {
+		t.Fatalf("Pull() did not set the digest of the index")
+	}
+
+	tests := []struct {
+		name   string
+		digest string
+		want   []string
+	}{
+		{
+			name:   "attached by cosign",
+			digest: img.Digest,
+			want:   []string{`{"payloadType":"application/vnd.in-toto+json","payload":"e30=","signatures":[]}`},
+		},
+		{
+			name:   "sigstore bundle referrer",
+			digest: img.IndexDigest,
+			want:   []string{`{"payloadType":"application/vnd.in-toto+json","payload":"e30=","signatures":[]}`},
+		},
+		{
+			name:   "no attestations",
+			digest: "sha256:0000000000000000000000000000000000000000000000000000000000000000",
+			want:   nil,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt // Reinitialize for t.Parallel()
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			envelopes, err := client.Attestations(context.Background(), ref, tt.digest)
+			if err != nil {
+				t.Fatalf("Attestations() error = %v", err)
+			}
+
+			var got []string
+			for _, envelope := range envelopes {
+				got = append(got, string(envelope))
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Attestations() (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package image
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// dockerConfig is the part of the configuration of the Docker CLI that
+// has the credentials for registries, see `docker login`
+type dockerConfig struct {
+	Auths map[string]struct {
+		Auth string `json:"auth"`
+	} `json:"auths"`
+	CredsStore  string            `json:"credsStore"`
+	CredHelpers map[string]string `json:"credHelpers"`
+}
+
+// credentials are the username and password that a registry is logged in to with
+type credentials struct {
+	Username string
+	Password string
+}
+
+// dockerConfigPath returns where the Docker CLI keeps its configuration
+func dockerConfigPath() string {
+	if dir := os.Getenv("DOCKER_CONFIG"); dir != "" {
+		return filepath.Join(dir, "config.json")
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(home, ".docker", "config.json")
+}
+
+// registryKeys are the keys that the credentials of a registry can be stored under,
+// which are historically URLs for Docker Hub
+func registryKeys(registry string) []string {
+	if registry == dockerHubRegistry {
+		return []string{"https://index.docker.io/v1/", "index.docker.io", dockerHubRegistry}
+	}
+
+	return []string{registry, "https://" + registry, "http://" + registry}
+}
+
+// credentialsFromHelper gets the credentials of a registry from a Docker credential helper,
+// which is a program named docker-credential-<helper> that is given the registry on stdin
+func credentialsFromHelper(helper string, registry string) (credentials, error) {
+	cmd := exec.Command("docker-credential-"+helper, "get")
+	cmd.Stdin = strings.NewReader(registry)
+
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+
+	if err := cmd.Run(); err != nil {
+		return credentials{}, fmt.Errorf("docker-credential-%s failed: %w", helper, err)
+	}
+
+	var creds struct {
+		Username string `json:"Username"`
+		Secret   string `json:"Secret"`
+	}
+
+	if err := json.Unmarshal(stdout.Bytes(), &creds); err != nil {
+		return credentials{}, fmt.Errorf("docker-credential-%s returned invalid credentials: %w", helper, err)
+	}
+
+	return credentials{Username: creds.Username, Password: creds.Secret}, nil
+}
+
+// lookupCredentials returns the credentials that the Docker CLI has for the registry,
+// or false if it is not logged in to it, in which case the registry is accessed anonymously
+func lookupCredentials(registry string) (credentials, bool) {
+	content, err := os.ReadFile(dockerConfigPath())
+	if err != nil {
+		return credentials{}, false
+	}
+
+	var config dockerConfig
+	if err := json.Unmarshal(content, &config); err != nil {
+		return credentials{}, false
+	}
+
+	for _, key := range registryKeys(registry) {
+		helper, ok := config.CredHelpers[key]
+		if !ok {
+			continue
+		}
+
+		if creds, err := credentialsFromHelper(helper, key); err == nil {
+			return creds, true
+		}
+	}
+
+	for _, key := range registryKeys(registry) {
+		auth, ok := config.Auths[key]
+		if !ok {
+			continue
+		}
+
+		if auth.Auth == "" && config.CredsStore != "" {
+			if creds, err := credentialsFromHelper(config.CredsStore, key); err == nil {
+				return creds, true
+			}
+
+			continue
+		}
+
+		decoded, err := base64.StdEncoding.DecodeString(auth.Auth)
+		if err != nil {
+			continue
+		}
+
+		if username, password, ok := strings.Cut(string(decoded), ":"); ok {
+			return credentials{Username: username, Password: password}, true
+		}
+	}
+
+	return credentials{}, false
+}
@@ -0,0 +1,100 @@
+package image
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+const (
+	// DefaultContainerdStateDir is where containerd keeps the state of the containers that it runs
+	// with version 2 of its runtime, which has the bundle of each of them in a directory for its namespace
+	DefaultContainerdStateDir = "/run/containerd/io.containerd.runtime.v2.task"
+	// dockerContainerdNamespace is the namespace that Docker runs its containers in,
+	// which are listed through the API of Docker instead
+	dockerContainerdNamespace = "moby"
+)
+
+// bundleSpec is the part of the OCI runtime spec in the bundle of a container that
+// has the annotations that the Kubernetes CRI plugin of containerd adds to it
+type bundleSpec struct {
+	Annotations map[string]string `json:"annotations"`
+}
+
+// ContainerdContainers lists the containers that containerd is running from its state directory, which
+// has the bundle of each of them along with their filesystem mounted in it. Containers of Kubernetes
+// are named by their namespace, pod, and container, and their sandboxes are not listed
+func ContainerdContainers(stateDir string) ([]Container, error) {
+	namespaces, err := os.ReadDir(stateDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var containers []Container
+
+	for _, namespace := range namespaces {
+		if !namespace.IsDir() || namespace.Name() == dockerContainerdNamespace {
+			continue
+		}
+
+		tasks, err := os.ReadDir(filepath.Join(stateDir, namespace.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, task := range tasks {
+			bundle := filepath.Join(stateDir, namespace.Name(), task.Name())
+
+			if info, err := os.Stat(filepath.Join(bundle, "rootfs")); err != nil || !info.IsDir() {
+				continue
+			}
+
+			container := Container{
+				ID:      task.Name(),
+				Name:    namespace.Name() + "/" + task.Name(),
+				Runtime: "containerd",
+				Rootfs:  filepath.Join(bundle, "rootfs"),
+			}
+
+			spec, err := readBundleSpec(filepath.Join(bundle, "config.json"))
+			if err != nil {
+				return nil, err
+			}
+
+			if spec.Annotations["io.kubernetes.cri.container-type"] == "sandbox" {
+				continue
+			}
+
+			if name := spec.Annotations["io.kubernetes.cri.container-name"]; name != "" {
+				container.Name = spec.Annotations["io.kubernetes.cri.sandbox-namespace"] + "/" +
+					spec.Annotations["io.kubernetes.cri.sandbox-name"] + "/" + name
+			}
+
+			container.Image = spec.Annotations["io.kubernetes.cri.image-name"]
+			containers = append(containers, container)
+		}
+	}
+
+	return containers, nil
+}
+
+// readBundleSpec reads the spec of the bundle of a container, which does not exist
+// for containers that were not created from a bundle
+func readBundleSpec(path string) (bundleSpec, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return bundleSpec{}, nil
+		}
+
+		return bundleSpec{}, err
+	}
+
+	var spec bundleSpec
+	if err := json.Unmarshal(content, &spec); err != nil {
+		return bundleSpec{}, err
+	}
+
+	return spec, nil
+}
@@ -0,0 +1,72 @@
+package image_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/internal/image"
+)
+
+func writeBundle(t *testing.T, stateDir string, namespace string, id string, spec string) {
+	t.Helper()
+
+	bundle := filepath.Join(stateDir, namespace, id)
+
+	if err := os.MkdirAll(filepath.Join(bundle, "rootfs"), 0o755); err != nil {
+		t.Fatalf("failed to create bundle: %v", err)
+	}
+
+	if spec == "" {
+		return
+	}
+
+	if err := os.WriteFile(filepath.Join(bundle, "config.json"), []byte(spec), 0o644); err != nil {
+		t.Fatalf("failed to write bundle spec: %v", err)
+	}
+}
+
+func TestContainerdContainers(t *testing.T) {
+	t.Parallel()
+
+	stateDir := t.TempDir()
+
+	writeBundle(t, stateDir, "default", "redis", `{"annotations":{}}`)
+	writeBundle(t, stateDir, "moby", "0f3c2b", "")
+	writeBundle(t, stateDir, "k8s.io", "a1b2c3", `{
+		"annotations": {
+			"io.kubernetes.cri.container-type": "container",
+			"io.kubernetes.cri.container-name": "api",
+			"io.kubernetes.cri.sandbox-namespace": "shop",
+			"io.kubernetes.cri.sandbox-name": "api-7d9f8",
+			"io.kubernetes.cri.image-name": "ghcr.io/org/api:2.1"
+		}
+	}`)
+	writeBundle(t, stateDir, "k8s.io", "d4e5f6", `{"annotations":{"io.kubernetes.cri.container-type":"sandbox"}}`)
+
+	containers, err := image.ContainerdContainers(stateDir)
+	if err != nil {
+		t.Fatalf("ContainerdContainers() error = %v", err)
+	}
+
+	want := []image.Container{
+		{
+			ID:      "redis",
+			Name:    "default/redis",
+			Runtime: "containerd",
+			Rootfs:  filepath.Join(stateDir, "default", "redis", "rootfs"),
+		},
+		{
+			ID:      "a1b2c3",
+			Name:    "shop/api-7d9f8/api",
+			Image:   "ghcr.io/org/api:2.1",
+			Runtime: "containerd",
+			Rootfs:  filepath.Join(stateDir, "k8s.io", "a1b2c3", "rootfs"),
+		},
+	}
+
+	if diff := cmp.Diff(want, containers); diff != "" {
+		t.Errorf("ContainerdContainers() (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,137 @@
+package image
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// defaultDockerHost is where the Docker daemon listens unless DOCKER_HOST says otherwise
+const defaultDockerHost = "unix:///var/run/docker.sock"
+
+// Container is a container that is running on the host
+type Container struct {
+	ID   string
+	Name string
+	// Image is the reference of the image that the container was created from, if it is known
+	Image string
+	// Runtime is what runs the container, which is either "docker" or "containerd"
+	Runtime string
+	// Rootfs is where the filesystem of the container is mounted on the host,
+	// which is only known for containers that are run by containerd
+	Rootfs string
+}
+
+// DockerClient talks to the API of the Docker daemon, which is served
+// over a unix socket unless the daemon has been configured otherwise
+type DockerClient struct {
+	HTTPClient *http.Client
+	baseURL    string
+}
+
+// DockerHost returns the address of the Docker daemon that the Docker CLI would use
+func DockerHost() string {
+	if host := os.Getenv("DOCKER_HOST"); host != "" {
+		return host
+	}
+
+	return defaultDockerHost
+}
+
+// NewDockerClient returns a client for the Docker daemon at the host, which is
+// either a unix socket like "unix:///var/run/docker.sock" or "tcp://host:port"
+func NewDockerClient(host string) (*DockerClient, error) {
+	u, err := url.Parse(host)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Docker host %q: %w", host, err)
+	}
+
+	switch u.Scheme {
+	case "unix":
+		socket := u.Path
+		transport := &http.Transport{
+			DialContext: func(ctx context.Context, _ string, _ string) (net.Conn, error) {
+				return (&net.Dialer{}).DialContext(ctx, "unix", socket)
+			},
+		}
+
+		// the host of the URL is ignored when dialing the socket, but it still has to be valid
+		return &DockerClient{HTTPClient: &http.Client{Transport: transport}, baseURL: "http://docker"}, nil
+	case "tcp", "http":
+		return &DockerClient{HTTPClient: http.DefaultClient, baseURL: "http://" + u.Host}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported Docker host %q, only unix:// and tcp:// hosts are supported", host)
+}
+
+func (c *DockerClient) get(ctx context.Context, path string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the Docker daemon: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("failed to get %s from the Docker daemon: %s", path, resp.Status)
+	}
+
+	return resp, nil
+}
+
+// Containers lists the containers that are running
+func (c *DockerClient) Containers(ctx context.Context) ([]Container, error) {
+	resp, err := c.get(ctx, "/containers/json")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var listed []struct {
+		ID    string   `json:"Id"`
+		Names []string `json:"Names"`
+		Image string   `json:"Image"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&listed); err != nil {
+		return nil, fmt.Errorf("failed to parse containers: %w", err)
+	}
+
+	containers := make([]Container, 0, len(listed))
+	for _, l := range listed {
+		container := Container{ID: l.ID, Name: l.ID, Image: l.Image, Runtime: "docker"}
+
+		// names are listed with a leading slash, as containers used to be able to be linked under others
+		if len(l.Names) > 0 {
+			container.Name = strings.TrimPrefix(l.Names[0], "/")
+		}
+
+		containers = append(containers, container)
+	}
+
+	return containers, nil
+}
+
+// Export writes the filesystem of a running container to the directory, as `docker export` does
+func (c *DockerClient) Export(ctx context.Context, container Container, dir string) error {
+	resp, err := c.get(ctx, "/containers/"+url.PathEscape(container.ID)+"/export")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if _, err := applyLayer(resp.Body, dir); err != nil {
+		return fmt.Errorf("failed to export %s: %w", container.Name, err)
+	}
+
+	return nil
+}
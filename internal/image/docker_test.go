@@ -0,0 +1,82 @@
+package image_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/internal/image"
+)
+
+// newDockerDaemon serves the parts of the API of the Docker daemon that list and export containers
+func newDockerDaemon(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	var export bytes.Buffer
+	tw := tar.NewWriter(&export)
+	status := "Package: curl\nStatus: install ok installed\nVersion: 7.88.1-10+deb12u4\n"
+	_ = tw.WriteHeader(&tar.Header{Name: "var/lib/dpkg/status", Typeflag: tar.TypeReg, Size: int64(len(status)), Mode: 0o644})
+	_, _ = tw.Write([]byte(status))
+	_ = tw.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/containers/json":
+			_, _ = w.Write([]byte(`[{"Id":"4a5e3f","Names":["/web"],"Image":"nginx:1.25","State":"running"}]`))
+		case "/containers/4a5e3f/export":
+			_, _ = w.Write(export.Bytes())
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestDockerClient(t *testing.T) {
+	t.Parallel()
+
+	server := newDockerDaemon(t)
+
+	client, err := image.NewDockerClient(strings.Replace(server.URL, "http://", "tcp://", 1))
+	if err != nil {
+		t.Fatalf("NewDockerClient() error = %v", err)
+	}
+
+	containers, err := client.Containers(context.Background())
+	if err != nil {
+		t.Fatalf("Containers() error = %v", err)
+	}
+
+	want := []image.Container{{ID: "4a5e3f", Name: "web", Image: "nginx:1.25", Runtime: "docker"}}
+
+	if diff := cmp.Diff(want, containers); diff != "" {
+		t.Fatalf("Containers() (-want +got):\n%s", diff)
+	}
+
+	dir := t.TempDir()
+	if err := client.Export(context.Background(), containers[0], dir); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "var", "lib", "dpkg", "status"))
+	if err != nil || !strings.Contains(string(content), "Package: curl") {
+		t.Errorf("Export() wrote %q to var/lib/dpkg/status, %v", content, err)
+	}
+}
+
+func TestNewDockerClient_UnsupportedHost(t *testing.T) {
+	t.Parallel()
+
+	if _, err := image.NewDockerClient("ssh://user@host"); err == nil {
+		t.Errorf("NewDockerClient() did not return an error for an ssh host")
+	}
+}
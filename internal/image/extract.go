@@ -0,0 +1,325 @@
+package image
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	securejoin "github.com/cyphar/filepath-securejoin"
+)
+
+const (
+	// whiteoutPrefix marks a file as deleted by a layer, see
+	// https://github.com/opencontainers/image-spec/blob/main/layer.md#whiteouts
+	whiteoutPrefix = ".wh."
+	// opaqueWhiteout marks a directory as having none of the contents of the layers below it
+	opaqueWhiteout = ".wh..wh..opq"
+)
+
+var ErrUnsupportedLayer = errors.New("unsupported layer")
+
+func sha256Digest(b []byte) string {
+	sum := sha256.Sum256(b)
+
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// digestVerifier hashes what is read through it, so that the content can be checked against its digest
+type digestVerifier struct {
+	r      io.Reader
+	hasher hash.Hash
+	digest string
+}
+
+func newDigestVerifier(r io.Reader, digest string) (*digestVerifier, error) {
+	if !strings.HasPrefix(digest, "sha256:") {
+		return nil, fmt.Errorf("%w: digest %s does not use sha256", ErrUnsupportedLayer, digest)
+	}
+
+	hasher := sha256.New()
+
+	return &digestVerifier{r: io.TeeReader(r, hasher), hasher: hasher, digest: digest}, nil
+}
+
+func (v *digestVerifier) Read(p []byte) (int, error) {
+	return v.r.Read(p)
+}
+
+// verify reads the rest of the content, and checks that it matches its digest
+func (v *digestVerifier) verify() error {
+	if _, err := io.Copy(io.Discard, v.r); err != nil {
+		return err
+	}
+
+	if got := "sha256:" + hex.EncodeToString(v.hasher.Sum(nil)); got != v.digest {
+		return fmt.Errorf("content has digest %s, but expected %s", got, v.digest)
+	}
+
+	return nil
+}
+
+// decompress returns the tarball of a layer, which is usually compressed with gzip
+func decompress(r io.Reader, mediaType string) (io.Reader, error) {
+	if strings.HasSuffix(mediaType, "zstd") {
+		return nil, fmt.Errorf("%w: layers compressed with zstd are not supported", ErrUnsupportedLayer)
+	}
+
+	// some registries do not report the media type of layers accurately, so the content is checked instead
+	buffered := bufio.NewReader(r)
+	magic, err := buffered.Peek(2)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	if len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+		return gzip.NewReader(buffered)
+	}
+
+	return buffered, nil
+}
+
+// LayerFunc is called after each layer of an image is applied, with the
+// paths in the image of the files that were written by the layer
+type LayerFunc func(index int, layer Layer, files []string) error
+
+// Extract downloads the layers of the image, or reads them from the archive that it was read from,
+// and applies them in order to the directory, which results in the filesystem that a container
+// of the image starts with, calling onLayer after each of them unless it is nil
+func (c *Client) Extract(ctx context.Context, img *Image, dir string, onLayer LayerFunc) error {
+	for i, layer := range img.Layers {
+		files, err := c.extractLayer(ctx, img, layer, dir)
+		if err != nil {
+			return fmt.Errorf("failed to extract layer %d (%s) of %s: %w", i+1, layer.Digest, img.name(), err)
+		}
+
+		if onLayer == nil {
+			continue
+		}
+
+		if err := onLayer(i, layer, files); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *Client) extractLayer(ctx context.Context, img *Image, layer Layer, dir string) ([]string, error) {
+	var blob io.ReadCloser
+	var err error
+
+	if img.blobs != nil {
+		blob, err = img.blobs(layer.Digest)
+	} else {
+		blob, err = c.fetchBlob(ctx, img.Reference, layer.Digest)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer blob.Close()
+
+	verifier, err := newDigestVerifier(blob, layer.Digest)
+	if err != nil {
+		return nil, err
+	}
+
+	tarball, err := decompress(verifier, layer.MediaType)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := applyLayer(tarball, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	return files, verifier.verify()
+}
+
+// resolveInRoot returns where the path of an entry of a layer is in the root, resolving any
+// symlinks of the directories that it is in within the root rather than the host, but not
+// the entry itself, as that is what is being replaced
+func resolveInRoot(root string, name string) (string, error) {
+	name = path.Clean("/" + name)
+
+	if name == "/" {
+		return root, nil
+	}
+
+	parent, err := securejoin.SecureJoin(root, path.Dir(name))
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(parent, path.Base(name)), nil
+}
+
+// applyLayer applies the changes of the tarball of a layer to the filesystem at the root,
+// deleting the files that it whites out and writing the files that it has, but not
+// special files such as devices as only the contents of the filesystem are needed.
+// The paths of the regular files that it writes are returned in the order of the tarball
+func applyLayer(r io.Reader, root string) ([]string, error) {
+	tr := tar.NewReader(r)
+
+	// files written by this layer are not removed by its opaque whiteouts
+	written := make(map[string]bool)
+	var opaque []string
+	var files []string
+
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		name := path.Clean("/" + header.Name)
+		base := path.Base(name)
+
+		if base == opaqueWhiteout {
+			opaque = append(opaque, path.Dir(name))
+			continue
+		}
+
+		target, err := resolveInRoot(root, name)
+		if err != nil {
+			return nil, err
+		}
+
+		if strings.HasPrefix(base, whiteoutPrefix) {
+			deleted := filepath.Join(filepath.Dir(target), strings.TrimPrefix(base, whiteoutPrefix))
+			if err := os.RemoveAll(deleted); err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		if err := writeEntry(tr, header, root, name, target); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", name, err)
+		}
+
+		written[name] = true
+
+		if header.Typeflag == tar.TypeReg || header.Typeflag == tar.TypeLink {
+			files = append(files, name)
+		}
+	}
+
+	for _, dir := range opaque {
+		if err := removeContents(root, dir, written); err != nil {
+			return nil, err
+		}
+	}
+
+	return files, nil
+}
+
+// removeContents removes the contents of a directory that were not written by the current layer
+func removeContents(root string, dir string, written map[string]bool) error {
+	target, err := securejoin.SecureJoin(root, dir)
+	if err != nil {
+		return err
+	}
+
+	entries, err := os.ReadDir(target)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+
+		return err
+	}
+
+	for _, entry := range entries {
+		name := path.Join(dir, entry.Name())
+
+		if written[name] {
+			if entry.IsDir() {
+				if err := removeContents(root, name, written); err != nil {
+					return err
+				}
+			}
+
+			continue
+		}
+
+		if err := os.RemoveAll(filepath.Join(target, entry.Name())); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// rootedLinkname returns what a symlink with the given name that links to linkname should link to
+// from where it is in the root, which is made relative with any parents beyond the root removed, so
+// that it is resolved within the root rather than the filesystem of the host
+func rootedLinkname(root string, name string, target string, linkname string) (string, error) {
+	if !path.IsAbs(linkname) {
+		linkname = path.Join(path.Dir(name), linkname)
+	}
+
+	return filepath.Rel(filepath.Dir(target), filepath.Join(root, filepath.FromSlash(path.Clean("/"+linkname))))
+}
+
+// writeEntry writes an entry of a layer with the given name to where it is in the root
+func writeEntry(r io.Reader, header *tar.Header, root string, name string, target string) error {
+	// directories are kept so that the contents of lower layers are not lost,
+	// but anything else that is replaced by the entry is removed first
+	if info, err := os.Lstat(target); err == nil && !(info.IsDir() && header.Typeflag == tar.TypeDir) {
+		if err := os.RemoveAll(target); err != nil {
+			return err
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+
+	switch header.Typeflag {
+	case tar.TypeDir:
+		return os.MkdirAll(target, 0o755)
+	case tar.TypeReg:
+		file, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+		if err != nil {
+			return err
+		}
+
+		_, err = io.Copy(file, r)
+		if errClose := file.Close(); err == nil {
+			err = errClose
+		}
+
+		return err
+	case tar.TypeSymlink:
+		linkname, err := rootedLinkname(root, name, target, header.Linkname)
+		if err != nil {
+			return err
+		}
+
+		return os.Symlink(linkname, target)
+	case tar.TypeLink:
+		source, err := securejoin.SecureJoin(root, header.Linkname)
+		if err != nil {
+			return err
+		}
+
+		return os.Link(source, target)
+	}
+
+	return nil
+}
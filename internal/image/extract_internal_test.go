@@ -0,0 +1,150 @@
+package image
+
+import (
+	"archive/tar"
+	"bytes"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type tarEntry struct {
+	name     string
+	typeflag byte
+	content  string
+	linkname string
+}
+
+func buildLayer(t *testing.T, entries []tarEntry) *bytes.Buffer {
+	t.Helper()
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+
+	for _, entry := range entries {
+		header := &tar.Header{
+			Name:     entry.name,
+			Typeflag: entry.typeflag,
+			Linkname: entry.linkname,
+			Size:     int64(len(entry.content)),
+			Mode:     0o644,
+		}
+
+		if err := tw.WriteHeader(header); err != nil {
+			t.Fatalf("failed to write header: %v", err)
+		}
+		if _, err := tw.Write([]byte(entry.content)); err != nil {
+			t.Fatalf("failed to write content: %v", err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		t.Fatalf("failed to close tarball: %v", err)
+	}
+
+	return &buf
+}
+
+// listFiles returns the regular files in the root along with their content
+func listFiles(t *testing.T, root string) map[string]string {
+	t.Helper()
+
+	files := make(map[string]string)
+
+	err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		rel, _ := filepath.Rel(root, path)
+		files[filepath.ToSlash(rel)] = string(content)
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to list files: %v", err)
+	}
+
+	return files
+}
+
+func TestApplyLayer(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+
+	layers := [][]tarEntry{
+		{
+			{name: "etc/", typeflag: tar.TypeDir},
+			{name: "etc/os-release", typeflag: tar.TypeReg, content: "ID=debian\n"},
+			{name: "var/lib/dpkg/status", typeflag: tar.TypeReg, content: "Package: base-files\n"},
+			{name: "app/", typeflag: tar.TypeDir},
+			{name: "app/old.txt", typeflag: tar.TypeReg, content: "old"},
+			{name: "tmp/cache.txt", typeflag: tar.TypeReg, content: "cache"},
+		},
+		{
+			{name: "var/lib/dpkg/status", typeflag: tar.TypeReg, content: "Package: curl\n"},
+			{name: "tmp/.wh.cache.txt", typeflag: tar.TypeReg},
+			{name: "app/.wh..wh..opq", typeflag: tar.TypeReg},
+			{name: "app/package-lock.json", typeflag: tar.TypeReg, content: "{}"},
+			{name: "usr/lib/os-release", typeflag: tar.TypeSymlink, linkname: "/etc/os-release"},
+			{name: "escape", typeflag: tar.TypeSymlink, linkname: "../../../../etc"},
+			{name: "escape/passwd", typeflag: tar.TypeReg, content: "root"},
+		},
+	}
+
+	var files [][]string
+	for _, layer := range layers {
+		written, err := applyLayer(buildLayer(t, layer), root)
+		if err != nil {
+			t.Fatalf("applyLayer() error = %v", err)
+		}
+
+		files = append(files, written)
+	}
+
+	wantWritten := [][]string{
+		{"/etc/os-release", "/var/lib/dpkg/status", "/app/old.txt", "/tmp/cache.txt"},
+		{"/var/lib/dpkg/status", "/app/package-lock.json", "/escape/passwd"},
+	}
+
+	if diff := cmp.Diff(wantWritten, files); diff != "" {
+		t.Errorf("applyLayer() written (-want +got):\n%s", diff)
+	}
+
+	want := map[string]string{
+		"etc/os-release":        "ID=debian\n",
+		"etc/passwd":            "root",
+		"var/lib/dpkg/status":   "Package: curl\n",
+		"app/package-lock.json": "{}",
+	}
+
+	if diff := cmp.Diff(want, listFiles(t, root)); diff != "" {
+		t.Errorf("applyLayer() files (-want +got):\n%s", diff)
+	}
+
+	// links are resolved within the root
+	content, err := os.ReadFile(filepath.Join(root, "usr", "lib", "os-release"))
+	if err != nil || string(content) != "ID=debian\n" {
+		t.Errorf("usr/lib/os-release = %q, %v, want a link to etc/os-release", content, err)
+	}
+
+	entries, _ := os.ReadDir(root)
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+
+	if diff := cmp.Diff([]string{"app", "escape", "etc", "tmp", "usr", "var"}, names); diff != "" {
+		t.Errorf("applyLayer() root (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,383 @@
+package image
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+var ErrUnsupportedFirmware = errors.New("unsupported firmware")
+
+// squashfsMagic is what squashfs 4.0 filesystems start with, which are always little-endian
+var squashfsMagic = []byte("hsqs")
+
+// cpioTrailer is the name of the entry that marks the end of a cpio archive
+const cpioTrailer = "TRAILER!!!"
+
+// cpioHeaderSize is the size of the header of the entries of cpio archives in the "newc" format, which
+// is what the Linux kernel unpacks initramfs from, made up of a magic and 13 fields of 8 hex digits
+const cpioHeaderSize = 110
+
+// firmwareCompressions are the compressions of initramfs that are recognised but not supported,
+// keyed by their magic, as only those compressed with gzip can be decompressed
+var firmwareCompressions = map[string]string{
+	"\xfd7zXZ\x00":     "xz",
+	"\x28\xb5\x2f\xfd": "zstd",
+	"\x02\x21\x4c\x18": "lz4",
+	"BZh":              "bzip2",
+	"\x5d\x00\x00":     "lzma",
+}
+
+// findSquashfs returns the offset of the squashfs filesystem in a firmware image, which is either the
+// whole image or a part of it, like the root filesystem of a sysupgrade image of OpenWrt that comes after
+// its kernel, or -1 if it does not have one. Filesystems are told apart from their magic appearing by
+// chance by their version and their size, which are in their superblock
+func findSquashfs(f *os.File) (int64, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return -1, err
+	}
+
+	const chunkSize = 1 << 20
+
+	buf := make([]byte, chunkSize+len(squashfsMagic)-1)
+	superblock := make([]byte, 48)
+
+	for offset := int64(0); offset < info.Size(); offset += chunkSize {
+		n, err := f.ReadAt(buf, offset)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return -1, err
+		}
+
+		for i := 0; ; {
+			j := bytes.Index(buf[i:n], squashfsMagic)
+			if j < 0 {
+				break
+			}
+
+			at := offset + int64(i+j)
+			i += j + 1
+
+			if _, err := f.ReadAt(superblock, at); err != nil {
+				continue
+			}
+
+			major := binary.LittleEndian.Uint16(superblock[28:])
+			size := binary.LittleEndian.Uint64(superblock[40:])
+
+			if major == 4 && size > 0 && size <= uint64(info.Size()-at) {
+				return at, nil
+			}
+		}
+	}
+
+	return -1, nil
+}
+
+// extractSquashfs extracts the squashfs filesystem at the offset of a firmware image to the directory
+// with unsquashfs, as squashfs can be compressed with algorithms that the standard library does not have
+func extractSquashfs(path string, offset int64, dir string) error {
+	args := []string{"-no-xattrs", "-force", "-dest", dir}
+	if offset > 0 {
+		args = append(args, "-offset", strconv.FormatInt(offset, 10))
+	}
+
+	cmd := exec.Command("unsquashfs", append(args, path)...)
+
+	out, err := cmd.CombinedOutput()
+	if errors.Is(err, exec.ErrNotFound) {
+		return fmt.Errorf("%w: unsquashfs from squashfs-tools is needed to extract squashfs filesystems", ErrUnsupportedFirmware)
+	}
+	if err != nil {
+		return fmt.Errorf("unsquashfs failed: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+
+	return confineSymlinks(dir)
+}
+
+// confineSymlinks rewrites the symlinks of the filesystem that was extracted to the root, so that
+// they are resolved within the root rather than the filesystem of the host, like those of layers
+func confineSymlinks(root string) error {
+	return filepath.WalkDir(root, func(target string, d fs.DirEntry, err error) error {
+		if err != nil || d.Type()&fs.ModeSymlink == 0 {
+			return err
+		}
+
+		linkname, err := os.Readlink(target)
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(root, target)
+		if err != nil {
+			return err
+		}
+
+		linkname, err = rootedLinkname(root, "/"+filepath.ToSlash(rel), target, linkname)
+		if err != nil {
+			return err
+		}
+
+		if err := os.Remove(target); err != nil {
+			return err
+		}
+
+		return os.Symlink(linkname, target)
+	})
+}
+
+// parseCPIOHeader parses the header of an entry of a cpio archive in the "newc" format, returning the
+// mode of the entry, the number of links to it, its inode, the size of its content, and the size of its name
+func parseCPIOHeader(header []byte) (mode, nlink, ino, size, nameSize uint64, err error) {
+	field := func(i int) uint64 {
+		if err != nil {
+			return 0
+		}
+
+		var v uint64
+		v, err = strconv.ParseUint(string(header[6+i*8:14+i*8]), 16, 32)
+
+		return v
+	}
+
+	ino, mode, nlink, size, nameSize = field(0), field(1), field(4), field(6), field(11)
+
+	return mode, nlink, ino, size, nameSize, err
+}
+
+// cpioPadding returns how many bytes pad a part of an entry of a cpio archive to 4 bytes
+func cpioPadding(n uint64) int64 {
+	return int64((4 - n%4) % 4)
+}
+
+// extractCPIO extracts a cpio archive in the "newc" format from the reader to the root, up to its trailer.
+// Hard links only have their content in the last of their entries, so the others are linked to it after
+func extractCPIO(r io.Reader, root string) error {
+	header := make([]byte, cpioHeaderSize)
+	hardlinks := make(map[uint64][]string)
+
+	for {
+		if _, err := io.ReadFull(r, header); err != nil {
+			return err
+		}
+
+		if magic := string(header[:6]); magic != "070701" && magic != "070702" {
+			return fmt.Errorf("%w: unsupported cpio entry %q", ErrUnsupportedFirmware, magic)
+		}
+
+		mode, nlink, ino, size, nameSize, err := parseCPIOHeader(header)
+		if err != nil {
+			return fmt.Errorf("malformed cpio entry: %w", err)
+		}
+
+		nameBytes := make([]byte, nameSize)
+		if _, err := io.ReadFull(r, nameBytes); err != nil {
+			return err
+		}
+		if _, err := io.CopyN(io.Discard, r, cpioPadding(cpioHeaderSize+nameSize)); err != nil {
+			return err
+		}
+
+		rawName := strings.TrimRight(string(nameBytes), "\x00")
+		if rawName == cpioTrailer {
+			// hard links to empty files do not have any of their entries with content
+			for _, links := range hardlinks {
+				for _, link := range links {
+					if err := writeFirmwareEntry(strings.NewReader(""), &tar.Header{Typeflag: tar.TypeReg}, root, link); err != nil {
+						return err
+					}
+				}
+			}
+
+			return nil
+		}
+
+		content := io.LimitReader(r, int64(size))
+		name := path.Clean("/" + rawName)
+
+		var entry *tar.Header
+
+		switch mode & 0o170000 {
+		case 0o040000:
+			entry = &tar.Header{Typeflag: tar.TypeDir}
+		case 0o100000:
+			entry = &tar.Header{Typeflag: tar.TypeReg}
+		case 0o120000:
+			linkname, err := io.ReadAll(content)
+			if err != nil {
+				return err
+			}
+
+			entry = &tar.Header{Typeflag: tar.TypeSymlink, Linkname: string(linkname)}
+		}
+
+		if entry != nil && name != "/" {
+			if entry.Typeflag == tar.TypeReg && nlink > 1 && size == 0 {
+				hardlinks[ino] = append(hardlinks[ino], name)
+			} else if err := writeFirmwareEntry(content, entry, root, name); err != nil {
+				return err
+			}
+
+			if entry.Typeflag == tar.TypeReg && nlink > 1 && size > 0 {
+				for _, link := range hardlinks[ino] {
+					if err := writeFirmwareEntry(nil, &tar.Header{Typeflag: tar.TypeLink, Linkname: name}, root, link); err != nil {
+						return err
+					}
+				}
+
+				delete(hardlinks, ino)
+			}
+		}
+
+		if _, err := io.Copy(io.Discard, content); err != nil {
+			return err
+		}
+		if _, err := io.CopyN(io.Discard, r, cpioPadding(size)); err != nil {
+			return err
+		}
+	}
+}
+
+// writeFirmwareEntry writes an entry of a firmware image with the given name to where it is in the root
+func writeFirmwareEntry(r io.Reader, header *tar.Header, root string, name string) error {
+	target, err := resolveInRoot(root, name)
+	if err != nil {
+		return err
+	}
+
+	if err := writeEntry(r, header, root, name, target); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// isInitramfs returns whether the magic that a firmware image starts with is of an initramfs,
+// which is either a cpio archive, or one that has been compressed
+func isInitramfs(magic []byte) bool {
+	if bytes.HasPrefix(magic, []byte("07070")) || bytes.HasPrefix(magic, []byte{0x1f, 0x8b}) {
+		return true
+	}
+
+	for prefix := range firmwareCompressions {
+		if bytes.HasPrefix(magic, []byte(prefix)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// extractInitramfs extracts an initramfs to the root, which is one or more cpio archives that are
+// concatenated together and can each be compressed, like the uncompressed archive of the microcode
+// of the CPU that comes before the compressed root filesystem. It returns how many archives it extracted
+func extractInitramfs(r *bufio.Reader, root string) (int, error) {
+	extracted := 0
+
+	for {
+		// archives are padded with zeros between them
+		b, err := r.Peek(1)
+		if errors.Is(err, io.EOF) {
+			return extracted, nil
+		}
+		if err != nil {
+			return extracted, err
+		}
+		if b[0] == 0 {
+			if _, err := r.Discard(1); err != nil {
+				return extracted, err
+			}
+
+			continue
+		}
+
+		magic, _ := r.Peek(6)
+
+		switch {
+		case bytes.HasPrefix(magic, []byte("07070")):
+			if err := extractCPIO(r, root); err != nil {
+				return extracted, err
+			}
+
+			extracted++
+
+			continue
+		case bytes.HasPrefix(magic, []byte{0x1f, 0x8b}):
+			gz, err := gzip.NewReader(r)
+			if err != nil {
+				return extracted, err
+			}
+
+			// only the current member is read, so that what comes after it can be extracted too
+			gz.Multistream(false)
+
+			n, err := extractInitramfs(bufio.NewReader(gz), root)
+			if err != nil {
+				return extracted, err
+			}
+
+			extracted += n
+
+			continue
+		}
+
+		for prefix, compression := range firmwareCompressions {
+			if bytes.HasPrefix(magic, []byte(prefix)) {
+				return extracted, fmt.Errorf("%w: initramfs compressed with %s is not supported", ErrUnsupportedFirmware, compression)
+			}
+		}
+
+		// anything after the last archive, like a signature, is ignored
+		return extracted, nil
+	}
+}
+
+// ExtractFirmware extracts the root filesystem of a firmware image to the directory, which is either a
+// squashfs filesystem, like those of OpenWrt and many other embedded devices, or a cpio archive like an
+// initramfs. Squashfs filesystems are found within the image if they do not start it, such as those that
+// come after a kernel, and are extracted with unsquashfs, which has to be installed
+func ExtractFirmware(path string, dir string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	magic, _ := r.Peek(6)
+
+	// archives of an initramfs can contain squashfs filesystems, so they are checked for first
+	if isInitramfs(magic) {
+		extracted, err := extractInitramfs(r, dir)
+		if err != nil {
+			return fmt.Errorf("failed to extract initramfs: %w", err)
+		}
+		if extracted == 0 {
+			return fmt.Errorf("%w: %s does not have a cpio archive", ErrUnsupportedFirmware, path)
+		}
+
+		return nil
+	}
+
+	offset, err := findSquashfs(f)
+	if err != nil {
+		return err
+	}
+	if offset < 0 {
+		return fmt.Errorf("%w: %s is not a squashfs filesystem or cpio archive", ErrUnsupportedFirmware, path)
+	}
+
+	return extractSquashfs(path, offset, dir)
+}
@@ -0,0 +1,222 @@
+package image_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/osv-scanner/internal/image"
+)
+
+type cpioEntry struct {
+	name    string
+	mode    uint32
+	ino     uint32
+	nlink   uint32
+	content string
+}
+
+// cpioArchive returns a cpio archive in the "newc" format of the entries, ending with its trailer
+func cpioArchive(entries ...cpioEntry) []byte {
+	var buf bytes.Buffer
+
+	pad := func() {
+		for buf.Len()%4 != 0 {
+			buf.WriteByte(0)
+		}
+	}
+
+	for _, entry := range append(entries, cpioEntry{name: "TRAILER!!!", nlink: 1}) {
+		fmt.Fprintf(
+			&buf,
+			"070701%08X%08X%08X%08X%08X%08X%08X%08X%08X%08X%08X%08X%08X",
+			entry.ino, entry.mode, 0, 0, entry.nlink, 0, len(entry.content), 0, 0, 0, 0, len(entry.name)+1, 0,
+		)
+		buf.WriteString(entry.name + "\x00")
+		pad()
+		buf.WriteString(entry.content)
+		pad()
+	}
+
+	return buf.Bytes()
+}
+
+func gzipped(t *testing.T, b []byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(b); err != nil {
+		t.Fatalf("failed to compress: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("failed to compress: %v", err)
+	}
+
+	return buf.Bytes()
+}
+
+func writeFirmware(t *testing.T, b []byte) string {
+	t.Helper()
+
+	p := filepath.Join(t.TempDir(), "firmware.bin")
+	if err := os.WriteFile(p, b, 0o600); err != nil {
+		t.Fatalf("failed to write firmware: %v", err)
+	}
+
+	return p
+}
+
+var rootfs = cpioArchive(
+	cpioEntry{name: ".", mode: 0o40755, nlink: 2},
+	cpioEntry{name: "etc", mode: 0o40755, nlink: 2},
+	cpioEntry{name: "usr/lib/os-release", mode: 0o100644, nlink: 1, content: "NAME=\"OpenWrt\"\nVERSION_ID=\"23.05.2\"\n"},
+	cpioEntry{name: "etc/os-release", mode: 0o120777, nlink: 1, content: "/usr/lib/os-release"},
+	cpioEntry{name: "bin/sh", mode: 0o100755, ino: 7, nlink: 2},
+	cpioEntry{name: "bin/busybox", mode: 0o100755, ino: 7, nlink: 2, content: "busybox"},
+	cpioEntry{name: "dev/console", mode: 0o20600, nlink: 1},
+)
+
+func expectFile(t *testing.T, dir string, name string, want string) {
+	t.Helper()
+
+	got, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+	if err != nil {
+		t.Errorf("failed to read %s: %v", name, err)
+		return
+	}
+
+	if string(got) != want {
+		t.Errorf("expected %s to be %q, but got %q", name, want, got)
+	}
+}
+
+func TestExtractFirmware_CPIO(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	if err := image.ExtractFirmware(writeFirmware(t, rootfs), dir); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectFile(t, dir, "usr/lib/os-release", "NAME=\"OpenWrt\"\nVERSION_ID=\"23.05.2\"\n")
+	expectFile(t, dir, "etc/os-release", "NAME=\"OpenWrt\"\nVERSION_ID=\"23.05.2\"\n")
+	expectFile(t, dir, "bin/busybox", "busybox")
+	expectFile(t, dir, "bin/sh", "busybox")
+
+	// links are resolved within the filesystem rather than that of the host
+	linkname, err := os.Readlink(filepath.Join(dir, "etc", "os-release"))
+	if err != nil {
+		t.Fatalf("failed to read link: %v", err)
+	}
+	if filepath.IsAbs(linkname) {
+		t.Errorf("expected link to be relative, but got %s", linkname)
+	}
+
+	if _, err := os.Lstat(filepath.Join(dir, "dev", "console")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected devices to not be extracted, but got %v", err)
+	}
+
+	if got := image.OSRelease(dir); got != "OpenWrt 23.05.2" {
+		t.Errorf("expected the release to be OpenWrt 23.05.2, but got %s", got)
+	}
+}
+
+func TestExtractFirmware_Initramfs(t *testing.T) {
+	t.Parallel()
+
+	// the microcode of the CPU comes first, uncompressed and padded, before the compressed root filesystem
+	microcode := cpioArchive(cpioEntry{name: "kernel/x86/microcode/GenuineIntel.bin", mode: 0o100644, nlink: 1, content: "microcode"})
+	microcode = append(microcode, make([]byte, 512-len(microcode)%512)...)
+
+	dir := t.TempDir()
+
+	if err := image.ExtractFirmware(writeFirmware(t, append(microcode, gzipped(t, rootfs)...)), dir); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectFile(t, dir, "kernel/x86/microcode/GenuineIntel.bin", "microcode")
+	expectFile(t, dir, "bin/busybox", "busybox")
+}
+
+func TestExtractFirmware_PathTraversal(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "rootfs")
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+
+	archive := cpioArchive(cpioEntry{name: "../../escaped", mode: 0o100644, nlink: 1, content: "escaped"})
+
+	if err := image.ExtractFirmware(writeFirmware(t, archive), dir); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectFile(t, dir, "escaped", "escaped")
+}
+
+func TestExtractFirmware_Unsupported(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		firmware []byte
+		want     string
+	}{
+		{
+			name:     "not firmware",
+			firmware: []byte("this is not firmware"),
+			want:     "is not a squashfs filesystem or cpio archive",
+		},
+		{
+			name:     "xz",
+			firmware: []byte("\xfd7zXZ\x00\x00\x04"),
+			want:     "initramfs compressed with xz is not supported",
+		},
+		{
+			name:     "truncated",
+			firmware: cpioArchive(cpioEntry{name: "bin/busybox", mode: 0o100755, nlink: 1, content: "busybox"})[:120],
+			want:     "failed to extract initramfs",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := image.ExtractFirmware(writeFirmware(t, tt.firmware), t.TempDir())
+
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, but got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+//nolint:paralleltest // PATH is changed so that unsquashfs cannot be found
+func TestExtractFirmware_SquashfsWithoutUnsquashfs(t *testing.T) {
+	t.Setenv("PATH", "")
+
+	// a squashfs filesystem after a kernel, which is found by its superblock
+	superblock := make([]byte, 96)
+	copy(superblock, "hsqs")
+	binary.LittleEndian.PutUint16(superblock[28:], 4)
+	binary.LittleEndian.PutUint64(superblock[40:], 96)
+
+	firmware := append(bytes.Repeat([]byte("kernel hsqs "), 100), superblock...)
+
+	err := image.ExtractFirmware(writeFirmware(t, firmware), t.TempDir())
+
+	if !errors.Is(err, image.ErrUnsupportedFirmware) || !strings.Contains(err.Error(), "unsquashfs") {
+		t.Errorf("expected unsquashfs to be needed, but got %v", err)
+	}
+}
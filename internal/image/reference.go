@@ -0,0 +1,101 @@
+package image
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	// dockerHubRegistry is the registry of references that do not name one
+	dockerHubRegistry = "docker.io"
+	// dockerHubHost is where the API of Docker Hub is served from
+	dockerHubHost = "registry-1.docker.io"
+)
+
+var ErrInvalidReference = errors.New("invalid image reference")
+
+// Reference is a reference to an image in a registry, such as "ghcr.io/org/app:tag"
+type Reference struct {
+	Registry   string
+	Repository string
+	// Tag is the tag of the image, which is "latest" if neither a tag nor a digest is given
+	Tag string
+	// Digest is the digest of the manifest of the image, which takes precedence over the tag
+	Digest string
+}
+
+// ParseReference parses a reference to an image like Docker does, so images
+// without a registry are from Docker Hub, and official images are in "library"
+func ParseReference(s string) (Reference, error) {
+	ref := Reference{}
+	remainder := s
+
+	if before, after, ok := strings.Cut(remainder, "@"); ok {
+		if !strings.Contains(after, ":") {
+			return Reference{}, fmt.Errorf("%w %q: digest must be of the form algorithm:hex", ErrInvalidReference, s)
+		}
+
+		remainder, ref.Digest = before, after
+	}
+
+	// a colon after the last slash separates the tag, while one before it is the port of the registry
+	if i := strings.LastIndex(remainder, ":"); i > strings.LastIndex(remainder, "/") {
+		remainder, ref.Tag = remainder[:i], remainder[i+1:]
+	}
+
+	// the first component is only a registry if it looks like a host
+	if first, rest, ok := strings.Cut(remainder, "/"); ok && (strings.ContainsAny(first, ".:") || first == "localhost") {
+		ref.Registry, remainder = first, rest
+	} else {
+		ref.Registry = dockerHubRegistry
+	}
+
+	if remainder == "" || strings.HasSuffix(remainder, "/") || remainder != strings.ToLower(remainder) {
+		return Reference{}, fmt.Errorf("%w %q: repository must be lowercase and not empty", ErrInvalidReference, s)
+	}
+
+	if ref.Registry == dockerHubRegistry && !strings.Contains(remainder, "/") {
+		remainder = "library/" + remainder
+	}
+
+	ref.Repository = remainder
+
+	if ref.Tag == "" && ref.Digest == "" {
+		ref.Tag = "latest"
+	}
+
+	return ref, nil
+}
+
+// host returns where the API of the registry is served from
+func (ref Reference) host() string {
+	if ref.Registry == dockerHubRegistry {
+		return dockerHubHost
+	}
+
+	return ref.Registry
+}
+
+// identifier returns what the manifest of the image is fetched by
+func (ref Reference) identifier() string {
+	if ref.Digest != "" {
+		return ref.Digest
+	}
+
+	return ref.Tag
+}
+
+func (ref Reference) String() string {
+	s := ref.Registry + "/" + ref.Repository
+
+	if ref.Tag != "" {
+		s += ":" + ref.Tag
+	}
+
+	if ref.Digest != "" {
+		s += "@" + ref.Digest
+	}
+
+	return s
+}
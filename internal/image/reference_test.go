@@ -0,0 +1,78 @@
+package image_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/internal/image"
+)
+
+func TestParseReference(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		s    string
+		want image.Reference
+	}{
+		{
+			s:    "alpine",
+			want: image.Reference{Registry: "docker.io", Repository: "library/alpine", Tag: "latest"},
+		},
+		{
+			s:    "grafana/grafana:10.2.3",
+			want: image.Reference{Registry: "docker.io", Repository: "grafana/grafana", Tag: "10.2.3"},
+		},
+		{
+			s:    "ghcr.io/org/app:tag",
+			want: image.Reference{Registry: "ghcr.io", Repository: "org/app", Tag: "tag"},
+		},
+		{
+			s:    "localhost:5000/app",
+			want: image.Reference{Registry: "localhost:5000", Repository: "app", Tag: "latest"},
+		},
+		{
+			s: "gcr.io/distroless/base@sha256:9d4e5680d67c984ac9c957f66405de25634012e2d5d6dc396c4bdd2ba6ae569f",
+			want: image.Reference{
+				Registry:   "gcr.io",
+				Repository: "distroless/base",
+				Digest:     "sha256:9d4e5680d67c984ac9c957f66405de25634012e2d5d6dc396c4bdd2ba6ae569f",
+			},
+		},
+		{
+			s: "debian:12@sha256:9d4e5680d67c984ac9c957f66405de25634012e2d5d6dc396c4bdd2ba6ae569f",
+			want: image.Reference{
+				Registry:   "docker.io",
+				Repository: "library/debian",
+				Tag:        "12",
+				Digest:     "sha256:9d4e5680d67c984ac9c957f66405de25634012e2d5d6dc396c4bdd2ba6ae569f",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.s, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := image.ParseReference(tt.s)
+			if err != nil {
+				t.Fatalf("ParseReference() error = %v", err)
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ParseReference() (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseReference_Invalid(t *testing.T) {
+	t.Parallel()
+
+	for _, s := range []string{"", "ghcr.io/Org/App", "alpine@1234"} {
+		if _, err := image.ParseReference(s); !errors.Is(err, image.ErrInvalidReference) {
+			t.Errorf("ParseReference(%q) error = %v, want %v", s, err, image.ErrInvalidReference)
+		}
+	}
+}
@@ -0,0 +1,538 @@
+package image
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+)
+
+const (
+	mediaTypeOCIIndex           = "application/vnd.oci.image.index.v1+json"
+	mediaTypeOCIManifest        = "application/vnd.oci.image.manifest.v1+json"
+	mediaTypeDockerManifestList = "application/vnd.docker.distribution.manifest.list.v2+json"
+	mediaTypeDockerManifest     = "application/vnd.docker.distribution.manifest.v2+json"
+)
+
+const (
+	// annotationBaseName is the reference of the image that an image was built on, see
+	// https://github.com/opencontainers/image-spec/blob/main/annotations.md#pre-defined-annotation-keys
+	annotationBaseName = "org.opencontainers.image.base.name"
+	// annotationBaseDigest is the digest of the manifest of the image that an image was built on
+	annotationBaseDigest = "org.opencontainers.image.base.digest"
+)
+
+var ErrNoMatchingPlatform = errors.New("image does not have a manifest for the platform")
+
+// errNotFound is returned when the registry does not have what was asked for
+var errNotFound = errors.New("not found")
+
+// Platform is the operating system and CPU architecture that an image is built for
+type Platform struct {
+	OS           string `json:"os"`
+	Architecture string `json:"architecture"`
+	Variant      string `json:"variant,omitempty"`
+}
+
+// DefaultPlatform is the platform of the machine that the scanner is running on,
+// which is what Docker would pull an image for
+func DefaultPlatform() Platform {
+	return Platform{OS: "linux", Architecture: runtime.GOARCH}
+}
+
+// ParsePlatform parses a platform in the form that Docker takes it in, such as "linux/arm64/v8"
+func ParsePlatform(s string) (Platform, error) {
+	parts := strings.Split(s, "/")
+
+	if len(parts) < 2 || len(parts) > 3 || slices.Contains(parts, "") {
+		return Platform{}, fmt.Errorf("invalid platform %q, must be of the form os/architecture[/variant]", s)
+	}
+
+	platform := Platform{OS: parts[0], Architecture: parts[1]}
+	if len(parts) == 3 {
+		platform.Variant = parts[2]
+	}
+
+	return platform, nil
+}
+
+func (p Platform) String() string {
+	s := p.OS + "/" + p.Architecture
+
+	if p.Variant != "" {
+		s += "/" + p.Variant
+	}
+
+	return s
+}
+
+// descriptor describes content in a registry, such as a manifest or a layer
+type descriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Platform    *Platform         `json:"platform,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// ArtifactType is what a manifest that refers to another is, such as an attestation
+	ArtifactType string `json:"artifactType,omitempty"`
+}
+
+// manifest is either the manifest of an image or an index of the manifests of
+// an image for each of the platforms that it is built for
+type manifest struct {
+	MediaType   string            `json:"mediaType"`
+	Config      descriptor        `json:"config"`
+	Layers      []descriptor      `json:"layers"`
+	Manifests   []descriptor      `json:"manifests"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// history is a step of the build of an image, as recorded in its configuration
+type history struct {
+	CreatedBy string `json:"created_by"`
+	// EmptyLayer is true if the step only changed the configuration of the image, like ENV
+	EmptyLayer bool `json:"empty_layer"`
+}
+
+// config is the part of the configuration of an image that records how it was built
+type config struct {
+	OS           string    `json:"os"`
+	Architecture string    `json:"architecture"`
+	Variant      string    `json:"variant"`
+	History      []history `json:"history"`
+	RootFS       struct {
+		// DiffIDs are the digests of the uncompressed tarballs of the layers
+		DiffIDs []string `json:"diff_ids"`
+	} `json:"rootfs"`
+}
+
+// Layer is a layer of an image, which is a tarball of the changes that it makes to the filesystem
+type Layer struct {
+	Digest    string
+	MediaType string
+	Size      int64
+	// Command is the Dockerfile instruction that created the layer, which is
+	// empty if the image was built without recording its history
+	Command string
+}
+
+// Image is an image in a registry whose manifest has been fetched
+type Image struct {
+	Reference Reference
+	// Digest is the digest of the manifest of the image for its platform
+	Digest string
+	// IndexDigest is the digest of the index that the manifest was chosen from,
+	// which is empty if the image is only built for one platform
+	IndexDigest string
+	Platform    Platform
+	Layers      []Layer
+	Annotations map[string]string
+
+	// archive is the path of the archive that the image was read from, which is empty for images in a registry
+	archive string
+	// blobs opens the blobs of an image that was read from an archive, instead of fetching them from its registry
+	blobs func(digest string) (io.ReadCloser, error)
+}
+
+// name is what the image is referred to as in errors, which is the archive that it was read from if it was
+func (img *Image) name() string {
+	if img.archive != "" {
+		return img.archive
+	}
+
+	return img.Reference.String()
+}
+
+// BaseImage returns the reference of the image that the image was built on, pinned to the digest
+// that it had when the image was built if that is known, or false if the image does not have
+// the annotations that record it, which builders only add when asked to
+func (img *Image) BaseImage() (Reference, bool) {
+	name := img.Annotations[annotationBaseName]
+	if name == "" {
+		return Reference{}, false
+	}
+
+	ref, err := ParseReference(name)
+	if err != nil {
+		return Reference{}, false
+	}
+
+	if digest := img.Annotations[annotationBaseDigest]; digest != "" && ref.Digest == "" {
+		ref.Digest = digest
+	}
+
+	return ref, true
+}
+
+// Client fetches images from registries using the OCI distribution API,
+// authenticating with the credentials that the Docker CLI is logged in with
+type Client struct {
+	HTTPClient *http.Client
+	// Scheme is "https" unless the registry is only served over plain HTTP
+	Scheme string
+
+	mu     sync.Mutex
+	tokens map[string]string
+}
+
+func NewClient() *Client {
+	return &Client{
+		HTTPClient: http.DefaultClient,
+		Scheme:     "https",
+		tokens:     make(map[string]string),
+	}
+}
+
+// parseChallenge parses the WWW-Authenticate header of a response, which is a
+// scheme followed by comma separated parameters like realm="https://auth.docker.io/token"
+func parseChallenge(header string) (string, map[string]string) {
+	scheme, rest, _ := strings.Cut(header, " ")
+	params := make(map[string]string)
+
+	for rest != "" {
+		var key, value string
+		key, rest, _ = strings.Cut(strings.TrimLeft(rest, ", "), "=")
+
+		if strings.HasPrefix(rest, `"`) {
+			value, rest, _ = strings.Cut(rest[1:], `"`)
+		} else {
+			value, rest, _ = strings.Cut(rest, ",")
+		}
+
+		params[strings.ToLower(strings.TrimSpace(key))] = value
+	}
+
+	return strings.ToLower(scheme), params
+}
+
+// authenticate returns the Authorization header to retry a request to the repository with,
+// based on the challenge of the registry, which is a bearer token fetched from the realm
+// of the challenge for most registries
+func (c *Client) authenticate(ctx context.Context, ref Reference, challenge string) (string, error) {
+	creds, hasCreds := lookupCredentials(ref.Registry)
+	scheme, params := parseChallenge(challenge)
+
+	switch scheme {
+	case "basic":
+		if !hasCreds {
+			return "", fmt.Errorf("%s requires credentials, use `docker login` to provide them", ref.Registry)
+		}
+
+		req := &http.Request{Header: http.Header{}}
+		req.SetBasicAuth(creds.Username, creds.Password)
+
+		return req.Header.Get("Authorization"), nil
+	case "bearer":
+		realm, err := url.Parse(params["realm"])
+		if err != nil || params["realm"] == "" {
+			return "", fmt.Errorf("%s has an invalid authentication realm %q", ref.Registry, params["realm"])
+		}
+
+		query := realm.Query()
+		if params["service"] != "" {
+			query.Set("service", params["service"])
+		}
+		query.Set("scope", "repository:"+ref.Repository+":pull")
+		realm.RawQuery = query.Encode()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+		if err != nil {
+			return "", err
+		}
+		if hasCreds {
+			req.SetBasicAuth(creds.Username, creds.Password)
+		}
+
+		resp, err := c.HTTPClient.Do(req)
+		if err != nil {
+			return "", fmt.Errorf("failed to get token for %s: %w", ref.Registry, err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return "", fmt.Errorf("failed to get token for %s: %s", ref.Registry, resp.Status)
+		}
+
+		var token struct {
+			Token       string `json:"token"`
+			AccessToken string `json:"access_token"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+			return "", fmt.Errorf("failed to get token for %s: %w", ref.Registry, err)
+		}
+
+		if token.Token == "" {
+			token.Token = token.AccessToken
+		}
+
+		return "Bearer " + token.Token, nil
+	}
+
+	return "", fmt.Errorf("%s requires unsupported authentication %q", ref.Registry, scheme)
+}
+
+// get fetches a path of the API of the repository of the image, authenticating
+// if the registry challenges the request and retrying it once
+func (c *Client) get(ctx context.Context, ref Reference, path string, accept []string) (*http.Response, error) {
+	u := fmt.Sprintf("%s://%s/v2/%s/%s", c.Scheme, ref.host(), ref.Repository, path)
+	key := ref.Registry + "/" + ref.Repository
+
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(accept) > 0 {
+			req.Header.Set("Accept", strings.Join(accept, ", "))
+		}
+
+		c.mu.Lock()
+		if auth := c.tokens[key]; auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		c.mu.Unlock()
+
+		resp, err := c.HTTPClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.StatusCode == http.StatusUnauthorized && attempt == 0 {
+			challenge := resp.Header.Get("WWW-Authenticate")
+			resp.Body.Close()
+
+			auth, err := c.authenticate(ctx, ref, challenge)
+			if err != nil {
+				return nil, err
+			}
+
+			c.mu.Lock()
+			c.tokens[key] = auth
+			c.mu.Unlock()
+
+			continue
+		}
+
+		if resp.StatusCode == http.StatusNotFound {
+			resp.Body.Close()
+			return nil, fmt.Errorf("failed to get %s from %s: %w", path, ref, errNotFound)
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, fmt.Errorf("failed to get %s from %s: %s", path, ref, resp.Status)
+		}
+
+		return resp, nil
+	}
+}
+
+// fetchManifest fetches the manifest or index of the image with the given digest or tag
+func (c *Client) fetchManifest(ctx context.Context, ref Reference, identifier string) (manifest, string, error) {
+	resp, err := c.get(ctx, ref, "manifests/"+identifier, []string{
+		mediaTypeOCIIndex,
+		mediaTypeOCIManifest,
+		mediaTypeDockerManifestList,
+		mediaTypeDockerManifest,
+	})
+	if err != nil {
+		return manifest{}, "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return manifest{}, "", err
+	}
+
+	var m manifest
+	if err := json.Unmarshal(body, &m); err != nil {
+		return manifest{}, "", fmt.Errorf("failed to parse manifest of %s: %w", ref, err)
+	}
+
+	if m.MediaType == "" {
+		m.MediaType = resp.Header.Get("Content-Type")
+	}
+
+	digest := resp.Header.Get("Docker-Content-Digest")
+	if digest == "" {
+		digest = sha256Digest(body)
+	}
+
+	return m, digest, nil
+}
+
+// isIndex returns if the manifest is an index of the manifests of an image for each of its platforms
+func (m manifest) isIndex() bool {
+	return m.MediaType == mediaTypeOCIIndex || m.MediaType == mediaTypeDockerManifestList || len(m.Manifests) > 0
+}
+
+// Platforms returns the platforms that the image is built for, or nil if it is only built for one,
+// in which case its manifest does not say which. Manifests of an index that are not images, such
+// as the attestations that BuildKit adds with a platform of "unknown/unknown", are skipped
+func (c *Client) Platforms(ctx context.Context, ref Reference) ([]Platform, error) {
+	m, _, err := c.fetchManifest(ctx, ref, ref.identifier())
+	if err != nil {
+		return nil, err
+	}
+
+	if !m.isIndex() {
+		return nil, nil
+	}
+
+	var platforms []Platform
+	for _, desc := range m.Manifests {
+		if desc.Platform == nil || desc.Platform.OS == "unknown" {
+			continue
+		}
+
+		platforms = append(platforms, *desc.Platform)
+	}
+
+	return platforms, nil
+}
+
+// chooseManifest returns the manifest of an index that is for the platform, or nil if there is not one,
+// where a platform without a variant is for any variant of its architecture
+func chooseManifest(manifests []descriptor, platform Platform) *descriptor {
+	for i, desc := range manifests {
+		if desc.Platform != nil && desc.Platform.OS == platform.OS && desc.Platform.Architecture == platform.Architecture &&
+			(platform.Variant == "" || desc.Platform.Variant == platform.Variant) {
+			return &manifests[i]
+		}
+	}
+
+	return nil
+}
+
+// Pull fetches the manifest of the image for the platform, which is chosen from
+// the index of the image if it is built for more than one platform
+func (c *Client) Pull(ctx context.Context, ref Reference, platform Platform) (*Image, error) {
+	m, digest, err := c.fetchManifest(ctx, ref, ref.identifier())
+	if err != nil {
+		return nil, err
+	}
+
+	indexDigest := ""
+
+	if m.isIndex() {
+		indexDigest = digest
+
+		chosen := chooseManifest(m.Manifests, platform)
+		if chosen == nil {
+			return nil, fmt.Errorf("%w %s: %s", ErrNoMatchingPlatform, platform, ref)
+		}
+
+		platform = *chosen.Platform
+
+		m, digest, err = c.fetchManifest(ctx, ref, chosen.Digest)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	img := &Image{
+		Reference:   ref,
+		Digest:      digest,
+		IndexDigest: indexDigest,
+		Platform:    platform,
+		Annotations: m.Annotations,
+	}
+
+	var commands []string
+	if m.Config.Digest != "" {
+		cfg, err := c.fetchConfig(ctx, ref, m.Config.Digest)
+		if err != nil {
+			return nil, err
+		}
+
+		commands = layerCommands(cfg, len(m.Layers))
+	}
+
+	for i, layer := range m.Layers {
+		img.Layers = append(img.Layers, Layer{
+			Digest:    layer.Digest,
+			MediaType: layer.MediaType,
+			Size:      layer.Size,
+		})
+
+		if commands != nil {
+			img.Layers[i].Command = commands[i]
+		}
+	}
+
+	return img, nil
+}
+
+// fetchConfig fetches the configuration of the image, which is a blob of its repository
+func (c *Client) fetchConfig(ctx context.Context, ref Reference, digest string) (config, error) {
+	blob, err := c.fetchBlob(ctx, ref, digest)
+	if err != nil {
+		return config{}, err
+	}
+	defer blob.Close()
+
+	var cfg config
+	if err := json.NewDecoder(blob).Decode(&cfg); err != nil {
+		return config{}, fmt.Errorf("failed to parse configuration of %s: %w", ref, err)
+	}
+
+	return cfg, nil
+}
+
+// dockerfileCommand returns the Dockerfile instruction that a step of the history of an
+// image was created by, which the legacy builder records as the shell command that it ran
+// for RUN instructions, and with a "#(nop)" marker for the instructions that ran no command
+func dockerfileCommand(createdBy string) string {
+	command := strings.TrimSpace(strings.TrimSuffix(createdBy, "# buildkit"))
+
+	if rest, ok := strings.CutPrefix(command, "RUN /bin/sh -c"); ok {
+		return "RUN " + strings.TrimSpace(rest)
+	}
+
+	if rest, ok := strings.CutPrefix(command, "/bin/sh -c #(nop)"); ok {
+		return strings.TrimSpace(rest)
+	}
+
+	if rest, ok := strings.CutPrefix(command, "/bin/sh -c"); ok {
+		return "RUN " + strings.TrimSpace(rest)
+	}
+
+	return command
+}
+
+// layerCommands returns the Dockerfile instruction that created each layer of the image,
+// or nil if the history of the image does not match its layers
+func layerCommands(cfg config, layers int) []string {
+	var commands []string
+
+	for _, step := range cfg.History {
+		if !step.EmptyLayer {
+			commands = append(commands, dockerfileCommand(step.CreatedBy))
+		}
+	}
+
+	if len(commands) != layers {
+		return nil
+	}
+
+	return commands
+}
+
+// fetchBlob fetches the content of a blob of the repository of the image, such as a layer
+func (c *Client) fetchBlob(ctx context.Context, ref Reference, digest string) (io.ReadCloser, error) {
+	resp, err := c.get(ctx, ref, "blobs/"+digest, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Body, nil
+}
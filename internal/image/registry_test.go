@@ -0,0 +1,352 @@
+package image_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/internal/image"
+)
+
+func digestOf(b []byte) string {
+	sum := sha256.Sum256(b)
+
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// newRegistry serves an image that is built for linux/amd64 and linux/arm64 in an index,
+// which requires a bearer token to be fetched from the registry like Docker Hub, along
+// with attestations that are attached to the manifest for linux/amd64 and to the index
+func newRegistry(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	var layer bytes.Buffer
+	gz := gzip.NewWriter(&layer)
+	tw := tar.NewWriter(gz)
+	status := "Package: curl\nStatus: install ok installed\nVersion: 7.88.1-10+deb12u4\n"
+	_ = tw.WriteHeader(&tar.Header{Name: "var/lib/dpkg/status", Typeflag: tar.TypeReg, Size: int64(len(status)), Mode: 0o644})
+	_, _ = tw.Write([]byte(status))
+	_ = tw.Close()
+	_ = gz.Close()
+
+	config, _ := json.Marshal(map[string]any{
+		"architecture": "amd64",
+		"os":           "linux",
+		"history": []any{
+			map[string]any{"created_by": "ENV DEBIAN_FRONTEND=noninteractive", "empty_layer": true},
+			map[string]any{"created_by": "RUN /bin/sh -c apt-get install -y curl # buildkit"},
+		},
+	})
+
+	manifest, _ := json.Marshal(map[string]any{
+		"schemaVersion": 2,
+		"mediaType":     "application/vnd.oci.image.manifest.v1+json",
+		"annotations": map[string]string{
+			"org.opencontainers.image.base.name":   "docker.io/library/debian:12.4",
+			"org.opencontainers.image.base.digest": "sha256:1111111111111111111111111111111111111111111111111111111111111111",
+		},
+		"config": map[string]any{
+			"mediaType": "application/vnd.oci.image.config.v1+json",
+			"digest":    digestOf(config),
+			"size":      len(config),
+		},
+		"layers": []any{map[string]any{
+			"mediaType": "application/vnd.oci.image.layer.v1.tar+gzip",
+			"digest":    digestOf(layer.Bytes()),
+			"size":      layer.Len(),
+		}},
+	})
+
+	index, _ := json.Marshal(map[string]any{
+		"schemaVersion": 2,
+		"mediaType":     "application/vnd.oci.image.index.v1+json",
+		"manifests": []any{
+			map[string]any{
+				"mediaType": "application/vnd.oci.image.manifest.v1+json",
+				"digest":    "sha256:0000000000000000000000000000000000000000000000000000000000000000",
+				"platform":  map[string]string{"os": "linux", "architecture": "arm64"},
+			},
+			map[string]any{
+				"mediaType": "application/vnd.oci.image.manifest.v1+json",
+				"digest":    digestOf(manifest),
+				"platform":  map[string]string{"os": "linux", "architecture": "amd64"},
+			},
+			map[string]any{
+				"mediaType": "application/vnd.oci.image.manifest.v1+json",
+				"digest":    "sha256:2222222222222222222222222222222222222222222222222222222222222222",
+				"platform":  map[string]string{"os": "unknown", "architecture": "unknown"},
+			},
+		},
+	})
+
+	// the manifest has an attestation attached by cosign, and the index has one attached as a referrer
+	envelope := []byte(`{"payloadType":"application/vnd.in-toto+json","payload":"e30=","signatures":[]}`)
+	bundle := []byte(`{"mediaType":"application/vnd.dev.sigstore.bundle.v0.3+json","dsseEnvelope":{"payloadType":"application/vnd.in-toto+json","payload":"e30=","signatures":[]}}`)
+
+	attestations, _ := json.Marshal(map[string]any{
+		"schemaVersion": 2,
+		"mediaType":     "application/vnd.oci.image.manifest.v1+json",
+		"layers": []any{map[string]any{
+			"mediaType": "application/vnd.dsse.envelope.v1+json",
+			"digest":    digestOf(envelope),
+			"size":      len(envelope),
+		}},
+	})
+
+	bundleManifest, _ := json.Marshal(map[string]any{
+		"schemaVersion": 2,
+		"mediaType":     "application/vnd.oci.image.manifest.v1+json",
+		"artifactType":  "application/vnd.dev.sigstore.bundle.v0.3+json",
+		"layers": []any{map[string]any{
+			"mediaType": "application/vnd.dev.sigstore.bundle.v0.3+json",
+			"digest":    digestOf(bundle),
+			"size":      len(bundle),
+		}},
+	})
+
+	referrers, _ := json.Marshal(map[string]any{
+		"schemaVersion": 2,
+		"mediaType":     "application/vnd.oci.image.index.v1+json",
+		"manifests": []any{
+			map[string]any{
+				"mediaType":    "application/vnd.oci.image.manifest.v1+json",
+				"artifactType": "application/vnd.dev.sigstore.bundle.v0.3+json",
+				"digest":       digestOf(bundleManifest),
+			},
+			map[string]any{
+				"mediaType":    "application/vnd.oci.image.manifest.v1+json",
+				"artifactType": "application/vnd.example.signature",
+				"digest":       "sha256:3333333333333333333333333333333333333333333333333333333333333333",
+			},
+		},
+	})
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			if r.URL.Query().Get("scope") != "repository:org/app:pull" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			_, _ = w.Write([]byte(`{"token":"secret"}`))
+
+			return
+		}
+
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="`+server.URL+`/token",service="registry.test"`)
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		switch r.URL.Path {
+		case "/v2/org/app/tags/list":
+			// the tags are split into two pages
+			if r.URL.Query().Get("last") == "" {
+				w.Header().Set("Link", `</v2/org/app/tags/list?last=1.0&n=2>; rel="next"`)
+				_, _ = w.Write([]byte(`{"name":"org/app","tags":["0.9","1.0"]}`))
+			} else {
+				_, _ = w.Write([]byte(`{"name":"org/app","tags":["1.1","latest"]}`))
+			}
+		case "/v2/org/app/manifests/1.0":
+			w.Header().Set("Content-Type", "application/vnd.oci.image.index.v1+json")
+			_, _ = w.Write(index)
+		case "/v2/org/app/manifests/" + digestOf(manifest):
+			w.Header().Set("Content-Type", "application/vnd.oci.image.manifest.v1+json")
+			_, _ = w.Write(manifest)
+		case "/v2/org/app/blobs/" + digestOf(config):
+			_, _ = w.Write(config)
+		case "/v2/org/app/blobs/" + digestOf(layer.Bytes()):
+			_, _ = w.Write(layer.Bytes())
+		case "/v2/org/app/manifests/" + strings.Replace(digestOf(manifest), ":", "-", 1) + ".att":
+			w.Header().Set("Content-Type", "application/vnd.oci.image.manifest.v1+json")
+			_, _ = w.Write(attestations)
+		case "/v2/org/app/blobs/" + digestOf(envelope):
+			_, _ = w.Write(envelope)
+		case "/v2/org/app/referrers/" + digestOf(index):
+			w.Header().Set("Content-Type", "application/vnd.oci.image.index.v1+json")
+			_, _ = w.Write(referrers)
+		case "/v2/org/app/manifests/" + digestOf(bundleManifest):
+			w.Header().Set("Content-Type", "application/vnd.oci.image.manifest.v1+json")
+			_, _ = w.Write(bundleManifest)
+		case "/v2/org/app/blobs/" + digestOf(bundle):
+			_, _ = w.Write(bundle)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestClient_PullAndExtract(t *testing.T) {
+	t.Parallel()
+
+	server := newRegistry(t)
+
+	ref, err := image.ParseReference(strings.TrimPrefix(server.URL, "http://") + "/org/app:1.0")
+	if err != nil {
+		t.Fatalf("ParseReference() error = %v", err)
+	}
+
+	client := image.NewClient()
+	client.Scheme = "http"
+
+	img, err := client.Pull(context.Background(), ref, image.Platform{OS: "linux", Architecture: "amd64"})
+	if err != nil {
+		t.Fatalf("Pull() error = %v", err)
+	}
+
+	if len(img.Layers) != 1 {
+		t.Fatalf("Pull() returned %d layers, want 1", len(img.Layers))
+	}
+
+	if got, want := img.Layers[0].Command, "RUN apt-get install -y curl"; got != want {
+		t.Errorf("Pull() layer command = %q, want %q", got, want)
+	}
+
+	base, ok := img.BaseImage()
+	if !ok {
+		t.Fatalf("BaseImage() did not find the base image")
+	}
+
+	if got, want := base.String(), "docker.io/library/debian:12.4@sha256:1111111111111111111111111111111111111111111111111111111111111111"; got != want {
+		t.Errorf("BaseImage() = %q, want %q", got, want)
+	}
+
+	dir := t.TempDir()
+	var written []string
+	err = client.Extract(context.Background(), img, dir, func(index int, layer image.Layer, files []string) error {
+		written = append(written, files...)
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+
+	if len(written) != 1 || written[0] != "/var/lib/dpkg/status" {
+		t.Errorf("Extract() reported %v as written, want [/var/lib/dpkg/status]", written)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "var", "lib", "dpkg", "status"))
+	if err != nil {
+		t.Fatalf("Extract() did not write the layer: %v", err)
+	}
+
+	if !strings.Contains(string(content), "Package: curl") {
+		t.Errorf("Extract() wrote %q to var/lib/dpkg/status", content)
+	}
+}
+
+func TestClient_Pull_NoMatchingPlatform(t *testing.T) {
+	t.Parallel()
+
+	server := newRegistry(t)
+
+	ref, err := image.ParseReference(strings.TrimPrefix(server.URL, "http://") + "/org/app:1.0")
+	if err != nil {
+		t.Fatalf("ParseReference() error = %v", err)
+	}
+
+	client := image.NewClient()
+	client.Scheme = "http"
+
+	_, err = client.Pull(context.Background(), ref, image.Platform{OS: "windows", Architecture: "amd64"})
+	if !errors.Is(err, image.ErrNoMatchingPlatform) {
+		t.Errorf("Pull() error = %v, want %v", err, image.ErrNoMatchingPlatform)
+	}
+}
+
+func TestClient_Tags(t *testing.T) {
+	t.Parallel()
+
+	server := newRegistry(t)
+
+	ref, err := image.ParseReference(strings.TrimPrefix(server.URL, "http://") + "/org/app:1.0")
+	if err != nil {
+		t.Fatalf("ParseReference() error = %v", err)
+	}
+
+	client := image.NewClient()
+	client.Scheme = "http"
+
+	tags, err := client.Tags(context.Background(), ref)
+	if err != nil {
+		t.Fatalf("Tags() error = %v", err)
+	}
+
+	if diff := cmp.Diff([]string{"0.9", "1.0", "1.1", "latest"}, tags); diff != "" {
+		t.Errorf("Tags() (-want +got):\n%s", diff)
+	}
+}
+
+func TestClient_Platforms(t *testing.T) {
+	t.Parallel()
+
+	server := newRegistry(t)
+
+	ref, err := image.ParseReference(strings.TrimPrefix(server.URL, "http://") + "/org/app:1.0")
+	if err != nil {
+		t.Fatalf("ParseReference() error = %v", err)
+	}
+
+	client := image.NewClient()
+	client.Scheme = "http"
+
+	platforms, err := client.Platforms(context.Background(), ref)
+	if err != nil {
+		t.Fatalf("Platforms() error = %v", err)
+	}
+
+	want := []image.Platform{{OS: "linux", Architecture: "arm64"}, {OS: "linux", Architecture: "amd64"}}
+
+	if diff := cmp.Diff(want, platforms); diff != "" {
+		t.Errorf("Platforms() (-want +got):\n%s", diff)
+	}
+}
+
+func TestParsePlatform(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		platform string
+		want     image.Platform
+		wantErr  bool
+	}{
+		{platform: "linux/amd64", want: image.Platform{OS: "linux", Architecture: "amd64"}},
+		{platform: "linux/arm64/v8", want: image.Platform{OS: "linux", Architecture: "arm64", Variant: "v8"}},
+		{platform: "linux", wantErr: true},
+		{platform: "linux//v7", wantErr: true},
+		{platform: "linux/arm/v7/extra", wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt // Reinitialize for t.Parallel()
+		t.Run(tt.platform, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := image.ParsePlatform(tt.platform)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePlatform() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ParsePlatform() (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package image
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// readRelease reads the file of the filesystem of an image that has the release of its distribution, and
+// returns its trimmed content, which is empty if the image does not have it or if it cannot be read
+func readRelease(dir string, name string) string {
+	content, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(string(content))
+}
+
+// parseOSRelease parses the fields of an os-release file, whose values can be quoted
+func parseOSRelease(content string) map[string]string {
+	fields := make(map[string]string)
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(strings.TrimSpace(scanner.Text()), "=")
+		if !ok || strings.HasPrefix(key, "#") {
+			continue
+		}
+
+		fields[key] = strings.Trim(value, `"'`)
+	}
+
+	return fields
+}
+
+// OSRelease returns the release of the distribution of the filesystem of an image that has been extracted
+// to the directory, like "Debian GNU/Linux 12 (bookworm)", which is read from its os-release file. Images
+// that do not have one, like some of those that are distroless, are identified by the version files of
+// Debian and Alpine instead, and an empty string is returned if the distribution cannot be identified
+func OSRelease(dir string) string {
+	for _, name := range []string{"etc/os-release", "usr/lib/os-release"} {
+		fields := parseOSRelease(readRelease(dir, name))
+
+		if fields["PRETTY_NAME"] != "" {
+			return fields["PRETTY_NAME"]
+		}
+
+		if fields["NAME"] != "" {
+			return strings.TrimSpace(fields["NAME"] + " " + fields["VERSION_ID"])
+		}
+	}
+
+	if version := readRelease(dir, "etc/debian_version"); version != "" {
+		return "Debian GNU/Linux " + version
+	}
+
+	if version := readRelease(dir, "etc/alpine-release"); version != "" {
+		return "Alpine Linux v" + version
+	}
+
+	return ""
+}
@@ -0,0 +1,73 @@
+package image_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/osv-scanner/internal/image"
+)
+
+func TestOSRelease(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  string
+	}{
+		{
+			name:  "no release",
+			files: map[string]string{"bin/app": "app"},
+			want:  "",
+		},
+		{
+			name: "os-release",
+			files: map[string]string{
+				"etc/os-release": "# comment\nNAME=\"Debian GNU/Linux\"\nVERSION_ID=\"12\"\nPRETTY_NAME=\"Debian GNU/Linux 12 (bookworm)\"\n",
+			},
+			want: "Debian GNU/Linux 12 (bookworm)",
+		},
+		{
+			name: "os-release in usr/lib",
+			files: map[string]string{
+				"usr/lib/os-release": "NAME=Wolfi\nVERSION_ID=20230201\n",
+			},
+			want: "Wolfi 20230201",
+		},
+		{
+			name: "distroless",
+			files: map[string]string{
+				"etc/debian_version":            "12.5\n",
+				"var/lib/dpkg/status.d/libssl3": "Package: libssl3\n",
+			},
+			want: "Debian GNU/Linux 12.5",
+		},
+		{
+			name:  "alpine-release",
+			files: map[string]string{"etc/alpine-release": "3.19.1\n"},
+			want:  "Alpine Linux v3.19.1",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				p := filepath.Join(dir, filepath.FromSlash(name))
+				if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+					t.Fatalf("failed to write %s: %v", name, err)
+				}
+				if err := os.WriteFile(p, []byte(content), 0o600); err != nil {
+					t.Fatalf("failed to write %s: %v", name, err)
+				}
+			}
+
+			if got := image.OSRelease(dir); got != tt.want {
+				t.Errorf("OSRelease() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package image
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/google/osv-scanner/internal/cachedregexp"
+)
+
+// tagsPageSize is how many tags are asked for at once, which registries may lower
+const tagsPageSize = 1000
+
+// Tags lists the tags of the repository of the image, following the pages that the registry splits them into
+func (c *Client) Tags(ctx context.Context, ref Reference) ([]string, error) {
+	var tags []string
+	last := ""
+
+	for {
+		query := url.Values{"n": {strconv.Itoa(tagsPageSize)}}
+		if last != "" {
+			query.Set("last", last)
+		}
+
+		resp, err := c.get(ctx, ref, "tags/list?"+query.Encode(), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var page struct {
+			Tags []string `json:"tags"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&page)
+		hasNext := strings.Contains(resp.Header.Get("Link"), `rel="next"`)
+		resp.Body.Close()
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse tags of %s: %w", ref, err)
+		}
+
+		tags = append(tags, page.Tags...)
+
+		if !hasNext || len(page.Tags) == 0 {
+			return tags, nil
+		}
+
+		last = page.Tags[len(page.Tags)-1]
+	}
+}
+
+// tagVersion splits a tag into the numbers of the version that it starts with, and the
+// variant of the tag that is around them, or returns false if the tag is not a version
+func tagVersion(tag string) ([]int, string, bool) {
+	match := cachedregexp.MustCompile(`^(v?)(\d+(?:\.\d+)*)(.*)$`).FindStringSubmatch(tag)
+	if match == nil {
+		return nil, "", false
+	}
+
+	var numbers []int
+	for _, part := range strings.Split(match[2], ".") {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, "", false
+		}
+
+		numbers = append(numbers, n)
+	}
+
+	return numbers, match[1] + "<version>" + match[3], true
+}
+
+// NewerTags returns the tags that are a newer version of the same variant as the given tag, from
+// oldest to newest, where a variant of "12.4" is any version with two numbers and nothing after
+// them like "12.5" or "13.0", while one of "3.11-slim" is any version with two numbers and "-slim"
+// after them. Tags that are not versions, like "latest", do not have any newer tags
+func NewerTags(tag string, tags []string) []string {
+	current, variant, ok := tagVersion(tag)
+	if !ok {
+		return nil
+	}
+
+	type candidate struct {
+		tag     string
+		version []int
+	}
+
+	var candidates []candidate
+
+	for _, t := range tags {
+		version, v, ok := tagVersion(t)
+		if !ok || v != variant || len(version) != len(current) || slices.Compare(version, current) <= 0 {
+			continue
+		}
+
+		candidates = append(candidates, candidate{tag: t, version: version})
+	}
+
+	slices.SortFunc(candidates, func(a, b candidate) int {
+		return slices.Compare(a.version, b.version)
+	})
+
+	newer := make([]string, 0, len(candidates))
+	for _, c := range candidates {
+		newer = append(newer, c.tag)
+	}
+
+	return newer
+}
@@ -0,0 +1,72 @@
+package image_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/internal/image"
+)
+
+func TestNewerTags(t *testing.T) {
+	t.Parallel()
+
+	tags := []string{
+		"latest", "bookworm", "11", "12", "13", "11.8", "12.4", "12.5", "12.10", "13.0",
+		"12.4-slim", "12.5-slim", "3.11.4-slim-bookworm", "3.11.5-slim-bookworm",
+		"3.12.0-slim-bookworm", "3.12.0-slim-bullseye", "v1.2", "v1.3", "1.4",
+	}
+
+	tests := []struct {
+		name string
+		tag  string
+		want []string
+	}{
+		{
+			name: "major version",
+			tag:  "12",
+			want: []string{"13"},
+		},
+		{
+			name: "minor version",
+			tag:  "12.4",
+			want: []string{"12.5", "12.10", "13.0"},
+		},
+		{
+			name: "variant",
+			tag:  "12.4-slim",
+			want: []string{"12.5-slim"},
+		},
+		{
+			name: "variant with a suffix",
+			tag:  "3.11.4-slim-bookworm",
+			want: []string{"3.11.5-slim-bookworm", "3.12.0-slim-bookworm"},
+		},
+		{
+			name: "prefix",
+			tag:  "v1.2",
+			want: []string{"v1.3"},
+		},
+		{
+			name: "newest version",
+			tag:  "13.0",
+			want: []string{},
+		},
+		{
+			name: "not a version",
+			tag:  "latest",
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt // Reinitialize for t.Parallel()
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := image.NewerTags(tt.tag, tags)
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("NewerTags() (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,225 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// Client talks to the API server of a cluster
+type Client struct {
+	httpClient    *http.Client
+	server        string
+	authorization string
+}
+
+// Workload is what runs pods, such as a Deployment, along with the images that its pods are running
+type Workload struct {
+	Namespace string
+	Kind      string
+	Name      string
+	Images    []string
+}
+
+// pod is the part of a pod that has what owns it and the images of its containers
+type pod struct {
+	Metadata struct {
+		Name            string            `json:"name"`
+		Namespace       string            `json:"namespace"`
+		Labels          map[string]string `json:"labels"`
+		OwnerReferences []struct {
+			Kind       string `json:"kind"`
+			Name       string `json:"name"`
+			Controller bool   `json:"controller"`
+		} `json:"ownerReferences"`
+	} `json:"metadata"`
+	Spec struct {
+		Containers          []container `json:"containers"`
+		InitContainers      []container `json:"initContainers"`
+		EphemeralContainers []container `json:"ephemeralContainers"`
+	} `json:"spec"`
+	Status struct {
+		ContainerStatuses          []containerStatus `json:"containerStatuses"`
+		InitContainerStatuses      []containerStatus `json:"initContainerStatuses"`
+		EphemeralContainerStatuses []containerStatus `json:"ephemeralContainerStatuses"`
+	} `json:"status"`
+}
+
+type container struct {
+	Name  string `json:"name"`
+	Image string `json:"image"`
+}
+
+type containerStatus struct {
+	Name string `json:"name"`
+	// ImageID is the digest of the image that the container is running, like "docker.io/library/nginx@sha256:..."
+	ImageID string `json:"imageID"`
+}
+
+type podList struct {
+	Metadata struct {
+		Continue string `json:"continue"`
+	} `json:"metadata"`
+	Items []pod `json:"items"`
+}
+
+func (c *Client) get(ctx context.Context, path string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.server+path, nil)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Accept", "application/json")
+	if c.authorization != "" {
+		req.Header.Set("Authorization", c.authorization)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to connect to the cluster: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to get %s from the cluster: %s", path, resp.Status)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// pods lists the pods in the namespace, or in every namespace if it is empty
+func (c *Client) pods(ctx context.Context, namespace string) ([]pod, error) {
+	path := "/api/v1/pods"
+	if namespace != "" {
+		path = "/api/v1/namespaces/" + url.PathEscape(namespace) + "/pods"
+	}
+
+	var pods []pod
+	next := ""
+
+	for {
+		query := url.Values{"limit": {"500"}}
+		if next != "" {
+			query.Set("continue", next)
+		}
+
+		var list podList
+		if err := c.get(ctx, path+"?"+query.Encode(), &list); err != nil {
+			return nil, err
+		}
+
+		pods = append(pods, list.Items...)
+
+		if list.Metadata.Continue == "" {
+			return pods, nil
+		}
+
+		next = list.Metadata.Continue
+	}
+}
+
+// workloadOf returns the kind and name of what runs the pod, which is the pod itself if nothing controls
+// it. Pods of Deployments are controlled by a ReplicaSet that is named after the Deployment and the hash
+// of the template of the pod, so they are attributed to the Deployment instead
+func workloadOf(p pod) (string, string) {
+	for _, owner := range p.Metadata.OwnerReferences {
+		if !owner.Controller {
+			continue
+		}
+
+		if hash := p.Metadata.Labels["pod-template-hash"]; owner.Kind == "ReplicaSet" && hash != "" {
+			if deployment, ok := strings.CutSuffix(owner.Name, "-"+hash); ok {
+				return "Deployment", deployment
+			}
+		}
+
+		return owner.Kind, owner.Name
+	}
+
+	return "Pod", p.Metadata.Name
+}
+
+// runningImage returns the image that a container is running, which is pinned to the digest of the image
+// that the node pulled if it is known, so that the image is scanned as it is running even if its tag
+// has since been moved
+func runningImage(image string, statuses []containerStatus, name string) string {
+	if strings.Contains(image, "@") {
+		return image
+	}
+
+	for _, status := range statuses {
+		if status.Name != name {
+			continue
+		}
+
+		// some runtimes report the image ID with a scheme, like "docker-pullable://nginx@sha256:..."
+		if _, digest, ok := strings.Cut(status.ImageID, "@"); ok && strings.HasPrefix(digest, "sha256:") {
+			return image + "@" + digest
+		}
+	}
+
+	return image
+}
+
+// Workloads lists the workloads in the namespace, or in every namespace if it is empty, with
+// the images that the containers of their pods are running, which are sorted by workload
+func (c *Client) Workloads(ctx context.Context, namespace string) ([]Workload, error) {
+	pods, err := c.pods(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	type key struct {
+		namespace, kind, name string
+	}
+
+	images := make(map[key]map[string]bool)
+
+	for _, p := range pods {
+		kind, name := workloadOf(p)
+		k := key{namespace: p.Metadata.Namespace, kind: kind, name: name}
+
+		if images[k] == nil {
+			images[k] = make(map[string]bool)
+		}
+
+		for _, c := range p.Spec.Containers {
+			images[k][runningImage(c.Image, p.Status.ContainerStatuses, c.Name)] = true
+		}
+		for _, c := range p.Spec.InitContainers {
+			images[k][runningImage(c.Image, p.Status.InitContainerStatuses, c.Name)] = true
+		}
+		for _, c := range p.Spec.EphemeralContainers {
+			images[k][runningImage(c.Image, p.Status.EphemeralContainerStatuses, c.Name)] = true
+		}
+	}
+
+	workloads := make([]Workload, 0, len(images))
+	for k, imgs := range images {
+		workload := Workload{Namespace: k.namespace, Kind: k.kind, Name: k.name}
+		for image := range imgs {
+			workload.Images = append(workload.Images, image)
+		}
+		sort.Strings(workload.Images)
+
+		workloads = append(workloads, workload)
+	}
+
+	sort.Slice(workloads, func(i, j int) bool {
+		a, b := workloads[i], workloads[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+
+		return a.Name < b.Name
+	})
+
+	return workloads, nil
+}
@@ -0,0 +1,121 @@
+package kubernetes_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/internal/kubernetes"
+)
+
+// writeKubeconfig writes a kubeconfig with a context for the server that authenticates with a token
+func writeKubeconfig(t *testing.T, server string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config")
+	config := `apiVersion: v1
+kind: Config
+current-context: test
+clusters:
+  - name: test
+    cluster:
+      server: ` + server + `
+contexts:
+  - name: test
+    context:
+      cluster: test
+      user: test
+      namespace: shop
+users:
+  - name: test
+    user:
+      token: secret
+`
+
+	if err := os.WriteFile(path, []byte(config), 0o600); err != nil {
+		t.Fatalf("failed to write kubeconfig: %v", err)
+	}
+
+	return path
+}
+
+func TestClient_Workloads(t *testing.T) {
+	t.Parallel()
+
+	pods, err := os.ReadFile("fixtures/pods.json")
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		if r.URL.Path != "/api/v1/pods" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		_, _ = w.Write(pods)
+	}))
+	t.Cleanup(server.Close)
+
+	client, namespace, err := kubernetes.LoadConfig(writeKubeconfig(t, server.URL), "")
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+
+	if namespace != "shop" {
+		t.Errorf("LoadConfig() namespace = %q, want %q", namespace, "shop")
+	}
+
+	workloads, err := client.Workloads(context.Background(), "")
+	if err != nil {
+		t.Fatalf("Workloads() error = %v", err)
+	}
+
+	want := []kubernetes.Workload{
+		{
+			Namespace: "default",
+			Kind:      "Pod",
+			Name:      "debug",
+			Images:    []string{"busybox"},
+		},
+		{
+			Namespace: "shop",
+			Kind:      "Deployment",
+			Name:      "api",
+			Images: []string{
+				"envoyproxy/envoy:v1.28.0",
+				"ghcr.io/org/api:2.1@sha256:2222222222222222222222222222222222222222222222222222222222222222",
+				"ghcr.io/org/migrate:1.4@sha256:1111111111111111111111111111111111111111111111111111111111111111",
+			},
+		},
+		{
+			Namespace: "shop",
+			Kind:      "StatefulSet",
+			Name:      "redis",
+			Images:    []string{"redis@sha256:3333333333333333333333333333333333333333333333333333333333333333"},
+		},
+	}
+
+	if diff := cmp.Diff(want, workloads); diff != "" {
+		t.Errorf("Workloads() (-want +got):\n%s", diff)
+	}
+}
+
+func TestLoadConfig_ContextNotFound(t *testing.T) {
+	t.Parallel()
+
+	_, _, err := kubernetes.LoadConfig(writeKubeconfig(t, "https://127.0.0.1:6443"), "production")
+	if !errors.Is(err, kubernetes.ErrContextNotFound) {
+		t.Errorf("LoadConfig() error = %v, want %v", err, kubernetes.ErrContextNotFound)
+	}
+}
@@ -0,0 +1,264 @@
+package kubernetes
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var ErrContextNotFound = errors.New("context not found in kubeconfig")
+
+// kubeconfig is the part of the configuration that kubectl uses to access clusters
+// that has the context to use, and the cluster and credentials that it refers to
+type kubeconfig struct {
+	CurrentContext string `yaml:"current-context"`
+	Clusters       []struct {
+		Name    string `yaml:"name"`
+		Cluster struct {
+			Server                   string `yaml:"server"`
+			CertificateAuthority     string `yaml:"certificate-authority"`
+			CertificateAuthorityData string `yaml:"certificate-authority-data"`
+			InsecureSkipTLSVerify    bool   `yaml:"insecure-skip-tls-verify"`
+		} `yaml:"cluster"`
+	} `yaml:"clusters"`
+	Contexts []struct {
+		Name    string `yaml:"name"`
+		Context struct {
+			Cluster   string `yaml:"cluster"`
+			User      string `yaml:"user"`
+			Namespace string `yaml:"namespace"`
+		} `yaml:"context"`
+	} `yaml:"contexts"`
+	Users []struct {
+		Name string   `yaml:"name"`
+		User userInfo `yaml:"user"`
+	} `yaml:"users"`
+}
+
+// userInfo are the credentials that a cluster is accessed with
+type userInfo struct {
+	Token                 string      `yaml:"token"`
+	TokenFile             string      `yaml:"tokenFile"`
+	ClientCertificate     string      `yaml:"client-certificate"`
+	ClientCertificateData string      `yaml:"client-certificate-data"`
+	ClientKey             string      `yaml:"client-key"`
+	ClientKeyData         string      `yaml:"client-key-data"`
+	Username              string      `yaml:"username"`
+	Password              string      `yaml:"password"`
+	Exec                  *execConfig `yaml:"exec"`
+}
+
+// execConfig is a credential plugin, which is a command that prints the credentials to access a
+// cluster with, such as those that cloud providers use to exchange their own credentials for a token
+type execConfig struct {
+	APIVersion string   `yaml:"apiVersion"`
+	Command    string   `yaml:"command"`
+	Args       []string `yaml:"args"`
+	Env        []struct {
+		Name  string `yaml:"name"`
+		Value string `yaml:"value"`
+	} `yaml:"env"`
+}
+
+// KubeconfigPath returns where kubectl reads its configuration from, which is the
+// first of the files in $KUBECONFIG if it is set, as merging them is not supported
+func KubeconfigPath() string {
+	if paths := filepath.SplitList(os.Getenv("KUBECONFIG")); len(paths) > 0 && paths[0] != "" {
+		return paths[0]
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(home, ".kube", "config")
+}
+
+// readFileOrData returns the base64 encoded data if it is set, or the content of the file otherwise,
+// with relative files being relative to the directory of the kubeconfig like kubectl does
+func readFileOrData(dir string, file string, data string) ([]byte, error) {
+	if data != "" {
+		return base64.StdEncoding.DecodeString(data)
+	}
+
+	if file == "" {
+		return nil, nil
+	}
+
+	if !filepath.IsAbs(file) {
+		file = filepath.Join(dir, file)
+	}
+
+	return os.ReadFile(file)
+}
+
+// runExecPlugin runs a credential plugin, and returns the token that it prints
+func runExecPlugin(plugin *execConfig) (string, error) {
+	cmd := exec.Command(plugin.Command, plugin.Args...)
+	cmd.Env = os.Environ()
+	for _, env := range plugin.Env {
+		cmd.Env = append(cmd.Env, env.Name+"="+env.Value)
+	}
+
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("credential plugin %s failed: %w", plugin.Command, err)
+	}
+
+	var credential struct {
+		Status struct {
+			Token string `json:"token"`
+		} `json:"status"`
+	}
+
+	if err := json.Unmarshal(stdout.Bytes(), &credential); err != nil {
+		return "", fmt.Errorf("credential plugin %s returned invalid credentials: %w", plugin.Command, err)
+	}
+
+	if credential.Status.Token == "" {
+		return "", fmt.Errorf("credential plugin %s did not return a token, which is the only credential supported from plugins", plugin.Command)
+	}
+
+	return credential.Status.Token, nil
+}
+
+// LoadConfig reads the kubeconfig at the path, and returns a client for the cluster of the context with
+// the given name, or the current context if it is empty, along with the namespace of the context
+func LoadConfig(path string, contextName string) (*Client, string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read kubeconfig: %w", err)
+	}
+
+	var config kubeconfig
+	if err := yaml.Unmarshal(content, &config); err != nil {
+		return nil, "", fmt.Errorf("failed to parse kubeconfig %s: %w", path, err)
+	}
+
+	if contextName == "" {
+		contextName = config.CurrentContext
+	}
+
+	client := &Client{}
+	var clusterName, userName, namespace string
+	found := false
+
+	for _, c := range config.Contexts {
+		if c.Name == contextName {
+			clusterName, userName, namespace = c.Context.Cluster, c.Context.User, c.Context.Namespace
+			found = true
+
+			break
+		}
+	}
+
+	if !found {
+		return nil, "", fmt.Errorf("%w: %q", ErrContextNotFound, contextName)
+	}
+
+	dir := filepath.Dir(path)
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	for _, c := range config.Clusters {
+		if c.Name != clusterName {
+			continue
+		}
+
+		client.server = strings.TrimSuffix(c.Cluster.Server, "/")
+		//nolint:gosec // this is what the cluster has been configured to be accessed with
+		tlsConfig.InsecureSkipVerify = c.Cluster.InsecureSkipTLSVerify
+
+		ca, err := readFileOrData(dir, c.Cluster.CertificateAuthority, c.Cluster.CertificateAuthorityData)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to read certificate authority of cluster %s: %w", clusterName, err)
+		}
+
+		if ca != nil {
+			tlsConfig.RootCAs = x509.NewCertPool()
+			if !tlsConfig.RootCAs.AppendCertsFromPEM(ca) {
+				return nil, "", fmt.Errorf("certificate authority of cluster %s is not valid PEM", clusterName)
+			}
+		}
+	}
+
+	if client.server == "" {
+		return nil, "", fmt.Errorf("cluster %q of context %q does not have a server", clusterName, contextName)
+	}
+
+	for _, u := range config.Users {
+		if u.Name != userName {
+			continue
+		}
+
+		if err := client.setCredentials(dir, u.User, tlsConfig); err != nil {
+			return nil, "", fmt.Errorf("failed to load credentials of user %s: %w", userName, err)
+		}
+	}
+
+	client.httpClient = &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}}
+
+	return client, namespace, nil
+}
+
+// setCredentials sets up the client to authenticate with the credentials of the user, which
+// are either a client certificate, a token that can come from a credential plugin, or a password
+func (c *Client) setCredentials(dir string, user userInfo, tlsConfig *tls.Config) error {
+	cert, err := readFileOrData(dir, user.ClientCertificate, user.ClientCertificateData)
+	if err != nil {
+		return err
+	}
+
+	key, err := readFileOrData(dir, user.ClientKey, user.ClientKeyData)
+	if err != nil {
+		return err
+	}
+
+	if cert != nil && key != nil {
+		pair, err := tls.X509KeyPair(cert, key)
+		if err != nil {
+			return err
+		}
+
+		tlsConfig.Certificates = []tls.Certificate{pair}
+	}
+
+	switch {
+	case user.Token != "":
+		c.authorization = "Bearer " + user.Token
+	case user.TokenFile != "":
+		token, err := readFileOrData(dir, user.TokenFile, "")
+		if err != nil {
+			return err
+		}
+
+		c.authorization = "Bearer " + strings.TrimSpace(string(token))
+	case user.Exec != nil:
+		token, err := runExecPlugin(user.Exec)
+		if err != nil {
+			return err
+		}
+
+		c.authorization = "Bearer " + token
+	case user.Username != "":
+		req := &http.Request{Header: http.Header{}}
+		req.SetBasicAuth(user.Username, user.Password)
+		c.authorization = req.Header.Get("Authorization")
+	}
+
+	return nil
+}
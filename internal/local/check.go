@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path"
+	"strings"
 
 	"github.com/google/osv-scanner/pkg/lockfile"
 	"github.com/google/osv-scanner/pkg/models"
@@ -30,7 +31,7 @@ func toPackageDetails(query *osv.Query) (lockfile.PackageDetails, error) {
 			Name:      pkg.Name,
 			Version:   pkg.Version,
 			Ecosystem: lockfile.Ecosystem(pkg.Ecosystem),
-			CompareAs: lockfile.Ecosystem(pkg.Ecosystem),
+			CompareAs: compareAs(pkg.Ecosystem),
 		}, nil
 	}
 
@@ -39,10 +40,18 @@ func toPackageDetails(query *osv.Query) (lockfile.PackageDetails, error) {
 		Version:   query.Version,
 		Commit:    query.Commit,
 		Ecosystem: lockfile.Ecosystem(query.Package.Ecosystem),
-		CompareAs: lockfile.Ecosystem(query.Package.Ecosystem),
+		CompareAs: compareAs(query.Package.Ecosystem),
 	}, nil
 }
 
+// compareAs returns the ecosystem that versions of the given ecosystem are compared as,
+// which for the releases of a distribution such as "Debian:12" is the distribution itself
+func compareAs(ecosystem string) lockfile.Ecosystem {
+	base, _, _ := strings.Cut(ecosystem, ":")
+
+	return lockfile.Ecosystem(base)
+}
+
 // setupLocalDBDirectory attempts to set up the directory the scanner should
 // use to store local databases.
 //
@@ -154,3 +163,14 @@ func MakeRequest(r reporter.Reporter, query osv.BatchedQuery, offline bool, loca
 
 	return &osv.HydratedBatchedResponse{Results: results}, nil
 }
+
+// LoadOfflineDB loads the database of the ecosystem that has already been downloaded to the local database directory,
+// without making any network requests.
+func LoadOfflineDB(localDBPath string, ecosystem lockfile.Ecosystem) (*ZipDB, error) {
+	dbBasePath, err := setupLocalDBDirectory(localDBPath)
+	if err != nil {
+		return nil, fmt.Errorf("could not create %s: %w", dbBasePath, err)
+	}
+
+	return loadDB(dbBasePath, ecosystem, true)
+}
@@ -0,0 +1,205 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/CycloneDX/cyclonedx-go"
+	"github.com/google/osv-scanner/internal/utility/severity"
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+// cyclonedxBOMRef returns the reference of the component of a package in a CycloneDX BOM,
+// which is its Package URL if it has one
+func cyclonedxBOMRef(pkg models.PackageInfo) string {
+	if purl := models.PackageToPURL(pkg); purl != "" {
+		return purl
+	}
+
+	if pkg.Commit != "" {
+		return pkg.Name + "@" + pkg.Commit
+	}
+
+	return pkg.Ecosystem + "/" + pkg.Name + "@" + pkg.Version
+}
+
+// cyclonedxRatings converts the severities of a vulnerability to CycloneDX ratings
+func cyclonedxRatings(vuln models.Vulnerability) []cyclonedx.VulnerabilityRating {
+	ratings := make([]cyclonedx.VulnerabilityRating, 0, len(vuln.Severity))
+
+	for _, sev := range vuln.Severity {
+		rating := cyclonedx.VulnerabilityRating{
+			Method: cyclonedx.ScoringMethodOther,
+			Vector: sev.Score,
+		}
+
+		switch sev.Type {
+		case models.SeverityCVSSV2:
+			rating.Method = cyclonedx.ScoringMethodCVSSv2
+		case models.SeverityCVSSV3:
+			rating.Method = cyclonedx.ScoringMethodCVSSv3
+			if strings.HasPrefix(sev.Score, "CVSS:3.1") {
+				rating.Method = cyclonedx.ScoringMethodCVSSv31
+			}
+		case models.SeverityCVSSV4:
+			// CycloneDX 1.5 does not have a method for CVSS v4, so it is rated as "other"
+		}
+
+		if score, level, err := severity.CalculateScore(sev); err == nil && score >= 0 {
+			rating.Score = &score
+			rating.Severity = cyclonedx.Severity(strings.ToLower(level))
+		}
+
+		ratings = append(ratings, rating)
+	}
+
+	return ratings
+}
+
+// cyclonedxVulnerability converts a vulnerability found in the given components to CycloneDX
+func cyclonedxVulnerability(vuln models.Vulnerability, affects []cyclonedx.Affects) cyclonedx.Vulnerability {
+	cdxVuln := cyclonedx.Vulnerability{
+		BOMRef: vuln.ID,
+		ID:     vuln.ID,
+		Source: &cyclonedx.Source{
+			Name: "OSV",
+			URL:  "https://osv.dev/vulnerability/" + vuln.ID,
+		},
+		Description: vuln.Summary,
+		Detail:      vuln.Details,
+		Affects:     &affects,
+	}
+
+	if !vuln.Published.IsZero() {
+		cdxVuln.Published = vuln.Published.Format(time.RFC3339)
+	}
+	if !vuln.Modified.IsZero() {
+		cdxVuln.Updated = vuln.Modified.Format(time.RFC3339)
+	}
+
+	if len(vuln.Aliases) > 0 {
+		references := make([]cyclonedx.VulnerabilityReference, 0, len(vuln.Aliases))
+		for _, alias := range vuln.Aliases {
+			references = append(references, cyclonedx.VulnerabilityReference{
+				ID: alias,
+				Source: &cyclonedx.Source{
+					URL: "https://osv.dev/vulnerability/" + alias,
+				},
+			})
+		}
+		cdxVuln.References = &references
+	}
+
+	if ratings := cyclonedxRatings(vuln); len(ratings) > 0 {
+		cdxVuln.Ratings = &ratings
+	}
+
+	var advisories []cyclonedx.Advisory
+	for _, ref := range vuln.References {
+		if ref.Type == models.ReferenceAdvisory {
+			advisories = append(advisories, cyclonedx.Advisory{URL: ref.URL})
+		}
+	}
+	if len(advisories) > 0 {
+		cdxVuln.Advisories = &advisories
+	}
+
+	return cdxVuln
+}
+
+// cyclonedxComponent converts a package that was scanned to a CycloneDX component
+func cyclonedxComponent(pkg models.PackageInfo) cyclonedx.Component {
+	version := pkg.Version
+	if version == "" {
+		version = pkg.Commit
+	}
+
+	return cyclonedx.Component{
+		BOMRef:     cyclonedxBOMRef(pkg),
+		Type:       cyclonedx.ComponentTypeLibrary,
+		Name:       pkg.Name,
+		Version:    version,
+		PackageURL: models.PackageToPURL(pkg),
+	}
+}
+
+// cyclonedxAffects returns that the version of the package of the referenced component is affected
+func cyclonedxAffects(ref string, pkg models.PackageInfo) cyclonedx.Affects {
+	return cyclonedx.Affects{
+		Ref: ref,
+		Range: &[]cyclonedx.AffectedVersions{
+			{
+				Version: pkg.Version,
+				Status:  cyclonedx.VulnerabilityStatusAffected,
+			},
+		},
+	}
+}
+
+// cyclonedxVulnerabilities converts the vulnerabilities that were found to CycloneDX, sorted by their ID
+func cyclonedxVulnerabilities(vulns map[string]models.Vulnerability, affects map[string][]cyclonedx.Affects) []cyclonedx.Vulnerability {
+	ids := make([]string, 0, len(vulns))
+	for id := range vulns {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	vulnerabilities := make([]cyclonedx.Vulnerability, 0, len(ids))
+	for _, id := range ids {
+		vulnerabilities = append(vulnerabilities, cyclonedxVulnerability(vulns[id], affects[id]))
+	}
+
+	return vulnerabilities
+}
+
+// writeCycloneDXBOM writes the BOM to the provided writer as JSON
+func writeCycloneDXBOM(bom *cyclonedx.BOM, outputWriter io.Writer) error {
+	encoder := cyclonedx.NewBOMEncoder(outputWriter, cyclonedx.BOMFileFormatJSON)
+	encoder.SetPretty(true)
+
+	if err := encoder.Encode(bom); err != nil {
+		return fmt.Errorf("failed to encode CycloneDX BOM: %w", err)
+	}
+
+	return nil
+}
+
+// PrintCycloneDXReport writes the packages that were scanned to the provided writer as
+// a CycloneDX BOM, with the vulnerabilities that were found in them
+func PrintCycloneDXReport(vulnResult *models.VulnerabilityResults, outputWriter io.Writer) error {
+	bom := cyclonedx.NewBOM()
+
+	components := []cyclonedx.Component{}
+	seenComponents := make(map[string]bool)
+
+	vulns := make(map[string]models.Vulnerability)
+	affects := make(map[string][]cyclonedx.Affects)
+
+	for _, source := range vulnResult.Results {
+		for _, pkg := range source.Packages {
+			ref := cyclonedxBOMRef(pkg.Package)
+
+			if !seenComponents[ref] {
+				seenComponents[ref] = true
+				components = append(components, cyclonedxComponent(pkg.Package))
+			}
+
+			for _, vuln := range pkg.Vulnerabilities {
+				vulns[vuln.ID] = vuln
+				affects[vuln.ID] = append(affects[vuln.ID], cyclonedxAffects(ref, pkg.Package))
+			}
+		}
+	}
+
+	vulnerabilities := cyclonedxVulnerabilities(vulns, affects)
+
+	bom.Components = &components
+	if len(vulnerabilities) > 0 {
+		bom.Vulnerabilities = &vulnerabilities
+	}
+
+	return writeCycloneDXBOM(bom, outputWriter)
+}
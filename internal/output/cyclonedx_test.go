@@ -0,0 +1,68 @@
+package output_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/CycloneDX/cyclonedx-go"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/internal/output"
+	"github.com/google/osv-scanner/internal/testutility"
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+func TestPrintCycloneDXReport(t *testing.T) {
+	t.Parallel()
+
+	results := testutility.LoadJSONFixture[models.VulnerabilityResults](t, "fixtures/test-vuln-results-a.json")
+
+	outputWriter := &bytes.Buffer{}
+	err := output.PrintCycloneDXReport(&results, outputWriter)
+
+	if err != nil {
+		t.Fatalf("Error writing CycloneDX output: %s", err)
+	}
+
+	var bom cyclonedx.BOM
+	if err := cyclonedx.NewBOMDecoder(outputWriter, cyclonedx.BOMFileFormatJSON).Decode(&bom); err != nil {
+		t.Fatalf("Output is not a valid CycloneDX BOM: %s", err)
+	}
+
+	wantComponents := []cyclonedx.Component{
+		{
+			BOMRef:     "pkg:golang/github.com/gogo/protobuf@1.3.1",
+			Type:       cyclonedx.ComponentTypeLibrary,
+			Name:       "github.com/gogo/protobuf",
+			Version:    "1.3.1",
+			PackageURL: "pkg:golang/github.com/gogo/protobuf@1.3.1",
+		},
+		{
+			BOMRef:     "pkg:cargo/regex@1.5.1",
+			Type:       cyclonedx.ComponentTypeLibrary,
+			Name:       "regex",
+			Version:    "1.5.1",
+			PackageURL: "pkg:cargo/regex@1.5.1",
+		},
+	}
+
+	if diff := cmp.Diff(wantComponents, *bom.Components); diff != "" {
+		t.Errorf("PrintCycloneDXReport() components (-want +got):\n%s", diff)
+	}
+
+	gotAffects := map[string][]string{}
+	for _, vuln := range *bom.Vulnerabilities {
+		for _, affects := range *vuln.Affects {
+			gotAffects[vuln.ID] = append(gotAffects[vuln.ID], affects.Ref)
+		}
+	}
+
+	wantAffects := map[string][]string{
+		"GO-2021-0053":        {"pkg:golang/github.com/gogo/protobuf@1.3.1"},
+		"GHSA-m5pq-gvj9-9vr8": {"pkg:cargo/regex@1.5.1"},
+		"RUSTSEC-2022-0013":   {"pkg:cargo/regex@1.5.1"},
+	}
+
+	if diff := cmp.Diff(wantAffects, gotAffects); diff != "" {
+		t.Errorf("PrintCycloneDXReport() vulnerabilities (-want +got):\n%s", diff)
+	}
+}
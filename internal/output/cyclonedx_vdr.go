@@ -0,0 +1,163 @@
+package output
+
+import (
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/CycloneDX/cyclonedx-go"
+	"github.com/google/osv-scanner/internal/sbom"
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+// cyclonedxLinkKey is the key of a package in a source that was scanned,
+// which is linked to the component of the package in the source
+func cyclonedxLinkKey(source models.SourceInfo, pkg models.PackageInfo) string {
+	return source.Path + ":" + pkg.Ecosystem + "/" + pkg.Name + "@" + pkg.Version
+}
+
+// cyclonedxSourceLinks returns BOM-Links to the components of the packages in the
+// CycloneDX SBOMs that were scanned, along with BOM-Links to the SBOMs themselves
+func cyclonedxSourceLinks(vulnResult *models.VulnerabilityResults) (map[string]string, []string) {
+	links := make(map[string]string)
+	var bomLinks []string
+
+	for _, source := range vulnResult.Results {
+		if source.Source.Type != "sbom" {
+			continue
+		}
+
+		file, err := os.Open(source.Source.Path)
+		if err != nil {
+			continue
+		}
+
+		// SBOMs that are not CycloneDX or that cannot be linked to are not an error,
+		// as their packages are included in the report as components instead
+		_ = (&sbom.CycloneDX{}).GetPackages(file, func(id sbom.Identifier) error {
+			if id.BOMLink == "" {
+				return nil
+			}
+
+			pkg, err := models.PURLToPackage(id.PURL)
+			if err != nil {
+				//nolint:nilerr
+				return nil
+			}
+
+			links[cyclonedxLinkKey(source.Source, pkg)] = id.BOMLink
+
+			bomLink, _, _ := strings.Cut(id.BOMLink, "#")
+			if !slices.Contains(bomLinks, bomLink) {
+				bomLinks = append(bomLinks, bomLink)
+			}
+
+			return nil
+		})
+
+		_ = file.Close()
+	}
+
+	return links, bomLinks
+}
+
+// cyclonedxAnalysis returns the analysis of a vulnerability based on if the vulnerable code
+// is called by any of the packages that it affects, and if any of them have a fixed version
+func cyclonedxAnalysis(called bool, fixed bool) *cyclonedx.VulnerabilityAnalysis {
+	if !called {
+		return &cyclonedx.VulnerabilityAnalysis{
+			State:         cyclonedx.IASNotAffected,
+			Justification: cyclonedx.IAJCodeNotReachable,
+			Detail:        "Call analysis found that the vulnerable code is not called",
+		}
+	}
+
+	analysis := &cyclonedx.VulnerabilityAnalysis{
+		State:  cyclonedx.IASInTriage,
+		Detail: "The affected version of the package is in use",
+	}
+
+	if fixed {
+		analysis.Response = &[]cyclonedx.ImpactAnalysisResponse{cyclonedx.IARUpdate}
+	}
+
+	return analysis
+}
+
+// PrintCycloneDXVDRReport writes the vulnerabilities that were found to the provided writer as
+// a CycloneDX Vulnerability Disclosure Report, which references the components of packages that
+// were scanned from a CycloneDX SBOM by their BOM-Link, and otherwise includes them as components
+func PrintCycloneDXVDRReport(vulnResult *models.VulnerabilityResults, outputWriter io.Writer) error {
+	bom := cyclonedx.NewBOM()
+
+	links, bomLinks := cyclonedxSourceLinks(vulnResult)
+
+	components := []cyclonedx.Component{}
+	seenComponents := make(map[string]bool)
+
+	vulns := make(map[string]models.Vulnerability)
+	affects := make(map[string][]cyclonedx.Affects)
+	seenAffects := make(map[string]bool)
+	called := make(map[string]bool)
+	fixed := make(map[string]bool)
+
+	for _, vf := range vulnResult.Flatten() {
+		// license violations are not vulnerabilities
+		if vf.Vulnerability.ID == "" {
+			continue
+		}
+
+		id := vf.Vulnerability.ID
+
+		ref, ok := links[cyclonedxLinkKey(vf.Source, vf.Package)]
+		if !ok {
+			ref = cyclonedxBOMRef(vf.Package)
+
+			if !seenComponents[ref] {
+				seenComponents[ref] = true
+				components = append(components, cyclonedxComponent(vf.Package))
+			}
+		}
+
+		vulns[id] = vf.Vulnerability
+		called[id] = called[id] || vf.GroupInfo.IsCalled()
+
+		pkg := models.Package{
+			Ecosystem: models.Ecosystem(vf.Package.Ecosystem),
+			Name:      vf.Package.Name,
+		}
+		fixed[id] = fixed[id] || len(vf.Vulnerability.FixedVersions()[pkg]) > 0
+
+		// the same package can be affected in more than one source
+		if seenAffects[id+" "+ref] {
+			continue
+		}
+		seenAffects[id+" "+ref] = true
+
+		affects[id] = append(affects[id], cyclonedxAffects(ref, vf.Package))
+	}
+
+	vulnerabilities := cyclonedxVulnerabilities(vulns, affects)
+	for i := range vulnerabilities {
+		vulnerabilities[i].Analysis = cyclonedxAnalysis(called[vulnerabilities[i].ID], fixed[vulnerabilities[i].ID])
+	}
+
+	if len(components) > 0 {
+		bom.Components = &components
+	}
+	bom.Vulnerabilities = &vulnerabilities
+
+	if len(bomLinks) > 0 {
+		references := make([]cyclonedx.ExternalReference, 0, len(bomLinks))
+		for _, bomLink := range bomLinks {
+			references = append(references, cyclonedx.ExternalReference{
+				URL:  bomLink,
+				Type: cyclonedx.ERTypeBOM,
+			})
+		}
+		bom.ExternalReferences = &references
+	}
+
+	return writeCycloneDXBOM(bom, outputWriter)
+}
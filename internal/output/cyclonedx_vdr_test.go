@@ -0,0 +1,106 @@
+package output_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/CycloneDX/cyclonedx-go"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/internal/output"
+	"github.com/google/osv-scanner/internal/testutility"
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+func TestPrintCycloneDXVDRReport(t *testing.T) {
+	t.Parallel()
+
+	results := testutility.LoadJSONFixture[models.VulnerabilityResults](t, "fixtures/test-vuln-results-a.json")
+
+	// the vulnerable code of the Go package is not called
+	results.Results[0].Packages[0].Groups[0].ExperimentalAnalysis = map[string]models.AnalysisInfo{
+		"GO-2021-0053": {Called: false},
+	}
+
+	// the Rust package was scanned from an SBOM that can be linked to
+	results.Results[1].Source = models.SourceInfo{Path: "fixtures/regex.cdx.json", Type: "sbom"}
+
+	outputWriter := &bytes.Buffer{}
+	err := output.PrintCycloneDXVDRReport(&results, outputWriter)
+
+	if err != nil {
+		t.Fatalf("Error writing CycloneDX VDR output: %s", err)
+	}
+
+	var bom cyclonedx.BOM
+	if err := cyclonedx.NewBOMDecoder(outputWriter, cyclonedx.BOMFileFormatJSON).Decode(&bom); err != nil {
+		t.Fatalf("Output is not a valid CycloneDX BOM: %s", err)
+	}
+
+	wantComponents := []cyclonedx.Component{
+		{
+			BOMRef:     "pkg:golang/github.com/gogo/protobuf@1.3.1",
+			Type:       cyclonedx.ComponentTypeLibrary,
+			Name:       "github.com/gogo/protobuf",
+			Version:    "1.3.1",
+			PackageURL: "pkg:golang/github.com/gogo/protobuf@1.3.1",
+		},
+	}
+
+	if diff := cmp.Diff(wantComponents, *bom.Components); diff != "" {
+		t.Errorf("PrintCycloneDXVDRReport() components (-want +got):\n%s", diff)
+	}
+
+	wantReferences := []cyclonedx.ExternalReference{
+		{
+			URL:  "urn:cdx:3e671687-395b-41f5-a30f-a58921a69b79/1",
+			Type: cyclonedx.ERTypeBOM,
+		},
+	}
+
+	if diff := cmp.Diff(wantReferences, *bom.ExternalReferences); diff != "" {
+		t.Errorf("PrintCycloneDXVDRReport() external references (-want +got):\n%s", diff)
+	}
+
+	type finding struct {
+		Affects  []string
+		Analysis cyclonedx.VulnerabilityAnalysis
+	}
+
+	gotFindings := map[string]finding{}
+	for _, vuln := range *bom.Vulnerabilities {
+		f := finding{Analysis: *vuln.Analysis}
+		for _, affects := range *vuln.Affects {
+			f.Affects = append(f.Affects, affects.Ref)
+		}
+		gotFindings[vuln.ID] = f
+	}
+
+	inTriage := cyclonedx.VulnerabilityAnalysis{
+		State:    cyclonedx.IASInTriage,
+		Response: &[]cyclonedx.ImpactAnalysisResponse{cyclonedx.IARUpdate},
+		Detail:   "The affected version of the package is in use",
+	}
+
+	wantFindings := map[string]finding{
+		"GO-2021-0053": {
+			Affects: []string{"pkg:golang/github.com/gogo/protobuf@1.3.1"},
+			Analysis: cyclonedx.VulnerabilityAnalysis{
+				State:         cyclonedx.IASNotAffected,
+				Justification: cyclonedx.IAJCodeNotReachable,
+				Detail:        "Call analysis found that the vulnerable code is not called",
+			},
+		},
+		"GHSA-m5pq-gvj9-9vr8": {
+			Affects:  []string{"urn:cdx:3e671687-395b-41f5-a30f-a58921a69b79/1#regex"},
+			Analysis: inTriage,
+		},
+		"RUSTSEC-2022-0013": {
+			Affects:  []string{"urn:cdx:3e671687-395b-41f5-a30f-a58921a69b79/1#regex"},
+			Analysis: inTriage,
+		},
+	}
+
+	if diff := cmp.Diff(wantFindings, gotFindings); diff != "" {
+		t.Errorf("PrintCycloneDXVDRReport() vulnerabilities (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,246 @@
+//nolint:nosnakecase
+package output
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/CycloneDX/cyclonedx-go"
+	"github.com/google/osv-scanner/pkg/models"
+	spdx_json "github.com/spdx/tools-golang/json"
+	"github.com/spdx/tools-golang/spdx/v2/v2_3"
+	"github.com/spdx/tools-golang/tagvalue"
+)
+
+// ErrUnsupportedSBOM is returned when an SBOM cannot be enriched, as it
+// is not a CycloneDX BOM or an SPDX 2 document in a format that can be written
+var ErrUnsupportedSBOM = errors.New("unsupported SBOM")
+
+// vulnerabilitiesByPackage returns the vulnerabilities that were found in each package,
+// regardless of the source that it is in
+func vulnerabilitiesByPackage(vulnResult *models.VulnerabilityResults) map[string][]models.Vulnerability {
+	vulns := make(map[string][]models.Vulnerability)
+
+	for _, source := range vulnResult.Results {
+		for _, pkg := range source.Packages {
+			key := pkg.Package.Ecosystem + "/" + pkg.Package.Name + "@" + pkg.Package.Version
+
+			for _, vuln := range pkg.Vulnerabilities {
+				if !slices.ContainsFunc(vulns[key], func(v models.Vulnerability) bool { return v.ID == vuln.ID }) {
+					vulns[key] = append(vulns[key], vuln)
+				}
+			}
+		}
+	}
+
+	return vulns
+}
+
+// purlVulnerabilities returns the vulnerabilities of the package that a PURL is for
+func purlVulnerabilities(vulns map[string][]models.Vulnerability, purl string) []models.Vulnerability {
+	pkg, err := models.PURLToPackage(purl)
+	if err != nil {
+		return nil
+	}
+
+	return vulns[pkg.Ecosystem+"/"+pkg.Name+"@"+pkg.Version]
+}
+
+// enrichCycloneDXComponents adds the components that are affected by each vulnerability to
+// affects, recursing into the components that they are made of, and gives the components that
+// are affected a bom-ref if they do not have one so that they can be referenced
+func enrichCycloneDXComponents(
+	components *[]cyclonedx.Component,
+	vulns map[string][]models.Vulnerability,
+	found map[string]models.Vulnerability,
+	affects map[string][]cyclonedx.Affects,
+) {
+	if components == nil {
+		return
+	}
+
+	for i := range *components {
+		component := &(*components)[i]
+
+		if component.PackageURL != "" {
+			for _, vuln := range purlVulnerabilities(vulns, component.PackageURL) {
+				if component.BOMRef == "" {
+					component.BOMRef = component.PackageURL
+				}
+
+				found[vuln.ID] = vuln
+				affects[vuln.ID] = append(affects[vuln.ID], cyclonedx.Affects{
+					Ref: component.BOMRef,
+					Range: &[]cyclonedx.AffectedVersions{
+						{
+							Version: component.Version,
+							Status:  cyclonedx.VulnerabilityStatusAffected,
+						},
+					},
+				})
+			}
+		}
+
+		enrichCycloneDXComponents(component.Components, vulns, found, affects)
+	}
+}
+
+// enrichCycloneDX adds the vulnerabilities of the components of the BOM to it, returning if any were added.
+//
+// Vulnerabilities that the BOM already has keep their analysis, and only have the
+// components that they affect added to them if they are not already included.
+func enrichCycloneDX(bom *cyclonedx.BOM, vulns map[string][]models.Vulnerability) bool {
+	found := make(map[string]models.Vulnerability)
+	affects := make(map[string][]cyclonedx.Affects)
+
+	if bom.Metadata != nil && bom.Metadata.Component != nil {
+		enrichCycloneDXComponents(bom.Metadata.Component.Components, vulns, found, affects)
+	}
+	enrichCycloneDXComponents(bom.Components, vulns, found, affects)
+
+	if len(found) == 0 {
+		return false
+	}
+
+	added := false
+
+	vulnerabilities := []cyclonedx.Vulnerability{}
+	if bom.Vulnerabilities != nil {
+		vulnerabilities = *bom.Vulnerabilities
+	}
+
+	for i := range vulnerabilities {
+		existing := &vulnerabilities[i]
+
+		if _, ok := found[existing.ID]; !ok {
+			continue
+		}
+
+		existingAffects := []cyclonedx.Affects{}
+		if existing.Affects != nil {
+			existingAffects = *existing.Affects
+		}
+
+		for _, a := range affects[existing.ID] {
+			if !slices.ContainsFunc(existingAffects, func(e cyclonedx.Affects) bool { return e.Ref == a.Ref }) {
+				existingAffects = append(existingAffects, a)
+				added = true
+			}
+		}
+
+		existing.Affects = &existingAffects
+		delete(found, existing.ID)
+	}
+
+	ids := make([]string, 0, len(found))
+	for id := range found {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		vulnerabilities = append(vulnerabilities, cyclonedxVulnerability(found[id], affects[id]))
+		added = true
+	}
+
+	bom.Vulnerabilities = &vulnerabilities
+
+	return added
+}
+
+// enrichSPDX adds security references to the advisories of the vulnerabilities of the
+// packages of the document to them
+func enrichSPDX(doc *v2_3.Document, vulns map[string][]models.Vulnerability) {
+	for _, pkg := range doc.Packages {
+		var purls []string
+		for _, ref := range pkg.PackageExternalReferences {
+			if ref.RefType == "purl" {
+				purls = append(purls, ref.Locator)
+			}
+		}
+
+		for _, purl := range purls {
+			for _, vuln := range purlVulnerabilities(vulns, purl) {
+				addSPDXAdvisory(pkg, vuln.ID)
+			}
+		}
+	}
+}
+
+// readCycloneDX reads a CycloneDX BOM in either JSON or XML, returning the format that it is in
+func readCycloneDX(r io.ReadSeeker) (*cyclonedx.BOM, cyclonedx.BOMFileFormat, error) {
+	var errs []error
+
+	for _, format := range []cyclonedx.BOMFileFormat{cyclonedx.BOMFileFormatJSON, cyclonedx.BOMFileFormatXML} {
+		if _, err := r.Seek(0, io.SeekStart); err != nil {
+			return nil, format, fmt.Errorf("failed to seek to start of file: %w", err)
+		}
+
+		var bom cyclonedx.BOM
+		err := cyclonedx.NewBOMDecoder(r, format).Decode(&bom)
+
+		if err == nil {
+			if bom.BOMFormat == "CycloneDX" || strings.HasPrefix(bom.XMLNS, "http://cyclonedx.org/schema/bom") {
+				return &bom, format, nil
+			}
+
+			err = errors.New("invalid BOMFormat")
+		}
+
+		errs = append(errs, err)
+	}
+
+	return nil, cyclonedx.BOMFileFormatJSON, errors.Join(errs...)
+}
+
+// EnrichSBOM reads a CycloneDX BOM or SPDX 2 document, and writes it to the provided
+// writer in the same format with the vulnerabilities that were found in its packages.
+//
+// CycloneDX BOMs have the vulnerabilities added to their vulnerabilities, and their version
+// incremented if any were added, while the packages of SPDX documents have security references
+// to the advisories of their vulnerabilities added to them.
+func EnrichSBOM(r io.ReadSeeker, vulnResult *models.VulnerabilityResults, outputWriter io.Writer) error {
+	vulns := vulnerabilitiesByPackage(vulnResult)
+
+	if bom, format, err := readCycloneDX(r); err == nil {
+		if enrichCycloneDX(bom, vulns) {
+			bom.Version++
+		}
+
+		encoder := cyclonedx.NewBOMEncoder(outputWriter, format)
+		encoder.SetPretty(true)
+
+		// vulnerabilities were added to CycloneDX in 1.4, so older BOMs are upgraded to it
+		if errEncode := encoder.EncodeVersion(bom, max(bom.SpecVersion, cyclonedx.SpecVersion1_4)); errEncode != nil {
+			return fmt.Errorf("failed to encode CycloneDX BOM: %w", errEncode)
+		}
+
+		return nil
+	}
+
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to seek to start of file: %w", err)
+	}
+
+	if doc, err := spdx_json.Read(r); err == nil && doc.SPDXVersion != "" {
+		enrichSPDX(doc, vulns)
+
+		return spdx_json.Write(doc, outputWriter, spdx_json.Indent("  "))
+	}
+
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to seek to start of file: %w", err)
+	}
+
+	if doc, err := tagvalue.Read(r); err == nil && doc.SPDXVersion != "" {
+		enrichSPDX(doc, vulns)
+
+		return tagvalue.Write(doc, outputWriter)
+	}
+
+	return fmt.Errorf("%w: only CycloneDX BOMs and SPDX 2 documents in JSON or tag-value can be enriched", ErrUnsupportedSBOM)
+}
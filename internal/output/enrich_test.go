@@ -0,0 +1,153 @@
+package output_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/CycloneDX/cyclonedx-go"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/internal/output"
+	"github.com/google/osv-scanner/internal/testutility"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/spdx/tools-golang/tagvalue"
+)
+
+func enrichFixture(t *testing.T, path string) *bytes.Buffer {
+	t.Helper()
+
+	results := testutility.LoadJSONFixture[models.VulnerabilityResults](t, "fixtures/test-vuln-results-a.json")
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Failed to open fixture: %v", err)
+	}
+	defer f.Close()
+
+	outputWriter := &bytes.Buffer{}
+	if err := output.EnrichSBOM(f, &results, outputWriter); err != nil {
+		t.Fatalf("Error enriching SBOM: %s", err)
+	}
+
+	return outputWriter
+}
+
+func TestEnrichSBOM_CycloneDX(t *testing.T) {
+	t.Parallel()
+
+	outputWriter := enrichFixture(t, "fixtures/enrich/bom.cdx.json")
+
+	var bom cyclonedx.BOM
+	if err := cyclonedx.NewBOMDecoder(outputWriter, cyclonedx.BOMFileFormatJSON).Decode(&bom); err != nil {
+		t.Fatalf("Output is not a valid CycloneDX BOM: %s", err)
+	}
+
+	if bom.SerialNumber != "urn:uuid:3e671687-395b-41f5-a30f-a58921a69b79" {
+		t.Errorf("expected the serial number of the BOM to be kept, but got %q", bom.SerialNumber)
+	}
+
+	if bom.Version != 2 {
+		t.Errorf("expected the version of the BOM to be incremented to 2, but got %d", bom.Version)
+	}
+
+	wantProperties := []cyclonedx.Property{{Name: "build", Value: "1234"}}
+	if diff := cmp.Diff(wantProperties, *bom.Metadata.Properties); diff != "" {
+		t.Errorf("EnrichSBOM() metadata properties (-want +got):\n%s", diff)
+	}
+
+	if ref := (*bom.Components)[0].BOMRef; ref != "pkg:cargo/regex@1.5.1" {
+		t.Errorf("expected the affected component to be given a bom-ref, but got %q", ref)
+	}
+
+	if ref := (*bom.Components)[1].BOMRef; ref != "left-pad" {
+		t.Errorf("expected the bom-ref of an unaffected component to be kept, but got %q", ref)
+	}
+
+	type finding struct {
+		Affects  []string
+		Analysis *cyclonedx.VulnerabilityAnalysis
+	}
+
+	var gotFindings []string
+	got := map[string]finding{}
+	for _, vuln := range *bom.Vulnerabilities {
+		f := finding{Analysis: vuln.Analysis}
+		for _, affects := range *vuln.Affects {
+			f.Affects = append(f.Affects, affects.Ref)
+		}
+		gotFindings = append(gotFindings, vuln.ID)
+		got[vuln.ID] = f
+	}
+
+	wantFindings := []string{"GO-2021-0053", "GHSA-m5pq-gvj9-9vr8", "RUSTSEC-2022-0013"}
+	if diff := cmp.Diff(wantFindings, gotFindings); diff != "" {
+		t.Errorf("EnrichSBOM() vulnerabilities (-want +got):\n%s", diff)
+	}
+
+	want := map[string]finding{
+		"GO-2021-0053": {
+			Affects: []string{"my-app", "protobuf"},
+			Analysis: &cyclonedx.VulnerabilityAnalysis{
+				State:         cyclonedx.IASNotAffected,
+				Justification: cyclonedx.IAJCodeNotReachable,
+			},
+		},
+		"GHSA-m5pq-gvj9-9vr8": {Affects: []string{"pkg:cargo/regex@1.5.1"}},
+		"RUSTSEC-2022-0013":   {Affects: []string{"pkg:cargo/regex@1.5.1"}},
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("EnrichSBOM() affects (-want +got):\n%s", diff)
+	}
+}
+
+func TestEnrichSBOM_SPDX(t *testing.T) {
+	t.Parallel()
+
+	outputWriter := enrichFixture(t, "fixtures/enrich/document.spdx")
+
+	doc, err := tagvalue.Read(outputWriter)
+	if err != nil {
+		t.Fatalf("Output is not a valid SPDX document: %s", err)
+	}
+
+	if doc.DocumentNamespace != "https://example.com/spdxdocs/my-app-1234" {
+		t.Errorf("expected the namespace of the document to be kept, but got %q", doc.DocumentNamespace)
+	}
+
+	got := map[string][]string{}
+	for _, pkg := range doc.Packages {
+		for _, ref := range pkg.PackageExternalReferences {
+			got[pkg.PackageName] = append(got[pkg.PackageName], ref.RefType+" "+ref.Locator)
+		}
+	}
+
+	want := map[string][]string{
+		"regex": {
+			"purl pkg:cargo/regex@1.5.1",
+			"advisory https://osv.dev/vulnerability/GHSA-m5pq-gvj9-9vr8",
+			"advisory https://osv.dev/vulnerability/RUSTSEC-2022-0013",
+		},
+		"left-pad": {
+			"purl pkg:npm/left-pad@1.3.0",
+		},
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("EnrichSBOM() external references (-want +got):\n%s", diff)
+	}
+}
+
+func TestEnrichSBOM_Unsupported(t *testing.T) {
+	t.Parallel()
+
+	results := testutility.LoadJSONFixture[models.VulnerabilityResults](t, "fixtures/test-vuln-results-a.json")
+
+	err := output.EnrichSBOM(strings.NewReader("not an sbom"), &results, &bytes.Buffer{})
+
+	if !errors.Is(err, output.ErrUnsupportedSBOM) {
+		t.Errorf("expected \"%v\" error but got \"%v\"", output.ErrUnsupportedSBOM, err)
+	}
+}
@@ -0,0 +1,62 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/google/osv-scanner/internal/ci"
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+// PrintPackageDiffJSON writes the difference between two scans to the provided writer in JSON format
+func PrintPackageDiffJSON(diff ci.PackageDiff, outputWriter io.Writer) error {
+	encoder := json.NewEncoder(outputWriter)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(diff)
+}
+
+// PrintPackageDiffTable prints the difference between two scans into a human friendly table,
+// followed by a summary of the vulnerabilities that were introduced and fixed
+func PrintPackageDiffTable(diff ci.PackageDiff, outputWriter io.Writer, terminalWidth int) {
+	outputTable := newTable(outputWriter, terminalWidth)
+	outputTable.AppendHeader(table.Row{"Change", "Ecosystem", "Package", "Old Version", "New Version", "Introduced", "Fixed"})
+
+	for _, changes := range []struct {
+		kind    string
+		changes []ci.PackageChange
+	}{
+		{"added", diff.Added},
+		{"removed", diff.Removed},
+		{"changed", diff.Changed},
+	} {
+		for _, change := range changes.changes {
+			outputTable.AppendRow(table.Row{
+				changes.kind,
+				change.Ecosystem,
+				change.Name,
+				change.OldVersion,
+				change.NewVersion,
+				strings.Join(change.IntroducedVulns, "\n"),
+				strings.Join(change.FixedVulns, "\n"),
+			})
+		}
+	}
+
+	if outputTable.Length() == 0 {
+		fmt.Fprintln(outputWriter, "No packages were added, removed, or changed")
+	} else {
+		outputTable.Render()
+	}
+
+	fmt.Fprintf(
+		outputWriter,
+		"%d %s introduced, %d %s fixed\n",
+		len(diff.IntroducedVulns),
+		Form(len(diff.IntroducedVulns), "vulnerability", "vulnerabilities"),
+		len(diff.FixedVulns),
+		Form(len(diff.FixedVulns), "vulnerability", "vulnerabilities"),
+	)
+}
@@ -0,0 +1,89 @@
+package output_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/internal/ci"
+	"github.com/google/osv-scanner/internal/output"
+)
+
+var testPackageDiff = ci.PackageDiff{
+	Added:   []ci.PackageChange{},
+	Removed: []ci.PackageChange{},
+	Changed: []ci.PackageChange{
+		{
+			Name:       "lodash",
+			Ecosystem:  "npm",
+			OldVersion: "4.17.20",
+			NewVersion: "4.17.21",
+			FixedVulns: []string{"GHSA-29mw-wpgm-hmr9", "GHSA-35jh-r3h4-6jhm"},
+		},
+	},
+	IntroducedVulns: []string{},
+	FixedVulns:      []string{"GHSA-29mw-wpgm-hmr9", "GHSA-35jh-r3h4-6jhm"},
+}
+
+func TestPrintPackageDiffJSON(t *testing.T) {
+	t.Parallel()
+
+	outputWriter := &bytes.Buffer{}
+	if err := output.PrintPackageDiffJSON(testPackageDiff, outputWriter); err != nil {
+		t.Fatalf("Error writing JSON output: %s", err)
+	}
+
+	var got ci.PackageDiff
+	if err := json.NewDecoder(outputWriter).Decode(&got); err != nil {
+		t.Fatalf("Output is not valid JSON: %s", err)
+	}
+
+	if diff := cmp.Diff(testPackageDiff, got); diff != "" {
+		t.Errorf("PrintPackageDiffJSON() (-want +got):\n%s", diff)
+	}
+}
+
+func TestPrintPackageDiffTable(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		diff ci.PackageDiff
+		want []string
+	}{
+		{
+			name: "no changes",
+			diff: ci.PackageDiff{},
+			want: []string{
+				"No packages were added, removed, or changed\n",
+				"0 vulnerabilities introduced, 0 vulnerabilities fixed\n",
+			},
+		},
+		{
+			name: "changed package",
+			diff: testPackageDiff,
+			want: []string{
+				"| changed | npm       | lodash  | 4.17.20     | 4.17.21     |            | GHSA-29mw-wpgm-hmr9 |",
+				"0 vulnerabilities introduced, 2 vulnerabilities fixed\n",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			outputWriter := &bytes.Buffer{}
+			output.PrintPackageDiffTable(tt.diff, outputWriter, 0)
+
+			for _, want := range tt.want {
+				if !strings.Contains(outputWriter.String(), want) {
+					t.Errorf("PrintPackageDiffTable() output does not contain %q:\n%s", want, outputWriter.String())
+				}
+			}
+		})
+	}
+}
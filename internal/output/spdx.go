@@ -0,0 +1,132 @@
+//nolint:nosnakecase
+package output
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/osv-scanner/internal/version"
+	"github.com/google/osv-scanner/pkg/models"
+	spdx_json "github.com/spdx/tools-golang/json"
+	"github.com/spdx/tools-golang/spdx/v2/common"
+	"github.com/spdx/tools-golang/spdx/v2/v2_3"
+	"github.com/spdx/tools-golang/tagvalue"
+)
+
+// spdxNoAssertion is the value of fields that the scanner does not know the value of
+const spdxNoAssertion = "NOASSERTION"
+
+// addSPDXAdvisory adds a security reference to the advisory of a vulnerability to the package,
+// unless it already has one, such as when it has the same vulnerability in more than one source
+func addSPDXAdvisory(spdxPkg *v2_3.Package, id string) {
+	locator := "https://osv.dev/vulnerability/" + id
+
+	if slices.ContainsFunc(spdxPkg.PackageExternalReferences, func(ref *v2_3.PackageExternalReference) bool {
+		return ref.Category == common.CategorySecurity && ref.Locator == locator
+	}) {
+		return
+	}
+
+	spdxPkg.PackageExternalReferences = append(spdxPkg.PackageExternalReferences, &v2_3.PackageExternalReference{
+		Category: common.CategorySecurity,
+		RefType:  common.TypeSecurityAdvisory,
+		Locator:  locator,
+	})
+}
+
+// buildSPDXDocument builds an SPDX 2.3 document of the packages that were scanned, with
+// the vulnerabilities that were found in each of them as security references
+func buildSPDXDocument(vulnResult *models.VulnerabilityResults, created time.Time) *v2_3.Document {
+	doc := &v2_3.Document{
+		SPDXVersion:    v2_3.Version,
+		DataLicense:    v2_3.DataLicense,
+		SPDXIdentifier: "DOCUMENT",
+		DocumentName:   "osv-scanner",
+		CreationInfo: &v2_3.CreationInfo{
+			Creators: []common.Creator{
+				{Creator: "osv-scanner-" + version.OSVVersion, CreatorType: "Tool"},
+			},
+			Created: created.UTC().Format(time.RFC3339),
+		},
+	}
+
+	// the namespace of the document must be unique, so it is based on what it contains
+	hash := sha256.New()
+	packages := make(map[string]*v2_3.Package)
+
+	for _, source := range vulnResult.Results {
+		for _, pkg := range source.Packages {
+			key := pkg.Package.Ecosystem + "/" + pkg.Package.Name + "@" + pkg.Package.Version + pkg.Package.Commit
+
+			spdxPkg, ok := packages[key]
+			if !ok {
+				id := common.ElementID(fmt.Sprintf("Package-%d", len(packages)+1))
+
+				fmt.Fprintln(hash, key)
+
+				pkgVersion := pkg.Package.Version
+				if pkgVersion == "" {
+					pkgVersion = pkg.Package.Commit
+				}
+
+				spdxPkg = &v2_3.Package{
+					PackageName:             pkg.Package.Name,
+					PackageSPDXIdentifier:   id,
+					PackageVersion:          pkgVersion,
+					PackageDownloadLocation: spdxNoAssertion,
+					PackageLicenseConcluded: spdxNoAssertion,
+					PackageLicenseDeclared:  spdxNoAssertion,
+					PackageCopyrightText:    spdxNoAssertion,
+				}
+
+				if len(pkg.Licenses) > 0 && !slices.Contains(pkg.Licenses, "UNKNOWN") {
+					licenses := make([]string, 0, len(pkg.Licenses))
+					for _, license := range pkg.Licenses {
+						licenses = append(licenses, string(license))
+					}
+					spdxPkg.PackageLicenseDeclared = strings.Join(licenses, " AND ")
+				}
+
+				if purl := models.PackageToPURL(pkg.Package); purl != "" {
+					spdxPkg.PackageExternalReferences = append(spdxPkg.PackageExternalReferences, &v2_3.PackageExternalReference{
+						Category: common.CategoryPackageManager,
+						RefType:  common.TypePackageManagerPURL,
+						Locator:  purl,
+					})
+				}
+
+				packages[key] = spdxPkg
+				doc.Packages = append(doc.Packages, spdxPkg)
+				doc.Relationships = append(doc.Relationships, &v2_3.Relationship{
+					RefA:         common.MakeDocElementID("", "DOCUMENT"),
+					RefB:         common.MakeDocElementID("", string(id)),
+					Relationship: common.TypeRelationshipDescribe,
+				})
+			}
+
+			for _, vuln := range pkg.Vulnerabilities {
+				addSPDXAdvisory(spdxPkg, vuln.ID)
+			}
+		}
+	}
+
+	doc.DocumentNamespace = fmt.Sprintf("https://osv.dev/spdxdocs/osv-scanner-%x", hash.Sum(nil))
+
+	return doc
+}
+
+// PrintSPDXJSONReport writes the packages that were scanned to the provided writer as an
+// SPDX 2.3 document in JSON, with the vulnerabilities that were found in them
+func PrintSPDXJSONReport(vulnResult *models.VulnerabilityResults, outputWriter io.Writer) error {
+	return spdx_json.Write(buildSPDXDocument(vulnResult, time.Now()), outputWriter, spdx_json.Indent("  "))
+}
+
+// PrintSPDXTagValueReport writes the packages that were scanned to the provided writer as
+// an SPDX 2.3 document in the tag-value format, with the vulnerabilities that were found in them
+func PrintSPDXTagValueReport(vulnResult *models.VulnerabilityResults, outputWriter io.Writer) error {
+	return tagvalue.Write(buildSPDXDocument(vulnResult, time.Now()), outputWriter)
+}
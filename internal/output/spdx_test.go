@@ -0,0 +1,83 @@
+package output_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/internal/output"
+	"github.com/google/osv-scanner/internal/testutility"
+	"github.com/google/osv-scanner/pkg/models"
+	spdx_json "github.com/spdx/tools-golang/json"
+	"github.com/spdx/tools-golang/spdx"
+	"github.com/spdx/tools-golang/tagvalue"
+)
+
+func TestPrintSPDXReports(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		print func(*models.VulnerabilityResults, io.Writer) error
+		read  func(io.Reader) (*spdx.Document, error)
+	}{
+		{
+			name:  "json",
+			print: output.PrintSPDXJSONReport,
+			read:  spdx_json.Read,
+		},
+		{
+			name:  "tag-value",
+			print: output.PrintSPDXTagValueReport,
+			read:  tagvalue.Read,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			results := testutility.LoadJSONFixture[models.VulnerabilityResults](t, "fixtures/test-vuln-results-a.json")
+
+			outputWriter := &bytes.Buffer{}
+			if err := tt.print(&results, outputWriter); err != nil {
+				t.Fatalf("Error writing SPDX output: %s", err)
+			}
+
+			doc, err := tt.read(outputWriter)
+			if err != nil {
+				t.Fatalf("Output is not a valid SPDX document: %s", err)
+			}
+
+			got := map[string][]string{}
+			for _, pkg := range doc.Packages {
+				key := pkg.PackageName + "@" + pkg.PackageVersion
+				for _, ref := range pkg.PackageExternalReferences {
+					got[key] = append(got[key], ref.RefType+" "+ref.Locator)
+				}
+			}
+
+			want := map[string][]string{
+				"github.com/gogo/protobuf@1.3.1": {
+					"purl pkg:golang/github.com/gogo/protobuf@1.3.1",
+					"advisory https://osv.dev/vulnerability/GO-2021-0053",
+				},
+				"regex@1.5.1": {
+					"purl pkg:cargo/regex@1.5.1",
+					"advisory https://osv.dev/vulnerability/GHSA-m5pq-gvj9-9vr8",
+					"advisory https://osv.dev/vulnerability/RUSTSEC-2022-0013",
+				},
+			}
+
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("SPDX packages (-want +got):\n%s", diff)
+			}
+
+			if len(doc.Relationships) != len(doc.Packages) {
+				t.Errorf("expected the document to describe %d packages, but it has %d relationships", len(doc.Packages), len(doc.Relationships))
+			}
+		})
+	}
+}
@@ -34,6 +34,11 @@ func PrintTableResults(vulnResult *models.VulnerabilityResults, outputWriter io.
 		outputTable.Render()
 	}
 
+	printImages(vulnResult, outputWriter)
+	printBaseImages(vulnResult, outputWriter)
+	printPlatformDifferences(vulnResult, outputWriter)
+	printWorkloads(vulnResult, outputWriter)
+
 	// Render the licenses if any.
 	outputLicenseTable := newTable(outputWriter, terminalWidth)
 	outputLicenseTable = licenseTableBuilder(outputLicenseTable, vulnResult)
@@ -43,6 +48,85 @@ func PrintTableResults(vulnResult *models.VulnerabilityResults, outputWriter io.
 	outputLicenseTable.Render()
 }
 
+// printWorkloads prints how many vulnerabilities each of the images that the workloads of
+// a Kubernetes cluster run have, so that they can be traced back to what runs them
+func printWorkloads(vulnResult *models.VulnerabilityResults, outputWriter io.Writer) {
+	for _, workload := range vulnResult.Workloads {
+		for _, image := range workload.Images {
+			fmt.Fprintf(
+				outputWriter,
+				"%s/%s/%s runs %s, which has %d known %s\n",
+				workload.Namespace,
+				workload.Kind,
+				workload.Name,
+				image.Image,
+				len(image.Vulnerabilities),
+				Form(len(image.Vulnerabilities), "vulnerability", "vulnerabilities"),
+			)
+		}
+	}
+}
+
+// printPlatformDifferences prints the vulnerabilities of the container images that were scanned
+// for more than one platform that only some of the platforms have
+func printPlatformDifferences(vulnResult *models.VulnerabilityResults, outputWriter io.Writer) {
+	for _, differences := range vulnResult.PlatformDifferences {
+		if len(differences.Vulnerabilities) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(
+			outputWriter,
+			"%s has %d %s that not all of %s have:\n",
+			differences.Image,
+			len(differences.Vulnerabilities),
+			Form(len(differences.Vulnerabilities), "vulnerability", "vulnerabilities"),
+			strings.Join(differences.Platforms, ", "),
+		)
+
+		for _, vuln := range differences.Vulnerabilities {
+			fmt.Fprintf(outputWriter, "  %s in %s only affects %s\n", vuln.ID, vuln.Package, strings.Join(vuln.Platforms, ", "))
+		}
+	}
+}
+
+// printImages prints the distributions and the runtimes of languages that were detected in the container images that were scanned
+func printImages(vulnResult *models.VulnerabilityResults, outputWriter io.Writer) {
+	for _, image := range vulnResult.Images {
+		if image.OS != "" {
+			fmt.Fprintf(outputWriter, "%s runs on %s\n", image.Image, image.OS)
+		}
+
+		for _, runtime := range image.Runtimes {
+			fmt.Fprintf(outputWriter, "%s has %s %s installed at %s\n", image.Image, runtime.Name, runtime.Version, runtime.Path)
+		}
+	}
+}
+
+// printBaseImages prints the base images of the container images that were scanned,
+// and the newer base image that each of them is recommended to be rebuilt on
+func printBaseImages(vulnResult *models.VulnerabilityResults, outputWriter io.Writer) {
+	for _, base := range vulnResult.BaseImages {
+		fmt.Fprintf(
+			outputWriter,
+			"%s is built on %s, which introduced %d known %s\n",
+			base.Image,
+			base.Name,
+			len(base.Vulnerabilities),
+			Form(len(base.Vulnerabilities), "vulnerability", "vulnerabilities"),
+		)
+
+		if base.Recommendation != nil {
+			fmt.Fprintf(
+				outputWriter,
+				"Rebuild on %s to remove %d of them\n",
+				base.Recommendation.Name,
+				len(base.Recommendation.Fixed),
+			)
+		}
+	}
+}
+
 func newTable(outputWriter io.Writer, terminalWidth int) table.Writer {
 	outputTable := table.NewWriter()
 	outputTable.SetOutputMirror(outputWriter)
@@ -58,8 +142,54 @@ func newTable(outputWriter io.Writer, terminalWidth int) table.Writer {
 	return outputTable
 }
 
+// maxOriginCommandLength is how much of the command of the layer that introduced a package is shown,
+// as commands that install packages tend to be long enough to crowd out the rest of the table
+const maxOriginCommandLength = 60
+
+// hasImageOrigins returns if any of the packages were found in a container image,
+// in which case the layer that introduced each of them is shown
+func hasImageOrigins(vulnResult *models.VulnerabilityResults) bool {
+	for _, sourceRes := range vulnResult.Results {
+		for _, pkg := range sourceRes.Packages {
+			if pkg.ImageOrigin != nil {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// imageOriginString describes the layer that introduced a package by its number and command
+func imageOriginString(origin *models.ImageOrigin) string {
+	if origin == nil {
+		return ""
+	}
+
+	s := fmt.Sprintf("layer %d", origin.Layer)
+	if origin.InBaseImage {
+		s += " (base image)"
+	}
+
+	if origin.Command == "" {
+		return s
+	}
+
+	command := []rune(origin.Command)
+	if len(command) > maxOriginCommandLength {
+		command = append(command[:maxOriginCommandLength-3], []rune("...")...)
+	}
+
+	return s + ": " + string(command)
+}
+
 func tableBuilder(outputTable table.Writer, vulnResult *models.VulnerabilityResults, addStyling bool) table.Writer {
-	outputTable.AppendHeader(table.Row{"OSV URL", "CVSS", "Ecosystem", "Package", "Version", "Source"})
+	header := table.Row{"OSV URL", "CVSS", "Ecosystem", "Package", "Version", "Source"}
+	if hasImageOrigins(vulnResult) {
+		header = append(header, "Introduced In")
+	}
+
+	outputTable.AppendHeader(header)
 	rows := tableBuilderInner(vulnResult, addStyling, true)
 	for _, elem := range rows {
 		outputTable.AppendRow(elem.row, table.RowConfig{AutoMerge: elem.shouldMerge})
@@ -88,6 +218,7 @@ type tbInnerResponse struct {
 
 func tableBuilderInner(vulnResult *models.VulnerabilityResults, addStyling bool, calledVulns bool) []tbInnerResponse {
 	allOutputRows := []tbInnerResponse{}
+	showOrigins := hasImageOrigins(vulnResult)
 	// Working directory used to simplify path
 	workingDir, err := os.Getwd()
 	if err != nil {
@@ -136,6 +267,9 @@ func tableBuilderInner(vulnResult *models.VulnerabilityResults, addStyling bool,
 				}
 
 				outputRow = append(outputRow, source.Path)
+				if showOrigins {
+					outputRow = append(outputRow, imageOriginString(pkg.ImageOrigin))
+				}
 				allOutputRows = append(allOutputRows, tbInnerResponse{
 					row:         outputRow,
 					shouldMerge: shouldMerge,
@@ -0,0 +1,79 @@
+package remediation
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"deps.dev/util/resolve"
+	"github.com/google/osv-scanner/internal/resolution"
+	"github.com/google/osv-scanner/internal/resolution/client"
+	"github.com/google/osv-scanner/internal/resolution/util"
+	"github.com/google/osv-scanner/internal/utility/vulns"
+)
+
+// abandonedAge is how long ago a package's latest version has to have been published for it to be considered abandoned.
+const abandonedAge = 2 * 365 * 24 * time.Hour
+
+// AlternativeSuggestion is guidance to replace a package with vulnerabilities that will never be fixed.
+type AlternativeSuggestion struct {
+	Pkg           resolve.PackageKey
+	LastPublished time.Time // When the latest version of the package was published
+	Deprecation   string    // Why the latest version was deprecated, which often names a replacement, empty if it is not deprecated
+	Vulns         []resolution.ResolutionVuln
+	Explanation   string // Human-readable replacement guidance
+}
+
+// suggestAlternative checks if none of the package's versions fix the vulnerabilities
+// and the package appears to be abandoned, suggesting that it be replaced.
+// A package is only considered abandoned if the client knows when its latest version was published,
+// and the replacement guidance is taken from the registry's deprecation of the latest version, if any.
+func suggestAlternative(ctx context.Context, cl client.DependencyClient, pk resolve.PackageKey, vulnList []resolution.ResolutionVuln) (AlternativeSuggestion, bool) {
+	ptc, ok := client.As[client.PublishTimeClient](cl)
+	if !ok {
+		return AlternativeSuggestion{}, false
+	}
+
+	// Only suggest alternatives if no version of the package fixes the vulnerabilities
+	_, err := findFixedVersion(ctx, cl, pk, true, func(vk resolve.VersionKey) bool {
+		for _, v := range vulnList {
+			if vulns.IsAffected(v.Vulnerability, util.VKToPackageDetails(vk)) {
+				return false
+			}
+		}
+
+		return true
+	})
+	if err == nil {
+		return AlternativeSuggestion{}, false
+	}
+
+	latest, err := latestVersion(ctx, cl, pk)
+	if err != nil {
+		return AlternativeSuggestion{}, false
+	}
+	published, err := ptc.PublishTime(ctx, latest)
+	if err != nil || published.IsZero() || time.Since(published) < abandonedAge {
+		return AlternativeSuggestion{}, false
+	}
+
+	sugg := AlternativeSuggestion{
+		Pkg:           pk,
+		LastPublished: published,
+		Vulns:         vulnList,
+	}
+	sugg.Explanation = fmt.Sprintf("%s has no fixed version and was last published on %s, consider replacing it",
+		pk.Name, published.Format(time.DateOnly))
+	if dc, ok := client.As[client.DeprecationClient](cl); ok {
+		if reason, err := dc.Deprecation(ctx, latest); err == nil && reason != "" {
+			sugg.Deprecation = reason
+			sugg.Explanation += fmt.Sprintf(" (deprecated: %s)", reason)
+		}
+	}
+
+	return sugg, true
+}
+
+func latestVersion(ctx context.Context, cl client.DependencyClient, pk resolve.PackageKey) (resolve.VersionKey, error) {
+	return findFixedVersion(ctx, cl, pk, false, func(resolve.VersionKey) bool { return true })
+}
@@ -0,0 +1,317 @@
+// Package baseimage suggests newer versions of a container's base image that remove vulnerabilities
+// in the OS packages inherited from the base image.
+package baseimage
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/google/osv-scanner/internal/resolution"
+	"github.com/google/osv-scanner/pkg/lockfile"
+	"github.com/google/osv-scanner/pkg/osv"
+)
+
+// ImageRef is a reference to a container image e.g. "docker.io/library/debian:12.4-slim"
+type ImageRef struct {
+	Repository string
+	Tag        string
+	Digest     string
+}
+
+// ParseImageRef parses an image reference in the form "repository[:tag][@digest]".
+// The tag defaults to "latest" if neither a tag nor a digest is specified.
+func ParseImageRef(ref string) ImageRef {
+	var img ImageRef
+	ref, img.Digest, _ = strings.Cut(ref, "@")
+	// The tag is after the last colon, as long as it isn't part of a registry host:port
+	if i := strings.LastIndex(ref, ":"); i >= 0 && !strings.Contains(ref[i:], "/") {
+		img.Repository, img.Tag = ref[:i], ref[i+1:]
+	} else {
+		img.Repository = ref
+	}
+	if img.Tag == "" && img.Digest == "" {
+		img.Tag = "latest"
+	}
+
+	return img
+}
+
+func (img ImageRef) String() string {
+	s := img.Repository
+	if img.Tag != "" {
+		s += ":" + img.Tag
+	}
+	if img.Digest != "" {
+		s += "@" + img.Digest
+	}
+
+	return s
+}
+
+// ErrScratchBase is returned by ParseDockerfileBase if the final stage is built FROM scratch, which has no base image to change.
+var ErrScratchBase = errors.New("the final stage of the Dockerfile is built FROM scratch")
+
+// ParseDockerfileBase finds the base image of the final stage of a Dockerfile.
+// Build arguments in the image reference are substituted with their values in buildArgs,
+// or the default values of the ARG instructions before the first FROM.
+func ParseDockerfileBase(r io.Reader, buildArgs map[string]string) (ImageRef, error) {
+	args := make(map[string]string)     // build arguments that can be used in FROM instructions
+	stages := make(map[string]ImageRef) // named build stages, which can be used as the base of later stages
+	var base ImageRef
+	found := false
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		if !found && strings.EqualFold(fields[0], "ARG") {
+			// Only the ARGs declared before the first FROM can be used in FROM instructions
+			for _, arg := range fields[1:] {
+				name, def, _ := strings.Cut(arg, "=")
+				if v, ok := buildArgs[name]; ok {
+					args[name] = v
+				} else {
+					args[name] = strings.Trim(def, `"'`)
+				}
+			}
+
+			continue
+		}
+		if !strings.EqualFold(fields[0], "FROM") {
+			continue
+		}
+		fields = slices.DeleteFunc(fields[1:], func(f string) bool { return strings.HasPrefix(f, "--") }) // e.g. --platform=
+		if len(fields) == 0 {
+			continue
+		}
+		ref := os.Expand(fields[0], func(name string) string {
+			// e.g. ${VERSION:-12}, which uses the default if the argument is empty
+			name, def, _ := strings.Cut(name, ":-")
+			if v := args[name]; v != "" {
+				return v
+			}
+
+			return def
+		})
+		if ref == "" || strings.HasPrefix(ref, ":") || strings.HasPrefix(ref, "@") {
+			return ImageRef{}, fmt.Errorf("cannot determine the image of %q, pass a value for its build arguments", fields[0])
+		}
+		img, ok := stages[ref]
+		if !ok {
+			img = ParseImageRef(ref)
+		}
+		if len(fields) >= 3 && strings.EqualFold(fields[1], "AS") {
+			stages[fields[2]] = img
+		}
+		base = img
+		found = true
+	}
+	if err := scanner.Err(); err != nil {
+		return ImageRef{}, err
+	}
+	if !found {
+		return ImageRef{}, errors.New("no FROM instruction found in Dockerfile")
+	}
+	if base.Repository == "scratch" {
+		return ImageRef{}, ErrScratchBase
+	}
+
+	return base, nil
+}
+
+// ImageClient gets information about container images.
+type ImageClient interface {
+	// Tags returns the tags of an image repository.
+	Tags(ctx context.Context, repository string) ([]string, error)
+	// Packages returns the OS packages installed in an image.
+	Packages(ctx context.Context, img ImageRef) ([]lockfile.PackageDetails, error)
+}
+
+// Candidate is a possible replacement for the base image.
+type Candidate struct {
+	Image      ImageRef
+	Eliminated []string // IDs of the vulnerabilities in the current base image that are not in this image
+	Remaining  []string // IDs of the vulnerabilities in the current base image that are also in this image
+	Introduced []string // IDs of the vulnerabilities in this image that are not in the current base image
+}
+
+// FromLine is the Dockerfile FROM instruction to use the candidate image.
+func (c Candidate) FromLine() string {
+	return "FROM " + c.Image.String()
+}
+
+// maxCandidates limits how many images are pulled and scanned, since each can be fairly large.
+const maxCandidates = 5
+
+// SuggestResult is the outcome of SuggestCandidates.
+type SuggestResult struct {
+	Candidates  []Candidate
+	Diagnostics []resolution.Diagnostic // Images that could not be checked
+}
+
+// SuggestCandidates finds newer tags of the base image and computes which of its vulnerabilities each would eliminate.
+// The candidates are sorted from best to worst, and only include images that eliminate at least one vulnerability.
+// Candidate images that cannot be checked are skipped, with a diagnostic explaining why.
+func SuggestCandidates(ctx context.Context, cl ImageClient, base ImageRef) (SuggestResult, error) {
+	var result SuggestResult
+	baseVulns, err := imageVulns(ctx, cl, base)
+	if err != nil {
+		return result, err
+	}
+	if len(baseVulns) == 0 {
+		return result, nil
+	}
+
+	tags, err := cl.Tags(ctx, base.Repository)
+	if err != nil {
+		return result, err
+	}
+
+	var candidates []Candidate
+	for _, tag := range candidateTags(base.Tag, tags) {
+		img := ImageRef{Repository: base.Repository, Tag: tag}
+		vulns, err := imageVulns(ctx, cl, img)
+		if err != nil {
+			result.Diagnostics = append(result.Diagnostics, resolution.Diagnostic{
+				Kind:    resolution.DiagnosticSkippedPackage,
+				Package: img.Repository,
+				Version: img.Tag,
+				Message: fmt.Sprintf("could not check the vulnerabilities of the image: %v", err),
+			})
+
+			continue
+		}
+		c := Candidate{Image: img}
+		for id := range baseVulns {
+			if _, ok := vulns[id]; ok {
+				c.Remaining = append(c.Remaining, id)
+			} else {
+				c.Eliminated = append(c.Eliminated, id)
+			}
+		}
+		for id := range vulns {
+			if _, ok := baseVulns[id]; !ok {
+				c.Introduced = append(c.Introduced, id)
+			}
+		}
+		if len(c.Eliminated) == 0 {
+			continue
+		}
+		slices.Sort(c.Eliminated)
+		slices.Sort(c.Remaining)
+		slices.Sort(c.Introduced)
+		candidates = append(candidates, c)
+	}
+
+	slices.SortFunc(candidates, func(a, b Candidate) int {
+		// Fewest vulnerabilities after the change
+		if c := cmp.Compare(len(a.Remaining)+len(a.Introduced), len(b.Remaining)+len(b.Introduced)); c != 0 {
+			return c
+		}
+		// Most vulnerabilities eliminated
+		if c := cmp.Compare(len(a.Eliminated), len(b.Eliminated)); c != 0 {
+			return -c
+		}
+
+		return cmp.Compare(a.Image.Tag, b.Image.Tag)
+	})
+	result.Candidates = candidates
+
+	return result, nil
+}
+
+// candidateTags picks the tags that are likely newer versions of the same image variant as the current tag,
+// e.g. for "12.1-slim": "12.1-slim" (which may have been rebuilt since), "12.4-slim", "13.0-slim".
+// Tags without a version (e.g. "bookworm") are only candidates for themselves,
+// except for "latest" which can be replaced by any plain version tag e.g. "12.4".
+func candidateTags(current string, tags []string) []string {
+	curVer, curVariant := splitTag(current)
+	var candidates []string
+	for _, t := range tags {
+		ver, variant := splitTag(t)
+		switch {
+		case t == current:
+			// the same tag may have been rebuilt with updated packages
+		case ver == "":
+			continue
+		case current == "latest":
+			if variant != "" {
+				continue
+			}
+		case curVer == "" || variant != curVariant || compareTagVersions(ver, curVer) < 0:
+			continue
+		}
+		candidates = append(candidates, t)
+	}
+	// prefer the newest versions
+	slices.SortFunc(candidates, func(a, b string) int {
+		aVer, _ := splitTag(a)
+		bVer, _ := splitTag(b)
+
+		return -compareTagVersions(aVer, bVer)
+	})
+	if len(candidates) > maxCandidates {
+		candidates = candidates[:maxCandidates]
+	}
+
+	return candidates
+}
+
+// splitTag splits a tag into its numeric version prefix and variant suffix e.g. "12.4-slim" -> "12.4", "-slim"
+func splitTag(tag string) (string, string) {
+	i := strings.IndexFunc(tag, func(r rune) bool { return (r < '0' || r > '9') && r != '.' })
+	if i < 0 {
+		return tag, ""
+	}
+
+	return tag[:i], tag[i:]
+}
+
+func compareTagVersions(a, b string) int {
+	aParts := strings.Split(a, ".")
+	bParts := strings.Split(b, ".")
+	for i := 0; i < len(aParts) && i < len(bParts); i++ {
+		aNum, _ := strconv.Atoi(aParts[i]) // non-numbers are treated as 0
+		bNum, _ := strconv.Atoi(bParts[i])
+		if c := cmp.Compare(aNum, bNum); c != 0 {
+			return c
+		}
+	}
+
+	return cmp.Compare(len(aParts), len(bParts))
+}
+
+// imageVulns finds the IDs of the vulnerabilities affecting the OS packages in the image.
+func imageVulns(ctx context.Context, cl ImageClient, img ImageRef) (map[string]struct{}, error) {
+	pkgs, err := cl.Packages(ctx, img)
+	if err != nil {
+		return nil, err
+	}
+
+	var query osv.BatchedQuery
+	for _, pkg := range pkgs {
+		query.Queries = append(query.Queries, osv.MakePkgRequest(pkg))
+	}
+	resp, err := osv.MakeRequest(query)
+	if err != nil {
+		return nil, err
+	}
+
+	vulns := make(map[string]struct{})
+	for _, res := range resp.Results {
+		for _, v := range res.Vulns {
+			vulns[v.ID] = struct{}{}
+		}
+	}
+
+	return vulns, nil
+}
@@ -0,0 +1,128 @@
+package baseimage
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseDockerfileBase(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		dockerfile string
+		buildArgs  map[string]string
+		want       ImageRef
+		wantErr    error
+	}{
+		{
+			name:       "single stage",
+			dockerfile: "FROM debian:12.4-slim\nRUN apt-get update\n",
+			want:       ImageRef{Repository: "debian", Tag: "12.4-slim"},
+		},
+		{
+			name:       "default tag",
+			dockerfile: "FROM --platform=linux/amd64 debian\n",
+			want:       ImageRef{Repository: "debian", Tag: "latest"},
+		},
+		{
+			name:       "multi-stage",
+			dockerfile: "FROM golang:1.22 AS build\nRUN go build\nFROM build AS test\nFROM debian:12-slim\nCOPY --from=build /app /app\n",
+			want:       ImageRef{Repository: "debian", Tag: "12-slim"},
+		},
+		{
+			name:       "final stage from a named stage",
+			dockerfile: "FROM debian:12 AS base\nRUN apt-get update\nFROM base\n",
+			want:       ImageRef{Repository: "debian", Tag: "12"},
+		},
+		{
+			name:       "build argument default",
+			dockerfile: "ARG DEBIAN_VERSION=12.4\nFROM debian:${DEBIAN_VERSION}-slim\n",
+			want:       ImageRef{Repository: "debian", Tag: "12.4-slim"},
+		},
+		{
+			name:       "build argument value",
+			dockerfile: "ARG DEBIAN_VERSION=12.4\nFROM debian:$DEBIAN_VERSION\n",
+			buildArgs:  map[string]string{"DEBIAN_VERSION": "11"},
+			want:       ImageRef{Repository: "debian", Tag: "11"},
+		},
+		{
+			name:       "build argument inline default",
+			dockerfile: "ARG DEBIAN_VERSION\nFROM debian:${DEBIAN_VERSION:-12}\n",
+			want:       ImageRef{Repository: "debian", Tag: "12"},
+		},
+		{
+			name:       "build argument without value",
+			dockerfile: "ARG IMAGE\nFROM ${IMAGE}\n",
+			wantErr:    errAny,
+		},
+		{
+			name:       "scratch",
+			dockerfile: "FROM golang:1.22 AS build\nFROM scratch\nCOPY --from=build /app /app\n",
+			wantErr:    ErrScratchBase,
+		},
+		{
+			name:       "no FROM",
+			dockerfile: "RUN echo hello\n",
+			wantErr:    errAny,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseDockerfileBase(strings.NewReader(tt.dockerfile), tt.buildArgs)
+			switch {
+			case tt.wantErr == nil && err != nil:
+				t.Fatalf("ParseDockerfileBase() error = %v", err)
+			case tt.wantErr == errAny && err == nil, tt.wantErr != nil && tt.wantErr != errAny && !errors.Is(err, tt.wantErr):
+				t.Fatalf("ParseDockerfileBase() error = %v, want %v", err, tt.wantErr)
+			case tt.wantErr == nil && got != tt.want:
+				t.Errorf("ParseDockerfileBase() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// errAny is used in tests expecting any error.
+var errAny = errors.New("any error")
+
+func TestCandidateTags(t *testing.T) {
+	t.Parallel()
+
+	tags := []string{"latest", "bookworm", "bookworm-slim", "11", "11-slim", "12", "12.1-slim", "12.4", "12.4-slim", "13-slim"}
+	tests := []struct {
+		current string
+		want    []string
+	}{
+		{
+			current: "12.1-slim",
+			want:    []string{"13-slim", "12.4-slim", "12.1-slim"},
+		},
+		{
+			current: "12",
+			want:    []string{"12.4", "12"},
+		},
+		{
+			current: "latest",
+			want:    []string{"12.4", "12", "11", "latest"},
+		},
+		{
+			current: "bookworm",
+			want:    []string{"bookworm"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.current, func(t *testing.T) {
+			t.Parallel()
+
+			if got := candidateTags(tt.current, tags); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("candidateTags() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
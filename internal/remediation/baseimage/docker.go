@@ -0,0 +1,111 @@
+package baseimage
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os/exec"
+	"strings"
+
+	"github.com/google/osv-scanner/pkg/lockfile"
+	"github.com/google/osv-scanner/pkg/osv"
+)
+
+const dockerHubTagsURL = "https://hub.docker.com/v2/repositories/%s/tags?page_size=100"
+
+// ErrImageRunNotAllowed is returned by DockerClient.Packages if running images has not been allowed.
+var ErrImageRunNotAllowed = errors.New("listing the packages of an image requires running it with docker, which has not been allowed")
+
+// DockerClient lists image tags from Docker Hub, and runs images with the local docker CLI to find their packages.
+// Candidate images are arbitrary images from the registry, so they are only run if AllowRun is set.
+// TODO: registries other than Docker Hub, non-Debian images
+type DockerClient struct {
+	AllowRun bool
+}
+
+type dockerHubTagsResponse struct {
+	Next    string `json:"next"`
+	Results []struct {
+		Name string `json:"name"`
+	} `json:"results"`
+}
+
+// maxTagPages limits how many pages of tags are fetched, popular images can have thousands of tags.
+const maxTagPages = 10
+
+func (c DockerClient) Tags(ctx context.Context, repository string) ([]string, error) {
+	repo := strings.TrimPrefix(repository, "docker.io/")
+	if strings.Contains(repo, ".") || strings.Contains(repo, ":") {
+		return nil, fmt.Errorf("unsupported image registry: %s", repository)
+	}
+	if !strings.Contains(repo, "/") {
+		// official images are in the "library" namespace
+		repo = "library/" + repo
+	}
+
+	var tags []string
+	url := fmt.Sprintf(dockerHubTagsURL, repo)
+	for page := 0; url != "" && page < maxTagPages; page++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+		if osv.RequestUserAgent != "" {
+			req.Header.Set("User-Agent", osv.RequestUserAgent)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		var tagsResp dockerHubTagsResponse
+		err = json.NewDecoder(resp.Body).Decode(&tagsResp)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, errors.New(resp.Status)
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range tagsResp.Results {
+			tags = append(tags, r.Name)
+		}
+		url = tagsResp.Next
+	}
+
+	return tags, nil
+}
+
+func (c DockerClient) Packages(ctx context.Context, img ImageRef) ([]lockfile.PackageDetails, error) {
+	if !c.AllowRun {
+		return nil, ErrImageRunNotAllowed
+	}
+	cmd := exec.CommandContext(ctx, "docker", "run", "--rm", "--entrypoint", "/usr/bin/dpkg-query", img.String(), "-f", "${Package}###${Version}\\n", "-W")
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list packages in %s: %w", img, err)
+	}
+
+	var pkgs []lockfile.PackageDetails
+	scanner := bufio.NewScanner(strings.NewReader(string(out)))
+	for scanner.Scan() {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		name, version, ok := strings.Cut(text, "###")
+		if !ok {
+			return nil, fmt.Errorf("unexpected output from %s: %s", img, text)
+		}
+		pkgs = append(pkgs, lockfile.PackageDetails{
+			Name:      name,
+			Version:   version,
+			Ecosystem: lockfile.DebianEcosystem,
+			CompareAs: lockfile.DebianEcosystem,
+		})
+	}
+
+	return pkgs, scanner.Err()
+}
@@ -0,0 +1,484 @@
+package remediation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"deps.dev/util/resolve"
+	"github.com/google/osv-scanner/internal/resolution"
+	"github.com/google/osv-scanner/internal/resolution/client"
+	lf "github.com/google/osv-scanner/internal/resolution/lockfile"
+	"github.com/google/osv-scanner/internal/resolution/manifest"
+	"github.com/google/osv-scanner/internal/resolution/util"
+	"github.com/google/osv-scanner/pkg/lockfile"
+)
+
+// BatchProject is a project found under a monorepo root, with a manifest and/or lockfile to remediate.
+type BatchProject struct {
+	Dir      string `json:"dir"`
+	Manifest string `json:"manifest,omitempty"`
+	Lockfile string `json:"lockfile,omitempty"`
+}
+
+// projectKey identifies a project found by DiscoverProjects.
+type projectKey struct {
+	dir    string
+	system resolve.System
+	file   string // set for manifests that share a directory and ecosystem with another manifest
+}
+
+// DiscoverProjects finds every supported manifest and lockfile under root, grouped into projects.
+// A directory can have a project for each ecosystem, and a manifest is paired with the lockfile of its ecosystem.
+// Installed dependency directories (e.g. node_modules) are not searched.
+func DiscoverProjects(root string) ([]BatchProject, error) {
+	projects := make(map[projectKey]*BatchProject)
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != root && (d.Name() == "node_modules" || d.Name() == ".git") {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		_, manifErr := manifest.GetManifestIO(path)
+		_, lockErr := lf.GetLockfileIO(path)
+		if manifErr != nil && lockErr != nil {
+			return nil
+		}
+
+		dir := filepath.Dir(path)
+		key := projectKey{dir: dir, system: projectSystem(path)}
+		if p, ok := projects[key]; ok && manifErr == nil && p.Manifest != "" {
+			// e.g. both requirements.txt and pyproject.toml, which are remediated separately
+			key.file = path
+		}
+		p, ok := projects[key]
+		if !ok {
+			p = &BatchProject{Dir: dir}
+			projects[key] = p
+		}
+		switch {
+		case manifErr == nil:
+			p.Manifest = path
+		case filepath.Base(p.Lockfile) == "npm-shrinkwrap.json":
+			// npm uses npm-shrinkwrap.json instead of package-lock.json if both exist
+		default:
+			p.Lockfile = path
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]BatchProject, 0, len(projects))
+	for key, p := range projects {
+		// Manifests that are npm workspaces of another project are remediated as part of that project
+		if key.system == resolve.NPM && p.Lockfile == "" && isWorkspaceOf(p.Dir, projects) {
+			continue
+		}
+		result = append(result, *p)
+	}
+	slices.SortFunc(result, func(a, b BatchProject) int { return strings.Compare(a.file(), b.file()) })
+
+	return result, nil
+}
+
+// projectSystem returns the package system of a supported manifest or lockfile.
+func projectSystem(path string) resolve.System {
+	base := filepath.Base(path)
+	switch {
+	case base == "pom.xml":
+		return resolve.Maven
+	case base == "requirements.txt" || base == "pyproject.toml":
+		return util.PyPI
+	case base == "go.mod":
+		return util.Go
+	case base == "Cargo.toml" || base == "Cargo.lock":
+		return util.Cargo
+	case manifest.IsNuGetProjectFile(base) || base == "packages.lock.json":
+		return util.NuGet
+	default:
+		return resolve.NPM
+	}
+}
+
+// file returns the path of the project's manifest, or its lockfile if it has no manifest.
+// No two projects share a file, so it uniquely identifies the project.
+func (p BatchProject) file() string {
+	if p.Manifest != "" {
+		return p.Manifest
+	}
+
+	return p.Lockfile
+}
+
+// isWorkspaceOf checks if the npm project in dir is a workspace of another npm project with a shared lockfile,
+// i.e. if it is matched by the workspaces of the package.json of a project in a parent directory.
+func isWorkspaceOf(dir string, projects map[projectKey]*BatchProject) bool {
+	for child, parent := dir, filepath.Dir(dir); parent != child; child, parent = parent, filepath.Dir(parent) {
+		p, ok := projects[projectKey{dir: parent, system: resolve.NPM}]
+		if !ok || p.Lockfile == "" || p.Manifest == "" {
+			continue
+		}
+		var included, excluded bool
+		for _, pattern := range npmWorkspaces(p.Manifest) {
+			pattern, exclude := strings.CutPrefix(pattern, "!")
+			if matched, err := filepath.Match(filepath.Join(parent, pattern), dir); err == nil && matched {
+				excluded = excluded || exclude
+				included = included || !exclude
+			}
+		}
+		if included && !excluded {
+			return true
+		}
+	}
+
+	return false
+}
+
+// npmWorkspaces returns the workspace patterns of a package.json, or nil if it cannot be read.
+func npmWorkspaces(path string) []string {
+	f, err := lockfile.OpenLocalDepFile(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	var packagejson manifest.PackageJSON
+	if err := json.NewDecoder(f).Decode(&packagejson); err != nil {
+		return nil
+	}
+	patterns, err := manifest.NpmWorkspacePatterns(f, packagejson)
+	if err != nil {
+		return nil
+	}
+
+	return patterns
+}
+
+// BatchPatch is a single dependency version change in a batch remediation plan.
+type BatchPatch struct {
+	Package     string   `json:"package"`
+	OrigVersion string   `json:"origVersion"`
+	NewVersion  string   `json:"newVersion"`
+	File        string   `json:"file"` // The manifest or lockfile to change
+	FixedVulns  []string `json:"fixedVulns"`
+	Prerelease  bool     `json:"prerelease,omitempty"` // Whether NewVersion is a prerelease version
+}
+
+// BatchUnmanageable is a vulnerability in a dependency installed from outside the package registry, which cannot be patched.
+type BatchUnmanageable struct {
+	ID      string `json:"id"`
+	Package string `json:"package"`
+	Version string `json:"version"`
+	Source  string `json:"source"` // The URL or path the dependency was installed from
+}
+
+// BatchProjectPlan is the remediation plan of a single project.
+type BatchProjectPlan struct {
+	BatchProject
+	Patches      []BatchPatch            `json:"patches"`
+	Unfixable    []string                `json:"unfixable"`
+	Suggestions  []string                `json:"suggestions,omitempty"` // Guidance for vulnerabilities that cannot be fixed
+	Unmanageable []BatchUnmanageable     `json:"unmanageable,omitempty"`
+	Diagnostics  []resolution.Diagnostic `json:"diagnostics,omitempty"` // Problems encountered that may have made the plan less accurate
+	Error        string                  `json:"error,omitempty"`
+
+	severities map[string]float64 // highest CVSS score of each vulnerability found in the project
+}
+
+// BatchAdvisory is a vulnerability found in one or more projects, deduplicated across the projects.
+type BatchAdvisory struct {
+	ID        string   `json:"id"`
+	Severity  float64  `json:"severity"`  // Highest CVSS score of the vulnerability, or -1 if unknown
+	Projects  []string `json:"projects"`  // Directories of the projects affected by the vulnerability
+	Unfixable []string `json:"unfixable"` // Directories of the projects where the vulnerability cannot be fixed
+}
+
+// BatchPlan is a consolidated remediation plan across multiple projects.
+type BatchPlan struct {
+	Projects   []BatchProjectPlan `json:"projects"`
+	Advisories []BatchAdvisory    `json:"advisories"`
+}
+
+// ComputeBatchPlan runs remediation on each of the projects and consolidates the results.
+// Projects with a lockfile are patched in-place, otherwise their manifest is relaxed.
+// Errors remediating a project are recorded in its plan, rather than aborting the whole batch.
+func ComputeBatchPlan(ctx context.Context, cl client.ResolutionClient, projects []BatchProject, opts RemediationOptions) BatchPlan {
+	var plan BatchPlan
+	advisories := make(map[string]*BatchAdvisory)
+	addAdvisory := func(id, dir string, severity float64, fixable bool) {
+		adv, ok := advisories[id]
+		if !ok {
+			adv = &BatchAdvisory{ID: id, Severity: severity}
+			advisories[id] = adv
+		}
+		if !slices.Contains(adv.Projects, dir) {
+			adv.Projects = append(adv.Projects, dir)
+		}
+		if !fixable && !slices.Contains(adv.Unfixable, dir) {
+			adv.Unfixable = append(adv.Unfixable, dir)
+		}
+	}
+
+	for _, p := range projects {
+		projPlan, err := computeProjectPlan(ctx, cl, p, opts)
+		if err != nil {
+			projPlan.Error = err.Error()
+		}
+		for _, patch := range projPlan.Patches {
+			for _, id := range patch.FixedVulns {
+				addAdvisory(id, p.Dir, projPlan.severity(id), true)
+			}
+		}
+		for _, id := range projPlan.Unfixable {
+			addAdvisory(id, p.Dir, projPlan.severity(id), false)
+		}
+		for _, u := range projPlan.Unmanageable {
+			addAdvisory(u.ID, p.Dir, projPlan.severity(u.ID), false)
+		}
+		plan.Projects = append(plan.Projects, projPlan)
+	}
+
+	for _, adv := range advisories {
+		slices.Sort(adv.Projects)
+		slices.Sort(adv.Unfixable)
+		plan.Advisories = append(plan.Advisories, *adv)
+	}
+	slices.SortFunc(plan.Advisories, func(a, b BatchAdvisory) int { return strings.Compare(a.ID, b.ID) })
+
+	return plan
+}
+
+func computeProjectPlan(ctx context.Context, cl client.ResolutionClient, p BatchProject, opts RemediationOptions) (BatchProjectPlan, error) {
+	plan := BatchProjectPlan{
+		BatchProject: p,
+		Patches:      []BatchPatch{},
+		Unfixable:    []string{},
+		severities:   make(map[string]float64),
+	}
+
+	if !opts.IgnoreEngines {
+		// Each project can have its own runtime engine requirements
+		engines, diag, ok := ProjectEngines(p.Dir)
+		opts.Engines = engines
+		if !ok {
+			plan.Diagnostics = append(plan.Diagnostics, diag)
+		}
+	}
+
+	if p.Lockfile != "" {
+		rw, err := lf.GetLockfileIO(p.Lockfile)
+		if err != nil {
+			return plan, err
+		}
+		if opts.VerifyIntegrity {
+			diags, err := CheckLockfileIntegrity(ctx, cl.DependencyClient, rw, p.Lockfile)
+			plan.Diagnostics = append(plan.Diagnostics, diags...)
+			if err != nil {
+				return plan, err
+			}
+		}
+		f, err := lockfile.OpenLocalDepFile(p.Lockfile)
+		if err != nil {
+			return plan, err
+		}
+		g, err := rw.Read(f)
+		f.Close()
+		if err != nil {
+			return plan, err
+		}
+		res, err := ComputeInPlacePatches(ctx, cl, g, opts)
+		if err != nil {
+			return plan, err
+		}
+		plan.Diagnostics = append(plan.Diagnostics, res.Diagnostics...)
+		for _, patch := range res.Patches {
+			plan.addSeverities(patch.ResolvedVulns)
+		}
+		// Patches are sorted by priority,
+		// so only use the first patch for each vulnerable package version.
+		seen := make(map[resolve.PackageKey][]string)
+		for _, patch := range res.Patches {
+			if slices.Contains(seen[patch.Pkg], patch.OrigVersion) {
+				continue
+			}
+			seen[patch.Pkg] = append(seen[patch.Pkg], patch.OrigVersion)
+			plan.Patches = append(plan.Patches, BatchPatch{
+				Package:     patch.Pkg.Name,
+				OrigVersion: patch.OrigVersion,
+				NewVersion:  patch.NewVersion,
+				File:        p.Lockfile,
+				FixedVulns:  resolutionVulnIDs(patch.ResolvedVulns),
+				Prerelease:  patch.Prerelease,
+			})
+		}
+		unfixable := res.Unfixable
+		for _, b := range res.Bundled {
+			unfixable = append(unfixable, b.Vuln)
+			plan.Suggestions = append(plan.Suggestions, fmt.Sprintf("%s@%s is bundled in %s@%s, upgrade %s to fix %s",
+				b.Pkg.Name, b.Version, b.Parent.Name, b.Parent.Version, b.Parent.Name, b.Vuln.Vulnerability.ID))
+		}
+		plan.addSeverities(unfixable)
+		plan.Unfixable = resolutionVulnIDs(unfixable)
+		for _, u := range res.Unmanageable {
+			plan.addSeverities([]resolution.ResolutionVuln{u.Vuln})
+			plan.Unmanageable = append(plan.Unmanageable, BatchUnmanageable{
+				ID:      u.Vuln.Vulnerability.ID,
+				Package: u.Pkg.Name,
+				Version: u.Version,
+				Source:  u.Source,
+			})
+		}
+		for _, alt := range res.Alternatives {
+			plan.Suggestions = append(plan.Suggestions, alt.Explanation)
+		}
+
+		return plan, nil
+	}
+
+	rw, err := manifest.GetManifestIO(p.Manifest)
+	if err != nil {
+		return plan, err
+	}
+	f, err := lockfile.OpenLocalDepFile(p.Manifest)
+	if err != nil {
+		return plan, err
+	}
+	m, err := rw.Read(f)
+	f.Close()
+	if err != nil {
+		return plan, err
+	}
+	cl.PreFetch(ctx, m.Requirements, p.Manifest)
+	res, err := resolution.Resolve(ctx, cl, m)
+	if err != nil {
+		return plan, err
+	}
+	res.FilterVulns(opts.MatchVuln)
+	plan.Diagnostics = res.Diagnostics
+	plan.addSeverities(res.Vulns)
+
+	// Try to fix all the vulnerabilities at once, otherwise fall back to the best individual patch
+	var diff resolution.ResolutionDiff
+	newRes, err := tryRelaxRemediate(ctx, cl, res, resolutionVulnIDs(res.Vulns), opts)
+	switch {
+	case err == nil:
+		newRes.FilterVulns(opts.MatchVuln)
+		diff = res.CalculateDiff(newRes)
+	case errors.Is(err, errRelaxRemediateImpossible):
+		diffs, err := ComputeRelaxPatches(ctx, cl, res, opts)
+		if err != nil {
+			return plan, err
+		}
+		if len(diffs) == 0 {
+			plan.Unfixable = resolutionVulnIDs(res.Vulns)
+			return plan, nil
+		}
+		diff = diffs[0]
+	default:
+		return plan, err
+	}
+
+	fixed := resolutionVulnIDs(diff.RemovedVulns)
+	for _, d := range diff.Deps {
+		file := d.ManifestFile
+		if file == "" {
+			file = p.Manifest
+		}
+		plan.Patches = append(plan.Patches, BatchPatch{
+			Package:     d.Pkg.Name,
+			OrigVersion: d.OrigRequire,
+			NewVersion:  d.NewRequire,
+			File:        file,
+			FixedVulns:  fixed,
+		})
+	}
+	for _, v := range res.Vulns {
+		if !slices.Contains(fixed, v.Vulnerability.ID) {
+			plan.Unfixable = append(plan.Unfixable, v.Vulnerability.ID)
+		}
+	}
+
+	return plan, nil
+}
+
+func (p BatchProjectPlan) addSeverities(vulns []resolution.ResolutionVuln) {
+	for _, v := range vulns {
+		score, _ := maxSeverity(v)
+		p.severities[v.Vulnerability.ID] = score
+	}
+}
+
+func (p BatchProjectPlan) severity(id string) float64 {
+	if score, ok := p.severities[id]; ok {
+		return score
+	}
+
+	return -1
+}
+
+func resolutionVulnIDs(vulns []resolution.ResolutionVuln) []string {
+	ids := make([]string, 0, len(vulns))
+	for _, v := range vulns {
+		if !slices.Contains(ids, v.Vulnerability.ID) {
+			ids = append(ids, v.Vulnerability.ID)
+		}
+	}
+	slices.Sort(ids)
+
+	return ids
+}
+
+// WriteJSON writes the consolidated plan in JSON format.
+func (p BatchPlan) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(p)
+}
+
+// WriteProjectFiles writes each project's plan to a separate JSON file in outDir,
+// named after the path of the project's manifest (or lockfile) relative to root, with path separators escaped
+// e.g. "web%2Fapp%2Fpackage.json.patch.json".
+func (p BatchPlan) WriteProjectFiles(root, outDir string) error {
+	if err := os.MkdirAll(outDir, 0750); err != nil {
+		return err
+	}
+
+	for _, proj := range p.Projects {
+		rel, err := filepath.Rel(root, proj.file())
+		if err != nil {
+			return err
+		}
+		name := url.PathEscape(filepath.ToSlash(rel))
+		if err := writeJSONFile(filepath.Join(outDir, name+".patch.json"), proj); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func writeJSONFile(path string, v any) error {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, append(b, '\n'), 0600)
+}
@@ -0,0 +1,132 @@
+package remediation_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+
+	"deps.dev/util/resolve"
+	"github.com/google/osv-scanner/internal/remediation"
+	"github.com/google/osv-scanner/internal/resolution/client"
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+func TestDiscoverProjects(t *testing.T) {
+	t.Parallel()
+
+	root := filepath.FromSlash("fixtures/batch")
+	path := func(p string) string { return filepath.Join(root, filepath.FromSlash(p)) }
+
+	got, err := remediation.DiscoverProjects(root)
+	if err != nil {
+		t.Fatalf("DiscoverProjects() error = %v", err)
+	}
+
+	// packages/ws is a workspace of the root npm project, and node_modules is not searched
+	want := []remediation.BatchProject{
+		{Dir: path("a/b"), Manifest: path("a/b/package.json")},
+		{Dir: path("a_b"), Manifest: path("a_b/package.json")},
+		{Dir: root, Manifest: path("package.json"), Lockfile: path("package-lock.json")},
+		{Dir: path("py"), Manifest: path("py/pyproject.toml")},
+		{Dir: path("py"), Manifest: path("py/requirements.txt")},
+		{Dir: root, Manifest: path("requirements.txt")},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DiscoverProjects() = %v, want %v", got, want)
+	}
+}
+
+func TestBatchPlan_WriteProjectFiles(t *testing.T) {
+	t.Parallel()
+
+	root := filepath.FromSlash("fixtures/batch")
+	projects, err := remediation.DiscoverProjects(root)
+	if err != nil {
+		t.Fatalf("DiscoverProjects() error = %v", err)
+	}
+	var plan remediation.BatchPlan
+	for _, p := range projects {
+		plan.Projects = append(plan.Projects, remediation.BatchProjectPlan{BatchProject: p})
+	}
+
+	outDir := t.TempDir()
+	if err := plan.WriteProjectFiles(root, outDir); err != nil {
+		t.Fatalf("WriteProjectFiles() error = %v", err)
+	}
+
+	entries, err := os.ReadDir(outDir)
+	if err != nil {
+		t.Fatalf("failed to read output directory: %v", err)
+	}
+	var got []string
+	for _, e := range entries {
+		got = append(got, e.Name())
+	}
+	slices.Sort(got)
+
+	// Every project has its own file, even if their directories would be ambiguous once flattened
+	want := []string{
+		"a%2Fb%2Fpackage.json.patch.json",
+		"a_b%2Fpackage.json.patch.json",
+		"package.json.patch.json",
+		"py%2Fpyproject.toml.patch.json",
+		"py%2Frequirements.txt.patch.json",
+		"requirements.txt.patch.json",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("WriteProjectFiles() wrote %v, want %v", got, want)
+	}
+}
+
+func TestComputeBatchPlan_Workspaces(t *testing.T) {
+	t.Parallel()
+
+	dc := resolve.NewLocalClient()
+	for _, name := range []string{"foo", "bar"} {
+		dc.AddVersion(resolve.Version{VersionKey: npmVK(name, "1.0.0")}, nil)
+		dc.AddVersion(resolve.Version{VersionKey: npmVK(name, "1.1.0")}, nil)
+	}
+	vc := &fakeVulnClient{
+		vulns: map[resolve.VersionKey][]models.Vulnerability{
+			npmVK("foo", "1.0.0"): {npmVuln("GHSA-foo", "foo", "1.1.0")},
+			npmVK("bar", "1.0.0"): {npmVuln("GHSA-bar", "bar", "1.1.0")},
+		},
+		lookups: make(map[resolve.VersionKey]int),
+	}
+
+	// foo and bar are required by the pnpm workspaces, so their requirements are changed in the workspace manifests
+	root := filepath.FromSlash("fixtures/pnpm-workspaces")
+	project := remediation.BatchProject{Dir: root, Manifest: filepath.Join(root, "package.json")}
+	cl := client.ResolutionClient{DependencyClient: localDependencyClient{dc}, VulnerabilityClient: vc}
+	plan := remediation.ComputeBatchPlan(context.Background(), cl, []remediation.BatchProject{project}, remediation.RemediationOptions{IgnoreEngines: true})
+	if len(plan.Projects) != 1 || plan.Projects[0].Error != "" {
+		t.Fatalf("ComputeBatchPlan() projects = %+v, want one without an error", plan.Projects)
+	}
+
+	// the local manifests are read with absolute paths
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		t.Fatalf("failed to get absolute path of %s: %v", root, err)
+	}
+	got := make(map[string]string)
+	for _, p := range plan.Projects[0].Patches {
+		rel, err := filepath.Rel(absRoot, p.File)
+		if err != nil {
+			t.Fatalf("patch of %s changes %s, outside of %s", p.Package, p.File, absRoot)
+		}
+		got[p.Package] = filepath.ToSlash(rel)
+	}
+	want := map[string]string{
+		"foo": "packages/a/package.json",
+		"bar": "packages/b/package.json",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ComputeBatchPlan() patched files = %v, want %v", got, want)
+	}
+	if unfixable := plan.Projects[0].Unfixable; len(unfixable) != 0 {
+		t.Errorf("ComputeBatchPlan() unfixable = %v, want none", unfixable)
+	}
+}
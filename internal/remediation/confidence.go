@@ -0,0 +1,64 @@
+package remediation
+
+import (
+	"context"
+	"time"
+
+	"deps.dev/util/resolve"
+	"deps.dev/util/semver"
+	"github.com/google/osv-scanner/internal/resolution/client"
+	"github.com/google/osv-scanner/internal/resolution/util"
+)
+
+// recentPublishWindow is how recently a version has to be published for upgrading to it to be considered riskier,
+// as very new versions are more likely to contain undiscovered regressions.
+const recentPublishWindow = 14 * 24 * time.Hour
+
+// UpgradeConfidence estimates how likely a version change is to be a safe upgrade.
+type UpgradeConfidence struct {
+	Score      float64       // From 0 (likely to break things) to 1 (unlikely to break things)
+	Diff       semver.Diff   // The most significant semver component that changed
+	Age        time.Duration // Time since the new version was published, or 0 if unknown
+	KnownVulns int           // Number of known vulnerabilities affecting the new version
+}
+
+// computeConfidence scores the upgrade from vk to newVK, based on the semver distance,
+// how recently newVK was published, and whether newVK has known vulnerabilities.
+func computeConfidence(ctx context.Context, cl client.DependencyClient, vv *versionVulns, vk, newVK resolve.VersionKey) UpgradeConfidence {
+	conf := UpgradeConfidence{Score: 1}
+
+	_, diff, err := util.Semver(vk.System).Difference(vk.Version, newVK.Version)
+	if err != nil {
+		// Unparsable versions are treated as a major change
+		diff = semver.DiffMajor
+	}
+	conf.Diff = diff
+	switch diff {
+	case semver.DiffMajor:
+		conf.Score -= 0.5
+	case semver.DiffMinor:
+		conf.Score -= 0.2
+	case semver.DiffPatch:
+		conf.Score -= 0.05
+	default:
+		conf.Score -= 0.1 // prerelease/build changes
+	}
+
+	if ptc, ok := client.As[client.PublishTimeClient](cl); ok {
+		if published, err := ptc.PublishTime(ctx, newVK); err == nil && !published.IsZero() {
+			conf.Age = time.Since(published)
+			if conf.Age < recentPublishWindow {
+				conf.Score -= 0.2
+			}
+		}
+	}
+
+	if newVulns, err := vv.get(newVK); err == nil {
+		conf.KnownVulns = len(newVulns)
+		conf.Score -= 0.1 * float64(conf.KnownVulns)
+	}
+
+	conf.Score = max(conf.Score, 0)
+
+	return conf
+}
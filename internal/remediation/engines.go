@@ -0,0 +1,103 @@
+package remediation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strconv"
+
+	"deps.dev/util/resolve"
+	"deps.dev/util/semver"
+	"github.com/google/osv-scanner/internal/cachedregexp"
+	"github.com/google/osv-scanner/internal/resolution"
+	"github.com/google/osv-scanner/internal/resolution/client"
+	"github.com/google/osv-scanner/internal/resolution/manifest"
+)
+
+// ProjectEngines reads the runtime engine requirements of the project in dir from its package.json, if it has one.
+// If the package.json cannot be read, it returns false and a diagnostic explaining that engines will not be checked.
+func ProjectEngines(dir string) (map[string]string, resolution.Diagnostic, bool) {
+	path := filepath.Join(dir, "package.json")
+	engines, err := manifest.NpmEngines(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, resolution.Diagnostic{
+			Kind:    resolution.DiagnosticUnreadableManifest,
+			Message: fmt.Sprintf("could not read the engines of %s, new versions' engines will not be checked: %v", path, err),
+		}, false
+	}
+
+	return engines, resolution.Diagnostic{}, true
+}
+
+// enginesSatisfied checks if the runtime engine requirements of a new version are compatible with the project's.
+// Versions are always considered compatible if the client does not know the engine requirements.
+func enginesSatisfied(ctx context.Context, cl client.DependencyClient, vk resolve.VersionKey, opts RemediationOptions, diags *resolution.Diagnostics) bool {
+	if opts.IgnoreEngines || len(opts.Engines) == 0 {
+		return true
+	}
+
+	ec, ok := client.As[client.EnginesClient](cl)
+	if !ok {
+		return true
+	}
+
+	engines, err := ec.Engines(ctx, vk)
+	if err != nil {
+		diags.Add(resolution.DiagnosticRegistryError, vk, "could not get its engine requirements, assuming they are compatible: %v", err)
+		return true
+	}
+
+	return enginesCompatible(opts.Engines, engines)
+}
+
+// enginesCompatible checks if every runtime version supported by the project is also supported by the package.
+// Since the full set of runtime versions is not known, only the versions on the boundaries
+// of the project's requirement (e.g. 14.17.0 for "^14.17.0") are checked.
+// TODO: non-npm ecosystems
+func enginesCompatible(projEngines, pkgEngines map[string]string) bool {
+	for engine, projReq := range projEngines {
+		pkgReq, ok := pkgEngines[engine]
+		if !ok {
+			continue
+		}
+		projConstr, err := semver.NPM.ParseConstraint(projReq)
+		if err != nil {
+			continue
+		}
+		pkgConstr, err := semver.NPM.ParseConstraint(pkgReq)
+		if err != nil {
+			continue
+		}
+		for _, v := range boundaryVersions(projReq) {
+			if projConstr.Match(v) && !pkgConstr.Match(v) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// boundaryVersions makes a list of versions around each version mentioned in a requirement string,
+// e.g. ">=14.17" gives 14.17.0, 14.17.1, 14.18.0, 15.0.0
+func boundaryVersions(req string) []string {
+	re := cachedregexp.MustCompile(`(\d+)(?:\.(\d+))?(?:\.(\d+))?`)
+	var vers []string
+	for _, match := range re.FindAllStringSubmatch(req, -1) {
+		var parts [3]int
+		for i, p := range match[1:] {
+			parts[i], _ = strconv.Atoi(p) // missing parts are treated as 0
+		}
+		major, minor, patch := parts[0], parts[1], parts[2]
+		vers = append(vers,
+			fmt.Sprintf("%d.%d.%d", major, minor, patch),
+			fmt.Sprintf("%d.%d.%d", major, minor, patch+1),
+			fmt.Sprintf("%d.%d.0", major, minor+1),
+			fmt.Sprintf("%d.0.0", major+1),
+		)
+	}
+
+	return vers
+}
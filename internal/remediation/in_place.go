@@ -4,7 +4,9 @@ import (
 	"cmp"
 	"context"
 	"errors"
+	"fmt"
 	"slices"
+	"strings"
 
 	"deps.dev/util/resolve"
 	"deps.dev/util/resolve/dep"
@@ -12,28 +14,72 @@ import (
 	"github.com/google/osv-scanner/internal/resolution"
 	"github.com/google/osv-scanner/internal/resolution/client"
 	lf "github.com/google/osv-scanner/internal/resolution/lockfile"
+	"github.com/google/osv-scanner/internal/resolution/manifest"
 	"github.com/google/osv-scanner/internal/resolution/util"
 	"github.com/google/osv-scanner/internal/utility/vulns"
+	"github.com/google/osv-scanner/pkg/models"
 	"golang.org/x/exp/maps"
 )
 
 type InPlacePatch struct {
 	lf.DependencyPatch
 	ResolvedVulns []resolution.ResolutionVuln
+	Prerelease    bool    // Whether NewVersion is a prerelease version
+	EPSS          float64 // Highest EPSS exploit probability of the ResolvedVulns, if known
+	Confidence    UpgradeConfidence
+}
+
+// InPlacePartialMitigation is a version change that does not fix a vulnerability,
+// but reduces the number of vulnerabilities or the maximum severity of the vulnerabilities affecting the package.
+type InPlacePartialMitigation struct {
+	lf.DependencyPatch
+	UnfixedVulns []resolution.ResolutionVuln // The vulnerabilities that could not be fixed in-place
+	RemovedVulns []resolution.ResolutionVuln // The vulnerabilities that no longer apply to the new version
+	Explanation  string                      // Human-readable description of how the change mitigates the vulnerabilities
+}
+
+// InPlaceUnmanageable is a vulnerability in a dependency installed from outside the package registry
+// (e.g. a git repository, local path, or tarball URL), which cannot be changed by remediation.
+type InPlaceUnmanageable struct {
+	Pkg     resolve.PackageKey
+	Version string
+	Source  string // The URL or path the dependency was installed from
+	Vuln    resolution.ResolutionVuln
+}
+
+// InPlaceBundled is a vulnerability in a package bundled within another package (i.e. npm bundledDependencies),
+// which can only be fixed by changing the version of the package that bundles it.
+type InPlaceBundled struct {
+	Pkg     resolve.PackageKey
+	Version string
+	Parent  resolve.VersionKey // The package the vulnerable package is bundled in
+	Vuln    resolution.ResolutionVuln
 }
 
 type InPlaceResult struct {
-	Patches   []InPlacePatch
-	Unfixable []resolution.ResolutionVuln
+	Patches            []InPlacePatch
+	Unfixable          []resolution.ResolutionVuln
+	Unmanageable       []InPlaceUnmanageable
+	Bundled            []InPlaceBundled
+	PartialMitigations []InPlacePartialMitigation
+	Alternatives       []AlternativeSuggestion // Replacements for abandoned packages with Unfixable vulns
+	Diagnostics        []resolution.Diagnostic // Problems encountered that may have made the results less accurate
+	EPSS               map[string]float64      // EPSS exploit probabilities of the vulns fixed by the Patches, keyed by ID, if known
 }
 
 // ComputeInPlacePatches finds all possible targeting version changes that would fix vulnerabilities in a resolved graph.
 // TODO: Check for introduced vulnerabilities
 func ComputeInPlacePatches(ctx context.Context, cl client.ResolutionClient, graph *resolve.Graph, opts RemediationOptions) (InPlaceResult, error) {
-	res, err := inPlaceVulnsNodes(cl, graph)
+	// The same packages' versions & requirements are checked for many vulnerable nodes, so memoize them for this run
+	cl.DependencyClient = client.NewMemoClient(cl.DependencyClient)
+
+	res, err := inPlaceVulnsNodes(cl, graph, opts.AllChains)
 	if err != nil {
 		return InPlaceResult{}, err
 	}
+	var diags resolution.Diagnostics
+	// The vulnerabilities of candidate versions are needed by both partial mitigations and confidence scores
+	vv := newVersionVulns(cl)
 
 	// Compute the overall constraints imposed by the dependent packages on the vulnerable nodes
 	vkDependentConstraint := make(map[resolve.VersionKey]semver.Set)
@@ -42,57 +88,158 @@ func ComputeInPlacePatches(ctx context.Context, cl client.ResolutionClient, grap
 		for _, vuln := range vulns {
 			for _, c := range vuln.ProblemChains {
 				_, req := c.EndDependency()
-				reqVers[req] = struct{}{}
+				reqVers[resolveRequirementTag(ctx, cl.DependencyClient, vk.PackageKey, req)] = struct{}{}
 			}
 		}
-		set, err := buildConstraintSet(vk.Semver(), maps.Keys(reqVers))
+		set, err := buildConstraintSet(util.Semver(vk.System), maps.Keys(reqVers))
 		if err != nil {
-			// TODO: log error?
+			diags.Add(resolution.DiagnosticUnparseableConstraint, vk, "could not combine the requirements of its dependents, it will not be upgraded: %v", err)
 			continue
 		}
 		vkDependentConstraint[vk] = set
 	}
 
+	// Find the local packages (e.g. npm workspaces), which cannot be changed
+	localPkgs := make(map[resolve.PackageKey]struct{})
+	for _, e := range graph.Edges {
+		if s, ok := e.Type.GetAttr(dep.Scope); ok && s == lf.WorkspaceScope {
+			localPkgs[graph.Nodes[e.To].Version.PackageKey] = struct{}{}
+		}
+	}
+
+	// Find the packages installed from outside the registry, which cannot be changed
+	vkSources := make(map[resolve.VersionKey]string)
+	for _, e := range graph.Edges {
+		if manifest.IsNPMNonRegistrySpec(e.Requirement) {
+			vkSources[graph.Nodes[e.To].Version] = e.Requirement
+		}
+	}
+
+	// Find the packages bundled in other packages, which cannot be changed independently of the package bundling them
+	bundledIn := make(map[resolve.NodeID]resolve.NodeID)
+	for _, e := range graph.Edges {
+		if s, ok := e.Type.GetAttr(dep.Scope); ok && s == lf.BundleScope {
+			bundledIn[e.To] = e.From
+		}
+	}
+	bundleParent := func(nID resolve.NodeID) (resolve.VersionKey, bool) {
+		parent, ok := bundledIn[nID]
+		if !ok {
+			return resolve.VersionKey{}, false
+		}
+		// bundled packages can have their own bundled dependencies, find the outermost package.
+		// Malformed lockfiles can have cycles of bundled packages, so stop at any package already seen.
+		seen := map[resolve.NodeID]struct{}{nID: {}, parent: {}}
+		for p, ok := bundledIn[parent]; ok; p, ok = bundledIn[p] {
+			if _, ok := seen[p]; ok {
+				break
+			}
+			seen[p] = struct{}{}
+			parent = p
+		}
+
+		return graph.Nodes[parent].Version, true
+	}
+
 	var result InPlaceResult
+	unfixableVKs := make(map[resolve.VersionKey][]resolution.ResolutionVuln)
 	// TODO: This could be parallelized
 	for vk, vulnList := range res.vkVulns {
-		for _, vuln := range vulnList {
-			if !opts.MatchVuln(vuln) {
-				continue
+		_, isLocal := localPkgs[vk.PackageKey]
+		var installedNodes []resolve.NodeID    // nodes that can be patched
+		var bundleParents []resolve.VersionKey // packages that bundle this version
+		for _, nID := range res.vkNodes[vk] {
+			if parent, ok := bundleParent(nID); ok {
+				if !slices.Contains(bundleParents, parent) {
+					bundleParents = append(bundleParents, parent)
+				}
+			} else {
+				installedNodes = append(installedNodes, nID)
 			}
-			// Consider vulns affecting packages we don't want to change unfixable
-			if slices.Contains(opts.AvoidPkgs, vk.Name) {
-				result.Unfixable = append(result.Unfixable, vuln)
-				continue
+		}
+		canReplace := func(newVK resolve.VersionKey) bool {
+			// Check if the new version is allowed by the package's pinned version
+			if !opts.matchPin(newVK) {
+				return false
 			}
-			newVK, err := findFixedVersion(ctx, cl, vk.PackageKey, func(newVK resolve.VersionKey) bool {
-				// Check if this is a disallowed major version bump
-				if !opts.AllowMajor {
-					_, diff, err := vk.Semver().Difference(vk.Version, newVK.Version)
-					if err != nil || diff == semver.DiffMajor {
-						return false
-					}
-				}
-				// Check if dependent packages are still satisfied by new version
-				ok, err := vkDependentConstraint[vk].Match(newVK.Version)
-				if err != nil || !ok {
+			// Check if this is a disallowed major version bump
+			if !opts.AllowMajor {
+				_, diff, err := util.Semver(vk.System).Difference(vk.Version, newVK.Version)
+				if err != nil || diff == semver.DiffMajor {
 					return false
 				}
+			}
+			// Check if dependent packages are still satisfied by new version.
+			// Versions whose dependents' requirements could not be combined are not upgraded.
+			constraint, ok := vkDependentConstraint[vk]
+			if !ok {
+				return false
+			}
+			ok, err := constraint.Match(newVK.Version)
+			if err != nil || !ok {
+				return false
+			}
+
+			// Check if the new version supports the project's runtime engines
+			if !enginesSatisfied(ctx, cl.DependencyClient, newVK, opts, &diags) {
+				return false
+			}
 
-				// Check if new version's dependencies are satisfied by existing packages
-				for _, nID := range res.vkNodes[vk] {
-					ok, err := dependenciesSatisfied(ctx, cl, newVK, res.nodeDependencies[nID])
-					if err != nil || !ok {
-						return false
-					}
+			// Check if new version's dependencies are satisfied by existing packages
+			for _, nID := range installedNodes {
+				ok, err := dependenciesSatisfied(ctx, cl.DependencyClient, newVK, res.nodeDependencies[nID])
+				if err != nil {
+					diags.Add(resolution.DiagnosticRegistryError, newVK, "could not check its dependencies, skipping this version: %v", err)
+					return false
+				}
+				if !ok {
+					return false
 				}
+			}
+
+			return true
+		}
+
+		for _, vuln := range vulnList {
+			if !opts.MatchVuln(vuln) {
+				continue
+			}
+			if source, ok := vkSources[vk]; ok && !isLocal {
+				result.Unmanageable = append(result.Unmanageable, InPlaceUnmanageable{
+					Pkg:     vk.PackageKey,
+					Version: vk.Version,
+					Source:  source,
+					Vuln:    vuln,
+				})
 
+				continue
+			}
+			// Vulns in bundled copies need the bundling package to be changed
+			for _, parent := range bundleParents {
+				result.Bundled = append(result.Bundled, InPlaceBundled{
+					Pkg:     vk.PackageKey,
+					Version: vk.Version,
+					Parent:  parent,
+					Vuln:    vuln,
+				})
+			}
+			if len(installedNodes) == 0 {
+				continue
+			}
+			// Consider vulns affecting packages we don't want to (or can't) change unfixable
+			if isLocal || slices.Contains(opts.AvoidPkgs, vk.Name) {
+				result.Unfixable = append(result.Unfixable, vuln)
+				continue
+			}
+			newVK, err := findFixedVersion(ctx, cl, vk.PackageKey, opts.AllowPrerelease, func(newVK resolve.VersionKey) bool {
 				// Check if this version is vulnerable
-				return !vulns.IsAffected(vuln.Vulnerability, util.VKToPackageDetails(newVK))
+				return canReplace(newVK) && !vulns.IsAffected(vuln.Vulnerability, util.VKToPackageDetails(newVK))
 			})
 
 			if errors.Is(err, errInPlaceImpossible) {
 				result.Unfixable = append(result.Unfixable, vuln)
+				unfixableVKs[vk] = append(unfixableVKs[vk], vuln)
+
 				continue
 			} else if err != nil {
 				return InPlaceResult{}, err
@@ -110,13 +257,62 @@ func ComputeInPlacePatches(ctx context.Context, cl client.ResolutionClient, grap
 				result.Patches = append(result.Patches, InPlacePatch{
 					DependencyPatch: dp,
 					ResolvedVulns:   []resolution.ResolutionVuln{vuln},
+					Prerelease:      isPrerelease(newVK),
+					Confidence:      computeConfidence(ctx, cl.DependencyClient, vv, vk, newVK),
 				})
 			}
 		}
+
+		// If some vulns could not be fixed, look for a version that at least mitigates them
+		if unfixed, ok := unfixableVKs[vk]; ok {
+			pm, err := findPartialMitigation(ctx, cl, vv, vk, unfixed, vulnList, opts.AllowPrerelease, canReplace)
+			if errors.Is(err, errInPlaceImpossible) {
+				continue
+			} else if err != nil {
+				return InPlaceResult{}, err
+			}
+			result.PartialMitigations = append(result.PartialMitigations, pm)
+		}
+	}
+
+	// Suggest replacements for abandoned packages whose vulnerabilities will never be fixed
+	unfixablePkgs := make(map[resolve.PackageKey][]resolution.ResolutionVuln)
+	for vk, vulnList := range unfixableVKs {
+		unfixablePkgs[vk.PackageKey] = append(unfixablePkgs[vk.PackageKey], vulnList...)
+	}
+	for pk, vulnList := range unfixablePkgs {
+		if sugg, ok := suggestAlternative(ctx, cl.DependencyClient, pk, vulnList); ok {
+			result.Alternatives = append(result.Alternatives, sugg)
+		}
 	}
+	slices.SortFunc(result.Alternatives, func(a, b AlternativeSuggestion) int { return cmp.Compare(a.Pkg.Name, b.Pkg.Name) })
+
+	// Sort partial mitigations by package name & version for consistency
+	slices.SortFunc(result.PartialMitigations, func(a, b InPlacePartialMitigation) int {
+		if c := cmp.Compare(a.Pkg.Name, b.Pkg.Name); c != 0 {
+			return c
+		}
+
+		return cmp.Compare(a.OrigVersion, b.OrigVersion)
+	})
+
+	result.EPSS, err = addPatchesEPSS(ctx, cl, result.Patches)
+	if err != nil {
+		diags.Add(resolution.DiagnosticExploitDataMissing, resolve.VersionKey{}, "could not get EPSS scores, patches are prioritized by severity: %v", err)
+	}
+
+	result.Diagnostics = diags.List()
 
 	// Sort patches for priority/consistency
 	slices.SortFunc(result.Patches, func(a, b InPlacePatch) int {
+		// Exploit probability descending
+		if c := cmp.Compare(a.EPSS, b.EPSS); c != 0 {
+			return -c
+		}
+		// Highest severity descending
+		if c := cmp.Compare(patchSeverity(a), patchSeverity(b)); c != 0 {
+			return -c
+		}
 		// Number of vulns fixed descending
 		if c := cmp.Compare(len(a.ResolvedVulns), len(b.ResolvedVulns)); c != 0 {
 			return -c
@@ -136,40 +332,238 @@ func ComputeInPlacePatches(ctx context.Context, cl client.ResolutionClient, grap
 	return result, nil
 }
 
+// patchSeverity returns the highest CVSS score of the vulnerabilities a patch fixes, or -1 if none are known.
+func patchSeverity(p InPlacePatch) float64 {
+	score := -1.0
+	for _, v := range p.ResolvedVulns {
+		s, _ := maxSeverity(v)
+		score = max(score, s)
+	}
+
+	return score
+}
+
+// addPatchesEPSS fills in the EPSS scores of the patches, if the vulnerability client knows them,
+// and returns the score of each vulnerability the patches fix. If it returns an error, the scores are left unknown.
+func addPatchesEPSS(ctx context.Context, cl client.ResolutionClient, patches []InPlacePatch) (map[string]float64, error) {
+	ec, ok := cl.VulnerabilityClient.(client.ExploitClient)
+	if !ok {
+		return nil, nil
+	}
+
+	var vulnList []models.Vulnerability
+	for _, p := range patches {
+		for _, v := range p.ResolvedVulns {
+			vulnList = append(vulnList, v.Vulnerability)
+		}
+	}
+	scores, err := ec.EPSS(ctx, vulnList)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, p := range patches {
+		for _, v := range p.ResolvedVulns {
+			patches[i].EPSS = max(patches[i].EPSS, scores[v.Vulnerability.ID])
+		}
+	}
+
+	return scores, nil
+}
+
 var errInPlaceImpossible = errors.New("cannot find a version satisfying in-place constraints")
 
-func findFixedVersion(ctx context.Context, cl client.DependencyClient, pk resolve.PackageKey, satifyFn func(resolve.VersionKey) bool) (resolve.VersionKey, error) {
+// findFixedVersion finds the latest version of a package that satisfies satisfyFn.
+// Prerelease versions are skipped, unless allowPrerelease is set and no non-prerelease versions are satisfying.
+func findFixedVersion(ctx context.Context, cl client.DependencyClient, pk resolve.PackageKey, allowPrerelease bool, satifyFn func(resolve.VersionKey) bool) (resolve.VersionKey, error) {
 	vers, err := cl.Versions(ctx, pk)
 	if err != nil {
 		return resolve.VersionKey{}, err
 	}
 
-	// Make sure versions are sorted, then iterate over versions from latest to earliest looking for a satisfying version
-	slices.SortFunc(vers, func(a, b resolve.Version) int { return a.Semver().Compare(a.Version, b.Version) })
+	// Make sure versions are sorted, then iterate over versions from latest to earliest looking for a satisfying version.
+	// Sort a copy, since clients may return slices they share with other callers.
+	vers = slices.Clone(vers)
+	slices.SortFunc(vers, func(a, b resolve.Version) int { return util.Semver(a.System).Compare(a.Version, b.Version) })
+	var bestPre *resolve.VersionKey // latest satisfying prerelease version, used only if no other version is found
 	for i := len(vers) - 1; i >= 0; i-- {
 		vk := vers[i].VersionKey
-		if vk.VersionType == resolve.Concrete && satifyFn(vk) {
+		if vk.VersionType != resolve.Concrete {
+			continue
+		}
+		if isPrerelease(vk) {
+			// Only check prerelease versions if we're allowed to use them, and haven't already found one
+			if allowPrerelease && bestPre == nil && satifyFn(vk) {
+				bestPre = &vk
+			}
+
+			continue
+		}
+		if satifyFn(vk) {
 			return vk, nil
 		}
 	}
 
+	if bestPre != nil {
+		return *bestPre, nil
+	}
+
 	return resolve.VersionKey{}, errInPlaceImpossible
 }
 
+// findPartialMitigation finds a version of the package that is affected by fewer of the unfixable vulnerabilities in vulnList,
+// or whose most severe remaining vulnerability is less severe than that of the current version.
+// Versions affected by any vulnerabilities that are not in existing, the vulnerabilities of the current version, are rejected.
+func findPartialMitigation(ctx context.Context, cl client.ResolutionClient, vv *versionVulns, vk resolve.VersionKey, vulnList, existing []resolution.ResolutionVuln, allowPrerelease bool, canReplace func(resolve.VersionKey) bool) (InPlacePartialMitigation, error) {
+	origScore, origRating := maxVulnsSeverity(vulnList)
+	affectedVulns := func(newVK resolve.VersionKey) (affected, removed []resolution.ResolutionVuln) {
+		pkgDetails := util.VKToPackageDetails(newVK)
+		for _, v := range vulnList {
+			if vulns.IsAffected(v.Vulnerability, pkgDetails) {
+				affected = append(affected, v)
+			} else {
+				removed = append(removed, v)
+			}
+		}
+
+		return affected, removed
+	}
+
+	var vulnErr error
+	newVK, err := findFixedVersion(ctx, cl, vk.PackageKey, allowPrerelease, func(newVK resolve.VersionKey) bool {
+		if vulnErr != nil || newVK == vk || !canReplace(newVK) {
+			return false
+		}
+		affected, _ := affectedVulns(newVK)
+		score, _ := maxVulnsSeverity(affected)
+		fewer := len(affected) < len(vulnList) && score <= origScore
+		lessSevere := score < origScore && len(affected) <= len(vulnList)
+		if !fewer && !lessSevere {
+			return false
+		}
+
+		// The version must not trade the unfixable vulnerabilities for different ones
+		introduced, err := introducedVulns(vv, newVK, existing)
+		if err != nil {
+			vulnErr = err
+			return false
+		}
+
+		return len(introduced) == 0
+	})
+	if vulnErr != nil {
+		return InPlacePartialMitigation{}, vulnErr
+	}
+	if err != nil {
+		return InPlacePartialMitigation{}, err
+	}
+
+	affected, removed := affectedVulns(newVK)
+	newScore, newRating := maxVulnsSeverity(affected)
+	var reasons []string
+	if len(affected) < len(vulnList) {
+		reasons = append(reasons, fmt.Sprintf("reduces the number of vulnerabilities from %d to %d", len(vulnList), len(affected)))
+	}
+	if newScore < origScore {
+		reasons = append(reasons, fmt.Sprintf("reduces the maximum severity from %s (%.1f) to %s (%.1f)", origRating, origScore, newRating, newScore))
+	}
+
+	return InPlacePartialMitigation{
+		DependencyPatch: lf.DependencyPatch{
+			Pkg:         vk.PackageKey,
+			OrigVersion: vk.Version,
+			NewVersion:  newVK.Version,
+		},
+		UnfixedVulns: vulnList,
+		RemovedVulns: removed,
+		Explanation: fmt.Sprintf("%s@%s cannot be fixed in-place, but changing to %s %s",
+			vk.Name, vk.Version, newVK.Version, strings.Join(reasons, " and ")),
+	}, nil
+}
+
+// versionVulns memoizes the vulnerabilities of single package versions.
+type versionVulns struct {
+	cl    client.VulnerabilityClient
+	vulns map[resolve.VersionKey][]models.Vulnerability
+}
+
+func newVersionVulns(cl client.VulnerabilityClient) *versionVulns {
+	return &versionVulns{cl: cl, vulns: make(map[resolve.VersionKey][]models.Vulnerability)}
+}
+
+// get finds the vulnerabilities affecting vk.
+func (vv *versionVulns) get(vk resolve.VersionKey) ([]models.Vulnerability, error) {
+	if v, ok := vv.vulns[vk]; ok {
+		return v, nil
+	}
+
+	// FindVulns skips the root node, so the version is added after a placeholder root
+	g := &resolve.Graph{}
+	g.AddNode(resolve.VersionKey{PackageKey: resolve.PackageKey{System: vk.System}})
+	g.AddNode(vk)
+	nodeVulns, err := vv.cl.FindVulns(g)
+	if err != nil {
+		return nil, err
+	}
+	vv.vulns[vk] = nodeVulns[1]
+
+	return nodeVulns[1], nil
+}
+
+// introducedVulns finds the vulnerabilities affecting newVK that are not in existing.
+func introducedVulns(vv *versionVulns, newVK resolve.VersionKey, existing []resolution.ResolutionVuln) ([]models.Vulnerability, error) {
+	newVulns, err := vv.get(newVK)
+	if err != nil {
+		return nil, err
+	}
+
+	var introduced []models.Vulnerability
+	for _, v := range newVulns {
+		if !slices.ContainsFunc(existing, func(rv resolution.ResolutionVuln) bool { return rv.Vulnerability.ID == v.ID }) {
+			introduced = append(introduced, v)
+		}
+	}
+
+	return introduced, nil
+}
+
+// maxVulnsSeverity returns the highest CVSS score and its rating of a list of vulnerabilities.
+func maxVulnsSeverity(vulnList []resolution.ResolutionVuln) (float64, string) {
+	maxScore := -1.0
+	maxRating := "UNKNOWN"
+	for _, v := range vulnList {
+		if score, rating := maxSeverity(v); score > maxScore {
+			maxScore = score
+			maxRating = rating
+		}
+	}
+
+	return maxScore, maxRating
+}
+
+func isPrerelease(vk resolve.VersionKey) bool {
+	v, err := util.Semver(vk.System).Parse(vk.Version)
+	if err != nil {
+		return false
+	}
+
+	return v.IsPrerelease()
+}
+
 type inPlaceVulnsNodesResult struct {
-	nodeDependencies map[resolve.NodeID][]resolve.VersionKey
+	nodeDependencies map[resolve.NodeID][]installedDep
 	vkVulns          map[resolve.VersionKey][]resolution.ResolutionVuln
 	vkNodes          map[resolve.VersionKey][]resolve.NodeID
 }
 
-func inPlaceVulnsNodes(cl client.VulnerabilityClient, graph *resolve.Graph) (inPlaceVulnsNodesResult, error) {
+func inPlaceVulnsNodes(cl client.VulnerabilityClient, graph *resolve.Graph, allChains bool) (inPlaceVulnsNodesResult, error) {
 	nodeVulns, err := cl.FindVulns(graph)
 	if err != nil {
 		return inPlaceVulnsNodesResult{}, err
 	}
 
 	result := inPlaceVulnsNodesResult{
-		nodeDependencies: make(map[resolve.NodeID][]resolve.VersionKey),
+		nodeDependencies: make(map[resolve.NodeID][]installedDep),
 		vkVulns:          make(map[resolve.VersionKey][]resolution.ResolutionVuln),
 		vkNodes:          make(map[resolve.VersionKey][]resolve.NodeID),
 	}
@@ -177,7 +571,7 @@ func inPlaceVulnsNodes(cl client.VulnerabilityClient, graph *resolve.Graph) (inP
 	// Find all direct dependencies of vulnerable nodes.
 	for _, e := range graph.Edges {
 		if len(nodeVulns[e.From]) > 0 {
-			result.nodeDependencies[e.From] = append(result.nodeDependencies[e.From], graph.Nodes[e.To].Version)
+			result.nodeDependencies[e.From] = append(result.nodeDependencies[e.From], newInstalledDep(graph.Nodes[e.To].Version, e.Type))
 		}
 	}
 
@@ -189,9 +583,13 @@ func inPlaceVulnsNodes(cl client.VulnerabilityClient, graph *resolve.Graph) (inP
 			nodeIDs = append(nodeIDs, resolve.NodeID(nID))
 		}
 	}
-	nodeChains := resolution.ComputeChains(graph, nodeIDs)
-	// Computing ALL chains might be overkill...
-	// We only actually care about the shortest chain, the unique dependents of the vulnerable node, and maybe the unique direct dependencies.
+	// Computing ALL chains can be very slow on large graphs.
+	// We usually only actually care about the shortest chain & the unique dependents of the vulnerable node.
+	chainMode := resolution.ShortestChains
+	if allChains {
+		chainMode = resolution.AllChains
+	}
+	nodeChains := resolution.ComputeChains(graph, nodeIDs, chainMode)
 
 	for i, nID := range nodeIDs {
 		chains := nodeChains[i]
@@ -218,22 +616,12 @@ func inPlaceVulnsNodes(cl client.VulnerabilityClient, graph *resolve.Graph) (inP
 
 func buildConstraintSet(sys semver.System, requiredVers []string) (semver.Set, error) {
 	// combine a list of requirement strings into one semver.Set to allow version matching
-	v := requiredVers[0]
-	// 'latest' is effectively meaningless in a lockfile, since what 'latest' is could have changed between locking
-	// TODO: other tags e.g. "next", "old" (?)
-	// TODO: non-npm ecosystems
-	if v == "latest" {
-		v = "*"
-	}
-	c, err := sys.ParseConstraint(v)
+	c, err := sys.ParseConstraint(requiredVers[0])
 	if err != nil {
 		return semver.Set{}, err
 	}
 	cSet := c.Set()
 	for _, req := range requiredVers[1:] {
-		if req == "latest" {
-			req = "*"
-		}
 		c, err := sys.ParseConstraint(req)
 		if err != nil {
 			return semver.Set{}, err
@@ -246,9 +634,55 @@ func buildConstraintSet(sys semver.System, requiredVers []string) (semver.Set, e
 	return cSet, nil
 }
 
-func dependenciesSatisfied(ctx context.Context, cl client.DependencyClient, vk resolve.VersionKey, children []resolve.VersionKey) (bool, error) {
-	var deps []resolve.VersionKey
-	var optDeps []resolve.VersionKey
+// resolveRequirementTag replaces a requirement on a dist-tag (e.g. "next" or "beta") with the version the tag refers to.
+// Requirements that are not tags are returned unchanged.
+// TODO: non-npm ecosystems
+func resolveRequirementTag(ctx context.Context, cl client.DependencyClient, pk resolve.PackageKey, req string) string {
+	// 'latest' is effectively meaningless in a lockfile, since what 'latest' is could have changed between locking
+	if req == "latest" {
+		return "*"
+	}
+	if _, err := util.Semver(pk.System).ParseConstraint(req); err == nil {
+		return req
+	}
+
+	// Not a valid constraint, check if the registry knows it as a tag
+	vers, err := cl.MatchingVersions(ctx, resolve.VersionKey{
+		PackageKey:  pk,
+		Version:     req,
+		VersionType: resolve.Requirement,
+	})
+	if err != nil || len(vers) != 1 {
+		return req
+	}
+
+	return vers[0].Version
+}
+
+// installedDep is a direct dependency of a node in the graph,
+// with the name that its dependent refers to it by if it is an npm alias (e.g. "alias": "npm:pkg@^1.2.3").
+type installedDep struct {
+	resolve.VersionKey
+	KnownAs string
+}
+
+// refName is the name a dependent refers to the dependency by.
+func (d installedDep) refName() string {
+	if d.KnownAs != "" {
+		return d.KnownAs
+	}
+
+	return d.Name
+}
+
+func newInstalledDep(vk resolve.VersionKey, t dep.Type) installedDep {
+	knownAs, _ := t.GetAttr(dep.KnownAs)
+	return installedDep{VersionKey: vk, KnownAs: knownAs}
+}
+
+func dependenciesSatisfied(ctx context.Context, cl client.DependencyClient, vk resolve.VersionKey, children []installedDep) (bool, error) {
+	var deps []installedDep
+	var optDeps []installedDep
 	reqs, err := cl.Requirements(ctx, vk)
 	if err != nil {
 		return false, err
@@ -256,45 +690,68 @@ func dependenciesSatisfied(ctx context.Context, cl client.DependencyClient, vk r
 
 	for _, v := range reqs {
 		if v.Type.IsRegular() {
-			deps = append(deps, v.VersionKey)
+			deps = append(deps, newInstalledDep(v.VersionKey, v.Type))
 		} else if v.Type.HasAttr(dep.Opt) {
-			optDeps = append(optDeps, v.VersionKey)
+			optDeps = append(optDeps, newInstalledDep(v.VersionKey, v.Type))
 		}
 	}
 	// TODO: correctly handle other attrs e.g. npm peerDependencies
 
-	// remove the optional deps from the regular deps (because they show up in both) if they're not already installed
-	for _, optVk := range optDeps {
-		if !slices.ContainsFunc(children, func(vk resolve.VersionKey) bool { return vk.Name == optVk.Name }) {
-			idx := slices.IndexFunc(deps, func(vk resolve.VersionKey) bool { return vk.Name == optVk.Name })
-			deps = slices.Delete(deps, idx, idx+1)
+	// remove the optional deps from the regular deps (because they usually show up in both) if they're not already installed
+	// e.g. platform-specific binaries for other platforms are not in the lockfile
+	for _, optDep := range optDeps {
+		if !slices.ContainsFunc(children, func(d installedDep) bool { return d.refName() == optDep.refName() }) {
+			deps = slices.DeleteFunc(deps, func(d installedDep) bool { return d.refName() == optDep.refName() })
 		}
 	}
 
 	for _, depVK := range deps {
-		ver := depVK.Version
-		// 'latest' is effectively meaningless in a lockfile, since what 'latest' is could have changed between locking
-		// TODO: Support other tags e.g. "next", "old" & non-npm ecosystems
-		if ver == "latest" {
-			ver = "*"
-		}
-		constr, err := vk.Semver().ParseConstraint(ver)
+		ver := resolveRequirementTag(ctx, cl, depVK.PackageKey, depVK.Version)
+		constr, err := util.Semver(vk.System).ParseConstraint(ver)
 		if err != nil {
 			return false, err
 		}
 
 		// check if any of the current children satisfy this import
+		// aliased children must be referred to by the same alias, and be the same real package
 		ok := false
 		for _, child := range children {
-			if child.Name == depVK.Name && constr.Match(child.Version) {
+			if child.refName() == depVK.refName() && child.Name == depVK.Name && constr.Match(child.Version) {
 				ok = true
 				break
 			}
 		}
 		if !ok {
+			// Package managers skip platform-specific packages that cannot be installed on the current platform,
+			// so these dependencies are only required if they are already installed.
+			if !slices.ContainsFunc(children, func(d installedDep) bool { return d.refName() == depVK.refName() }) &&
+				isPlatformSpecific(ctx, cl, depVK.VersionKey) {
+				continue
+			}
+
 			return false, nil
 		}
 	}
 
 	return true, nil
 }
+
+// isPlatformSpecific checks if the versions matching the requirement can only be installed on some platforms
+// (e.g. with npm's "os" and "cpu" fields), according to the highest matching version.
+func isPlatformSpecific(ctx context.Context, cl client.DependencyClient, req resolve.VersionKey) bool {
+	pc, ok := client.As[client.PlatformClient](cl)
+	if !ok {
+		return false
+	}
+	vers, err := cl.MatchingVersions(ctx, req)
+	if err != nil || len(vers) == 0 {
+		return false
+	}
+	vk := slices.MaxFunc(vers, func(a, b resolve.Version) int { return util.Semver(req.System).Compare(a.Version, b.Version) }).VersionKey
+	os, cpu, err := pc.Platforms(ctx, vk)
+	if err != nil {
+		return false
+	}
+
+	return len(os) > 0 || len(cpu) > 0
+}
@@ -0,0 +1,655 @@
+package remediation_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+
+	"deps.dev/util/resolve"
+	"deps.dev/util/resolve/dep"
+	"deps.dev/util/resolve/version"
+	"deps.dev/util/semver"
+	"github.com/google/osv-scanner/internal/remediation"
+	"github.com/google/osv-scanner/internal/resolution"
+	"github.com/google/osv-scanner/internal/resolution/client"
+	lf "github.com/google/osv-scanner/internal/resolution/lockfile"
+	"github.com/google/osv-scanner/pkg/lockfile"
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+// localDependencyClient is a DependencyClient serving the versions of a resolve.LocalClient, without a cache.
+type localDependencyClient struct {
+	*resolve.LocalClient
+}
+
+func (localDependencyClient) WriteCache(string) error { return nil }
+func (localDependencyClient) LoadCache(string) error  { return nil }
+func (localDependencyClient) PreFetch(context.Context, []resolve.RequirementVersion, string) {
+}
+
+// fakeVulnClient finds the vulnerabilities of each package version, counting how many times each version is looked up.
+type fakeVulnClient struct {
+	vulns   map[resolve.VersionKey][]models.Vulnerability
+	lookups map[resolve.VersionKey]int
+}
+
+func (c *fakeVulnClient) FindVulns(g *resolve.Graph) ([]models.Vulnerabilities, error) {
+	nodeVulns := make([]models.Vulnerabilities, len(g.Nodes))
+	for i, n := range g.Nodes {
+		if i == 0 {
+			continue
+		}
+		c.lookups[n.Version]++
+		nodeVulns[i] = c.vulns[n.Version]
+	}
+
+	return nodeVulns, nil
+}
+
+// epssVulnClient is a fakeVulnClient that also knows the EPSS scores of vulnerabilities, or fails to get them with err.
+type epssVulnClient struct {
+	*fakeVulnClient
+	scores map[string]float64
+	err    error
+}
+
+func (c epssVulnClient) EPSS(context.Context, []models.Vulnerability) (map[string]float64, error) {
+	return c.scores, c.err
+}
+
+func npmVK(name, version string) resolve.VersionKey {
+	return resolve.VersionKey{PackageKey: resolve.PackageKey{System: resolve.NPM, Name: name}, Version: version, VersionType: resolve.Concrete}
+}
+
+// npmVuln is an npm vulnerability of a package, affecting the versions before fixed.
+func npmVuln(id, pkg, fixed string) models.Vulnerability {
+	events := []models.Event{{Introduced: "0"}}
+	if fixed != "" {
+		events = append(events, models.Event{Fixed: fixed})
+	}
+
+	return models.Vulnerability{
+		ID: id,
+		Affected: []models.Affected{{
+			Package: models.Package{Ecosystem: models.EcosystemNPM, Name: pkg},
+			Ranges:  []models.Range{{Type: models.RangeSemVer, Events: events}},
+		}},
+	}
+}
+
+func TestComputeInPlacePatches_Confidence(t *testing.T) {
+	t.Parallel()
+
+	root := npmVK("root", "1.0.0")
+	foo := npmVK("foo", "1.0.0")
+	bar := npmVK("bar", "2.0.0")
+
+	dc := resolve.NewLocalClient()
+	for _, vk := range []resolve.VersionKey{foo, npmVK("foo", "1.0.1"), npmVK("foo", "1.1.0"), bar, npmVK("bar", "2.0.1")} {
+		dc.AddVersion(resolve.Version{VersionKey: vk}, nil)
+	}
+
+	g := &resolve.Graph{}
+	rootID := g.AddNode(root)
+	for _, vk := range []resolve.VersionKey{foo, bar} {
+		if err := g.AddEdge(rootID, g.AddNode(vk), "^"+vk.Version, dep.NewType()); err != nil {
+			t.Fatalf("AddEdge() error = %v", err)
+		}
+	}
+
+	vc := &fakeVulnClient{
+		vulns: map[resolve.VersionKey][]models.Vulnerability{
+			// GHSA-foo2 cannot be fixed, so the versions of foo are also checked for a partial mitigation of it
+			foo: {npmVuln("GHSA-foo1", "foo", "1.0.1"), npmVuln("GHSA-foo2", "foo", "")},
+			bar: {npmVuln("GHSA-bar", "bar", "2.0.1")},
+			// fixes the vulnerabilities of foo@1.0.0, but has one of its own
+			npmVK("foo", "1.1.0"): {npmVuln("GHSA-foo3", "foo", "")},
+		},
+		lookups: make(map[resolve.VersionKey]int),
+	}
+
+	res, err := remediation.ComputeInPlacePatches(context.Background(), client.ResolutionClient{DependencyClient: localDependencyClient{dc}, VulnerabilityClient: vc}, g, remediation.RemediationOptions{})
+	if err != nil {
+		t.Fatalf("ComputeInPlacePatches() error = %v", err)
+	}
+
+	want := map[string]remediation.UpgradeConfidence{
+		// a minor upgrade to a version with a known vulnerability
+		"foo": {Score: 0.7, Diff: semver.DiffMinor, KnownVulns: 1},
+		// a patch upgrade to a version without known vulnerabilities
+		"bar": {Score: 0.95, Diff: semver.DiffPatch},
+	}
+	if len(res.Patches) != len(want) {
+		t.Fatalf("ComputeInPlacePatches() patches = %+v, want one for each of foo and bar", res.Patches)
+	}
+	for _, p := range res.Patches {
+		got := p.Confidence
+		got.Score = float64(int(got.Score*100+0.5)) / 100
+		if got != want[p.Pkg.Name] {
+			t.Errorf("confidence of %s@%s = %+v, want %+v", p.Pkg.Name, p.NewVersion, got, want[p.Pkg.Name])
+		}
+	}
+
+	// the vulnerabilities of the new versions are looked up once, for both confidence scores and partial mitigations
+	for vk, n := range vc.lookups {
+		if n > 1 && vk != foo && vk != bar {
+			t.Errorf("vulnerabilities of %s looked up %d times, want once", vk, n)
+		}
+	}
+	if len(res.PartialMitigations) != 0 {
+		t.Errorf("ComputeInPlacePatches() partial mitigations = %+v, want none", res.PartialMitigations)
+	}
+}
+
+func TestComputeInPlacePatches_EPSSOrder(t *testing.T) {
+	t.Parallel()
+
+	withSeverity := func(v models.Vulnerability, cvss string) models.Vulnerability {
+		v.Severity = []models.Severity{{Type: models.SeverityCVSSV3, Score: cvss}}
+		return v
+	}
+
+	dc := resolve.NewLocalClient()
+	g := &resolve.Graph{}
+	rootID := g.AddNode(npmVK("root", "1.0.0"))
+	vulns := make(map[resolve.VersionKey][]models.Vulnerability)
+	for name, cvss := range map[string]string{"crit": cvssCritical, "high": cvssHigh, "low": cvssLow} {
+		vk := npmVK(name, "1.0.0")
+		dc.AddVersion(resolve.Version{VersionKey: vk}, nil)
+		dc.AddVersion(resolve.Version{VersionKey: npmVK(name, "1.0.1")}, nil)
+		if err := g.AddEdge(rootID, g.AddNode(vk), "^1.0.0", dep.NewType()); err != nil {
+			t.Fatalf("AddEdge() error = %v", err)
+		}
+		vulns[vk] = []models.Vulnerability{withSeverity(npmVuln("GHSA-"+name, name, "1.0.1"), cvss)}
+	}
+	// the low severity vulnerability is the most likely to be exploited, and the high severity one has no score
+	scores := map[string]float64{"GHSA-crit": 0.1, "GHSA-low": 0.9}
+
+	tests := []struct {
+		name      string
+		err       error
+		want      []string
+		wantEPSS  map[string]float64
+		wantDiags int
+	}{
+		{
+			name:     "by exploit probability",
+			want:     []string{"low", "crit", "high"},
+			wantEPSS: scores,
+		},
+		{
+			name:      "by severity without scores",
+			err:       errors.New("no scores"),
+			want:      []string{"crit", "high", "low"},
+			wantDiags: 1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			vc := epssVulnClient{
+				fakeVulnClient: &fakeVulnClient{vulns: vulns, lookups: make(map[resolve.VersionKey]int)},
+				scores:         scores,
+				err:            tt.err,
+			}
+			res, err := remediation.ComputeInPlacePatches(context.Background(), client.ResolutionClient{DependencyClient: localDependencyClient{dc}, VulnerabilityClient: vc}, g, remediation.RemediationOptions{})
+			if err != nil {
+				t.Fatalf("ComputeInPlacePatches() error = %v", err)
+			}
+
+			got := make([]string, len(res.Patches))
+			for i, p := range res.Patches {
+				got[i] = p.Pkg.Name
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ComputeInPlacePatches() patch order = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(res.EPSS, tt.wantEPSS) {
+				t.Errorf("ComputeInPlacePatches() EPSS = %v, want %v", res.EPSS, tt.wantEPSS)
+			}
+			var diags int
+			for _, d := range res.Diagnostics {
+				if d.Kind == resolution.DiagnosticExploitDataMissing {
+					diags++
+				}
+			}
+			if diags != tt.wantDiags {
+				t.Errorf("ComputeInPlacePatches() exploit data diagnostics = %d, want %d", diags, tt.wantDiags)
+			}
+		})
+	}
+}
+
+func TestComputeInPlacePatches_YarnWorkspaces(t *testing.T) {
+	t.Parallel()
+
+	f, err := lockfile.OpenLocalDepFile("fixtures/yarn-berry-workspaces/yarn.lock")
+	if err != nil {
+		t.Fatalf("failed to open fixture: %v", err)
+	}
+	defer f.Close()
+	g, err := lf.YarnBerryLockfileIO{}.Read(f)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+
+	dc := resolve.NewLocalClient()
+	for _, name := range []string{"foo", "bar", "ws-a"} {
+		dc.AddVersion(resolve.Version{VersionKey: npmVK(name, "1.0.0")}, nil)
+		dc.AddVersion(resolve.Version{VersionKey: npmVK(name, "1.0.1")}, nil)
+	}
+	vc := &fakeVulnClient{
+		vulns: map[resolve.VersionKey][]models.Vulnerability{
+			npmVK("foo", "1.0.0"): {npmVuln("GHSA-foo", "foo", "1.0.1")},
+			// bar is only a dependency of ws-b, which no other workspace depends on
+			npmVK("bar", "1.0.0"): {npmVuln("GHSA-bar", "bar", "1.0.1")},
+			// a vulnerability in a registry package with the same name as the workspace
+			npmVK("ws-a", "0.0.0-use.local"): {npmVuln("GHSA-ws-a", "ws-a", "1.0.1")},
+		},
+		lookups: make(map[resolve.VersionKey]int),
+	}
+
+	res, err := remediation.ComputeInPlacePatches(context.Background(), client.ResolutionClient{DependencyClient: localDependencyClient{dc}, VulnerabilityClient: vc}, g, remediation.RemediationOptions{})
+	if err != nil {
+		t.Fatalf("ComputeInPlacePatches() error = %v", err)
+	}
+
+	var got []string
+	for _, p := range res.Patches {
+		got = append(got, p.Pkg.Name+"@"+p.OrigVersion+" -> "+p.NewVersion)
+	}
+	slices.Sort(got)
+	want := []string{"bar@1.0.0 -> 1.0.1", "foo@1.0.0 -> 1.0.1"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ComputeInPlacePatches() patches = %v, want %v", got, want)
+	}
+	// the workspace itself cannot be changed
+	if len(res.Unfixable) != 1 || res.Unfixable[0].Vulnerability.ID != "GHSA-ws-a" {
+		t.Errorf("ComputeInPlacePatches() unfixable = %+v, want GHSA-ws-a", res.Unfixable)
+	}
+}
+
+func TestComputeInPlacePatches_Aliases(t *testing.T) {
+	t.Parallel()
+
+	// parent depends on foo under the alias "alias", i.e. "alias": "npm:foo@^1.0.0"
+	aliasType := func() dep.Type {
+		typ := dep.NewType()
+		typ.AddAttr(dep.KnownAs, "alias")
+
+		return typ
+	}
+	fooReq := func(typ dep.Type) []resolve.RequirementVersion {
+		return []resolve.RequirementVersion{{
+			VersionKey: resolve.VersionKey{PackageKey: resolve.PackageKey{System: resolve.NPM, Name: "foo"}, Version: "^1.0.0", VersionType: resolve.Requirement},
+			Type:       typ,
+		}}
+	}
+
+	tests := []struct {
+		name string
+		reqs []resolve.RequirementVersion // the requirements of parent@1.0.1
+		want []string
+	}{
+		{
+			name: "new version requires the same alias",
+			reqs: fooReq(aliasType()),
+			want: []string{"parent@1.0.0 -> 1.0.1"},
+		},
+		{
+			// foo is installed as "alias", so there is nothing installed as "foo" for it to use
+			name: "new version requires the real package",
+			reqs: fooReq(dep.NewType()),
+			want: []string{},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			dc := resolve.NewLocalClient()
+			dc.AddVersion(resolve.Version{VersionKey: npmVK("parent", "1.0.0")}, fooReq(aliasType()))
+			dc.AddVersion(resolve.Version{VersionKey: npmVK("parent", "1.0.1")}, tt.reqs)
+			dc.AddVersion(resolve.Version{VersionKey: npmVK("foo", "1.0.0")}, nil)
+
+			g := &resolve.Graph{}
+			rootID := g.AddNode(npmVK("root", "1.0.0"))
+			parentID := g.AddNode(npmVK("parent", "1.0.0"))
+			fooID := g.AddNode(npmVK("foo", "1.0.0"))
+			if err := g.AddEdge(rootID, parentID, "^1.0.0", dep.NewType()); err != nil {
+				t.Fatalf("AddEdge() error = %v", err)
+			}
+			if err := g.AddEdge(parentID, fooID, "^1.0.0", aliasType()); err != nil {
+				t.Fatalf("AddEdge() error = %v", err)
+			}
+
+			vc := &fakeVulnClient{
+				vulns: map[resolve.VersionKey][]models.Vulnerability{
+					npmVK("parent", "1.0.0"): {npmVuln("GHSA-parent", "parent", "1.0.1")},
+				},
+				lookups: make(map[resolve.VersionKey]int),
+			}
+			res, err := remediation.ComputeInPlacePatches(context.Background(), client.ResolutionClient{DependencyClient: localDependencyClient{dc}, VulnerabilityClient: vc}, g, remediation.RemediationOptions{})
+			if err != nil {
+				t.Fatalf("ComputeInPlacePatches() error = %v", err)
+			}
+
+			got := []string{}
+			for _, p := range res.Patches {
+				got = append(got, p.Pkg.Name+"@"+p.OrigVersion+" -> "+p.NewVersion)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ComputeInPlacePatches() patches = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComputeInPlacePatches_DistTags(t *testing.T) {
+	t.Parallel()
+
+	// The "next" tag has moved on to the fixed version since foo@1.0.0 was locked
+	dc := resolve.NewLocalClient()
+	dc.AddVersion(resolve.Version{VersionKey: npmVK("foo", "1.0.0")}, nil)
+	var next version.AttrSet
+	next.SetAttr(version.Tags, "next")
+	dc.AddVersion(resolve.Version{VersionKey: npmVK("foo", "1.0.1"), AttrSet: next}, nil)
+	dc.AddVersion(resolve.Version{VersionKey: npmVK("foo", "1.1.0")}, nil)
+
+	tests := []struct {
+		name      string
+		req       string
+		want      []string
+		wantDiags int
+	}{
+		{
+			name: "latest matches any version",
+			req:  "latest",
+			want: []string{"1.1.0"},
+		},
+		{
+			name: "tag is resolved by the registry",
+			req:  "next",
+			want: []string{"1.0.1"},
+		},
+		{
+			name:      "unknown tag",
+			req:       "beta",
+			want:      []string{},
+			wantDiags: 1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			g := &resolve.Graph{}
+			rootID := g.AddNode(npmVK("root", "1.0.0"))
+			if err := g.AddEdge(rootID, g.AddNode(npmVK("foo", "1.0.0")), tt.req, dep.NewType()); err != nil {
+				t.Fatalf("AddEdge() error = %v", err)
+			}
+			vc := &fakeVulnClient{
+				vulns: map[resolve.VersionKey][]models.Vulnerability{
+					npmVK("foo", "1.0.0"): {npmVuln("GHSA-foo", "foo", "1.0.1")},
+				},
+				lookups: make(map[resolve.VersionKey]int),
+			}
+			res, err := remediation.ComputeInPlacePatches(context.Background(), client.ResolutionClient{DependencyClient: localDependencyClient{dc}, VulnerabilityClient: vc}, g, remediation.RemediationOptions{})
+			if err != nil {
+				t.Fatalf("ComputeInPlacePatches() error = %v", err)
+			}
+
+			got := []string{}
+			for _, p := range res.Patches {
+				got = append(got, p.NewVersion)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ComputeInPlacePatches() patched versions = %v, want %v", got, tt.want)
+			}
+			var diags int
+			for _, d := range res.Diagnostics {
+				if d.Kind == resolution.DiagnosticUnparseableConstraint {
+					diags++
+				}
+			}
+			if diags != tt.wantDiags {
+				t.Errorf("ComputeInPlacePatches() unparseable constraint diagnostics = %d, want %d", diags, tt.wantDiags)
+			}
+		})
+	}
+}
+
+func TestComputeInPlacePatches_Unmanageable(t *testing.T) {
+	t.Parallel()
+
+	dc := resolve.NewLocalClient()
+	for _, vk := range []resolve.VersionKey{npmVK("foo", "1.0.0"), npmVK("foo", "1.0.1"), npmVK("bar", "1.0.0"), npmVK("bar", "1.0.1")} {
+		dc.AddVersion(resolve.Version{VersionKey: vk}, nil)
+	}
+
+	// foo is installed from a git repository, so it cannot be changed to a version from the registry
+	g := &resolve.Graph{}
+	rootID := g.AddNode(npmVK("root", "1.0.0"))
+	if err := g.AddEdge(rootID, g.AddNode(npmVK("foo", "1.0.0")), "github:user/foo#v1.0.0", dep.NewType()); err != nil {
+		t.Fatalf("AddEdge() error = %v", err)
+	}
+	if err := g.AddEdge(rootID, g.AddNode(npmVK("bar", "1.0.0")), "^1.0.0", dep.NewType()); err != nil {
+		t.Fatalf("AddEdge() error = %v", err)
+	}
+	vc := &fakeVulnClient{
+		vulns: map[resolve.VersionKey][]models.Vulnerability{
+			npmVK("foo", "1.0.0"): {npmVuln("GHSA-foo", "foo", "1.0.1")},
+			npmVK("bar", "1.0.0"): {npmVuln("GHSA-bar", "bar", "1.0.1")},
+		},
+		lookups: make(map[resolve.VersionKey]int),
+	}
+
+	res, err := remediation.ComputeInPlacePatches(context.Background(), client.ResolutionClient{DependencyClient: localDependencyClient{dc}, VulnerabilityClient: vc}, g, remediation.RemediationOptions{})
+	if err != nil {
+		t.Fatalf("ComputeInPlacePatches() error = %v", err)
+	}
+
+	if len(res.Patches) != 1 || res.Patches[0].Pkg.Name != "bar" {
+		t.Errorf("ComputeInPlacePatches() patches = %+v, want only bar", res.Patches)
+	}
+	if len(res.Unmanageable) != 1 {
+		t.Fatalf("ComputeInPlacePatches() unmanageable = %+v, want foo", res.Unmanageable)
+	}
+	u := res.Unmanageable[0]
+	if u.Pkg.Name != "foo" || u.Version != "1.0.0" || u.Source != "github:user/foo#v1.0.0" || u.Vuln.Vulnerability.ID != "GHSA-foo" {
+		t.Errorf("ComputeInPlacePatches() unmanageable = %+v, want GHSA-foo in foo@1.0.0 from github:user/foo#v1.0.0", u)
+	}
+	if len(res.Unfixable) != 0 {
+		t.Errorf("ComputeInPlacePatches() unfixable = %+v, want none", res.Unfixable)
+	}
+}
+
+func TestComputeInPlacePatches_Bundled(t *testing.T) {
+	t.Parallel()
+
+	bundleType := func() dep.Type {
+		typ := dep.NewType()
+		typ.AddAttr(dep.Scope, lf.BundleScope)
+
+		return typ
+	}
+
+	tests := []struct {
+		name        string
+		installed   bool // whether root also installs its own copy of baz
+		nested      bool // whether baz is bundled in mid, which is itself bundled in parent
+		wantPatches []string
+	}{
+		{
+			name:        "only bundled",
+			wantPatches: []string{},
+		},
+		{
+			name:        "bundled and installed",
+			installed:   true,
+			wantPatches: []string{"baz@1.0.0 -> 1.0.1"},
+		},
+		{
+			name:        "bundled in a bundled package",
+			nested:      true,
+			wantPatches: []string{},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			dc := resolve.NewLocalClient()
+			for _, vk := range []resolve.VersionKey{npmVK("parent", "1.0.0"), npmVK("mid", "1.0.0"), npmVK("baz", "1.0.0"), npmVK("baz", "1.0.1")} {
+				dc.AddVersion(resolve.Version{VersionKey: vk}, nil)
+			}
+
+			g := &resolve.Graph{}
+			rootID := g.AddNode(npmVK("root", "1.0.0"))
+			parentID := g.AddNode(npmVK("parent", "1.0.0"))
+			bazID := g.AddNode(npmVK("baz", "1.0.0"))
+			if err := g.AddEdge(rootID, parentID, "^1.0.0", dep.NewType()); err != nil {
+				t.Fatalf("AddEdge() error = %v", err)
+			}
+			bundlerID := parentID
+			if tt.nested {
+				bundlerID = g.AddNode(npmVK("mid", "1.0.0"))
+				if err := g.AddEdge(parentID, bundlerID, "^1.0.0", bundleType()); err != nil {
+					t.Fatalf("AddEdge() error = %v", err)
+				}
+			}
+			if err := g.AddEdge(bundlerID, bazID, "^1.0.0", bundleType()); err != nil {
+				t.Fatalf("AddEdge() error = %v", err)
+			}
+			if tt.installed {
+				if err := g.AddEdge(rootID, g.AddNode(npmVK("baz", "1.0.0")), "^1.0.0", dep.NewType()); err != nil {
+					t.Fatalf("AddEdge() error = %v", err)
+				}
+			}
+
+			vc := &fakeVulnClient{
+				vulns: map[resolve.VersionKey][]models.Vulnerability{
+					npmVK("baz", "1.0.0"): {npmVuln("GHSA-baz", "baz", "1.0.1")},
+				},
+				lookups: make(map[resolve.VersionKey]int),
+			}
+			res, err := remediation.ComputeInPlacePatches(context.Background(), client.ResolutionClient{DependencyClient: localDependencyClient{dc}, VulnerabilityClient: vc}, g, remediation.RemediationOptions{})
+			if err != nil {
+				t.Fatalf("ComputeInPlacePatches() error = %v", err)
+			}
+
+			got := []string{}
+			for _, p := range res.Patches {
+				got = append(got, p.Pkg.Name+"@"+p.OrigVersion+" -> "+p.NewVersion)
+			}
+			if !reflect.DeepEqual(got, tt.wantPatches) {
+				t.Errorf("ComputeInPlacePatches() patches = %v, want %v", got, tt.wantPatches)
+			}
+
+			// the bundled copy can only be fixed by changing the outermost package bundling it
+			if len(res.Bundled) != 1 {
+				t.Fatalf("ComputeInPlacePatches() bundled = %+v, want baz in parent", res.Bundled)
+			}
+			b := res.Bundled[0]
+			if b.Pkg.Name != "baz" || b.Version != "1.0.0" || b.Parent != npmVK("parent", "1.0.0") || b.Vuln.Vulnerability.ID != "GHSA-baz" {
+				t.Errorf("ComputeInPlacePatches() bundled = %+v, want GHSA-baz in baz@1.0.0 bundled in parent@1.0.0", b)
+			}
+			if len(res.Unfixable) != 0 {
+				t.Errorf("ComputeInPlacePatches() unfixable = %+v, want none", res.Unfixable)
+			}
+		})
+	}
+}
+
+// platformDependencyClient is a localDependencyClient that knows the operating systems some package versions are restricted to.
+type platformDependencyClient struct {
+	localDependencyClient
+	os map[resolve.VersionKey][]string
+}
+
+func (c platformDependencyClient) Platforms(_ context.Context, vk resolve.VersionKey) ([]string, []string, error) {
+	return c.os[vk], nil, nil
+}
+
+func TestComputeInPlacePatches_PlatformSpecific(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		dep       string // the dependency of foo@1.0.1, which is not installed
+		platforms bool   // whether the client knows the platforms of the packages
+		want      []string
+	}{
+		{
+			// e.g. a linux lockfile does not have the darwin binaries
+			name:      "platform-specific dependency",
+			dep:       "bin-darwin",
+			platforms: true,
+			want:      []string{"foo@1.0.0 -> 1.0.1"},
+		},
+		{
+			name: "platforms unknown",
+			dep:  "bin-darwin",
+			want: []string{},
+		},
+		{
+			name:      "dependency for every platform",
+			dep:       "bar",
+			platforms: true,
+			want:      []string{},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			lc := resolve.NewLocalClient()
+			lc.AddVersion(resolve.Version{VersionKey: npmVK("foo", "1.0.0")}, nil)
+			lc.AddVersion(resolve.Version{VersionKey: npmVK("foo", "1.0.1")}, []resolve.RequirementVersion{{
+				VersionKey: resolve.VersionKey{PackageKey: resolve.PackageKey{System: resolve.NPM, Name: tt.dep}, Version: "^1.0.0", VersionType: resolve.Requirement},
+				Type:       dep.NewType(),
+			}})
+			lc.AddVersion(resolve.Version{VersionKey: npmVK("bin-darwin", "1.0.0")}, nil)
+			lc.AddVersion(resolve.Version{VersionKey: npmVK("bar", "1.0.0")}, nil)
+			var dc client.DependencyClient = localDependencyClient{lc}
+			if tt.platforms {
+				dc = platformDependencyClient{
+					localDependencyClient: localDependencyClient{lc},
+					os:                    map[resolve.VersionKey][]string{npmVK("bin-darwin", "1.0.0"): {"darwin"}},
+				}
+			}
+
+			g := &resolve.Graph{}
+			rootID := g.AddNode(npmVK("root", "1.0.0"))
+			if err := g.AddEdge(rootID, g.AddNode(npmVK("foo", "1.0.0")), "^1.0.0", dep.NewType()); err != nil {
+				t.Fatalf("AddEdge() error = %v", err)
+			}
+			vc := &fakeVulnClient{
+				vulns: map[resolve.VersionKey][]models.Vulnerability{
+					npmVK("foo", "1.0.0"): {npmVuln("GHSA-foo", "foo", "1.0.1")},
+				},
+				lookups: make(map[resolve.VersionKey]int),
+			}
+			res, err := remediation.ComputeInPlacePatches(context.Background(), client.ResolutionClient{DependencyClient: dc, VulnerabilityClient: vc}, g, remediation.RemediationOptions{})
+			if err != nil {
+				t.Fatalf("ComputeInPlacePatches() error = %v", err)
+			}
+
+			got := []string{}
+			for _, p := range res.Patches {
+				got = append(got, p.Pkg.Name+"@"+p.OrigVersion+" -> "+p.NewVersion)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ComputeInPlacePatches() patches = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
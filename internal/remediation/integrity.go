@@ -0,0 +1,41 @@
+package remediation
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/google/osv-scanner/internal/resolution"
+	"github.com/google/osv-scanner/internal/resolution/client"
+	"github.com/google/osv-scanner/internal/resolution/lockfile"
+	lf "github.com/google/osv-scanner/pkg/lockfile"
+)
+
+// ErrIntegrityMismatch is returned when the integrity hashes of packages in a lockfile do not match the registry's.
+var ErrIntegrityMismatch = errors.New("lockfile integrity hashes do not match the registry, the lockfile may have been tampered with")
+
+// CheckLockfileIntegrity verifies the integrity hashes recorded in the lockfile at path against the registry,
+// returning ErrIntegrityMismatch along with the diagnostics if any do not match.
+// Lockfile formats that do not record comparable hashes are not checked.
+func CheckLockfileIntegrity(ctx context.Context, cl client.DependencyClient, rw lockfile.LockfileIO, path string) ([]resolution.Diagnostic, error) {
+	ir, ok := rw.(lockfile.IntegrityReader)
+	if !ok {
+		return nil, nil
+	}
+	f, err := lf.OpenLocalDepFile(path)
+	if err != nil {
+		return nil, err
+	}
+	integrities, err := ir.Integrities(f)
+	f.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	diags := resolution.VerifyIntegrity(ctx, cl, integrities)
+	if slices.ContainsFunc(diags, func(d resolution.Diagnostic) bool { return d.Kind == resolution.DiagnosticIntegrityMismatch }) {
+		return diags, ErrIntegrityMismatch
+	}
+
+	return diags, nil
+}
@@ -0,0 +1,132 @@
+package remediation
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"deps.dev/util/resolve"
+	"deps.dev/util/resolve/dep"
+	"deps.dev/util/semver"
+	"github.com/google/osv-scanner/internal/resolution"
+	"github.com/google/osv-scanner/internal/resolution/client"
+	"github.com/google/osv-scanner/internal/resolution/util"
+	"github.com/google/osv-scanner/internal/utility/vulns"
+)
+
+// ComputeOverridePatches attempts to fix each vulnerability found in result independently by
+// forcing the vulnerable packages to a fixed version (e.g. with npm "overrides" or yarn "resolutions").
+// This can fix vulnerabilities where an intermediate dependency pins the vulnerable version,
+// which cannot be fixed in-place or by relaxing the direct dependencies.
+func ComputeOverridePatches(ctx context.Context, cl client.ResolutionClient, result *resolution.ResolutionResult, opts RemediationOptions) ([]resolution.ResolutionDiff, error) {
+	// Each attempt re-resolves mostly the same graph concurrently, so share the requests between them
+	cl.DependencyClient = client.NewMemoClient(cl.DependencyClient)
+	// Filter the original result just in case it hasn't been already
+	result.FilterVulns(opts.MatchVuln)
+
+	var allResults []resolution.ResolutionDiff
+	// TODO: This could be parallelized
+	for _, vuln := range result.Vulns {
+		res, err := tryOverrideRemediate(ctx, cl, result, vuln, opts)
+		if errors.Is(err, errOverrideImpossible) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		res.FilterVulns(opts.MatchVuln)
+		allResults = append(allResults, result.CalculateDiff(res))
+	}
+
+	// Sort and remove duplicate patches
+	slices.SortFunc(allResults, func(a, b resolution.ResolutionDiff) int { return a.Compare(b) })
+	allResults = slices.CompactFunc(allResults, func(a, b resolution.ResolutionDiff) bool { return a.Compare(b) == 0 })
+
+	return allResults, nil
+}
+
+var errOverrideImpossible = errors.New("cannot fix vulns by overriding")
+
+func tryOverrideRemediate(
+	ctx context.Context,
+	cl client.ResolutionClient,
+	orig *resolution.ResolutionResult,
+	vuln resolution.ResolutionVuln,
+	opts RemediationOptions,
+) (*resolution.ResolutionResult, error) {
+	// Find every vulnerable version of the packages that need to be forced
+	pkgVersions := make(map[resolve.PackageKey][]resolve.VersionKey)
+	for _, ch := range append(slices.Clone(vuln.ProblemChains), vuln.NonProblemChains...) {
+		vk, _ := ch.EndDependency()
+		if !slices.Contains(pkgVersions[vk.PackageKey], vk) {
+			pkgVersions[vk.PackageKey] = append(pkgVersions[vk.PackageKey], vk)
+		}
+	}
+
+	var diags resolution.Diagnostics
+	manif := orig.Manifest.Clone()
+	for pk, vks := range pkgVersions {
+		// Local packages (e.g. npm workspaces) and packages we want to avoid changing cannot be overridden
+		if slices.Contains(opts.AvoidPkgs, pk.Name) || manif.LocalManifestIndex(pk) >= 0 {
+			return nil, errOverrideImpossible
+		}
+
+		newVK, err := findFixedVersion(ctx, cl, pk, opts.AllowPrerelease, func(newVK resolve.VersionKey) bool {
+			// Check if the new version is allowed by the package's pinned version
+			if !opts.matchPin(newVK) {
+				return false
+			}
+			// Check if this is a disallowed major version bump from any of the vulnerable versions
+			if !opts.AllowMajor {
+				for _, vk := range vks {
+					_, diff, err := util.Semver(vk.System).Difference(vk.Version, newVK.Version)
+					if err != nil || diff == semver.DiffMajor {
+						return false
+					}
+				}
+			}
+
+			// Check if the new version supports the project's runtime engines
+			if !enginesSatisfied(ctx, cl.DependencyClient, newVK, opts, &diags) {
+				return false
+			}
+
+			return !vulns.IsAffected(vuln.Vulnerability, util.VKToPackageDetails(newVK))
+		})
+		if errors.Is(err, errInPlaceImpossible) {
+			return nil, errOverrideImpossible
+		} else if err != nil {
+			return nil, err
+		}
+
+		override := resolve.RequirementVersion{
+			Type: dep.NewType(),
+			VersionKey: resolve.VersionKey{
+				PackageKey:  pk,
+				Version:     newVK.Version,
+				VersionType: resolve.Requirement,
+			},
+		}
+		if idx := slices.IndexFunc(manif.Overrides, func(rv resolve.RequirementVersion) bool { return rv.PackageKey == pk }); idx >= 0 {
+			manif.Overrides[idx] = override
+		} else {
+			manif.Overrides = append(manif.Overrides, override)
+		}
+	}
+
+	// re-resolve the manifest with the forced versions, making sure the graph still resolves
+	newRes, err := resolution.Resolve(ctx, cl, manif)
+	if err != nil {
+		return nil, errOverrideImpossible
+	}
+
+	// Check that forcing the versions actually removed the vulnerability
+	if slices.ContainsFunc(newRes.UnfilteredVulns, func(rv resolution.ResolutionVuln) bool {
+		return rv.Vulnerability.ID == vuln.Vulnerability.ID
+	}) {
+		return nil, errOverrideImpossible
+	}
+	newRes.Diagnostics = append(newRes.Diagnostics, diags.List()...)
+
+	return newRes, nil
+}
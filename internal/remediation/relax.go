@@ -13,6 +13,8 @@ import (
 
 // ComputeRelaxPatches attempts to resolve each vulnerability found in result independently, returning the list of unique possible patches
 func ComputeRelaxPatches(ctx context.Context, cl client.ResolutionClient, result *resolution.ResolutionResult, opts RemediationOptions) ([]resolution.ResolutionDiff, error) {
+	// Each attempt re-resolves mostly the same graph concurrently, so share the requests between them
+	cl.DependencyClient = client.NewMemoClient(cl.DependencyClient)
 	// Filter the original result just in case it hasn't been already
 	result.FilterVulns(opts.MatchVuln)
 
@@ -86,12 +88,19 @@ func tryRelaxRemediate(
 	}
 
 	newRes := orig
-	toRelax := reqsToRelax(newRes, vulnIDs, opts)
+	toRelax, ok := reqsToRelax(newRes, vulnIDs, opts)
+	if !ok {
+		return nil, errRelaxRemediateImpossible
+	}
 	for len(toRelax) > 0 {
 		// Try relaxing all necessary requirements
 		manif := newRes.Manifest.Clone()
 		for _, idx := range toRelax {
-			rv := manif.Requirements[idx]
+			reqs := manif.Requirements
+			if idx.local >= 0 {
+				reqs = manif.LocalManifests[idx.local].Requirements
+			}
+			rv := reqs[idx.req]
 			// If we'd need to relax a package we want to avoid changing, we cannot fix the vuln
 			if slices.Contains(opts.AvoidPkgs, rv.Name) {
 				return nil, errRelaxRemediateImpossible
@@ -100,7 +109,7 @@ func tryRelaxRemediate(
 			if !ok {
 				return nil, errRelaxRemediateImpossible
 			}
-			manif.Requirements[idx] = newVer
+			reqs[idx.req] = newVer
 		}
 
 		// re-resolve relaxed manifest
@@ -108,14 +117,55 @@ func tryRelaxRemediate(
 		if err != nil {
 			return nil, err
 		}
-		toRelax = reqsToRelax(newRes, vulnIDs, opts)
+		// If relaxing moved any packages outside of their pinned versions, we cannot fix the vuln
+		if !pinsSatisfied(orig.Graph, newRes.Graph, opts) {
+			return nil, errRelaxRemediateImpossible
+		}
+		toRelax, ok = reqsToRelax(newRes, vulnIDs, opts)
+		if !ok {
+			return nil, errRelaxRemediateImpossible
+		}
 	}
 
 	return newRes, nil
 }
 
-func reqsToRelax(res *resolution.ResolutionResult, vulnIDs []string, opts RemediationOptions) []int {
-	toRelax := make(map[resolve.VersionKey]string)
+// pinsSatisfied checks if the versions in newGraph that were not already in origGraph satisfy the pinned versions.
+func pinsSatisfied(origGraph, newGraph *resolve.Graph, opts RemediationOptions) bool {
+	if len(opts.PinnedVersions) == 0 {
+		return true
+	}
+
+	origVKs := make(map[resolve.VersionKey]struct{})
+	for _, n := range origGraph.Nodes {
+		origVKs[n.Version] = struct{}{}
+	}
+	for _, n := range newGraph.Nodes[1:] { // skipping the root node
+		if _, ok := origVKs[n.Version]; ok {
+			continue
+		}
+		if !opts.matchPin(n.Version) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// relaxIdx identifies a requirement in a manifest (or one of its local manifests) that needs relaxing.
+type relaxIdx struct {
+	local int // index into Manifest.LocalManifests, or -1 for the root manifest
+	req   int // index into the manifest's Requirements
+}
+
+// reqsToRelax finds the requirements that need to be relaxed to remove the vulnerabilities.
+// Returns false if any of the vulnerabilities cannot be fixed by relaxing.
+func reqsToRelax(res *resolution.ResolutionResult, vulnIDs []string, opts RemediationOptions) ([]relaxIdx, bool) {
+	type localReq struct {
+		local int
+		vk    resolve.VersionKey
+	}
+	toRelax := make(map[localReq]string)
 	for _, v := range res.Vulns {
 		// Don't do a full opts.MatchVuln() since we know we don't need to check every condition
 		if !slices.Contains(vulnIDs, v.Vulnerability.ID) || (!opts.DevDeps && v.DevOnly) {
@@ -123,21 +173,41 @@ func reqsToRelax(res *resolution.ResolutionResult, vulnIDs []string, opts Remedi
 		}
 		// Only relax dependencies if their chain length is less than MaxDepth
 		for _, ch := range v.ProblemChains {
-			if opts.MaxDepth <= 0 || len(ch.Edges) <= opts.MaxDepth {
-				vk, req := ch.DirectDependency()
-				toRelax[vk] = req
+			if opts.MaxDepth > 0 && len(ch.Edges) > opts.MaxDepth {
+				continue
+			}
+			// Local packages (e.g. npm workspaces) are immovable - the vulnerability cannot be fixed by changing versions
+			if vk, _ := ch.EndDependency(); res.Manifest.LocalManifestIndex(vk.PackageKey) >= 0 {
+				return nil, false
 			}
+			// Walk down the chain past any local packages.
+			// Their requirements are relaxed in their own manifest, rather than the root manifest's requirement on them.
+			local := -1
+			i := len(ch.Edges) - 1
+			for ; i > 0; i-- {
+				idx := res.Manifest.LocalManifestIndex(ch.Graph.Nodes[ch.Edges[i].To].Version.PackageKey)
+				if idx < 0 {
+					break
+				}
+				local = idx
+			}
+			edge := ch.Edges[i]
+			toRelax[localReq{local: local, vk: ch.Graph.Nodes[edge.To].Version}] = edge.Requirement
 		}
 	}
 
 	// Find the index into the Manifest.Requirements of each that needs to be relaxed
-	reqIdxs := make([]int, 0, len(toRelax))
-	for vk, req := range toRelax {
-		idx := slices.IndexFunc(res.Manifest.Requirements, func(rv resolve.RequirementVersion) bool {
-			return rv.PackageKey == vk.PackageKey && rv.Version == req
+	reqIdxs := make([]relaxIdx, 0, len(toRelax))
+	for lr, req := range toRelax {
+		reqs := res.Manifest.Requirements
+		if lr.local >= 0 {
+			reqs = res.Manifest.LocalManifests[lr.local].Requirements
+		}
+		idx := slices.IndexFunc(reqs, func(rv resolve.RequirementVersion) bool {
+			return rv.PackageKey == lr.vk.PackageKey && rv.Version == req
 		})
-		reqIdxs = append(reqIdxs, idx)
+		reqIdxs = append(reqIdxs, relaxIdx{local: lr.local, req: idx})
 	}
 
-	return reqIdxs
+	return reqIdxs, true
 }
@@ -0,0 +1,15 @@
+package relaxer
+
+import (
+	"context"
+
+	"deps.dev/util/resolve"
+)
+
+type CargoRelaxer struct{}
+
+func (r CargoRelaxer) Relax(ctx context.Context, cl resolve.Client, req resolve.RequirementVersion, allowMajor bool) (resolve.RequirementVersion, bool) {
+	// Cargo's requirement syntax is close enough to npm's that the same relaxation applies,
+	// a bare version "1.2.3" is a caret requirement in Cargo, which is always relaxed to a wider range.
+	return relaxSemverRange(ctx, cl, req, allowMajor)
+}
@@ -0,0 +1,62 @@
+package relaxer
+
+import (
+	"context"
+
+	"deps.dev/util/resolve"
+	"golang.org/x/mod/semver"
+)
+
+type GoRelaxer struct{}
+
+func (r GoRelaxer) Relax(ctx context.Context, cl resolve.Client, req resolve.RequirementVersion, allowMajor bool) (resolve.RequirementVersion, bool) {
+	if !semver.IsValid(req.Version) {
+		return req, false
+	}
+
+	allVKs, err := cl.Versions(ctx, req.PackageKey)
+	if err != nil {
+		return req, false
+	}
+
+	// Go requirements are minimum versions, so relax by picking increasingly larger version changes:
+	// v1.2.3 -> v1.2.(latest) -> v1.(latest).x
+	// Major versions >= 2 are different module paths (e.g. example.com/mod/v2), so cannot be relaxed into.
+	// The only major change possible is from v0 to v1.
+	var bestPatch, bestMinor, bestMajor string
+	for _, vk := range allVKs {
+		v := vk.Version
+		if vk.VersionType != resolve.Concrete || semver.Prerelease(v) != "" || semver.Compare(v, req.Version) <= 0 {
+			continue
+		}
+		switch {
+		case semver.MajorMinor(v) == semver.MajorMinor(req.Version):
+			bestPatch = semverMax(bestPatch, v)
+		case semver.Major(v) == semver.Major(req.Version):
+			bestMinor = semverMax(bestMinor, v)
+		case semver.Major(req.Version) == "v0" && semver.Major(v) == "v1":
+			bestMajor = semverMax(bestMajor, v)
+		}
+	}
+
+	switch {
+	case bestPatch != "":
+		req.Version = bestPatch
+	case bestMinor != "":
+		req.Version = bestMinor
+	case bestMajor != "" && allowMajor:
+		req.Version = bestMajor
+	default:
+		return req, false
+	}
+
+	return req, true
+}
+
+func semverMax(a, b string) string {
+	if a == "" || semver.Compare(b, a) > 0 {
+		return b
+	}
+
+	return a
+}
@@ -0,0 +1,86 @@
+package relaxer
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"deps.dev/util/resolve"
+	"deps.dev/util/semver"
+	"github.com/google/osv-scanner/internal/resolution/util"
+)
+
+type MavenRelaxer struct{}
+
+func (r MavenRelaxer) Relax(ctx context.Context, cl resolve.Client, req resolve.RequirementVersion, allowMajor bool) (resolve.RequirementVersion, bool) {
+	return relaxSoftVersion(ctx, cl, req, allowMajor)
+}
+
+// relaxSoftVersion relaxes requirements in ecosystems where a plain version is a minimum or preferred version
+// rather than a range (i.e. Maven and NuGet), by bumping the version.
+func relaxSoftVersion(ctx context.Context, cl resolve.Client, req resolve.RequirementVersion, allowMajor bool) (resolve.RequirementVersion, bool) {
+	// TODO: version ranges e.g. "[1.2,2.0)"
+	if req.Version == "" || strings.ContainsAny(req.Version, "[(,)]") {
+		return req, false
+	}
+	sys := util.Semver(req.System)
+	if _, err := sys.Parse(req.Version); err != nil {
+		return req, false
+	}
+
+	// Get all the concrete versions of the package
+	allVKs, err := cl.Versions(ctx, req.PackageKey)
+	if err != nil {
+		return req, false
+	}
+	var vers []string
+	for _, vk := range allVKs {
+		if vk.VersionType != resolve.Concrete {
+			continue
+		}
+		v, err := sys.Parse(vk.Version)
+		if err != nil || v.IsPrerelease() || sys.Compare(vk.Version, req.Version) <= 0 {
+			continue
+		}
+		vers = append(vers, vk.Version)
+	}
+	if len(vers) == 0 {
+		return req, false
+	}
+	slices.SortFunc(vers, sys.Compare)
+
+	// Maven requirements are (soft) pinned versions, so relax by picking increasingly larger version changes:
+	// 1.2.3 -> 1.2.(latest) -> 1.(latest).x -> 2.(latest).x -> 3.(latest).x -> ...
+	_, diff, err := sys.Difference(req.Version, vers[0])
+	if err != nil {
+		return req, false
+	}
+	if diff == semver.DiffMajor && !allowMajor {
+		return req, false
+	}
+
+	// Find the highest version with the same difference
+	cmpVer := req.Version
+	if diff == semver.DiffMajor {
+		// Want to step only one major version at a time
+		cmpVer = vers[0]
+		diff = semver.DiffMinor
+	}
+	best := vers[0]
+	for _, v := range vers[1:] {
+		_, d, err := sys.Difference(cmpVer, v)
+		if err != nil {
+			continue
+		}
+		// DiffMajor < DiffMinor < DiffPatch < DiffPrerelease
+		// So if d is less than the original diff, it represents a larger change
+		if d < diff {
+			break
+		}
+		best = v
+	}
+
+	req.Version = best
+
+	return req, true
+}
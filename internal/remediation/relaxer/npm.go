@@ -6,12 +6,19 @@ import (
 
 	"deps.dev/util/resolve"
 	"deps.dev/util/semver"
+	"github.com/google/osv-scanner/internal/resolution/util"
 )
 
 type NpmRelaxer struct{}
 
 func (r NpmRelaxer) Relax(ctx context.Context, cl resolve.Client, req resolve.RequirementVersion, allowMajor bool) (resolve.RequirementVersion, bool) {
-	c, err := semver.NPM.ParseConstraint(req.Version)
+	return relaxSemverRange(ctx, cl, req, allowMajor)
+}
+
+// relaxSemverRange relaxes requirements in ecosystems that use npm-like caret & tilde ranges (i.e. npm and Cargo).
+func relaxSemverRange(ctx context.Context, cl resolve.Client, req resolve.RequirementVersion, allowMajor bool) (resolve.RequirementVersion, bool) {
+	sys := util.Semver(req.System)
+	c, err := sys.ParseConstraint(req.Version)
 	if err != nil {
 		// The specified version is not a valid semver constraint
 		// Check if it's a version tag (usually 'latest') by seeing if there are matching versions
@@ -20,7 +27,7 @@ func (r NpmRelaxer) Relax(ctx context.Context, cl resolve.Client, req resolve.Re
 			return req, false
 		}
 		// Use the first matching version (there should only be one) as a pinned version
-		c, err = semver.NPM.ParseConstraint(vks[0].Version)
+		c, err = sys.ParseConstraint(vks[0].Version)
 		if err != nil {
 			return req, false
 		}
@@ -37,14 +44,14 @@ func (r NpmRelaxer) Relax(ctx context.Context, cl resolve.Client, req resolve.Re
 			vers = append(vers, vk.Version)
 		}
 	}
-	slices.SortFunc(vers, semver.NPM.Compare)
+	slices.SortFunc(vers, sys.Compare)
 
 	// Find the versions on either side of the upper boundary of the requirement
 	var lastIdx int      // highest version matching constraint
 	var nextIdx int = -1 // next version outside of range, preferring non-prerelease
 	nextIsPre := true    // if the next version is a prerelease version
 	for lastIdx = len(vers) - 1; lastIdx >= 0; lastIdx-- {
-		v, err := semver.NPM.Parse(vers[lastIdx])
+		v, err := sys.Parse(vers[lastIdx])
 		if err != nil {
 			continue
 		}
@@ -76,7 +83,7 @@ func (r NpmRelaxer) Relax(ctx context.Context, cl resolve.Client, req resolve.Re
 	// using the latest versions of the ranges
 
 	cmpVer := vers[lastIdx]
-	_, diff, _ := semver.NPM.Difference(cmpVer, vers[nextIdx])
+	_, diff, _ := sys.Difference(cmpVer, vers[nextIdx])
 	if diff == semver.DiffMajor {
 		if !allowMajor {
 			return req, false
@@ -91,7 +98,7 @@ func (r NpmRelaxer) Relax(ctx context.Context, cl resolve.Client, req resolve.Re
 	// Find the highest version with the same difference
 	best := vers[nextIdx]
 	for i := nextIdx + 1; i < len(vers); i++ {
-		_, d, err := semver.NPM.Difference(cmpVer, vers[i])
+		_, d, err := sys.Difference(cmpVer, vers[i])
 		if err != nil {
 			continue
 		}
@@ -100,7 +107,7 @@ func (r NpmRelaxer) Relax(ctx context.Context, cl resolve.Client, req resolve.Re
 		if d < diff {
 			break
 		}
-		ver, err := semver.NPM.Parse(vers[i])
+		ver, err := sys.Parse(vers[i])
 		if err != nil {
 			continue
 		}
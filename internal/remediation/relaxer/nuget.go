@@ -0,0 +1,15 @@
+package relaxer
+
+import (
+	"context"
+
+	"deps.dev/util/resolve"
+)
+
+type NuGetRelaxer struct{}
+
+func (r NuGetRelaxer) Relax(ctx context.Context, cl resolve.Client, req resolve.RequirementVersion, allowMajor bool) (resolve.RequirementVersion, bool) {
+	// A plain NuGet version "1.2.3" is a minimum version, but NuGet selects the lowest applicable version,
+	// so it has to be bumped like Maven's soft requirements.
+	return relaxSoftVersion(ctx, cl, req, allowMajor)
+}
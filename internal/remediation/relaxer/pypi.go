@@ -0,0 +1,94 @@
+package relaxer
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"deps.dev/util/resolve"
+	"deps.dev/util/semver"
+)
+
+type PyPIRelaxer struct{}
+
+func (r PyPIRelaxer) Relax(ctx context.Context, cl resolve.Client, req resolve.RequirementVersion, allowMajor bool) (resolve.RequirementVersion, bool) {
+	if req.Version == "" {
+		// Any version is already allowed
+		return req, false
+	}
+	c, err := semver.PyPI.ParseConstraint(req.Version)
+	if err != nil {
+		return req, false
+	}
+
+	// Get all the concrete, non-prerelease versions of the package
+	allVKs, err := cl.Versions(ctx, req.PackageKey)
+	if err != nil {
+		return req, false
+	}
+	var vers []string
+	for _, vk := range allVKs {
+		if vk.VersionType != resolve.Concrete {
+			continue
+		}
+		if v, err := semver.PyPI.Parse(vk.Version); err == nil && !v.IsPrerelease() {
+			vers = append(vers, vk.Version)
+		}
+	}
+	slices.SortFunc(vers, semver.PyPI.Compare)
+
+	// Find the versions on either side of the upper boundary of the requirement
+	lastIdx := len(vers) - 1
+	for ; lastIdx >= 0; lastIdx-- {
+		if v, err := semver.PyPI.Parse(vers[lastIdx]); err == nil && c.MatchVersion(v) {
+			break
+		}
+	}
+	nextIdx := lastIdx + 1
+	if lastIdx == -1 || nextIdx >= len(vers) {
+		// No versions match the existing requirement, or there are no higher versions
+		return req, false
+	}
+
+	// Our desired relaxation ordering is
+	// ==1.2.3 -> >=1.2.4,==1.2.* -> >=1.4.5,==1.* -> >=2.6.7,==2.* -> ...
+	// using the latest versions of the ranges
+	cmpVer := vers[lastIdx]
+	_, diff, _ := semver.PyPI.Difference(cmpVer, vers[nextIdx])
+	if diff == semver.DiffMajor {
+		if !allowMajor {
+			return req, false
+		}
+		// Want to step only one major version at a time
+		cmpVer = vers[nextIdx]
+		diff = semver.DiffMinor
+	}
+
+	// Find the highest version with the same difference
+	best := vers[nextIdx]
+	for _, v := range vers[nextIdx+1:] {
+		_, d, err := semver.PyPI.Difference(cmpVer, v)
+		if err != nil {
+			continue
+		}
+		// DiffMajor < DiffMinor < DiffPatch < DiffPrerelease
+		// So if d is less than the original diff, it represents a larger change
+		if d < diff {
+			break
+		}
+		best = v
+	}
+
+	prefixLen := 1
+	if diff == semver.DiffPatch {
+		prefixLen = 2
+	}
+	parts := strings.Split(best, ".")
+	if len(parts) <= prefixLen {
+		req.Version = ">=" + best
+	} else {
+		req.Version = ">=" + best + ",==" + strings.Join(parts[:prefixLen], ".") + ".*"
+	}
+
+	return req, true
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 
 	"deps.dev/util/resolve"
+	"github.com/google/osv-scanner/internal/resolution/util"
 )
 
 // A RequirementRelaxer provides an ecosystem-specific method for 'relaxing' the
@@ -28,6 +29,16 @@ func GetRelaxer(ecosystem resolve.System) (RequirementRelaxer, error) {
 	switch ecosystem { //nolint:exhaustive
 	case resolve.NPM:
 		return NpmRelaxer{}, nil
+	case resolve.Maven:
+		return MavenRelaxer{}, nil
+	case util.PyPI:
+		return PyPIRelaxer{}, nil
+	case util.Go:
+		return GoRelaxer{}, nil
+	case util.Cargo:
+		return CargoRelaxer{}, nil
+	case util.NuGet:
+		return NuGetRelaxer{}, nil
 	default:
 		return nil, errors.New("unsupported ecosystem")
 	}
@@ -1,11 +1,18 @@
 package remediation
 
 import (
+	"cmp"
+	"errors"
+	"fmt"
 	"math"
 	"slices"
 
+	"deps.dev/util/resolve"
 	"github.com/google/osv-scanner/internal/resolution"
+	"github.com/google/osv-scanner/internal/resolution/util"
 	"github.com/google/osv-scanner/internal/utility/severity"
+	"github.com/google/osv-scanner/pkg/config"
+	"golang.org/x/exp/maps"
 )
 
 type RemediationOptions struct {
@@ -16,8 +23,17 @@ type RemediationOptions struct {
 	MinSeverity float64 // Minimum vulnerability CVSS score to consider
 	MaxDepth    int     // Maximum depth of dependency to consider vulnerabilities for (e.g. 1 for direct only)
 
-	AvoidPkgs  []string // Names of dependencies to avoid upgrading
-	AllowMajor bool     // Whether to allow changes to major versions of direct dependencies
+	AvoidPkgs       []string                 // Names of dependencies to avoid upgrading
+	PinnedVersions  map[config.PinKey]string // Dependencies mapped to version constraints their new versions must satisfy
+	AllowMajor      bool                     // Whether to allow changes to major versions of direct dependencies
+	AllowPrerelease bool                     // Whether to allow upgrading to prerelease versions if no other fix is available
+
+	Engines       map[string]string // The project's runtime engine requirements e.g. npm's {"node": ">=18"}
+	IgnoreEngines bool              // Whether to allow new versions with runtime engine requirements incompatible with Engines
+
+	VerifyIntegrity bool // Whether to check the integrity hashes in lockfiles match the registry before remediating them
+
+	AllChains bool // Whether in-place remediation considers every dependency chain of vulnerable packages, instead of only the shortest through each dependent
 }
 
 func (opts RemediationOptions) MatchVuln(v resolution.ResolutionVuln) bool {
@@ -37,19 +53,101 @@ func (opts RemediationOptions) MatchVuln(v resolution.ResolutionVuln) bool {
 }
 
 func (opts RemediationOptions) matchSeverity(v resolution.ResolutionVuln) bool {
+	maxScore, _ := maxSeverity(v)
+
+	// CVSS scores are meant to only be to 1 decimal place
+	// and we want to avoid something being falsely rejected/included due to floating point precision.
+	// Multiply and round to only consider relevant parts of the score.
+	return math.Round(10*maxScore) >= math.Round(10*opts.MinSeverity) ||
+		maxScore < 0 // Always include vulns with unknown severities
+}
+
+// pin returns the pinned version constraint of a package, if it has one.
+// A pin for the package's ecosystem takes precedence over a pin of the same name without an ecosystem.
+func (opts RemediationOptions) pin(pk resolve.PackageKey) (string, bool) {
+	if pin, ok := opts.PinnedVersions[config.PinKey{Ecosystem: string(util.OSVEcosystem[pk.System]), Name: pk.Name}]; ok {
+		return pin, true
+	}
+	pin, ok := opts.PinnedVersions[config.PinKey{Name: pk.Name}]
+
+	return pin, ok
+}
+
+// matchPin checks if a version satisfies its package's pinned version constraint, if it has one.
+// Versions never satisfy an unparseable pin, which CheckPins reports before remediating.
+func (opts RemediationOptions) matchPin(vk resolve.VersionKey) bool {
+	pin, ok := opts.pin(vk.PackageKey)
+	if !ok {
+		return true
+	}
+	c, err := util.Semver(vk.System).ParseConstraint(pin)
+	if err != nil {
+		return false
+	}
+
+	return c.Match(vk.Version)
+}
+
+// CheckPins checks that the pinned version constraints can be parsed in their ecosystems.
+// Pins without an ecosystem must be parseable in at least one ecosystem.
+// The errors are in order of ecosystem and package name, so the same pins are always reported the same way.
+func CheckPins(pins map[config.PinKey]string) error {
+	keys := maps.Keys(pins)
+	slices.SortFunc(keys, func(a, b config.PinKey) int {
+		if c := cmp.Compare(a.Ecosystem, b.Ecosystem); c != 0 {
+			return c
+		}
+
+		return cmp.Compare(a.Name, b.Name)
+	})
+	systems := maps.Keys(util.OSVEcosystem)
+	slices.SortFunc(systems, func(a, b resolve.System) int { return cmp.Compare(util.OSVEcosystem[a], util.OSVEcosystem[b]) })
+
+	var errs []error
+	for _, key := range keys {
+		pin := pins[key]
+		var ok, tried bool
+		var parseErr error
+		for _, sys := range systems {
+			if key.Ecosystem != "" && key.Ecosystem != string(util.OSVEcosystem[sys]) {
+				continue
+			}
+			tried = true
+			_, err := util.Semver(sys).ParseConstraint(pin)
+			if err == nil {
+				ok = true
+				break
+			}
+			if parseErr == nil {
+				parseErr = err
+			}
+		}
+		switch {
+		case ok:
+		case !tried:
+			errs = append(errs, fmt.Errorf("pinned version of %s: unsupported ecosystem %q", key.Name, key.Ecosystem))
+		default:
+			errs = append(errs, fmt.Errorf("pinned version of %s: %w", key.Name, parseErr))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// maxSeverity returns the highest CVSS score and its rating of a vulnerability.
+// The score is negative and the rating is "UNKNOWN" if the severity is unknown.
+func maxSeverity(v resolution.ResolutionVuln) (float64, string) {
 	maxScore := -1.0
+	maxRating := "UNKNOWN"
 	// TODO: also check Vulnerability.Affected[].Severity
 	for _, sev := range v.Vulnerability.Severity {
-		if score, _, _ := severity.CalculateScore(sev); score > maxScore {
+		if score, rating, _ := severity.CalculateScore(sev); score > maxScore {
 			maxScore = score
+			maxRating = rating
 		}
 	}
 
-	// CVSS scores are meant to only be to 1 decimal place
-	// and we want to avoid something being falsely rejected/included due to floating point precision.
-	// Multiply and round to only consider relevant parts of the score.
-	return math.Round(10*maxScore) >= math.Round(10*opts.MinSeverity) ||
-		maxScore < 0 // Always include vulns with unknown severities
+	return maxScore, maxRating
 }
 
 func (opts RemediationOptions) matchDepth(v resolution.ResolutionVuln) bool {
@@ -0,0 +1,93 @@
+package remediation_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/osv-scanner/internal/remediation"
+	"github.com/google/osv-scanner/pkg/config"
+)
+
+func TestCheckPins(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		pins    map[config.PinKey]string
+		wantErr bool
+	}{
+		{
+			name:    "no pins",
+			pins:    nil,
+			wantErr: false,
+		},
+		{
+			name: "valid pins",
+			pins: map[config.PinKey]string{
+				{Name: "lodash"}:                          "4.x",
+				{Ecosystem: "npm", Name: "express"}:       "4.18.2",
+				{Ecosystem: "PyPI", Name: "requests"}:     ">=2.0,<3",
+				{Ecosystem: "Maven", Name: "junit:junit"}: "[4.0,5.0)",
+			},
+			wantErr: false,
+		},
+		{
+			name: "unparseable pin",
+			pins: map[config.PinKey]string{
+				{Ecosystem: "npm", Name: "express"}: "=<>1",
+			},
+			wantErr: true,
+		},
+		{
+			name: "unsupported ecosystem",
+			pins: map[config.PinKey]string{
+				{Ecosystem: "Hackage", Name: "aeson"}: "2.x",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if err := remediation.CheckPins(tt.pins); (err != nil) != tt.wantErr {
+				t.Errorf("CheckPins() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckPins_Order(t *testing.T) {
+	t.Parallel()
+
+	pins := map[config.PinKey]string{
+		{Ecosystem: "npm", Name: "express"}:   "=<>1",
+		{Ecosystem: "Hackage", Name: "aeson"}: "2.x",
+		{Ecosystem: "npm", Name: "chalk"}:     "=<>2",
+		{Name: "lodash"}:                      "=<>3",
+		{Ecosystem: "Hackage", Name: "text"}:  "1.x",
+	}
+	err := remediation.CheckPins(pins)
+	if err == nil {
+		t.Fatalf("CheckPins() error = nil, want error")
+	}
+
+	// the errors are ordered by ecosystem, then package name
+	var names []string
+	for _, line := range strings.Split(err.Error(), "\n") {
+		name, _, _ := strings.Cut(strings.TrimPrefix(line, "pinned version of "), ":")
+		names = append(names, name)
+	}
+	if want := []string{"lodash", "aeson", "text", "chalk", "express"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("CheckPins() errors are for %v, want %v", names, want)
+	}
+
+	// and every ecosystem is tried in the same order, so the same error is reported each time
+	for i := 0; i < 20; i++ {
+		if got := remediation.CheckPins(pins); got.Error() != err.Error() {
+			t.Fatalf("CheckPins() error = %q, previously %q", got, err)
+		}
+	}
+}
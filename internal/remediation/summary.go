@@ -0,0 +1,171 @@
+package remediation
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/google/osv-scanner/internal/resolution"
+)
+
+// severityRatings are the possible vulnerability severity ratings, from most to least severe.
+var severityRatings = []string{"CRITICAL", "HIGH", "MEDIUM", "LOW", "NONE", "UNKNOWN"}
+
+// SeverityCount is the number of vulnerabilities of a severity rating before and after remediation.
+type SeverityCount struct {
+	Severity string `json:"severity"`
+	Before   int    `json:"before"`
+	After    int    `json:"after"`
+}
+
+// RemediationSummary compares the vulnerabilities present before and after applying remediation patches.
+type RemediationSummary struct {
+	Severities        []SeverityCount    `json:"severities"`
+	Fixed             []string           `json:"fixed"`      // IDs of vulnerabilities removed by the patches
+	Remaining         []string           `json:"remaining"`  // IDs of vulnerabilities present both before and after
+	Introduced        []string           `json:"introduced"` // IDs of vulnerabilities added by the patches
+	DependencyChanges int                `json:"dependencyChanges"`
+	Upgrades          []UpgradeSummary   `json:"upgrades,omitempty"` // The version changes made in-place, if they are known
+	EPSS              map[string]float64 `json:"epss,omitempty"`     // EPSS exploit probabilities of the vulnerabilities, keyed by ID, if known
+}
+
+// UpgradeSummary is a dependency version change made by the remediation patches.
+type UpgradeSummary struct {
+	Package     string  `json:"package"`
+	OrigVersion string  `json:"origVersion"`
+	NewVersion  string  `json:"newVersion"`
+	Prerelease  bool    `json:"prerelease"` // Whether NewVersion is a prerelease version
+	Confidence  float64 `json:"confidence"` // How unlikely the change is to break things, from 0 to 1
+}
+
+// InPlaceUpgrades summarises the version changes of the in-place patches.
+func InPlaceUpgrades(patches []InPlacePatch) []UpgradeSummary {
+	upgrades := make([]UpgradeSummary, len(patches))
+	for i, p := range patches {
+		upgrades[i] = UpgradeSummary{
+			Package:     p.Pkg.Name,
+			OrigVersion: p.OrigVersion,
+			NewVersion:  p.NewVersion,
+			Prerelease:  p.Prerelease,
+			Confidence:  p.Confidence.Score,
+		}
+	}
+
+	return upgrades
+}
+
+// ComputeSummary summarises the vulnerabilities before and after remediation,
+// where changes is the total number of dependency version changes made by the applied patches.
+func ComputeSummary(before, after []resolution.ResolutionVuln, changes int) RemediationSummary {
+	summary := RemediationSummary{
+		Severities:        make([]SeverityCount, len(severityRatings)),
+		Fixed:             []string{},
+		Remaining:         []string{},
+		Introduced:        []string{},
+		DependencyChanges: changes,
+	}
+	for i, rating := range severityRatings {
+		summary.Severities[i].Severity = rating
+	}
+
+	// The same vulnerability may appear multiple times, only count each ID once
+	beforeIDs := make(map[string]struct{})
+	for _, v := range before {
+		if _, ok := beforeIDs[v.Vulnerability.ID]; ok {
+			continue
+		}
+		beforeIDs[v.Vulnerability.ID] = struct{}{}
+		summary.Severities[severityIndex(v)].Before++
+	}
+
+	afterIDs := make(map[string]struct{})
+	for _, v := range after {
+		if _, ok := afterIDs[v.Vulnerability.ID]; ok {
+			continue
+		}
+		afterIDs[v.Vulnerability.ID] = struct{}{}
+		summary.Severities[severityIndex(v)].After++
+		if _, ok := beforeIDs[v.Vulnerability.ID]; ok {
+			summary.Remaining = append(summary.Remaining, v.Vulnerability.ID)
+		} else {
+			summary.Introduced = append(summary.Introduced, v.Vulnerability.ID)
+		}
+	}
+
+	for id := range beforeIDs {
+		if _, ok := afterIDs[id]; !ok {
+			summary.Fixed = append(summary.Fixed, id)
+		}
+	}
+
+	slices.Sort(summary.Fixed)
+	slices.Sort(summary.Remaining)
+	slices.Sort(summary.Introduced)
+
+	return summary
+}
+
+func severityIndex(v resolution.ResolutionVuln) int {
+	_, rating := maxSeverity(v)
+	if idx := slices.Index(severityRatings, rating); idx >= 0 {
+		return idx
+	}
+
+	return len(severityRatings) - 1 // UNKNOWN
+}
+
+// WriteText writes the summary in a human-readable format.
+func (s RemediationSummary) WriteText(w io.Writer) error {
+	var sb strings.Builder
+	sb.WriteString("Vulnerabilities by severity (before -> after):\n")
+	for _, sc := range s.Severities {
+		if sc.Before == 0 && sc.After == 0 {
+			continue
+		}
+		fmt.Fprintf(&sb, "  %-8s %d -> %d\n", sc.Severity+":", sc.Before, sc.After)
+	}
+	fmt.Fprintf(&sb, "Fixed vulnerabilities (%d):%s\n", len(s.Fixed), s.formatIDs(s.Fixed))
+	fmt.Fprintf(&sb, "Remaining vulnerabilities (%d):%s\n", len(s.Remaining), s.formatIDs(s.Remaining))
+	if len(s.Introduced) > 0 {
+		fmt.Fprintf(&sb, "Introduced vulnerabilities (%d):%s\n", len(s.Introduced), s.formatIDs(s.Introduced))
+	}
+	fmt.Fprintf(&sb, "Dependency version changes: %d\n", s.DependencyChanges)
+	for _, u := range s.Upgrades {
+		fmt.Fprintf(&sb, "  %s: %s -> %s (", u.Package, u.OrigVersion, u.NewVersion)
+		if u.Prerelease {
+			sb.WriteString("prerelease, ")
+		}
+		fmt.Fprintf(&sb, "confidence %.2f)\n", u.Confidence)
+	}
+
+	_, err := io.WriteString(w, sb.String())
+
+	return err
+}
+
+// formatIDs lists the vulnerability IDs, with the EPSS score of each vulnerability that has one.
+func (s RemediationSummary) formatIDs(ids []string) string {
+	if len(ids) == 0 {
+		return ""
+	}
+
+	formatted := make([]string, len(ids))
+	for i, id := range ids {
+		formatted[i] = id
+		if score, ok := s.EPSS[id]; ok {
+			formatted[i] += fmt.Sprintf(" (EPSS %.2f)", score)
+		}
+	}
+
+	return " " + strings.Join(formatted, ", ")
+}
+
+// WriteJSON writes the summary in JSON format.
+func (s RemediationSummary) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(s)
+}
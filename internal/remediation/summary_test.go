@@ -0,0 +1,210 @@
+package remediation_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"deps.dev/util/resolve"
+	"github.com/google/osv-scanner/internal/remediation"
+	"github.com/google/osv-scanner/internal/resolution"
+	lf "github.com/google/osv-scanner/internal/resolution/lockfile"
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+func vulnWithSeverity(id, cvss string) resolution.ResolutionVuln {
+	v := resolution.ResolutionVuln{Vulnerability: models.Vulnerability{ID: id}}
+	if cvss != "" {
+		v.Vulnerability.Severity = []models.Severity{{Type: models.SeverityCVSSV3, Score: cvss}}
+	}
+
+	return v
+}
+
+const (
+	cvssCritical = "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H" // 9.8
+	cvssHigh     = "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:N/I:N/A:H" // 7.5
+	cvssLow      = "CVSS:3.1/AV:N/AC:H/PR:N/UI:R/S:U/C:L/I:N/A:N" // 3.1
+)
+
+func TestComputeSummary(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		before  []resolution.ResolutionVuln
+		after   []resolution.ResolutionVuln
+		changes int
+		want    remediation.RemediationSummary
+	}{
+		{
+			name:    "no vulnerabilities",
+			before:  nil,
+			after:   nil,
+			changes: 0,
+			want: remediation.RemediationSummary{
+				Severities: []remediation.SeverityCount{
+					{Severity: "CRITICAL"}, {Severity: "HIGH"}, {Severity: "MEDIUM"},
+					{Severity: "LOW"}, {Severity: "NONE"}, {Severity: "UNKNOWN"},
+				},
+				Fixed:      []string{},
+				Remaining:  []string{},
+				Introduced: []string{},
+			},
+		},
+		{
+			name: "fixed, remaining and introduced",
+			before: []resolution.ResolutionVuln{
+				vulnWithSeverity("GHSA-aaaa", cvssCritical),
+				vulnWithSeverity("GHSA-bbbb", cvssHigh),
+				// the same vulnerability can be found in multiple packages, it is only counted once
+				vulnWithSeverity("GHSA-bbbb", cvssHigh),
+				vulnWithSeverity("GHSA-cccc", ""),
+			},
+			after: []resolution.ResolutionVuln{
+				vulnWithSeverity("GHSA-bbbb", cvssHigh),
+				vulnWithSeverity("GHSA-dddd", cvssLow),
+			},
+			changes: 3,
+			want: remediation.RemediationSummary{
+				Severities: []remediation.SeverityCount{
+					{Severity: "CRITICAL", Before: 1, After: 0},
+					{Severity: "HIGH", Before: 1, After: 1},
+					{Severity: "MEDIUM"},
+					{Severity: "LOW", Before: 0, After: 1},
+					{Severity: "NONE"},
+					{Severity: "UNKNOWN", Before: 1, After: 0},
+				},
+				Fixed:             []string{"GHSA-aaaa", "GHSA-cccc"},
+				Remaining:         []string{"GHSA-bbbb"},
+				Introduced:        []string{"GHSA-dddd"},
+				DependencyChanges: 3,
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := remediation.ComputeSummary(tt.before, tt.after, tt.changes)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ComputeSummary() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRemediationSummary_WriteText(t *testing.T) {
+	t.Parallel()
+
+	summary := remediation.ComputeSummary(
+		[]resolution.ResolutionVuln{vulnWithSeverity("GHSA-aaaa", cvssCritical), vulnWithSeverity("GHSA-bbbb", cvssHigh)},
+		[]resolution.ResolutionVuln{vulnWithSeverity("GHSA-bbbb", cvssHigh)},
+		1,
+	)
+
+	var sb strings.Builder
+	if err := summary.WriteText(&sb); err != nil {
+		t.Fatalf("WriteText() error = %v", err)
+	}
+
+	want := "Vulnerabilities by severity (before -> after):\n" +
+		"  CRITICAL: 1 -> 0\n" +
+		"  HIGH:    1 -> 1\n" +
+		"Fixed vulnerabilities (1): GHSA-aaaa\n" +
+		"Remaining vulnerabilities (1): GHSA-bbbb\n" +
+		"Dependency version changes: 1\n"
+	if got := sb.String(); got != want {
+		t.Errorf("WriteText() got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRemediationSummary_Upgrades(t *testing.T) {
+	t.Parallel()
+
+	summary := remediation.ComputeSummary(
+		[]resolution.ResolutionVuln{vulnWithSeverity("GHSA-aaaa", cvssCritical), vulnWithSeverity("GHSA-bbbb", cvssHigh)},
+		nil,
+		2,
+	)
+	summary.Upgrades = remediation.InPlaceUpgrades([]remediation.InPlacePatch{
+		{DependencyPatch: lf.DependencyPatch{Pkg: resolve.PackageKey{System: resolve.NPM, Name: "foo"}, OrigVersion: "1.0.0", NewVersion: "1.0.1"}, Confidence: remediation.UpgradeConfidence{Score: 0.95}},
+		{DependencyPatch: lf.DependencyPatch{Pkg: resolve.PackageKey{System: resolve.NPM, Name: "bar"}, OrigVersion: "2.0.0", NewVersion: "2.1.0-rc.1"}, Prerelease: true, Confidence: remediation.UpgradeConfidence{Score: 0.7}},
+	})
+
+	var sb strings.Builder
+	if err := summary.WriteText(&sb); err != nil {
+		t.Fatalf("WriteText() error = %v", err)
+	}
+	want := "Vulnerabilities by severity (before -> after):\n" +
+		"  CRITICAL: 1 -> 0\n" +
+		"  HIGH:    1 -> 0\n" +
+		"Fixed vulnerabilities (2): GHSA-aaaa, GHSA-bbbb\n" +
+		"Remaining vulnerabilities (0):\n" +
+		"Dependency version changes: 2\n" +
+		"  foo: 1.0.0 -> 1.0.1 (confidence 0.95)\n" +
+		"  bar: 2.0.0 -> 2.1.0-rc.1 (prerelease, confidence 0.70)\n"
+	if got := sb.String(); got != want {
+		t.Errorf("WriteText() got:\n%s\nwant:\n%s", got, want)
+	}
+
+	sb.Reset()
+	if err := summary.WriteJSON(&sb); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+	var got struct {
+		Upgrades []remediation.UpgradeSummary `json:"upgrades"`
+	}
+	if err := json.Unmarshal([]byte(sb.String()), &got); err != nil {
+		t.Fatalf("WriteJSON() wrote invalid JSON: %v", err)
+	}
+	wantUpgrades := []remediation.UpgradeSummary{
+		{Package: "foo", OrigVersion: "1.0.0", NewVersion: "1.0.1", Confidence: 0.95},
+		{Package: "bar", OrigVersion: "2.0.0", NewVersion: "2.1.0-rc.1", Prerelease: true, Confidence: 0.7},
+	}
+	if !reflect.DeepEqual(got.Upgrades, wantUpgrades) {
+		t.Errorf("WriteJSON() upgrades = %+v, want %+v", got.Upgrades, wantUpgrades)
+	}
+}
+
+func TestRemediationSummary_EPSS(t *testing.T) {
+	t.Parallel()
+
+	summary := remediation.ComputeSummary(
+		[]resolution.ResolutionVuln{vulnWithSeverity("GHSA-aaaa", cvssCritical), vulnWithSeverity("GHSA-bbbb", cvssHigh)},
+		[]resolution.ResolutionVuln{vulnWithSeverity("GHSA-bbbb", cvssHigh)},
+		1,
+	)
+	// GHSA-bbbb has no CVE alias, so its score is not known
+	summary.EPSS = map[string]float64{"GHSA-aaaa": 0.9731}
+
+	var sb strings.Builder
+	if err := summary.WriteText(&sb); err != nil {
+		t.Fatalf("WriteText() error = %v", err)
+	}
+	want := "Vulnerabilities by severity (before -> after):\n" +
+		"  CRITICAL: 1 -> 0\n" +
+		"  HIGH:    1 -> 1\n" +
+		"Fixed vulnerabilities (1): GHSA-aaaa (EPSS 0.97)\n" +
+		"Remaining vulnerabilities (1): GHSA-bbbb\n" +
+		"Dependency version changes: 1\n"
+	if got := sb.String(); got != want {
+		t.Errorf("WriteText() got:\n%s\nwant:\n%s", got, want)
+	}
+
+	sb.Reset()
+	if err := summary.WriteJSON(&sb); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+	var got struct {
+		EPSS map[string]float64 `json:"epss"`
+	}
+	if err := json.Unmarshal([]byte(sb.String()), &got); err != nil {
+		t.Fatalf("WriteJSON() wrote invalid JSON: %v", err)
+	}
+	if !reflect.DeepEqual(got.EPSS, summary.EPSS) {
+		t.Errorf("WriteJSON() epss = %v, want %v", got.EPSS, summary.EPSS)
+	}
+}
@@ -0,0 +1,72 @@
+package remediation
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"deps.dev/util/resolve"
+	"deps.dev/util/resolve/dep"
+	"github.com/google/osv-scanner/internal/resolution"
+	"github.com/google/osv-scanner/internal/resolution/client"
+	"github.com/google/osv-scanner/internal/resolution/manifest"
+)
+
+// RequirementChange is a hypothetical change to the requirements of a manifest.
+type RequirementChange struct {
+	Pkg          resolve.PackageKey // The package to change the requirement of
+	NewRequire   string             // The new requirement string e.g. "^2.0.0"
+	Override     bool               // Whether to force the version on all (including indirect) dependencies, instead of changing a direct requirement
+	ManifestFile string             // Path to the local manifest of the direct requirement e.g. an npm workspace, empty for the root manifest
+}
+
+// PreviewChanges re-resolves the manifest of result with the hypothetical requirement changes applied,
+// so the consequences of a proposed upgrade can be inspected before it is made.
+// The returned diff holds the re-resolved graph and its vulnerabilities, filtered by opts, in New.
+func PreviewChanges(ctx context.Context, cl client.ResolutionClient, result *resolution.ResolutionResult, changes []RequirementChange, opts RemediationOptions) (resolution.ResolutionDiff, error) {
+	// Filter the original result just in case it hasn't been already
+	result.FilterVulns(opts.MatchVuln)
+
+	manif := result.Manifest.Clone()
+	for _, c := range changes {
+		if c.Override {
+			override := resolve.RequirementVersion{
+				Type: dep.NewType(),
+				VersionKey: resolve.VersionKey{
+					PackageKey:  c.Pkg,
+					Version:     c.NewRequire,
+					VersionType: resolve.Requirement,
+				},
+			}
+			if idx := slices.IndexFunc(manif.Overrides, func(rv resolve.RequirementVersion) bool { return rv.PackageKey == c.Pkg }); idx >= 0 {
+				manif.Overrides[idx] = override
+			} else {
+				manif.Overrides = append(manif.Overrides, override)
+			}
+
+			continue
+		}
+
+		reqs := manif.Requirements
+		if c.ManifestFile != "" {
+			locIdx := slices.IndexFunc(manif.LocalManifests, func(loc manifest.Manifest) bool { return loc.FilePath == c.ManifestFile })
+			if locIdx < 0 {
+				return resolution.ResolutionDiff{}, fmt.Errorf("no local manifest %s", c.ManifestFile)
+			}
+			reqs = manif.LocalManifests[locIdx].Requirements
+		}
+		idx := slices.IndexFunc(reqs, func(rv resolve.RequirementVersion) bool { return rv.PackageKey == c.Pkg })
+		if idx < 0 {
+			return resolution.ResolutionDiff{}, fmt.Errorf("%s is not a direct dependency", c.Pkg.Name)
+		}
+		reqs[idx].Version = c.NewRequire
+	}
+
+	newRes, err := resolution.Resolve(ctx, cl, manif)
+	if err != nil {
+		return resolution.ResolutionDiff{}, err
+	}
+	newRes.FilterVulns(opts.MatchVuln)
+
+	return result.CalculateDiff(newRes), nil
+}
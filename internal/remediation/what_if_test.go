@@ -0,0 +1,59 @@
+package remediation_test
+
+import (
+	"context"
+	"testing"
+
+	"deps.dev/util/resolve"
+	"github.com/google/osv-scanner/internal/remediation"
+	"github.com/google/osv-scanner/internal/resolution"
+	"github.com/google/osv-scanner/internal/resolution/client"
+	"github.com/google/osv-scanner/internal/resolution/manifest"
+)
+
+func TestPreviewChanges_InvalidChanges(t *testing.T) {
+	t.Parallel()
+
+	npmPkg := func(name string) resolve.PackageKey { return resolve.PackageKey{System: resolve.NPM, Name: name} }
+	m := manifest.Manifest{
+		FilePath: "package.json",
+		Requirements: []resolve.RequirementVersion{{
+			VersionKey: resolve.VersionKey{PackageKey: npmPkg("foo"), Version: "^1.0.0", VersionType: resolve.Requirement},
+		}},
+		LocalManifests: []manifest.Manifest{{FilePath: "packages/ws/package.json"}},
+	}
+
+	// Changes that cannot be applied to the manifest fail before anything is resolved
+	tests := []struct {
+		name   string
+		change remediation.RequirementChange
+	}{
+		{
+			name:   "not a direct dependency",
+			change: remediation.RequirementChange{Pkg: npmPkg("bar"), NewRequire: "^2.0.0"},
+		},
+		{
+			name:   "not a direct dependency of the local manifest",
+			change: remediation.RequirementChange{Pkg: npmPkg("foo"), NewRequire: "^2.0.0", ManifestFile: "packages/ws/package.json"},
+		},
+		{
+			name:   "unknown local manifest",
+			change: remediation.RequirementChange{Pkg: npmPkg("foo"), NewRequire: "^2.0.0", ManifestFile: "packages/other/package.json"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			res := &resolution.ResolutionResult{Manifest: m.Clone()}
+			_, err := remediation.PreviewChanges(context.Background(), client.ResolutionClient{}, res, []remediation.RequirementChange{tt.change}, remediation.RemediationOptions{})
+			if err == nil {
+				t.Errorf("PreviewChanges() succeeded, want error")
+			}
+			if got := res.Manifest.Requirements[0].Version; got != "^1.0.0" {
+				t.Errorf("PreviewChanges() modified the original manifest requirement to %q", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package resolution
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"deps.dev/util/resolve"
+	"deps.dev/util/resolve/dep"
+	"deps.dev/util/semver"
+)
+
+// cargoResolver resolves Rust crate graphs.
+// Cargo unifies each crate to a single version per semver-compatible range,
+// so multiple versions of a crate can be in the graph if they are incompatible e.g. rand 0.7.x and rand 0.8.x.
+// Each requirement is resolved to the highest matching version, reusing an already-selected compatible version if it matches.
+// TODO: features (optional dependencies are only resolved for the root), backtracking on conflicting requirements
+type cargoResolver struct {
+	cl resolve.Client
+}
+
+func newCargoResolver(cl resolve.Client) resolve.Resolver {
+	return cargoResolver{cl: cl}
+}
+
+// cargoCompatKey identifies the range of versions that Cargo considers semver-compatible,
+// i.e. the leftmost non-zero version component: 1.2.3 -> "1", 0.2.3 -> "0.2", 0.0.3 -> "0.0.3"
+func cargoCompatKey(ver string) string {
+	ver, _, _ = strings.Cut(ver, "+")
+	ver, _, _ = strings.Cut(ver, "-")
+	parts := strings.Split(ver, ".")
+	for i, p := range parts {
+		if p != "0" {
+			return strings.Join(parts[:i+1], ".")
+		}
+	}
+
+	return ver
+}
+
+func (r cargoResolver) Resolve(ctx context.Context, vk resolve.VersionKey) (*resolve.Graph, error) {
+	g := &resolve.Graph{}
+	root := g.AddNode(vk)
+
+	type cargoNode struct {
+		id resolve.NodeID
+		vk resolve.VersionKey
+	}
+	type compatKey struct {
+		pk     resolve.PackageKey
+		compat string
+	}
+	// the selected version of each crate, in each compatible range
+	chosen := make(map[compatKey]cargoNode)
+	selected := make(map[resolve.PackageKey][]cargoNode)
+
+	queue := []cargoNode{{id: root, vk: vk}}
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		reqs, err := r.cl.Requirements(ctx, n.vk)
+		if err != nil {
+			return nil, err
+		}
+		for _, req := range reqs {
+			if n.id != root && (req.Type.HasAttr(dep.Dev) || req.Type.HasAttr(dep.Opt)) {
+				continue
+			}
+
+			// Reuse an already-selected version if it satisfies the requirement
+			c, err := semver.Cargo.ParseConstraint(req.Version)
+			if err != nil {
+				return nil, fmt.Errorf("invalid requirement %s %q: %w", req.Name, req.Version, err)
+			}
+			reused := false
+			for _, s := range selected[req.PackageKey] {
+				if v, err := semver.Cargo.Parse(s.vk.Version); err == nil && c.MatchVersion(v) {
+					if err := g.AddEdge(n.id, s.id, req.Version, req.Type); err != nil {
+						return nil, err
+					}
+					reused = true
+
+					break
+				}
+			}
+			if reused {
+				continue
+			}
+
+			vers, err := r.cl.MatchingVersions(ctx, req.VersionKey)
+			if err != nil {
+				return nil, err
+			}
+			if len(vers) == 0 {
+				g.Error = fmt.Sprintf("no version of %s matches %q", req.Name, req.Version)
+				return g, nil
+			}
+			best := vers[0].VersionKey
+			for _, v := range vers[1:] {
+				if semver.Cargo.Compare(v.Version, best.Version) > 0 {
+					best = v.VersionKey
+				}
+			}
+
+			key := compatKey{pk: req.PackageKey, compat: cargoCompatKey(best.Version)}
+			if existing, ok := chosen[key]; ok {
+				// A different version in the same compatible range was already selected, which Cargo would not allow.
+				// Keep the existing version, the conflict is visible as an unsatisfied edge.
+				if err := g.AddEdge(n.id, existing.id, req.Version, req.Type); err != nil {
+					return nil, err
+				}
+
+				continue
+			}
+
+			node := cargoNode{id: g.AddNode(best), vk: best}
+			chosen[key] = node
+			selected[req.PackageKey] = append(selected[req.PackageKey], node)
+			if err := g.AddEdge(n.id, node.id, req.Version, req.Type); err != nil {
+				return nil, err
+			}
+			queue = append(queue, node)
+		}
+	}
+
+	return g, nil
+}
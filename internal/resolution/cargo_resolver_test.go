@@ -0,0 +1,86 @@
+package resolution
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"deps.dev/util/resolve"
+	"deps.dev/util/resolve/dep"
+	"github.com/google/osv-scanner/internal/resolution/util"
+)
+
+func TestCargoResolver(t *testing.T) {
+	t.Parallel()
+
+	req := func(name, version string, attrs ...any) resolve.RequirementVersion {
+		return testReq(util.Cargo, name, version, attrs...)
+	}
+	tests := []struct {
+		name string
+		pkgs []testPackage // the first package is the root
+		want []string
+	}{
+		{
+			name: "incompatible versions of a crate",
+			pkgs: []testPackage{
+				{name: "root", version: "0.1.0", reqs: []resolve.RequirementVersion{req("rand", "0.8"), req("serde", "1.0")}},
+				{name: "rand", version: "0.7.3"},
+				{name: "rand", version: "0.8.4"},
+				{name: "rand", version: "0.8.5"},
+				{name: "serde", version: "1.0.190", reqs: []resolve.RequirementVersion{req("rand", "0.7")}},
+			},
+			want: []string{
+				`root@0.1.0 -> rand@0.8.5 "0.8"`,
+				`root@0.1.0 -> serde@1.0.190 "1.0"`,
+				`serde@1.0.190 -> rand@0.7.3 "0.7"`,
+			},
+		},
+		{
+			name: "compatible requirements reuse the selected version",
+			pkgs: []testPackage{
+				{name: "root", version: "0.1.0", reqs: []resolve.RequirementVersion{req("a", "1"), req("b", "1")}},
+				{name: "a", version: "1.0.0", reqs: []resolve.RequirementVersion{req("c", "1.2")}},
+				{name: "b", version: "1.0.0", reqs: []resolve.RequirementVersion{req("c", "1.1")}},
+				{name: "c", version: "1.1.0"},
+				{name: "c", version: "1.2.0"},
+				{name: "c", version: "1.3.0"},
+			},
+			want: []string{
+				`root@0.1.0 -> a@1.0.0 "1"`,
+				`root@0.1.0 -> b@1.0.0 "1"`,
+				`a@1.0.0 -> c@1.3.0 "1.2"`,
+				`b@1.0.0 -> c@1.3.0 "1.1"`,
+			},
+		},
+		{
+			name: "dev and optional dependencies are only resolved for the root",
+			pkgs: []testPackage{
+				{name: "root", version: "0.1.0", reqs: []resolve.RequirementVersion{req("a", "1"), req("criterion", "0.5", dep.Dev, "")}},
+				{name: "a", version: "1.0.0", reqs: []resolve.RequirementVersion{req("proptest", "1", dep.Dev, ""), req("serde", "1", dep.Opt, "")}},
+				{name: "criterion", version: "0.5.1"},
+				{name: "proptest", version: "1.4.0"},
+				{name: "serde", version: "1.0.190"},
+			},
+			want: []string{
+				`root@0.1.0 -> a@1.0.0 "1"`,
+				`root@0.1.0 -> criterion@0.5.1 "0.5"`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := newCargoResolver(newTestClient(util.Cargo, tt.pkgs))
+			g, err := r.Resolve(context.Background(), rootKey(util.Cargo, tt.pkgs))
+			if err != nil {
+				t.Fatalf("Resolve() error = %v", err)
+			}
+			if got := graphLines(g); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Resolve() graph:\n%q\nwant:\n%q", got, tt.want)
+			}
+		})
+	}
+}
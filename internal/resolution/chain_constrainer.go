@@ -0,0 +1,111 @@
+package resolution
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"deps.dev/util/resolve"
+	"github.com/google/osv-scanner/internal/resolution/util"
+)
+
+var errNoMatchingVersions = errors.New("no matching versions")
+
+// chainConstrainer implements the version selection semantics of an ecosystem,
+// to determine whether a requirement is forcing a vulnerable version to be chosen in resolution.
+type chainConstrainer interface {
+	// isolatedVersion finds the version a requirement would resolve to if it were the only requirement on the package.
+	isolatedVersion(ctx context.Context, cl resolve.Client, req resolve.VersionKey) (resolve.VersionKey, error)
+}
+
+func getChainConstrainer(sys resolve.System) chainConstrainer {
+	switch sys { //nolint:exhaustive
+	case resolve.Maven:
+		return mavenChainConstrainer{}
+	case util.PyPI:
+		return pypiChainConstrainer{}
+	case util.Go:
+		return goChainConstrainer{}
+	case util.NuGet:
+		return nugetChainConstrainer{}
+	default:
+		// npm & Cargo pick the highest matching version
+		return npmChainConstrainer{}
+	}
+}
+
+// npmChainConstrainer picks the highest version matching the requirement's range.
+// An exact pin only matches itself, so is always the version picked.
+type npmChainConstrainer struct{}
+
+func (npmChainConstrainer) isolatedVersion(ctx context.Context, cl resolve.Client, req resolve.VersionKey) (resolve.VersionKey, error) {
+	vers, err := cl.MatchingVersions(ctx, req)
+	if err != nil {
+		return resolve.VersionKey{}, err
+	}
+	if len(vers) == 0 {
+		return resolve.VersionKey{}, errNoMatchingVersions
+	}
+
+	return vers[len(vers)-1].VersionKey, nil
+}
+
+// mavenChainConstrainer treats a plain version as the soft requirement it is, which selects exactly that version
+// when it is the nearest declaration. Version ranges pick the highest matching version.
+type mavenChainConstrainer struct{}
+
+func (mavenChainConstrainer) isolatedVersion(ctx context.Context, cl resolve.Client, req resolve.VersionKey) (resolve.VersionKey, error) {
+	if req.Version != "" && !strings.ContainsAny(req.Version, "[(,)]") {
+		req.VersionType = resolve.Concrete
+		return req, nil
+	}
+
+	return npmChainConstrainer{}.isolatedVersion(ctx, cl, req)
+}
+
+// pypiChainConstrainer picks the highest matching version, excluding prereleases unless
+// they are explicitly requested or are the only versions that match (per PEP 440).
+type pypiChainConstrainer struct{}
+
+func (pypiChainConstrainer) isolatedVersion(ctx context.Context, cl resolve.Client, req resolve.VersionKey) (resolve.VersionKey, error) {
+	vers, err := cl.MatchingVersions(ctx, req)
+	if err != nil {
+		return resolve.VersionKey{}, err
+	}
+	if len(vers) == 0 {
+		return resolve.VersionKey{}, errNoMatchingVersions
+	}
+	sys := util.Semver(req.System)
+	for i := len(vers) - 1; i >= 0; i-- {
+		v, err := sys.Parse(vers[i].Version)
+		if err == nil && !v.IsPrerelease() {
+			return vers[i].VersionKey, nil
+		}
+	}
+
+	return vers[len(vers)-1].VersionKey, nil
+}
+
+// goChainConstrainer follows minimal version selection, where a requirement is the minimum version,
+// which is the version selected when there are no other requirements on the module.
+type goChainConstrainer struct{}
+
+func (goChainConstrainer) isolatedVersion(_ context.Context, _ resolve.Client, req resolve.VersionKey) (resolve.VersionKey, error) {
+	req.VersionType = resolve.Concrete
+	return req, nil
+}
+
+// nugetChainConstrainer picks the lowest applicable version of the requirement's range.
+type nugetChainConstrainer struct{}
+
+func (nugetChainConstrainer) isolatedVersion(ctx context.Context, cl resolve.Client, req resolve.VersionKey) (resolve.VersionKey, error) {
+	vers, err := cl.MatchingVersions(ctx, req)
+	if err != nil {
+		return resolve.VersionKey{}, err
+	}
+	if len(vers) == 0 {
+		return resolve.VersionKey{}, errNoMatchingVersions
+	}
+
+	return vers[0].VersionKey, nil
+}
@@ -0,0 +1,186 @@
+package resolution
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"deps.dev/util/resolve"
+	"deps.dev/util/resolve/dep"
+	"github.com/google/osv-scanner/internal/resolution/util"
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+func TestChainConstrainer_IsolatedVersion(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		sys      resolve.System
+		versions []string
+		req      string
+		want     string
+		wantErr  error
+	}{
+		{
+			name:     "npm range picks the highest match",
+			sys:      resolve.NPM,
+			versions: []string{"1.0.0", "1.1.0", "2.0.0"},
+			req:      "^1.0.0",
+			want:     "1.1.0",
+		},
+		{
+			name:     "npm exact pin",
+			sys:      resolve.NPM,
+			versions: []string{"1.0.0", "1.1.0", "2.0.0"},
+			req:      "1.0.0",
+			want:     "1.0.0",
+		},
+		{
+			name:     "npm without matches",
+			sys:      resolve.NPM,
+			versions: []string{"1.0.0"},
+			req:      "^3.0.0",
+			wantErr:  errNoMatchingVersions,
+		},
+		{
+			name:     "cargo picks the highest match",
+			sys:      util.Cargo,
+			versions: []string{"1.0.0", "1.2.0", "2.0.0"},
+			req:      "1.0",
+			want:     "1.2.0",
+		},
+		{
+			name:     "maven soft requirement",
+			sys:      resolve.Maven,
+			versions: []string{"1.0", "1.1", "2.0"},
+			req:      "1.0",
+			want:     "1.0",
+		},
+		{
+			name:     "maven range picks the highest match",
+			sys:      resolve.Maven,
+			versions: []string{"1.0", "1.1", "2.0"},
+			req:      "[1.0,2.0)",
+			want:     "1.1",
+		},
+		{
+			name:     "pypi skips prereleases",
+			sys:      util.PyPI,
+			versions: []string{"1.0", "1.1", "2.0rc1"},
+			req:      ">=1.0",
+			want:     "1.1",
+		},
+		{
+			name:     "pypi picks a prerelease when only prereleases match",
+			sys:      util.PyPI,
+			versions: []string{"1.0", "2.0rc1"},
+			req:      ">=2.0rc1",
+			want:     "2.0rc1",
+		},
+		{
+			name:     "go minimal version selection",
+			sys:      util.Go,
+			versions: []string{"v1.2.0", "v1.3.0"},
+			req:      "v1.2.0",
+			want:     "v1.2.0",
+		},
+		{
+			name:     "nuget picks the lowest applicable version",
+			sys:      util.NuGet,
+			versions: []string{"1.0.0", "1.2.0", "1.5.0"},
+			req:      "[1.1.0,2.0.0)",
+			want:     "1.2.0",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var pkgs []testPackage
+			for _, v := range tt.versions {
+				pkgs = append(pkgs, testPackage{name: "pkg", version: v})
+			}
+			req := resolve.VersionKey{
+				PackageKey:  resolve.PackageKey{System: tt.sys, Name: "pkg"},
+				Version:     tt.req,
+				VersionType: resolve.Requirement,
+			}
+			got, err := getChainConstrainer(tt.sys).isolatedVersion(context.Background(), newTestClient(tt.sys, pkgs), req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("isolatedVersion() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got.Version != tt.want || got.VersionType != resolve.Concrete {
+				t.Errorf("isolatedVersion() = %v, want concrete %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChainConstrains(t *testing.T) {
+	t.Parallel()
+
+	// the vulnerability affects every version of pkg before 1.1.0
+	vuln := models.Vulnerability{
+		ID: "GHSA-pkg",
+		Affected: []models.Affected{{
+			Package: models.Package{Ecosystem: models.EcosystemNPM, Name: "pkg"},
+			Ranges:  []models.Range{{Type: models.RangeSemVer, Events: []models.Event{{Introduced: "0"}, {Fixed: "1.1.0"}}}},
+		}},
+	}
+
+	tests := []struct {
+		name      string
+		req       string
+		want      bool
+		wantDiags int
+	}{
+		{
+			// on its own, the range would pick the fixed 1.1.0
+			name: "range allowing a fixed version",
+			req:  "^1.0.0",
+			want: false,
+		},
+		{
+			name: "exact pin on the vulnerable version",
+			req:  "1.0.0",
+			want: true,
+		},
+		{
+			name:      "requirement without matching versions",
+			req:       "^3.0.0",
+			want:      true,
+			wantDiags: 1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cl := newTestClient(resolve.NPM, []testPackage{
+				{name: "pkg", version: "1.0.0"},
+				{name: "pkg", version: "1.1.0"},
+			})
+			g := &resolve.Graph{}
+			root := g.AddNode(resolve.VersionKey{PackageKey: resolve.PackageKey{System: resolve.NPM, Name: "root"}, Version: "1.0.0", VersionType: resolve.Concrete})
+			pkg := g.AddNode(resolve.VersionKey{PackageKey: resolve.PackageKey{System: resolve.NPM, Name: "pkg"}, Version: "1.0.0", VersionType: resolve.Concrete})
+			if err := g.AddEdge(root, pkg, tt.req, dep.NewType()); err != nil {
+				t.Fatalf("AddEdge() error = %v", err)
+			}
+
+			var diags Diagnostics
+			chain := DependencyChain{Graph: g, Edges: g.Edges}
+			if got := chainConstrains(context.Background(), cl, chain, &vuln, &diags); got != tt.want {
+				t.Errorf("chainConstrains() = %v, want %v", got, tt.want)
+			}
+			if got := diags.List(); len(got) != tt.wantDiags {
+				t.Errorf("chainConstrains() diagnostics = %v, want %d", got, tt.wantDiags)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package client
 
 import (
 	"context"
+	"time"
 
 	"deps.dev/util/resolve"
 	"github.com/google/osv-scanner/pkg/models"
@@ -27,3 +28,63 @@ type VulnerabilityClient interface {
 	// The returned Vulnerabilities[i] corresponds to the vulnerabilities in g.Nodes[i].
 	FindVulns(g *resolve.Graph) ([]models.Vulnerabilities, error)
 }
+
+// As finds the first client that implements T, such as one of the optional client interfaces below,
+// in cl and the clients it wraps, similarly to errors.As.
+// Clients that wrap another client without adding capabilities of their own (e.g. MemoClient)
+// return it from an Unwrap method, so they don't claim capabilities the wrapped client doesn't have.
+func As[T any](cl DependencyClient) (T, bool) {
+	for cl != nil {
+		if t, ok := cl.(T); ok {
+			return t, true
+		}
+		u, ok := cl.(interface{ Unwrap() DependencyClient })
+		if !ok {
+			break
+		}
+		cl = u.Unwrap()
+	}
+
+	var zero T
+
+	return zero, false
+}
+
+// EnginesClient is implemented by DependencyClients that know the runtime engine requirements of package versions.
+type EnginesClient interface {
+	// Engines returns the runtime engines required by the version e.g. npm's {"node": ">=18"}
+	Engines(ctx context.Context, vk resolve.VersionKey) (map[string]string, error)
+}
+
+// PlatformClient is implemented by DependencyClients that know which platforms package versions can be installed on.
+type PlatformClient interface {
+	// Platforms returns the operating systems and CPU architectures the version is restricted to
+	// e.g. npm's "os" and "cpu" fields, or empty slices if it can be installed anywhere.
+	Platforms(ctx context.Context, vk resolve.VersionKey) (os []string, cpu []string, err error)
+}
+
+// IntegrityClient is implemented by DependencyClients that know the integrity hashes of package versions' published archives.
+type IntegrityClient interface {
+	// Integrity returns the Subresource Integrity hashes of the version's archive e.g. "sha512-...",
+	// or an empty string if they are not known.
+	Integrity(ctx context.Context, vk resolve.VersionKey) (string, error)
+}
+
+// PublishTimeClient is implemented by DependencyClients that know when package versions were published.
+type PublishTimeClient interface {
+	// PublishTime returns the time the version was published, or the zero time if it is not known.
+	PublishTime(ctx context.Context, vk resolve.VersionKey) (time.Time, error)
+}
+
+// DeprecationClient is implemented by DependencyClients that know which package versions are deprecated.
+type DeprecationClient interface {
+	// Deprecation returns the reason the version was deprecated, which often names a replacement,
+	// or an empty string if it is not deprecated (or the reason is not known).
+	Deprecation(ctx context.Context, vk resolve.VersionKey) (string, error)
+}
+
+// ExploitClient is implemented by VulnerabilityClients that know the exploit probabilities of vulnerabilities.
+type ExploitClient interface {
+	// EPSS returns the EPSS exploit probability score of each vulnerability, keyed by vulnerability ID.
+	EPSS(ctx context.Context, vulns []models.Vulnerability) (map[string]float64, error)
+}
@@ -0,0 +1,161 @@
+package client
+
+import (
+	"context"
+	"encoding/gob"
+	"fmt"
+	"os"
+	"slices"
+
+	"deps.dev/util/resolve"
+	"deps.dev/util/resolve/dep"
+	"deps.dev/util/semver"
+	"github.com/google/osv-scanner/internal/resolution/datasource"
+	"github.com/google/osv-scanner/internal/resolution/util"
+)
+
+const cratesIndexCacheExt = ".resolve.cargo"
+
+// CratesIndexClient is a DependencyClient for Rust crates, using the crates.io sparse index.
+type CratesIndexClient struct {
+	api *datasource.CratesIndexAPIClient
+}
+
+func NewCratesIndexClient(cfg datasource.RegistryConfig) (*CratesIndexClient, error) {
+	api, err := datasource.NewCratesIndexAPIClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CratesIndexClient{api: api}, nil
+}
+
+func (c *CratesIndexClient) Version(ctx context.Context, vk resolve.VersionKey) (resolve.Version, error) {
+	return resolve.Version{VersionKey: vk}, nil
+}
+
+func (c *CratesIndexClient) Versions(ctx context.Context, pk resolve.PackageKey) ([]resolve.Version, error) {
+	if pk.System != util.Cargo {
+		return nil, fmt.Errorf("unsupported system: %v", pk.System)
+	}
+
+	crates, err := c.api.Versions(ctx, pk.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	vks := make([]resolve.Version, 0, len(crates))
+	for _, cv := range crates {
+		// yanked versions cannot be newly selected
+		if cv.Yanked {
+			continue
+		}
+		vks = append(vks, resolve.Version{
+			VersionKey: resolve.VersionKey{
+				PackageKey:  pk,
+				Version:     cv.Vers,
+				VersionType: resolve.Concrete,
+			}})
+	}
+
+	slices.SortFunc(vks, func(a, b resolve.Version) int { return semver.Cargo.Compare(a.Version, b.Version) })
+
+	return vks, nil
+}
+
+func (c *CratesIndexClient) Requirements(ctx context.Context, vk resolve.VersionKey) ([]resolve.RequirementVersion, error) {
+	if vk.System != util.Cargo {
+		return nil, fmt.Errorf("unsupported system: %v", vk.System)
+	}
+
+	crates, err := c.api.Versions(ctx, vk.Name)
+	if err != nil {
+		return nil, err
+	}
+	idx := slices.IndexFunc(crates, func(cv datasource.CrateVersion) bool { return cv.Vers == vk.Version })
+	if idx < 0 {
+		return nil, fmt.Errorf("no version %s for crate %s", vk.Version, vk.Name)
+	}
+
+	deps := make([]resolve.RequirementVersion, 0, len(crates[idx].Deps))
+	for _, d := range crates[idx].Deps {
+		deps = append(deps, makeCargoReqVer(d))
+	}
+	resolve.SortDependencies(deps)
+
+	return deps, nil
+}
+
+// makeCargoReqVer converts a crates.io index dependency into a RequirementVersion.
+func makeCargoReqVer(d datasource.CrateDependency) resolve.RequirementVersion {
+	name := d.Name
+	typ := dep.NewType()
+	if d.Package != "" && d.Package != d.Name {
+		// The dependency is renamed, depend on the actual crate with the KnownAs attribute set to the rename.
+		typ.AddAttr(dep.KnownAs, d.Name)
+		name = d.Package
+	}
+	switch d.Kind {
+	case "dev":
+		typ.AddAttr(dep.Dev, "")
+	case "build":
+		typ.AddAttr(dep.Scope, "build")
+	}
+	if d.Optional {
+		typ.AddAttr(dep.Opt, "")
+	}
+	if d.Target != "" {
+		typ.AddAttr(dep.Environment, d.Target)
+	}
+
+	return resolve.RequirementVersion{
+		Type: typ,
+		VersionKey: resolve.VersionKey{
+			PackageKey: resolve.PackageKey{
+				System: util.Cargo,
+				Name:   name,
+			},
+			Version:     d.Req,
+			VersionType: resolve.Requirement,
+		},
+	}
+}
+
+func (c *CratesIndexClient) MatchingVersions(ctx context.Context, vk resolve.VersionKey) ([]resolve.Version, error) {
+	vers, err := c.Versions(ctx, vk.PackageKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return util.MatchRequirement(vk, vers), nil
+}
+
+func (c *CratesIndexClient) PreFetch(ctx context.Context, requirements []resolve.RequirementVersion, manifestPath string) {
+	// It doesn't matter if loading the cache fails
+	_ = c.LoadCache(manifestPath)
+
+	for _, req := range requirements {
+		go c.Versions(ctx, req.PackageKey) //nolint:errcheck
+	}
+	// don't bother waiting for goroutines to finish.
+}
+
+func (c *CratesIndexClient) WriteCache(path string) error {
+	f, err := os.Create(path + cratesIndexCacheExt)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return gob.NewEncoder(f).Encode(c.api)
+}
+
+func (c *CratesIndexClient) LoadCache(path string) error {
+	f, err := os.Open(path + cratesIndexCacheExt)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return gob.NewDecoder(f).Decode(&c.api)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/gob"
 	"os"
+	"time"
 
 	pb "deps.dev/api/v3alpha"
 	"deps.dev/util/resolve"
@@ -75,6 +76,29 @@ func (d *DepsDevClient) PreFetch(ctx context.Context, requirements []resolve.Req
 	// Don't bother waiting for these goroutines to finish.
 }
 
+// PublishTime returns the time deps.dev reports the version was published.
+func (d *DepsDevClient) PublishTime(ctx context.Context, vk resolve.VersionKey) (time.Time, error) {
+	v, err := d.getVersion(ctx, vk)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if v.GetPublishedAt() == nil {
+		return time.Time{}, nil
+	}
+
+	return v.GetPublishedAt().AsTime(), nil
+}
+
+func (d *DepsDevClient) getVersion(ctx context.Context, vk resolve.VersionKey) (*pb.Version, error) {
+	return d.c.GetVersion(ctx, &pb.GetVersionRequest{
+		VersionKey: &pb.VersionKey{
+			System:  pb.System(vk.System),
+			Name:    vk.Name,
+			Version: vk.Version,
+		},
+	})
+}
+
 func (d *DepsDevClient) WriteCache(path string) error {
 	f, err := os.Create(path + depsDevCacheExt)
 	if err != nil {
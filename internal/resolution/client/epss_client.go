@@ -0,0 +1,132 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/osv"
+	"golang.org/x/exp/maps"
+)
+
+const (
+	// EPSSEndpoint is the URL for getting EPSS scores from FIRST.
+	EPSSEndpoint = "https://api.first.org/data/v1/epss"
+	// maxCVEsPerEPSSRequest is the number of CVEs returned in one page of the EPSS API
+	maxCVEsPerEPSSRequest = 100
+)
+
+// EPSSClient wraps a VulnerabilityClient, adding the EPSS exploit probabilities of vulnerabilities
+// from the FIRST Exploit Prediction Scoring System API.
+type EPSSClient struct {
+	VulnerabilityClient
+	scoreCache sync.Map // map[string]float64, keyed by CVE ID
+}
+
+func NewEPSSClient(c VulnerabilityClient) *EPSSClient {
+	return &EPSSClient{VulnerabilityClient: c}
+}
+
+type epssResponse struct {
+	Data []struct {
+		CVE  string `json:"cve"`
+		EPSS string `json:"epss"`
+	} `json:"data"`
+}
+
+// EPSS returns the highest EPSS score of the CVE IDs and aliases of each vulnerability, keyed by vulnerability ID.
+// Vulnerabilities without a CVE alias, or without an EPSS score, are not included.
+func (c *EPSSClient) EPSS(ctx context.Context, vulns []models.Vulnerability) (map[string]float64, error) {
+	// Determine which CVEs we don't already have cached
+	toQuery := make(map[string]struct{})
+	for _, v := range vulns {
+		for _, id := range cveIDs(v) {
+			if _, ok := c.scoreCache.Load(id); !ok {
+				toQuery[id] = struct{}{}
+			}
+		}
+	}
+
+	cves := maps.Keys(toQuery)
+	for len(cves) > 0 {
+		n := min(len(cves), maxCVEsPerEPSSRequest)
+		if err := c.fetch(ctx, cves[:n]); err != nil {
+			return nil, err
+		}
+		cves = cves[n:]
+	}
+
+	scores := make(map[string]float64)
+	for _, v := range vulns {
+		for _, id := range cveIDs(v) {
+			scoreAny, ok := c.scoreCache.Load(id)
+			if !ok {
+				continue
+			}
+			score, ok := scoreAny.(float64)
+			if !ok || score == 0 {
+				continue
+			}
+			scores[v.ID] = max(scores[v.ID], score)
+		}
+	}
+
+	return scores, nil
+}
+
+func (c *EPSSClient) fetch(ctx context.Context, cves []string) error {
+	reqURL := EPSSEndpoint + "?cve=" + url.QueryEscape(strings.Join(cves, ","))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return err
+	}
+	if osv.RequestUserAgent != "" {
+		req.Header.Set("User-Agent", osv.RequestUserAgent)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("EPSS request failed: %s", resp.Status)
+	}
+
+	var epssResp epssResponse
+	if err := json.NewDecoder(resp.Body).Decode(&epssResp); err != nil {
+		return err
+	}
+
+	// CVEs not in the response have no score, cache them as 0 so they're not queried again
+	for _, id := range cves {
+		c.scoreCache.Store(id, 0.0)
+	}
+	for _, d := range epssResp.Data {
+		score, err := strconv.ParseFloat(d.EPSS, 64)
+		if err != nil {
+			continue
+		}
+		c.scoreCache.Store(d.CVE, score)
+	}
+
+	return nil
+}
+
+func cveIDs(v models.Vulnerability) []string {
+	var ids []string
+	for _, id := range append([]string{v.ID}, v.Aliases...) {
+		if strings.HasPrefix(id, "CVE-") {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids
+}
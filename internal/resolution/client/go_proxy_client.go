@@ -0,0 +1,141 @@
+package client
+
+import (
+	"context"
+	"encoding/gob"
+	"fmt"
+	"os"
+	"slices"
+
+	"deps.dev/util/resolve"
+	"deps.dev/util/resolve/dep"
+	"github.com/google/osv-scanner/internal/resolution/datasource"
+	"github.com/google/osv-scanner/internal/resolution/util"
+	"golang.org/x/mod/semver"
+)
+
+const goProxyCacheExt = ".resolve.go"
+
+// GoProxyClient is a DependencyClient for Go modules, using the Go module proxy.
+// Go requirements are minimum versions, so a requirement only matches its exact version;
+// the newest of the minimums is selected by the resolver (minimal version selection).
+type GoProxyClient struct {
+	api *datasource.GoProxyAPIClient
+}
+
+func NewGoProxyClient(cfg datasource.RegistryConfig) (*GoProxyClient, error) {
+	api, err := datasource.NewGoProxyAPIClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GoProxyClient{api: api}, nil
+}
+
+func (c *GoProxyClient) Version(ctx context.Context, vk resolve.VersionKey) (resolve.Version, error) {
+	return resolve.Version{VersionKey: vk}, nil
+}
+
+func (c *GoProxyClient) Versions(ctx context.Context, pk resolve.PackageKey) ([]resolve.Version, error) {
+	if pk.System != util.Go {
+		return nil, fmt.Errorf("unsupported system: %v", pk.System)
+	}
+
+	vers, err := c.api.Versions(ctx, pk.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	vks := make([]resolve.Version, 0, len(vers))
+	for _, v := range vers {
+		if !semver.IsValid(v) {
+			continue
+		}
+		vks = append(vks, resolve.Version{
+			VersionKey: resolve.VersionKey{
+				PackageKey:  pk,
+				Version:     v,
+				VersionType: resolve.Concrete,
+			}})
+	}
+
+	slices.SortFunc(vks, func(a, b resolve.Version) int { return semver.Compare(a.Version, b.Version) })
+
+	return vks, nil
+}
+
+func (c *GoProxyClient) Requirements(ctx context.Context, vk resolve.VersionKey) ([]resolve.RequirementVersion, error) {
+	if vk.System != util.Go {
+		return nil, fmt.Errorf("unsupported system: %v", vk.System)
+	}
+
+	reqs, err := c.api.Requires(ctx, vk.Name, vk.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	deps := make([]resolve.RequirementVersion, len(reqs))
+	for i, r := range reqs {
+		deps[i] = resolve.RequirementVersion{
+			Type: dep.NewType(),
+			VersionKey: resolve.VersionKey{
+				PackageKey: resolve.PackageKey{
+					System: util.Go,
+					Name:   r.Path,
+				},
+				Version:     r.Version,
+				VersionType: resolve.Requirement,
+			},
+		}
+	}
+	resolve.SortDependencies(deps)
+
+	return deps, nil
+}
+
+func (c *GoProxyClient) MatchingVersions(ctx context.Context, vk resolve.VersionKey) ([]resolve.Version, error) {
+	// The required version may be a pseudo-version that is not in the proxy's list, but it still exists.
+	if !semver.IsValid(vk.Version) {
+		return nil, nil
+	}
+
+	return []resolve.Version{{
+		VersionKey: resolve.VersionKey{
+			PackageKey:  vk.PackageKey,
+			Version:     vk.Version,
+			VersionType: resolve.Concrete,
+		},
+	}}, nil
+}
+
+func (c *GoProxyClient) PreFetch(ctx context.Context, requirements []resolve.RequirementVersion, manifestPath string) {
+	// It doesn't matter if loading the cache fails
+	_ = c.LoadCache(manifestPath)
+
+	for _, req := range requirements {
+		vk := req.VersionKey
+		vk.VersionType = resolve.Concrete
+		go c.Requirements(ctx, vk) //nolint:errcheck
+	}
+	// don't bother waiting for goroutines to finish.
+}
+
+func (c *GoProxyClient) WriteCache(path string) error {
+	f, err := os.Create(path + goProxyCacheExt)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return gob.NewEncoder(f).Encode(c.api)
+}
+
+func (c *GoProxyClient) LoadCache(path string) error {
+	f, err := os.Open(path + goProxyCacheExt)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return gob.NewDecoder(f).Decode(&c.api)
+}
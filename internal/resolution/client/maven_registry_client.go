@@ -0,0 +1,175 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"deps.dev/util/resolve"
+	"deps.dev/util/resolve/dep"
+	"deps.dev/util/semver"
+	"github.com/google/osv-scanner/internal/resolution/datasource"
+	"github.com/google/osv-scanner/internal/resolution/manifest"
+	"github.com/google/osv-scanner/internal/resolution/util"
+)
+
+const mavenRegistryCacheExt = ".resolve.maven"
+
+// MavenRegistryClient is a DependencyClient for Maven artifacts, using Maven Central or a mirror repository.
+type MavenRegistryClient struct {
+	api *datasource.MavenRegistryAPIClient
+}
+
+func NewMavenRegistryClient(cfg datasource.RegistryConfig) (*MavenRegistryClient, error) {
+	api, err := datasource.NewMavenRegistryAPIClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MavenRegistryClient{api: api}, nil
+}
+
+func (c *MavenRegistryClient) Version(ctx context.Context, vk resolve.VersionKey) (resolve.Version, error) {
+	return resolve.Version{VersionKey: vk}, nil
+}
+
+func (c *MavenRegistryClient) Versions(ctx context.Context, pk resolve.PackageKey) ([]resolve.Version, error) {
+	if pk.System != resolve.Maven {
+		return nil, fmt.Errorf("unsupported system: %v", pk.System)
+	}
+
+	vers, err := c.api.Versions(ctx, pk.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	vks := make([]resolve.Version, len(vers))
+	for i, v := range vers {
+		vks[i] = resolve.Version{
+			VersionKey: resolve.VersionKey{
+				PackageKey:  pk,
+				Version:     v,
+				VersionType: resolve.Concrete,
+			}}
+	}
+
+	slices.SortFunc(vks, func(a, b resolve.Version) int { return semver.Maven.Compare(a.Version, b.Version) })
+
+	return vks, nil
+}
+
+// pom fetches and parses the pom of the artifact version, with its parent poms and imported BOMs merged into it.
+func (c *MavenRegistryClient) pom(ctx context.Context, name, version string) (manifest.PomXML, error) {
+	var pom manifest.PomXML
+	body, err := c.api.POM(ctx, name, version)
+	if err != nil {
+		return pom, err
+	}
+	if err := xml.NewDecoder(bytes.NewReader(body)).Decode(&pom); err != nil {
+		return pom, fmt.Errorf("could not parse pom of %s:%s: %w", name, version, err)
+	}
+	if err := pom.MergeEffective(ctx, "", c.api.POM); err != nil {
+		return pom, err
+	}
+
+	return pom, nil
+}
+
+func (c *MavenRegistryClient) Requirements(ctx context.Context, vk resolve.VersionKey) ([]resolve.RequirementVersion, error) {
+	if vk.System != resolve.Maven {
+		return nil, fmt.Errorf("unsupported system: %v", vk.System)
+	}
+
+	pom, err := c.pom(ctx, vk.Name, vk.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	var deps []resolve.RequirementVersion
+	for _, d := range pom.Dependencies {
+		scope := strings.TrimSpace(d.Scope)
+		// Only compile and runtime dependencies are transitive, as are non-optional ones
+		if scope == "test" || scope == "provided" || scope == "system" || strings.TrimSpace(d.Optional) == "true" {
+			continue
+		}
+		groupID := pom.Interpolate(d.GroupID)
+		artifactID := pom.Interpolate(d.ArtifactID)
+		ver := pom.Interpolate(d.Version)
+		if ver == "" {
+			ver = pom.ManagedVersion(groupID, artifactID)
+		}
+		typ := dep.NewType()
+		if scope != "" && scope != "compile" {
+			typ.AddAttr(dep.Scope, scope)
+		}
+		deps = append(deps, resolve.RequirementVersion{
+			Type: typ,
+			VersionKey: resolve.VersionKey{
+				PackageKey: resolve.PackageKey{
+					System: resolve.Maven,
+					Name:   groupID + ":" + artifactID,
+				},
+				Version:     ver,
+				VersionType: resolve.Requirement,
+			},
+		})
+	}
+	resolve.SortDependencies(deps)
+
+	return deps, nil
+}
+
+func (c *MavenRegistryClient) MatchingVersions(ctx context.Context, vk resolve.VersionKey) ([]resolve.Version, error) {
+	vers, err := c.Versions(ctx, vk.PackageKey)
+	if err != nil {
+		return nil, err
+	}
+
+	// A soft requirement (e.g. "1.0") is only a recommendation, which matches exactly that version
+	if !strings.ContainsAny(vk.Version, "[](),") {
+		for _, v := range vers {
+			if v.Version == vk.Version {
+				return []resolve.Version{v}, nil
+			}
+		}
+
+		return nil, nil
+	}
+
+	return util.MatchRequirement(vk, vers), nil
+}
+
+func (c *MavenRegistryClient) PreFetch(ctx context.Context, requirements []resolve.RequirementVersion, manifestPath string) {
+	// It doesn't matter if loading the cache fails
+	_ = c.LoadCache(manifestPath)
+
+	for _, req := range requirements {
+		go c.Versions(ctx, req.PackageKey) //nolint:errcheck
+	}
+	// don't bother waiting for goroutines to finish.
+}
+
+func (c *MavenRegistryClient) WriteCache(path string) error {
+	f, err := os.Create(path + mavenRegistryCacheExt)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return gob.NewEncoder(f).Encode(c.api)
+}
+
+func (c *MavenRegistryClient) LoadCache(path string) error {
+	f, err := os.Open(path + mavenRegistryCacheExt)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return gob.NewDecoder(f).Decode(&c.api)
+}
@@ -0,0 +1,108 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"deps.dev/util/resolve"
+	"golang.org/x/sync/singleflight"
+)
+
+// MemoClient wraps a DependencyClient, memoizing the results of Versions, MatchingVersions and Requirements calls.
+// Concurrent identical requests are deduplicated, so only one of them reaches the wrapped client.
+// Resolution and remediation make many repeated requests for the same packages,
+// so this avoids repeating them to the underlying registry or API.
+// Errors are not memoized, and each caller gets its own copy of the results.
+// The optional client interfaces of the wrapped client are found with As.
+type MemoClient struct {
+	DependencyClient
+
+	group            singleflight.Group
+	mu               sync.Mutex
+	versions         map[resolve.PackageKey][]resolve.Version
+	matchingVersions map[resolve.VersionKey][]resolve.Version
+	requirements     map[resolve.VersionKey][]resolve.RequirementVersion
+}
+
+// NewMemoClient wraps cl in a MemoClient, or returns cl if it is already one.
+func NewMemoClient(cl DependencyClient) *MemoClient {
+	if mc, ok := cl.(*MemoClient); ok {
+		return mc
+	}
+
+	return &MemoClient{
+		DependencyClient: cl,
+		versions:         make(map[resolve.PackageKey][]resolve.Version),
+		matchingVersions: make(map[resolve.VersionKey][]resolve.Version),
+		requirements:     make(map[resolve.VersionKey][]resolve.RequirementVersion),
+	}
+}
+
+// memoize returns a copy of the slice cached in m for key if there is one,
+// otherwise it calls fn (at most once at a time for each flight key) and caches the result.
+// Callers get their own copy, so they can sort or modify it without affecting the cache or each other.
+func memoize[K comparable, E any](ctx context.Context, c *MemoClient, m map[K][]E, key K, flightKey string, fn func() ([]E, error)) ([]E, error) {
+	c.mu.Lock()
+	v, ok := m[key]
+	c.mu.Unlock()
+	if ok {
+		return slices.Clone(v), nil
+	}
+
+	call := func() ([]E, error) {
+		v, err := fn()
+		if err != nil {
+			return nil, err
+		}
+		c.mu.Lock()
+		m[key] = v
+		c.mu.Unlock()
+
+		return v, nil
+	}
+
+	res, err, _ := c.group.Do(flightKey, func() (any, error) { return call() })
+	if err != nil {
+		// The call made by another request may have been cancelled by that request's context,
+		// which should not fail this request if its own context is still live.
+		if isContextErr(err) && ctx.Err() == nil {
+			v, err := call()
+			return slices.Clone(v), err
+		}
+
+		return nil, err
+	}
+
+	return slices.Clone(res.([]E)), nil
+}
+
+func isContextErr(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+func (c *MemoClient) Versions(ctx context.Context, pk resolve.PackageKey) ([]resolve.Version, error) {
+	return memoize(ctx, c, c.versions, pk, fmt.Sprintf("versions:%v", pk), func() ([]resolve.Version, error) {
+		return c.DependencyClient.Versions(ctx, pk)
+	})
+}
+
+func (c *MemoClient) MatchingVersions(ctx context.Context, vk resolve.VersionKey) ([]resolve.Version, error) {
+	return memoize(ctx, c, c.matchingVersions, vk, fmt.Sprintf("matching:%v", vk), func() ([]resolve.Version, error) {
+		return c.DependencyClient.MatchingVersions(ctx, vk)
+	})
+}
+
+func (c *MemoClient) Requirements(ctx context.Context, vk resolve.VersionKey) ([]resolve.RequirementVersion, error) {
+	return memoize(ctx, c, c.requirements, vk, fmt.Sprintf("requirements:%v", vk), func() ([]resolve.RequirementVersion, error) {
+		return c.DependencyClient.Requirements(ctx, vk)
+	})
+}
+
+// Unwrap returns the wrapped client, which As looks for the optional client interfaces in.
+// MemoClient does not implement them itself, so that it only has the capabilities of the wrapped client.
+func (c *MemoClient) Unwrap() DependencyClient {
+	return c.DependencyClient
+}
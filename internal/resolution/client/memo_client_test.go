@@ -0,0 +1,138 @@
+package client_test
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"deps.dev/util/resolve"
+	"github.com/google/osv-scanner/internal/resolution/client"
+)
+
+// countingClient is a DependencyClient serving versions from a resolve.LocalClient, counting the Versions calls that reach it.
+type countingClient struct {
+	*resolve.LocalClient
+	calls int
+}
+
+func (c *countingClient) Versions(ctx context.Context, pk resolve.PackageKey) ([]resolve.Version, error) {
+	c.calls++
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return c.LocalClient.Versions(ctx, pk)
+}
+
+func (c *countingClient) WriteCache(string) error                                        { return nil }
+func (c *countingClient) LoadCache(string) error                                         { return nil }
+func (c *countingClient) PreFetch(context.Context, []resolve.RequirementVersion, string) {}
+
+func newCountingClient(pk resolve.PackageKey, versions ...string) *countingClient {
+	lc := resolve.NewLocalClient()
+	for _, v := range versions {
+		lc.AddVersion(resolve.Version{VersionKey: resolve.VersionKey{PackageKey: pk, Version: v, VersionType: resolve.Concrete}}, nil)
+	}
+
+	return &countingClient{LocalClient: lc}
+}
+
+func TestMemoClient_Versions(t *testing.T) {
+	t.Parallel()
+
+	pk := resolve.PackageKey{System: resolve.NPM, Name: "foo"}
+	cl := newCountingClient(pk, "1.0.0", "2.0.0", "3.0.0")
+	mc := client.NewMemoClient(cl)
+
+	first, err := mc.Versions(context.Background(), pk)
+	if err != nil {
+		t.Fatalf("Versions() error = %v", err)
+	}
+	want := slices.Clone(first)
+
+	// Callers may sort or modify the returned slice without affecting the cached versions
+	slices.Reverse(first)
+	first[0].Version = "modified"
+
+	second, err := mc.Versions(context.Background(), pk)
+	if err != nil {
+		t.Fatalf("Versions() error = %v", err)
+	}
+	if !reflect.DeepEqual(second, want) {
+		t.Errorf("Versions() = %v, want %v", second, want)
+	}
+	if cl.calls != 1 {
+		t.Errorf("wrapped client called %d times, want 1", cl.calls)
+	}
+}
+
+func TestMemoClient_Versions_Cancelled(t *testing.T) {
+	t.Parallel()
+
+	pk := resolve.PackageKey{System: resolve.NPM, Name: "foo"}
+	cl := newCountingClient(pk, "1.0.0")
+	mc := client.NewMemoClient(cl)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := mc.Versions(ctx, pk); err == nil {
+		t.Fatalf("Versions() with cancelled context succeeded, want error")
+	}
+
+	// The cancellation of one request must not be remembered for later requests
+	vers, err := mc.Versions(context.Background(), pk)
+	if err != nil {
+		t.Fatalf("Versions() error = %v", err)
+	}
+	if len(vers) != 1 {
+		t.Errorf("Versions() = %v, want 1 version", vers)
+	}
+}
+
+// publishTimeClient is a countingClient that knows when versions were published, but none of the other optional client interfaces.
+type publishTimeClient struct {
+	*countingClient
+	published time.Time
+}
+
+func (c publishTimeClient) PublishTime(context.Context, resolve.VersionKey) (time.Time, error) {
+	return c.published, nil
+}
+
+func TestMemoClient_OptionalClients(t *testing.T) {
+	t.Parallel()
+
+	pk := resolve.PackageKey{System: resolve.NPM, Name: "foo"}
+	vk := resolve.VersionKey{PackageKey: pk, Version: "1.0.0", VersionType: resolve.Concrete}
+	published := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	mc := client.NewMemoClient(publishTimeClient{countingClient: newCountingClient(pk, "1.0.0"), published: published})
+
+	// The capabilities of the wrapped client are found through the MemoClient
+	ptc, ok := client.As[client.PublishTimeClient](mc)
+	if !ok {
+		t.Fatalf("As[PublishTimeClient]() = false, want the wrapped client")
+	}
+	if got, err := ptc.PublishTime(context.Background(), vk); err != nil || !got.Equal(published) {
+		t.Errorf("PublishTime() = %v, %v, want %v", got, err, published)
+	}
+
+	// but the MemoClient doesn't claim the ones the wrapped client doesn't have
+	var dc client.DependencyClient = mc
+	if _, ok := dc.(client.PublishTimeClient); ok {
+		t.Errorf("MemoClient implements PublishTimeClient itself, want only through As")
+	}
+	if _, ok := client.As[client.EnginesClient](mc); ok {
+		t.Errorf("As[EnginesClient]() = true, want false")
+	}
+	if _, ok := client.As[client.PlatformClient](mc); ok {
+		t.Errorf("As[PlatformClient]() = true, want false")
+	}
+	if _, ok := client.As[client.IntegrityClient](mc); ok {
+		t.Errorf("As[IntegrityClient]() = true, want false")
+	}
+	if _, ok := client.As[client.DeprecationClient](mc); ok {
+		t.Errorf("As[DeprecationClient]() = true, want false")
+	}
+}
@@ -8,12 +8,14 @@ import (
 	"os"
 	"slices"
 	"strings"
+	"time"
 
 	pb "deps.dev/api/v3alpha"
 	"deps.dev/util/resolve"
 	"deps.dev/util/resolve/dep"
 	"deps.dev/util/semver"
 	"github.com/google/osv-scanner/internal/resolution/datasource"
+	"github.com/google/osv-scanner/internal/resolution/util"
 	"github.com/google/osv-scanner/pkg/depsdev"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
@@ -29,8 +31,8 @@ type NpmRegistryClient struct {
 	fallback *resolve.APIClient
 }
 
-func NewNpmRegistryClient(workdir string) (*NpmRegistryClient, error) {
-	api, err := datasource.NewNpmRegistryAPIClient(workdir)
+func NewNpmRegistryClient(workdir string, cfg datasource.RegistryConfig) (*NpmRegistryClient, error) {
+	api, err := datasource.NewNpmRegistryAPIClient(workdir, cfg)
 	if err != nil {
 		return nil, err
 	}
@@ -181,6 +183,71 @@ func (c *NpmRegistryClient) Requirements(ctx context.Context, vk resolve.Version
 	return deps, nil
 }
 
+func (c *NpmRegistryClient) Engines(ctx context.Context, vk resolve.VersionKey) (map[string]string, error) {
+	if isNpmBundle(vk.PackageKey) { // bundled dependencies are not checked
+		return nil, nil
+	}
+
+	dependencies, err := c.api.Dependencies(ctx, vk.Name, vk.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	return dependencies.Engines, nil
+}
+
+func (c *NpmRegistryClient) Platforms(ctx context.Context, vk resolve.VersionKey) ([]string, []string, error) {
+	if isNpmBundle(vk.PackageKey) {
+		return nil, nil, nil
+	}
+
+	dependencies, err := c.api.Dependencies(ctx, vk.Name, vk.Version)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return dependencies.OS, dependencies.CPU, nil
+}
+
+func (c *NpmRegistryClient) Integrity(ctx context.Context, vk resolve.VersionKey) (string, error) {
+	if isNpmBundle(vk.PackageKey) {
+		return "", nil
+	}
+
+	dependencies, err := c.api.Dependencies(ctx, vk.Name, vk.Version)
+	if err != nil {
+		return "", err
+	}
+
+	return dependencies.Integrity, nil
+}
+
+func (c *NpmRegistryClient) PublishTime(ctx context.Context, vk resolve.VersionKey) (time.Time, error) {
+	if isNpmBundle(vk.PackageKey) {
+		return time.Time{}, nil
+	}
+
+	dependencies, err := c.api.Dependencies(ctx, vk.Name, vk.Version)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return dependencies.Published, nil
+}
+
+func (c *NpmRegistryClient) Deprecation(ctx context.Context, vk resolve.VersionKey) (string, error) {
+	if isNpmBundle(vk.PackageKey) {
+		return "", nil
+	}
+
+	dependencies, err := c.api.Dependencies(ctx, vk.Name, vk.Version)
+	if err != nil {
+		return "", err
+	}
+
+	return dependencies.Deprecated, nil
+}
+
 func (c *NpmRegistryClient) MatchingVersions(ctx context.Context, vk resolve.VersionKey) ([]resolve.Version, error) {
 	if isNpmBundle(vk.PackageKey) { // bundled dependencies, fallback to deps.dev client
 		return c.fallback.MatchingVersions(ctx, vk)
@@ -213,7 +280,7 @@ func (c *NpmRegistryClient) MatchingVersions(ctx context.Context, vk resolve.Ver
 		}
 	}
 
-	return resolve.MatchRequirement(vk, resVersions), nil
+	return util.MatchRequirement(vk, resVersions), nil
 }
 
 func isNpmBundle(pk resolve.PackageKey) bool {
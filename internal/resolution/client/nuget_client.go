@@ -0,0 +1,138 @@
+package client
+
+import (
+	"context"
+	"encoding/gob"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"deps.dev/util/resolve"
+	"deps.dev/util/resolve/dep"
+	"deps.dev/util/semver"
+	"github.com/google/osv-scanner/internal/resolution/datasource"
+	"github.com/google/osv-scanner/internal/resolution/util"
+)
+
+const nugetCacheExt = ".resolve.nuget"
+
+// NuGetClient is a DependencyClient for .NET packages, using the nuget.org V3 API.
+type NuGetClient struct {
+	api *datasource.NuGetAPIClient
+}
+
+func NewNuGetClient(cfg datasource.RegistryConfig) (*NuGetClient, error) {
+	api, err := datasource.NewNuGetAPIClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &NuGetClient{api: api}, nil
+}
+
+func (c *NuGetClient) Version(ctx context.Context, vk resolve.VersionKey) (resolve.Version, error) {
+	return resolve.Version{VersionKey: vk}, nil
+}
+
+func (c *NuGetClient) Versions(ctx context.Context, pk resolve.PackageKey) ([]resolve.Version, error) {
+	if pk.System != util.NuGet {
+		return nil, fmt.Errorf("unsupported system: %v", pk.System)
+	}
+
+	vers, err := c.api.Versions(ctx, pk.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	vks := make([]resolve.Version, len(vers))
+	for i, v := range vers {
+		vks[i] = resolve.Version{
+			VersionKey: resolve.VersionKey{
+				PackageKey:  pk,
+				Version:     v,
+				VersionType: resolve.Concrete,
+			}}
+	}
+
+	slices.SortFunc(vks, func(a, b resolve.Version) int { return semver.NuGet.Compare(a.Version, b.Version) })
+
+	return vks, nil
+}
+
+func (c *NuGetClient) Requirements(ctx context.Context, vk resolve.VersionKey) ([]resolve.RequirementVersion, error) {
+	if vk.System != util.NuGet {
+		return nil, fmt.Errorf("unsupported system: %v", vk.System)
+	}
+
+	groups, err := c.api.Dependencies(ctx, vk.Name, vk.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	// TODO: select the dependency group for the project's target framework,
+	// the dependencies of all frameworks are currently combined, using the first listed requirement of each package.
+	var deps []resolve.RequirementVersion
+	seen := make(map[string]struct{})
+	for _, g := range groups {
+		for _, d := range g.Dependencies {
+			if _, ok := seen[strings.ToLower(d.ID)]; ok {
+				continue
+			}
+			seen[strings.ToLower(d.ID)] = struct{}{}
+			deps = append(deps, resolve.RequirementVersion{
+				Type: dep.NewType(),
+				VersionKey: resolve.VersionKey{
+					PackageKey: resolve.PackageKey{
+						System: util.NuGet,
+						Name:   d.ID,
+					},
+					Version:     d.Version,
+					VersionType: resolve.Requirement,
+				},
+			})
+		}
+	}
+	resolve.SortDependencies(deps)
+
+	return deps, nil
+}
+
+func (c *NuGetClient) MatchingVersions(ctx context.Context, vk resolve.VersionKey) ([]resolve.Version, error) {
+	vers, err := c.Versions(ctx, vk.PackageKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return util.MatchRequirement(vk, vers), nil
+}
+
+func (c *NuGetClient) PreFetch(ctx context.Context, requirements []resolve.RequirementVersion, manifestPath string) {
+	// It doesn't matter if loading the cache fails
+	_ = c.LoadCache(manifestPath)
+
+	for _, req := range requirements {
+		go c.Versions(ctx, req.PackageKey) //nolint:errcheck
+	}
+	// don't bother waiting for goroutines to finish.
+}
+
+func (c *NuGetClient) WriteCache(path string) error {
+	f, err := os.Create(path + nugetCacheExt)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return gob.NewEncoder(f).Encode(c.api)
+}
+
+func (c *NuGetClient) LoadCache(path string) error {
+	f, err := os.Open(path + nugetCacheExt)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return gob.NewDecoder(f).Decode(&c.api)
+}
@@ -0,0 +1,201 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"deps.dev/util/resolve"
+	"deps.dev/util/resolve/dep"
+	"github.com/google/osv-scanner/internal/resolution/util"
+)
+
+// OfflineClient is a DependencyClient that serves package information from a local snapshot file,
+// for running guided remediation without network access (e.g. in air-gapped environments).
+//
+// The snapshot is a JSON file in the format of OfflineSnapshot, which can be exported from deps.dev or a package registry.
+type OfflineClient struct {
+	versions map[resolve.PackageKey][]resolve.Version
+	reqs     map[resolve.VersionKey][]resolve.RequirementVersion
+}
+
+// OfflineSnapshot is the file format of the OfflineClient's snapshot.
+type OfflineSnapshot struct {
+	Packages []OfflinePackage `json:"packages"`
+}
+
+type OfflinePackage struct {
+	System   string           `json:"system"` // e.g. "npm", "maven", "pypi", "go", "cargo", "nuget"
+	Name     string           `json:"name"`
+	Versions []OfflineVersion `json:"versions"`
+}
+
+type OfflineVersion struct {
+	Version      string               `json:"version"`
+	Requirements []OfflineRequirement `json:"requirements"`
+}
+
+type OfflineRequirement struct {
+	Name     string `json:"name"`
+	Version  string `json:"version"`
+	Dev      bool   `json:"dev,omitempty"`
+	Optional bool   `json:"optional,omitempty"`
+	Scope    string `json:"scope,omitempty"`
+	KnownAs  string `json:"knownAs,omitempty"`
+	// Attrs are any other attributes of the dependency type, keyed by their names in offlineAttrs.
+	Attrs map[string]string `json:"attrs,omitempty"`
+}
+
+// offlineAttrs are the names of the dependency type attributes that are not fields of OfflineRequirement.
+var offlineAttrs = map[string]dep.AttrKey{
+	"test":                  dep.Test,
+	"xtest":                 dep.XTest,
+	"framework":             dep.Framework,
+	"mavenClassifier":       dep.MavenClassifier,
+	"mavenArtifactType":     dep.MavenArtifactType,
+	"mavenDependencyOrigin": dep.MavenDependencyOrigin,
+	"mavenExclusions":       dep.MavenExclusions,
+	"enabledDependencies":   dep.EnabledDependencies,
+	"environment":           dep.Environment,
+	"selector":              dep.Selector,
+}
+
+var offlineSystems = map[string]resolve.System{
+	"npm":   resolve.NPM,
+	"maven": resolve.Maven,
+	"pypi":  util.PyPI,
+	"go":    util.Go,
+	"cargo": util.Cargo,
+	"nuget": util.NuGet,
+}
+
+// NewOfflineClient loads the snapshot file at path.
+func NewOfflineClient(path string) (*OfflineClient, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read offline snapshot: %w", err)
+	}
+	var snapshot OfflineSnapshot
+	if err := json.Unmarshal(b, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to parse offline snapshot %s: %w", path, err)
+	}
+
+	c := &OfflineClient{
+		versions: make(map[resolve.PackageKey][]resolve.Version),
+		reqs:     make(map[resolve.VersionKey][]resolve.RequirementVersion),
+	}
+	for _, p := range snapshot.Packages {
+		sys, ok := offlineSystems[strings.ToLower(p.System)]
+		if !ok {
+			return nil, fmt.Errorf("unsupported system %q in offline snapshot", p.System)
+		}
+		pk := resolve.PackageKey{System: sys, Name: p.Name}
+		for _, v := range p.Versions {
+			vk := resolve.VersionKey{
+				PackageKey:  pk,
+				Version:     v.Version,
+				VersionType: resolve.Concrete,
+			}
+			c.versions[pk] = append(c.versions[pk], resolve.Version{VersionKey: vk})
+			deps := make([]resolve.RequirementVersion, len(v.Requirements))
+			for i, r := range v.Requirements {
+				if deps[i], err = r.requirementVersion(sys); err != nil {
+					return nil, fmt.Errorf("invalid requirement of %s@%s in offline snapshot: %w", p.Name, v.Version, err)
+				}
+			}
+			resolve.SortDependencies(deps)
+			c.reqs[vk] = deps
+		}
+	}
+	for pk, vers := range c.versions {
+		slices.SortFunc(vers, func(a, b resolve.Version) int { return util.Semver(pk.System).Compare(a.Version, b.Version) })
+	}
+
+	return c, nil
+}
+
+func (r OfflineRequirement) requirementVersion(sys resolve.System) (resolve.RequirementVersion, error) {
+	typ := dep.NewType()
+	if r.Dev {
+		typ.AddAttr(dep.Dev, "")
+	}
+	if r.Optional {
+		typ.AddAttr(dep.Opt, "")
+	}
+	if r.Scope != "" {
+		typ.AddAttr(dep.Scope, r.Scope)
+	}
+	if r.KnownAs != "" {
+		typ.AddAttr(dep.KnownAs, r.KnownAs)
+	}
+	for name, value := range r.Attrs {
+		key, ok := offlineAttrs[name]
+		if !ok {
+			return resolve.RequirementVersion{}, fmt.Errorf("unknown dependency attribute %q of %s", name, r.Name)
+		}
+		typ.AddAttr(key, value)
+	}
+
+	return resolve.RequirementVersion{
+		Type: typ,
+		VersionKey: resolve.VersionKey{
+			PackageKey: resolve.PackageKey{
+				System: sys,
+				Name:   r.Name,
+			},
+			Version:     r.Version,
+			VersionType: resolve.Requirement,
+		},
+	}, nil
+}
+
+func (c *OfflineClient) Version(ctx context.Context, vk resolve.VersionKey) (resolve.Version, error) {
+	if _, ok := c.reqs[vk]; !ok {
+		return resolve.Version{}, fmt.Errorf("%s@%s not found in offline snapshot", vk.Name, vk.Version)
+	}
+
+	return resolve.Version{VersionKey: vk}, nil
+}
+
+func (c *OfflineClient) Versions(ctx context.Context, pk resolve.PackageKey) ([]resolve.Version, error) {
+	vers, ok := c.versions[pk]
+	if !ok {
+		return nil, fmt.Errorf("%s not found in offline snapshot", pk.Name)
+	}
+
+	return slices.Clone(vers), nil
+}
+
+func (c *OfflineClient) Requirements(ctx context.Context, vk resolve.VersionKey) ([]resolve.RequirementVersion, error) {
+	reqs, ok := c.reqs[vk]
+	if !ok {
+		return nil, fmt.Errorf("%s@%s not found in offline snapshot", vk.Name, vk.Version)
+	}
+
+	return slices.Clone(reqs), nil
+}
+
+func (c *OfflineClient) MatchingVersions(ctx context.Context, vk resolve.VersionKey) ([]resolve.Version, error) {
+	vers, err := c.Versions(ctx, vk.PackageKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return util.MatchRequirement(vk, vers), nil
+}
+
+// The snapshot is already local, so there is nothing to cache or prefetch.
+
+func (c *OfflineClient) PreFetch(context.Context, []resolve.RequirementVersion, string) {}
+
+func (c *OfflineClient) WriteCache(string) error {
+	return nil
+}
+
+func (c *OfflineClient) LoadCache(string) error {
+	return errors.New("offline client has no cache")
+}
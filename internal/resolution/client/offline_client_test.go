@@ -0,0 +1,122 @@
+package client_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"deps.dev/util/resolve"
+	"deps.dev/util/resolve/dep"
+	"github.com/google/osv-scanner/internal/resolution/client"
+)
+
+func TestNewOfflineClient_Errors(t *testing.T) {
+	t.Parallel()
+
+	for _, path := range []string{
+		"fixtures/offline/does-not-exist.json",
+		"fixtures/offline/not-json.txt",
+		"fixtures/offline/unsupported-system.json",
+	} {
+		if _, err := client.NewOfflineClient(path); err == nil {
+			t.Errorf("NewOfflineClient(%q) succeeded, want error", path)
+		}
+	}
+}
+
+func TestOfflineClient(t *testing.T) {
+	t.Parallel()
+
+	cl, err := client.NewOfflineClient("fixtures/offline/snapshot.json")
+	if err != nil {
+		t.Fatalf("NewOfflineClient() error = %v", err)
+	}
+	ctx := context.Background()
+	foo := resolve.PackageKey{System: resolve.NPM, Name: "foo"}
+	concrete := func(pk resolve.PackageKey, version string) resolve.Version {
+		return resolve.Version{VersionKey: resolve.VersionKey{PackageKey: pk, Version: version, VersionType: resolve.Concrete}}
+	}
+
+	t.Run("versions are sorted", func(t *testing.T) {
+		t.Parallel()
+
+		got, err := cl.Versions(ctx, foo)
+		if err != nil {
+			t.Fatalf("Versions() error = %v", err)
+		}
+		want := []resolve.Version{concrete(foo, "1.2.0"), concrete(foo, "1.9.0"), concrete(foo, "1.10.0")}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Versions() = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("matching versions", func(t *testing.T) {
+		t.Parallel()
+
+		req := resolve.VersionKey{PackageKey: foo, Version: "~1.9.0", VersionType: resolve.Requirement}
+		got, err := cl.MatchingVersions(ctx, req)
+		if err != nil {
+			t.Fatalf("MatchingVersions() error = %v", err)
+		}
+		want := []resolve.Version{concrete(foo, "1.9.0")}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("MatchingVersions() = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("requirements keep their dependency types", func(t *testing.T) {
+		t.Parallel()
+
+		got, err := cl.Requirements(ctx, concrete(foo, "1.10.0").VersionKey)
+		if err != nil {
+			t.Fatalf("Requirements() error = %v", err)
+		}
+		types := make(map[string]dep.Type)
+		for _, r := range got {
+			types[r.Name] = r.Type
+		}
+		if len(got) != 3 {
+			t.Fatalf("Requirements() = %v, want 3 requirements", got)
+		}
+		if typ := types["bar"]; !typ.IsRegular() {
+			t.Errorf("bar type = %v, want regular", typ)
+		}
+		if typ := types["baz"]; !typ.HasAttr(dep.Dev) {
+			t.Errorf("baz type = %v, want dev", typ)
+		}
+		alias := types["qux-alias"]
+		if knownAs, _ := alias.GetAttr(dep.KnownAs); !alias.HasAttr(dep.Opt) || knownAs != "qux" {
+			t.Errorf("qux-alias type = %v, want optional and known as qux", alias)
+		}
+
+		junit := resolve.PackageKey{System: resolve.Maven, Name: "junit:junit"}
+		got, err = cl.Requirements(ctx, concrete(junit, "4.13.2").VersionKey)
+		if err != nil {
+			t.Fatalf("Requirements() error = %v", err)
+		}
+		if scope, _ := got[0].Type.GetAttr(dep.Scope); len(got) != 1 || got[0].System != resolve.Maven || scope != "compile" {
+			t.Errorf("Requirements() = %v, want a compile scoped Maven requirement", got)
+		}
+	})
+
+	t.Run("systems are case-insensitive", func(t *testing.T) {
+		t.Parallel()
+
+		bar := resolve.PackageKey{System: resolve.NPM, Name: "bar"}
+		if _, err := cl.Version(ctx, concrete(bar, "2.1.0").VersionKey); err != nil {
+			t.Errorf("Version() error = %v", err)
+		}
+	})
+
+	t.Run("missing packages are errors", func(t *testing.T) {
+		t.Parallel()
+
+		missing := resolve.PackageKey{System: resolve.NPM, Name: "missing"}
+		if _, err := cl.Versions(ctx, missing); err == nil {
+			t.Errorf("Versions() of a missing package succeeded, want error")
+		}
+		if _, err := cl.Requirements(ctx, concrete(foo, "9.9.9").VersionKey); err == nil {
+			t.Errorf("Requirements() of a missing version succeeded, want error")
+		}
+	})
+}
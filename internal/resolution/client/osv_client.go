@@ -5,90 +5,179 @@ import (
 
 	"deps.dev/util/resolve"
 	"github.com/google/osv-scanner/internal/resolution/util"
-	"github.com/google/osv-scanner/internal/utility/vulns"
 	"github.com/google/osv-scanner/pkg/models"
 	"github.com/google/osv-scanner/pkg/osv"
 	"golang.org/x/exp/maps"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// osvQueryBatchSize is the maximum number of queries in a single OSV querybatch request.
+	osvQueryBatchSize = 1000
+	// osvMaxConcurrentBatches bounds the number of querybatch requests in flight at once.
+	osvMaxConcurrentBatches = 4
+	// osvMaxConcurrentGets bounds the number of requests for full vulnerability records in flight at once.
+	osvMaxConcurrentGets = 25
 )
 
 type OSVClient struct {
-	// vulnCache caches all vulnerabilities affecting any versions of particular packages.
-	// We cache call vulns & manually check affected, rather than querying the affected versions directly
-	// since remediation needs to query for OSV vulnerabilities multiple times for the same packages.
-	vulnCache sync.Map // map[resolve.PackageKey][]models.Vulnerability
-	// TODO: This tends to get the full info of a lot of vulns that never show up in the dependency graphs.
-	// Worst case is something like PyPI:tensorflow, which has >600 vulns across all versions, but a specific version may be affected by 0.
+	// vulnCache caches the vulnerabilities affecting particular package versions.
+	// Remediation queries for OSV vulnerabilities multiple times for largely the same graphs,
+	// so only the versions not seen before need to be queried.
+	vulnCache sync.Map // map[resolve.VersionKey][]models.Vulnerability
+	// recordCache caches the full vulnerability records, which are shared by many package versions.
+	recordCache sync.Map // map[string]models.Vulnerability
+
+	// request and get make the OSV API requests for querybatch and full vulnerability records
+	request func(osv.BatchedQuery) (*osv.BatchedResponse, error)
+	get     func(id string) (*models.Vulnerability, error)
 }
 
 func NewOSVClient() *OSVClient {
-	return &OSVClient{}
+	return &OSVClient{
+		request: osv.MakeRequest,
+		get:     osv.Get,
+	}
 }
 
 func (c *OSVClient) FindVulns(g *resolve.Graph) ([]models.Vulnerabilities, error) {
-	// Determine which packages we don't already have cached
-	toQuery := make(map[resolve.PackageKey]struct{})
+	// Determine which package versions we don't already have cached
+	toQuery := make(map[resolve.VersionKey]struct{})
 	for _, node := range g.Nodes[1:] { // skipping the root node
-		pk := node.Version.PackageKey
-		if _, ok := c.vulnCache.Load(pk); !ok {
-			toQuery[pk] = struct{}{}
+		vk := node.Version
+		if _, ok := c.vulnCache.Load(vk); !ok {
+			toQuery[vk] = struct{}{}
 		}
 	}
 
-	// Query OSV for the missing records
 	if len(toQuery) > 0 {
-		pks := maps.Keys(toQuery)
-		var batchRequest osv.BatchedQuery
-		batchRequest.Queries = make([]*osv.Query, len(pks))
-		for i, pk := range pks {
-			batchRequest.Queries[i] = &osv.Query{
-				Package: osv.Package{
-					Name:      pk.Name,
-					Ecosystem: string(util.OSVEcosystem[pk.System]),
-				},
-				// Omitting the Version from the query gets all vulns affecting any version of the package
-				// (I'm not actually sure if this behaviour is explicitly documented anywhere)
-			}
-		}
-		batchResponse, err := osv.MakeRequest(batchRequest)
-		if err != nil {
+		if err := c.queryVersions(maps.Keys(toQuery)); err != nil {
 			return nil, err
 		}
-		hydrated, err := osv.Hydrate(batchResponse)
-		if err != nil {
-			return nil, err
-		}
-		// fill in the cache with the responses
-		for i, pk := range pks {
-			c.vulnCache.Store(pk, hydrated.Results[i].Vulns)
-		}
 	}
 
-	// Compute the actual affected vulnerabilities for each node
+	// Collect the cached vulnerabilities for each node
 	nodeVulns := make([]models.Vulnerabilities, len(g.Nodes))
 	// For convenience, include the root node as an empty slice in the results
 	for i, n := range g.Nodes {
 		if i == 0 {
 			continue
 		}
-		pkgVulnsAny, ok := c.vulnCache.Load(n.Version.PackageKey)
+		vulnsAny, ok := c.vulnCache.Load(n.Version)
 		if !ok {
 			// This should be impossible
 			panic("vulnerability caching failed")
 		}
-		pkgVulns, ok := pkgVulnsAny.([]models.Vulnerability)
+		vulns, ok := vulnsAny.([]models.Vulnerability)
 		if !ok {
 			panic("vulnerability caching failed")
 		}
+		nodeVulns[i] = vulns
+	}
+
+	return nodeVulns, nil
+}
+
+// queryVersions queries OSV for the vulnerabilities of the package versions in concurrent batches,
+// then fetches the full records of the vulnerabilities found, and fills in the cache.
+func (c *OSVClient) queryVersions(vks []resolve.VersionKey) error {
+	batchIDs := make([][][]string, (len(vks)+osvQueryBatchSize-1)/osvQueryBatchSize)
+	var eg errgroup.Group
+	eg.SetLimit(osvMaxConcurrentBatches)
+	for b := range batchIDs {
+		b := b
+		batch := vks[b*osvQueryBatchSize : min((b+1)*osvQueryBatchSize, len(vks))]
+		eg.Go(func() error {
+			ids, err := queryBatch(batch, c.request)
+			if err != nil {
+				return err
+			}
+			batchIDs[b] = ids
+
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return err
+	}
+
+	// Fetch each vulnerability record not already known only once, as many versions share the same vulnerabilities
+	toGet := make(map[string]struct{})
+	for _, ids := range batchIDs {
+		for _, vulnIDs := range ids {
+			for _, id := range vulnIDs {
+				if _, ok := c.recordCache.Load(id); !ok {
+					toGet[id] = struct{}{}
+				}
+			}
+		}
+	}
+	var getGroup errgroup.Group
+	getGroup.SetLimit(osvMaxConcurrentGets)
+	for id := range toGet {
+		id := id
+		getGroup.Go(func() error {
+			vuln, err := c.get(id)
+			if err != nil {
+				return err
+			}
+			c.recordCache.Store(id, *vuln)
 
-		var affectedVulns []models.Vulnerability
-		pkgDetails := util.VKToPackageDetails(n.Version)
-		for _, vuln := range pkgVulns {
-			if vulns.IsAffected(vuln, pkgDetails) {
-				affectedVulns = append(affectedVulns, vuln)
+			return nil
+		})
+	}
+	if err := getGroup.Wait(); err != nil {
+		return err
+	}
+
+	// fill in the cache with the responses
+	for b, ids := range batchIDs {
+		for i, vulnIDs := range ids {
+			vulns := make([]models.Vulnerability, 0, len(vulnIDs))
+			for _, id := range vulnIDs {
+				v, _ := c.recordCache.Load(id)
+				vulns = append(vulns, v.(models.Vulnerability))
 			}
+			c.vulnCache.Store(vks[b*osvQueryBatchSize+i], vulns)
 		}
-		nodeVulns[i] = affectedVulns
 	}
 
-	return nodeVulns, nil
+	return nil
+}
+
+// queryBatch queries OSV for the IDs of the vulnerabilities affecting each package version in a single batch.
+// Packages with more vulnerabilities than fit in one response are re-queried for the remaining pages.
+func queryBatch(batch []resolve.VersionKey, request func(osv.BatchedQuery) (*osv.BatchedResponse, error)) ([][]string, error) {
+	ids := make([][]string, len(batch))
+	queries := make([]*osv.Query, len(batch))
+	pending := make([]int, len(batch))
+	for i, vk := range batch {
+		queries[i] = osv.MakePkgRequest(util.VKToPackageDetails(vk))
+		pending[i] = i
+	}
+
+	for len(pending) > 0 {
+		var query osv.BatchedQuery
+		for _, i := range pending {
+			query.Queries = append(query.Queries, queries[i])
+		}
+		resp, err := request(query)
+		if err != nil {
+			return nil, err
+		}
+		var next []int
+		for j, r := range resp.Results {
+			i := pending[j]
+			for _, v := range r.Vulns {
+				ids[i] = append(ids[i], v.ID)
+			}
+			if r.NextPageToken != "" {
+				queries[i].PageToken = r.NextPageToken
+				next = append(next, i)
+			}
+		}
+		pending = next
+	}
+
+	return ids, nil
 }
@@ -0,0 +1,184 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"deps.dev/util/resolve"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/osv"
+)
+
+func TestQueryBatch_Pagination(t *testing.T) {
+	t.Parallel()
+
+	// The vulnerability IDs returned in each page of results for each package
+	pages := map[string][][]string{
+		"foo": {{"GHSA-1", "GHSA-2"}, {"GHSA-3"}, {"GHSA-4"}},
+		"bar": {{"GHSA-5"}},
+		"baz": {nil},
+	}
+	var requested []int // the number of queries in each request
+	request := func(q osv.BatchedQuery) (*osv.BatchedResponse, error) {
+		requested = append(requested, len(q.Queries))
+		var resp osv.BatchedResponse
+		for _, query := range q.Queries {
+			page := 0
+			if query.PageToken != "" {
+				var err error
+				if page, err = strconv.Atoi(query.PageToken); err != nil {
+					return nil, err
+				}
+			}
+			var r osv.MinimalResponse
+			for _, id := range pages[query.Package.Name][page] {
+				r.Vulns = append(r.Vulns, osv.MinimalVulnerability{ID: id})
+			}
+			if page+1 < len(pages[query.Package.Name]) {
+				r.NextPageToken = strconv.Itoa(page + 1)
+			}
+			resp.Results = append(resp.Results, r)
+		}
+
+		return &resp, nil
+	}
+
+	var batch []resolve.VersionKey
+	for _, name := range []string{"foo", "bar", "baz"} {
+		batch = append(batch, resolve.VersionKey{
+			PackageKey:  resolve.PackageKey{System: resolve.NPM, Name: name},
+			Version:     "1.0.0",
+			VersionType: resolve.Concrete,
+		})
+	}
+
+	got, err := queryBatch(batch, request)
+	if err != nil {
+		t.Fatalf("queryBatch() error = %v", err)
+	}
+	want := [][]string{{"GHSA-1", "GHSA-2", "GHSA-3", "GHSA-4"}, {"GHSA-5"}, nil}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("queryBatch() = %v, want %v", got, want)
+	}
+	// Only the packages with more pages are queried again
+	if wantRequested := []int{3, 1, 1}; !reflect.DeepEqual(requested, wantRequested) {
+		t.Errorf("queryBatch() made requests of %v queries, want %v", requested, wantRequested)
+	}
+}
+
+func TestQueryBatch_Error(t *testing.T) {
+	t.Parallel()
+
+	errRequest := errors.New("request failed")
+	batch := []resolve.VersionKey{{PackageKey: resolve.PackageKey{System: resolve.NPM, Name: "foo"}, Version: "1.0.0"}}
+	_, err := queryBatch(batch, func(osv.BatchedQuery) (*osv.BatchedResponse, error) { return nil, errRequest })
+	if !errors.Is(err, errRequest) {
+		t.Errorf("queryBatch() error = %v, want %v", err, errRequest)
+	}
+}
+
+func TestOSVClient_FindVulns(t *testing.T) {
+	t.Parallel()
+
+	// every package is affected by GHSA-shared, and each tenth package by its own vulnerability as well
+	vulnIDs := func(name string) []string {
+		ids := []string{"GHSA-shared"}
+		if n, _ := strconv.Atoi(name[len("pkg"):]); n%10 == 0 {
+			ids = append(ids, "GHSA-"+name)
+		}
+
+		return ids
+	}
+
+	var mu sync.Mutex
+	var requested []int // the number of queries in each request
+	got := make(map[string]int)
+	var inFlight, maxInFlight int
+	cl := &OSVClient{
+		request: func(q osv.BatchedQuery) (*osv.BatchedResponse, error) {
+			mu.Lock()
+			requested = append(requested, len(q.Queries))
+			mu.Unlock()
+			var resp osv.BatchedResponse
+			for _, query := range q.Queries {
+				var r osv.MinimalResponse
+				for _, id := range vulnIDs(query.Package.Name) {
+					r.Vulns = append(r.Vulns, osv.MinimalVulnerability{ID: id})
+				}
+				resp.Results = append(resp.Results, r)
+			}
+
+			return &resp, nil
+		},
+		get: func(id string) (*models.Vulnerability, error) {
+			mu.Lock()
+			inFlight++
+			maxInFlight = max(maxInFlight, inFlight)
+			got[id]++
+			mu.Unlock()
+			time.Sleep(time.Millisecond)
+			mu.Lock()
+			inFlight--
+			mu.Unlock()
+
+			return &models.Vulnerability{ID: id}, nil
+		},
+	}
+
+	g := &resolve.Graph{}
+	g.AddNode(resolve.VersionKey{PackageKey: resolve.PackageKey{System: resolve.NPM, Name: "root"}, Version: "1.0.0"})
+	for i := 0; i < 2500; i++ {
+		g.AddNode(resolve.VersionKey{PackageKey: resolve.PackageKey{System: resolve.NPM, Name: fmt.Sprintf("pkg%d", i)}, Version: "1.0.0", VersionType: resolve.Concrete})
+	}
+
+	nodeVulns, err := cl.FindVulns(g)
+	if err != nil {
+		t.Fatalf("FindVulns() error = %v", err)
+	}
+	for i, vulns := range nodeVulns {
+		var ids []string
+		for _, v := range vulns {
+			ids = append(ids, v.ID)
+		}
+		var want []string
+		if i > 0 {
+			want = vulnIDs(g.Nodes[i].Version.Name)
+		}
+		if !reflect.DeepEqual(ids, want) {
+			t.Errorf("FindVulns() node %d vulns = %v, want %v", i, ids, want)
+		}
+	}
+
+	// the versions are chunked into batches of the API's size limit
+	slices.Sort(requested)
+	if want := []int{500, 1000, 1000}; !reflect.DeepEqual(requested, want) {
+		t.Errorf("FindVulns() made requests of %v queries, want %v", requested, want)
+	}
+	// each record is fetched once, with bounded concurrency
+	if len(got) != 251 {
+		t.Errorf("FindVulns() got %d records, want 251", len(got))
+	}
+	for id, n := range got {
+		if n != 1 {
+			t.Errorf("FindVulns() got %s %d times, want once", id, n)
+		}
+	}
+	if maxInFlight > osvMaxConcurrentGets {
+		t.Errorf("FindVulns() made %d concurrent requests for records, want at most %d", maxInFlight, osvMaxConcurrentGets)
+	}
+
+	// versions already seen are cached
+	requested = nil
+	if _, err := cl.FindVulns(g); err != nil {
+		t.Fatalf("FindVulns() error = %v", err)
+	}
+	if len(requested) != 0 {
+		t.Errorf("FindVulns() made requests of %v queries for cached versions, want none", requested)
+	}
+}
@@ -0,0 +1,82 @@
+package client
+
+import (
+	"sync"
+
+	"deps.dev/util/resolve"
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+// VulnSource is where a VulnSourceClient found a vulnerability.
+type VulnSource string
+
+const (
+	VulnSourceLocal VulnSource = "local" // a locally downloaded OSV database
+	VulnSourceAPI   VulnSource = "api"   // the OSV API
+)
+
+// VulnSourceClient is implemented by VulnerabilityClients that find vulnerabilities from multiple sources.
+type VulnSourceClient interface {
+	// VulnSource returns where the vulnerabilities of the package version were found,
+	// or false if it has not been checked for vulnerabilities.
+	VulnSource(vk resolve.VersionKey) (VulnSource, bool)
+}
+
+// OSVHybridClient is a VulnerabilityClient that checks the local OSV databases first,
+// falling back to the OSV API for the ecosystems that do not have a database downloaded.
+type OSVHybridClient struct {
+	local  *OSVOfflineClient
+	online VulnerabilityClient
+
+	sources sync.Map // map[resolve.VersionKey]VulnSource
+}
+
+func NewOSVHybridClient(local *OSVOfflineClient, online VulnerabilityClient) *OSVHybridClient {
+	return &OSVHybridClient{local: local, online: online}
+}
+
+func (c *OSVHybridClient) FindVulns(g *resolve.Graph) ([]models.Vulnerabilities, error) {
+	nodeVulns := make([]models.Vulnerabilities, len(g.Nodes))
+	// The nodes without a local database are queried together in a graph of their own,
+	// with a placeholder root node as FindVulns skips the root
+	onlineGraph := &resolve.Graph{}
+	onlineGraph.AddNode(g.Nodes[0].Version)
+	var onlineNodes []int
+	for i, n := range g.Nodes[1:] {
+		vk := n.Version
+		if !c.local.HasDatabase(vk.System) {
+			onlineGraph.AddNode(vk)
+			onlineNodes = append(onlineNodes, i+1)
+
+			continue
+		}
+		vs, err := c.local.packageVulns(vk)
+		if err != nil {
+			return nil, err
+		}
+		nodeVulns[i+1] = vs
+		c.sources.Store(vk, VulnSourceLocal)
+	}
+
+	if len(onlineNodes) > 0 {
+		onlineVulns, err := c.online.FindVulns(onlineGraph)
+		if err != nil {
+			return nil, err
+		}
+		for j, i := range onlineNodes {
+			nodeVulns[i] = onlineVulns[j+1]
+			c.sources.Store(g.Nodes[i].Version, VulnSourceAPI)
+		}
+	}
+
+	return nodeVulns, nil
+}
+
+func (c *OSVHybridClient) VulnSource(vk resolve.VersionKey) (VulnSource, bool) {
+	src, ok := c.sources.Load(vk)
+	if !ok {
+		return "", false
+	}
+
+	return src.(VulnSource), true
+}
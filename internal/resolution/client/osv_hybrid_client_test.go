@@ -0,0 +1,100 @@
+package client_test
+
+import (
+	"reflect"
+	"testing"
+
+	"deps.dev/util/resolve"
+	"github.com/google/osv-scanner/internal/resolution/client"
+	"github.com/google/osv-scanner/internal/resolution/util"
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+// onlineVulnClient finds a vulnerability in every package version, recording the graphs it is asked about.
+type onlineVulnClient struct {
+	graphs []*resolve.Graph
+}
+
+func (c *onlineVulnClient) FindVulns(g *resolve.Graph) ([]models.Vulnerabilities, error) {
+	c.graphs = append(c.graphs, g)
+	nodeVulns := make([]models.Vulnerabilities, len(g.Nodes))
+	for i, n := range g.Nodes[1:] {
+		nodeVulns[i+1] = models.Vulnerabilities{{ID: "PYSEC-" + n.Version.Name}}
+	}
+
+	return nodeVulns, nil
+}
+
+func TestOSVHybridClient(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeOfflineDB(t, dir, models.EcosystemNPM, []models.Vulnerability{{
+		ID: "GHSA-foo",
+		Affected: []models.Affected{{
+			Package: models.Package{Ecosystem: models.EcosystemNPM, Name: "foo"},
+			Ranges:  []models.Range{{Type: models.RangeSemVer, Events: []models.Event{{Introduced: "0"}}}},
+		}},
+	}})
+	online := &onlineVulnClient{}
+	cl := client.NewOSVHybridClient(client.NewOSVOfflineClient(dir), online)
+
+	vk := func(sys resolve.System, name string) resolve.VersionKey {
+		return resolve.VersionKey{PackageKey: resolve.PackageKey{System: sys, Name: name}, Version: "1.0.0", VersionType: resolve.Concrete}
+	}
+	g := &resolve.Graph{}
+	for _, v := range []resolve.VersionKey{vk(resolve.NPM, "root"), vk(resolve.NPM, "foo"), vk(util.PyPI, "bar"), vk(resolve.NPM, "baz")} {
+		g.AddNode(v)
+	}
+
+	nodeVulns, err := cl.FindVulns(g)
+	if err != nil {
+		t.Fatalf("FindVulns() error = %v", err)
+	}
+	got := make([][]string, len(nodeVulns))
+	for i, vulns := range nodeVulns {
+		for _, v := range vulns {
+			got[i] = append(got[i], v.ID)
+		}
+	}
+	want := [][]string{nil, {"GHSA-foo"}, {"PYSEC-bar"}, nil}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FindVulns() = %v, want %v", got, want)
+	}
+
+	// only the package without a local database is queried online, in a single graph
+	if len(online.graphs) != 1 {
+		t.Fatalf("FindVulns() queried online %d times, want once", len(online.graphs))
+	}
+	var queried []string
+	for _, n := range online.graphs[0].Nodes[1:] {
+		queried = append(queried, n.Version.Name)
+	}
+	if !reflect.DeepEqual(queried, []string{"bar"}) {
+		t.Errorf("FindVulns() queried %v online, want [bar]", queried)
+	}
+
+	for _, tt := range []struct {
+		vk     resolve.VersionKey
+		want   client.VulnSource
+		wantOk bool
+	}{
+		{vk: vk(resolve.NPM, "foo"), want: client.VulnSourceLocal, wantOk: true},
+		{vk: vk(resolve.NPM, "baz"), want: client.VulnSourceLocal, wantOk: true},
+		{vk: vk(util.PyPI, "bar"), want: client.VulnSourceAPI, wantOk: true},
+		{vk: vk(resolve.NPM, "not-checked"), want: "", wantOk: false},
+	} {
+		if got, ok := cl.VulnSource(tt.vk); got != tt.want || ok != tt.wantOk {
+			t.Errorf("VulnSource(%v) = %q, %v, want %q, %v", tt.vk, got, ok, tt.want, tt.wantOk)
+		}
+	}
+
+	// graphs covered by local databases are not queried online
+	online.graphs = nil
+	if _, err := cl.FindVulns(&resolve.Graph{Nodes: g.Nodes[:2]}); err != nil {
+		t.Fatalf("FindVulns() error = %v", err)
+	}
+	if len(online.graphs) != 0 {
+		t.Errorf("FindVulns() queried online %d times for local packages, want none", len(online.graphs))
+	}
+}
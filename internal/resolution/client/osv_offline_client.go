@@ -0,0 +1,110 @@
+package client
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+
+	"deps.dev/util/resolve"
+	"github.com/google/osv-scanner/internal/local"
+	"github.com/google/osv-scanner/internal/resolution/util"
+	"github.com/google/osv-scanner/internal/utility/vulns"
+	"github.com/google/osv-scanner/pkg/lockfile"
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+// OSVOfflineClient is a VulnerabilityClient that matches package versions against
+// the OSV databases (the all.zip of each ecosystem) already downloaded to the local database directory,
+// so vulnerabilities can be found without any network access.
+type OSVOfflineClient struct {
+	localDBPath string
+
+	mu      sync.Mutex
+	indexes map[resolve.System]*offlineVulnIndex
+}
+
+// offlineVulnIndex is the vulnerabilities of an ecosystem's database, keyed by the names of the affected packages.
+type offlineVulnIndex struct {
+	vulns map[string][]models.Vulnerability
+	err   error // set if the database could not be loaded
+}
+
+// NewOSVOfflineClient creates a client using the databases in localDBPath,
+// or in the default local database directory if it is empty.
+func NewOSVOfflineClient(localDBPath string) *OSVOfflineClient {
+	return &OSVOfflineClient{
+		localDBPath: localDBPath,
+		indexes:     make(map[resolve.System]*offlineVulnIndex),
+	}
+}
+
+// index loads and indexes the ecosystem's database the first time it is needed.
+func (c *OSVOfflineClient) index(sys resolve.System) *offlineVulnIndex {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if idx, ok := c.indexes[sys]; ok {
+		return idx
+	}
+
+	idx := &offlineVulnIndex{vulns: make(map[string][]models.Vulnerability)}
+	c.indexes[sys] = idx
+	eco, ok := util.OSVEcosystem[sys]
+	if !ok {
+		idx.err = fmt.Errorf("no OSV ecosystem for %v", sys)
+		return idx
+	}
+	db, err := local.LoadOfflineDB(c.localDBPath, lockfile.Ecosystem(eco))
+	if err != nil {
+		idx.err = err
+		return idx
+	}
+	for _, v := range db.Vulnerabilities(false) {
+		var names []string
+		for _, a := range v.Affected {
+			if a.Package.Ecosystem == eco && !slices.Contains(names, a.Package.Name) {
+				names = append(names, a.Package.Name)
+			}
+		}
+		for _, name := range names {
+			idx.vulns[name] = append(idx.vulns[name], v)
+		}
+	}
+
+	return idx
+}
+
+// HasDatabase reports whether the database of the ecosystem is available locally.
+func (c *OSVOfflineClient) HasDatabase(sys resolve.System) bool {
+	return c.index(sys).err == nil
+}
+
+// packageVulns finds the vulnerabilities in the local database affecting the package version.
+func (c *OSVOfflineClient) packageVulns(vk resolve.VersionKey) ([]models.Vulnerability, error) {
+	idx := c.index(vk.System)
+	if idx.err != nil {
+		return nil, idx.err
+	}
+	pkg := util.VKToPackageDetails(vk)
+	var found []models.Vulnerability
+	for _, v := range idx.vulns[vk.Name] {
+		if vulns.IsAffected(v, pkg) {
+			found = append(found, v)
+		}
+	}
+
+	return found, nil
+}
+
+func (c *OSVOfflineClient) FindVulns(g *resolve.Graph) ([]models.Vulnerabilities, error) {
+	nodeVulns := make([]models.Vulnerabilities, len(g.Nodes))
+	// For convenience, include the root node as an empty slice in the results
+	for i, n := range g.Nodes[1:] {
+		vs, err := c.packageVulns(n.Version)
+		if err != nil {
+			return nil, fmt.Errorf("could not load local OSV database: %w", err)
+		}
+		nodeVulns[i+1] = vs
+	}
+
+	return nodeVulns, nil
+}
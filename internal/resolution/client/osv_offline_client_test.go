@@ -0,0 +1,107 @@
+package client_test
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"deps.dev/util/resolve"
+	"github.com/google/osv-scanner/internal/resolution/client"
+	"github.com/google/osv-scanner/internal/resolution/util"
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+// writeOfflineDB writes the vulnerabilities as the downloaded database of the ecosystem in the local database directory.
+func writeOfflineDB(t *testing.T, dir string, eco models.Ecosystem, vulns []models.Vulnerability) {
+	t.Helper()
+
+	dbDir := filepath.Join(dir, "osv-scanner", string(eco))
+	if err := os.MkdirAll(dbDir, 0750); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Create(filepath.Join(dbDir, "all.zip"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	w := zip.NewWriter(f)
+	for _, v := range vulns {
+		data, err := json.Marshal(v)
+		if err != nil {
+			t.Fatalf("could not marshal %v: %v", v, err)
+		}
+		zf, err := w.Create(v.ID + ".json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := zf.Write(data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestOSVOfflineClient(t *testing.T) {
+	t.Parallel()
+
+	affected := func(name, fixed string) models.Affected {
+		events := []models.Event{{Introduced: "0"}}
+		if fixed != "" {
+			events = append(events, models.Event{Fixed: fixed})
+		}
+
+		return models.Affected{
+			Package: models.Package{Ecosystem: models.EcosystemNPM, Name: name},
+			Ranges:  []models.Range{{Type: models.RangeSemVer, Events: events}},
+		}
+	}
+	dir := t.TempDir()
+	writeOfflineDB(t, dir, models.EcosystemNPM, []models.Vulnerability{
+		{ID: "GHSA-foo", Affected: []models.Affected{affected("foo", "1.1.0")}},
+		{ID: "GHSA-multi", Affected: []models.Affected{affected("foo", ""), affected("bar", "")}},
+		{ID: "GHSA-withdrawn", Affected: []models.Affected{affected("bar", "")}, Withdrawn: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+	})
+
+	cl := client.NewOSVOfflineClient(dir)
+	if !cl.HasDatabase(resolve.NPM) {
+		t.Errorf("HasDatabase(npm) = false, want true")
+	}
+	if cl.HasDatabase(util.PyPI) {
+		t.Errorf("HasDatabase(PyPI) = true, want false")
+	}
+
+	g := &resolve.Graph{}
+	for _, vk := range [][2]string{{"root", "1.0.0"}, {"foo", "1.0.0"}, {"foo", "1.1.0"}, {"bar", "1.0.0"}, {"baz", "1.0.0"}} {
+		g.AddNode(resolve.VersionKey{PackageKey: resolve.PackageKey{System: resolve.NPM, Name: vk[0]}, Version: vk[1], VersionType: resolve.Concrete})
+	}
+	nodeVulns, err := cl.FindVulns(g)
+	if err != nil {
+		t.Fatalf("FindVulns() error = %v", err)
+	}
+	got := make([][]string, len(nodeVulns))
+	for i, vulns := range nodeVulns {
+		for _, v := range vulns {
+			got[i] = append(got[i], v.ID)
+		}
+		slices.Sort(got[i])
+	}
+	// withdrawn vulnerabilities are ignored
+	want := [][]string{nil, {"GHSA-foo", "GHSA-multi"}, {"GHSA-multi"}, {"GHSA-multi"}, nil}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FindVulns() = %v, want %v", got, want)
+	}
+
+	// the ecosystems of all the packages need to have been downloaded
+	g.AddNode(resolve.VersionKey{PackageKey: resolve.PackageKey{System: util.PyPI, Name: "qux"}, Version: "1.0.0", VersionType: resolve.Concrete})
+	if _, err := cl.FindVulns(g); err == nil {
+		t.Errorf("FindVulns() without the PyPI database succeeded, want error")
+	}
+}
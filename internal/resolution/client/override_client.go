@@ -5,6 +5,7 @@ import (
 	"slices"
 
 	"deps.dev/util/resolve"
+	"github.com/google/osv-scanner/internal/resolution/util"
 )
 
 // OverrideClient wraps a DependencyClient, allowing for custom packages & versions to be added
@@ -13,6 +14,7 @@ type OverrideClient struct {
 	// Can't quite reuse resolve.LocalClient because it automatically creates dependencies
 	pkgVers map[resolve.PackageKey][]resolve.Version            // versions of a package
 	verDeps map[resolve.VersionKey][]resolve.RequirementVersion // dependencies of a version
+	forced  map[resolve.PackageKey]string                       // requirement versions forced on every dependency of a package
 }
 
 func NewOverrideClient(c DependencyClient) *OverrideClient {
@@ -20,13 +22,20 @@ func NewOverrideClient(c DependencyClient) *OverrideClient {
 		DependencyClient: c,
 		pkgVers:          make(map[resolve.PackageKey][]resolve.Version),
 		verDeps:          make(map[resolve.VersionKey][]resolve.RequirementVersion),
+		forced:           make(map[resolve.PackageKey]string),
 	}
 }
 
+// ForceRequirement replaces the version of every requirement on the package with the given version,
+// e.g. to implement npm "overrides".
+func (c *OverrideClient) ForceRequirement(pk resolve.PackageKey, version string) {
+	c.forced[pk] = version
+}
+
 func (c *OverrideClient) AddVersion(v resolve.Version, deps []resolve.RequirementVersion) {
 	// TODO: Inserting multiple co-dependent requirements may not work, depending on order
 	versions := c.pkgVers[v.PackageKey]
-	sem := v.Semver()
+	sem := util.Semver(v.System)
 	// Only add it to the versions if not already there (and keep versions sorted)
 	idx, ok := slices.BinarySearchFunc(versions, v, func(a, b resolve.Version) int {
 		return sem.Compare(a.Version, b.Version)
@@ -57,16 +66,33 @@ func (c *OverrideClient) Versions(ctx context.Context, pk resolve.PackageKey) ([
 }
 
 func (c *OverrideClient) Requirements(ctx context.Context, vk resolve.VersionKey) ([]resolve.RequirementVersion, error) {
-	if deps, ok := c.verDeps[vk]; ok {
+	deps, ok := c.verDeps[vk]
+	if !ok {
+		var err error
+		deps, err = c.DependencyClient.Requirements(ctx, vk)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(c.forced) == 0 {
 		return deps, nil
 	}
 
-	return c.DependencyClient.Requirements(ctx, vk)
+	// Clone the slice before replacing the forced versions to avoid modifying the underlying client's cached values
+	deps = slices.Clone(deps)
+	for i, d := range deps {
+		if v, ok := c.forced[d.PackageKey]; ok {
+			deps[i].Version = v
+		}
+	}
+
+	return deps, nil
 }
 
 func (c *OverrideClient) MatchingVersions(ctx context.Context, vk resolve.VersionKey) ([]resolve.Version, error) {
 	if vs, ok := c.pkgVers[vk.PackageKey]; ok {
-		return resolve.MatchRequirement(vk, vs), nil
+		return util.MatchRequirement(vk, vs), nil
 	}
 
 	return c.DependencyClient.MatchingVersions(ctx, vk)
@@ -0,0 +1,141 @@
+package client
+
+import (
+	"context"
+	"encoding/gob"
+	"fmt"
+	"os"
+	"slices"
+
+	"deps.dev/util/resolve"
+	"deps.dev/util/resolve/dep"
+	"deps.dev/util/semver"
+	"github.com/google/osv-scanner/internal/resolution/datasource"
+	"github.com/google/osv-scanner/internal/resolution/manifest"
+	"github.com/google/osv-scanner/internal/resolution/util"
+)
+
+const pypiCacheExt = ".resolve.pypi"
+
+// PyPIClient is a DependencyClient for Python packages, using the JSON API of PyPI or a mirror registry.
+type PyPIClient struct {
+	api *datasource.PyPIJSONAPIClient
+}
+
+func NewPyPIClient(cfg datasource.RegistryConfig) (*PyPIClient, error) {
+	api, err := datasource.NewPyPIJSONAPIClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PyPIClient{api: api}, nil
+}
+
+func (c *PyPIClient) Version(ctx context.Context, vk resolve.VersionKey) (resolve.Version, error) {
+	return resolve.Version{VersionKey: vk}, nil
+}
+
+func (c *PyPIClient) Versions(ctx context.Context, pk resolve.PackageKey) ([]resolve.Version, error) {
+	if pk.System != util.PyPI {
+		return nil, fmt.Errorf("unsupported system: %v", pk.System)
+	}
+
+	vers, err := c.api.Versions(ctx, pk.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	vks := make([]resolve.Version, 0, len(vers))
+	for _, v := range vers {
+		if _, err := semver.PyPI.Parse(v); err != nil {
+			// skip legacy versions that are not valid PEP 440 versions
+			continue
+		}
+		vks = append(vks, resolve.Version{
+			VersionKey: resolve.VersionKey{
+				PackageKey:  pk,
+				Version:     v,
+				VersionType: resolve.Concrete,
+			}})
+	}
+
+	slices.SortFunc(vks, func(a, b resolve.Version) int { return semver.PyPI.Compare(a.Version, b.Version) })
+
+	return vks, nil
+}
+
+func (c *PyPIClient) Requirements(ctx context.Context, vk resolve.VersionKey) ([]resolve.RequirementVersion, error) {
+	if vk.System != util.PyPI {
+		return nil, fmt.Errorf("unsupported system: %v", vk.System)
+	}
+
+	reqs, err := c.api.RequiresDist(ctx, vk.Name, vk.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	deps := make([]resolve.RequirementVersion, 0, len(reqs))
+	for _, r := range reqs {
+		name, spec, marker, ok := manifest.ParsePythonRequirement(r)
+		if !ok {
+			continue
+		}
+		typ := dep.NewType()
+		if marker != "" {
+			typ.AddAttr(dep.Environment, marker)
+		}
+		deps = append(deps, resolve.RequirementVersion{
+			Type: typ,
+			VersionKey: resolve.VersionKey{
+				PackageKey: resolve.PackageKey{
+					System: util.PyPI,
+					Name:   name,
+				},
+				Version:     spec,
+				VersionType: resolve.Requirement,
+			},
+		})
+	}
+	resolve.SortDependencies(deps)
+
+	return deps, nil
+}
+
+func (c *PyPIClient) MatchingVersions(ctx context.Context, vk resolve.VersionKey) ([]resolve.Version, error) {
+	vers, err := c.Versions(ctx, vk.PackageKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return util.MatchRequirement(vk, vers), nil
+}
+
+func (c *PyPIClient) PreFetch(ctx context.Context, requirements []resolve.RequirementVersion, manifestPath string) {
+	// It doesn't matter if loading the cache fails
+	_ = c.LoadCache(manifestPath)
+
+	for _, req := range requirements {
+		go c.Versions(ctx, req.PackageKey) //nolint:errcheck
+	}
+	// don't bother waiting for goroutines to finish.
+}
+
+func (c *PyPIClient) WriteCache(path string) error {
+	f, err := os.Create(path + pypiCacheExt)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return gob.NewEncoder(f).Encode(c.api)
+}
+
+func (c *PyPIClient) LoadCache(path string) error {
+	f, err := os.Open(path + pypiCacheExt)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return gob.NewDecoder(f).Decode(&c.api)
+}
@@ -0,0 +1,313 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"deps.dev/util/resolve"
+	"deps.dev/util/resolve/dep"
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+// Recording is every response a RecordClient has seen, which can be saved to a file and replayed with a ReplayClient
+// to reproduce a run without network access, or to share the exact inputs of a resolution issue in a bug report.
+type Recording struct {
+	Version          []recordedVersion          `json:"version"`
+	Versions         []recordedVersions         `json:"versions"`
+	MatchingVersions []recordedMatchingVersions `json:"matchingVersions"`
+	Requirements     []recordedRequirements     `json:"requirements"`
+	Vulns            []recordedVulns            `json:"vulns"`
+}
+
+type recordedVersion struct {
+	Version resolve.VersionKey `json:"version"`
+	Error   string             `json:"error,omitempty"`
+}
+
+type recordedVersions struct {
+	Package  resolve.PackageKey `json:"package"`
+	Versions []string           `json:"versions,omitempty"`
+	Error    string             `json:"error,omitempty"`
+}
+
+type recordedMatchingVersions struct {
+	Requirement resolve.VersionKey `json:"requirement"`
+	Versions    []string           `json:"versions,omitempty"`
+	Error       string             `json:"error,omitempty"`
+}
+
+type recordedRequirements struct {
+	Version      resolve.VersionKey   `json:"version"`
+	Requirements []OfflineRequirement `json:"requirements,omitempty"`
+	Error        string               `json:"error,omitempty"`
+}
+
+type recordedVulns struct {
+	Version resolve.VersionKey     `json:"version"`
+	Vulns   []models.Vulnerability `json:"vulns,omitempty"`
+}
+
+func errString(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	return err.Error()
+}
+
+func versionStrings(vers []resolve.Version) []string {
+	strs := make([]string, len(vers))
+	for i, v := range vers {
+		strs[i] = v.Version
+	}
+
+	return strs
+}
+
+func offlineRequirementOf(rv resolve.RequirementVersion) OfflineRequirement {
+	r := OfflineRequirement{
+		Name:     rv.Name,
+		Version:  rv.Version,
+		Dev:      rv.Type.HasAttr(dep.Dev),
+		Optional: rv.Type.HasAttr(dep.Opt),
+	}
+	r.Scope, _ = rv.Type.GetAttr(dep.Scope)
+	r.KnownAs, _ = rv.Type.GetAttr(dep.KnownAs)
+	for name, key := range offlineAttrs {
+		if value, ok := rv.Type.GetAttr(key); ok {
+			if r.Attrs == nil {
+				r.Attrs = make(map[string]string)
+			}
+			r.Attrs[name] = value
+		}
+	}
+
+	return r
+}
+
+// RecordClient wraps a ResolutionClient, recording all of its responses.
+type RecordClient struct {
+	DependencyClient
+	vulns VulnerabilityClient
+
+	mu  sync.Mutex
+	rec Recording
+}
+
+func NewRecordClient(cl ResolutionClient) *RecordClient {
+	return &RecordClient{DependencyClient: cl.DependencyClient, vulns: cl.VulnerabilityClient}
+}
+
+// ResolutionClient returns a ResolutionClient that records both dependency and vulnerability responses.
+func (c *RecordClient) ResolutionClient() ResolutionClient {
+	return ResolutionClient{DependencyClient: c, VulnerabilityClient: c}
+}
+
+func (c *RecordClient) Version(ctx context.Context, vk resolve.VersionKey) (resolve.Version, error) {
+	v, err := c.DependencyClient.Version(ctx, vk)
+	c.mu.Lock()
+	c.rec.Version = append(c.rec.Version, recordedVersion{Version: vk, Error: errString(err)})
+	c.mu.Unlock()
+
+	return v, err
+}
+
+func (c *RecordClient) Versions(ctx context.Context, pk resolve.PackageKey) ([]resolve.Version, error) {
+	vers, err := c.DependencyClient.Versions(ctx, pk)
+	c.mu.Lock()
+	c.rec.Versions = append(c.rec.Versions, recordedVersions{Package: pk, Versions: versionStrings(vers), Error: errString(err)})
+	c.mu.Unlock()
+
+	return vers, err
+}
+
+func (c *RecordClient) MatchingVersions(ctx context.Context, vk resolve.VersionKey) ([]resolve.Version, error) {
+	vers, err := c.DependencyClient.MatchingVersions(ctx, vk)
+	c.mu.Lock()
+	c.rec.MatchingVersions = append(c.rec.MatchingVersions, recordedMatchingVersions{Requirement: vk, Versions: versionStrings(vers), Error: errString(err)})
+	c.mu.Unlock()
+
+	return vers, err
+}
+
+func (c *RecordClient) Requirements(ctx context.Context, vk resolve.VersionKey) ([]resolve.RequirementVersion, error) {
+	reqs, err := c.DependencyClient.Requirements(ctx, vk)
+	rec := recordedRequirements{Version: vk, Error: errString(err)}
+	for _, r := range reqs {
+		rec.Requirements = append(rec.Requirements, offlineRequirementOf(r))
+	}
+	c.mu.Lock()
+	c.rec.Requirements = append(c.rec.Requirements, rec)
+	c.mu.Unlock()
+
+	return reqs, err
+}
+
+func (c *RecordClient) FindVulns(g *resolve.Graph) ([]models.Vulnerabilities, error) {
+	vulns, err := c.vulns.FindVulns(g)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	for i, n := range g.Nodes {
+		c.rec.Vulns = append(c.rec.Vulns, recordedVulns{Version: n.Version, Vulns: vulns[i]})
+	}
+	c.mu.Unlock()
+
+	return vulns, nil
+}
+
+// Save writes the recording to a file, which can be loaded with NewReplayClient.
+func (c *RecordClient) Save(path string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	b, err := json.MarshalIndent(c.rec, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	//nolint:gosec // the recording does not contain secrets
+	return os.WriteFile(path, b, 0644)
+}
+
+// ReplayClient is a ResolutionClient that only responds with a previously saved Recording.
+// Any query that was not recorded is an error.
+type ReplayClient struct {
+	version          map[resolve.VersionKey]recordedVersion
+	versions         map[resolve.PackageKey]recordedVersions
+	matchingVersions map[resolve.VersionKey]recordedMatchingVersions
+	requirements     map[resolve.VersionKey]recordedRequirements
+	vulns            map[resolve.VersionKey][]models.Vulnerability
+}
+
+func NewReplayClient(path string) (*ReplayClient, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read recording: %w", err)
+	}
+	var rec Recording
+	if err := json.Unmarshal(b, &rec); err != nil {
+		return nil, fmt.Errorf("failed to parse recording %s: %w", path, err)
+	}
+
+	c := &ReplayClient{
+		version:          make(map[resolve.VersionKey]recordedVersion),
+		versions:         make(map[resolve.PackageKey]recordedVersions),
+		matchingVersions: make(map[resolve.VersionKey]recordedMatchingVersions),
+		requirements:     make(map[resolve.VersionKey]recordedRequirements),
+		vulns:            make(map[resolve.VersionKey][]models.Vulnerability),
+	}
+	for _, r := range rec.Version {
+		c.version[r.Version] = r
+	}
+	for _, r := range rec.Versions {
+		c.versions[r.Package] = r
+	}
+	for _, r := range rec.MatchingVersions {
+		c.matchingVersions[r.Requirement] = r
+	}
+	for _, r := range rec.Requirements {
+		c.requirements[r.Version] = r
+	}
+	for _, r := range rec.Vulns {
+		c.vulns[r.Version] = r.Vulns
+	}
+
+	return c, nil
+}
+
+// ResolutionClient returns a ResolutionClient that replays both dependency and vulnerability responses.
+func (c *ReplayClient) ResolutionClient() ResolutionClient {
+	return ResolutionClient{DependencyClient: c, VulnerabilityClient: c}
+}
+
+func replayVersions(pk resolve.PackageKey, vers []string, errStr string) ([]resolve.Version, error) {
+	if errStr != "" {
+		return nil, errors.New(errStr)
+	}
+	res := make([]resolve.Version, len(vers))
+	for i, v := range vers {
+		res[i] = resolve.Version{VersionKey: resolve.VersionKey{PackageKey: pk, Version: v, VersionType: resolve.Concrete}}
+	}
+
+	return res, nil
+}
+
+func (c *ReplayClient) Version(ctx context.Context, vk resolve.VersionKey) (resolve.Version, error) {
+	r, ok := c.version[vk]
+	if !ok {
+		return resolve.Version{}, fmt.Errorf("no recorded version %s@%s", vk.Name, vk.Version)
+	}
+	if r.Error != "" {
+		return resolve.Version{}, errors.New(r.Error)
+	}
+
+	return resolve.Version{VersionKey: vk}, nil
+}
+
+func (c *ReplayClient) Versions(ctx context.Context, pk resolve.PackageKey) ([]resolve.Version, error) {
+	r, ok := c.versions[pk]
+	if !ok {
+		return nil, fmt.Errorf("no recorded versions of %s", pk.Name)
+	}
+
+	return replayVersions(pk, r.Versions, r.Error)
+}
+
+func (c *ReplayClient) MatchingVersions(ctx context.Context, vk resolve.VersionKey) ([]resolve.Version, error) {
+	r, ok := c.matchingVersions[vk]
+	if !ok {
+		return nil, fmt.Errorf("no recorded matching versions of %s@%s", vk.Name, vk.Version)
+	}
+
+	return replayVersions(vk.PackageKey, r.Versions, r.Error)
+}
+
+func (c *ReplayClient) Requirements(ctx context.Context, vk resolve.VersionKey) ([]resolve.RequirementVersion, error) {
+	r, ok := c.requirements[vk]
+	if !ok {
+		return nil, fmt.Errorf("no recorded requirements of %s@%s", vk.Name, vk.Version)
+	}
+	if r.Error != "" {
+		return nil, errors.New(r.Error)
+	}
+	reqs := make([]resolve.RequirementVersion, len(r.Requirements))
+	for i, req := range r.Requirements {
+		var err error
+		if reqs[i], err = req.requirementVersion(vk.System); err != nil {
+			return nil, err
+		}
+	}
+
+	return reqs, nil
+}
+
+func (c *ReplayClient) FindVulns(g *resolve.Graph) ([]models.Vulnerabilities, error) {
+	vulns := make([]models.Vulnerabilities, len(g.Nodes))
+	for i, n := range g.Nodes {
+		v, ok := c.vulns[n.Version]
+		if !ok {
+			return nil, fmt.Errorf("no recorded vulnerabilities of %s@%s", n.Version.Name, n.Version.Version)
+		}
+		vulns[i] = v
+	}
+
+	return vulns, nil
+}
+
+// The recording is already local, so there is nothing to cache or prefetch.
+
+func (c *ReplayClient) PreFetch(context.Context, []resolve.RequirementVersion, string) {}
+
+func (c *ReplayClient) WriteCache(string) error {
+	return nil
+}
+
+func (c *ReplayClient) LoadCache(string) error {
+	return errors.New("replay client has no cache")
+}
@@ -0,0 +1,82 @@
+package client_test
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"deps.dev/util/resolve"
+	"deps.dev/util/resolve/dep"
+	"github.com/google/osv-scanner/internal/resolution/client"
+	"github.com/google/osv-scanner/internal/resolution/util"
+)
+
+func TestRecordReplayClient(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	pk := resolve.PackageKey{System: util.PyPI, Name: "foo"}
+	vk := resolve.VersionKey{PackageKey: pk, Version: "1.0.0", VersionType: resolve.Concrete}
+
+	typ := dep.NewType(dep.Dev)
+	typ.AddAttr(dep.Environment, `python_version < "3.8"`)
+	typ.AddAttr(dep.Test, "")
+	reqs := []resolve.RequirementVersion{{
+		VersionKey: resolve.VersionKey{
+			PackageKey:  resolve.PackageKey{System: util.PyPI, Name: "bar"},
+			Version:     ">=2.0",
+			VersionType: resolve.Requirement,
+		},
+		Type: typ,
+	}}
+	cl := newCountingClient(pk)
+	cl.AddVersion(resolve.Version{VersionKey: vk}, reqs)
+
+	rc := client.NewRecordClient(client.ResolutionClient{DependencyClient: cl})
+	wantVersion, err := rc.Version(ctx, vk)
+	if err != nil {
+		t.Fatalf("Version() error = %v", err)
+	}
+	wantVersions, err := rc.Versions(ctx, pk)
+	if err != nil {
+		t.Fatalf("Versions() error = %v", err)
+	}
+	wantReqs, err := rc.Requirements(ctx, vk)
+	if err != nil {
+		t.Fatalf("Requirements() error = %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "recording.json")
+	if err := rc.Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	replay, err := client.NewReplayClient(path)
+	if err != nil {
+		t.Fatalf("NewReplayClient() error = %v", err)
+	}
+
+	if got, err := replay.Version(ctx, vk); err != nil || !reflect.DeepEqual(got, wantVersion) {
+		t.Errorf("replayed Version() = %v, %v, want %v", got, err, wantVersion)
+	}
+	if got, err := replay.Versions(ctx, pk); err != nil || !reflect.DeepEqual(got, wantVersions) {
+		t.Errorf("replayed Versions() = %v, %v, want %v", got, err, wantVersions)
+	}
+	got, err := replay.Requirements(ctx, vk)
+	if err != nil {
+		t.Fatalf("replayed Requirements() error = %v", err)
+	}
+	if len(got) != 1 || got[0].VersionKey != wantReqs[0].VersionKey || !got[0].Type.Equal(wantReqs[0].Type) {
+		t.Errorf("replayed Requirements() = %v, want %v", got, wantReqs)
+	}
+
+	// queries that were not recorded are errors
+	unrecorded := vk
+	unrecorded.Version = "2.0.0"
+	if _, err := replay.Version(ctx, unrecorded); err == nil {
+		t.Errorf("replayed Version() of an unrecorded version succeeded, want error")
+	}
+	if _, err := replay.Requirements(ctx, unrecorded); err == nil {
+		t.Errorf("replayed Requirements() of an unrecorded version succeeded, want error")
+	}
+}
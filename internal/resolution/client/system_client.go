@@ -0,0 +1,135 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"deps.dev/util/resolve"
+)
+
+// SystemClient is a DependencyClient that sends each query to the client of the package's system,
+// so that projects of several ecosystems can be resolved with one client e.g. when remediating a monorepo.
+type SystemClient struct {
+	clients map[resolve.System]DependencyClient
+}
+
+func NewSystemClient(clients map[resolve.System]DependencyClient) *SystemClient {
+	return &SystemClient{clients: clients}
+}
+
+func (c *SystemClient) client(sys resolve.System) (DependencyClient, error) {
+	cl, ok := c.clients[sys]
+	if !ok {
+		return nil, fmt.Errorf("unsupported system: %v", sys)
+	}
+
+	return cl, nil
+}
+
+func (c *SystemClient) Version(ctx context.Context, vk resolve.VersionKey) (resolve.Version, error) {
+	cl, err := c.client(vk.System)
+	if err != nil {
+		return resolve.Version{}, err
+	}
+
+	return cl.Version(ctx, vk)
+}
+
+func (c *SystemClient) Versions(ctx context.Context, pk resolve.PackageKey) ([]resolve.Version, error) {
+	cl, err := c.client(pk.System)
+	if err != nil {
+		return nil, err
+	}
+
+	return cl.Versions(ctx, pk)
+}
+
+func (c *SystemClient) Requirements(ctx context.Context, vk resolve.VersionKey) ([]resolve.RequirementVersion, error) {
+	cl, err := c.client(vk.System)
+	if err != nil {
+		return nil, err
+	}
+
+	return cl.Requirements(ctx, vk)
+}
+
+func (c *SystemClient) MatchingVersions(ctx context.Context, vk resolve.VersionKey) ([]resolve.Version, error) {
+	cl, err := c.client(vk.System)
+	if err != nil {
+		return nil, err
+	}
+
+	return cl.MatchingVersions(ctx, vk)
+}
+
+// The optional client interfaces are forwarded if the system's client implements them,
+// otherwise the information is reported as unknown.
+
+func (c *SystemClient) Engines(ctx context.Context, vk resolve.VersionKey) (map[string]string, error) {
+	if cl, ok := c.clients[vk.System].(EnginesClient); ok {
+		return cl.Engines(ctx, vk)
+	}
+
+	return nil, nil
+}
+
+func (c *SystemClient) Platforms(ctx context.Context, vk resolve.VersionKey) ([]string, []string, error) {
+	if cl, ok := c.clients[vk.System].(PlatformClient); ok {
+		return cl.Platforms(ctx, vk)
+	}
+
+	return nil, nil, nil
+}
+
+func (c *SystemClient) Integrity(ctx context.Context, vk resolve.VersionKey) (string, error) {
+	if cl, ok := c.clients[vk.System].(IntegrityClient); ok {
+		return cl.Integrity(ctx, vk)
+	}
+
+	return "", nil
+}
+
+func (c *SystemClient) PublishTime(ctx context.Context, vk resolve.VersionKey) (time.Time, error) {
+	if cl, ok := c.clients[vk.System].(PublishTimeClient); ok {
+		return cl.PublishTime(ctx, vk)
+	}
+
+	return time.Time{}, nil
+}
+
+func (c *SystemClient) Deprecation(ctx context.Context, vk resolve.VersionKey) (string, error) {
+	if cl, ok := c.clients[vk.System].(DeprecationClient); ok {
+		return cl.Deprecation(ctx, vk)
+	}
+
+	return "", nil
+}
+
+func (c *SystemClient) PreFetch(ctx context.Context, requirements []resolve.RequirementVersion, manifestPath string) {
+	if len(requirements) == 0 {
+		return
+	}
+	if cl, ok := c.clients[requirements[0].System]; ok {
+		cl.PreFetch(ctx, requirements, manifestPath)
+	}
+}
+
+func (c *SystemClient) WriteCache(path string) error {
+	var errs []error
+	for _, cl := range c.clients {
+		errs = append(errs, cl.WriteCache(path))
+	}
+
+	return errors.Join(errs...)
+}
+
+func (c *SystemClient) LoadCache(path string) error {
+	var errs []error
+	for _, cl := range c.clients {
+		errs = append(errs, cl.LoadCache(path))
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,124 @@
+package datasource
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const cratesIndexURL = "https://index.crates.io"
+
+// CratesIndexAPIClient queries a sparse registry index (https://doc.rust-lang.org/cargo/reference/registry-index.html),
+// which is crates.io's unless a mirror is configured,
+// for the versions and dependencies of crates.
+type CratesIndexAPIClient struct {
+	indexURL   string
+	httpClient *http.Client
+
+	// cache fields
+	mu             sync.Mutex
+	cacheTimestamp *time.Time // If set, this means we loaded from a cache
+	crates         map[string][]CrateVersion
+}
+
+// CrateVersion is a single version entry of a crate in the index.
+type CrateVersion struct {
+	Name   string            `json:"name"`
+	Vers   string            `json:"vers"`
+	Deps   []CrateDependency `json:"deps"`
+	Yanked bool              `json:"yanked"`
+}
+
+type CrateDependency struct {
+	Name     string `json:"name"`
+	Req      string `json:"req"`
+	Optional bool   `json:"optional"`
+	Target   string `json:"target"`
+	Kind     string `json:"kind"`    // "normal", "dev" or "build"
+	Package  string `json:"package"` // the actual name of the crate, if Name is a rename
+}
+
+func NewCratesIndexAPIClient(cfg RegistryConfig) (*CratesIndexAPIClient, error) {
+	httpClient, err := cfg.HTTPClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return &CratesIndexAPIClient{
+		indexURL:   cfg.urlOr(cratesIndexURL),
+		httpClient: httpClient,
+		crates:     make(map[string][]CrateVersion),
+	}, nil
+}
+
+// Versions returns all versions of the crate in the index, including yanked versions.
+func (c *CratesIndexAPIClient) Versions(ctx context.Context, name string) ([]CrateVersion, error) {
+	name = strings.ToLower(name)
+	c.mu.Lock()
+	vers, ok := c.crates[name]
+	c.mu.Unlock()
+	if ok {
+		return vers, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.indexURL+"/"+cratesIndexPath(name), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.New(resp.Status)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	// Each line of the file is the JSON of one version
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	scanner.Buffer(nil, len(body)+1)
+	for scanner.Scan() {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var v CrateVersion
+		if err := json.Unmarshal(scanner.Bytes(), &v); err != nil {
+			return nil, err
+		}
+		vers = append(vers, v)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.crates[name] = vers
+	c.mu.Unlock()
+
+	return vers, nil
+}
+
+// cratesIndexPath is the path of the crate's file in the index, which is prefixed depending on the length of the name.
+func cratesIndexPath(name string) string {
+	switch len(name) {
+	case 1:
+		return "1/" + name
+	case 2:
+		return "2/" + name
+	case 3:
+		return "3/" + name[:1] + "/" + name
+	default:
+		return name[:2] + "/" + name[2:4] + "/" + name
+	}
+}
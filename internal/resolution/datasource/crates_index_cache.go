@@ -0,0 +1,47 @@
+package datasource
+
+import (
+	"time"
+)
+
+type cratesIndexCache struct {
+	Timestamp *time.Time
+	Crates    map[string][]CrateVersion
+}
+
+func (c *CratesIndexAPIClient) GobEncode() ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.cacheTimestamp == nil {
+		now := time.Now().UTC()
+		c.cacheTimestamp = &now
+	}
+
+	cache := cratesIndexCache{
+		Timestamp: c.cacheTimestamp,
+		Crates:    c.crates,
+	}
+
+	return gobMarshal(&cache)
+}
+
+func (c *CratesIndexAPIClient) GobDecode(b []byte) error {
+	var cache cratesIndexCache
+	if err := gobUnmarshal(b, &cache); err != nil {
+		return err
+	}
+
+	if cache.Timestamp != nil && time.Since(*cache.Timestamp) >= cacheExpiry {
+		// Cache expired
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.cacheTimestamp = cache.Timestamp
+	c.crates = cache.Crates
+
+	return nil
+}
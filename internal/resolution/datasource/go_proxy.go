@@ -0,0 +1,140 @@
+package datasource
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+)
+
+const defaultGoProxy = "https://proxy.golang.org"
+
+// GoProxyAPIClient queries a Go module proxy (https://go.dev/ref/mod#goproxy-protocol)
+// for the versions and go.mod requirements of modules.
+type GoProxyAPIClient struct {
+	proxyURL   string
+	httpClient *http.Client
+
+	// cache fields
+	mu             sync.Mutex
+	cacheTimestamp *time.Time // If set, this means we loaded from a cache
+	versions       map[string][]string
+	requires       map[module.Version][]module.Version
+}
+
+func NewGoProxyAPIClient(cfg RegistryConfig) (*GoProxyAPIClient, error) {
+	httpClient, err := cfg.HTTPClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return &GoProxyAPIClient{
+		proxyURL:   cfg.urlOr(goProxyURL()),
+		httpClient: httpClient,
+		versions:   make(map[string][]string),
+		requires:   make(map[module.Version][]module.Version),
+	}, nil
+}
+
+// goProxyURL finds the first HTTP proxy in the GOPROXY environment variable, falling back to the public proxy.
+// TODO: fall through the list on 404/410 errors like the go command does
+func goProxyURL() string {
+	for _, p := range strings.FieldsFunc(os.Getenv("GOPROXY"), func(r rune) bool { return r == ',' || r == '|' }) {
+		if strings.HasPrefix(p, "https://") || strings.HasPrefix(p, "http://") {
+			return strings.TrimSuffix(p, "/")
+		}
+	}
+
+	return defaultGoProxy
+}
+
+// Versions returns the list of known tagged versions of the module.
+func (c *GoProxyAPIClient) Versions(ctx context.Context, mod string) ([]string, error) {
+	c.mu.Lock()
+	vers, ok := c.versions[mod]
+	c.mu.Unlock()
+	if ok {
+		return vers, nil
+	}
+
+	path, err := module.EscapePath(mod)
+	if err != nil {
+		return nil, err
+	}
+	body, err := c.get(ctx, path, "@v", "list")
+	if err != nil {
+		return nil, err
+	}
+	vers = strings.Fields(string(body))
+
+	c.mu.Lock()
+	c.versions[mod] = vers
+	c.mu.Unlock()
+
+	return vers, nil
+}
+
+// Requires returns the modules required by the go.mod file of the module version.
+func (c *GoProxyAPIClient) Requires(ctx context.Context, mod, version string) ([]module.Version, error) {
+	mv := module.Version{Path: mod, Version: version}
+	c.mu.Lock()
+	reqs, ok := c.requires[mv]
+	c.mu.Unlock()
+	if ok {
+		return reqs, nil
+	}
+
+	path, err := module.EscapePath(mod)
+	if err != nil {
+		return nil, err
+	}
+	ver, err := module.EscapeVersion(version)
+	if err != nil {
+		return nil, err
+	}
+	body, err := c.get(ctx, path, "@v", ver+".mod")
+	if err != nil {
+		return nil, err
+	}
+	f, err := modfile.ParseLax("go.mod", body, nil)
+	if err != nil {
+		return nil, fmt.Errorf("parsing go.mod of %s@%s: %w", mod, version, err)
+	}
+	reqs = make([]module.Version, len(f.Require))
+	for i, r := range f.Require {
+		reqs[i] = r.Mod
+	}
+
+	c.mu.Lock()
+	c.requires[mv] = reqs
+	c.mu.Unlock()
+
+	return reqs, nil
+}
+
+func (c *GoProxyAPIClient) get(ctx context.Context, urlComponents ...string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.proxyURL+"/"+strings.Join(urlComponents, "/"), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.New(resp.Status)
+	}
+
+	return io.ReadAll(resp.Body)
+}
@@ -0,0 +1,61 @@
+package datasource
+
+import (
+	"time"
+
+	"golang.org/x/mod/module"
+)
+
+type goProxyCache struct {
+	Timestamp *time.Time
+	ProxyURL  string
+	Versions  map[string][]string
+	Requires  map[module.Version][]module.Version
+}
+
+func (c *GoProxyAPIClient) GobEncode() ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.cacheTimestamp == nil {
+		now := time.Now().UTC()
+		c.cacheTimestamp = &now
+	}
+
+	cache := goProxyCache{
+		Timestamp: c.cacheTimestamp,
+		ProxyURL:  c.proxyURL,
+		Versions:  c.versions,
+		Requires:  c.requires,
+	}
+
+	return gobMarshal(&cache)
+}
+
+func (c *GoProxyAPIClient) GobDecode(b []byte) error {
+	var cache goProxyCache
+	if err := gobUnmarshal(b, &cache); err != nil {
+		return err
+	}
+
+	if cache.Timestamp != nil && time.Since(*cache.Timestamp) >= cacheExpiry {
+		// Cache expired
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if cache.ProxyURL != c.proxyURL {
+		// The proxy has changed, the cached data may not be valid
+		return nil
+	}
+
+	c.cacheTimestamp = cache.Timestamp
+	// The Go module proxy's data is immutable, except for the versions list
+	// which can only grow, so the cache can be used as-is.
+	c.versions = cache.Versions
+	c.requires = cache.Requires
+
+	return nil
+}
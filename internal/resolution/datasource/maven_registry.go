@@ -0,0 +1,128 @@
+package datasource
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const mavenCentralURL = "https://repo.maven.apache.org/maven2"
+
+// MavenRegistryAPIClient queries a Maven repository, Maven Central unless a mirror is configured,
+// for the versions of artifacts and their pom files.
+type MavenRegistryAPIClient struct {
+	baseURL    string
+	httpClient *http.Client
+
+	// cache fields
+	mu             sync.Mutex
+	cacheTimestamp *time.Time // If set, this means we loaded from a cache
+	versions       map[string][]string
+	poms           map[string][]byte // keyed by "groupId:artifactId@version"
+}
+
+func NewMavenRegistryAPIClient(cfg RegistryConfig) (*MavenRegistryAPIClient, error) {
+	httpClient, err := cfg.HTTPClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return &MavenRegistryAPIClient{
+		baseURL:    cfg.urlOr(mavenCentralURL),
+		httpClient: httpClient,
+		versions:   make(map[string][]string),
+		poms:       make(map[string][]byte),
+	}, nil
+}
+
+// mavenArtifactPath is the path of the artifact's directory in the repository e.g. "org/apache/commons/commons-lang3"
+func mavenArtifactPath(name string) (string, string, error) {
+	groupID, artifactID, ok := strings.Cut(name, ":")
+	if !ok {
+		return "", "", fmt.Errorf("invalid Maven package name: %s", name)
+	}
+
+	return strings.ReplaceAll(groupID, ".", "/") + "/" + artifactID, artifactID, nil
+}
+
+// Versions returns the versions of the artifact "groupId:artifactId" listed in its maven-metadata.xml.
+func (c *MavenRegistryAPIClient) Versions(ctx context.Context, name string) ([]string, error) {
+	c.mu.Lock()
+	vers, ok := c.versions[name]
+	c.mu.Unlock()
+	if ok {
+		return vers, nil
+	}
+
+	path, _, err := mavenArtifactPath(name)
+	if err != nil {
+		return nil, err
+	}
+	body, err := c.get(ctx, path+"/maven-metadata.xml")
+	if err != nil {
+		return nil, err
+	}
+	var metadata struct {
+		Versions []string `xml:"versioning>versions>version"`
+	}
+	if err := xml.Unmarshal(body, &metadata); err != nil {
+		return nil, fmt.Errorf("parsing maven-metadata.xml of %s: %w", name, err)
+	}
+
+	c.mu.Lock()
+	c.versions[name] = metadata.Versions
+	c.mu.Unlock()
+
+	return metadata.Versions, nil
+}
+
+// POM returns the contents of the pom file of the artifact version.
+func (c *MavenRegistryAPIClient) POM(ctx context.Context, name, version string) ([]byte, error) {
+	key := name + "@" + version
+	c.mu.Lock()
+	pom, ok := c.poms[key]
+	c.mu.Unlock()
+	if ok {
+		return pom, nil
+	}
+
+	path, artifactID, err := mavenArtifactPath(name)
+	if err != nil {
+		return nil, err
+	}
+	pom, err = c.get(ctx, path+"/"+version+"/"+artifactID+"-"+version+".pom")
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.poms[key] = pom
+	c.mu.Unlock()
+
+	return pom, nil
+}
+
+func (c *MavenRegistryAPIClient) get(ctx context.Context, path string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/"+path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.New(resp.Status)
+	}
+
+	return io.ReadAll(resp.Body)
+}
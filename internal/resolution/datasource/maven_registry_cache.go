@@ -0,0 +1,57 @@
+package datasource
+
+import (
+	"time"
+)
+
+type mavenRegistryCache struct {
+	Timestamp *time.Time
+	BaseURL   string
+	Versions  map[string][]string
+	POMs      map[string][]byte
+}
+
+func (c *MavenRegistryAPIClient) GobEncode() ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.cacheTimestamp == nil {
+		now := time.Now().UTC()
+		c.cacheTimestamp = &now
+	}
+
+	cache := mavenRegistryCache{
+		Timestamp: c.cacheTimestamp,
+		BaseURL:   c.baseURL,
+		Versions:  c.versions,
+		POMs:      c.poms,
+	}
+
+	return gobMarshal(&cache)
+}
+
+func (c *MavenRegistryAPIClient) GobDecode(b []byte) error {
+	var cache mavenRegistryCache
+	if err := gobUnmarshal(b, &cache); err != nil {
+		return err
+	}
+
+	if cache.Timestamp != nil && time.Since(*cache.Timestamp) >= cacheExpiry {
+		// Cache expired
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if cache.BaseURL != c.baseURL {
+		// The repository has changed, the cached data may not be valid
+		return nil
+	}
+
+	c.cacheTimestamp = cache.Timestamp
+	c.versions = cache.Versions
+	c.poms = cache.POMs
+
+	return nil
+}
@@ -2,10 +2,13 @@ package datasource
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"sync"
 	"time"
 
@@ -18,6 +21,7 @@ type NpmRegistryAPIClient struct {
 	// This should only be written to when the client is first being created.
 	// Other functions should not modify it & it is not covered by the mutex.
 	registries npmRegistries
+	httpClient *http.Client
 
 	// cache fields
 	mu             sync.Mutex
@@ -31,14 +35,28 @@ type npmRegistryPackageDetails struct {
 	Tags     map[string]string
 }
 
-func NewNpmRegistryAPIClient(workdir string) (*NpmRegistryAPIClient, error) {
+func NewNpmRegistryAPIClient(workdir string, cfg RegistryConfig) (*NpmRegistryAPIClient, error) {
 	npmrc, err := loadNpmrc(workdir)
 	if err != nil {
 		return nil, err
 	}
+	if cfg.URL != "" {
+		// The mirror replaces the default registry, scoped registries from the npmrc are still used
+		npmrc.Key("registry").SetValue(cfg.URL)
+	}
+	httpClient, err := cfg.HTTPClient()
+	if err != nil {
+		return nil, err
+	}
+
+	registries, err := parseRegistryInfo(npmrc)
+	if err != nil {
+		return nil, err
+	}
 
 	return &NpmRegistryAPIClient{
-		registries: parseRegistryInfo(npmrc),
+		registries: registries,
+		httpClient: httpClient,
 		details:    make(map[string]npmRegistryPackageDetails),
 	}, nil
 }
@@ -67,6 +85,17 @@ type npmRegistryDependencies struct {
 	PeerDependencies     map[string]string
 	OptionalDependencies map[string]string
 	BundleDependencies   []string
+	// Engines is not a dependency, but the runtime requirements of the version e.g. {"node": ">=18"}
+	Engines map[string]string
+	// OS and CPU are the platforms the version can be installed on e.g. ["darwin"] and ["arm64"], empty if unrestricted
+	OS  []string
+	CPU []string
+	// Integrity is the Subresource Integrity hashes of the published tarball e.g. "sha512-... sha1-..."
+	Integrity string
+	// Published is the time the version was published to the registry, if known
+	Published time.Time
+	// Deprecated is the deprecation message of the version, empty if it is not deprecated
+	Deprecated string
 }
 
 func (c *NpmRegistryAPIClient) Dependencies(ctx context.Context, pkg, version string) (npmRegistryDependencies, error) {
@@ -92,7 +121,7 @@ func (c *NpmRegistryAPIClient) get(ctx context.Context, urlComponents ...string)
 		return gjson.Result{}, err
 	}
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return gjson.Result{}, err
 	}
@@ -126,6 +155,7 @@ func (c *NpmRegistryAPIClient) getPackageDetails(ctx context.Context, pkg string
 		return npmRegistryPackageDetails{}, err
 	}
 
+	times := jsonData.Get("time").Map()
 	versions := make(map[string]npmRegistryDependencies)
 	for v, data := range jsonData.Get("versions").Map() {
 		versions[v] = npmRegistryDependencies{
@@ -134,6 +164,12 @@ func (c *NpmRegistryAPIClient) getPackageDetails(ctx context.Context, pkg string
 			PeerDependencies:     jsonToStringMap(data.Get("peerDependencies")),
 			OptionalDependencies: jsonToStringMap(data.Get("optionalDependencies")),
 			BundleDependencies:   jsonToStringSlice(data.Get("bundleDependencies")),
+			Engines:              jsonToStringMap(data.Get("engines")),
+			OS:                   jsonToStringSlice(data.Get("os")),
+			CPU:                  jsonToStringSlice(data.Get("cpu")),
+			Integrity:            distIntegrity(data.Get("dist")),
+			Published:            times[v].Time(),
+			Deprecated:           data.Get("deprecated").String(),
 		}
 	}
 	pkgData = npmRegistryPackageDetails{
@@ -148,6 +184,19 @@ func (c *NpmRegistryAPIClient) getPackageDetails(ctx context.Context, pkg string
 	return pkgData, nil
 }
 
+// distIntegrity combines the integrity hash and the (older, hex-encoded SHA-1) shasum of a version's "dist" into a single integrity string.
+func distIntegrity(dist gjson.Result) string {
+	var hashes []string
+	if integrity := dist.Get("integrity").String(); integrity != "" {
+		hashes = append(hashes, integrity)
+	}
+	if shasum, err := hex.DecodeString(dist.Get("shasum").String()); err == nil && len(shasum) > 0 {
+		hashes = append(hashes, "sha1-"+base64.StdEncoding.EncodeToString(shasum))
+	}
+
+	return strings.Join(hashes, " ")
+}
+
 func jsonToStringSlice(v gjson.Result) []string {
 	arr := v.Array()
 	if len(arr) == 0 {
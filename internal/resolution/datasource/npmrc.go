@@ -46,9 +46,7 @@ func loadNpmrc(workdir string) (npmrcConfig, error) {
 	var userFile string
 	switch {
 	case fullNpmrc.Section("").HasKey("userconfig"):
-		userFile = os.ExpandEnv(fullNpmrc.Section("").Key("userconfig").String())
-		// TODO: npm config replaces only ${VAR}, not $VAR
-		// and if VAR is unset, it will leave the string as "${VAR}"
+		userFile = npmrcExpandEnv(fullNpmrc.Section("").Key("userconfig").String())
 	default:
 		homeDir, err := os.UserHomeDir()
 		if err == nil { // only set userFile if homeDir exists
@@ -67,9 +65,9 @@ func loadNpmrc(workdir string) (npmrcConfig, error) {
 	// cannot be set within the global npmrc itself
 	switch {
 	case fullNpmrc.Section("").HasKey("globalconfig"):
-		globalFile = os.ExpandEnv(fullNpmrc.Section("").Key("globalconfig").String())
+		globalFile = npmrcExpandEnv(fullNpmrc.Section("").Key("globalconfig").String())
 	case fullNpmrc.Section("").HasKey("prefix"):
-		prefix := os.ExpandEnv(fullNpmrc.Section("").Key("prefix").String())
+		prefix := npmrcExpandEnv(fullNpmrc.Section("").Key("prefix").String())
 		globalFile, _ = filepath.Abs(filepath.Join(prefix, "etc", "npmrc"))
 	case os.Getenv("PREFIX") != "":
 		globalFile, _ = filepath.Abs(filepath.Join(os.Getenv("PREFIX"), "etc", "npmrc"))
@@ -136,7 +134,10 @@ type npmRegistryAuthInfo struct {
 	password  string
 }
 
-func (authInfo npmRegistryAuthInfo) addAuth(header http.Header) {
+func (authInfo *npmRegistryAuthInfo) addAuth(header http.Header) error {
+	if authInfo == nil {
+		return nil
+	}
 	switch {
 	case authInfo.authToken != "":
 		header.Set("Authorization", "Bearer "+authInfo.authToken)
@@ -149,12 +150,14 @@ func (authInfo npmRegistryAuthInfo) addAuth(header http.Header) {
 		b, err := base64.StdEncoding.DecodeString(authInfo.password)
 		if err != nil {
 			// TODO: npm seems to actually be quite lenient with invalid encodings
-			panic(fmt.Sprintf("Unable to decode registry password: %v", err))
+			return fmt.Errorf("unable to decode registry password: %w", err)
 		}
 		authBytes = append(authBytes, b...)
 		auth := base64.StdEncoding.EncodeToString(authBytes)
 		header.Set("Authorization", "Basic "+auth)
 	}
+
+	return nil
 }
 
 type npmRegistryInfo struct {
@@ -177,7 +180,9 @@ func (info npmRegistryInfo) buildRequest(ctx context.Context, urlComponents ...s
 	if err != nil {
 		return nil, err
 	}
-	info.authInfo.addAuth(req.Header)
+	if err := info.authInfo.addAuth(req.Header); err != nil {
+		return nil, err
+	}
 
 	return req, nil
 }
@@ -205,34 +210,41 @@ func (r npmRegistries) buildRequest(ctx context.Context, urlComponents ...string
 	return info.buildRequest(ctx, urlComponents...)
 }
 
-func parseRegistryInfo(npmrc npmrcConfig) npmRegistries {
-	infos := make(npmRegistries)                   // map of @scope to info
-	auths := make(map[string]*npmRegistryAuthInfo) // map of host url to auth
+// npmrcExpandEnv replaces environment variables in npmrc values the way npm does:
+// only ${VAR} is replaced (not $VAR), and references to unset variables are left unchanged.
+func npmrcExpandEnv(value string) string {
+	re := cachedregexp.MustCompile(`\$\{([^}]+)\}`)
 
-	getOrCreateAuth := func(host string) *npmRegistryAuthInfo {
-		host, _ = strings.CutSuffix(host, "/")
-		if authInfo, ok := auths[host]; ok {
-			return authInfo
+	return re.ReplaceAllStringFunc(value, func(match string) string {
+		if v, ok := os.LookupEnv(match[2 : len(match)-1]); ok {
+			return v
 		}
-		auths[host] = &npmRegistryAuthInfo{}
 
-		return auths[host]
-	}
+		return match
+	})
+}
 
-	makeRegistryInfo := func(fullURL string) npmRegistryInfo {
-		u, err := url.Parse(fullURL)
-		if err != nil {
-			panic(fmt.Sprintf("Error parsing url %s: %v", fullURL, err))
-		}
+// nerfDart converts a registry URL to the "//host/path" form used to key its auth settings in npmrc.
+func nerfDart(u *url.URL) string {
+	return "//" + u.Host + strings.TrimSuffix(u.Path, "/")
+}
 
-		return npmRegistryInfo{
-			URL:      fullURL,
-			authInfo: getOrCreateAuth(u.Host),
+func parseRegistryInfo(npmrc npmrcConfig) (npmRegistries, error) {
+	registryURLs := make(map[string]string)        // map of @scope to registry url
+	auths := make(map[string]*npmRegistryAuthInfo) // map of nerf-darted url (//host/path) to auth
+
+	getOrCreateAuth := func(key string) *npmRegistryAuthInfo {
+		key = "//" + strings.TrimSuffix(key, "/")
+		if authInfo, ok := auths[key]; ok {
+			return authInfo
 		}
+		auths[key] = &npmRegistryAuthInfo{}
+
+		return auths[key]
 	}
 
 	// set the default registry
-	infos[""] = makeRegistryInfo("https://registry.npmjs.org")
+	registryURLs[""] = "https://registry.npmjs.org"
 	// Regexes for matching the scope/host in npmrc keys
 	var (
 		urlRegex       = cachedregexp.MustCompile(`^(@.*):registry$`)
@@ -244,15 +256,13 @@ func parseRegistryInfo(npmrc npmrcConfig) npmRegistries {
 
 	for _, k := range npmrc.Keys() {
 		name := k.Name()
-		value := os.ExpandEnv(k.String())
-		// TODO: npm config replaces only ${VAR}, not $VAR
-		// and if VAR is unset, it will leave the string as "${VAR}"
+		value := npmrcExpandEnv(k.String())
 		switch {
 		case name == "registry":
-			infos[""] = makeRegistryInfo(value)
+			registryURLs[""] = value
 		case urlRegex.MatchString(name):
 			scope := urlRegex.FindStringSubmatch(name)[1]
-			infos[scope] = makeRegistryInfo(value)
+			registryURLs[scope] = value
 		case authTokenRegex.MatchString(name):
 			u := authTokenRegex.FindStringSubmatch(name)[1]
 			info := getOrCreateAuth(u)
@@ -272,5 +282,28 @@ func parseRegistryInfo(npmrc npmrcConfig) npmRegistries {
 		}
 	}
 
-	return infos
+	infos := make(npmRegistries)
+	for scope, regURL := range registryURLs {
+		u, err := url.Parse(regURL)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing npm registry url %s: %w", regURL, err)
+		}
+		info := npmRegistryInfo{URL: regURL}
+		// Like npm, use the auth of the most specific path of the registry url that has any set
+		// e.g. for https://example.com/api/npm/registry/, check //example.com/api/npm/registry, //example.com/api/npm, ...
+		for key := nerfDart(u); ; {
+			if authInfo, ok := auths[key]; ok {
+				info.authInfo = authInfo
+				break
+			}
+			i := strings.LastIndex(key, "/")
+			if i <= 1 { // reached the "//" prefix
+				break
+			}
+			key = key[:i]
+		}
+		infos[scope] = info
+	}
+
+	return infos, nil
 }
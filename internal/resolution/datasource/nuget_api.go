@@ -0,0 +1,142 @@
+package datasource
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const nugetFlatContainerURL = "https://api.nuget.org/v3-flatcontainer"
+
+// NuGetAPIClient queries the NuGet V3 package content API (https://learn.microsoft.com/en-us/nuget/api/package-base-address-resource)
+// for the versions of packages and the dependencies in their .nuspec files.
+type NuGetAPIClient struct {
+	baseURL    string
+	httpClient *http.Client
+
+	// cache fields
+	mu             sync.Mutex
+	cacheTimestamp *time.Time // If set, this means we loaded from a cache
+	versions       map[string][]string
+	dependencies   map[string][]NuGetDependencyGroup // keyed by "id@version"
+}
+
+// NuGetDependencyGroup is the dependencies of a package for one target framework.
+type NuGetDependencyGroup struct {
+	TargetFramework string                  `xml:"targetFramework,attr"`
+	Dependencies    []NuGetNuspecDependency `xml:"dependency"`
+}
+
+type NuGetNuspecDependency struct {
+	ID      string `xml:"id,attr"`
+	Version string `xml:"version,attr"`
+}
+
+// NewNuGetAPIClient creates a client for the nuget.org API,
+// or the package content resource (PackageBaseAddress) of the configured registry URL.
+func NewNuGetAPIClient(cfg RegistryConfig) (*NuGetAPIClient, error) {
+	httpClient, err := cfg.HTTPClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return &NuGetAPIClient{
+		baseURL:      cfg.urlOr(nugetFlatContainerURL),
+		httpClient:   httpClient,
+		versions:     make(map[string][]string),
+		dependencies: make(map[string][]NuGetDependencyGroup),
+	}, nil
+}
+
+// Versions returns all the versions of the package, including unlisted versions.
+func (c *NuGetAPIClient) Versions(ctx context.Context, id string) ([]string, error) {
+	id = strings.ToLower(id)
+	c.mu.Lock()
+	vers, ok := c.versions[id]
+	c.mu.Unlock()
+	if ok {
+		return vers, nil
+	}
+
+	body, err := c.get(ctx, id, "index.json")
+	if err != nil {
+		return nil, err
+	}
+	var index struct {
+		Versions []string `json:"versions"`
+	}
+	if err := json.Unmarshal(body, &index); err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.versions[id] = index.Versions
+	c.mu.Unlock()
+
+	return index.Versions, nil
+}
+
+// Dependencies returns the dependency groups from the package version's .nuspec file.
+func (c *NuGetAPIClient) Dependencies(ctx context.Context, id, version string) ([]NuGetDependencyGroup, error) {
+	id = strings.ToLower(id)
+	version = strings.ToLower(version)
+	key := id + "@" + version
+	c.mu.Lock()
+	groups, ok := c.dependencies[key]
+	c.mu.Unlock()
+	if ok {
+		return groups, nil
+	}
+
+	body, err := c.get(ctx, id, version, id+".nuspec")
+	if err != nil {
+		return nil, err
+	}
+	var nuspec struct {
+		Metadata struct {
+			Dependencies struct {
+				Groups []NuGetDependencyGroup `xml:"group"`
+				// Old .nuspec files can list dependencies without groups, which apply to all frameworks
+				Dependencies []NuGetNuspecDependency `xml:"dependency"`
+			} `xml:"dependencies"`
+		} `xml:"metadata"`
+	}
+	if err := xml.Unmarshal(body, &nuspec); err != nil {
+		return nil, err
+	}
+	groups = nuspec.Metadata.Dependencies.Groups
+	if deps := nuspec.Metadata.Dependencies.Dependencies; len(deps) > 0 {
+		groups = append(groups, NuGetDependencyGroup{Dependencies: deps})
+	}
+
+	c.mu.Lock()
+	c.dependencies[key] = groups
+	c.mu.Unlock()
+
+	return groups, nil
+}
+
+func (c *NuGetAPIClient) get(ctx context.Context, urlComponents ...string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/"+strings.Join(urlComponents, "/"), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.New(resp.Status)
+	}
+
+	return io.ReadAll(resp.Body)
+}
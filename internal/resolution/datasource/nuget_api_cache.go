@@ -0,0 +1,50 @@
+package datasource
+
+import (
+	"time"
+)
+
+type nugetAPICache struct {
+	Timestamp    *time.Time
+	Versions     map[string][]string
+	Dependencies map[string][]NuGetDependencyGroup
+}
+
+func (c *NuGetAPIClient) GobEncode() ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.cacheTimestamp == nil {
+		now := time.Now().UTC()
+		c.cacheTimestamp = &now
+	}
+
+	cache := nugetAPICache{
+		Timestamp:    c.cacheTimestamp,
+		Versions:     c.versions,
+		Dependencies: c.dependencies,
+	}
+
+	return gobMarshal(&cache)
+}
+
+func (c *NuGetAPIClient) GobDecode(b []byte) error {
+	var cache nugetAPICache
+	if err := gobUnmarshal(b, &cache); err != nil {
+		return err
+	}
+
+	if cache.Timestamp != nil && time.Since(*cache.Timestamp) >= cacheExpiry {
+		// Cache expired
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.cacheTimestamp = cache.Timestamp
+	c.versions = cache.Versions
+	c.dependencies = cache.Dependencies
+
+	return nil
+}
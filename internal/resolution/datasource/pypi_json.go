@@ -0,0 +1,131 @@
+package datasource
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const pypiURL = "https://pypi.org/pypi"
+
+// PyPIJSONAPIClient queries the PyPI JSON API (https://warehouse.pypa.io/api-reference/json.html)
+// for the versions of packages and their dependencies.
+type PyPIJSONAPIClient struct {
+	baseURL    string
+	httpClient *http.Client
+
+	// cache fields
+	mu             sync.Mutex
+	cacheTimestamp *time.Time // If set, this means we loaded from a cache
+	versions       map[string][]string
+	requiresDist   map[string][]string // keyed by "name@version"
+}
+
+// NewPyPIJSONAPIClient creates a client for the pypi.org JSON API,
+// or the JSON API of the configured registry URL (e.g. "https://artifactory.example.com/api/pypi/pypi-remote/pypi").
+func NewPyPIJSONAPIClient(cfg RegistryConfig) (*PyPIJSONAPIClient, error) {
+	httpClient, err := cfg.HTTPClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return &PyPIJSONAPIClient{
+		baseURL:      cfg.urlOr(pypiURL),
+		httpClient:   httpClient,
+		versions:     make(map[string][]string),
+		requiresDist: make(map[string][]string),
+	}, nil
+}
+
+// Versions returns the versions of the package that have at least one file that has not been yanked.
+func (c *PyPIJSONAPIClient) Versions(ctx context.Context, name string) ([]string, error) {
+	c.mu.Lock()
+	vers, ok := c.versions[name]
+	c.mu.Unlock()
+	if ok {
+		return vers, nil
+	}
+
+	body, err := c.get(ctx, name, "json")
+	if err != nil {
+		return nil, err
+	}
+	var project struct {
+		Releases map[string][]struct {
+			Yanked bool `json:"yanked"`
+		} `json:"releases"`
+	}
+	if err := json.Unmarshal(body, &project); err != nil {
+		return nil, err
+	}
+	vers = make([]string, 0, len(project.Releases))
+	for v, files := range project.Releases {
+		for _, f := range files {
+			if !f.Yanked {
+				vers = append(vers, v)
+				break
+			}
+		}
+	}
+
+	c.mu.Lock()
+	c.versions[name] = vers
+	c.mu.Unlock()
+
+	return vers, nil
+}
+
+// RequiresDist returns the PEP 508 dependency specifiers of the package version.
+func (c *PyPIJSONAPIClient) RequiresDist(ctx context.Context, name, version string) ([]string, error) {
+	key := name + "@" + version
+	c.mu.Lock()
+	reqs, ok := c.requiresDist[key]
+	c.mu.Unlock()
+	if ok {
+		return reqs, nil
+	}
+
+	body, err := c.get(ctx, name, version, "json")
+	if err != nil {
+		return nil, err
+	}
+	var release struct {
+		Info struct {
+			RequiresDist []string `json:"requires_dist"`
+		} `json:"info"`
+	}
+	if err := json.Unmarshal(body, &release); err != nil {
+		return nil, err
+	}
+	reqs = release.Info.RequiresDist
+
+	c.mu.Lock()
+	c.requiresDist[key] = reqs
+	c.mu.Unlock()
+
+	return reqs, nil
+}
+
+func (c *PyPIJSONAPIClient) get(ctx context.Context, urlComponents ...string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/"+strings.Join(urlComponents, "/"), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.New(resp.Status)
+	}
+
+	return io.ReadAll(resp.Body)
+}
@@ -0,0 +1,57 @@
+package datasource
+
+import (
+	"time"
+)
+
+type pypiJSONAPICache struct {
+	Timestamp    *time.Time
+	BaseURL      string
+	Versions     map[string][]string
+	RequiresDist map[string][]string
+}
+
+func (c *PyPIJSONAPIClient) GobEncode() ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.cacheTimestamp == nil {
+		now := time.Now().UTC()
+		c.cacheTimestamp = &now
+	}
+
+	cache := pypiJSONAPICache{
+		Timestamp:    c.cacheTimestamp,
+		BaseURL:      c.baseURL,
+		Versions:     c.versions,
+		RequiresDist: c.requiresDist,
+	}
+
+	return gobMarshal(&cache)
+}
+
+func (c *PyPIJSONAPIClient) GobDecode(b []byte) error {
+	var cache pypiJSONAPICache
+	if err := gobUnmarshal(b, &cache); err != nil {
+		return err
+	}
+
+	if cache.Timestamp != nil && time.Since(*cache.Timestamp) >= cacheExpiry {
+		// Cache expired
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if cache.BaseURL != c.baseURL {
+		// The registry has changed, the cached data may not be valid
+		return nil
+	}
+
+	c.cacheTimestamp = cache.Timestamp
+	c.versions = cache.Versions
+	c.requiresDist = cache.RequiresDist
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package datasource
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// RegistryConfig configures how a package registry is accessed,
+// to allow using a mirror or proxy registry (e.g. Artifactory or Nexus) in place of the public registry.
+type RegistryConfig struct {
+	URL string // URL of the mirror registry, or empty to use the default public registry
+
+	// TLS client certificate authentication, as PEM files
+	CertFile string
+	KeyFile  string
+	// Extra CA certificates to trust when connecting to the registry, as a PEM file
+	CAFile string
+}
+
+// urlOr returns the configured registry URL without a trailing slash, or def if it is not set.
+func (cfg RegistryConfig) urlOr(def string) string {
+	if cfg.URL == "" {
+		return def
+	}
+
+	return strings.TrimSuffix(cfg.URL, "/")
+}
+
+// HTTPClient creates the http.Client to make requests to the registry with.
+func (cfg RegistryConfig) HTTPClient() (*http.Client, error) {
+	if cfg.CertFile == "" && cfg.KeyFile == "" && cfg.CAFile == "" {
+		return http.DefaultClient, nil
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if cfg.CertFile != "" || cfg.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("loading registry client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	if cfg.CAFile != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			return nil, fmt.Errorf("getting system cert pool: %w", err)
+		}
+		pem, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading registry CA certificate: %w", err)
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", cfg.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+
+	return &http.Client{Transport: transport}, nil
+}
@@ -8,7 +8,6 @@ import (
 	"github.com/google/osv-scanner/internal/resolution/manifest"
 	"github.com/google/osv-scanner/internal/resolution/util"
 	vulnUtil "github.com/google/osv-scanner/internal/utility/vulns"
-	"github.com/google/osv-scanner/pkg/lockfile"
 	"github.com/google/osv-scanner/pkg/models"
 )
 
@@ -29,16 +28,23 @@ func (dc DependencyChain) EndDependency() (resolve.VersionKey, string) {
 
 func ChainIsDev(dc DependencyChain, m manifest.Manifest) bool {
 	direct, _ := dc.DirectDependency()
-	ecosystem, ok := util.OSVEcosystem[direct.System]
-	if !ok {
-		return false
-	}
 
-	return lockfile.Ecosystem(ecosystem).IsDevGroup(m.Groups[direct.PackageKey])
+	return manifest.IsDevGroup(direct.System, m.Groups[direct.PackageKey])
 }
 
-// ComputeChains computes all paths from each specified NodeID to the root node.
-func ComputeChains(g *resolve.Graph, nodes []resolve.NodeID) [][]DependencyChain {
+// ChainMode selects which dependency chains ComputeChains finds.
+type ChainMode int
+
+const (
+	// AllChains finds every path to the root node.
+	// The number of paths can grow exponentially with the size of the graph.
+	AllChains ChainMode = iota
+	// ShortestChains finds, for each unique direct dependent of a node, the shortest path to the root node through it.
+	ShortestChains
+)
+
+// ComputeChains computes the paths from each specified NodeID to the root node, as selected by mode.
+func ComputeChains(g *resolve.Graph, nodes []resolve.NodeID, mode ChainMode) [][]DependencyChain {
 	// find the parent nodes of each node in graph, for easier traversal
 	parentEdges := make(map[resolve.NodeID][]resolve.Edge)
 	for _, e := range g.Edges {
@@ -49,6 +55,10 @@ func ComputeChains(g *resolve.Graph, nodes []resolve.NodeID) [][]DependencyChain
 		parentEdges[e.To] = append(parentEdges[e.To], e)
 	}
 
+	if mode == ShortestChains {
+		return computeShortestChains(g, nodes, parentEdges)
+	}
+
 	allChains := make([][]DependencyChain, len(nodes))
 	// for each node, traverse up all possible paths to the root node
 	for i, node := range nodes {
@@ -83,10 +93,58 @@ func ComputeChains(g *resolve.Graph, nodes []resolve.NodeID) [][]DependencyChain
 	return allChains
 }
 
+func computeShortestChains(g *resolve.Graph, nodes []resolve.NodeID, parentEdges map[resolve.NodeID][]resolve.Edge) [][]DependencyChain {
+	// breadth-first search down from the root, to find the edge to each node on its shortest path from the root
+	childEdges := make(map[resolve.NodeID][]resolve.Edge)
+	for _, e := range g.Edges {
+		if e.From != e.To {
+			childEdges[e.From] = append(childEdges[e.From], e)
+		}
+	}
+	shortestEdge := make(map[resolve.NodeID]resolve.Edge)
+	visited := map[resolve.NodeID]bool{0: true}
+	queue := []resolve.NodeID{0}
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		for _, e := range childEdges[n] {
+			if visited[e.To] {
+				continue
+			}
+			visited[e.To] = true
+			shortestEdge[e.To] = e
+			queue = append(queue, e.To)
+		}
+	}
+
+	allChains := make([][]DependencyChain, len(nodes))
+	for i, node := range nodes {
+	parents:
+		for _, pEdge := range parentEdges[node] {
+			if !visited[pEdge.From] {
+				// not reachable from the root
+				continue
+			}
+			chain := DependencyChain{Graph: g, Edges: []resolve.Edge{pEdge}}
+			for n := pEdge.From; n != 0; n = shortestEdge[n].From {
+				if n == node {
+					// the shortest path to this parent goes through the node itself
+					continue parents
+				}
+				chain.Edges = append(chain.Edges, shortestEdge[n])
+			}
+			allChains[i] = append(allChains[i], chain)
+		}
+		// shortest chain first
+		slices.SortStableFunc(allChains[i], func(a, b DependencyChain) int { return len(a.Edges) - len(b.Edges) })
+	}
+
+	return allChains
+}
+
 // chainConstrains check if a DependencyChain is 'Problematic'
 // i.e. if it is forcing the vulnerable package to chosen in resolution.
-func chainConstrains(ctx context.Context, cl resolve.Client, chain DependencyChain, vuln *models.Vulnerability) bool {
-	// TODO: Logic needs to be ecosystem-specific.
+func chainConstrains(ctx context.Context, cl resolve.Client, chain DependencyChain, vuln *models.Vulnerability, diags *Diagnostics) bool {
 	if len(chain.Edges) == 0 {
 		return false
 	}
@@ -99,17 +157,16 @@ func chainConstrains(ctx context.Context, cl resolve.Client, chain DependencyCha
 	// But resolving A alone could pick B@2.2.0 which might not depend on C
 	// Similarly, a direct dependency could be constrained by an indirect dependency with similar results.
 
-	// Check if the latest allowable version of the package is vulnerable
+	// Check if the version the requirement would resolve to on its own is vulnerable
 	vk, req := chain.EndDependency()
 	vk.Version = req
 	vk.VersionType = resolve.Requirement
-	vers, err := cl.MatchingVersions(ctx, vk)
+	isolatedVK, err := getChainConstrainer(vk.System).isolatedVersion(ctx, cl, vk)
 	if err != nil {
-		// TODO: handle error
+		diags.Add(DiagnosticRegistryError, chain.Graph.Nodes[chain.Edges[0].To].Version,
+			"could not find versions matching %q, assuming it requires the vulnerable version: %v", req, err)
 		return true
 	}
 
-	bestVk := vers[len(vers)-1] // This should be the highest version for npm
-
-	return vulnUtil.IsAffected(*vuln, util.VKToPackageDetails(bestVk.VersionKey))
+	return vulnUtil.IsAffected(*vuln, util.VKToPackageDetails(isolatedVK))
 }
@@ -0,0 +1,89 @@
+package resolution
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"deps.dev/util/resolve"
+	"deps.dev/util/resolve/dep"
+)
+
+// chainGraph builds the graph:
+//
+//	root -> a -> c -> v
+//	root -> b -> c -> a (cycle)
+//	        b -> v
+//	orphan -> v (unreachable from root)
+func chainGraph(t *testing.T) *resolve.Graph {
+	t.Helper()
+
+	g := &resolve.Graph{}
+	for _, name := range []string{"root", "a", "b", "c", "v", "orphan"} {
+		g.AddNode(resolve.VersionKey{PackageKey: resolve.PackageKey{System: resolve.NPM, Name: name}, Version: "1.0.0", VersionType: resolve.Concrete})
+	}
+	for _, e := range [][2]resolve.NodeID{{0, 1}, {0, 2}, {1, 3}, {2, 3}, {3, 1}, {3, 4}, {2, 4}, {5, 4}} {
+		if err := g.AddEdge(e[0], e[1], "^1.0.0", dep.NewType()); err != nil {
+			t.Fatalf("AddEdge() error = %v", err)
+		}
+	}
+
+	return g
+}
+
+// chainLines formats each chain as "node <- dependent <- ... <- root".
+func chainLines(chains []DependencyChain) []string {
+	lines := []string{}
+	for _, c := range chains {
+		names := []string{c.Graph.Nodes[c.Edges[0].To].Version.Name}
+		for _, e := range c.Edges {
+			names = append(names, c.Graph.Nodes[e.From].Version.Name)
+		}
+		lines = append(lines, strings.Join(names, " <- "))
+	}
+
+	return lines
+}
+
+func TestComputeChains(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		mode ChainMode
+		want [][]string
+	}{
+		{
+			name: "all chains",
+			mode: AllChains,
+			want: [][]string{
+				{"v <- b <- root", "v <- c <- a <- root", "v <- c <- b <- root"},
+				{"a <- root", "a <- c <- b <- root"},
+			},
+		},
+		{
+			// one chain per dependent, and chains back through the node itself are dropped
+			name: "shortest chains",
+			mode: ShortestChains,
+			want: [][]string{
+				{"v <- b <- root", "v <- c <- a <- root"},
+				{"a <- root"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			chains := ComputeChains(chainGraph(t), []resolve.NodeID{4, 1}, tt.mode)
+			got := make([][]string, len(chains))
+			for i, c := range chains {
+				got[i] = chainLines(c)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ComputeChains() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
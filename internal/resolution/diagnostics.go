@@ -0,0 +1,79 @@
+package resolution
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+
+	"deps.dev/util/resolve"
+)
+
+// DiagnosticKind is the category of a Diagnostic.
+type DiagnosticKind string
+
+const (
+	DiagnosticUnparseableConstraint DiagnosticKind = "unparseable-constraint"
+	DiagnosticRegistryError         DiagnosticKind = "registry-error"
+	DiagnosticSkippedPackage        DiagnosticKind = "skipped-package"
+	DiagnosticIntegrityMismatch     DiagnosticKind = "integrity-mismatch"
+	DiagnosticIntegrityUnverifiable DiagnosticKind = "integrity-unverifiable"
+	DiagnosticUnreadableManifest    DiagnosticKind = "unreadable-manifest"
+	DiagnosticExploitDataMissing    DiagnosticKind = "exploit-data-missing"
+	DiagnosticUnresolvedRequirement DiagnosticKind = "unresolved-requirement"
+)
+
+// Diagnostic is a problem encountered during resolution or remediation that did not stop it,
+// but which may have made the results less accurate.
+type Diagnostic struct {
+	Kind    DiagnosticKind `json:"kind"`
+	Package string         `json:"package,omitempty"`
+	Version string         `json:"version,omitempty"`
+	Message string         `json:"message"`
+}
+
+func (d Diagnostic) String() string {
+	switch {
+	case d.Package == "":
+		return fmt.Sprintf("%s: %s", d.Kind, d.Message)
+	case d.Version == "":
+		return fmt.Sprintf("%s: %s: %s", d.Kind, d.Package, d.Message)
+	default:
+		return fmt.Sprintf("%s: %s@%s: %s", d.Kind, d.Package, d.Version, d.Message)
+	}
+}
+
+// Diagnostics collects the Diagnostics of a run. It is safe for concurrent use, and a nil *Diagnostics discards everything.
+type Diagnostics struct {
+	mu   sync.Mutex
+	list []Diagnostic
+}
+
+// Add records a diagnostic about a package version, ignoring duplicates.
+func (d *Diagnostics) Add(kind DiagnosticKind, vk resolve.VersionKey, format string, a ...any) {
+	if d == nil {
+		return
+	}
+	diag := Diagnostic{
+		Kind:    kind,
+		Package: vk.Name,
+		Version: vk.Version,
+		Message: fmt.Sprintf(format, a...),
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !slices.Contains(d.list, diag) {
+		d.list = append(d.list, diag)
+	}
+}
+
+// List returns the collected diagnostics, in the order they were added.
+func (d *Diagnostics) List() []Diagnostic {
+	if d == nil {
+		return nil
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return slices.Clone(d.list)
+}
@@ -0,0 +1,92 @@
+package resolution
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"deps.dev/util/resolve"
+)
+
+func TestDiagnostic_String(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		diag Diagnostic
+		want string
+	}{
+		{
+			diag: Diagnostic{Kind: DiagnosticExploitDataMissing, Message: "could not get EPSS scores"},
+			want: "exploit-data-missing: could not get EPSS scores",
+		},
+		{
+			diag: Diagnostic{Kind: DiagnosticSkippedPackage, Package: "foo", Message: "not in the registry"},
+			want: "skipped-package: foo: not in the registry",
+		},
+		{
+			diag: Diagnostic{Kind: DiagnosticRegistryError, Package: "foo", Version: "1.0.0", Message: "timed out"},
+			want: "registry-error: foo@1.0.0: timed out",
+		},
+	}
+	for _, tt := range tests {
+		if got := tt.diag.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestDiagnostics(t *testing.T) {
+	t.Parallel()
+
+	foo := resolve.VersionKey{PackageKey: resolve.PackageKey{System: resolve.NPM, Name: "foo"}, Version: "1.0.0"}
+	bar := resolve.VersionKey{PackageKey: resolve.PackageKey{System: resolve.NPM, Name: "bar"}, Version: "2.0.0"}
+
+	var diags Diagnostics
+	diags.Add(DiagnosticUnparseableConstraint, foo, "could not parse %q", "^x")
+	diags.Add(DiagnosticRegistryError, bar, "timed out")
+	diags.Add(DiagnosticUnparseableConstraint, foo, "could not parse %q", "^x") // duplicate
+
+	want := []Diagnostic{
+		{Kind: DiagnosticUnparseableConstraint, Package: "foo", Version: "1.0.0", Message: `could not parse "^x"`},
+		{Kind: DiagnosticRegistryError, Package: "bar", Version: "2.0.0", Message: "timed out"},
+	}
+	got := diags.List()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("List() = %v, want %v", got, want)
+	}
+
+	// the list is a copy, so is not changed by later diagnostics
+	diags.Add(DiagnosticSkippedPackage, resolve.VersionKey{}, "skipped")
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("List() result changed to %v after Add()", got)
+	}
+}
+
+func TestDiagnostics_Nil(t *testing.T) {
+	t.Parallel()
+
+	var diags *Diagnostics
+	diags.Add(DiagnosticRegistryError, resolve.VersionKey{}, "discarded")
+	if got := diags.List(); got != nil {
+		t.Errorf("List() = %v, want nil", got)
+	}
+}
+
+func TestDiagnostics_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	var diags Diagnostics
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			diags.Add(DiagnosticRegistryError, resolve.VersionKey{}, "error %d", i%10)
+		}(i)
+	}
+	wg.Wait()
+
+	if got := len(diags.List()); got != 10 {
+		t.Errorf("List() has %d diagnostics, want 10", got)
+	}
+}
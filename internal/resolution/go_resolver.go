@@ -0,0 +1,84 @@
+package resolution
+
+import (
+	"context"
+
+	"deps.dev/util/resolve"
+	"golang.org/x/mod/semver"
+)
+
+// goResolver resolves Go module graphs using minimal version selection (https://research.swtch.com/vgo-mvs):
+// each module is resolved to the highest of the minimum versions required of it anywhere in the requirement graph.
+// TODO: module graph pruning (go >= 1.17), replace & exclude directives in the main module
+type goResolver struct {
+	cl resolve.Client
+}
+
+func newGoResolver(cl resolve.Client) resolve.Resolver {
+	return goResolver{cl: cl}
+}
+
+func (r goResolver) Resolve(ctx context.Context, vk resolve.VersionKey) (*resolve.Graph, error) {
+	// Walk the full requirement graph to find the selected version of every module
+	selected := make(map[resolve.PackageKey]string)
+	seen := make(map[resolve.VersionKey]struct{})
+	queue := []resolve.VersionKey{vk}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		reqs, err := r.cl.Requirements(ctx, cur)
+		if err != nil {
+			return nil, err
+		}
+		for _, req := range reqs {
+			if req.PackageKey == vk.PackageKey {
+				continue
+			}
+			if v, ok := selected[req.PackageKey]; !ok || semver.Compare(req.Version, v) > 0 {
+				selected[req.PackageKey] = req.Version
+			}
+			next := resolve.VersionKey{
+				PackageKey:  req.PackageKey,
+				Version:     req.Version,
+				VersionType: resolve.Concrete,
+			}
+			if _, ok := seen[next]; !ok {
+				seen[next] = struct{}{}
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	// Build the graph from the selected versions only,
+	// leaving out modules that are only required by versions that were not selected.
+	g := &resolve.Graph{}
+	nodes := make(map[resolve.PackageKey]resolve.NodeID)
+	nodes[vk.PackageKey] = g.AddNode(vk)
+	queue = []resolve.VersionKey{vk}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		reqs, err := r.cl.Requirements(ctx, cur)
+		if err != nil {
+			return nil, err
+		}
+		for _, req := range reqs {
+			nID, ok := nodes[req.PackageKey]
+			if !ok {
+				next := resolve.VersionKey{
+					PackageKey:  req.PackageKey,
+					Version:     selected[req.PackageKey],
+					VersionType: resolve.Concrete,
+				}
+				nID = g.AddNode(next)
+				nodes[req.PackageKey] = nID
+				queue = append(queue, next)
+			}
+			if err := g.AddEdge(nodes[cur.PackageKey], nID, req.Version, req.Type); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return g, nil
+}
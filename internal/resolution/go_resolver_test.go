@@ -0,0 +1,71 @@
+package resolution
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"deps.dev/util/resolve"
+	"github.com/google/osv-scanner/internal/resolution/util"
+)
+
+func TestGoResolver(t *testing.T) {
+	t.Parallel()
+
+	req := func(name, version string) resolve.RequirementVersion {
+		return testReq(util.Go, name, version)
+	}
+	tests := []struct {
+		name string
+		pkgs []testPackage // the first package is the root
+		want []string
+	}{
+		{
+			name: "minimal version selection",
+			pkgs: []testPackage{
+				{name: "example.com/root", version: "v0.0.0", reqs: []resolve.RequirementVersion{req("example.com/a", "v1.0.0"), req("example.com/b", "v1.0.0")}},
+				{name: "example.com/a", version: "v1.0.0", reqs: []resolve.RequirementVersion{req("example.com/c", "v1.1.0")}},
+				{name: "example.com/b", version: "v1.0.0", reqs: []resolve.RequirementVersion{req("example.com/c", "v1.2.0")}},
+				// only required by a version of c that is not selected
+				{name: "example.com/c", version: "v1.1.0", reqs: []resolve.RequirementVersion{req("example.com/d", "v1.0.0")}},
+				{name: "example.com/c", version: "v1.2.0"},
+				{name: "example.com/d", version: "v1.0.0"},
+			},
+			want: []string{
+				`example.com/root@v0.0.0 -> example.com/a@v1.0.0 "v1.0.0"`,
+				`example.com/root@v0.0.0 -> example.com/b@v1.0.0 "v1.0.0"`,
+				`example.com/a@v1.0.0 -> example.com/c@v1.2.0 "v1.1.0"`,
+				`example.com/b@v1.0.0 -> example.com/c@v1.2.0 "v1.2.0"`,
+			},
+		},
+		{
+			name: "higher requirement deeper in the graph",
+			pkgs: []testPackage{
+				{name: "example.com/root", version: "v0.0.0", reqs: []resolve.RequirementVersion{req("example.com/a", "v1.0.0"), req("example.com/b", "v1.0.0")}},
+				{name: "example.com/a", version: "v1.0.0", reqs: []resolve.RequirementVersion{req("example.com/b", "v1.3.0")}},
+				{name: "example.com/b", version: "v1.0.0"},
+				{name: "example.com/b", version: "v1.3.0"},
+			},
+			want: []string{
+				`example.com/root@v0.0.0 -> example.com/a@v1.0.0 "v1.0.0"`,
+				`example.com/root@v0.0.0 -> example.com/b@v1.3.0 "v1.0.0"`,
+				`example.com/a@v1.0.0 -> example.com/b@v1.3.0 "v1.3.0"`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := newGoResolver(newTestClient(util.Go, tt.pkgs))
+			g, err := r.Resolve(context.Background(), rootKey(util.Go, tt.pkgs))
+			if err != nil {
+				t.Fatalf("Resolve() error = %v", err)
+			}
+			if got := graphLines(g); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Resolve() graph:\n%q\nwant:\n%q", got, tt.want)
+			}
+		})
+	}
+}
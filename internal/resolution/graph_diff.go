@@ -0,0 +1,111 @@
+package resolution
+
+import (
+	"encoding/json"
+	"io"
+	"slices"
+
+	"deps.dev/util/resolve"
+)
+
+// PackageVersionChange is a package that resolved to a different version in the new graph.
+type PackageVersionChange struct {
+	Package    resolve.PackageKey `json:"package"`
+	OldVersion string             `json:"oldVersion"`
+	NewVersion string             `json:"newVersion"`
+}
+
+// GraphDiff is the difference between two resolved dependency graphs, such as a lockfile before and after a change.
+type GraphDiff struct {
+	Added           []resolve.VersionKey   `json:"added,omitempty"`
+	Removed         []resolve.VersionKey   `json:"removed,omitempty"`
+	Changed         []PackageVersionChange `json:"changed,omitempty"`
+	IntroducedVulns []string               `json:"introducedVulns,omitempty"`
+	RemovedVulns    []string               `json:"removedVulns,omitempty"`
+}
+
+// graphPackageVersions collects the distinct versions of each package in the graph, excluding the root.
+func graphPackageVersions(g *resolve.Graph) map[resolve.PackageKey][]string {
+	versions := make(map[resolve.PackageKey][]string)
+	for _, n := range g.Nodes[1:] {
+		vers := versions[n.Version.PackageKey]
+		if !slices.Contains(vers, n.Version.Version) {
+			versions[n.Version.PackageKey] = append(vers, n.Version.Version)
+		}
+	}
+
+	return versions
+}
+
+// DiffGraphs compares the resolved graphs and vulnerabilities of res and other.
+// A package with exactly one version in both graphs is reported as changed,
+// otherwise its versions that only appear in one graph are reported as added or removed.
+// The vulnerabilities compared are the filtered Vulns of both results.
+func (res *ResolutionResult) DiffGraphs(other *ResolutionResult) GraphDiff {
+	var diff GraphDiff
+	oldVersions := graphPackageVersions(res.Graph)
+	newVersions := graphPackageVersions(other.Graph)
+
+	for pk, oldVers := range oldVersions {
+		newVers := newVersions[pk]
+		if len(oldVers) == 1 && len(newVers) == 1 {
+			if oldVers[0] != newVers[0] {
+				diff.Changed = append(diff.Changed, PackageVersionChange{Package: pk, OldVersion: oldVers[0], NewVersion: newVers[0]})
+			}
+
+			continue
+		}
+		for _, v := range oldVers {
+			if !slices.Contains(newVers, v) {
+				diff.Removed = append(diff.Removed, resolve.VersionKey{PackageKey: pk, Version: v, VersionType: resolve.Concrete})
+			}
+		}
+		for _, v := range newVers {
+			if !slices.Contains(oldVers, v) {
+				diff.Added = append(diff.Added, resolve.VersionKey{PackageKey: pk, Version: v, VersionType: resolve.Concrete})
+			}
+		}
+	}
+	for pk, newVers := range newVersions {
+		if _, ok := oldVersions[pk]; ok {
+			continue
+		}
+		for _, v := range newVers {
+			diff.Added = append(diff.Added, resolve.VersionKey{PackageKey: pk, Version: v, VersionType: resolve.Concrete})
+		}
+	}
+
+	slices.SortFunc(diff.Added, resolve.VersionKey.Compare)
+	slices.SortFunc(diff.Removed, resolve.VersionKey.Compare)
+	slices.SortFunc(diff.Changed, func(a, b PackageVersionChange) int { return a.Package.Compare(b.Package) })
+
+	// Vulnerabilities are compared by ID, as in CalculateDiff
+	oldVulns := make(map[string]bool, len(res.Vulns))
+	for _, v := range res.Vulns {
+		oldVulns[v.Vulnerability.ID] = true
+	}
+	newVulns := make(map[string]bool, len(other.Vulns))
+	for _, v := range other.Vulns {
+		newVulns[v.Vulnerability.ID] = true
+		if !oldVulns[v.Vulnerability.ID] {
+			diff.IntroducedVulns = append(diff.IntroducedVulns, v.Vulnerability.ID)
+		}
+	}
+	for _, v := range res.Vulns {
+		if !newVulns[v.Vulnerability.ID] {
+			diff.RemovedVulns = append(diff.RemovedVulns, v.Vulnerability.ID)
+		}
+	}
+	slices.Sort(diff.IntroducedVulns)
+	slices.Sort(diff.RemovedVulns)
+
+	return diff
+}
+
+// WriteJSON writes the graph diff in JSON format.
+func (d GraphDiff) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(d)
+}
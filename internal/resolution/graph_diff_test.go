@@ -0,0 +1,94 @@
+package resolution
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"deps.dev/util/resolve"
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+// diffResult makes a result of a graph with the root and the package versions, e.g. "foo@1.0.0", affected by the vulns.
+func diffResult(pkgs []string, vulnIDs []string) *ResolutionResult {
+	g := &resolve.Graph{}
+	g.AddNode(resolve.VersionKey{PackageKey: resolve.PackageKey{System: resolve.NPM, Name: "root"}, Version: "1.0.0", VersionType: resolve.Concrete})
+	for _, p := range pkgs {
+		name, version, _ := strings.Cut(p, "@")
+		g.AddNode(resolve.VersionKey{PackageKey: resolve.PackageKey{System: resolve.NPM, Name: name}, Version: version, VersionType: resolve.Concrete})
+	}
+	res := &ResolutionResult{Graph: g}
+	for _, id := range vulnIDs {
+		res.Vulns = append(res.Vulns, ResolutionVuln{Vulnerability: models.Vulnerability{ID: id}})
+	}
+
+	return res
+}
+
+func TestResolutionResult_DiffGraphs(t *testing.T) {
+	t.Parallel()
+
+	npmVK := func(name, version string) resolve.VersionKey {
+		return resolve.VersionKey{PackageKey: resolve.PackageKey{System: resolve.NPM, Name: name}, Version: version, VersionType: resolve.Concrete}
+	}
+
+	oldRes := diffResult([]string{"foo@1.0.0", "bar@1.0.0", "baz@1.0.0", "baz@2.0.0", "gone@1.0.0", "bar@1.0.0"}, []string{"GHSA-a", "GHSA-b"})
+	newRes := diffResult([]string{"new@1.0.0", "foo@1.1.0", "bar@1.0.0", "baz@2.0.0", "baz@3.0.0"}, []string{"GHSA-c", "GHSA-b"})
+
+	want := GraphDiff{
+		// baz has more than one version, so its versions are added and removed rather than changed
+		Added:   []resolve.VersionKey{npmVK("baz", "3.0.0"), npmVK("new", "1.0.0")},
+		Removed: []resolve.VersionKey{npmVK("baz", "1.0.0"), npmVK("gone", "1.0.0")},
+		Changed: []PackageVersionChange{{
+			Package:    resolve.PackageKey{System: resolve.NPM, Name: "foo"},
+			OldVersion: "1.0.0",
+			NewVersion: "1.1.0",
+		}},
+		IntroducedVulns: []string{"GHSA-c"},
+		RemovedVulns:    []string{"GHSA-a"},
+	}
+	if got := oldRes.DiffGraphs(newRes); !reflect.DeepEqual(got, want) {
+		t.Errorf("DiffGraphs() = %+v, want %+v", got, want)
+	}
+
+	// and the reverse
+	want = GraphDiff{
+		Added:   want.Removed,
+		Removed: want.Added,
+		Changed: []PackageVersionChange{{
+			Package:    resolve.PackageKey{System: resolve.NPM, Name: "foo"},
+			OldVersion: "1.1.0",
+			NewVersion: "1.0.0",
+		}},
+		IntroducedVulns: want.RemovedVulns,
+		RemovedVulns:    want.IntroducedVulns,
+	}
+	if got := newRes.DiffGraphs(oldRes); !reflect.DeepEqual(got, want) {
+		t.Errorf("DiffGraphs() reversed = %+v, want %+v", got, want)
+	}
+}
+
+func TestGraphDiff_WriteJSON(t *testing.T) {
+	t.Parallel()
+
+	res := diffResult([]string{"foo@1.0.0"}, []string{"GHSA-a"})
+	var sb strings.Builder
+	if err := res.DiffGraphs(res).WriteJSON(&sb); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+	// identical graphs have no differences to write
+	if got := sb.String(); got != "{}\n" {
+		t.Errorf("WriteJSON() = %q, want %q", got, "{}\n")
+	}
+
+	sb.Reset()
+	diff := diffResult([]string{"foo@1.0.0"}, nil).DiffGraphs(diffResult([]string{"foo@1.1.0"}, []string{"GHSA-a"}))
+	if err := diff.WriteJSON(&sb); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+	for _, want := range []string{`"oldVersion": "1.0.0"`, `"newVersion": "1.1.0"`, `"introducedVulns": [`, `"GHSA-a"`} {
+		if !strings.Contains(sb.String(), want) {
+			t.Errorf("WriteJSON() = %s, want it to contain %s", sb.String(), want)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package resolution
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"deps.dev/util/resolve"
+)
+
+type graphEdgeKey struct {
+	From, To resolve.NodeID
+}
+
+// graphHighlights finds the vulnerability IDs affecting each node, and the edges that are part of a problem chain.
+func (res *ResolutionResult) graphHighlights() (map[resolve.NodeID][]string, map[graphEdgeKey]bool) {
+	nodeVulns := make(map[resolve.NodeID][]string)
+	problemEdges := make(map[graphEdgeKey]bool)
+	for _, v := range res.Vulns {
+		for _, chain := range v.ProblemChains {
+			for _, e := range chain.Edges {
+				problemEdges[graphEdgeKey{e.From, e.To}] = true
+			}
+			end := chain.Edges[0].To
+			if !slices.Contains(nodeVulns[end], v.Vulnerability.ID) {
+				nodeVulns[end] = append(nodeVulns[end], v.Vulnerability.ID)
+			}
+		}
+	}
+	for _, ids := range nodeVulns {
+		slices.Sort(ids)
+	}
+
+	return nodeVulns, problemEdges
+}
+
+func graphNodeLabel(n resolve.Node) string {
+	return n.Version.Name + "@" + n.Version.Version
+}
+
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
+
+// WriteDOT writes the resolved graph in Graphviz DOT format.
+// Vulnerable nodes and the edges of the chains constraining them to vulnerable versions are highlighted in red.
+func (res *ResolutionResult) WriteDOT(w io.Writer) error {
+	nodeVulns, problemEdges := res.graphHighlights()
+
+	var sb strings.Builder
+	sb.WriteString("digraph dependencies {\n")
+	sb.WriteString("  node [shape=box];\n")
+	for i, n := range res.Graph.Nodes {
+		id := resolve.NodeID(i)
+		fmt.Fprintf(&sb, "  n%d [label=%s", id, dotQuote(graphNodeLabel(n)))
+		if vulns := nodeVulns[id]; len(vulns) > 0 {
+			fmt.Fprintf(&sb, ", color=red, style=filled, fillcolor=\"#ffdddd\", tooltip=%s", dotQuote(strings.Join(vulns, "\n")))
+		}
+		sb.WriteString("];\n")
+	}
+	for _, e := range res.Graph.Edges {
+		fmt.Fprintf(&sb, "  n%d -> n%d [label=%s", e.From, e.To, dotQuote(e.Requirement))
+		if problemEdges[graphEdgeKey{e.From, e.To}] {
+			sb.WriteString(", color=red, penwidth=2")
+		}
+		sb.WriteString("];\n")
+	}
+	sb.WriteString("}\n")
+
+	_, err := io.WriteString(w, sb.String())
+
+	return err
+}
+
+type graphML struct {
+	XMLName xml.Name     `xml:"graphml"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	Keys    []graphMLKey `xml:"key"`
+	Graph   graphMLGraph `xml:"graph"`
+}
+
+type graphMLKey struct {
+	ID       string `xml:"id,attr"`
+	For      string `xml:"for,attr"`
+	AttrName string `xml:"attr.name,attr"`
+	AttrType string `xml:"attr.type,attr"`
+}
+
+type graphMLGraph struct {
+	ID          string        `xml:"id,attr"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Nodes       []graphMLNode `xml:"node"`
+	Edges       []graphMLEdge `xml:"edge"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+type graphMLNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+type graphMLEdge struct {
+	Source string        `xml:"source,attr"`
+	Target string        `xml:"target,attr"`
+	Data   []graphMLData `xml:"data"`
+}
+
+// WriteGraphML writes the resolved graph in GraphML format.
+// Nodes have the vulnerability IDs affecting them, and edges are marked if they are part of a chain
+// constraining a package to a vulnerable version.
+func (res *ResolutionResult) WriteGraphML(w io.Writer) error {
+	nodeVulns, problemEdges := res.graphHighlights()
+
+	doc := graphML{
+		XMLNS: "http://graphml.graphdrawing.org/xmlns",
+		Keys: []graphMLKey{
+			{ID: "name", For: "node", AttrName: "name", AttrType: "string"},
+			{ID: "version", For: "node", AttrName: "version", AttrType: "string"},
+			{ID: "vulns", For: "node", AttrName: "vulns", AttrType: "string"},
+			{ID: "requirement", For: "edge", AttrName: "requirement", AttrType: "string"},
+			{ID: "problem", For: "edge", AttrName: "problem", AttrType: "boolean"},
+		},
+		Graph: graphMLGraph{ID: "dependencies", EdgeDefault: "directed"},
+	}
+	for i, n := range res.Graph.Nodes {
+		id := resolve.NodeID(i)
+		node := graphMLNode{
+			ID: fmt.Sprintf("n%d", id),
+			Data: []graphMLData{
+				{Key: "name", Value: n.Version.Name},
+				{Key: "version", Value: n.Version.Version},
+			},
+		}
+		if vulns := nodeVulns[id]; len(vulns) > 0 {
+			node.Data = append(node.Data, graphMLData{Key: "vulns", Value: strings.Join(vulns, ",")})
+		}
+		doc.Graph.Nodes = append(doc.Graph.Nodes, node)
+	}
+	for _, e := range res.Graph.Edges {
+		doc.Graph.Edges = append(doc.Graph.Edges, graphMLEdge{
+			Source: fmt.Sprintf("n%d", e.From),
+			Target: fmt.Sprintf("n%d", e.To),
+			Data: []graphMLData{
+				{Key: "requirement", Value: e.Requirement},
+				{Key: "problem", Value: fmt.Sprint(problemEdges[graphEdgeKey{e.From, e.To}])},
+			},
+		})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+
+	return err
+}
@@ -0,0 +1,118 @@
+package resolution
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"deps.dev/util/resolve"
+	"deps.dev/util/resolve/dep"
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+// exportResult is a resolved graph root -> a -> v, root -> b -> v, where a constrains v to a vulnerable version.
+func exportResult(t *testing.T) *ResolutionResult {
+	t.Helper()
+
+	g := &resolve.Graph{}
+	for _, name := range []string{"root", "a", "b", "v"} {
+		g.AddNode(resolve.VersionKey{PackageKey: resolve.PackageKey{System: resolve.NPM, Name: name}, Version: "1.0.0", VersionType: resolve.Concrete})
+	}
+	for _, e := range []struct {
+		from, to resolve.NodeID
+		req      string
+	}{{0, 1, "^1.0.0"}, {0, 2, "^1.0.0"}, {1, 3, "1.0.0"}, {2, 3, "^1.0.0"}} {
+		if err := g.AddEdge(e.from, e.to, e.req, dep.NewType()); err != nil {
+			t.Fatalf("AddEdge() error = %v", err)
+		}
+	}
+
+	return &ResolutionResult{
+		Graph: g,
+		Vulns: []ResolutionVuln{{
+			Vulnerability:    models.Vulnerability{ID: "GHSA-v"},
+			ProblemChains:    []DependencyChain{{Graph: g, Edges: []resolve.Edge{g.Edges[2], g.Edges[0]}}},
+			NonProblemChains: []DependencyChain{{Graph: g, Edges: []resolve.Edge{g.Edges[3], g.Edges[1]}}},
+		}},
+	}
+}
+
+func TestResolutionResult_WriteDOT(t *testing.T) {
+	t.Parallel()
+
+	var sb strings.Builder
+	if err := exportResult(t).WriteDOT(&sb); err != nil {
+		t.Fatalf("WriteDOT() error = %v", err)
+	}
+
+	want := `digraph dependencies {
+  node [shape=box];
+  n0 [label="root@1.0.0"];
+  n1 [label="a@1.0.0"];
+  n2 [label="b@1.0.0"];
+  n3 [label="v@1.0.0", color=red, style=filled, fillcolor="#ffdddd", tooltip="GHSA-v"];
+  n0 -> n1 [label="^1.0.0", color=red, penwidth=2];
+  n0 -> n2 [label="^1.0.0"];
+  n1 -> n3 [label="1.0.0", color=red, penwidth=2];
+  n2 -> n3 [label="^1.0.0"];
+}
+`
+	if got := sb.String(); got != want {
+		t.Errorf("WriteDOT() =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestDotQuote(t *testing.T) {
+	t.Parallel()
+
+	if got, want := dotQuote("a \"b\" \\c\nd"), `"a \"b\" \\c\nd"`; got != want {
+		t.Errorf("dotQuote() = %s, want %s", got, want)
+	}
+}
+
+func TestResolutionResult_WriteGraphML(t *testing.T) {
+	t.Parallel()
+
+	var sb strings.Builder
+	if err := exportResult(t).WriteGraphML(&sb); err != nil {
+		t.Fatalf("WriteGraphML() error = %v", err)
+	}
+
+	// the output must be valid GraphML, so read it back to check the data of the nodes and edges
+	var doc graphML
+	if err := xml.Unmarshal([]byte(sb.String()), &doc); err != nil {
+		t.Fatalf("WriteGraphML() wrote invalid XML: %v", err)
+	}
+	if doc.XMLName.Space != "http://graphml.graphdrawing.org/xmlns" || doc.Graph.EdgeDefault != "directed" {
+		t.Errorf("WriteGraphML() namespace = %q, edgedefault = %q", doc.XMLName.Space, doc.Graph.EdgeDefault)
+	}
+
+	data := func(d []graphMLData) string {
+		var parts []string
+		for _, kv := range d {
+			parts = append(parts, kv.Key+"="+kv.Value)
+		}
+
+		return strings.Join(parts, " ")
+	}
+	var got []string
+	for _, n := range doc.Graph.Nodes {
+		got = append(got, n.ID+": "+data(n.Data))
+	}
+	for _, e := range doc.Graph.Edges {
+		got = append(got, e.Source+" -> "+e.Target+": "+data(e.Data))
+	}
+	want := []string{
+		"n0: name=root version=1.0.0",
+		"n1: name=a version=1.0.0",
+		"n2: name=b version=1.0.0",
+		"n3: name=v version=1.0.0 vulns=GHSA-v",
+		"n0 -> n1: requirement=^1.0.0 problem=true",
+		"n0 -> n2: requirement=^1.0.0 problem=false",
+		"n1 -> n3: requirement=1.0.0 problem=true",
+		"n2 -> n3: requirement=^1.0.0 problem=false",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("WriteGraphML() =\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
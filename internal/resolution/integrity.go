@@ -0,0 +1,95 @@
+package resolution
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"deps.dev/util/resolve"
+	"github.com/google/osv-scanner/internal/resolution/client"
+	"golang.org/x/sync/errgroup"
+)
+
+// maxConcurrentIntegrityChecks bounds the number of registry requests made at once when verifying integrity hashes.
+const maxConcurrentIntegrityChecks = 16
+
+// parseIntegrity splits Subresource Integrity hashes (e.g. "sha512-abc== sha1-def=") into the digests of each algorithm.
+func parseIntegrity(integrity string) map[string][]string {
+	digests := make(map[string][]string)
+	for _, h := range strings.Fields(integrity) {
+		h, _, _ = strings.Cut(h, "?") // remove any options
+		alg, digest, ok := strings.Cut(h, "-")
+		if ok && digest != "" {
+			digests[alg] = append(digests[alg], digest)
+		}
+	}
+
+	return digests
+}
+
+// compareIntegrity checks if every algorithm the locked and published hashes have in common has a matching digest.
+// If they have no algorithms in common they cannot be compared, and comparable is false.
+func compareIntegrity(locked, published string) (matches, comparable bool) {
+	publishedDigests := parseIntegrity(published)
+	for alg, digests := range parseIntegrity(locked) {
+		pub, ok := publishedDigests[alg]
+		if !ok {
+			continue
+		}
+		comparable = true
+		if !slices.ContainsFunc(digests, func(d string) bool { return slices.Contains(pub, d) }) {
+			return false, true
+		}
+	}
+
+	return comparable, comparable
+}
+
+// VerifyIntegrity compares the integrity hashes recorded in a lockfile with the hashes of the archives published to the registry.
+// Each package version whose hashes do not match, which may mean the lockfile has been tampered with,
+// is reported as a DiagnosticIntegrityMismatch.
+// Versions are not checked if the client does not know their published hashes,
+// and versions whose hashes use different algorithms to the registry's are reported as DiagnosticIntegrityUnverifiable.
+func VerifyIntegrity(ctx context.Context, cl client.DependencyClient, integrities map[resolve.VersionKey]string) []Diagnostic {
+	ic, ok := client.As[client.IntegrityClient](cl)
+	if !ok {
+		return nil
+	}
+
+	var diags Diagnostics
+	var eg errgroup.Group
+	eg.SetLimit(maxConcurrentIntegrityChecks)
+	for vk, locked := range integrities {
+		vk, locked := vk, locked
+		eg.Go(func() error {
+			published, err := ic.Integrity(ctx, vk)
+			if err != nil {
+				diags.Add(DiagnosticRegistryError, vk, "could not get its published integrity hash: %v", err)
+				return nil
+			}
+			if published == "" {
+				return nil
+			}
+			switch matches, comparable := compareIntegrity(locked, published); {
+			case !comparable:
+				diags.Add(DiagnosticIntegrityUnverifiable, vk, "lockfile integrity %q has no hash algorithm in common with the registry's %q", locked, published)
+			case !matches:
+				diags.Add(DiagnosticIntegrityMismatch, vk, "lockfile integrity %q does not match the registry's %q, the lockfile may have been tampered with", locked, published)
+			}
+
+			return nil
+		})
+	}
+	_ = eg.Wait() // errors are recorded as diagnostics
+
+	list := diags.List()
+	slices.SortFunc(list, func(a, b Diagnostic) int {
+		if c := strings.Compare(a.Package, b.Package); c != 0 {
+			return c
+		}
+
+		return strings.Compare(a.Version, b.Version)
+	})
+
+	return list
+}
@@ -0,0 +1,69 @@
+package resolution
+
+import "testing"
+
+func TestCompareIntegrity(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		locked         string
+		published      string
+		wantMatches    bool
+		wantComparable bool
+	}{
+		{
+			name:           "same hash",
+			locked:         "sha512-abc==",
+			published:      "sha512-abc==",
+			wantMatches:    true,
+			wantComparable: true,
+		},
+		{
+			name:           "different hash",
+			locked:         "sha512-abc==",
+			published:      "sha512-def==",
+			wantMatches:    false,
+			wantComparable: true,
+		},
+		{
+			name:           "only common algorithms are compared",
+			locked:         "sha1-old sha512-abc==",
+			published:      "sha512-abc==",
+			wantMatches:    true,
+			wantComparable: true,
+		},
+		{
+			name:           "any common algorithm mismatching",
+			locked:         "sha1-old sha512-abc==",
+			published:      "sha1-new sha512-abc==",
+			wantMatches:    false,
+			wantComparable: true,
+		},
+		{
+			name:           "no common algorithms",
+			locked:         "sha1-abc",
+			published:      "sha512-abc==",
+			wantMatches:    false,
+			wantComparable: false,
+		},
+		{
+			name:           "unparseable lockfile hash",
+			locked:         "not-a-hash",
+			published:      "sha512-abc==",
+			wantMatches:    false,
+			wantComparable: false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			matches, comparable := compareIntegrity(tt.locked, tt.published)
+			if matches != tt.wantMatches || comparable != tt.wantComparable {
+				t.Errorf("compareIntegrity(%q, %q) = %v, %v, want %v, %v", tt.locked, tt.published, matches, comparable, tt.wantMatches, tt.wantComparable)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package lockfile
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"deps.dev/util/resolve"
+	"deps.dev/util/resolve/dep"
+	"github.com/BurntSushi/toml"
+	"github.com/google/osv-scanner/internal/cachedregexp"
+	"github.com/google/osv-scanner/internal/resolution/util"
+	"github.com/google/osv-scanner/pkg/lockfile"
+)
+
+type CargoLockfileIO struct{}
+
+const cratesIORegistrySource = "registry+https://github.com/rust-lang/crates.io-index"
+
+type cargoLockPackage struct {
+	Name         string   `toml:"name"`
+	Version      string   `toml:"version"`
+	Source       string   `toml:"source"`
+	Dependencies []string `toml:"dependencies"`
+}
+
+type cargoLockFile struct {
+	Packages []cargoLockPackage `toml:"package"`
+}
+
+func (rw CargoLockfileIO) Read(file lockfile.DepFile) (*resolve.Graph, error) {
+	var lock cargoLockFile
+	if _, err := toml.NewDecoder(file).Decode(&lock); err != nil {
+		return nil, fmt.Errorf("could not parse %s: %w", file.Path(), err)
+	}
+	if len(lock.Packages) == 0 {
+		return nil, errors.New("no packages in Cargo.lock")
+	}
+
+	// The root package is the one described by the Cargo.toml next to the lockfile.
+	// Fall back to the first local (i.e. source-less) package if it can't be found.
+	rootIdx := slices.IndexFunc(lock.Packages, func(p cargoLockPackage) bool { return p.Source == "" })
+	if f, err := file.Open("Cargo.toml"); err == nil {
+		var cargoTOML struct {
+			Package struct {
+				Name string `toml:"name"`
+			} `toml:"package"`
+		}
+		if _, err := toml.NewDecoder(f).Decode(&cargoTOML); err == nil {
+			if idx := slices.IndexFunc(lock.Packages, func(p cargoLockPackage) bool {
+				return p.Source == "" && p.Name == cargoTOML.Package.Name
+			}); idx >= 0 {
+				rootIdx = idx
+			}
+		}
+		f.Close()
+	}
+	if rootIdx < 0 {
+		return nil, errors.New("could not find root package in Cargo.lock")
+	}
+
+	// The root node has to be the first node in the graph
+	pkgs := slices.Clone(lock.Packages)
+	pkgs[0], pkgs[rootIdx] = pkgs[rootIdx], pkgs[0]
+
+	g := &resolve.Graph{}
+	byName := make(map[string][]resolve.NodeID)
+	for _, p := range pkgs {
+		id := g.AddNode(resolve.VersionKey{
+			PackageKey: resolve.PackageKey{
+				System: util.Cargo,
+				Name:   p.Name,
+			},
+			Version:     p.Version,
+			VersionType: resolve.Concrete,
+		})
+		byName[p.Name] = append(byName[p.Name], id)
+	}
+
+	for i, p := range pkgs {
+		for _, d := range p.Dependencies {
+			// Dependencies are "name", "name version", or "name version (source)",
+			// with the version and source only included if needed to disambiguate.
+			fields := strings.Fields(d)
+			if len(fields) == 0 {
+				continue
+			}
+			candidates := byName[fields[0]]
+			idx := slices.IndexFunc(candidates, func(id resolve.NodeID) bool {
+				return len(fields) < 2 || g.Nodes[id].Version.Version == fields[1]
+			})
+			if idx < 0 {
+				return nil, fmt.Errorf("could not find dependency %q of %s in Cargo.lock", d, p.Name)
+			}
+			to := candidates[idx]
+			toPkg := pkgs[to]
+
+			// Cargo.lock does not record the requirements, so use Cargo's default caret requirement on the locked version,
+			// which is always at least as strict as the actual requirement.
+			req := "^" + toPkg.Version
+			typ := dep.NewType()
+			switch {
+			case toPkg.Source == "":
+				typ.AddAttr(dep.Scope, WorkspaceScope)
+				req = toPkg.Version
+			case toPkg.Source != cratesIORegistrySource && !strings.HasPrefix(toPkg.Source, "sparse+"):
+				// crates from git or other registries cannot be changed, use the source as the requirement
+				req = toPkg.Source
+			}
+			if err := g.AddEdge(resolve.NodeID(i), to, req, typ); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return g, nil
+}
+
+func (rw CargoLockfileIO) Write(original lockfile.DepFile, output io.Writer, patches []DependencyPatch) error {
+	var buf strings.Builder
+	if _, err := io.Copy(&buf, original); err != nil {
+		return err
+	}
+	text := buf.String()
+
+	pkgRe := cachedregexp.MustCompile(`(?s)\[\[package\]\]\n.*?(?:\n\n|$)`)
+	for _, p := range patches {
+		found := false
+		text = pkgRe.ReplaceAllStringFunc(text, func(block string) string {
+			if !strings.Contains(block, "\nname = \""+p.Pkg.Name+"\"\n") || !strings.Contains(block, "\nversion = \""+p.OrigVersion+"\"\n") {
+				return block
+			}
+			found = true
+			block = strings.Replace(block, "\nversion = \""+p.OrigVersion+"\"\n", "\nversion = \""+p.NewVersion+"\"\n", 1)
+			// The checksum is of the original version's crate, remove it so Cargo recomputes it
+			block = cachedregexp.MustCompile(`(?m)^checksum = ".*"\n`).ReplaceAllString(block, "")
+
+			return block
+		})
+		if !found {
+			return fmt.Errorf("could not find package %s@%s in Cargo.lock", p.Pkg.Name, p.OrigVersion)
+		}
+		// Dependencies refer to the version if there are multiple versions of the crate in the lockfile
+		text = strings.ReplaceAll(text, "\""+p.Pkg.Name+" "+p.OrigVersion+"\"", "\""+p.Pkg.Name+" "+p.NewVersion+"\"")
+		text = strings.ReplaceAll(text, "\""+p.Pkg.Name+" "+p.OrigVersion+" (", "\""+p.Pkg.Name+" "+p.NewVersion+" (")
+	}
+
+	_, err := io.WriteString(output, text)
+
+	return err
+}
@@ -0,0 +1,137 @@
+package lockfile
+
+import (
+	"bufio"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"deps.dev/util/resolve"
+	"github.com/google/osv-scanner/pkg/lockfile"
+)
+
+// IntegrityReader is implemented by LockfileIOs whose lockfiles record the integrity hashes of the installed packages.
+type IntegrityReader interface {
+	// Integrities reads the Subresource Integrity hashes (e.g. "sha512-...") of the installed package versions.
+	// Packages without an integrity hash, such as local workspaces, are not included.
+	Integrities(file lockfile.DepFile) (map[resolve.VersionKey]string, error)
+}
+
+func (rw NpmLockfileIO) Integrities(file lockfile.DepFile) (map[resolve.VersionKey]string, error) {
+	var lockJSON lockfile.NpmLockfile
+	if err := json.NewDecoder(file).Decode(&lockJSON); err != nil {
+		return nil, err
+	}
+
+	integrities := make(map[resolve.VersionKey]string)
+	add := func(name, version, integrity string) {
+		if integrity == "" {
+			return
+		}
+		integrities[resolve.VersionKey{
+			PackageKey: resolve.PackageKey{
+				System: resolve.NPM,
+				Name:   name,
+			},
+			VersionType: resolve.Concrete,
+			Version:     version,
+		}] = integrity
+	}
+
+	if lockJSON.Packages != nil {
+		for path, pkg := range lockJSON.Packages {
+			name := pkg.Name
+			if idx := strings.LastIndex(path, "node_modules/"); name == "" && idx >= 0 {
+				name = path[idx+len("node_modules/"):]
+			}
+			add(name, pkg.Version, pkg.Integrity)
+		}
+
+		return integrities, nil
+	}
+
+	var addDeps func(deps map[string]lockfile.NpmLockDependency)
+	addDeps = func(deps map[string]lockfile.NpmLockDependency) {
+		for name, d := range deps {
+			version := d.Version
+			// aliased packages have versions like "npm:[name]@[version]"
+			if aliased, ok := strings.CutPrefix(version, "npm:"); ok {
+				if idx := strings.LastIndex(aliased, "@"); idx > 0 {
+					name, version = aliased[:idx], aliased[idx+1:]
+				}
+			}
+			add(name, version, d.Integrity)
+			addDeps(d.Dependencies)
+		}
+	}
+	addDeps(lockJSON.Dependencies)
+
+	return integrities, nil
+}
+
+// Integrities reads the integrity hashes of a Yarn Classic (v1) lockfile, which are of the registry's tarballs.
+// Yarn Berry's checksums are of the archives in Yarn's own cache rather than the registry's tarballs,
+// so cannot be verified against the registry and none are returned for Berry lockfiles.
+func (rw YarnBerryLockfileIO) Integrities(file lockfile.DepFile) (map[resolve.VersionKey]string, error) {
+	integrities := make(map[resolve.VersionKey]string)
+	var name, version, integrity string
+	add := func() {
+		if name != "" && version != "" && integrity != "" {
+			integrities[resolve.VersionKey{
+				PackageKey: resolve.PackageKey{
+					System: resolve.NPM,
+					Name:   name,
+				},
+				VersionType: resolve.Concrete,
+				Version:     version,
+			}] = integrity
+		}
+		name, version, integrity = "", "", ""
+	}
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		// skip comments, and the nested fields of dependencies
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "    ") {
+			continue
+		}
+		if !strings.HasPrefix(line, " ") {
+			// the start of a package entry e.g. `"foo@^1.0.0", foo@^1.1.0:`
+			if strings.HasPrefix(line, "__metadata:") {
+				return integrities, nil // a Yarn Berry lockfile
+			}
+			add()
+			name = yarnClassicName(line)
+
+			continue
+		}
+		field, value, _ := strings.Cut(strings.TrimSpace(line), " ")
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			value = unquoted
+		}
+		switch field {
+		case "version":
+			version = value
+		case "integrity":
+			integrity = value
+		}
+	}
+	add()
+
+	return integrities, scanner.Err()
+}
+
+// yarnClassicName returns the name of the package of a Yarn Classic lockfile entry, from the first descriptor of its key.
+// Aliases e.g. "alias@npm:foo@^1.0.0" are named after the real package.
+func yarnClassicName(key string) string {
+	key = strings.TrimSuffix(key, ":")
+	first, _, _ := strings.Cut(key, ",")
+	first = strings.Trim(strings.TrimSpace(first), `"`)
+	name, r := yarnBerryDescriptor(first)
+	if aliased, ok := strings.CutPrefix(r, "npm:"); ok && strings.Contains(aliased[min(1, len(aliased)):], "@") {
+		name, _ = yarnBerryDescriptor(aliased)
+	}
+
+	return name
+}
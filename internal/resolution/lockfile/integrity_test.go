@@ -0,0 +1,55 @@
+package lockfile_test
+
+import (
+	"reflect"
+	"testing"
+
+	"deps.dev/util/resolve"
+	lf "github.com/google/osv-scanner/internal/resolution/lockfile"
+	"github.com/google/osv-scanner/pkg/lockfile"
+)
+
+func TestYarnBerryLockfileIO_Integrities(t *testing.T) {
+	t.Parallel()
+
+	npmVersion := func(name, version string) resolve.VersionKey {
+		return resolve.VersionKey{PackageKey: resolve.PackageKey{System: resolve.NPM, Name: name}, Version: version, VersionType: resolve.Concrete}
+	}
+	tests := []struct {
+		path string
+		want map[resolve.VersionKey]string
+	}{
+		{
+			path: "fixtures/yarn-classic/yarn.lock",
+			want: map[resolve.VersionKey]string{
+				npmVersion("@babel/code-frame", "7.22.13"): "sha512-XktuhWlJ5g+3TJXc5upd9Ks1HutSArik6jf2eAjYFyIOf4ej3RN+184cZbzDvbPnuTJIUhPKKJE3cIsYTiAT3w==",
+				npmVersion("foo", "1.0.0"):                 "sha1-abc",
+			},
+		},
+		{
+			// Yarn Berry checksums are not of the registry's tarballs
+			path: "fixtures/yarn-berry/yarn.lock",
+			want: map[resolve.VersionKey]string{},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.path, func(t *testing.T) {
+			t.Parallel()
+
+			f, err := lockfile.OpenLocalDepFile(tt.path)
+			if err != nil {
+				t.Fatalf("failed to open fixture: %v", err)
+			}
+			defer f.Close()
+
+			got, err := lf.YarnBerryLockfileIO{}.Integrities(f)
+			if err != nil {
+				t.Fatalf("Integrities() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Integrities() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 
 	"deps.dev/util/resolve"
+	"github.com/google/osv-scanner/internal/resolution/datasource"
 	"github.com/google/osv-scanner/pkg/lockfile"
 )
 
@@ -49,9 +50,31 @@ func Overwrite(rw LockfileIO, filename string, patches []DependencyPatch) error
 func GetLockfileIO(pathToLockfile string) (LockfileIO, error) {
 	base := filepath.Base(pathToLockfile)
 	switch {
-	case base == "package-lock.json":
+	case base == "package-lock.json" || base == "npm-shrinkwrap.json":
+		// npm-shrinkwrap.json has the same format as package-lock.json
 		return NpmLockfileIO{}, nil
+	case base == "yarn.lock":
+		return YarnBerryLockfileIO{}, nil
+	case base == "Cargo.lock":
+		return CargoLockfileIO{}, nil
+	case base == "packages.lock.json":
+		return NuGetLockfileIO{}, nil
 	default:
 		return nil, fmt.Errorf("unsupported lockfile type: %s", base)
 	}
 }
+
+// WithNpmRegistry sets the registry that an npm or Yarn LockfileIO fetches the metadata of patched versions from.
+// Other LockfileIOs are returned unchanged.
+func WithNpmRegistry(rw LockfileIO, cfg datasource.RegistryConfig) LockfileIO {
+	switch rw := rw.(type) {
+	case NpmLockfileIO:
+		rw.Registry = cfg
+		return rw
+	case YarnBerryLockfileIO:
+		rw.Registry = cfg
+		return rw
+	default:
+		return rw
+	}
+}
@@ -2,7 +2,6 @@ package lockfile
 
 import (
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io"
 	"path/filepath"
@@ -15,7 +14,17 @@ import (
 	"github.com/google/osv-scanner/pkg/lockfile"
 )
 
-type NpmLockfileIO struct{}
+type NpmLockfileIO struct {
+	// Registry is the npm registry the metadata of patched versions is fetched from when writing the lockfile.
+	Registry datasource.RegistryConfig
+}
+
+const (
+	// WorkspaceScope is the dep.Scope attribute value set on graph edges to local npm workspace packages.
+	WorkspaceScope = "workspace"
+	// BundleScope is the dep.Scope attribute value set on graph edges to packages bundled in their parent package.
+	BundleScope = "bundle"
+)
 
 type npmNodeModule struct {
 	NodeID       resolve.NodeID
@@ -24,6 +33,9 @@ type npmNodeModule struct {
 	Deps         map[string]string
 	OptionalDeps map[string]string
 	ActualName   string // set if the node is an alias, the real package name this refers to
+	IsWorkspace  bool   // set if the node is a local workspace package, rather than an installed package
+	IsBundled    bool   // set if the node is installed as part of its parent's bundledDependencies
+	IsPlatform   bool   // set if the package can only be installed on some platforms (with "os" or "cpu" fields)
 }
 
 func (n npmNodeModule) IsAliased() bool {
@@ -52,7 +64,7 @@ func (rw NpmLockfileIO) Read(file lockfile.DepFile) (*resolve.Graph, error) {
 		defer manifestFile.Close()
 		g, nodeModuleTree, err = rw.nodesFromDependencies(lockJSON, manifestFile)
 	default:
-		return nil, errors.New("no dependencies in package-lock.json")
+		return nil, fmt.Errorf("no dependencies in %s", filepath.Base(file.Path()))
 	}
 	if err != nil {
 		return nil, err
@@ -60,6 +72,9 @@ func (rw NpmLockfileIO) Read(file lockfile.DepFile) (*resolve.Graph, error) {
 
 	// Traverse the graph (somewhat inefficiently) to add edges between nodes
 	aliasNodes := make(map[resolve.NodeID]string)
+	workspaceNodes := make(map[resolve.NodeID]struct{})
+	bundledNodes := make(map[resolve.NodeID]struct{})
+	platformNodes := make(map[resolve.NodeID]struct{})
 	todo := []*npmNodeModule{nodeModuleTree}
 	seen := make(map[*npmNodeModule]struct{})
 	seen[nodeModuleTree] = struct{}{}
@@ -72,6 +87,15 @@ func (rw NpmLockfileIO) Read(file lockfile.DepFile) (*resolve.Graph, error) {
 			// Don't rename them now because we rely on the names for working out edges
 			aliasNodes[node.NodeID] = node.ActualName
 		}
+		if node.IsWorkspace {
+			workspaceNodes[node.NodeID] = struct{}{}
+		}
+		if node.IsBundled {
+			bundledNodes[node.NodeID] = struct{}{}
+		}
+		if node.IsPlatform {
+			platformNodes[node.NodeID] = struct{}{}
+		}
 
 		// Add the directory's children to the queue
 		for _, child := range node.Children {
@@ -95,18 +119,29 @@ func (rw NpmLockfileIO) Read(file lockfile.DepFile) (*resolve.Graph, error) {
 			if depNode == -1 {
 				continue
 			}
-			if err := g.AddEdge(node.NodeID, depNode, depVer, dep.Type{}); err != nil {
+			if err := g.AddEdge(node.NodeID, depNode, depVer, dep.NewType(dep.Opt)); err != nil {
 				return nil, err
 			}
 		}
 	}
 
 	// Add alias KnownAs attribute and rename them correctly
+	// Also mark the edges to workspaces and bundled packages, so they can be identified as local packages
 	for i, e := range g.Edges {
 		if _, ok := aliasNodes[e.To]; ok {
 			name := g.Nodes[e.To].Version.Name
 			g.Edges[i].Type.AddAttr(dep.KnownAs, name)
 		}
+		if _, ok := workspaceNodes[e.To]; ok {
+			g.Edges[i].Type.AddAttr(dep.Scope, WorkspaceScope)
+		}
+		if _, ok := bundledNodes[e.To]; ok {
+			g.Edges[i].Type.AddAttr(dep.Scope, BundleScope)
+		}
+		// Platform-specific packages are not installed on other platforms, even if they are regular dependencies
+		if _, ok := platformNodes[e.To]; ok && !e.Type.HasAttr(dep.Opt) {
+			g.Edges[i].Type.AddAttr(dep.Opt, "")
+		}
 	}
 	for i := range g.Nodes {
 		if name, ok := aliasNodes[resolve.NodeID(i)]; ok {
@@ -152,7 +187,7 @@ func (rw NpmLockfileIO) Write(original lockfile.DepFile, output io.Writer, patch
 		patchMap[p.Pkg.Name][p.OrigVersion] = p.NewVersion
 	}
 
-	api, err := datasource.NewNpmRegistryAPIClient(filepath.Dir(original.Path()))
+	api, err := datasource.NewNpmRegistryAPIClient(filepath.Dir(original.Path()), rw.Registry)
 	if err != nil {
 		return err
 	}
@@ -165,7 +200,7 @@ func (rw NpmLockfileIO) Write(original lockfile.DepFile, output io.Writer, patch
 		return err
 	}
 
-	// Write out modified package-lock.json
+	// Write out modified package-lock.json / npm-shrinkwrap.json
 	_, err = io.WriteString(output, lock)
 
 	return err
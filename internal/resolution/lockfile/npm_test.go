@@ -0,0 +1,250 @@
+package lockfile_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"deps.dev/util/resolve"
+	"deps.dev/util/resolve/dep"
+	"github.com/google/osv-scanner/internal/resolution/datasource"
+	lf "github.com/google/osv-scanner/internal/resolution/lockfile"
+	"github.com/google/osv-scanner/pkg/lockfile"
+	"github.com/tidwall/gjson"
+)
+
+func TestGetLockfileIO_Npm(t *testing.T) {
+	t.Parallel()
+
+	for _, path := range []string{"package-lock.json", "npm-shrinkwrap.json"} {
+		rw, err := lf.GetLockfileIO(path)
+		if err != nil {
+			t.Errorf("GetLockfileIO(%q) error = %v", path, err)
+			continue
+		}
+		if _, ok := rw.(lf.NpmLockfileIO); !ok {
+			t.Errorf("GetLockfileIO(%q) = %T, want lockfile.NpmLockfileIO", path, rw)
+		}
+	}
+}
+
+func TestNpmLockfileIO_ReadShrinkwrap(t *testing.T) {
+	t.Parallel()
+
+	f, err := lockfile.OpenLocalDepFile("fixtures/npm-shrinkwrap/npm-shrinkwrap.json")
+	if err != nil {
+		t.Fatalf("failed to open fixture: %v", err)
+	}
+	defer f.Close()
+
+	g, err := lf.NpmLockfileIO{}.Read(f)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+
+	var got []string
+	for _, e := range g.Edges {
+		from, to := g.Nodes[e.From].Version, g.Nodes[e.To].Version
+		got = append(got, from.Name+"@"+from.Version+" -> "+to.Name+"@"+to.Version+" "+e.Requirement)
+	}
+	want := []string{
+		"my-app@1.0.0 -> foo@1.0.0 ^1.0.0",
+		"foo@1.0.0 -> bar@2.0.0 ^2.0.0",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Read() edges:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestNpmLockfileIO_ReadAlias(t *testing.T) {
+	t.Parallel()
+
+	f, err := lockfile.OpenLocalDepFile("fixtures/npm-alias/package-lock.json")
+	if err != nil {
+		t.Fatalf("failed to open fixture: %v", err)
+	}
+	defer f.Close()
+
+	g, err := lf.NpmLockfileIO{}.Read(f)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+
+	// aliased packages are nodes of the real package, and the edges to them have the alias and its range
+	var got []string
+	for _, e := range g.Edges {
+		from, to := g.Nodes[e.From].Version, g.Nodes[e.To].Version
+		edge := from.Name + "@" + from.Version + " -> " + to.Name + "@" + to.Version + " " + e.Requirement
+		if knownAs, ok := e.Type.GetAttr(dep.KnownAs); ok {
+			edge += " (as " + knownAs + ")"
+		}
+		got = append(got, edge)
+	}
+	slices.Sort(got)
+	want := []string{
+		"my-app@1.0.0 -> bar@2.0.0 ^2.0.0",
+		"my-app@1.0.0 -> foo@1.0.0 ^1.0.0 (as foo-alias)",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Read() edges:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestNpmLockfileIO_WriteShrinkwrap(t *testing.T) {
+	t.Parallel()
+
+	// The metadata of patched versions is fetched from the configured registry
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/foo/1.1.0" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`{
+			"name": "foo",
+			"version": "1.1.0",
+			"dependencies": {"bar": "^2.1.0"},
+			"dist": {"tarball": "https://mirror.example.com/foo/-/foo-1.1.0.tgz", "integrity": "sha512-foo110"}
+		}`))
+	}))
+	defer srv.Close()
+
+	f, err := lockfile.OpenLocalDepFile("fixtures/npm-shrinkwrap/npm-shrinkwrap.json")
+	if err != nil {
+		t.Fatalf("failed to open fixture: %v", err)
+	}
+	defer f.Close()
+
+	rw := lf.WithNpmRegistry(lf.NpmLockfileIO{}, datasource.RegistryConfig{URL: srv.URL})
+	patches := []lf.DependencyPatch{{
+		Pkg:         resolve.PackageKey{System: resolve.NPM, Name: "foo"},
+		OrigVersion: "1.0.0",
+		NewVersion:  "1.1.0",
+	}}
+	var sb strings.Builder
+	if err := rw.Write(f, &sb, patches); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	foo := gjson.Get(sb.String(), `packages.node_modules/foo`)
+	for path, want := range map[string]string{
+		"version":          "1.1.0",
+		"resolved":         "https://mirror.example.com/foo/-/foo-1.1.0.tgz",
+		"integrity":        "sha512-foo110",
+		"dependencies.bar": "^2.1.0",
+	} {
+		if got := foo.Get(path).String(); got != want {
+			t.Errorf("Write() node_modules/foo %s = %q, want %q", path, got, want)
+		}
+	}
+	if got := gjson.Get(sb.String(), `packages.node_modules/bar.version`).String(); got != "2.0.0" {
+		t.Errorf("Write() node_modules/bar version = %q, want unchanged 2.0.0", got)
+	}
+}
+
+func TestNpmLockfileIO_Bundled(t *testing.T) {
+	t.Parallel()
+
+	f, err := lockfile.OpenLocalDepFile("fixtures/npm-bundled/package-lock.json")
+	if err != nil {
+		t.Fatalf("failed to open fixture: %v", err)
+	}
+	defer f.Close()
+
+	g, err := lf.NpmLockfileIO{}.Read(f)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+
+	// only the copy of baz in parent is bundled
+	var got []string
+	for _, e := range g.Edges {
+		from, to := g.Nodes[e.From].Version, g.Nodes[e.To].Version
+		edge := from.Name + " -> " + to.Name
+		if s, ok := e.Type.GetAttr(dep.Scope); ok {
+			edge += " (" + s + ")"
+		}
+		got = append(got, edge)
+	}
+	slices.Sort(got)
+	want := []string{
+		"my-app -> baz",
+		"my-app -> parent",
+		"parent -> baz (" + lf.BundleScope + ")",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Read() edges:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/baz/1.0.1" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`{"name": "baz", "version": "1.0.1", "dist": {"tarball": "https://registry.npmjs.org/baz/-/baz-1.0.1.tgz", "integrity": "sha512-baz101"}}`))
+	}))
+	defer srv.Close()
+
+	f, err = lockfile.OpenLocalDepFile("fixtures/npm-bundled/package-lock.json")
+	if err != nil {
+		t.Fatalf("failed to open fixture: %v", err)
+	}
+	defer f.Close()
+
+	rw := lf.WithNpmRegistry(lf.NpmLockfileIO{}, datasource.RegistryConfig{URL: srv.URL})
+	patches := []lf.DependencyPatch{{
+		Pkg:         resolve.PackageKey{System: resolve.NPM, Name: "baz"},
+		OrigVersion: "1.0.0",
+		NewVersion:  "1.0.1",
+	}}
+	var sb strings.Builder
+	if err := rw.Write(f, &sb, patches); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	// the bundled copy is part of parent's published archive, so it is left unchanged
+	for path, want := range map[string]string{
+		`packages.node_modules/baz.version`:                     "1.0.1",
+		`packages.node_modules/parent/node_modules/baz.version`: "1.0.0",
+	} {
+		if got := gjson.Get(sb.String(), path).String(); got != want {
+			t.Errorf("Write() %s = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestNpmLockfileIO_ReadPlatform(t *testing.T) {
+	t.Parallel()
+
+	f, err := lockfile.OpenLocalDepFile("fixtures/npm-platform/package-lock.json")
+	if err != nil {
+		t.Fatalf("failed to open fixture: %v", err)
+	}
+	defer f.Close()
+
+	g, err := lf.NpmLockfileIO{}.Read(f)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+
+	// platform-specific packages are optional, as they are not installed on other platforms,
+	// and the binaries for other platforms are not in the lockfile
+	var got []string
+	for _, e := range g.Edges {
+		edge := g.Nodes[e.From].Version.Name + " -> " + g.Nodes[e.To].Version.Name
+		if e.Type.HasAttr(dep.Opt) {
+			edge += " (optional)"
+		}
+		got = append(got, edge)
+	}
+	slices.Sort(got)
+	want := []string{
+		"esbuild -> @esbuild/linux-x64 (optional)",
+		"my-app -> esbuild",
+		"my-app -> linux-only (optional)",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Read() edges:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
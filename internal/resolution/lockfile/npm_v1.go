@@ -80,6 +80,7 @@ func (rw NpmLockfileIO) computeDependenciesRecursive(g *resolve.Graph, parent *n
 			Children:     make(map[string]*npmNodeModule),
 			OptionalDeps: optDeps,
 			ActualName:   actualName,
+			IsBundled:    d.Bundled,
 		}
 		parent.Children[name] = nm
 		if d.Dependencies != nil {
@@ -110,6 +111,10 @@ func (rw NpmLockfileIO) modifyPackageLockDependenciesRecurse(lockJSON, path stri
 				return lockJSON, err
 			}
 		}
+		if data.Get("bundled").Bool() {
+			// bundled packages are part of their parent package, and cannot be changed independently
+			continue
+		}
 		isAlias := false
 		realPkg, version := manifest.SplitNPMAlias(data.Get("version").String())
 		if realPkg != "" {
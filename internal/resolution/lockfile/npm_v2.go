@@ -66,6 +66,7 @@ func (rw NpmLockfileIO) nodesFromPackages(lockJSON lockfile.NpmLockfile) (*resol
 			})
 			m := rw.makeNodeModuleDeps(pkg, true) // NB: including the dev dependencies
 			m.NodeID = nID
+			m.IsWorkspace = true
 			workspaceModules[path[0]] = m
 
 			continue
@@ -125,7 +126,12 @@ func (rw NpmLockfileIO) nodesFromPackages(lockJSON lockfile.NpmLockfile) (*resol
 		parent.Children[name] = rw.makeNodeModuleDeps(pkg, false)
 		parent.Children[name].NodeID = nID
 		parent.Children[name].Parent = parent
-		parent.Children[name].ActualName = pkg.Name
+		parent.Children[name].IsBundled = pkg.InBundle
+		parent.Children[name].IsPlatform = len(pkg.OS) > 0 || len(pkg.CPU) > 0
+		if pkg.Name != "" && pkg.Name != name {
+			// this is an alias, "name" is the real package name
+			parent.Children[name].ActualName = pkg.Name
+		}
 	}
 
 	return &g, nodeModuleTree, nil
@@ -177,6 +183,10 @@ func (rw NpmLockfileIO) modifyPackageLockPackages(lockJSON string, patches map[s
 		if len(parts) == 0 {
 			continue
 		}
+		if value.Get("inBundle").Bool() {
+			// bundled packages are part of their parent package, and cannot be changed independently
+			continue
+		}
 		pkg := parts[len(parts)-1]
 		if n := value.Get("name"); n.Exists() { // if this is an alias, use the real package as the name
 			pkg = n.String()
@@ -0,0 +1,97 @@
+package lockfile
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"deps.dev/util/resolve"
+	"deps.dev/util/resolve/dep"
+	"github.com/google/osv-scanner/internal/resolution/util"
+	"github.com/google/osv-scanner/pkg/lockfile"
+	"golang.org/x/exp/maps"
+)
+
+type NuGetLockfileIO struct{}
+
+func (rw NuGetLockfileIO) Read(file lockfile.DepFile) (*resolve.Graph, error) {
+	var lock lockfile.NuGetLockfile
+	if err := json.NewDecoder(file).Decode(&lock); err != nil {
+		return nil, fmt.Errorf("could not parse %s: %w", file.Path(), err)
+	}
+	if len(lock.Dependencies) == 0 {
+		return nil, errors.New("no dependencies in packages.lock.json")
+	}
+
+	// Each target framework has its own dependency graph.
+	// TODO: handle multi-targeting projects, only the first framework is currently used
+	frameworks := maps.Keys(lock.Dependencies)
+	slices.Sort(frameworks)
+	pkgs := lock.Dependencies[frameworks[0]]
+
+	// The lockfile does not name the project itself, so name the root after the project directory
+	g := &resolve.Graph{}
+	root := g.AddNode(resolve.VersionKey{
+		PackageKey: resolve.PackageKey{
+			System: util.NuGet,
+			Name:   filepath.Base(filepath.Dir(file.Path())),
+		},
+		VersionType: resolve.Concrete,
+	})
+
+	// NuGet package IDs are case-insensitive
+	nodes := make(map[string]resolve.NodeID)
+	names := maps.Keys(pkgs)
+	slices.Sort(names)
+	for _, name := range names {
+		p := pkgs[name]
+		nodes[strings.ToLower(name)] = g.AddNode(resolve.VersionKey{
+			PackageKey: resolve.PackageKey{
+				System: util.NuGet,
+				Name:   name,
+			},
+			Version:     p.Resolved,
+			VersionType: resolve.Concrete,
+		})
+	}
+
+	for _, name := range names {
+		p := pkgs[name]
+		from := nodes[strings.ToLower(name)]
+		typ := dep.NewType()
+		switch p.Type {
+		case "Direct":
+			if err := g.AddEdge(root, from, p.Requested, typ); err != nil {
+				return nil, err
+			}
+		case "Project":
+			typ.AddAttr(dep.Scope, WorkspaceScope)
+			if err := g.AddEdge(root, from, p.Resolved, typ); err != nil {
+				return nil, err
+			}
+		}
+		depNames := maps.Keys(p.Dependencies)
+		slices.Sort(depNames)
+		for _, d := range depNames {
+			to, ok := nodes[strings.ToLower(d)]
+			if !ok {
+				return nil, fmt.Errorf("could not find dependency %s of %s in packages.lock.json", d, name)
+			}
+			if err := g.AddEdge(from, to, p.Dependencies[d], dep.NewType()); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return g, nil
+}
+
+func (rw NuGetLockfileIO) Write(original lockfile.DepFile, output io.Writer, patches []DependencyPatch) error {
+	// packages.lock.json includes the content hash of each package, which cannot be computed without downloading it.
+	// The lockfile has to be regenerated with `dotnet restore --force-evaluate` after changing the project file instead.
+	return errors.New("modifying packages.lock.json is not supported, update the project file and run `dotnet restore --force-evaluate`")
+}
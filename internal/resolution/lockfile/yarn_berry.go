@@ -0,0 +1,319 @@
+package lockfile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"deps.dev/util/resolve"
+	"deps.dev/util/resolve/dep"
+	"github.com/google/osv-scanner/internal/cachedregexp"
+	"github.com/google/osv-scanner/internal/resolution/datasource"
+	"github.com/google/osv-scanner/pkg/lockfile"
+	"gopkg.in/yaml.v3"
+)
+
+// YarnBerryLockfileIO reads and writes the YAML yarn.lock files of Yarn Berry (v2+).
+// The lockfile fully describes the dependency tree, so Plug'n'Play projects (without node_modules) are also supported.
+// Yarn Classic (v1) lockfiles are not supported, other than reading their integrity hashes.
+type YarnBerryLockfileIO struct {
+	// Registry is the npm registry the dependencies of patched versions are fetched from when writing the lockfile.
+	Registry datasource.RegistryConfig
+}
+
+type yarnBerryPackage struct {
+	Version              string            `yaml:"version"`
+	Resolution           string            `yaml:"resolution"`
+	Dependencies         map[string]string `yaml:"dependencies"`
+	OptionalDependencies map[string]string `yaml:"optionalDependencies"`
+	DependenciesMeta     map[string]struct {
+		Optional bool `yaml:"optional"`
+	} `yaml:"dependenciesMeta"`
+	LinkType string `yaml:"linkType"`
+}
+
+// yarnBerryDescriptor splits a descriptor or locator e.g. "@scope/name@npm:^1.0.0" into the name and the range.
+func yarnBerryDescriptor(s string) (string, string) {
+	idx := strings.Index(s[min(1, len(s)):], "@") // skip the @ of a scope
+	if idx < 0 {
+		return s, ""
+	}
+	idx++
+
+	return s[:idx], s[idx+1:]
+}
+
+// yarnBerryRange converts a range as written in yarn.lock into the requirement used by the resolver.
+//   - "npm:^1.0.0" -> "^1.0.0"
+//   - "npm:other@^1.0.0" -> "^1.0.0" (an alias, which is marked with KnownAs on the edge)
+//   - "patch:name@npm%3A^1.0.0#./patches/name.patch" -> "^1.0.0" (the range of the package being patched)
+//
+// Other protocols (e.g. "workspace:", "git:", "file:", "portal:") are returned as-is.
+func yarnBerryRange(r string) string {
+	if rest, ok := strings.CutPrefix(r, "patch:"); ok {
+		rest, _, _ = strings.Cut(rest, "#")
+		if unescaped, err := url.PathUnescape(rest); err == nil {
+			rest = unescaped
+		}
+		_, inner := yarnBerryDescriptor(rest)
+
+		return yarnBerryRange(inner)
+	}
+	if rest, ok := strings.CutPrefix(r, "npm:"); ok {
+		if strings.Contains(rest[min(1, len(rest)):], "@") {
+			_, rest = yarnBerryDescriptor(rest)
+		}
+
+		return rest
+	}
+
+	return r
+}
+
+func (rw YarnBerryLockfileIO) Read(file lockfile.DepFile) (*resolve.Graph, error) {
+	var lock map[string]yarnBerryPackage
+	if err := yaml.NewDecoder(file).Decode(&lock); err != nil {
+		return nil, fmt.Errorf("could not parse %s (only Yarn Berry lockfiles are supported): %w", file.Path(), err)
+	}
+	if _, ok := lock["__metadata"]; !ok {
+		return nil, errors.New("yarn.lock is not a Yarn Berry lockfile, Yarn Classic lockfiles are not supported")
+	}
+	delete(lock, "__metadata")
+
+	// Sort the entries for deterministic node IDs, with the root workspace first
+	keys := make([]string, 0, len(lock))
+	for k := range lock {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	rootIdx := slices.IndexFunc(keys, func(k string) bool { return strings.HasSuffix(lock[k].Resolution, "@workspace:.") })
+	if rootIdx < 0 {
+		return nil, errors.New("could not find root workspace in yarn.lock")
+	}
+	keys[0], keys[rootIdx] = keys[rootIdx], keys[0]
+
+	g := &resolve.Graph{}
+	descriptors := make(map[string]resolve.NodeID) // every descriptor in the keys, to the node it resolved to
+	for _, k := range keys {
+		pkg := lock[k]
+		name, _ := yarnBerryDescriptor(pkg.Resolution)
+		id := g.AddNode(resolve.VersionKey{
+			PackageKey: resolve.PackageKey{
+				System: resolve.NPM,
+				Name:   name,
+			},
+			Version:     pkg.Version,
+			VersionType: resolve.Concrete,
+		})
+		for _, d := range strings.Split(k, ",") {
+			descriptors[strings.TrimSpace(d)] = id
+		}
+	}
+
+	for i, k := range keys {
+		pkg := lock[k]
+		deps := make(map[string]string, len(pkg.Dependencies)+len(pkg.OptionalDependencies))
+		for name, r := range pkg.Dependencies {
+			deps[name] = r
+		}
+		for name, r := range pkg.OptionalDependencies {
+			deps[name] = r
+		}
+		names := make([]string, 0, len(deps))
+		for name := range deps {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+
+		for _, name := range names {
+			r := deps[name]
+			to, ok := descriptors[name+"@"+r]
+			if !ok {
+				// ranges of npm packages are written without the "npm:" protocol
+				to, ok = descriptors[name+"@npm:"+r]
+			}
+			_, isOptional := pkg.OptionalDependencies[name]
+			isOptional = isOptional || pkg.DependenciesMeta[name].Optional
+			if !ok {
+				if isOptional {
+					// optional dependencies (e.g. for other platforms) might not be in the lockfile
+					continue
+				}
+
+				return nil, fmt.Errorf("could not find dependency %s@%s of %s in yarn.lock", name, r, k)
+			}
+
+			typ := dep.NewType()
+			if isOptional {
+				typ.AddAttr(dep.Opt, "")
+			}
+			if g.Nodes[to].Version.Name != name {
+				typ.AddAttr(dep.KnownAs, name)
+			}
+			if strings.HasPrefix(r, "workspace:") || strings.Contains(lock[keys[to]].Resolution, "@workspace:") {
+				typ.AddAttr(dep.Scope, WorkspaceScope)
+			}
+			if err := g.AddEdge(resolve.NodeID(i), to, yarnBerryRange(r), typ); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Every workspace is installed, including those that no other workspace depends on.
+	// Add those as dependencies of the root workspace, so that their dependencies are part of the graph.
+	required := make(map[resolve.NodeID]bool)
+	for _, e := range g.Edges {
+		required[e.To] = true
+	}
+	for i, k := range keys[1:] {
+		id := resolve.NodeID(i + 1)
+		_, r := yarnBerryDescriptor(lock[k].Resolution)
+		if !strings.HasPrefix(r, "workspace:") || required[id] {
+			continue
+		}
+		typ := dep.NewType()
+		typ.AddAttr(dep.Scope, WorkspaceScope)
+		if err := g.AddEdge(0, id, r, typ); err != nil {
+			return nil, err
+		}
+	}
+
+	return g, nil
+}
+
+func (rw YarnBerryLockfileIO) Write(original lockfile.DepFile, output io.Writer, patches []DependencyPatch) error {
+	var buf strings.Builder
+	if _, err := io.Copy(&buf, original); err != nil {
+		return err
+	}
+	text := buf.String()
+
+	var lock map[string]yarnBerryPackage
+	if err := yaml.Unmarshal([]byte(text), &lock); err != nil {
+		return fmt.Errorf("could not parse %s: %w", original.Path(), err)
+	}
+	descriptors := make(map[string]struct{})
+	for k := range lock {
+		for _, d := range strings.Split(k, ",") {
+			descriptors[strings.TrimSpace(d)] = struct{}{}
+		}
+	}
+
+	api, err := datasource.NewNpmRegistryAPIClient(filepath.Dir(original.Path()), rw.Registry)
+	if err != nil {
+		return err
+	}
+
+	// Each package is a top-level key followed by its indented fields, separated by blank lines
+	pkgRe := cachedregexp.MustCompile(`(?m)^\S[^\n]*:\n(?:[ \t]+[^\n]*\n?)*`)
+	for _, p := range patches {
+		deps, err := rw.patchedDependencies(api, descriptors, p)
+		if err != nil {
+			return err
+		}
+		found := false
+		origResolution := p.Pkg.Name + "@npm:" + p.OrigVersion
+		text = pkgRe.ReplaceAllStringFunc(text, func(block string) string {
+			resRe := cachedregexp.MustCompile(`(?m)^([ \t]+)(resolution: "?)` + regexp.QuoteMeta(origResolution) + `("?)$`)
+			m := resRe.FindStringSubmatch(block)
+			if m == nil {
+				return block
+			}
+			found = true
+			indent := m[1]
+			block = resRe.ReplaceAllString(block, "${1}${2}"+p.Pkg.Name+"@npm:"+p.NewVersion+"${3}")
+			verRe := cachedregexp.MustCompile(`(?m)^([ \t]+version: "?)` + regexp.QuoteMeta(p.OrigVersion) + `("?)$`)
+			block = verRe.ReplaceAllString(block, "${1}"+p.NewVersion+"${2}")
+			// The checksum is of the original version's archive, remove it so Yarn recomputes it
+			block = cachedregexp.MustCompile(`(?m)^[ \t]+checksum: .*\n?`).ReplaceAllString(block, "")
+
+			return rw.replaceDependencies(block, indent, deps)
+		})
+		if !found {
+			return fmt.Errorf("could not find %s in yarn.lock", origResolution)
+		}
+	}
+
+	_, err = io.WriteString(output, text)
+
+	return err
+}
+
+// patchedDependencies fetches the dependencies of the patched version from the registry, as they are written in yarn.lock.
+// Every required dependency must already be resolved in the lockfile, since resolving new packages is left to Yarn.
+func (rw YarnBerryLockfileIO) patchedDependencies(api *datasource.NpmRegistryAPIClient, descriptors map[string]struct{}, p DependencyPatch) (map[string]string, error) {
+	npmData, err := api.FullJSON(context.Background(), p.Pkg.Name, p.NewVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	// Yarn Berry lists optional dependencies with the regular dependencies
+	optional := npmData.Get("optionalDependencies").Map()
+	deps := make(map[string]string)
+	for _, field := range []string{"dependencies", "optionalDependencies"} {
+		for name, r := range npmData.Get(field).Map() {
+			rng := r.String()
+			if _, ok := descriptors[name+"@"+rng]; ok {
+				deps[name] = rng
+				continue
+			}
+			if _, ok := descriptors[name+"@npm:"+rng]; ok {
+				deps[name] = "npm:" + rng
+				continue
+			}
+			// optional dependencies (e.g. for other platforms) might not be in the lockfile, Yarn adds them on the next install
+			if _, ok := optional[name]; !ok {
+				return nil, fmt.Errorf("dependency %s@%s of %s@%s is not in yarn.lock, run yarn install to update the lockfile instead",
+					name, rng, p.Pkg.Name, p.NewVersion)
+			}
+		}
+	}
+
+	return deps, nil
+}
+
+// replaceDependencies replaces the dependencies field of a package's block, whose fields are indented with indent.
+func (rw YarnBerryLockfileIO) replaceDependencies(block, indent string, deps map[string]string) string {
+	var sb strings.Builder
+	if len(deps) > 0 {
+		names := make([]string, 0, len(deps))
+		for name := range deps {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		sb.WriteString(indent + "dependencies:\n")
+		for _, name := range names {
+			sb.WriteString(indent + indent + yarnBerryQuote(name) + ": " + yarnBerryQuote(deps[name]) + "\n")
+		}
+	}
+
+	depsRe := cachedregexp.MustCompile(`(?m)^` + regexp.QuoteMeta(indent) + `dependencies:\n(?:` + regexp.QuoteMeta(indent+indent) + `[^\n]*\n?)*`)
+	if depsRe.MatchString(block) {
+		return depsRe.ReplaceAllLiteralString(block, sb.String())
+	}
+	// Yarn writes the dependencies right after the resolution
+	resRe := cachedregexp.MustCompile(`(?m)^` + regexp.QuoteMeta(indent) + `resolution: [^\n]*(?:\n|$)`)
+	loc := resRe.FindStringIndex(block)
+	head := block[:loc[1]]
+	if !strings.HasSuffix(head, "\n") && sb.Len() > 0 {
+		head += "\n"
+	}
+
+	return head + sb.String() + block[loc[1]:]
+}
+
+// yarnBerryQuote quotes a key or value the way Yarn does in yarn.lock, if it would not be a plain YAML string.
+func yarnBerryQuote(s string) string {
+	if cachedregexp.MustCompile(`^[^-?:,\][{}#&*!|>'"%@` + "`" + ` \t\r\n](?:[ \t]*[^,\][{}:# \t\r\n])*$`).MatchString(s) {
+		return s
+	}
+
+	return strconv.Quote(s)
+}
@@ -0,0 +1,177 @@
+package lockfile_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"deps.dev/util/resolve"
+	"deps.dev/util/resolve/dep"
+	"github.com/google/osv-scanner/internal/resolution/datasource"
+	lf "github.com/google/osv-scanner/internal/resolution/lockfile"
+	"github.com/google/osv-scanner/pkg/lockfile"
+)
+
+func TestYarnBerryLockfileIO_Read(t *testing.T) {
+	t.Parallel()
+
+	f, err := lockfile.OpenLocalDepFile("fixtures/yarn-berry/yarn.lock")
+	if err != nil {
+		t.Fatalf("failed to open fixture: %v", err)
+	}
+	defer f.Close()
+
+	g, err := lf.YarnBerryLockfileIO{}.Read(f)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+
+	var got []string
+	for _, e := range g.Edges {
+		from, to := g.Nodes[e.From].Version, g.Nodes[e.To].Version
+		got = append(got, from.Name+"@"+from.Version+" -> "+to.Name+"@"+to.Version+" "+e.Requirement)
+	}
+	want := []string{
+		"my-app@0.0.0-use.local -> foo@1.0.0 ^1.0.0",
+		"foo@1.0.0 -> bar@2.1.0 ^2.0.0",
+		"foo@1.0.0 -> old-dep@1.0.0 ^1.0.0",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Read() edges:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestYarnBerryLockfileIO_ReadWorkspaces(t *testing.T) {
+	t.Parallel()
+
+	f, err := lockfile.OpenLocalDepFile("fixtures/yarn-berry-workspaces/yarn.lock")
+	if err != nil {
+		t.Fatalf("failed to open fixture: %v", err)
+	}
+	defer f.Close()
+
+	g, err := lf.YarnBerryLockfileIO{}.Read(f)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+
+	// ws-b is not a dependency of any workspace, so it is added as a dependency of the root
+	var got []string
+	for _, e := range g.Edges {
+		from, to := g.Nodes[e.From].Version, g.Nodes[e.To].Version
+		edge := from.Name + " -> " + to.Name + "@" + to.Version + " " + e.Requirement
+		if s, ok := e.Type.GetAttr(dep.Scope); ok {
+			edge += " (" + s + ")"
+		}
+		got = append(got, edge)
+	}
+	want := []string{
+		"ws-a -> foo@1.0.0 ^1.0.0",
+		"ws-b -> bar@1.0.0 ^1.0.0",
+		"ws-b -> ws-a@0.0.0-use.local workspace:^ (workspace)",
+		"my-app -> ws-b@0.0.0-use.local workspace:packages/b (workspace)",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Read() edges:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+// yarnBerryRegistry serves the metadata of foo@1.1.0, whose dependencies are given as JSON.
+func yarnBerryRegistry(t *testing.T, deps string) datasource.RegistryConfig {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/foo/1.1.0" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`{"name": "foo", "version": "1.1.0", ` + deps + `}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	return datasource.RegistryConfig{URL: srv.URL}
+}
+
+func TestYarnBerryLockfileIO_Write(t *testing.T) {
+	t.Parallel()
+
+	patches := []lf.DependencyPatch{{
+		Pkg:         resolve.PackageKey{System: resolve.NPM, Name: "foo"},
+		OrigVersion: "1.0.0",
+		NewVersion:  "1.1.0",
+	}}
+
+	tests := []struct {
+		name    string
+		deps    string
+		want    string // the expected foo block, or empty if writing should fail
+		wantErr bool
+	}{
+		{
+			name: "dependencies updated from the registry",
+			deps: `"dependencies": {"bar": "^2.1.0"}, "optionalDependencies": {"fsevents": "^2.3.0"}`,
+			want: `"foo@npm:^1.0.0":
+  version: 1.1.0
+  resolution: "foo@npm:1.1.0"
+  dependencies:
+    bar: "npm:^2.1.0"
+  languageName: node
+  linkType: hard
+`,
+		},
+		{
+			name: "all dependencies removed",
+			deps: `"dependencies": {}`,
+			want: `"foo@npm:^1.0.0":
+  version: 1.1.0
+  resolution: "foo@npm:1.1.0"
+  languageName: node
+  linkType: hard
+`,
+		},
+		{
+			name:    "new dependency not in the lockfile",
+			deps:    `"dependencies": {"bar": "^2.1.0", "new-dep": "^1.0.0"}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			f, err := lockfile.OpenLocalDepFile("fixtures/yarn-berry/yarn.lock")
+			if err != nil {
+				t.Fatalf("failed to open fixture: %v", err)
+			}
+			defer f.Close()
+
+			rw := lf.WithNpmRegistry(lf.YarnBerryLockfileIO{}, yarnBerryRegistry(t, tt.deps))
+			var sb strings.Builder
+			err = rw.Write(f, &sb, patches)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Write() succeeded, want error")
+				}
+
+				return
+			}
+			if err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+
+			orig, err := os.ReadFile("fixtures/yarn-berry/yarn.lock")
+			if err != nil {
+				t.Fatalf("failed to read fixture: %v", err)
+			}
+			start := strings.Index(string(orig), `"foo@npm:^1.0.0":`)
+			end := strings.Index(string(orig), `"my-app@workspace:.":`)
+			want := string(orig[:start]) + tt.want + "\n" + string(orig[end:])
+			if got := sb.String(); got != want {
+				t.Errorf("Write() got:\n%s\nwant:\n%s", got, want)
+			}
+		})
+	}
+}
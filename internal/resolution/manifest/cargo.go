@@ -0,0 +1,173 @@
+package manifest
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+
+	"deps.dev/util/resolve"
+	"deps.dev/util/resolve/dep"
+	"github.com/BurntSushi/toml"
+	"github.com/google/osv-scanner/internal/cachedregexp"
+	"github.com/google/osv-scanner/internal/resolution/util"
+	"github.com/google/osv-scanner/pkg/lockfile"
+)
+
+type CargoManifestIO struct{}
+
+type CargoTOML struct {
+	Package struct {
+		Name    string `toml:"name"`
+		Version string `toml:"version"`
+	} `toml:"package"`
+	// Dependencies are either a version requirement string, or a table e.g. { version = "1.0", features = ["derive"] }
+	Dependencies      map[string]any `toml:"dependencies"`
+	DevDependencies   map[string]any `toml:"dev-dependencies"`
+	BuildDependencies map[string]any `toml:"build-dependencies"`
+}
+
+// cargoDependency is the parts of a Cargo.toml dependency needed for resolution.
+type cargoDependency struct {
+	Key      string // the name the dependency is imported as
+	Package  string // the name of the crate, if renamed
+	Version  string
+	Optional bool
+	Local    bool // path or git dependencies, which are not from crates.io
+}
+
+func parseCargoDependency(key string, v any) cargoDependency {
+	d := cargoDependency{Key: key}
+	switch v := v.(type) {
+	case string:
+		d.Version = v
+	case map[string]any:
+		d.Version, _ = v["version"].(string)
+		d.Package, _ = v["package"].(string)
+		d.Optional, _ = v["optional"].(bool)
+		_, isPath := v["path"]
+		_, isGit := v["git"]
+		d.Local = isPath || isGit
+	}
+
+	return d
+}
+
+func (rw CargoManifestIO) Read(f lockfile.DepFile) (Manifest, error) {
+	var cargoTOML CargoTOML
+	if _, err := toml.NewDecoder(f).Decode(&cargoTOML); err != nil {
+		return Manifest{}, fmt.Errorf("could not parse %s: %w", f.Path(), err)
+	}
+
+	manif := newManifest()
+	manif.FilePath = f.Path()
+	manif.Root = resolve.Version{
+		VersionKey: resolve.VersionKey{
+			PackageKey: resolve.PackageKey{
+				Name:   cargoTOML.Package.Name,
+				System: util.Cargo,
+			},
+			Version:     cargoTOML.Package.Version,
+			VersionType: resolve.Concrete,
+		}}
+
+	// TODO: workspaces, target-specific dependencies & features
+	addDeps := func(deps map[string]any, group string) {
+		for key, v := range deps {
+			d := parseCargoDependency(key, v)
+			if d.Local {
+				continue
+			}
+			rv := rw.makeReqVer(d)
+			manif.Requirements = append(manif.Requirements, rv)
+			manif.Groups[rv.PackageKey] = append(manif.Groups[rv.PackageKey], group)
+		}
+	}
+	addDeps(cargoTOML.Dependencies, MainGroup)
+	addDeps(cargoTOML.DevDependencies, "dev")
+	addDeps(cargoTOML.BuildDependencies, "build")
+
+	slices.SortFunc(manif.Requirements, func(a, b resolve.RequirementVersion) int {
+		return a.VersionKey.Compare(b.VersionKey)
+	})
+
+	return manif, nil
+}
+
+func (CargoManifestIO) makeReqVer(d cargoDependency) resolve.RequirementVersion {
+	name := d.Key
+	// dev dependencies don't use dep.Dev, to force the resolver to resolve them (like npm's devDependencies)
+	typ := dep.NewType()
+	if d.Package != "" && d.Package != d.Key {
+		typ.AddAttr(dep.KnownAs, d.Key)
+		name = d.Package
+	}
+	if d.Optional {
+		typ.AddAttr(dep.Opt, "")
+	}
+
+	return resolve.RequirementVersion{
+		Type: typ,
+		VersionKey: resolve.VersionKey{
+			PackageKey: resolve.PackageKey{
+				Name:   name,
+				System: util.Cargo,
+			},
+			Version:     d.Version,
+			VersionType: resolve.Requirement,
+		},
+	}
+}
+
+func (rw CargoManifestIO) Write(r lockfile.DepFile, w io.Writer, patch ManifestPatch) error {
+	var buf strings.Builder
+	if _, err := io.Copy(&buf, r); err != nil {
+		return err
+	}
+	text := buf.String()
+
+	// Cargo's [patch] section only supports replacing the source of crates, not forcing their versions
+	if len(patch.Overrides) > 0 {
+		return fmt.Errorf("overrides are not supported for %s", r.Path())
+	}
+
+	for _, d := range patch.Deps {
+		key := d.Pkg.Name
+		if knownAs, ok := d.Type.GetAttr(dep.KnownAs); ok {
+			key = knownAs
+		}
+		var err error
+		text, err = rw.replaceRequirement(text, key, d.OrigRequire, d.NewRequire)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err := io.WriteString(w, text)
+
+	return err
+}
+
+// replaceRequirement changes the version requirement of a dependency in the Cargo.toml text, for any of the forms:
+//
+//	key = "1.0"
+//	key = { version = "1.0", features = [...] }
+//	[dependencies.key]
+//	version = "1.0"
+func (CargoManifestIO) replaceRequirement(text, key, origReq, newReq string) (string, error) {
+	k := regexp.QuoteMeta(key)
+	v := regexp.QuoteMeta(origReq)
+	patterns := []*regexp.Regexp{
+		cachedregexp.MustCompile(`(?m)^(\s*"?` + k + `"?\s*=\s*")` + v + `(")`),
+		cachedregexp.MustCompile(`(?m)^(\s*"?` + k + `"?\s*=\s*\{[^}\n]*\bversion\s*=\s*")` + v + `(")`),
+		cachedregexp.MustCompile(`(?m)(^\[(?:[a-z-]+\.)?dependencies\.` + k + `\]\s*\n(?:[^\[].*\n)*?\s*version\s*=\s*")` + v + `(")`),
+	}
+	for _, re := range patterns {
+		if re.MatchString(text) {
+			return re.ReplaceAllString(text, "${1}"+strings.ReplaceAll(newReq, "$", "$$")+"${2}"), nil
+		}
+	}
+
+	return "", fmt.Errorf("could not find dependency %s = %q in Cargo.toml", key, origReq)
+}
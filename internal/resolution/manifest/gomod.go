@@ -0,0 +1,117 @@
+package manifest
+
+import (
+	"fmt"
+	"io"
+	"slices"
+
+	"deps.dev/util/resolve"
+	"deps.dev/util/resolve/dep"
+	"github.com/google/osv-scanner/internal/resolution/util"
+	"github.com/google/osv-scanner/pkg/lockfile"
+	"golang.org/x/mod/modfile"
+)
+
+type GoModManifestIO struct{}
+
+func (GoModManifestIO) parse(f lockfile.DepFile) (*modfile.File, error) {
+	b, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+	mod, err := modfile.Parse(f.Path(), b, nil)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse %s: %w", f.Path(), err)
+	}
+
+	return mod, nil
+}
+
+func (rw GoModManifestIO) Read(f lockfile.DepFile) (Manifest, error) {
+	mod, err := rw.parse(f)
+	if err != nil {
+		return Manifest{}, err
+	}
+
+	manif := newManifest()
+	manif.FilePath = f.Path()
+	name := ""
+	if mod.Module != nil {
+		name = mod.Module.Mod.Path
+	}
+	manif.Root = resolve.Version{
+		VersionKey: resolve.VersionKey{
+			PackageKey: resolve.PackageKey{
+				Name:   name,
+				System: util.Go,
+			},
+			VersionType: resolve.Concrete,
+		}}
+
+	for _, r := range mod.Require {
+		rv := makeGoReqVer(r.Mod.Path, r.Mod.Version)
+		manif.Requirements = append(manif.Requirements, rv)
+		if r.Indirect {
+			manif.Groups[rv.PackageKey] = []string{"indirect"}
+		}
+	}
+	slices.SortFunc(manif.Requirements, func(a, b resolve.RequirementVersion) int {
+		return a.VersionKey.Compare(b.VersionKey)
+	})
+
+	// replace directives that only change the version of a module act like npm's overrides.
+	// TODO: replacements with a different module path or a local directory
+	for _, r := range mod.Replace {
+		if r.New.Path != r.Old.Path || r.New.Version == "" {
+			continue
+		}
+		manif.Overrides = append(manif.Overrides, makeGoReqVer(r.Old.Path, r.New.Version))
+	}
+	slices.SortFunc(manif.Overrides, func(a, b resolve.RequirementVersion) int {
+		return a.VersionKey.Compare(b.VersionKey)
+	})
+
+	return manif, nil
+}
+
+func makeGoReqVer(path, version string) resolve.RequirementVersion {
+	return resolve.RequirementVersion{
+		Type: dep.NewType(),
+		VersionKey: resolve.VersionKey{
+			PackageKey: resolve.PackageKey{
+				Name:   path,
+				System: util.Go,
+			},
+			Version:     version,
+			VersionType: resolve.Requirement,
+		},
+	}
+}
+
+func (rw GoModManifestIO) Write(r lockfile.DepFile, w io.Writer, patch ManifestPatch) error {
+	mod, err := rw.parse(r)
+	if err != nil {
+		return err
+	}
+
+	for _, d := range patch.Deps {
+		if err := mod.AddRequire(d.Pkg.Name, d.NewRequire); err != nil {
+			return err
+		}
+	}
+	for _, o := range patch.Overrides {
+		// Replace every version of the module, like an override.
+		if err := mod.AddReplace(o.Pkg.Name, "", o.Pkg.Name, o.NewRequire); err != nil {
+			return err
+		}
+	}
+	mod.Cleanup()
+
+	b, err := mod.Format()
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+
+	return err
+}
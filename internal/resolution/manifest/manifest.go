@@ -8,9 +8,11 @@ import (
 	"os"
 	"path/filepath"
 	"slices"
+	"strings"
 
 	"deps.dev/util/resolve"
 	"deps.dev/util/resolve/dep"
+	"github.com/google/osv-scanner/internal/resolution/util"
 	"github.com/google/osv-scanner/pkg/lockfile"
 )
 
@@ -20,6 +22,7 @@ type Manifest struct {
 	Requirements      []resolve.RequirementVersion    // All direct requirements, including dev
 	Groups            map[resolve.PackageKey][]string // Dependency groups that the imports belong to
 	LocalManifests    []Manifest                      // manifests of local packages
+	Overrides         []resolve.RequirementVersion    // Versions forced on all (including indirect) dependencies e.g. npm "overrides"
 	EcosystemSpecific any                             // Any ecosystem-specific information needed
 }
 
@@ -34,14 +37,65 @@ func (m Manifest) System() resolve.System {
 }
 
 func (m Manifest) Clone() Manifest {
-	return Manifest{
+	clone := Manifest{
 		FilePath:          m.FilePath,
 		Root:              m.Root,
 		Requirements:      slices.Clone(m.Requirements),
 		Groups:            maps.Clone(m.Groups),
-		LocalManifests:    slices.Clone(m.LocalManifests),
+		LocalManifests:    make([]Manifest, len(m.LocalManifests)),
+		Overrides:         slices.Clone(m.Overrides),
 		EcosystemSpecific: m.EcosystemSpecific, // TODO: Deep copy this?
 	}
+	// local manifests can be modified during remediation, so they need to be deep copied
+	for i, loc := range m.LocalManifests {
+		clone.LocalManifests[i] = loc.Clone()
+	}
+
+	return clone
+}
+
+// MainGroup is recorded in Manifest.Groups for requirements of the package itself, in ecosystems where
+// a requirement can also be in other groups, so a requirement that is also e.g. a dev dependency is not mistaken for dev-only.
+// No ecosystem has a group with an empty name.
+const MainGroup = ""
+
+// IsDevGroup reports whether all of the groups of a requirement, as recorded in Manifest.Groups,
+// mean the requirement is only needed for development and not when the package is used.
+//   - npm: "dev" (devDependencies)
+//   - Maven: the "test" scope (other scopes, e.g. "provided", are still needed at runtime)
+//   - PyPI: PEP 735 dependency groups, which are never part of the package's metadata, and an extra named "dev"
+//   - Cargo: "dev" (dev-dependencies)
+//   - NuGet: "dev" (PrivateAssets="all")
+func IsDevGroup(sys resolve.System, groups []string) bool {
+	if len(groups) == 0 {
+		return false
+	}
+	for _, g := range groups {
+		if !isDevGroup(sys, g) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isDevGroup(sys resolve.System, g string) bool {
+	switch sys { //nolint:exhaustive
+	case resolve.NPM, util.Cargo, util.NuGet:
+		return g == "dev"
+	case resolve.Maven:
+		return g == "test"
+	case util.PyPI:
+		return g == "dev" || strings.HasPrefix(g, pythonDependencyGroupPrefix)
+	default:
+		return false
+	}
+}
+
+// LocalManifestIndex returns the index into LocalManifests of the manifest for the package,
+// or -1 if the package is not a local package (e.g. an npm workspace).
+func (m Manifest) LocalManifestIndex(pk resolve.PackageKey) int {
+	return slices.IndexFunc(m.LocalManifests, func(loc Manifest) bool { return loc.Root.PackageKey == pk })
 }
 
 type DependencyPatch struct {
@@ -51,11 +105,13 @@ type DependencyPatch struct {
 	NewRequire   string             // The new requirement string e.g. "2.*.*"
 	OrigResolved string             // The version the original resolves to e.g. "1.2.3" (for display only)
 	NewResolved  string             // The version the new resolves to e.g. "2.4.6" (for display only)
+	ManifestFile string             // Path to the local manifest this applies to e.g. an npm workspace, empty if it's the root manifest
 }
 
 type ManifestPatch struct {
-	Manifest *Manifest         // The original manifest
-	Deps     []DependencyPatch // changed direct dependencies
+	Manifest  *Manifest         // The original manifest
+	Deps      []DependencyPatch // changed direct dependencies
+	Overrides []DependencyPatch // added or changed overrides, forcing versions of (indirect) dependencies
 }
 
 type ManifestIO interface {
@@ -66,9 +122,51 @@ type ManifestIO interface {
 	Write(original lockfile.DepFile, output io.Writer, patches ManifestPatch) error
 }
 
-// Overwrite applies the ManifestPatch to the manifest at filename.
-// Used so as to not have the same file open for reading and writing at the same time.
+// Overwrite applies the ManifestPatch to the manifest at filename,
+// as well as to any of its local manifests that the patch changes.
 func Overwrite(rw ManifestIO, filename string, p ManifestPatch) error {
+	// Group the patches by the manifest file they apply to
+	var files []string
+	fileDeps := make(map[string][]DependencyPatch)
+	for _, d := range p.Deps {
+		f := d.ManifestFile
+		if f == "" {
+			f = filename
+		}
+		if _, ok := fileDeps[f]; !ok {
+			files = append(files, f)
+		}
+		fileDeps[f] = append(fileDeps[f], d)
+	}
+
+	// Overrides are always written to the root manifest
+	if len(p.Overrides) > 0 {
+		if _, ok := fileDeps[filename]; !ok {
+			files = append(files, filename)
+		}
+	}
+
+	for _, f := range files {
+		patch := ManifestPatch{Manifest: p.Manifest, Deps: fileDeps[f]}
+		if f == filename {
+			patch.Overrides = p.Overrides
+		}
+		if p.Manifest != nil {
+			if idx := slices.IndexFunc(p.Manifest.LocalManifests, func(m Manifest) bool { return m.FilePath == f }); idx >= 0 {
+				patch.Manifest = &p.Manifest.LocalManifests[idx]
+			}
+		}
+		if err := overwriteFile(rw, f, patch); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// overwriteFile applies the ManifestPatch to the single manifest file at filename.
+// Used so as to not have the same file open for reading and writing at the same time.
+func overwriteFile(rw ManifestIO, filename string, p ManifestPatch) error {
 	r, err := lockfile.OpenLocalDepFile(filename)
 	if err != nil {
 		return err
@@ -94,6 +192,16 @@ func GetManifestIO(pathToManifest string) (ManifestIO, error) {
 	switch {
 	case base == "package.json":
 		return NpmManifestIO{}, nil
+	case base == "pom.xml":
+		return MavenManifestIO{}, nil
+	case base == "requirements.txt" || base == "pyproject.toml":
+		return PythonManifestIO{}, nil
+	case base == "go.mod":
+		return GoModManifestIO{}, nil
+	case base == "Cargo.toml":
+		return CargoManifestIO{}, nil
+	case IsNuGetProjectFile(base):
+		return NuGetManifestIO{}, nil
 	default:
 		return nil, fmt.Errorf("unsupported manifest type: %s", base)
 	}
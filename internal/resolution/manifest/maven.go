@@ -0,0 +1,462 @@
+package manifest
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"deps.dev/util/resolve"
+	"deps.dev/util/resolve/dep"
+	"github.com/google/osv-scanner/internal/cachedregexp"
+	"github.com/google/osv-scanner/pkg/lockfile"
+)
+
+type MavenManifestIO struct{}
+
+type mavenDependency struct {
+	GroupID    string `xml:"groupId"`
+	ArtifactID string `xml:"artifactId"`
+	Version    string `xml:"version"`
+	Type       string `xml:"type"`
+	Scope      string `xml:"scope"`
+	Optional   string `xml:"optional"`
+}
+
+func (d mavenDependency) name() string {
+	return d.GroupID + ":" + d.ArtifactID
+}
+
+type mavenProperties map[string]string
+
+func (p *mavenProperties) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	*p = make(mavenProperties)
+	for {
+		t, err := d.Token()
+		if err != nil {
+			return err
+		}
+
+		switch tt := t.(type) {
+		case xml.StartElement:
+			var s string
+			if err := d.DecodeElement(&s, &tt); err != nil {
+				return err
+			}
+			(*p)[tt.Name.Local] = strings.TrimSpace(s)
+		case xml.EndElement:
+			if tt.Name == start.Name {
+				return nil
+			}
+		}
+	}
+}
+
+type mavenParent struct {
+	GroupID    string `xml:"groupId"`
+	ArtifactID string `xml:"artifactId"`
+	Version    string `xml:"version"`
+	// RelativePath is nil if it is not set, in which case it defaults to "../pom.xml"
+	RelativePath *string `xml:"relativePath"`
+}
+
+// PomXML is the subset of a Maven pom.xml needed for dependency resolution.
+type PomXML struct {
+	GroupID              string            `xml:"groupId"`
+	ArtifactID           string            `xml:"artifactId"`
+	Version              string            `xml:"version"`
+	Parent               mavenParent       `xml:"parent"`
+	Properties           mavenProperties   `xml:"properties"`
+	Dependencies         []mavenDependency `xml:"dependencies>dependency"`
+	DependencyManagement []mavenDependency `xml:"dependencyManagement>dependencies>dependency"`
+}
+
+// Interpolate replaces ${property} references in s with the values of the properties.
+// Unknown properties are left unchanged.
+func (pom PomXML) Interpolate(s string) string {
+	re := cachedregexp.MustCompile(`\$\{([^}]+)\}`)
+
+	return re.ReplaceAllStringFunc(s, func(match string) string {
+		name := match[2 : len(match)-1]
+		switch name {
+		case "project.version", "pom.version", "version":
+			return pom.version()
+		case "project.groupId", "pom.groupId", "groupId":
+			return pom.groupID()
+		case "project.artifactId", "pom.artifactId", "artifactId":
+			return pom.ArtifactID
+		case "project.parent.version", "parent.version":
+			return pom.Parent.Version
+		}
+		if v, ok := pom.Properties[name]; ok {
+			return v
+		}
+
+		return match
+	})
+}
+
+// MergeParent inherits the properties, dependencies and dependencyManagement of the parent pom,
+// with the values set in this pom taking precedence.
+func (pom *PomXML) MergeParent(parent PomXML) {
+	if pom.Properties == nil {
+		pom.Properties = make(mavenProperties)
+	}
+	for k, v := range parent.Properties {
+		if _, ok := pom.Properties[k]; !ok {
+			pom.Properties[k] = parent.Interpolate(v)
+		}
+	}
+	declared := make(map[string]struct{})
+	for _, d := range pom.Dependencies {
+		declared[pom.Interpolate(d.name())] = struct{}{}
+	}
+	for _, d := range parent.Dependencies {
+		d.GroupID = parent.Interpolate(d.GroupID)
+		d.ArtifactID = parent.Interpolate(d.ArtifactID)
+		d.Version = parent.Interpolate(d.Version)
+		if _, ok := declared[d.name()]; ok {
+			continue
+		}
+		pom.Dependencies = append(pom.Dependencies, d)
+	}
+	for _, d := range parent.DependencyManagement {
+		d.GroupID = parent.Interpolate(d.GroupID)
+		d.ArtifactID = parent.Interpolate(d.ArtifactID)
+		d.Version = parent.Interpolate(d.Version)
+		pom.DependencyManagement = append(pom.DependencyManagement, d)
+	}
+}
+
+// MergeImport adds the dependencyManagement of an imported BOM after that of this pom,
+// so the versions managed by this pom and its parents take precedence.
+// Unlike parents, the properties of the BOM are not inherited.
+func (pom *PomXML) MergeImport(bom PomXML) {
+	for _, d := range bom.DependencyManagement {
+		d.GroupID = bom.Interpolate(d.GroupID)
+		d.ArtifactID = bom.Interpolate(d.ArtifactID)
+		d.Version = bom.Interpolate(d.Version)
+		pom.DependencyManagement = append(pom.DependencyManagement, d)
+	}
+}
+
+// maxParentDepth is how many levels of parent poms are merged into a pom.
+const maxParentDepth = 5
+
+// maxImportDepth is how many levels of BOMs importing other BOMs are merged into a pom.
+const maxImportDepth = 3
+
+// POMFetcher fetches the contents of the pom file of the artifact version,
+// where name is "groupId:artifactId".
+type POMFetcher func(ctx context.Context, name, version string) ([]byte, error)
+
+func parsePOM(body []byte) (PomXML, error) {
+	var pom PomXML
+	err := xml.NewDecoder(bytes.NewReader(body)).Decode(&pom)
+
+	return pom, err
+}
+
+// MergeEffective merges the parent poms and imported BOMs into the pom, making it the effective pom
+// that Maven uses to build the project.
+// If the pom was read from the file at path, parents are read from their relativePath when it has
+// the expected parent, the same as Maven does. All other poms are fetched with fetch.
+func (pom *PomXML) MergeEffective(ctx context.Context, path string, fetch POMFetcher) error {
+	return pom.mergeEffective(ctx, path, fetch, 0)
+}
+
+func (pom *PomXML) mergeEffective(ctx context.Context, path string, fetch POMFetcher, importDepth int) error {
+	parent := pom.Parent
+	for i := 0; i < maxParentDepth && parent.GroupID != "" && parent.ArtifactID != ""; i++ {
+		parentPom, parentPath, err := localParent(path, parent)
+		if err != nil {
+			return err
+		}
+		if parentPath == "" {
+			body, err := fetch(ctx, parent.GroupID+":"+parent.ArtifactID, parent.Version)
+			if err != nil {
+				return err
+			}
+			if parentPom, err = parsePOM(body); err != nil {
+				return fmt.Errorf("could not parse pom of %s:%s:%s: %w", parent.GroupID, parent.ArtifactID, parent.Version, err)
+			}
+		}
+		pom.MergeParent(parentPom)
+		parent = parentPom.Parent
+		path = parentPath
+	}
+
+	// BOMs are imported with <scope>import</scope> in the dependencyManagement,
+	// which is replaced by the dependencyManagement of the BOM
+	var managed, imports []mavenDependency
+	for _, d := range pom.DependencyManagement {
+		if strings.TrimSpace(d.Scope) == "import" && strings.TrimSpace(d.Type) == "pom" {
+			imports = append(imports, d)
+		} else {
+			managed = append(managed, d)
+		}
+	}
+	if len(imports) == 0 {
+		return nil
+	}
+	pom.DependencyManagement = managed
+	if importDepth >= maxImportDepth {
+		return nil
+	}
+
+	for _, d := range imports {
+		name := pom.Interpolate(d.name())
+		version := pom.Interpolate(d.Version)
+		body, err := fetch(ctx, name, version)
+		if err != nil {
+			return err
+		}
+		bom, err := parsePOM(body)
+		if err != nil {
+			return fmt.Errorf("could not parse pom of %s:%s: %w", name, version, err)
+		}
+		if err := bom.mergeEffective(ctx, "", fetch, importDepth+1); err != nil {
+			return err
+		}
+		pom.MergeImport(bom)
+	}
+
+	return nil
+}
+
+// localParent reads the parent pom from the relativePath of the parent of the pom at path,
+// returning the path of the parent, or an empty path if it is not there.
+func localParent(path string, parent mavenParent) (PomXML, string, error) {
+	if path == "" {
+		return PomXML{}, "", nil
+	}
+	relativePath := "../pom.xml"
+	if parent.RelativePath != nil {
+		relativePath = strings.TrimSpace(*parent.RelativePath)
+	}
+	if relativePath == "" {
+		return PomXML{}, "", nil
+	}
+
+	parentPath := filepath.Join(filepath.Dir(path), filepath.FromSlash(relativePath))
+	if info, err := os.Stat(parentPath); err == nil && info.IsDir() {
+		parentPath = filepath.Join(parentPath, "pom.xml")
+	}
+	body, err := os.ReadFile(parentPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return PomXML{}, "", nil
+	}
+	if err != nil {
+		return PomXML{}, "", err
+	}
+	pom, err := parsePOM(body)
+	if err != nil {
+		return PomXML{}, "", fmt.Errorf("could not parse %s: %w", parentPath, err)
+	}
+	// the pom at the relativePath is only used if it is actually the parent
+	if pom.groupID() != parent.GroupID || pom.ArtifactID != parent.ArtifactID {
+		return PomXML{}, "", nil
+	}
+
+	return pom, parentPath, nil
+}
+
+// ManagedVersion returns the version of the package set in the dependencyManagement, or empty if it is not managed.
+func (pom PomXML) ManagedVersion(groupID, artifactID string) string {
+	for _, d := range pom.DependencyManagement {
+		if pom.Interpolate(d.GroupID) == groupID && pom.Interpolate(d.ArtifactID) == artifactID {
+			return pom.Interpolate(d.Version)
+		}
+	}
+
+	return ""
+}
+
+// groupID is the groupId of the project, which can be inherited from the parent
+func (pom PomXML) groupID() string {
+	if pom.GroupID != "" {
+		return pom.GroupID
+	}
+
+	return pom.Parent.GroupID
+}
+
+// version is the version of the project, which can be inherited from the parent
+func (pom PomXML) version() string {
+	if pom.Version != "" {
+		return pom.Version
+	}
+
+	return pom.Parent.Version
+}
+
+func (rw MavenManifestIO) Read(f lockfile.DepFile) (Manifest, error) {
+	var pom PomXML
+	if err := xml.NewDecoder(f).Decode(&pom); err != nil {
+		return Manifest{}, fmt.Errorf("could not parse %s: %w", f.Path(), err)
+	}
+
+	manif := newManifest()
+	manif.FilePath = f.Path()
+	manif.Root = resolve.Version{
+		VersionKey: resolve.VersionKey{
+			PackageKey: resolve.PackageKey{
+				Name:   pom.groupID() + ":" + pom.ArtifactID,
+				System: resolve.Maven,
+			},
+			Version:     pom.Interpolate(pom.version()),
+			VersionType: resolve.Concrete,
+		}}
+
+	// Dependencies without a version take theirs from the dependencyManagement section
+	// TODO: inherit dependencyManagement & properties from parent poms and imported BOMs
+	managed := make(map[string]string)
+	for _, d := range pom.DependencyManagement {
+		managed[pom.Interpolate(d.name())] = pom.Interpolate(d.Version)
+	}
+
+	// dependencyManagement also sets the versions of indirect dependencies
+	// direct dependencies already have their managed version as their requirement
+	direct := make(map[string]struct{})
+	for _, d := range pom.Dependencies {
+		direct[pom.Interpolate(d.name())] = struct{}{}
+	}
+	for name, ver := range managed {
+		if _, ok := direct[name]; ok || ver == "" {
+			continue
+		}
+		manif.Overrides = append(manif.Overrides, resolve.RequirementVersion{
+			Type: dep.NewType(),
+			VersionKey: resolve.VersionKey{
+				PackageKey: resolve.PackageKey{
+					Name:   name,
+					System: resolve.Maven,
+				},
+				Version:     ver,
+				VersionType: resolve.Requirement,
+			},
+		})
+	}
+	slices.SortFunc(manif.Overrides, func(a, b resolve.RequirementVersion) int {
+		return a.VersionKey.Compare(b.VersionKey)
+	})
+
+	for _, d := range pom.Dependencies {
+		name := pom.Interpolate(d.name())
+		ver := pom.Interpolate(d.Version)
+		if ver == "" {
+			ver = managed[name]
+		}
+		typ := dep.NewType()
+		if strings.TrimSpace(d.Optional) == "true" {
+			typ = dep.NewType(dep.Opt)
+		}
+		scope := strings.TrimSpace(d.Scope)
+		if scope != "" && scope != "compile" {
+			typ.AddAttr(dep.Scope, scope)
+		}
+		rv := resolve.RequirementVersion{
+			Type: typ,
+			VersionKey: resolve.VersionKey{
+				PackageKey: resolve.PackageKey{
+					Name:   name,
+					System: resolve.Maven,
+				},
+				Version:     ver,
+				VersionType: resolve.Requirement,
+			},
+		}
+		manif.Requirements = append(manif.Requirements, rv)
+		if scope != "" {
+			manif.Groups[rv.PackageKey] = []string{scope}
+		}
+	}
+
+	slices.SortFunc(manif.Requirements, func(a, b resolve.RequirementVersion) int {
+		return a.VersionKey.Compare(b.VersionKey)
+	})
+
+	return manif, nil
+}
+
+func (rw MavenManifestIO) Write(r lockfile.DepFile, w io.Writer, patch ManifestPatch) error {
+	var buf strings.Builder
+	if _, err := io.Copy(&buf, r); err != nil {
+		return err
+	}
+	pomText := buf.String()
+
+	// Overrides are written to dependencyManagement, which uses the same format as the dependencies
+	// TODO: add new dependencyManagement entries for overrides that are not already present
+	for _, changedDep := range append(slices.Clone(patch.Deps), patch.Overrides...) {
+		groupID, artifactID, ok := strings.Cut(changedDep.Pkg.Name, ":")
+		if !ok {
+			return fmt.Errorf("invalid Maven package name: %s", changedDep.Pkg.Name)
+		}
+		var err error
+		pomText, err = rw.replaceVersion(pomText, groupID, artifactID, changedDep.OrigRequire, changedDep.NewRequire)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err := io.WriteString(w, pomText)
+
+	return err
+}
+
+// replaceVersion changes the version of a dependency in the pom.xml text, keeping the rest of the file unchanged.
+// If the version is set by a property, the property is changed instead.
+func (MavenManifestIO) replaceVersion(pomText, groupID, artifactID, origVer, newVer string) (string, error) {
+	depRe := cachedregexp.MustCompile(`(?s)<dependency>.*?</dependency>`)
+	verRe := cachedregexp.MustCompile(`(?s)<version>\s*(.*?)\s*</version>`)
+	for _, loc := range depRe.FindAllStringIndex(pomText, -1) {
+		block := pomText[loc[0]:loc[1]]
+		if !strings.Contains(block, "<groupId>"+groupID+"</groupId>") || !strings.Contains(block, "<artifactId>"+artifactID+"</artifactId>") {
+			continue
+		}
+		verLoc := verRe.FindStringSubmatchIndex(block)
+		if verLoc == nil {
+			// version is set in dependencyManagement, which is another <dependency> block
+			continue
+		}
+		ver := block[verLoc[2]:verLoc[3]]
+		if prop, ok := strings.CutPrefix(ver, "${"); ok {
+			prop = strings.TrimSuffix(prop, "}")
+			return replaceProperty(pomText, prop, newVer)
+		}
+		if ver != origVer {
+			continue
+		}
+
+		return pomText[:loc[0]+verLoc[2]] + newVer + pomText[loc[0]+verLoc[3]:], nil
+	}
+
+	return "", fmt.Errorf("could not find dependency %s:%s@%s in pom.xml", groupID, artifactID, origVer)
+}
+
+func replaceProperty(pomText, prop, newVer string) (string, error) {
+	propRe := cachedregexp.MustCompile(`(?s)<properties>.*?</properties>`)
+	loc := propRe.FindStringIndex(pomText)
+	if loc == nil {
+		return "", fmt.Errorf("could not find property %s in pom.xml", prop)
+	}
+	props := pomText[loc[0]:loc[1]]
+	openTag, closeTag := "<"+prop+">", "</"+prop+">"
+	start := strings.Index(props, openTag)
+	end := strings.Index(props, closeTag)
+	if start < 0 || end < start {
+		return "", fmt.Errorf("could not find property %s in pom.xml", prop)
+	}
+	start += loc[0] + len(openTag)
+	end += loc[0]
+
+	return pomText[:start] + newVer + pomText[end:], nil
+}
@@ -0,0 +1,136 @@
+package manifest_test
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/google/osv-scanner/internal/resolution/manifest"
+)
+
+// remotePOMs are the poms in the fake Maven repository, keyed by "groupId:artifactId@version"
+var remotePOMs = map[string]string{
+	"com.example:corporate-parent@3": `<project>
+  <groupId>com.example</groupId>
+  <artifactId>corporate-parent</artifactId>
+  <version>3</version>
+  <properties>
+    <commons-lang3.version>3.12.0</commons-lang3.version>
+    <jackson.version>2.15.2</jackson.version>
+  </properties>
+</project>`,
+	"com.fasterxml.jackson:jackson-bom@2.15.2": `<project>
+  <groupId>com.fasterxml.jackson</groupId>
+  <artifactId>jackson-bom</artifactId>
+  <version>2.15.2</version>
+  <dependencyManagement>
+    <dependencies>
+      <dependency>
+        <groupId>com.fasterxml.jackson.core</groupId>
+        <artifactId>jackson-annotations</artifactId>
+        <version>${project.version}</version>
+      </dependency>
+      <dependency>
+        <groupId>com.fasterxml.jackson.core</groupId>
+        <artifactId>jackson-databind</artifactId>
+        <version>${project.version}</version>
+      </dependency>
+    </dependencies>
+  </dependencyManagement>
+</project>`,
+}
+
+var errNotFound = errors.New("404 Not Found")
+
+func fetchRemotePOM(_ context.Context, name, version string) ([]byte, error) {
+	pom, ok := remotePOMs[name+"@"+version]
+	if !ok {
+		return nil, errNotFound
+	}
+
+	return []byte(pom), nil
+}
+
+func readPOM(t *testing.T, path string) manifest.PomXML {
+	t.Helper()
+
+	body, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+	var pom manifest.PomXML
+	if err := xml.Unmarshal(body, &pom); err != nil {
+		t.Fatalf("failed to parse fixture: %v", err)
+	}
+
+	return pom
+}
+
+func TestPomXML_MergeEffective(t *testing.T) {
+	t.Parallel()
+
+	const path = "fixtures/maven-effective/app/pom.xml"
+	pom := readPOM(t, path)
+	if err := pom.MergeEffective(context.Background(), path, fetchRemotePOM); err != nil {
+		t.Fatalf("MergeEffective() error: %v", err)
+	}
+
+	managed := []struct {
+		groupID, artifactID string
+		want                string
+	}{
+		// managed by the local parent pom
+		{"com.google.guava", "guava", "32.1.2-jre"},
+		// managed by the BOM imported by the local parent pom
+		{"com.fasterxml.jackson.core", "jackson-databind", "2.15.2"},
+		// managed by the pom itself, which takes precedence over the BOM
+		{"com.fasterxml.jackson.core", "jackson-annotations", "2.15.0"},
+		// the BOM is not a managed dependency itself
+		{"com.fasterxml.jackson", "jackson-bom", ""},
+	}
+	for _, m := range managed {
+		if got := pom.ManagedVersion(m.groupID, m.artifactID); got != m.want {
+			t.Errorf("ManagedVersion(%s:%s) = %q, want %q", m.groupID, m.artifactID, got, m.want)
+		}
+	}
+
+	// properties are inherited from the remote parent of the local parent pom
+	if got, want := pom.Interpolate("${commons-lang3.version}"), "3.12.0"; got != want {
+		t.Errorf("Interpolate(${commons-lang3.version}) = %q, want %q", got, want)
+	}
+
+	// dependencies are inherited from the local parent pom
+	var names []string
+	for _, d := range pom.Dependencies {
+		names = append(names, d.GroupID+":"+d.ArtifactID)
+	}
+	want := []string{
+		"com.google.guava:guava",
+		"com.fasterxml.jackson.core:jackson-databind",
+		"com.fasterxml.jackson.core:jackson-annotations",
+		"org.apache.commons:commons-lang3",
+		"junit:junit",
+	}
+	if len(names) != len(want) {
+		t.Fatalf("MergeEffective() dependencies = %v, want %v", names, want)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Errorf("MergeEffective() dependencies = %v, want %v", names, want)
+			break
+		}
+	}
+}
+
+func TestPomXML_MergeEffective_FetchError(t *testing.T) {
+	t.Parallel()
+
+	// without a local path, the local parent pom can't be found, and it is not in the repository
+	pom := readPOM(t, "fixtures/maven-effective/app/pom.xml")
+	err := pom.MergeEffective(context.Background(), "", fetchRemotePOM)
+	if !errors.Is(err, errNotFound) {
+		t.Errorf("MergeEffective() error = %v, want %v", err, errNotFound)
+	}
+}
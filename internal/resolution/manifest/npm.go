@@ -2,8 +2,10 @@ package manifest
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"path/filepath"
 	"slices"
 	"strings"
@@ -13,18 +15,22 @@ import (
 	"github.com/google/osv-scanner/pkg/lockfile"
 	"github.com/tidwall/gjson"
 	"github.com/tidwall/sjson"
+	"gopkg.in/yaml.v3"
 )
 
 type NpmManifestIO struct{}
 
 type PackageJSON struct {
-	Name    string `json:"name"`
-	Version string `json:"version"`
-	// TODO: yarn allows workspaces to be a object OR a list:
-	// https://classic.yarnpkg.com/blog/2018/02/15/nohoist/
-	Workspaces      []string          `json:"workspaces"`
+	Name            string            `json:"name"`
+	Version         string            `json:"version"`
+	Workspaces      NpmWorkspaces     `json:"workspaces"`
 	Dependencies    map[string]string `json:"dependencies"`
 	DevDependencies map[string]string `json:"devDependencies"`
+	// npm "overrides" can be nested objects, only the versions forced on the top-level packages are currently used
+	Overrides   map[string]json.RawMessage `json:"overrides"`
+	Resolutions map[string]string          `json:"resolutions"` // yarn's equivalent of "overrides"
+	// Legacy package.json files can have "engines" as an array, so it is decoded leniently by NpmEngines
+	Engines json.RawMessage `json:"engines"`
 
 	// These fields are currently only used when parsing package-lock.json
 	OptionalDependencies map[string]string `json:"optionalDependencies"`
@@ -32,6 +38,55 @@ type PackageJSON struct {
 	// BundleDependencies   []string          `json:"bundleDependencies"`
 }
 
+// NpmWorkspaces are the glob patterns of the workspace directories in a package.json.
+// yarn also allows them to be an object, with the patterns in "packages":
+// https://classic.yarnpkg.com/blog/2018/02/15/nohoist/
+type NpmWorkspaces []string
+
+func (w *NpmWorkspaces) UnmarshalJSON(data []byte) error {
+	var patterns []string
+	if err := json.Unmarshal(data, &patterns); err == nil {
+		*w = patterns
+		return nil
+	}
+
+	var yarnWorkspaces struct {
+		Packages []string `json:"packages"`
+	}
+	if err := json.Unmarshal(data, &yarnWorkspaces); err != nil {
+		return err
+	}
+	*w = yarnWorkspaces.Packages
+
+	return nil
+}
+
+// pnpmWorkspaceFile is the file that pnpm reads the workspaces of the package.json next to it from.
+const pnpmWorkspaceFile = "pnpm-workspace.yaml"
+
+// NpmWorkspacePatterns returns the glob patterns of the workspace directories of a package.json,
+// which pnpm reads from a pnpm-workspace.yaml next to it instead.
+// Patterns starting with "!" exclude the directories they match.
+func NpmWorkspacePatterns(f lockfile.DepFile, packagejson PackageJSON) ([]string, error) {
+	pnpmFile, err := f.Open(pnpmWorkspaceFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		return packagejson.Workspaces, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer pnpmFile.Close()
+
+	var pnpmWorkspace struct {
+		Packages []string `yaml:"packages"`
+	}
+	if err := yaml.NewDecoder(pnpmFile).Decode(&pnpmWorkspace); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("could not parse %s: %w", pnpmFile.Path(), err)
+	}
+
+	return append(slices.Clone(packagejson.Workspaces), pnpmWorkspace.Packages...), nil
+}
+
 func (rw NpmManifestIO) Read(f lockfile.DepFile) (Manifest, error) {
 	dec := json.NewDecoder(f)
 	var packagejson PackageJSON
@@ -53,17 +108,29 @@ func (rw NpmManifestIO) Read(f lockfile.DepFile) (Manifest, error) {
 		}}
 
 	// Find all package.json files in the workspaces & parse those too.
-	var workspaces []string
-	for _, pattern := range packagejson.Workspaces {
-		match, err := filepath.Glob(filepath.Join(filepath.Dir(f.Path()), pattern, "package.json"))
+	patterns, err := NpmWorkspacePatterns(f, packagejson)
+	if err != nil {
+		return Manifest{}, err
+	}
+	dir := filepath.Dir(f.Path())
+	var workspaces, excluded []string
+	for _, pattern := range patterns {
+		pattern, exclude := strings.CutPrefix(pattern, "!")
+		match, err := filepath.Glob(filepath.Join(dir, pattern, "package.json"))
 		if err != nil {
 			return Manifest{}, err
 		}
-		workspaces = append(workspaces, match...)
+		if exclude {
+			excluded = append(excluded, match...)
+		} else {
+			workspaces = append(workspaces, match...)
+		}
 	}
+	workspaces = slices.DeleteFunc(workspaces, func(path string) bool { return slices.Contains(excluded, path) })
 
 	// workspaces seem to be evaluated in sorted path order
 	slices.Sort(workspaces)
+	workspaces = slices.Compact(workspaces)
 	workspaceNames := make(map[string]struct{})
 	for _, path := range workspaces {
 		wsFile, err := f.Open(path)
@@ -121,6 +188,8 @@ func (rw NpmManifestIO) Read(f lockfile.DepFile) (Manifest, error) {
 		return a.VersionKey.Compare(b.VersionKey)
 	})
 
+	manif.Overrides = rw.makeOverrides(packagejson)
+
 	// resolve workspaces after regular requirements
 	for _, m := range manif.LocalManifests {
 		imp, ok := workspaceReqVers[m.Root.PackageKey]
@@ -140,9 +209,68 @@ func (rw NpmManifestIO) Read(f lockfile.DepFile) (Manifest, error) {
 	return manif, nil
 }
 
+// makeOverrides collects the versions forced by the npm "overrides" and yarn "resolutions" fields.
+func (rw NpmManifestIO) makeOverrides(packagejson PackageJSON) []resolve.RequirementVersion {
+	overrides := make(map[string]string)
+	for name, raw := range packagejson.Overrides {
+		var ver string
+		if err := json.Unmarshal(raw, &ver); err != nil {
+			// Nested overrides e.g. {"foo": {".": "1.0.0", "bar": "2.0.0"}} can set the version of the package itself with "."
+			// TODO: the overrides of the package's dependencies e.g. "bar"
+			var nested map[string]json.RawMessage
+			if err := json.Unmarshal(raw, &nested); err != nil {
+				continue
+			}
+			if err := json.Unmarshal(nested["."], &ver); err != nil {
+				continue
+			}
+		}
+		if ref, ok := strings.CutPrefix(ver, "$"); ok {
+			// "$foo" refers to the version of the direct dependency "foo"
+			if v, ok := packagejson.Dependencies[ref]; ok {
+				ver = v
+			} else if v, ok := packagejson.DevDependencies[ref]; ok {
+				ver = v
+			} else {
+				continue
+			}
+		}
+		overrides[name] = ver
+	}
+	for name, ver := range packagejson.Resolutions {
+		name = strings.TrimPrefix(name, "**/")
+		// TODO: resolutions for specific dependency paths e.g. "foo/bar" or "foo/**/bar"
+		maxSlashes := 0
+		if strings.HasPrefix(name, "@") { // scoped packages e.g. "@foo/bar"
+			maxSlashes = 1
+		}
+		if strings.Count(name, "/") > maxSlashes {
+			continue
+		}
+		overrides[name] = ver
+	}
+
+	reqs := make([]resolve.RequirementVersion, 0, len(overrides))
+	for name, ver := range overrides {
+		reqs = append(reqs, rw.makeNPMReqVer(name, ver))
+	}
+	slices.SortFunc(reqs, func(a, b resolve.RequirementVersion) int {
+		return a.VersionKey.Compare(b.VersionKey)
+	})
+
+	return reqs
+}
+
 func (rw NpmManifestIO) makeNPMReqVer(pkg, ver string) resolve.RequirementVersion {
 	// TODO: URLs, Git, GitHub, `file:`
 	typ := dep.NewType() // don't use dep.NewType(dep.Dev) for devDeps to force the resolver to resolve them
+	if r, ok := strings.CutPrefix(ver, "workspace:"); ok {
+		// yarn and pnpm's workspace protocol refers to another workspace, which is resolved to its local manifest
+		ver = r
+		if ver == "^" || ver == "~" {
+			ver = "*"
+		}
+	}
 	realPkg, realVer := SplitNPMAlias(ver)
 	if realPkg != "" {
 		// This dependency is aliased, add it as a
@@ -232,12 +360,87 @@ func (NpmManifestIO) Write(r lockfile.DepFile, w io.Writer, patch ManifestPatch)
 		}
 	}
 
+	if len(patch.Overrides) > 0 {
+		if manif, err = writeNpmOverrides(r, manif, patch.Overrides); err != nil {
+			return err
+		}
+	}
+
 	// Write out modified package.json
 	_, err = io.WriteString(w, manif)
 
 	return err
 }
 
+// writeNpmOverrides adds or updates the forced versions in the package.json.
+// Projects using yarn have the versions written to "resolutions", otherwise npm's "overrides" is used.
+func writeNpmOverrides(r lockfile.DepFile, manif string, overrides []DependencyPatch) (string, error) {
+	field := "overrides"
+	if f, err := r.Open("yarn.lock"); err == nil {
+		f.Close()
+		field = "resolutions"
+	} else if gjson.Get(manif, "resolutions").Exists() && !gjson.Get(manif, "overrides").Exists() {
+		field = "resolutions"
+	}
+
+	for _, o := range overrides {
+		path := field + "." + escapeJSONPath(o.Pkg.Name)
+		// yarn resolutions may already be using the "**/" glob for the package
+		if field == "resolutions" {
+			if glob := field + "." + escapeJSONPath("**/"+o.Pkg.Name); gjson.Get(manif, glob).Exists() {
+				path = glob
+			}
+		}
+		// Nested npm overrides set the version of the package itself with ".", keep the overrides of its dependencies
+		if gjson.Get(manif, path).IsObject() {
+			path += "." + escapeJSONPath(".")
+		}
+		var err error
+		manif, err = sjson.Set(manif, path, o.NewRequire)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return manif, nil
+}
+
+func escapeJSONPath(key string) string {
+	// escape the gjson/sjson path special characters that may appear in package names
+	key = strings.ReplaceAll(key, ".", "\\.")
+	key = strings.ReplaceAll(key, "*", "\\*")
+
+	return strings.ReplaceAll(key, "?", "\\?")
+}
+
+// NpmEngines reads the runtime engine requirements (e.g. {"node": ">=18"}) of the package.json at path.
+func NpmEngines(path string) (map[string]string, error) {
+	f, err := lockfile.OpenLocalDepFile(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var packagejson PackageJSON
+	if err := json.NewDecoder(f).Decode(&packagejson); err != nil {
+		return nil, err
+	}
+
+	// npm ignores "engines" that are not an object e.g. the legacy ["node >= 0.6"], and any non-string values
+	var raw map[string]any
+	if err := json.Unmarshal(packagejson.Engines, &raw); err != nil {
+		return nil, nil //nolint:nilerr
+	}
+	engines := make(map[string]string, len(raw))
+	for engine, req := range raw {
+		if s, ok := req.(string); ok {
+			engines[engine] = s
+		}
+	}
+
+	return engines, nil
+}
+
 // extract the real package name & version from an alias-specified version
 // e.g. "npm:pkg@^1.2.3" -> name: "pkg", version: "^1.2.3"
 // name is empty and version is unchanged if not an alias specifier
@@ -252,3 +455,16 @@ func SplitNPMAlias(v string) (name, version string) {
 
 	return "", v // not an alias
 }
+
+// IsNPMNonRegistrySpec checks if a dependency specifier refers to a source other than the npm registry
+// e.g. a git repository ("github:user/repo", "git+https://..."), a local path ("file:../pkg", "link:../pkg"), or a tarball URL
+func IsNPMNonRegistrySpec(v string) bool {
+	for _, prefix := range []string{"git:", "git+", "github:", "gitlab:", "bitbucket:", "gist:", "file:", "link:", "http:", "https:", "./", "../", "/", "~/"} {
+		if strings.HasPrefix(v, prefix) {
+			return true
+		}
+	}
+
+	// "user/repo" is shorthand for a GitHub repository, but scoped package names don't show up in specifiers
+	return strings.Contains(v, "/") && !strings.HasPrefix(v, "@")
+}
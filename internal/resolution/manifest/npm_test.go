@@ -0,0 +1,237 @@
+package manifest_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"deps.dev/util/resolve"
+	"deps.dev/util/resolve/dep"
+	"github.com/google/osv-scanner/internal/resolution/manifest"
+	"github.com/google/osv-scanner/pkg/lockfile"
+)
+
+func npmReqVer(name, version string) resolve.RequirementVersion {
+	return resolve.RequirementVersion{
+		Type: dep.NewType(),
+		VersionKey: resolve.VersionKey{
+			PackageKey: resolve.PackageKey{
+				System: resolve.NPM,
+				Name:   name,
+			},
+			Version:     version,
+			VersionType: resolve.Requirement,
+		},
+	}
+}
+
+func TestNpmManifestIO_ReadOverrides(t *testing.T) {
+	t.Parallel()
+
+	f, err := lockfile.OpenLocalDepFile("fixtures/npm-overrides/package.json")
+	if err != nil {
+		t.Fatalf("failed to open fixture: %v", err)
+	}
+	defer f.Close()
+
+	m, err := manifest.NpmManifestIO{}.Read(f)
+	if err != nil {
+		t.Fatalf("failed to read manifest: %v", err)
+	}
+
+	// "bar" only overrides its own dependencies, so does not force a version of itself
+	want := []resolve.RequirementVersion{
+		npmReqVer("baz", "1.0.0"),
+		npmReqVer("foo", "1.2.0"),
+	}
+	if !reflect.DeepEqual(m.Overrides, want) {
+		t.Errorf("Read() overrides = %v, want %v", m.Overrides, want)
+	}
+}
+
+func TestNpmManifestIO_ReadWorkspaces(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		dir  string
+	}{
+		{
+			name: "yarn workspaces object",
+			dir:  "fixtures/yarn-workspaces",
+		},
+		{
+			// packages/excluded is excluded by a "!" pattern
+			name: "pnpm-workspace.yaml",
+			dir:  "fixtures/pnpm-workspaces",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			f, err := lockfile.OpenLocalDepFile(filepath.Join(tt.dir, "package.json"))
+			if err != nil {
+				t.Fatalf("failed to open fixture: %v", err)
+			}
+			defer f.Close()
+
+			m, err := manifest.NpmManifestIO{}.Read(f)
+			if err != nil {
+				t.Fatalf("failed to read manifest: %v", err)
+			}
+
+			dir := filepath.Dir(f.Path())
+			wantFiles := []string{
+				filepath.Join(dir, "packages", "a", "package.json"),
+				filepath.Join(dir, "packages", "b", "package.json"),
+			}
+			var gotFiles []string
+			for _, loc := range m.LocalManifests {
+				gotFiles = append(gotFiles, loc.FilePath)
+			}
+			if !reflect.DeepEqual(gotFiles, wantFiles) {
+				t.Fatalf("Read() local manifests = %v, want %v", gotFiles, wantFiles)
+			}
+
+			// the workspaces are required by the root, and "workspace:^" requires the local version of ws-a
+			wantRoot := []resolve.RequirementVersion{npmReqVer("ws-a", "*"), npmReqVer("ws-b", "*")}
+			if !reflect.DeepEqual(m.Requirements, wantRoot) {
+				t.Errorf("Read() requirements = %v, want %v", m.Requirements, wantRoot)
+			}
+			wantB := []resolve.RequirementVersion{npmReqVer("bar", "^1.0.0"), npmReqVer("ws-a", "*")}
+			if got := m.LocalManifests[1].Requirements; !reflect.DeepEqual(got, wantB) {
+				t.Errorf("Read() requirements of ws-b = %v, want %v", got, wantB)
+			}
+		})
+	}
+}
+
+func TestNpmManifestIO_WriteOverrides(t *testing.T) {
+	t.Parallel()
+
+	f, err := lockfile.OpenLocalDepFile("fixtures/npm-overrides/package.json")
+	if err != nil {
+		t.Fatalf("failed to open fixture: %v", err)
+	}
+	defer f.Close()
+
+	override := func(name, origRequire, newRequire string) manifest.DependencyPatch {
+		return manifest.DependencyPatch{
+			Pkg:         resolve.PackageKey{System: resolve.NPM, Name: name},
+			Type:        dep.NewType(),
+			OrigRequire: origRequire,
+			NewRequire:  newRequire,
+		}
+	}
+	patch := manifest.ManifestPatch{
+		Overrides: []manifest.DependencyPatch{
+			override("baz", "1.0.0", "1.0.1"),
+			override("foo", "1.2.0", "1.2.1"),
+			override("bar", "", "2.1.0"),
+			override("new-pkg", "", "5.0.0"),
+		},
+	}
+
+	var buf strings.Builder
+	if err := (manifest.NpmManifestIO{}).Write(f, &buf, patch); err != nil {
+		t.Fatalf("failed to write manifest: %v", err)
+	}
+
+	// The nested overrides of "foo" and "bar" must be kept when their own versions are forced
+	want, err := os.ReadFile("fixtures/npm-overrides/package.patched.json")
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+	var got, wantJSON any
+	if err := json.Unmarshal([]byte(buf.String()), &got); err != nil {
+		t.Fatalf("written manifest is not valid JSON: %v\n%s", err, buf.String())
+	}
+	if err := json.Unmarshal(want, &wantJSON); err != nil {
+		t.Fatalf("failed to parse fixture: %v", err)
+	}
+	if !reflect.DeepEqual(got, wantJSON) {
+		t.Errorf("Write() got:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestNpmEngines(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		path    string
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name: "object",
+			path: "fixtures/npm-engines/object.json",
+			want: map[string]string{"node": ">=18", "npm": "^9.0.0"},
+		},
+		{
+			// npm ignores the legacy array form of "engines"
+			name: "legacy array",
+			path: "fixtures/npm-engines/legacy-array.json",
+			want: nil,
+		},
+		{
+			name: "non-string values",
+			path: "fixtures/npm-engines/non-string.json",
+			want: map[string]string{"node": ">=16"},
+		},
+		{
+			name: "no engines",
+			path: "fixtures/npm-engines/no-engines.json",
+			want: nil,
+		},
+		{
+			name:    "not json",
+			path:    "fixtures/npm-engines/not-json.json",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := manifest.NpmEngines(tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NpmEngines() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NpmEngines() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsNPMNonRegistrySpec(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		spec string
+		want bool
+	}{
+		{spec: "^1.2.3", want: false},
+		{spec: "latest", want: false},
+		{spec: "npm:foo@^1.0.0", want: false},
+		{spec: "github:user/repo", want: true},
+		{spec: "git+https://github.com/user/repo.git#v1.0.0", want: true},
+		{spec: "user/repo", want: true},
+		{spec: "file:../pkg", want: true},
+		{spec: "link:../pkg", want: true},
+		{spec: "./vendor/pkg", want: true},
+		{spec: "https://example.com/pkg.tgz", want: true},
+	}
+	for _, tt := range tests {
+		if got := manifest.IsNPMNonRegistrySpec(tt.spec); got != tt.want {
+			t.Errorf("IsNPMNonRegistrySpec(%q) = %v, want %v", tt.spec, got, tt.want)
+		}
+	}
+}
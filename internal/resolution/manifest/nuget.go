@@ -0,0 +1,150 @@
+package manifest
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"deps.dev/util/resolve"
+	"deps.dev/util/resolve/dep"
+	"github.com/google/osv-scanner/internal/cachedregexp"
+	"github.com/google/osv-scanner/internal/resolution/util"
+	"github.com/google/osv-scanner/pkg/lockfile"
+)
+
+// NuGetManifestIO reads and writes the PackageReferences of SDK-style .NET project files (e.g. .csproj).
+type NuGetManifestIO struct{}
+
+type nugetPackageReference struct {
+	Include         string `xml:"Include,attr"`
+	Update          string `xml:"Update,attr"`
+	VersionAttr     string `xml:"Version,attr"`
+	VersionElem     string `xml:"Version"`
+	PrivateAssets   string `xml:"PrivateAssets,attr"`
+	PrivateAssetsEl string `xml:"PrivateAssets"`
+}
+
+func (r nugetPackageReference) version() string {
+	if r.VersionAttr != "" {
+		return r.VersionAttr
+	}
+
+	return strings.TrimSpace(r.VersionElem)
+}
+
+type NuGetProject struct {
+	ItemGroups []struct {
+		PackageReferences []nugetPackageReference `xml:"PackageReference"`
+	} `xml:"ItemGroup"`
+}
+
+// IsNuGetProjectFile checks if the file is a .NET project file that can have PackageReferences.
+func IsNuGetProjectFile(path string) bool {
+	switch filepath.Ext(path) {
+	case ".csproj", ".fsproj", ".vbproj":
+		return true
+	default:
+		return false
+	}
+}
+
+func (rw NuGetManifestIO) Read(f lockfile.DepFile) (Manifest, error) {
+	var proj NuGetProject
+	if err := xml.NewDecoder(f).Decode(&proj); err != nil {
+		return Manifest{}, fmt.Errorf("could not parse %s: %w", f.Path(), err)
+	}
+
+	manif := newManifest()
+	manif.FilePath = f.Path()
+	manif.Root = resolve.Version{
+		VersionKey: resolve.VersionKey{
+			PackageKey: resolve.PackageKey{
+				Name:   strings.TrimSuffix(filepath.Base(f.Path()), filepath.Ext(f.Path())),
+				System: util.NuGet,
+			},
+			VersionType: resolve.Concrete,
+		}}
+
+	// TODO: central package management (Directory.Packages.props) & MSBuild properties in versions
+	for _, ig := range proj.ItemGroups {
+		for _, ref := range ig.PackageReferences {
+			if ref.Include == "" {
+				// <PackageReference Update="..."> changes an existing reference, usually from an imported file
+				continue
+			}
+			rv := resolve.RequirementVersion{
+				Type: dep.NewType(),
+				VersionKey: resolve.VersionKey{
+					PackageKey: resolve.PackageKey{
+						Name:   ref.Include,
+						System: util.NuGet,
+					},
+					Version:     ref.version(),
+					VersionType: resolve.Requirement,
+				},
+			}
+			manif.Requirements = append(manif.Requirements, rv)
+			// PrivateAssets="all" is used for development-only dependencies like analyzers
+			if strings.EqualFold(ref.PrivateAssets, "all") || strings.EqualFold(strings.TrimSpace(ref.PrivateAssetsEl), "all") {
+				manif.Groups[rv.PackageKey] = []string{"dev"}
+			}
+		}
+	}
+
+	slices.SortFunc(manif.Requirements, func(a, b resolve.RequirementVersion) int {
+		return a.VersionKey.Compare(b.VersionKey)
+	})
+
+	return manif, nil
+}
+
+func (rw NuGetManifestIO) Write(r lockfile.DepFile, w io.Writer, patch ManifestPatch) error {
+	var buf strings.Builder
+	if _, err := io.Copy(&buf, r); err != nil {
+		return err
+	}
+	text := buf.String()
+
+	// Transitive dependencies can only be overridden by adding direct PackageReferences to them.
+	// TODO: add new PackageReferences for overrides
+	if len(patch.Overrides) > 0 {
+		return fmt.Errorf("overrides are not supported for %s", filepath.Base(r.Path()))
+	}
+
+	for _, d := range patch.Deps {
+		var err error
+		text, err = rw.replaceVersion(text, d.Pkg.Name, d.OrigRequire, d.NewRequire)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err := io.WriteString(w, text)
+
+	return err
+}
+
+// replaceVersion changes the version of a PackageReference, in either the Version attribute or a <Version> child element.
+func (NuGetManifestIO) replaceVersion(text, name, origVer, newVer string) (string, error) {
+	n := regexp.QuoteMeta(name)
+	v := regexp.QuoteMeta(origVer)
+	patterns := []*regexp.Regexp{
+		// <PackageReference Include="name" Version="1.0.0" />
+		cachedregexp.MustCompile(`(?i)(<PackageReference\s[^>]*\bInclude\s*=\s*"` + n + `"[^>]*\bVersion\s*=\s*")` + v + `(")`),
+		// <PackageReference Version="1.0.0" Include="name" />
+		cachedregexp.MustCompile(`(?i)(<PackageReference\s[^>]*\bVersion\s*=\s*")` + v + `("[^>]*\bInclude\s*=\s*"` + n + `")`),
+		// <PackageReference Include="name"><Version>1.0.0</Version></PackageReference>
+		cachedregexp.MustCompile(`(?is)(<PackageReference\s[^>]*\bInclude\s*=\s*"` + n + `"[^>]*>(?:[^<]|<[^/V])*?<Version>\s*)` + v + `(\s*</Version>)`),
+	}
+	for _, re := range patterns {
+		if re.MatchString(text) {
+			return re.ReplaceAllString(text, "${1}"+strings.ReplaceAll(newVer, "$", "$$")+"${2}"), nil
+		}
+	}
+
+	return "", fmt.Errorf("could not find PackageReference %s %s", name, origVer)
+}
@@ -0,0 +1,283 @@
+package manifest
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"deps.dev/util/resolve"
+	"deps.dev/util/resolve/dep"
+	"github.com/BurntSushi/toml"
+	"github.com/google/osv-scanner/internal/cachedregexp"
+	"github.com/google/osv-scanner/internal/resolution/util"
+	"github.com/google/osv-scanner/pkg/lockfile"
+)
+
+// PythonManifestIO reads and writes the direct requirements of Python projects,
+// from either a pip requirements.txt file or the PEP 621 [project] table of a pyproject.toml file.
+type PythonManifestIO struct{}
+
+type PyProjectTOML struct {
+	Project struct {
+		Name                 string              `toml:"name"`
+		Version              string              `toml:"version"`
+		Dependencies         []string            `toml:"dependencies"`
+		OptionalDependencies map[string][]string `toml:"optional-dependencies"`
+	} `toml:"project"`
+	// PEP 735 dependency groups, whose entries are either requirements or {include-group = "name"} tables
+	DependencyGroups map[string][]any `toml:"dependency-groups"`
+}
+
+// pythonDependencyGroupPrefix is prepended to the names of PEP 735 dependency groups in Manifest.Groups,
+// to distinguish them from extras.
+const pythonDependencyGroupPrefix = "dependency-group:"
+
+// pythonRequirement is a PEP 508 dependency specifier e.g. "requests[security] >= 2.8.1, == 2.8.* ; python_version < '2.7'"
+type pythonRequirement struct {
+	Name      string // the name as written in the file
+	Specifier string // the PEP 440 version specifier e.g. ">=2.8.1,==2.8.*", empty if any version is allowed
+	Marker    string // the environment marker, if any
+}
+
+// parsePythonRequirement parses a PEP 508 dependency specifier.
+// URL-based requirements (e.g. "pkg @ https://...") are not supported.
+func parsePythonRequirement(s string) (pythonRequirement, bool) {
+	re := cachedregexp.MustCompile(`^\s*([A-Za-z0-9](?:[A-Za-z0-9._-]*[A-Za-z0-9])?)\s*(?:\[[^\]]*\])?\s*([^;@]*?)\s*(?:;\s*(.*))?$`)
+	m := re.FindStringSubmatch(s)
+	if m == nil {
+		return pythonRequirement{}, false
+	}
+	spec := strings.TrimSuffix(strings.TrimPrefix(m[2], "("), ")")
+	spec = strings.ReplaceAll(spec, " ", "")
+
+	return pythonRequirement{Name: m[1], Specifier: spec, Marker: strings.TrimSpace(m[3])}, true
+}
+
+// ParsePythonRequirement parses a PEP 508 dependency specifier into the PEP 503 normalized package name,
+// the version specifier and the environment marker.
+func ParsePythonRequirement(s string) (name, specifier, marker string, ok bool) {
+	req, ok := parsePythonRequirement(s)
+	if !ok {
+		return "", "", "", false
+	}
+
+	return normalizePythonName(req.Name), req.Specifier, req.Marker, true
+}
+
+// normalizePythonName normalizes the package name per PEP 503.
+func normalizePythonName(name string) string {
+	return strings.ToLower(cachedregexp.MustCompile(`[-_.]+`).ReplaceAllString(name, "-"))
+}
+
+func (rw PythonManifestIO) Read(f lockfile.DepFile) (Manifest, error) {
+	if filepath.Base(f.Path()) == "pyproject.toml" {
+		return rw.readPyProject(f)
+	}
+
+	return rw.readRequirementsTxt(f)
+}
+
+func (rw PythonManifestIO) readRequirementsTxt(f lockfile.DepFile) (Manifest, error) {
+	manif := newManifest()
+	manif.FilePath = f.Path()
+	// requirements.txt files do not name the project, so use the name of the directory instead
+	manif.Root = rw.makeRoot(filepath.Base(filepath.Dir(f.Path())), "")
+
+	seen := make(map[resolve.PackageKey]struct{})
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		// TODO: line continuations, and following "-r other-requirements.txt" references
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "-") {
+			continue
+		}
+		req, ok := parsePythonRequirement(line)
+		if !ok {
+			continue
+		}
+		rv := rw.makeReqVer(req)
+		if _, ok := seen[rv.PackageKey]; ok {
+			continue
+		}
+		seen[rv.PackageKey] = struct{}{}
+		manif.Requirements = append(manif.Requirements, rv)
+	}
+	if err := scanner.Err(); err != nil {
+		return Manifest{}, fmt.Errorf("could not read %s: %w", f.Path(), err)
+	}
+
+	slices.SortFunc(manif.Requirements, func(a, b resolve.RequirementVersion) int {
+		return a.VersionKey.Compare(b.VersionKey)
+	})
+
+	return manif, nil
+}
+
+func (rw PythonManifestIO) readPyProject(f lockfile.DepFile) (Manifest, error) {
+	var pyproject PyProjectTOML
+	if _, err := toml.NewDecoder(f).Decode(&pyproject); err != nil {
+		return Manifest{}, fmt.Errorf("could not parse %s: %w", f.Path(), err)
+	}
+
+	manif := newManifest()
+	manif.FilePath = f.Path()
+	manif.Root = rw.makeRoot(pyproject.Project.Name, pyproject.Project.Version)
+
+	seen := make(map[resolve.PackageKey]struct{})
+	for _, s := range pyproject.Project.Dependencies {
+		req, ok := parsePythonRequirement(s)
+		if !ok {
+			continue
+		}
+		rv := rw.makeReqVer(req)
+		seen[rv.PackageKey] = struct{}{}
+		manif.Requirements = append(manif.Requirements, rv)
+		manif.Groups[rv.PackageKey] = []string{MainGroup}
+	}
+
+	// Optional dependencies ("extras") are treated like npm's dev dependencies,
+	// they are only installed if the group is requested.
+	extras := make([]string, 0, len(pyproject.Project.OptionalDependencies))
+	for extra := range pyproject.Project.OptionalDependencies {
+		extras = append(extras, extra)
+	}
+	slices.Sort(extras)
+	for _, extra := range extras {
+		for _, s := range pyproject.Project.OptionalDependencies[extra] {
+			req, ok := parsePythonRequirement(s)
+			if !ok {
+				continue
+			}
+			rv := rw.makeReqVer(req)
+			if _, ok := seen[rv.PackageKey]; !ok {
+				seen[rv.PackageKey] = struct{}{}
+				manif.Requirements = append(manif.Requirements, rv)
+			}
+			if !slices.Contains(manif.Groups[rv.PackageKey], extra) {
+				manif.Groups[rv.PackageKey] = append(manif.Groups[rv.PackageKey], extra)
+			}
+		}
+	}
+
+	// Dependency groups are only used for development (e.g. for tests or linting),
+	// they are not installed with the package at all.
+	groups := make([]string, 0, len(pyproject.DependencyGroups))
+	for group := range pyproject.DependencyGroups {
+		groups = append(groups, group)
+	}
+	slices.Sort(groups)
+	for _, group := range groups {
+		for _, entry := range pyproject.DependencyGroups[group] {
+			// {include-group = "..."} entries are covered when reading the included group
+			s, ok := entry.(string)
+			if !ok {
+				continue
+			}
+			req, ok := parsePythonRequirement(s)
+			if !ok {
+				continue
+			}
+			rv := rw.makeReqVer(req)
+			if _, ok := seen[rv.PackageKey]; !ok {
+				seen[rv.PackageKey] = struct{}{}
+				manif.Requirements = append(manif.Requirements, rv)
+			}
+			name := pythonDependencyGroupPrefix + group
+			if !slices.Contains(manif.Groups[rv.PackageKey], name) {
+				manif.Groups[rv.PackageKey] = append(manif.Groups[rv.PackageKey], name)
+			}
+		}
+	}
+
+	slices.SortFunc(manif.Requirements, func(a, b resolve.RequirementVersion) int {
+		return a.VersionKey.Compare(b.VersionKey)
+	})
+
+	return manif, nil
+}
+
+func (PythonManifestIO) makeRoot(name, version string) resolve.Version {
+	return resolve.Version{
+		VersionKey: resolve.VersionKey{
+			PackageKey: resolve.PackageKey{
+				Name:   normalizePythonName(name),
+				System: util.PyPI,
+			},
+			Version:     version,
+			VersionType: resolve.Concrete,
+		}}
+}
+
+func (PythonManifestIO) makeReqVer(req pythonRequirement) resolve.RequirementVersion {
+	// TODO: evaluate environment markers, requirements that don't apply to the current environment are still included
+	return resolve.RequirementVersion{
+		Type: dep.NewType(),
+		VersionKey: resolve.VersionKey{
+			PackageKey: resolve.PackageKey{
+				Name:   normalizePythonName(req.Name),
+				System: util.PyPI,
+			},
+			Version:     req.Specifier,
+			VersionType: resolve.Requirement,
+		},
+	}
+}
+
+func (rw PythonManifestIO) Write(r lockfile.DepFile, w io.Writer, patch ManifestPatch) error {
+	var buf strings.Builder
+	if _, err := io.Copy(&buf, r); err != nil {
+		return err
+	}
+	text := buf.String()
+
+	// Python has no equivalent of npm's overrides, so only the direct dependencies can be changed.
+	// TODO: write overrides as constraints files (pip's "-c constraints.txt")
+	if len(patch.Overrides) > 0 {
+		return fmt.Errorf("overrides are not supported for %s", filepath.Base(r.Path()))
+	}
+
+	// In requirements.txt, each line is a requirement. In pyproject.toml, each requirement is a quoted string.
+	entryRe := cachedregexp.MustCompile(`(?m)^[^\n]*$`)
+	if filepath.Base(r.Path()) == "pyproject.toml" {
+		entryRe = cachedregexp.MustCompile(`"[^"\n]*"|'[^'\n]*'`)
+	}
+
+	for _, changedDep := range patch.Deps {
+		found := false
+		text = entryRe.ReplaceAllStringFunc(text, func(entry string) string {
+			newEntry, ok := rw.replaceSpecifier(entry, changedDep.Pkg.Name, changedDep.OrigRequire, changedDep.NewRequire)
+			found = found || ok
+
+			return newEntry
+		})
+		if !found {
+			return fmt.Errorf("could not find requirement %s%s in %s", changedDep.Pkg.Name, changedDep.OrigRequire, r.Path())
+		}
+	}
+
+	_, err := io.WriteString(w, text)
+
+	return err
+}
+
+// replaceSpecifier changes the version specifier of a single requirement if it is for the package,
+// keeping any quotes, extras, environment markers and comments unchanged.
+func (PythonManifestIO) replaceSpecifier(entry, name, origSpec, newSpec string) (string, bool) {
+	re := cachedregexp.MustCompile(`^(\s*["']?)([A-Za-z0-9](?:[A-Za-z0-9._-]*[A-Za-z0-9])?)(\s*(?:\[[^\]]*\])?\s*)([^;#"'@]*?)(\s*(?:[;#"'].*)?)$`)
+	m := re.FindStringSubmatch(entry)
+	if m == nil || normalizePythonName(m[2]) != name {
+		return entry, false
+	}
+	if strings.ReplaceAll(strings.Trim(m[4], "() "), " ", "") != origSpec {
+		return entry, false
+	}
+
+	return m[1] + m[2] + m[3] + newSpec + m[5], true
+}
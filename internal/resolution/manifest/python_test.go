@@ -0,0 +1,47 @@
+package manifest_test
+
+import (
+	"testing"
+
+	"github.com/google/osv-scanner/internal/resolution/manifest"
+	"github.com/google/osv-scanner/internal/resolution/util"
+	"github.com/google/osv-scanner/pkg/lockfile"
+)
+
+func TestPythonManifestIO_ReadPyProjectDevGroups(t *testing.T) {
+	t.Parallel()
+
+	f, err := lockfile.OpenLocalDepFile("fixtures/pyproject-groups/pyproject.toml")
+	if err != nil {
+		t.Fatalf("failed to open fixture: %v", err)
+	}
+	defer f.Close()
+
+	m, err := manifest.PythonManifestIO{}.Read(f)
+	if err != nil {
+		t.Fatalf("failed to read manifest: %v", err)
+	}
+
+	want := map[string]bool{
+		// runtime requirements that are also in a dev extra or dependency group are still needed at runtime
+		"requests": false,
+		"click":    false,
+		"pysocks":  false,
+		"black":    true,
+		"pytest":   true,
+		"ruff":     true,
+	}
+	if len(m.Requirements) != len(want) {
+		t.Errorf("Read() requirements = %v, want %d requirements", m.Requirements, len(want))
+	}
+	for _, req := range m.Requirements {
+		wantDev, ok := want[req.Name]
+		if !ok {
+			t.Errorf("Read() unexpected requirement %v", req)
+			continue
+		}
+		if got := manifest.IsDevGroup(util.PyPI, m.Groups[req.PackageKey]); got != wantDev {
+			t.Errorf("IsDevGroup(%s groups %q) = %v, want %v", req.Name, m.Groups[req.PackageKey], got, wantDev)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package resolution
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"deps.dev/util/resolve"
+	"deps.dev/util/resolve/dep"
+	"github.com/google/osv-scanner/internal/resolution/util"
+)
+
+// mavenResolver resolves Maven dependency graphs using Maven's "nearest definition" strategy:
+// the version of a package closest to the root of the dependency tree is used,
+// with ties broken by the order the dependencies are declared.
+// Requirements that cannot be resolved are recorded as errors on the node requiring them.
+// TODO: exclusions, version conflicts in ranges, parent poms & BOMs
+type mavenResolver struct {
+	cl resolve.Client
+}
+
+func newMavenResolver(cl resolve.Client) resolve.Resolver {
+	return mavenResolver{cl: cl}
+}
+
+// mavenTransitiveScope checks if a dependency with the given type is inherited by dependents,
+// test, provided & system dependencies, and optional dependencies are only used by the package declaring them.
+func mavenTransitiveScope(t dep.Type) bool {
+	if t.HasAttr(dep.Opt) {
+		return false
+	}
+	scope, _ := t.GetAttr(dep.Scope)
+	switch scope {
+	case "test", "provided", "system":
+		return false
+	default:
+		return true
+	}
+}
+
+func (r mavenResolver) Resolve(ctx context.Context, vk resolve.VersionKey) (*resolve.Graph, error) {
+	g := &resolve.Graph{}
+	root := g.AddNode(vk)
+
+	type mavenNode struct {
+		id resolve.NodeID
+		vk resolve.VersionKey
+	}
+	chosen := make(map[resolve.PackageKey]resolve.NodeID)
+	chosen[vk.PackageKey] = root
+	// breadth-first traversal, so the nearest definitions are seen first
+	queue := []mavenNode{{id: root, vk: vk}}
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		reqs, err := r.cl.Requirements(ctx, n.vk)
+		if err != nil {
+			return nil, err
+		}
+		for _, req := range reqs {
+			if n.id != root && !mavenTransitiveScope(req.Type) {
+				continue
+			}
+			if nID, ok := chosen[req.PackageKey]; ok {
+				// a nearer definition has already been chosen
+				if err := g.AddEdge(n.id, nID, req.Version, req.Type); err != nil {
+					return nil, err
+				}
+
+				continue
+			}
+			ver, err := r.selectVersion(ctx, req.VersionKey)
+			if err != nil {
+				if err := g.AddError(n.id, req.VersionKey, err.Error()); err != nil {
+					return nil, err
+				}
+
+				continue
+			}
+			nID := g.AddNode(ver)
+			chosen[req.PackageKey] = nID
+			if err := g.AddEdge(n.id, nID, req.Version, req.Type); err != nil {
+				return nil, err
+			}
+			queue = append(queue, mavenNode{id: nID, vk: ver})
+		}
+	}
+
+	return g, nil
+}
+
+// selectVersion picks the concrete version for a requirement.
+// Maven's plain version requirements (e.g. "1.2.3") are 'soft' requirements for that exact version,
+// while version ranges (e.g. "[1.2,2.0)") select the highest matching version.
+func (r mavenResolver) selectVersion(ctx context.Context, req resolve.VersionKey) (resolve.VersionKey, error) {
+	if req.Version == "" {
+		return resolve.VersionKey{}, errors.New("no version specified, it may be managed by a parent pom or BOM")
+	}
+	if !strings.HasPrefix(req.Version, "[") && !strings.HasPrefix(req.Version, "(") {
+		return resolve.VersionKey{
+			PackageKey:  req.PackageKey,
+			Version:     req.Version,
+			VersionType: resolve.Concrete,
+		}, nil
+	}
+
+	vers, err := r.cl.MatchingVersions(ctx, req)
+	if err != nil {
+		return resolve.VersionKey{}, err
+	}
+	if len(vers) == 0 {
+		return resolve.VersionKey{}, fmt.Errorf("no version matches %s", req.Version)
+	}
+	best := vers[0].VersionKey
+	for _, v := range vers[1:] {
+		if util.Semver(req.System).Compare(v.Version, best.Version) > 0 {
+			best = v.VersionKey
+		}
+	}
+
+	return best, nil
+}
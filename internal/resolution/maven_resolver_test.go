@@ -0,0 +1,94 @@
+package resolution
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"deps.dev/util/resolve"
+	"deps.dev/util/resolve/dep"
+)
+
+func TestMavenResolver(t *testing.T) {
+	t.Parallel()
+
+	req := func(name, version string, attrs ...any) resolve.RequirementVersion {
+		return testReq(resolve.Maven, name, version, attrs...)
+	}
+	tests := []struct {
+		name string
+		pkgs []testPackage // the first package is the root
+		want []string
+	}{
+		{
+			name: "nearest definition wins",
+			pkgs: []testPackage{
+				{name: "com.example:root", version: "1.0", reqs: []resolve.RequirementVersion{req("com.example:a", "1.0"), req("com.example:b", "1.0")}},
+				{name: "com.example:a", version: "1.0", reqs: []resolve.RequirementVersion{req("com.example:c", "1.0")}},
+				{name: "com.example:b", version: "1.0", reqs: []resolve.RequirementVersion{req("com.example:c", "2.0")}},
+				{name: "com.example:c", version: "1.0"},
+				{name: "com.example:c", version: "2.0"},
+			},
+			want: []string{
+				`com.example:root@1.0 -> com.example:a@1.0 "1.0"`,
+				`com.example:root@1.0 -> com.example:b@1.0 "1.0"`,
+				`com.example:a@1.0 -> com.example:c@1.0 "1.0"`,
+				`com.example:b@1.0 -> com.example:c@1.0 "2.0"`,
+			},
+		},
+		{
+			name: "test and optional dependencies are not transitive",
+			pkgs: []testPackage{
+				{name: "com.example:root", version: "1.0", reqs: []resolve.RequirementVersion{req("com.example:a", "1.0"), req("junit:junit", "4.13", dep.Scope, "test")}},
+				{name: "com.example:a", version: "1.0", reqs: []resolve.RequirementVersion{req("org.mockito:mockito-core", "5.0", dep.Scope, "test"), req("com.example:opt", "1.0", dep.Opt, "")}},
+				{name: "junit:junit", version: "4.13"},
+				{name: "org.mockito:mockito-core", version: "5.0"},
+				{name: "com.example:opt", version: "1.0"},
+			},
+			want: []string{
+				`com.example:root@1.0 -> com.example:a@1.0 "1.0"`,
+				`com.example:root@1.0 -> junit:junit@4.13 "4.13"`,
+			},
+		},
+		{
+			name: "range selects the highest matching version",
+			pkgs: []testPackage{
+				{name: "com.example:root", version: "1.0", reqs: []resolve.RequirementVersion{req("com.example:a", "[1.0,2.0)")}},
+				{name: "com.example:a", version: "1.0"},
+				{name: "com.example:a", version: "1.5"},
+				{name: "com.example:a", version: "2.0"},
+			},
+			want: []string{
+				`com.example:root@1.0 -> com.example:a@1.5 "[1.0,2.0)"`,
+			},
+		},
+		{
+			name: "unresolvable requirements are errors on the requiring node",
+			pkgs: []testPackage{
+				{name: "com.example:root", version: "1.0", reqs: []resolve.RequirementVersion{req("com.example:a", "[5.0,)"), req("com.example:managed", ""), req("com.example:b", "1.0")}},
+				{name: "com.example:a", version: "1.0"},
+				{name: "com.example:b", version: "1.0"},
+			},
+			want: []string{
+				`com.example:root@1.0 -> com.example:b@1.0 "1.0"`,
+				`com.example:root@1.0 ! com.example:a "[5.0,)": no version matches [5.0,)`,
+				`com.example:root@1.0 ! com.example:managed "": no version specified, it may be managed by a parent pom or BOM`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := newMavenResolver(newTestClient(resolve.Maven, tt.pkgs))
+			g, err := r.Resolve(context.Background(), rootKey(resolve.Maven, tt.pkgs))
+			if err != nil {
+				t.Fatalf("Resolve() error = %v", err)
+			}
+			if got := graphLines(g); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Resolve() graph:\n%q\nwant:\n%q", got, tt.want)
+			}
+		})
+	}
+}
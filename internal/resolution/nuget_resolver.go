@@ -0,0 +1,76 @@
+package resolution
+
+import (
+	"context"
+	"strings"
+
+	"deps.dev/util/resolve"
+	"github.com/google/osv-scanner/internal/resolution/util"
+)
+
+// nugetResolver resolves .NET package graphs using NuGet's dependency resolution rules:
+// each requirement selects the lowest applicable version, and the requirement nearest to the root wins
+// if the same package is required more than once ("direct dependency wins").
+// TODO: NuGet picks the highest of the lowest applicable versions for "cousin" dependencies at the same depth
+type nugetResolver struct {
+	cl resolve.Client
+}
+
+func newNuGetResolver(cl resolve.Client) resolve.Resolver {
+	return nugetResolver{cl: cl}
+}
+
+func (r nugetResolver) Resolve(ctx context.Context, vk resolve.VersionKey) (*resolve.Graph, error) {
+	g := &resolve.Graph{}
+	root := g.AddNode(vk)
+
+	type nugetNode struct {
+		id resolve.NodeID
+		vk resolve.VersionKey
+	}
+	// NuGet package IDs are case-insensitive
+	chosen := make(map[string]resolve.NodeID)
+	chosen[strings.ToLower(vk.Name)] = root
+	queue := []nugetNode{{id: root, vk: vk}}
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		reqs, err := r.cl.Requirements(ctx, n.vk)
+		if err != nil {
+			return nil, err
+		}
+		for _, req := range reqs {
+			name := strings.ToLower(req.Name)
+			if nID, ok := chosen[name]; ok {
+				// a nearer requirement has already been chosen
+				if err := g.AddEdge(n.id, nID, req.Version, req.Type); err != nil {
+					return nil, err
+				}
+
+				continue
+			}
+			vers, err := r.cl.MatchingVersions(ctx, req.VersionKey)
+			if err != nil {
+				return nil, err
+			}
+			if len(vers) == 0 {
+				g.Error = "no version of " + req.Name + " matches " + req.Version
+				return g, nil
+			}
+			lowest := vers[0].VersionKey
+			for _, v := range vers[1:] {
+				if util.Semver(req.System).Compare(v.Version, lowest.Version) < 0 {
+					lowest = v.VersionKey
+				}
+			}
+			nID := g.AddNode(lowest)
+			chosen[name] = nID
+			if err := g.AddEdge(n.id, nID, req.Version, req.Type); err != nil {
+				return nil, err
+			}
+			queue = append(queue, nugetNode{id: nID, vk: lowest})
+		}
+	}
+
+	return g, nil
+}
@@ -0,0 +1,69 @@
+package resolution
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"deps.dev/util/resolve"
+	"github.com/google/osv-scanner/internal/resolution/util"
+)
+
+func TestNuGetResolver(t *testing.T) {
+	t.Parallel()
+
+	req := func(name, version string) resolve.RequirementVersion {
+		return testReq(util.NuGet, name, version)
+	}
+	tests := []struct {
+		name string
+		pkgs []testPackage // the first package is the root
+		want []string
+	}{
+		{
+			name: "lowest applicable version",
+			pkgs: []testPackage{
+				{name: "Root", version: "1.0.0", reqs: []resolve.RequirementVersion{req("Newtonsoft.Json", "12.0.1"), req("Serilog", "[2.0.0,3.0.0)")}},
+				{name: "Newtonsoft.Json", version: "12.0.1"},
+				{name: "Newtonsoft.Json", version: "12.0.3"},
+				{name: "Newtonsoft.Json", version: "13.0.1"},
+				{name: "Serilog", version: "1.5.0"},
+				{name: "Serilog", version: "2.10.0"},
+				{name: "Serilog", version: "2.12.0"},
+			},
+			want: []string{
+				`Root@1.0.0 -> Newtonsoft.Json@12.0.1 "12.0.1"`,
+				`Root@1.0.0 -> Serilog@2.10.0 "[2.0.0,3.0.0)"`,
+			},
+		},
+		{
+			name: "direct dependency wins, ignoring case",
+			pkgs: []testPackage{
+				{name: "Root", version: "1.0.0", reqs: []resolve.RequirementVersion{req("A", "1.0.0"), req("Shared", "2.0.0")}},
+				{name: "A", version: "1.0.0", reqs: []resolve.RequirementVersion{req("shared", "2.1.0")}},
+				{name: "Shared", version: "2.0.0"},
+				{name: "Shared", version: "2.1.0"},
+			},
+			want: []string{
+				`Root@1.0.0 -> A@1.0.0 "1.0.0"`,
+				`Root@1.0.0 -> Shared@2.0.0 "2.0.0"`,
+				`A@1.0.0 -> Shared@2.0.0 "2.1.0"`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := newNuGetResolver(newTestClient(util.NuGet, tt.pkgs))
+			g, err := r.Resolve(context.Background(), rootKey(util.NuGet, tt.pkgs))
+			if err != nil {
+				t.Fatalf("Resolve() error = %v", err)
+			}
+			if got := graphLines(g); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Resolve() graph:\n%q\nwant:\n%q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package resolution
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"deps.dev/util/resolve"
+	"deps.dev/util/resolve/dep"
+	"deps.dev/util/semver"
+)
+
+// pypiResolver resolves Python dependency graphs using PEP 440 version specifiers.
+// Each package is resolved to the highest version matching the first requirement on it that is encountered,
+// in breadth-first order from the root.
+// Requirements that cannot be satisfied, including later requirements that the already-chosen version does not match,
+// are recorded as errors on the node requiring them.
+// Environment markers other than extras (e.g. `sys_platform == "win32"`) are not evaluated,
+// so those requirements are always included.
+// TODO: pip backtracks to find versions that satisfy all requirements at once.
+type pypiResolver struct {
+	cl resolve.Client
+}
+
+func newPyPIResolver(cl resolve.Client) resolve.Resolver {
+	return pypiResolver{cl: cl}
+}
+
+// pypiExtraOnly checks if a requirement is only installed when one of the package's extras is requested
+// e.g. `pytest; extra == "test"`
+func pypiExtraOnly(t dep.Type) bool {
+	env, ok := t.GetAttr(dep.Environment)
+
+	return ok && strings.Contains(env, "extra")
+}
+
+func (r pypiResolver) Resolve(ctx context.Context, vk resolve.VersionKey) (*resolve.Graph, error) {
+	g := &resolve.Graph{}
+	root := g.AddNode(vk)
+
+	type pypiNode struct {
+		id resolve.NodeID
+		vk resolve.VersionKey
+	}
+	chosen := make(map[resolve.PackageKey]resolve.NodeID)
+	chosen[vk.PackageKey] = root
+	queue := []pypiNode{{id: root, vk: vk}}
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		reqs, err := r.cl.Requirements(ctx, n.vk)
+		if err != nil {
+			return nil, err
+		}
+		for _, req := range reqs {
+			if n.id != root && pypiExtraOnly(req.Type) {
+				continue
+			}
+			if nID, ok := chosen[req.PackageKey]; ok {
+				if err := g.AddEdge(n.id, nID, req.Version, req.Type); err != nil {
+					return nil, err
+				}
+				if ver := g.Nodes[nID].Version; !pypiMatches(req.Version, ver.Version) {
+					msg := fmt.Sprintf("conflicts with %s, which was chosen for an earlier requirement", ver.Version)
+					if err := g.AddError(n.id, req.VersionKey, msg); err != nil {
+						return nil, err
+					}
+				}
+
+				continue
+			}
+			ver, err := r.selectVersion(ctx, req.VersionKey)
+			if err != nil {
+				if err := g.AddError(n.id, req.VersionKey, err.Error()); err != nil {
+					return nil, err
+				}
+
+				continue
+			}
+			nID := g.AddNode(ver)
+			chosen[req.PackageKey] = nID
+			if err := g.AddEdge(n.id, nID, req.Version, req.Type); err != nil {
+				return nil, err
+			}
+			queue = append(queue, pypiNode{id: nID, vk: ver})
+		}
+	}
+
+	return g, nil
+}
+
+// pypiMatches checks if a version matches a PEP 440 specifier. Unparseable specifiers match nothing.
+func pypiMatches(spec, version string) bool {
+	if spec == "" {
+		return true
+	}
+	c, err := semver.PyPI.ParseConstraint(spec)
+	if err != nil {
+		return false
+	}
+
+	return c.Match(version)
+}
+
+// pypiMentionsPrerelease checks if any of the versions in a PEP 440 specifier is a pre-release e.g. ">=2.0b1".
+func pypiMentionsPrerelease(spec string) bool {
+	for _, clause := range strings.Split(spec, ",") {
+		v := strings.TrimLeft(strings.TrimSpace(clause), "<>=!~ ")
+		v = strings.TrimSuffix(v, ".*")
+		if pv, err := semver.PyPI.Parse(v); err == nil && pv.IsPrerelease() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// selectVersion picks the highest version matching the PEP 440 specifier.
+// As in PEP 440, pre-releases are only selected if the specifier explicitly mentions one, or if no final release matches.
+func (r pypiResolver) selectVersion(ctx context.Context, req resolve.VersionKey) (resolve.VersionKey, error) {
+	var c *semver.Constraint
+	if req.Version != "" {
+		var err error
+		c, err = semver.PyPI.ParseConstraint(req.Version)
+		if err != nil {
+			return resolve.VersionKey{}, fmt.Errorf("invalid requirement %s%s: %w", req.Name, req.Version, err)
+		}
+	}
+	allowPre := pypiMentionsPrerelease(req.Version)
+
+	vers, err := r.cl.Versions(ctx, req.PackageKey)
+	if err != nil {
+		return resolve.VersionKey{}, err
+	}
+
+	var best, bestPre *semver.Version
+	var bestVK, bestPreVK resolve.VersionKey
+	for _, vk := range vers {
+		if vk.VersionType != resolve.Concrete {
+			continue
+		}
+		v, err := semver.PyPI.Parse(vk.Version)
+		if err != nil {
+			continue
+		}
+		// pre-releases are matched against the specifier here and only chosen below as a fallback
+		if c != nil && !c.MatchVersionPrerelease(v) {
+			continue
+		}
+		if v.IsPrerelease() && !allowPre {
+			if bestPre == nil || v.Compare(bestPre) > 0 {
+				bestPre, bestPreVK = v, vk.VersionKey
+			}
+
+			continue
+		}
+		if best == nil || v.Compare(best) > 0 {
+			best, bestVK = v, vk.VersionKey
+		}
+	}
+
+	switch {
+	case best != nil:
+		return bestVK, nil
+	case bestPre != nil:
+		return bestPreVK, nil
+	default:
+		return resolve.VersionKey{}, fmt.Errorf("no version matches %q", req.Version)
+	}
+}
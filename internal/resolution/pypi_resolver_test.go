@@ -0,0 +1,102 @@
+package resolution
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"deps.dev/util/resolve"
+	"deps.dev/util/resolve/dep"
+	"github.com/google/osv-scanner/internal/resolution/util"
+)
+
+func TestPyPIResolver(t *testing.T) {
+	t.Parallel()
+
+	req := func(name, version string, attrs ...any) resolve.RequirementVersion {
+		return testReq(util.PyPI, name, version, attrs...)
+	}
+	tests := []struct {
+		name string
+		pkgs []testPackage // the first package is the root
+		want []string
+	}{
+		{
+			name: "highest matching final release",
+			pkgs: []testPackage{
+				{name: "root", version: "1.0", reqs: []resolve.RequirementVersion{req("requests", ">=2.0,<3")}},
+				{name: "requests", version: "2.30.0", reqs: []resolve.RequirementVersion{req("urllib3", ">=1.21")}},
+				{name: "requests", version: "2.31.0", reqs: []resolve.RequirementVersion{req("urllib3", ">=1.21")}},
+				{name: "requests", version: "3.0.0"},
+				{name: "urllib3", version: "2.1.0"},
+				{name: "urllib3", version: "2.2.0b1"},
+			},
+			want: []string{
+				`root@1.0 -> requests@2.31.0 ">=2.0,<3"`,
+				`requests@2.31.0 -> urllib3@2.1.0 ">=1.21"`,
+			},
+		},
+		{
+			name: "pre-releases",
+			pkgs: []testPackage{
+				{name: "root", version: "1.0", reqs: []resolve.RequirementVersion{req("explicit", ">=2.0b1"), req("only-pre", ">=1.0")}},
+				{name: "explicit", version: "1.9"},
+				{name: "explicit", version: "2.0"},
+				{name: "explicit", version: "2.1b2"},
+				{name: "only-pre", version: "1.1a1"},
+				{name: "only-pre", version: "1.1a2"},
+			},
+			want: []string{
+				`root@1.0 -> explicit@2.1b2 ">=2.0b1"`,
+				`root@1.0 -> only-pre@1.1a2 ">=1.0"`,
+			},
+		},
+		{
+			name: "extras are only installed for the root",
+			pkgs: []testPackage{
+				{name: "root", version: "1.0", reqs: []resolve.RequirementVersion{req("flask", "==3.0.0"), req("pytest", ">=8", dep.Environment, `extra == "test"`)}},
+				{name: "flask", version: "3.0.0", reqs: []resolve.RequirementVersion{req("asgiref", ">=3.2", dep.Environment, `extra == "async"`)}},
+				{name: "pytest", version: "8.0.0"},
+				{name: "asgiref", version: "3.7.0"},
+			},
+			want: []string{
+				`root@1.0 -> flask@3.0.0 "==3.0.0"`,
+				`root@1.0 -> pytest@8.0.0 ">=8"`,
+			},
+		},
+		{
+			name: "unsatisfiable requirements are errors on the requiring node",
+			pkgs: []testPackage{
+				{name: "root", version: "1.0", reqs: []resolve.RequirementVersion{req("a", "==1.0"), req("b", "==1.0"), req("old", ">=1")}},
+				{name: "a", version: "1.0", reqs: []resolve.RequirementVersion{req("c", "<2")}},
+				{name: "b", version: "1.0", reqs: []resolve.RequirementVersion{req("c", ">=2")}},
+				{name: "c", version: "1.5"},
+				{name: "c", version: "2.0"},
+				{name: "old", version: "0.5"},
+			},
+			want: []string{
+				`root@1.0 -> a@1.0 "==1.0"`,
+				`root@1.0 -> b@1.0 "==1.0"`,
+				`a@1.0 -> c@1.5 "<2"`,
+				`b@1.0 -> c@1.5 ">=2"`,
+				`root@1.0 ! old ">=1": no version matches ">=1"`,
+				`b@1.0 ! c ">=2": conflicts with 1.5, which was chosen for an earlier requirement`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := newPyPIResolver(newTestClient(util.PyPI, tt.pkgs))
+			g, err := r.Resolve(context.Background(), rootKey(util.PyPI, tt.pkgs))
+			if err != nil {
+				t.Fatalf("Resolve() error = %v", err)
+			}
+			if got := graphLines(g); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Resolve() graph:\n%q\nwant:\n%q", got, tt.want)
+			}
+		})
+	}
+}
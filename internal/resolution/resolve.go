@@ -11,6 +11,7 @@ import (
 	"deps.dev/util/resolve/npm"
 	"github.com/google/osv-scanner/internal/resolution/client"
 	"github.com/google/osv-scanner/internal/resolution/manifest"
+	"github.com/google/osv-scanner/internal/resolution/util"
 	"github.com/google/osv-scanner/pkg/models"
 )
 
@@ -29,26 +30,46 @@ type ResolutionResult struct {
 	Graph           *resolve.Graph
 	Vulns           []ResolutionVuln
 	UnfilteredVulns []ResolutionVuln
+	Diagnostics     []Diagnostic // Problems encountered that may have made the results less accurate
 }
 
 func getResolver(sys resolve.System, cl resolve.Client) (resolve.Resolver, error) {
 	switch sys { //nolint:exhaustive
 	case resolve.NPM:
 		return npm.NewResolver(cl), nil
+	case resolve.Maven:
+		return newMavenResolver(cl), nil
+	case util.PyPI:
+		return newPyPIResolver(cl), nil
+	case util.Go:
+		return newGoResolver(cl), nil
+	case util.Cargo:
+		return newCargoResolver(cl), nil
+	case util.NuGet:
+		return newNuGetResolver(cl), nil
 	default:
 		return nil, fmt.Errorf("no resolver for ecosystem %v", sys)
 	}
 }
 
-func Resolve(ctx context.Context, cl client.ResolutionClient, m manifest.Manifest) (*ResolutionResult, error) {
-	c := client.NewOverrideClient(cl.DependencyClient)
+// manifestClient wraps cl to also know about the manifest, its local packages, and its overrides.
+func manifestClient(cl client.DependencyClient, m manifest.Manifest) client.DependencyClient {
+	c := client.NewOverrideClient(cl)
 	c.AddVersion(m.Root, m.Requirements)
 	for _, loc := range m.LocalManifests {
 		c.AddVersion(loc.Root, loc.Requirements)
 		// TODO: may need to do this recursively
 	}
-	cl.DependencyClient = c
-	r, err := getResolver(m.System(), cl.DependencyClient)
+	for _, o := range m.Overrides {
+		c.ForceRequirement(o.PackageKey, o.Version)
+	}
+
+	return c
+}
+
+// resolveGraph resolves the dependency graph of the manifest, using a client from manifestClient.
+func resolveGraph(ctx context.Context, cl client.DependencyClient, m manifest.Manifest) (*resolve.Graph, error) {
+	r, err := getResolver(m.System(), cl)
 	if err != nil {
 		return nil, err
 	}
@@ -62,10 +83,33 @@ func Resolve(ctx context.Context, cl client.ResolutionClient, m manifest.Manifes
 		return nil, errors.New(graph.Error)
 	}
 
+	return graph, nil
+}
+
+// ResolveDependencies resolves the dependency graph of the manifest, without finding the vulnerabilities in it.
+func ResolveDependencies(ctx context.Context, cl client.DependencyClient, m manifest.Manifest) (*resolve.Graph, error) {
+	return resolveGraph(ctx, manifestClient(cl, m), m)
+}
+
+func Resolve(ctx context.Context, cl client.ResolutionClient, m manifest.Manifest) (*ResolutionResult, error) {
+	cl.DependencyClient = manifestClient(cl.DependencyClient, m)
+	graph, err := resolveGraph(ctx, cl.DependencyClient, m)
+	if err != nil {
+		return nil, err
+	}
+
 	result := &ResolutionResult{
 		Manifest: m.Clone(),
 		Graph:    graph,
 	}
+	// Requirements that could not be resolved are left out of the graph, without failing the resolution
+	var diags Diagnostics
+	for _, n := range graph.Nodes {
+		for _, e := range n.Errors {
+			diags.Add(DiagnosticUnresolvedRequirement, e.Req, "required by %s@%s: %s", n.Version.Name, n.Version.Version, e.Error)
+		}
+	}
+	result.Diagnostics = diags.List()
 
 	if err := result.computeVulns(ctx, cl); err != nil {
 		return nil, err
@@ -77,6 +121,17 @@ func Resolve(ctx context.Context, cl client.ResolutionClient, m manifest.Manifes
 	return result, nil
 }
 
+// ResolveGraph finds the vulnerabilities in an already resolved graph, such as one read from a lockfile.
+func ResolveGraph(ctx context.Context, cl client.ResolutionClient, g *resolve.Graph) (*ResolutionResult, error) {
+	result := &ResolutionResult{Graph: g}
+	if err := result.computeVulns(ctx, cl); err != nil {
+		return nil, err
+	}
+	result.UnfilteredVulns = slices.Clone(result.Vulns)
+
+	return result, nil
+}
+
 // computeVulns scans for vulnerabilities in a resolved graph and populates res.Vulns
 func (res *ResolutionResult) computeVulns(ctx context.Context, cl client.ResolutionClient) error {
 	nodeVulns, err := cl.FindVulns(res.Graph)
@@ -95,7 +150,7 @@ func (res *ResolutionResult) computeVulns(ctx context.Context, cl client.Resolut
 		}
 	}
 
-	nodeChains := ComputeChains(res.Graph, vulnerableNodes)
+	nodeChains := ComputeChains(res.Graph, vulnerableNodes, AllChains)
 	vulnChains := make(map[string][]DependencyChain)
 	for i, idx := range vulnerableNodes {
 		for _, vuln := range nodeVulns[idx] {
@@ -107,10 +162,11 @@ func (res *ResolutionResult) computeVulns(ctx context.Context, cl client.Resolut
 	// TODO: This constructs a single ResolutionVuln per vulnerability ID.
 	// The scan action treats vulns with the same ID but affecting different versions of a package as distinct.
 	// TODO: Combine aliased IDs
+	var diags Diagnostics
 	for id, vuln := range vulnInfo {
 		rv := ResolutionVuln{Vulnerability: vuln, DevOnly: true}
 		for _, chain := range vulnChains[id] {
-			if chainConstrains(ctx, cl, chain, &rv.Vulnerability) {
+			if chainConstrains(ctx, cl, chain, &rv.Vulnerability, &diags) {
 				rv.ProblemChains = append(rv.ProblemChains, chain)
 			} else {
 				rv.NonProblemChains = append(rv.NonProblemChains, chain)
@@ -125,6 +181,7 @@ func (res *ResolutionResult) computeVulns(ctx context.Context, cl client.Resolut
 		}
 		res.Vulns = append(res.Vulns, rv)
 	}
+	res.Diagnostics = append(res.Diagnostics, diags.List()...)
 
 	return nil
 }
@@ -155,37 +212,13 @@ func (res *ResolutionResult) CalculateDiff(other *ResolutionResult) ResolutionDi
 		ManifestPatch: manifest.ManifestPatch{Manifest: &res.Manifest},
 	}
 	// Find the changed requirements and the versions they resolve to
-	for i, oldReq := range res.Manifest.Requirements { // assuming these are in the same order and none are added/removed
-		newReq := other.Manifest.Requirements[i]
-		if oldReq.Version == newReq.Version {
-			continue
-		}
-		// Find the node in the graph to find which actual version it resolved to
-		var oldResolved string
-		for _, e := range res.Graph.Edges {
-			toNode := res.Graph.Nodes[e.To]
-			if e.From == 0 && toNode.Version.PackageKey == oldReq.PackageKey {
-				oldResolved = toNode.Version.Version
-				break
-			}
-		}
-		var newResolved string
-		for _, e := range other.Graph.Edges {
-			toNode := other.Graph.Nodes[e.To]
-			if e.From == 0 && toNode.Version.PackageKey == newReq.PackageKey {
-				newResolved = toNode.Version.Version
-				break
-			}
-		}
-		diff.Deps = append(diff.Deps, manifest.DependencyPatch{
-			Pkg:          oldReq.PackageKey,
-			Type:         oldReq.Type.Clone(),
-			OrigRequire:  oldReq.Version,
-			OrigResolved: oldResolved,
-			NewRequire:   newReq.Version,
-			NewResolved:  newResolved,
-		})
+	diff.Deps = append(diff.Deps, requirementChanges(res.Manifest, res.Graph, other.Manifest, other.Graph, "")...)
+	// Changes to requirements of local packages (e.g. npm workspaces) are attributed to their own manifest files
+	for i, loc := range res.Manifest.LocalManifests {
+		newLoc := other.Manifest.LocalManifests[i]
+		diff.Deps = append(diff.Deps, requirementChanges(loc, res.Graph, newLoc, other.Graph, loc.FilePath)...)
 	}
+	diff.Overrides = overrideChanges(res.Manifest, other.Manifest)
 
 	// Compute differences in present vulnerabilities.
 	// Currently this relies on vulnerability IDs being unique in the Vulns slice.
@@ -210,20 +243,93 @@ func (res *ResolutionResult) CalculateDiff(other *ResolutionResult) ResolutionDi
 	return diff
 }
 
+// requirementChanges finds the changed requirements between two versions of a manifest,
+// and the versions they resolve to in their respective graphs.
+func requirementChanges(oldManif manifest.Manifest, oldGraph *resolve.Graph, newManif manifest.Manifest, newGraph *resolve.Graph, manifestFile string) []manifest.DependencyPatch {
+	var patches []manifest.DependencyPatch
+	for i, oldReq := range oldManif.Requirements { // assuming these are in the same order and none are added/removed
+		newReq := newManif.Requirements[i]
+		if oldReq.Version == newReq.Version {
+			continue
+		}
+		patches = append(patches, manifest.DependencyPatch{
+			Pkg:          oldReq.PackageKey,
+			Type:         oldReq.Type.Clone(),
+			OrigRequire:  oldReq.Version,
+			OrigResolved: resolvedVersion(oldGraph, oldManif.Root.PackageKey, oldReq.PackageKey),
+			NewRequire:   newReq.Version,
+			NewResolved:  resolvedVersion(newGraph, newManif.Root.PackageKey, newReq.PackageKey),
+			ManifestFile: manifestFile,
+		})
+	}
+
+	return patches
+}
+
+// overrideChanges finds the overrides that were added or changed between two versions of a manifest.
+func overrideChanges(oldManif, newManif manifest.Manifest) []manifest.DependencyPatch {
+	var patches []manifest.DependencyPatch
+	for _, newO := range newManif.Overrides {
+		var origRequire string
+		if idx := slices.IndexFunc(oldManif.Overrides, func(rv resolve.RequirementVersion) bool {
+			return rv.PackageKey == newO.PackageKey
+		}); idx >= 0 {
+			origRequire = oldManif.Overrides[idx].Version
+		}
+		if origRequire == newO.Version {
+			continue
+		}
+		patches = append(patches, manifest.DependencyPatch{
+			Pkg:         newO.PackageKey,
+			Type:        newO.Type.Clone(),
+			OrigRequire: origRequire,
+			NewRequire:  newO.Version,
+			NewResolved: newO.Version,
+		})
+	}
+
+	return patches
+}
+
+// resolvedVersion finds which actual version a requirement of the parent package resolved to in the graph.
+func resolvedVersion(g *resolve.Graph, parent, pkg resolve.PackageKey) string {
+	// The root node is always node 0, but local packages need to be found by name
+	parentID := resolve.NodeID(0)
+	if g.Nodes[0].Version.PackageKey != parent {
+		idx := slices.IndexFunc(g.Nodes, func(n resolve.Node) bool { return n.Version.PackageKey == parent })
+		if idx < 0 {
+			return ""
+		}
+		parentID = resolve.NodeID(idx)
+	}
+
+	for _, e := range g.Edges {
+		toNode := g.Nodes[e.To]
+		if e.From == parentID && toNode.Version.PackageKey == pkg {
+			return toNode.Version.Version
+		}
+	}
+
+	return ""
+}
+
 // Compare compares ResolutionDiffs based on 'effectiveness' (best first):
 //
 // Sort order:
-//  1. (number of fixed vulns - introduced vulns) / (number of changed direct dependencies) [descending]
+//  1. (number of fixed vulns - introduced vulns) / (number of changed direct dependencies & overrides) [descending]
 //     (i.e. more efficient first)
 //  2. number of fixed vulns [descending]
-//  3. number of changed direct dependencies [ascending]
-//  4. changed direct dependency name package names [ascending]
-//  5. size of changed direct dependency bump [ascending]
+//  3. number of changed direct dependencies & overrides [ascending]
+//  4. changed direct dependency & override package names [ascending]
+//  5. size of changed direct dependency & override bump [ascending]
 func (a ResolutionDiff) Compare(b ResolutionDiff) int {
+	aChanges := a.changes()
+	bChanges := b.changes()
+
 	// 1. (fixed - introduced) / (changes) [desc]
 	// Multiply out to avoid float casts
-	aRatio := (len(a.RemovedVulns) - len(a.AddedVulns)) * (len(b.Deps))
-	bRatio := (len(b.RemovedVulns) - len(b.AddedVulns)) * (len(a.Deps))
+	aRatio := (len(a.RemovedVulns) - len(a.AddedVulns)) * (len(bChanges))
+	bRatio := (len(b.RemovedVulns) - len(b.AddedVulns)) * (len(aChanges))
 	if c := cmp.Compare(aRatio, bRatio); c != 0 {
 		return -c
 	}
@@ -234,22 +340,22 @@ func (a ResolutionDiff) Compare(b ResolutionDiff) int {
 	}
 
 	// 3. number of changed deps [asc]
-	if c := cmp.Compare(len(a.Deps), len(b.Deps)); c != 0 {
+	if c := cmp.Compare(len(aChanges), len(bChanges)); c != 0 {
 		return c
 	}
 
 	// 4. changed names [asc]
-	for i, aDep := range a.Deps {
-		bDep := b.Deps[i]
+	for i, aDep := range aChanges {
+		bDep := bChanges[i]
 		if c := aDep.Pkg.Compare(bDep.Pkg); c != 0 {
 			return c
 		}
 	}
 
 	// 5. dependency bump amount [asc]
-	for i, aDep := range a.Deps {
-		bDep := b.Deps[i]
-		sv := aDep.Pkg.Semver()
+	for i, aDep := range aChanges {
+		bDep := bChanges[i]
+		sv := util.Semver(aDep.Pkg.System)
 		if c := sv.Compare(aDep.NewResolved, bDep.NewResolved); c != 0 {
 			return c
 		}
@@ -257,3 +363,12 @@ func (a ResolutionDiff) Compare(b ResolutionDiff) int {
 
 	return 0
 }
+
+// changes returns all the changed direct dependencies, followed by the changed overrides.
+func (a ResolutionDiff) changes() []manifest.DependencyPatch {
+	if len(a.Overrides) == 0 {
+		return a.Deps
+	}
+
+	return append(slices.Clone(a.Deps), a.Overrides...)
+}
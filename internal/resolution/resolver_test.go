@@ -0,0 +1,91 @@
+package resolution
+
+import (
+	"context"
+	"fmt"
+
+	"deps.dev/util/resolve"
+	"deps.dev/util/resolve/dep"
+	"github.com/google/osv-scanner/internal/resolution/util"
+)
+
+// testPackage is a package version in a test registry, and the requirements of that version.
+type testPackage struct {
+	name    string
+	version string
+	reqs    []resolve.RequirementVersion
+}
+
+// testReq makes a requirement on a package, with the given dependency type attributes e.g. dep.Scope, "test".
+func testReq(sys resolve.System, name, version string, attrs ...any) resolve.RequirementVersion {
+	t := dep.NewType()
+	for i := 0; i+1 < len(attrs); i += 2 {
+		t.AddAttr(attrs[i].(dep.AttrKey), attrs[i+1].(string))
+	}
+
+	return resolve.RequirementVersion{
+		VersionKey: resolve.VersionKey{
+			PackageKey:  resolve.PackageKey{System: sys, Name: name},
+			Version:     version,
+			VersionType: resolve.Requirement,
+		},
+		Type: t,
+	}
+}
+
+// testClient is a resolve.LocalClient that matches requirements by the constraints of their own system,
+// like the registry clients do for the systems that deps.dev/util/resolve does not define.
+type testClient struct {
+	*resolve.LocalClient
+}
+
+func (c testClient) MatchingVersions(ctx context.Context, vk resolve.VersionKey) ([]resolve.Version, error) {
+	vers, err := c.Versions(ctx, vk.PackageKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return util.MatchRequirement(vk, vers), nil
+}
+
+// newTestClient creates a client for a test registry containing the packages.
+func newTestClient(sys resolve.System, pkgs []testPackage) testClient {
+	cl := resolve.NewLocalClient()
+	for _, p := range pkgs {
+		cl.AddVersion(resolve.Version{
+			VersionKey: resolve.VersionKey{
+				PackageKey:  resolve.PackageKey{System: sys, Name: p.name},
+				Version:     p.version,
+				VersionType: resolve.Concrete,
+			},
+		}, p.reqs)
+	}
+
+	return testClient{cl}
+}
+
+// graphLines describes each edge and node error in a resolved graph,
+// e.g. `root@1.0.0 -> foo@1.2.3 "^1.0.0"` and `root@1.0.0 ! bar "^9.0.0": no version matches`.
+func graphLines(g *resolve.Graph) []string {
+	var lines []string
+	for _, e := range g.Edges {
+		from := g.Nodes[e.From].Version
+		to := g.Nodes[e.To].Version
+		lines = append(lines, fmt.Sprintf("%s@%s -> %s@%s %q", from.Name, from.Version, to.Name, to.Version, e.Requirement))
+	}
+	for _, n := range g.Nodes {
+		for _, e := range n.Errors {
+			lines = append(lines, fmt.Sprintf("%s@%s ! %s %q: %s", n.Version.Name, n.Version.Version, e.Req.Name, e.Req.Version, e.Error))
+		}
+	}
+
+	return lines
+}
+
+func rootKey(sys resolve.System, pkgs []testPackage) resolve.VersionKey {
+	return resolve.VersionKey{
+		PackageKey:  resolve.PackageKey{System: sys, Name: pkgs[0].name},
+		Version:     pkgs[0].version,
+		VersionType: resolve.Concrete,
+	}
+}
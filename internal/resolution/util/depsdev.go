@@ -9,6 +9,10 @@ import (
 var OSVEcosystem = map[resolve.System]models.Ecosystem{
 	resolve.NPM:   models.EcosystemNPM,
 	resolve.Maven: models.EcosystemMaven,
+	PyPI:          models.EcosystemPyPI,
+	Go:            models.EcosystemGo,
+	Cargo:         models.EcosystemCratesIO,
+	NuGet:         models.EcosystemNuGet,
 }
 
 func VKToPackageDetails(vk resolve.VersionKey) lockfile.PackageDetails {
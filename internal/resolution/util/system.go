@@ -0,0 +1,60 @@
+package util
+
+import (
+	pb "deps.dev/api/v3alpha"
+	"deps.dev/util/resolve"
+	"deps.dev/util/semver"
+)
+
+// The systems that deps.dev/util/resolve does not define, which have the same values
+// as their systems in the deps.dev API so that they are compatible with its clients
+const (
+	Go    = resolve.System(pb.System_GO)
+	Cargo = resolve.System(pb.System_CARGO)
+	PyPI  = resolve.System(pb.System_PYPI)
+	NuGet = resolve.System(pb.System_NUGET)
+)
+
+// Semver returns the semver.System that the versions of the system are parsed and compared with,
+// which resolve.System.Semver falls back to semver.DefaultSystem for with the systems defined here
+func Semver(sys resolve.System) semver.System {
+	switch sys {
+	case Go:
+		return semver.Go
+	case Cargo:
+		return semver.Cargo
+	case PyPI:
+		return semver.PyPI
+	case NuGet:
+		return semver.NuGet
+	}
+
+	return sys.Semver()
+}
+
+// MatchRequirement returns the versions that match the requirement, like resolve.MatchRequirement,
+// matching the requirements of the systems defined here by the constraints of their own system
+func MatchRequirement(req resolve.VersionKey, versions []resolve.Version) []resolve.Version {
+	switch req.System {
+	case Go, Cargo, PyPI, NuGet:
+	default:
+		return resolve.MatchRequirement(req, versions)
+	}
+
+	constraint, err := Semver(req.System).ParseConstraint(req.Version)
+	matches := make([]resolve.Version, 0, len(versions))
+
+	for _, v := range versions {
+		// requirements that are not constraints are matched by their exact version
+		if err != nil && req.Version != v.Version {
+			continue
+		}
+		if err == nil && !constraint.Match(v.Version) {
+			continue
+		}
+
+		matches = append(matches, v)
+	}
+
+	return matches
+}
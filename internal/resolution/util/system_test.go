@@ -0,0 +1,82 @@
+package util_test
+
+import (
+	"reflect"
+	"testing"
+
+	"deps.dev/util/resolve"
+	"github.com/google/osv-scanner/internal/resolution/util"
+)
+
+func TestMatchRequirement(t *testing.T) {
+	t.Parallel()
+
+	versions := func(sys resolve.System, vers ...string) []resolve.Version {
+		result := make([]resolve.Version, 0, len(vers))
+		for _, v := range vers {
+			result = append(result, resolve.Version{VersionKey: resolve.VersionKey{
+				PackageKey:  resolve.PackageKey{System: sys, Name: "pkg"},
+				Version:     v,
+				VersionType: resolve.Concrete,
+			}})
+		}
+
+		return result
+	}
+
+	tests := []struct {
+		name     string
+		sys      resolve.System
+		req      string
+		versions []string
+		want     []string
+	}{
+		{
+			name:     "cargo caret requirement",
+			sys:      util.Cargo,
+			req:      "1.2",
+			versions: []string{"1.1.0", "1.2.0", "1.3.0", "2.0.0"},
+			want:     []string{"1.2.0", "1.3.0"},
+		},
+		{
+			name:     "nuget range",
+			sys:      util.NuGet,
+			req:      "[2.0.0,3.0.0)",
+			versions: []string{"1.5.0", "2.0.0", "2.10.0", "3.0.0"},
+			want:     []string{"2.0.0", "2.10.0"},
+		},
+		{
+			name:     "pypi specifier",
+			sys:      util.PyPI,
+			req:      ">=2.0,<3",
+			versions: []string{"1.9", "2.0", "2.31.0", "3.0.0"},
+			want:     []string{"2.0", "2.31.0"},
+		},
+		{
+			name:     "npm is matched by deps.dev",
+			sys:      resolve.NPM,
+			req:      "^1.2.0",
+			versions: []string{"1.1.0", "1.2.0", "1.3.0", "2.0.0"},
+			want:     []string{"1.2.0", "1.3.0"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := resolve.VersionKey{
+				PackageKey:  resolve.PackageKey{System: tt.sys, Name: "pkg"},
+				Version:     tt.req,
+				VersionType: resolve.Requirement,
+			}
+			got := []string{}
+			for _, v := range util.MatchRequirement(req, versions(tt.sys, tt.versions...)) {
+				got = append(got, v.Version)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MatchRequirement(%q) = %v, want %v", tt.req, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package sbom
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+// inTotoPayloadType is the payload type of DSSE envelopes that contain an in-toto statement
+const inTotoPayloadType = "application/vnd.in-toto+json"
+
+// ErrAttestationNotVerified is returned when none of the signatures of an attestation
+// can be verified with the key that it is expected to be signed by
+var ErrAttestationNotVerified = errors.New("attestation signature could not be verified")
+
+type dsseSignature struct {
+	KeyID string `json:"keyid"`
+	Sig   string `json:"sig"`
+}
+
+// dsseEnvelope is a Dead Simple Signing Envelope, which is how cosign and
+// other in-toto tools sign attestations
+type dsseEnvelope struct {
+	PayloadType string          `json:"payloadType"`
+	Payload     string          `json:"payload"`
+	Signatures  []dsseSignature `json:"signatures"`
+}
+
+type inTotoSubject struct {
+	Name   string            `json:"name"`
+	Digest map[string]string `json:"digest"`
+}
+
+type inTotoStatement struct {
+	Subject       []inTotoSubject `json:"subject"`
+	PredicateType string          `json:"predicateType"`
+	Predicate     json.RawMessage `json:"predicate"`
+}
+
+// Attestation is an in-toto attestation of an SBOM, whose signature has been verified
+type Attestation struct {
+	// PredicateType is the type of the SBOM, such as "https://cyclonedx.org/bom"
+	PredicateType string
+	// SBOM is the SBOM that is attested to, which is the predicate of the attestation
+	SBOM []byte
+	// Subjects are the digests of what the SBOM is of, like "sha256:..." for an image
+	Subjects []string
+}
+
+// LoadPublicKey loads a PEM encoded public key, such as one created by `cosign generate-key-pair`
+func LoadPublicKey(path string) (crypto.PublicKey, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return nil, fmt.Errorf("%s is not a PEM encoded public key", path)
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key in %s: %w", path, err)
+	}
+
+	return key, nil
+}
+
+// preAuthEncoding returns the message that is signed for the payload of a DSSE envelope,
+// see https://github.com/secure-systems-lab/dsse/blob/master/protocol.md
+func preAuthEncoding(payloadType string, payload []byte) []byte {
+	return []byte(fmt.Sprintf("DSSEv1 %d %s %d %s", len(payloadType), payloadType, len(payload), payload))
+}
+
+// verifySignature checks that the signature of the message was made by the private key of the
+// public key, using SHA-256 as the hash for the kinds of keys that sign a digest of the message
+func verifySignature(key crypto.PublicKey, message, sig []byte) bool {
+	digest := sha256.Sum256(message)
+
+	switch k := key.(type) {
+	case *ecdsa.PublicKey:
+		return ecdsa.VerifyASN1(k, digest[:], sig)
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(k, crypto.SHA256, digest[:], sig) == nil
+	case ed25519.PublicKey:
+		return ed25519.Verify(k, message, sig)
+	}
+
+	return false
+}
+
+// ReadAttestation reads an in-toto attestation of an SBOM that is signed in a DSSE envelope,
+// returning the SBOM if the envelope has a signature that was made by the key
+func ReadAttestation(r io.Reader, key crypto.PublicKey) (*Attestation, error) {
+	var envelope dsseEnvelope
+
+	// Attestations downloaded with cosign are one envelope per line, of which the first is used
+	if err := json.NewDecoder(r).Decode(&envelope); err != nil {
+		return nil, fmt.Errorf("failed to parse attestation: %w", err)
+	}
+
+	if envelope.PayloadType != inTotoPayloadType {
+		return nil, fmt.Errorf("attestation has a payload of type %q, but expected %q", envelope.PayloadType, inTotoPayloadType)
+	}
+
+	payload, err := base64.StdEncoding.DecodeString(envelope.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode payload of attestation: %w", err)
+	}
+
+	message := preAuthEncoding(envelope.PayloadType, payload)
+	verified := false
+
+	for _, signature := range envelope.Signatures {
+		sig, err := base64.StdEncoding.DecodeString(signature.Sig)
+		if err != nil {
+			continue
+		}
+
+		if verifySignature(key, message, sig) {
+			verified = true
+			break
+		}
+	}
+
+	if !verified {
+		return nil, ErrAttestationNotVerified
+	}
+
+	var statement inTotoStatement
+	if err := json.Unmarshal(payload, &statement); err != nil {
+		return nil, fmt.Errorf("failed to parse statement of attestation: %w", err)
+	}
+
+	if len(statement.Predicate) == 0 {
+		return nil, errors.New("attestation does not have a predicate")
+	}
+
+	var subjects []string
+	for _, subject := range statement.Subject {
+		for algorithm, digest := range subject.Digest {
+			subjects = append(subjects, algorithm+":"+digest)
+		}
+	}
+	sort.Strings(subjects)
+
+	return &Attestation{
+		PredicateType: statement.PredicateType,
+		SBOM:          statement.Predicate,
+		Subjects:      subjects,
+	}, nil
+}
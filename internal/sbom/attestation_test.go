@@ -0,0 +1,83 @@
+package sbom_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/internal/sbom"
+)
+
+func readAttestation(t *testing.T, attestationFile string, keyFile string) (*sbom.Attestation, error) {
+	t.Helper()
+
+	key, err := sbom.LoadPublicKey(filepath.Join("fixtures", "attestation", keyFile))
+	if err != nil {
+		t.Fatalf("Failed to load key: %v", err)
+	}
+
+	f, err := os.Open(filepath.Join("fixtures", attestationFile))
+	if err != nil {
+		t.Fatalf("Failed to read fixture file: %v", err)
+	}
+	defer f.Close()
+
+	return sbom.ReadAttestation(f, key)
+}
+
+func TestReadAttestation(t *testing.T) {
+	t.Parallel()
+
+	attestation, err := readAttestation(t, "attestation/cyclonedx.intoto.jsonl", "cosign.pub")
+	if err != nil {
+		t.Fatalf("ReadAttestation() returned an error: %v", err)
+	}
+
+	if attestation.PredicateType != "https://cyclonedx.org/bom" {
+		t.Errorf("ReadAttestation() PredicateType = %s, want https://cyclonedx.org/bom", attestation.PredicateType)
+	}
+
+	wantSubjects := []string{"sha256:e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"}
+	if diff := cmp.Diff(wantSubjects, attestation.Subjects); diff != "" {
+		t.Errorf("ReadAttestation() Subjects (-want, +got):\n%s", diff)
+	}
+
+	got := []sbom.Identifier{}
+	err = (&sbom.CycloneDX{}).GetPackages(bytes.NewReader(attestation.SBOM), func(id sbom.Identifier) error {
+		got = append(got, id)
+		return nil
+	})
+	if err != nil {
+		t.Errorf("GetPackages returned an error: %v", err)
+	}
+
+	want := []sbom.Identifier{
+		{PURL: "pkg:maven/org.hdrhistogram/HdrHistogram@2.1.12"},
+		{PURL: "pkg:maven/org.apache.logging.log4j/log4j-core@2.16.0"},
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("GetPackages() returned an unexpected result (-want, +got):\n%s", diff)
+	}
+}
+
+func TestReadAttestation_WrongKey(t *testing.T) {
+	t.Parallel()
+
+	_, err := readAttestation(t, "attestation/cyclonedx.intoto.jsonl", "other.pub")
+	if !errors.Is(err, sbom.ErrAttestationNotVerified) {
+		t.Errorf("ReadAttestation() error = %v, want %v", err, sbom.ErrAttestationNotVerified)
+	}
+}
+
+func TestReadAttestation_NotAttestation(t *testing.T) {
+	t.Parallel()
+
+	_, err := readAttestation(t, "cyclonedx.json", "cosign.pub")
+	if err == nil {
+		t.Errorf("ReadAttestation() did not return an error for an SBOM that is not an attestation")
+	}
+}
@@ -58,19 +58,33 @@ func (c *CycloneDX) MatchesRecognizedFileNames(path string) bool {
 	return false
 }
 
-func (c *CycloneDX) enumerateComponents(components []cyclonedx.Component, callback func(Identifier) error) error {
+func (c *CycloneDX) enumerateComponents(bom *cyclonedx.BOM, components []cyclonedx.Component, scope cyclonedx.Scope, callback func(Identifier) error) error {
 	for _, component := range components {
+		// Components that do not have a scope of their own are part of an assembly
+		// that is only included in the scope of the component that they are in.
+		componentScope := scope
+		if component.Scope != "" {
+			componentScope = component.Scope
+		}
+
 		if component.PackageURL != "" {
-			err := callback(Identifier{
-				PURL: component.PackageURL,
-			})
-			if err != nil {
+			id := Identifier{
+				PURL:  component.PackageURL,
+				Scope: string(componentScope),
+			}
+
+			// Components can only be linked to if both they and the BOM can be referenced
+			if link, err := cyclonedx.NewBOMLink(bom.SerialNumber, bom.Version, component); err == nil {
+				id.BOMLink = link.String()
+			}
+
+			if err := callback(id); err != nil {
 				return err
 			}
 		}
 		// Components can have components, so enumerate them recursively.
 		if component.Components != nil {
-			err := c.enumerateComponents(*component.Components, callback)
+			err := c.enumerateComponents(bom, *component.Components, componentScope, callback)
 			if err != nil {
 				return err
 			}
@@ -81,11 +95,20 @@ func (c *CycloneDX) enumerateComponents(components []cyclonedx.Component, callba
 }
 
 func (c *CycloneDX) enumeratePackages(bom *cyclonedx.BOM, callback func(Identifier) error) error {
+	// The component that the BOM describes is not a package itself,
+	// but it can be an assembly of the packages that it is made of.
+	if bom.Metadata != nil && bom.Metadata.Component != nil && bom.Metadata.Component.Components != nil {
+		err := c.enumerateComponents(bom, *bom.Metadata.Component.Components, bom.Metadata.Component.Scope, callback)
+		if err != nil {
+			return err
+		}
+	}
+
 	if bom.Components == nil {
 		return nil
 	}
 
-	return c.enumerateComponents(*bom.Components, callback)
+	return c.enumerateComponents(bom, *bom.Components, "", callback)
 }
 
 func (c *CycloneDX) GetPackages(r io.ReadSeeker, callback func(Identifier) error) error {
@@ -48,6 +48,31 @@ func TestCycloneDXGetPackages(t *testing.T) {
 				{PURL: "pkg:maven/org.apache.logging.log4j/log4j-core@2.16.0"},
 			},
 		},
+		{
+			bomFile: "cyclonedx-nested.json",
+			identifiers: []sbom.Identifier{
+				{PURL: "pkg:apk/alpine/busybox@1.36.1-r2?arch=x86_64&distro=alpine-3.18.4"},
+				{PURL: "pkg:npm/express@4.18.2"},
+				{PURL: "pkg:npm/jest@29.7.0", Scope: "optional"},
+				{PURL: "pkg:npm/jest-cli@29.7.0", Scope: "optional"},
+				{PURL: "pkg:npm/lodash@4.17.21", Scope: "required"},
+				{PURL: "pkg:npm/left-pad@1.3.0", Scope: "excluded"},
+			},
+		},
+		{
+			bomFile: "cyclonedx-linked.json",
+			identifiers: []sbom.Identifier{
+				{
+					PURL:    "pkg:npm/express@4.18.2",
+					BOMLink: "urn:cdx:3e671687-395b-41f5-a30f-a58921a69b79/2#pkg%3Anpm%2Fexpress%404.18.2",
+				},
+				{
+					PURL:    "pkg:npm/lodash@4.17.21",
+					BOMLink: "urn:cdx:3e671687-395b-41f5-a30f-a58921a69b79/2#lodash",
+				},
+				{PURL: "pkg:npm/left-pad@1.3.0"},
+			},
+		},
 		{
 			bomFile:     "cyclonedx-empty.json",
 			identifiers: []sbom.Identifier{},
@@ -9,6 +9,15 @@ import (
 // Identifier is the identifier extracted from the SBOM.
 type Identifier struct {
 	PURL string
+	// CPE is the Common Platform Enumeration name of the package, if the SBOM has one,
+	// which cannot be scanned as OSV does not identify packages by their CPE
+	CPE string
+	// Scope is the scope of the package in the SBOM, such as "optional",
+	// or empty if the SBOM does not specify one
+	Scope string
+	// BOMLink is a CycloneDX BOM-Link to the component of the package in the SBOM,
+	// or empty if the SBOM does not have the serial number that is needed to link to it
+	BOMLink string
 }
 
 // Reader is an interface for all SBOM providers.
@@ -23,6 +32,7 @@ var (
 	Providers = []Reader{
 		&SPDX{},
 		&CycloneDX{},
+		&SWID{},
 	}
 )
 
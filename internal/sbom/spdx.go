@@ -68,6 +68,18 @@ func (s *SPDX) enumeratePackages(doc *v2_3.Document, callback func(Identifier) e
 func (s *SPDX) GetPackages(r io.ReadSeeker, callback func(Identifier) error) error {
 	//nolint:prealloc // Not sure how many there will be in advance.
 	var errs []error
+
+	// SPDX 3 is serialized as JSON-LD, which is not supported by the loaders of SPDX 2
+	_, err := r.Seek(0, io.SeekStart)
+	if err != nil {
+		return fmt.Errorf("failed to seek to start of file: %w", err)
+	}
+	doc3, err := readSPDX3(r)
+	if err == nil {
+		return s.enumerateSPDX3Packages(doc3, callback)
+	}
+	errs = append(errs, fmt.Errorf("failed trying json-ld: %w", err))
+
 	for _, loader := range spdxLoaders {
 		_, err := r.Seek(0, io.SeekStart)
 		if err != nil {
@@ -0,0 +1,81 @@
+package sbom
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+)
+
+// spdx3ExternalIdentifier is an identifier of an element that is defined outside of SPDX
+type spdx3ExternalIdentifier struct {
+	Type       string `json:"externalIdentifierType"`
+	Identifier string `json:"identifier"`
+}
+
+// spdx3Element is an element of the graph of an SPDX 3 document, of which only the
+// properties of packages from the software profile are needed
+type spdx3Element struct {
+	Type                string                    `json:"type"`
+	PackageURL          string                    `json:"software_packageUrl"`
+	ExternalIdentifiers []spdx3ExternalIdentifier `json:"externalIdentifier"`
+}
+
+// spdx3Document is an SPDX 3 document that has been serialized as JSON-LD
+type spdx3Document struct {
+	Context json.RawMessage `json:"@context"`
+	Graph   []spdx3Element  `json:"@graph"`
+}
+
+var errNotSPDX3 = errors.New("not an SPDX 3 document")
+
+func readSPDX3(r io.Reader) (*spdx3Document, error) {
+	var doc spdx3Document
+
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	// the context can be either a single URL or a list of them
+	if !strings.Contains(string(doc.Context), "spdx.org/rdf/3.") {
+		return nil, errNotSPDX3
+	}
+
+	return &doc, nil
+}
+
+// enumerateSPDX3Packages calls the callback with the package URL and CPE of each
+// package in the document, preferring the package URL that is a property of the
+// package over any that are external identifiers of it
+func (s *SPDX) enumerateSPDX3Packages(doc *spdx3Document, callback func(Identifier) error) error {
+	for _, element := range doc.Graph {
+		if element.Type != "software_Package" {
+			continue
+		}
+
+		id := Identifier{PURL: element.PackageURL}
+
+		for _, ext := range element.ExternalIdentifiers {
+			switch ext.Type {
+			case "packageUrl":
+				if id.PURL == "" {
+					id.PURL = ext.Identifier
+				}
+			case "cpe23", "cpe22":
+				if id.CPE == "" {
+					id.CPE = ext.Identifier
+				}
+			}
+		}
+
+		if id.PURL == "" && id.CPE == "" {
+			continue
+		}
+
+		if err := callback(id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,102 @@
+package sbom
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/package-url/packageurl-go"
+)
+
+// SWID reads ISO/IEC 19770-2 software identification tags, which describe a single
+// piece of software each, so an inventory is usually a directory of them
+type SWID struct{}
+
+const swidNamespace = "http://standards.iso.org/iso/19770/-2/2015/schema.xsd"
+
+type swidEntity struct {
+	RegID string `xml:"regid,attr"`
+	Role  string `xml:"role,attr"`
+}
+
+type swidLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+}
+
+type swidSoftwareIdentity struct {
+	XMLName      xml.Name     `xml:"SoftwareIdentity"`
+	Name         string       `xml:"name,attr"`
+	TagID        string       `xml:"tagId,attr"`
+	Version      string       `xml:"version,attr"`
+	Supplemental bool         `xml:"supplemental,attr"`
+	Entities     []swidEntity `xml:"Entity"`
+	Links        []swidLink   `xml:"Link"`
+}
+
+var errNotSWID = errors.New("not a SWID tag")
+
+func (s *SWID) Name() string {
+	return "SWID"
+}
+
+func (s *SWID) MatchesRecognizedFileNames(path string) bool {
+	// SWID tags are stored in files with the .swidtag extension, see section 8.5.2 of ISO/IEC 19770-2:2015
+	return strings.EqualFold(filepath.Ext(path), ".swidtag")
+}
+
+// swidPURL returns the Package URL of the software that a tag identifies, which is either
+// linked to by the tag or is its tag id. Otherwise, the Package URL is of the swid type,
+// which identifies the software by the tag id, product name, and version of its tag.
+func swidPURL(tag swidSoftwareIdentity) string {
+	for _, link := range tag.Links {
+		if strings.HasPrefix(link.Href, "pkg:") {
+			return link.Href
+		}
+	}
+
+	if strings.HasPrefix(tag.TagID, "pkg:") {
+		return tag.TagID
+	}
+
+	// the namespace of the swid type is the regid of the creator of the tag
+	namespace := ""
+	for _, entity := range tag.Entities {
+		if strings.Contains(entity.Role, "tagCreator") {
+			namespace = entity.RegID
+			break
+		}
+	}
+
+	qualifiers := packageurl.QualifiersFromMap(map[string]string{"tag_id": tag.TagID})
+
+	return packageurl.NewPackageURL("swid", namespace, tag.Name, tag.Version, qualifiers, "").ToString()
+}
+
+func (s *SWID) GetPackages(r io.ReadSeeker, callback func(Identifier) error) error {
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to seek to start of file: %w", err)
+	}
+
+	var tag swidSoftwareIdentity
+	err := xml.NewDecoder(r).Decode(&tag)
+	if err == nil && tag.XMLName.Space != swidNamespace {
+		err = errNotSWID
+	}
+	if err != nil {
+		return InvalidFormatError{
+			Msg:  "failed to parse SWID",
+			Errs: []error{err},
+		}
+	}
+
+	// Supplemental tags only add details to the tags of software that is described elsewhere
+	if tag.Supplemental || tag.TagID == "" {
+		return nil
+	}
+
+	return callback(Identifier{PURL: swidPURL(tag)})
+}
@@ -0,0 +1,78 @@
+package sbom_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/internal/sbom"
+)
+
+func TestSWIDGetPackages(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		tagFile     string
+		identifiers []sbom.Identifier
+	}{
+		{
+			tagFile: "openssl.swidtag",
+			identifiers: []sbom.Identifier{
+				{PURL: "pkg:deb/debian/openssl@3.0.11-1~deb12u2?distro=debian-12"},
+			},
+		},
+		{
+			tagFile: "enterprise-server.swidtag",
+			identifiers: []sbom.Identifier{
+				{PURL: "pkg:swid/acme.example.com/Enterprise+Server@1.0.0?tag_id=75b8c285-fa7b-485b-b199-4745e3004d0d"},
+			},
+		},
+		{
+			tagFile:     "supplemental.swidtag",
+			identifiers: []sbom.Identifier{},
+		},
+	}
+
+	for _, tt := range tests {
+		f, err := os.Open(filepath.Join("fixtures", tt.tagFile))
+		if err != nil {
+			t.Fatalf("Failed to read fixture file: %v", err)
+		}
+		defer f.Close()
+
+		got := []sbom.Identifier{}
+		callback := func(id sbom.Identifier) error {
+			got = append(got, id)
+			return nil
+		}
+
+		swid := &sbom.SWID{}
+		err = swid.GetPackages(f, callback)
+		if err != nil {
+			t.Errorf("GetPackages returned an error: %v", err)
+		}
+
+		if diff := cmp.Diff(tt.identifiers, got); diff != "" {
+			t.Errorf("GetPackages(%s) returned an unexpected result (-want, +got):\n%s", tt.tagFile, diff)
+		}
+	}
+}
+
+func TestSWIDGetPackages_NotSWID(t *testing.T) {
+	t.Parallel()
+
+	f, err := os.Open(filepath.Join("fixtures", "cyclonedx.json"))
+	if err != nil {
+		t.Fatalf("Failed to read fixture file: %v", err)
+	}
+	defer f.Close()
+
+	swid := &sbom.SWID{}
+	err = swid.GetPackages(f, func(sbom.Identifier) error { return nil })
+
+	var formatErr sbom.InvalidFormatError
+	if !errors.As(err, &formatErr) {
+		t.Errorf("GetPackages() error = %v, want an InvalidFormatError", err)
+	}
+}
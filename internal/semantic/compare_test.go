@@ -226,6 +226,14 @@ func TestVersion_Compare_Ecosystems(t *testing.T) {
 			name: "CRAN",
 			file: "cran-versions-generated.txt",
 		},
+		{
+			name: "Red Hat",
+			file: "redhat-versions.txt",
+		},
+		{
+			name: "openSUSE",
+			file: "redhat-versions.txt",
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
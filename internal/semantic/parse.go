@@ -36,6 +36,8 @@ func Parse(str string, ecosystem models.Ecosystem) (Version, error) {
 		return parsePackagistVersion(str), nil
 	case "Go":
 		return parseSemverVersion(str), nil
+	case "Hackage":
+		return parseSemverVersion(str), nil
 	case "Hex":
 		return parseSemverVersion(str), nil
 	case "Maven":
@@ -46,8 +48,40 @@ func Parse(str string, ecosystem models.Ecosystem) (Version, error) {
 		return parseSemverVersion(str), nil
 	case "ConanCenter":
 		return parseSemverVersion(str), nil
+	case "vcpkg":
+		return parseSemverVersion(str), nil
+	case "Bazel":
+		return parseSemverVersion(str), nil
+	case "Terraform":
+		return parseSemverVersion(str), nil
+	case "Helm":
+		return parseSemverVersion(str), nil
+	case "JSR":
+		return parseSemverVersion(str), nil
+	case "deno.land":
+		return parseSemverVersion(str), nil
 	case "CRAN":
 		return parseCRANVersion(str), nil
+	case "Bioconductor":
+		return parseCRANVersion(str), nil
+	case "Red Hat":
+		return parseRedHatVersion(str), nil
+	case "AlmaLinux":
+		return parseRedHatVersion(str), nil
+	case "Rocky Linux":
+		return parseRedHatVersion(str), nil
+	case "Fedora":
+		return parseRedHatVersion(str), nil
+	case "openSUSE":
+		return parseRedHatVersion(str), nil
+	case "SUSE":
+		return parseRedHatVersion(str), nil
+	case "Yocto":
+		return parseDebianVersion(str), nil
+	case "OpenWrt":
+		return parseDebianVersion(str), nil
+	case "Bitnami":
+		return parseSemverVersion(str), nil
 	}
 
 	return nil, fmt.Errorf("%w %s", ErrUnsupportedEcosystem, ecosystem)
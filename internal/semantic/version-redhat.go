@@ -0,0 +1,182 @@
+package semantic
+
+import (
+	"math/big"
+	"strings"
+)
+
+func isRedHatAlnum(c byte) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isRedHatDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// fetchRedHatChar returns the character at the start of s, or 0 if s is empty
+func fetchRedHatChar(s string) byte {
+	if s == "" {
+		return 0
+	}
+
+	return s[0]
+}
+
+// splitRedHatSegment splits the segment of digits or of letters at the start of s
+// from the rest of it, based on if the segment is to be numeric or not
+func splitRedHatSegment(s string, numeric bool) (string, string) {
+	i := 0
+
+	for i < len(s) && isRedHatAlnum(s[i]) && isRedHatDigit(s[i]) == numeric {
+		i++
+	}
+
+	return s[:i], s[i:]
+}
+
+// compareRedHatVersions compares two versions or releases the same way as rpmvercmp
+//
+// based off: https://github.com/rpm-software-management/rpm/blob/master/rpmio/rpmvercmp.cc
+func compareRedHatVersions(a, b string) int {
+	if a == b {
+		return 0
+	}
+
+	// everything other than letters, digits, tildes, and carets only separates segments
+	isSeparator := func(r rune) bool {
+		return (r >= 128 || !isRedHatAlnum(byte(r))) && r != '~' && r != '^'
+	}
+
+	for a != "" || b != "" {
+		a = strings.TrimLeftFunc(a, isSeparator)
+		b = strings.TrimLeftFunc(b, isSeparator)
+
+		ac, bc := fetchRedHatChar(a), fetchRedHatChar(b)
+
+		// the tilde sorts before everything else, even the end of the version
+		if ac == '~' || bc == '~' {
+			if ac != '~' {
+				return +1
+			}
+			if bc != '~' {
+				return -1
+			}
+
+			a, b = a[1:], b[1:]
+
+			continue
+		}
+
+		// the caret is like the tilde, except that it sorts after the end of the version
+		if ac == '^' || bc == '^' {
+			if a == "" {
+				return -1
+			}
+			if b == "" {
+				return +1
+			}
+			if ac != '^' {
+				return +1
+			}
+			if bc != '^' {
+				return -1
+			}
+
+			a, b = a[1:], b[1:]
+
+			continue
+		}
+
+		if a == "" || b == "" {
+			break
+		}
+
+		numeric := isRedHatDigit(ac)
+
+		var as, bs string
+
+		as, a = splitRedHatSegment(a, numeric)
+		bs, b = splitRedHatSegment(b, numeric)
+
+		// segments that are numeric are newer than those that are alphabetic
+		if bs == "" {
+			if numeric {
+				return +1
+			}
+
+			return -1
+		}
+
+		if numeric {
+			an, _ := new(big.Int).SetString(as, 10)
+			bn, _ := new(big.Int).SetString(bs, 10)
+
+			if diff := an.Cmp(bn); diff != 0 {
+				return diff
+			}
+
+			continue
+		}
+
+		if diff := strings.Compare(as, bs); diff != 0 {
+			return diff
+		}
+	}
+
+	if a == "" && b == "" {
+		return 0
+	}
+
+	if a != "" {
+		return +1
+	}
+
+	return -1
+}
+
+// RedHatVersion is the representation of a version of an RPM package, which is
+// made up of an optional epoch, the version, and the release e.g. "1:2.4.57-5.el9"
+type RedHatVersion struct {
+	epoch   *big.Int
+	version string
+	release string
+}
+
+func (v RedHatVersion) Compare(w RedHatVersion) int {
+	if diff := v.epoch.Cmp(w.epoch); diff != 0 {
+		return diff
+	}
+	if diff := compareRedHatVersions(v.version, w.version); diff != 0 {
+		return diff
+	}
+	if diff := compareRedHatVersions(v.release, w.release); diff != 0 {
+		return diff
+	}
+
+	return 0
+}
+
+func (v RedHatVersion) CompareStr(str string) int {
+	return v.Compare(parseRedHatVersion(str))
+}
+
+func parseRedHatVersion(str string) RedHatVersion {
+	var version, release string
+
+	str = strings.TrimSpace(str)
+	epoch := big.NewInt(0)
+
+	if strings.Contains(str, ":") {
+		var e string
+		e, str = splitAround(str, ":", false)
+
+		// an epoch that is not a number is treated as being missing, like rpm does
+		if n, ok := new(big.Int).SetString(e, 10); ok {
+			epoch = n
+		}
+	}
+
+	version, release = splitAround(str, "-", true)
+
+	return RedHatVersion{epoch, version, release}
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"slices"
 	"sort"
+	"strings"
 
 	"github.com/google/osv-scanner/internal/semantic"
 	"github.com/google/osv-scanner/pkg/lockfile"
@@ -118,8 +119,28 @@ func AffectsEcosystem(v models.Vulnerability, ecosystem lockfile.Ecosystem) bool
 	return false
 }
 
+// affectsGitTag returns whether the tag of the repository that the package is in the GIT ecosystem is
+// one of the versions of the affected, which lists the tags between the commits of its ranges of the repository
+func affectsGitTag(affected models.Affected, pkg lockfile.PackageDetails) bool {
+	for _, r := range affected.Ranges {
+		if r.Type == models.RangeGit && strings.TrimSuffix(r.Repo, ".git") == pkg.Name {
+			return slices.Contains(affected.Versions, pkg.Version)
+		}
+	}
+
+	return false
+}
+
 func IsAffected(v models.Vulnerability, pkg lockfile.PackageDetails) bool {
 	for _, affected := range v.Affected {
+		if pkg.Ecosystem == lockfile.GitEcosystem {
+			if affectsGitTag(affected, pkg) {
+				return true
+			}
+
+			continue
+		}
+
 		if string(affected.Package.Ecosystem) == string(pkg.Ecosystem) &&
 			affected.Package.Name == pkg.Name {
 			if len(affected.Ranges) == 0 && len(affected.Versions) == 0 {
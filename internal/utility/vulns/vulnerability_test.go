@@ -704,3 +704,45 @@ func TestOSV_IsAffected_OnlyVersions(t *testing.T) {
 	// an empty version should always be treated as affected
 	expectIsAffected(t, vuln, "", true)
 }
+
+func TestOSV_IsAffected_GitTags(t *testing.T) {
+	t.Parallel()
+
+	vuln := buildOSVWithAffected(
+		models.Affected{
+			Ranges: []models.Range{
+				{
+					Type: models.RangeGit,
+					Repo: "https://github.com/madler/zlib.git",
+					Events: []models.Event{
+						{Introduced: "0"},
+						{Fixed: "04f42ceca40f73e2978b50e93806c2a18c1281fc"},
+					},
+				},
+			},
+			Versions: []string{"v1.2.11", "v1.2.12"},
+		},
+	)
+
+	for _, tt := range []struct {
+		name    string
+		version string
+		want    bool
+	}{
+		{name: "https://github.com/madler/zlib", version: "v1.2.12", want: true},
+		{name: "https://github.com/madler/zlib", version: "v1.2.13", want: false},
+		{name: "https://github.com/madler/zlib", version: "", want: false},
+		{name: "https://github.com/curl/curl", version: "v1.2.12", want: false},
+	} {
+		pkg := lockfile.PackageDetails{
+			Name:      tt.name,
+			Version:   tt.version,
+			Ecosystem: lockfile.GitEcosystem,
+			CompareAs: lockfile.GitEcosystem,
+		}
+
+		if got := vulns.IsAffected(vuln, pkg); got != tt.want {
+			t.Errorf("IsAffected(%s@%s) = %v, want %v", tt.name, tt.version, got, tt.want)
+		}
+	}
+}
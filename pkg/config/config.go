@@ -23,8 +23,9 @@ type ConfigManager struct {
 }
 
 type Config struct {
-	IgnoredVulns []IgnoreEntry `toml:"IgnoredVulns"`
-	LoadPath     string        `toml:"LoadPath"`
+	IgnoredVulns   []IgnoreEntry `toml:"IgnoredVulns"`
+	PinnedVersions []PinEntry    `toml:"PinnedVersions"`
+	LoadPath       string        `toml:"LoadPath"`
 }
 
 type IgnoreEntry struct {
@@ -33,6 +34,34 @@ type IgnoreEntry struct {
 	Reason      string    `toml:"reason"`
 }
 
+// PinEntry constrains the versions a package can be changed to during remediation
+type PinEntry struct {
+	Name      string `toml:"name"`
+	Ecosystem string `toml:"ecosystem"` // OSV ecosystem of the package e.g. "npm", or empty to pin the package name in every ecosystem
+	Version   string `toml:"version"`   // Version constraint e.g. "2.x" to stay on 2.x, or "1.4.2" to remain exactly on 1.4.2
+	Reason    string `toml:"reason"`
+}
+
+// PinKey identifies the package a pinned version constraint applies to
+type PinKey struct {
+	Ecosystem string // Empty if the pin applies to every ecosystem
+	Name      string
+}
+
+// PinnedVersionConstraints returns the version constraints of the pinned packages, keyed by ecosystem and package name
+func (c *Config) PinnedVersionConstraints() map[PinKey]string {
+	if len(c.PinnedVersions) == 0 {
+		return nil
+	}
+
+	pins := make(map[PinKey]string, len(c.PinnedVersions))
+	for _, pin := range c.PinnedVersions {
+		pins[PinKey{Ecosystem: pin.Ecosystem, Name: pin.Name}] = pin.Version
+	}
+
+	return pins
+}
+
 func (c *Config) ShouldIgnore(vulnID string) (bool, IgnoreEntry) {
 	index := slices.IndexFunc(c.IgnoredVulns, func(elem IgnoreEntry) bool { return elem.ID == vulnID })
 	if index == -1 {
@@ -191,3 +191,72 @@ func TestConfig_ShouldIgnore(t *testing.T) {
 		})
 	}
 }
+
+func TestConfig_PinnedVersionConstraints(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		config Config
+		want   map[PinKey]string
+	}{
+		{
+			name:   "no pinned versions",
+			config: Config{},
+			want:   nil,
+		},
+		{
+			name: "multiple pinned versions",
+			config: Config{
+				PinnedVersions: []PinEntry{
+					{
+						Name:    "lodash",
+						Version: "4.x",
+					},
+					{
+						Name:    "express",
+						Version: "4.18.2",
+						Reason:  "upgrading breaks the build",
+					},
+				},
+			},
+			want: map[PinKey]string{
+				{Name: "lodash"}:  "4.x",
+				{Name: "express"}: "4.18.2",
+			},
+		},
+		{
+			name: "same name in different ecosystems",
+			config: Config{
+				PinnedVersions: []PinEntry{
+					{
+						Name:      "requests",
+						Ecosystem: "PyPI",
+						Version:   "2.x",
+					},
+					{
+						Name:      "requests",
+						Ecosystem: "npm",
+						Version:   "0.3.0",
+					},
+				},
+			},
+			want: map[PinKey]string{
+				{Ecosystem: "PyPI", Name: "requests"}: "2.x",
+				{Ecosystem: "npm", Name: "requests"}:  "0.3.0",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := tt.config.PinnedVersionConstraints()
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PinnedVersionConstraints() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -3,8 +3,12 @@ package lockfile
 import (
 	"bufio"
 	"fmt"
+	"io"
+	"path/filepath"
 	"sort"
 	"strings"
+
+	"github.com/google/osv-scanner/internal/cachedregexp"
 )
 
 const AlpineEcosystem Ecosystem = "Alpine"
@@ -33,9 +37,46 @@ func groupApkPackageLines(scanner *bufio.Scanner) [][]string {
 	return groups
 }
 
-func parseApkPackageGroup(group []string) PackageDetails {
+// apkInstalledPath is where the Alpine Package Keeper records the packages that
+// are installed, relative to the root of the filesystem
+const apkInstalledPath = "lib/apk/db/installed"
+
+// alpineReleaseEcosystem returns the ecosystem of the Alpine release that the installed
+// database of f is for, such as "Alpine:v3.18", based on the /etc/alpine-release of the
+// filesystem that it is in, or AlpineEcosystem if the release cannot be determined
+func alpineReleaseEcosystem(f DepFile) Ecosystem {
+	if !strings.HasSuffix(filepath.ToSlash(f.Path()), "/"+apkInstalledPath) {
+		return AlpineEcosystem
+	}
+
+	release, err := f.Open("../../../etc/alpine-release")
+
+	if err != nil {
+		return AlpineEcosystem
+	}
+
+	defer release.Close()
+
+	b, err := io.ReadAll(release)
+
+	if err != nil {
+		return AlpineEcosystem
+	}
+
+	// releases from the edge branch have a version like "3.19_alpha20230901",
+	// which are not a release that advisories are published for
+	match := cachedregexp.MustCompile(`^(\d+)\.(\d+)(?:\.\d+)?$`).FindStringSubmatch(strings.TrimSpace(string(b)))
+
+	if match == nil {
+		return AlpineEcosystem
+	}
+
+	return Ecosystem(fmt.Sprintf("%s:v%s.%s", AlpineEcosystem, match[1], match[2]))
+}
+
+func parseApkPackageGroup(group []string, ecosystem Ecosystem) PackageDetails {
 	var pkg = PackageDetails{
-		Ecosystem: AlpineEcosystem,
+		Ecosystem: ecosystem,
 		CompareAs: AlpineEcosystem,
 	}
 
@@ -61,18 +102,25 @@ func ParseApkInstalled(pathToLockfile string) ([]PackageDetails, error) {
 type ApkInstalledExtractor struct{}
 
 func (e ApkInstalledExtractor) ShouldExtract(path string) bool {
-	return path == "/lib/apk/db/installed"
+	path = filepath.ToSlash(path)
+
+	return path == apkInstalledPath || strings.HasSuffix(path, "/"+apkInstalledPath)
 }
 
+// Extract extracts the packages that are installed according to the database of the
+// Alpine Package Keeper, which are reported using the ecosystem of the Alpine release
+// that the filesystem it is in is running when it can be determined
+
 func (e ApkInstalledExtractor) Extract(f DepFile) ([]PackageDetails, error) {
 	scanner := bufio.NewScanner(f)
 
 	packageGroups := groupApkPackageLines(scanner)
+	ecosystem := alpineReleaseEcosystem(f)
 
 	packages := make([]PackageDetails, 0, len(packageGroups))
 
 	for _, group := range packageGroups {
-		pkg := parseApkPackageGroup(group)
+		pkg := parseApkPackageGroup(group, ecosystem)
 
 		if pkg.Name == "" {
 			continue
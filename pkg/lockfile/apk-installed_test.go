@@ -133,3 +133,100 @@ func TestParseApkInstalled_Multiple(t *testing.T) {
 		},
 	})
 }
+
+func TestParseApkInstalled_WithAlpineRelease(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseApkInstalled("fixtures/apk/rootfs/lib/apk/db/installed")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "apk-tools",
+			Version:   "2.12.10-r1",
+			Commit:    "0188f510baadbae393472103427b9c1875117136",
+			Ecosystem: "Alpine:v3.18",
+			CompareAs: lockfile.AlpineEcosystem,
+		},
+	})
+}
+
+func TestParseApkInstalled_WithEdgeAlpineRelease(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseApkInstalled("fixtures/apk/edge-rootfs/lib/apk/db/installed")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "apk-tools",
+			Version:   "2.12.10-r1",
+			Commit:    "0188f510baadbae393472103427b9c1875117136",
+			Ecosystem: lockfile.AlpineEcosystem,
+			CompareAs: lockfile.AlpineEcosystem,
+		},
+	})
+}
+
+func TestApkInstalledExtractor_ShouldExtract(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{
+			name: "",
+			path: "",
+			want: false,
+		},
+		{
+			name: "",
+			path: "installed",
+			want: false,
+		},
+		{
+			name: "",
+			path: "/lib/apk/db/installed",
+			want: true,
+		},
+		{
+			name: "",
+			path: "lib/apk/db/installed",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/rootfs/lib/apk/db/installed",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/rootfs/lib/apk/db/installed/file",
+			want: false,
+		},
+		{
+			name: "",
+			path: "path/to/rootfs/mylib/apk/db/installed",
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e := lockfile.ApkInstalledExtractor{}
+			got := e.ShouldExtract(tt.path)
+			if got != tt.want {
+				t.Errorf("ShouldExtract() - got %v, expected %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package lockfile
+
+import "sort"
+
+// artifactLockfile returns the packages that were found in an artifact, like a binary or an archive,
+// as a lockfile, since artifacts are scanned in the same way as the lockfiles of applications
+func artifactLockfile(path string, parsedAs string, packages []PackageDetails) Lockfile {
+	sort.Slice(packages, func(i, j int) bool {
+		if packages[i].Name == packages[j].Name {
+			return packages[i].Version < packages[j].Version
+		}
+
+		return packages[i].Name < packages[j].Name
+	})
+
+	return Lockfile{
+		FilePath: path,
+		ParsedAs: parsedAs,
+		Packages: packages,
+	}
+}
@@ -0,0 +1,237 @@
+package lockfile
+
+import (
+	"bytes"
+	"debug/elf"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/osv-scanner/internal/cachedregexp"
+)
+
+// GitEcosystem has the advisories of projects that are not published to the registry of an ecosystem,
+// like most C and C++ libraries, which are affected by the tags of their repository rather than versions
+const GitEcosystem Ecosystem = "GIT"
+
+// cLibrary is a C library that is identified by the soname of its shared library, whose version is read
+// from the version string that it embeds, or from the name of its file for libraries whose files are
+// named after their version, like "libxml2.so.2.9.14", with the patterns of each capturing the version
+type cLibrary struct {
+	repo        string
+	soname      string
+	version     string
+	fileVersion string
+	tag         func(version string) string
+}
+
+// underscored returns the version with its dots replaced by underscores, which is how
+// projects like curl and expat name the tags of their releases, like "curl-8_5_0"
+func underscored(version string) string {
+	return strings.ReplaceAll(version, ".", "_")
+}
+
+// cLibraries are the C libraries that are identified by their shared libraries, which are only the ones
+// with the version string of the library for those that are split into several, like libcrypto of OpenSSL
+var cLibraries = []cLibrary{
+	{
+		repo:    "https://github.com/openssl/openssl",
+		soname:  `^libcrypto\.so\.[\d.]+$`,
+		version: `OpenSSL (\d+\.\d+\.\d+[a-z]?) +\d{1,2} [A-Z][a-z]{2} \d{4}`,
+		tag: func(version string) string {
+			if strings.HasPrefix(version, "0.") || strings.HasPrefix(version, "1.") {
+				return "OpenSSL_" + underscored(version)
+			}
+
+			return "openssl-" + version
+		},
+	},
+	{
+		repo:        "https://github.com/madler/zlib",
+		soname:      `^libz\.so\.1(?:\.[\d.]+)?$`,
+		version:     `deflate (\d+\.\d+(?:\.\d+)*) Copyright`,
+		fileVersion: `^libz\.so\.(1\.\d+(?:\.\d+)*)$`,
+		tag:         func(version string) string { return "v" + version },
+	},
+	{
+		repo:    "https://github.com/curl/curl",
+		soname:  `^libcurl(?:-gnutls|-nss)?\.so\.4(?:\.[\d.]+)?$`,
+		version: `libcurl/(\d+\.\d+\.\d+)`,
+		tag:     func(version string) string { return "curl-" + underscored(version) },
+	},
+	{
+		repo:        "https://gitlab.gnome.org/GNOME/libxml2",
+		soname:      `^libxml2\.so\.2(?:\.[\d.]+)?$`,
+		fileVersion: `^libxml2\.so\.(2\.\d+\.\d+)$`,
+		tag:         func(version string) string { return "v" + version },
+	},
+	{
+		repo:    "https://github.com/libexpat/libexpat",
+		soname:  `^libexpat\.so\.1(?:\.[\d.]+)?$`,
+		version: `expat_(\d+\.\d+\.\d+)`,
+		tag:     func(version string) string { return "R_" + underscored(version) },
+	},
+	{
+		repo:    "https://github.com/glennrp/libpng",
+		soname:  `^libpng16\.so\.16(?:\.[\d.]+)?$`,
+		version: `libpng version (1\.6\.\d+)`,
+		tag:     func(version string) string { return "v" + version },
+	},
+	{
+		repo:    "https://github.com/sqlite/sqlite",
+		soname:  `^libsqlite3\.so\.0(?:\.[\d.]+)?$`,
+		version: `\x00(3\.\d+\.\d+)\x00`,
+		tag:     func(version string) string { return "version-" + version },
+	},
+}
+
+// distroLibraryDirs are where distributions install the libraries of their packages
+var distroLibraryDirs = []string{"lib/", "lib64/", "usr/lib/", "usr/lib64/"}
+
+// distroPackageDatabases are where the package managers of distributions keep the
+// packages that they have installed, relative to the root of the filesystem
+var distroPackageDatabases = append(
+	append([]string{apkInstalledPath, dpkgStatusPath, dpkgStatusDir}, rpmDatabasePaths...),
+	opkgStatusPaths...,
+)
+
+// cLibraryOf returns the C library that has the soname, or nil if it is not one that is known
+func cLibraryOf(soname string) *cLibrary {
+	for i, library := range cLibraries {
+		if cachedregexp.MustCompile(library.soname).MatchString(soname) {
+			return &cLibraries[i]
+		}
+	}
+
+	return nil
+}
+
+// isDistroLibrary returns whether the library is installed where the packages of a distribution install
+// their libraries, in a filesystem that has the database of the package manager of the distribution
+func isDistroLibrary(path string) bool {
+	for root := filepath.Dir(path); ; root = filepath.Dir(root) {
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return false
+		}
+
+		for _, dir := range distroLibraryDirs {
+			if !strings.HasPrefix(filepath.ToSlash(rel), dir) {
+				continue
+			}
+
+			for _, database := range distroPackageDatabases {
+				if _, err := os.Stat(filepath.Join(root, filepath.FromSlash(database))); err == nil {
+					return true
+				}
+			}
+		}
+
+		if parent := filepath.Dir(root); parent == root {
+			return false
+		}
+	}
+}
+
+type CLibraryExtractor struct{}
+
+// ShouldExtract returns whether the file is the shared library of a C library that is known, which is
+// only the case for those that are not installed by the package manager of a distribution, as they are
+// scanned as the packages of the distribution, which patch them without changing their version. Symlinks
+// are skipped, as libraries are linked to by their soname, like "libz.so.1", so that each is only reported once
+func (e CLibraryExtractor) ShouldExtract(path string) bool {
+	if cLibraryOf(filepath.Base(path)) == nil {
+		return false
+	}
+
+	info, err := os.Lstat(path)
+	if err == nil && info.Mode()&os.ModeSymlink != 0 {
+		return false
+	}
+
+	f, err := elf.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	return !isDistroLibrary(path)
+}
+
+// elfSoname returns the soname of the shared library, or an empty string if it does not have one
+func elfSoname(f *elf.File) string {
+	sonames, err := f.DynString(elf.DT_SONAME)
+	if err != nil || len(sonames) == 0 {
+		return ""
+	}
+
+	return sonames[0]
+}
+
+// Extract extracts the C library that the shared library is of, which is identified by its soname, or by
+// the name of its file if it does not have one, with the version that it embeds in its version string.
+// Libraries are reported as the tag of the release of their version in their repository, in the GIT
+// ecosystem, which is what the advisories of C and C++ libraries are affected by. Libraries whose
+// version cannot be determined are not reported
+func (e CLibraryExtractor) Extract(f DepFile) ([]PackageDetails, error) {
+	b, err := io.ReadAll(f)
+	if err != nil {
+		return []PackageDetails{}, fmt.Errorf("could not extract from %s: %w", f.Path(), err)
+	}
+
+	file, err := elf.NewFile(bytes.NewReader(b))
+	if err != nil {
+		return []PackageDetails{}, fmt.Errorf("could not extract from %s: %w", f.Path(), err)
+	}
+	defer file.Close()
+
+	name := filepath.Base(f.Path())
+
+	library := cLibraryOf(elfSoname(file))
+	if library == nil {
+		library = cLibraryOf(name)
+	}
+	if library == nil {
+		return []PackageDetails{}, nil
+	}
+
+	var version string
+
+	if library.version != "" {
+		if match := cachedregexp.MustCompile(library.version).FindSubmatch(b); match != nil {
+			version = string(match[1])
+		}
+	}
+
+	if library.fileVersion != "" && version == "" {
+		if match := cachedregexp.MustCompile(library.fileVersion).FindStringSubmatch(name); match != nil {
+			version = match[1]
+		}
+	}
+
+	if version == "" {
+		return []PackageDetails{}, nil
+	}
+
+	return []PackageDetails{{
+		Name:      library.repo,
+		Version:   library.tag(version),
+		Ecosystem: GitEcosystem,
+		CompareAs: GitEcosystem,
+	}}, nil
+}
+
+var _ Extractor = CLibraryExtractor{}
+
+func ParseCLibrary(pathToLibrary string) ([]PackageDetails, error) {
+	return extractFromFile(pathToLibrary, CLibraryExtractor{})
+}
+
+// FromCLibrary attempts to parse the given file as the shared library of a C library
+func FromCLibrary(pathToLibrary string) (Lockfile, error) {
+	packages, err := ParseCLibrary(pathToLibrary)
+
+	return artifactLockfile(pathToLibrary, "c-library", packages), err
+}
@@ -0,0 +1,180 @@
+package lockfile_test
+
+import (
+	"io/fs"
+	"testing"
+
+	"github.com/google/osv-scanner/pkg/lockfile"
+)
+
+func TestCLibraryExtractor_ShouldExtract(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{
+			name: "",
+			path: "",
+			want: false,
+		},
+		{
+			name: "",
+			path: "fixtures/c-library/usr/local/lib/libcrypto.so.3",
+			want: true,
+		},
+		{
+			name: "",
+			path: "fixtures/c-library/opt/app/lib/libcrypto.so.1.1",
+			want: true,
+		},
+		{
+			name: "",
+			path: "fixtures/c-library/usr/local/lib/libz.so.1.2.13",
+			want: true,
+		},
+		{
+			name: "",
+			path: "fixtures/c-library/usr/local/lib/libz.so.1",
+			want: false,
+		},
+		{
+			name: "",
+			path: "fixtures/c-library/usr/local/lib/libz.so.1.2.12",
+			want: false,
+		},
+		{
+			name: "",
+			path: "fixtures/c-library/usr/local/lib/libcurl.so.4.8.0",
+			want: true,
+		},
+		{
+			name: "",
+			path: "fixtures/c-library/opt/app/lib/libz-b8d9c5a1.so.1.3",
+			want: false,
+		},
+		{
+			name: "",
+			path: "fixtures/c-library/distro/usr/lib/x86_64-linux-gnu/libz.so.1.2.13",
+			want: false,
+		},
+		{
+			name: "",
+			path: "fixtures/c-library/distro/usr/local/lib/libz.so.1.2.13",
+			want: true,
+		},
+		{
+			name: "",
+			path: "fixtures/c-library/usr/local/lib/does-not-exist.so.1",
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e := lockfile.CLibraryExtractor{}
+			got := e.ShouldExtract(tt.path)
+			if got != tt.want {
+				t.Errorf("Extract() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCLibrary_FileDoesNotExist(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseCLibrary("fixtures/c-library/usr/local/lib/libz.so.1.2.14")
+
+	expectErrIs(t, err, fs.ErrNotExist)
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseCLibrary_NotELF(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseCLibrary("fixtures/c-library/usr/local/lib/libz.so.1.2.12")
+
+	expectErrContaining(t, err, "could not extract from")
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseCLibrary(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		path string
+		want []lockfile.PackageDetails
+	}{
+		{
+			name: "openssl 3",
+			path: "fixtures/c-library/usr/local/lib/libcrypto.so.3",
+			want: []lockfile.PackageDetails{{Name: "https://github.com/openssl/openssl", Version: "openssl-3.0.13"}},
+		},
+		{
+			name: "openssl 1",
+			path: "fixtures/c-library/opt/app/lib/libcrypto.so.1.1",
+			want: []lockfile.PackageDetails{{Name: "https://github.com/openssl/openssl", Version: "OpenSSL_1_1_1w"}},
+		},
+		{
+			name: "zlib",
+			path: "fixtures/c-library/usr/local/lib/libz.so.1.2.13",
+			want: []lockfile.PackageDetails{{Name: "https://github.com/madler/zlib", Version: "v1.2.13"}},
+		},
+		{
+			name: "zlib without soname",
+			path: "fixtures/c-library/opt/app/lib/libz.so.1.2.11",
+			want: []lockfile.PackageDetails{{Name: "https://github.com/madler/zlib", Version: "v1.2.11"}},
+		},
+		{
+			name: "zlib renamed when vendored",
+			path: "fixtures/c-library/opt/app/lib/libz-b8d9c5a1.so.1.3",
+			want: []lockfile.PackageDetails{{Name: "https://github.com/madler/zlib", Version: "v1.3"}},
+		},
+		{
+			name: "curl linked against openssl and zlib",
+			path: "fixtures/c-library/usr/local/lib/libcurl.so.4.8.0",
+			want: []lockfile.PackageDetails{{Name: "https://github.com/curl/curl", Version: "curl-8_5_0"}},
+		},
+		{
+			name: "libxml2 without version string",
+			path: "fixtures/c-library/usr/local/lib/libxml2.so.2.9.14",
+			want: []lockfile.PackageDetails{{Name: "https://gitlab.gnome.org/GNOME/libxml2", Version: "v2.9.14"}},
+		},
+		{
+			name: "sqlite",
+			path: "fixtures/c-library/usr/local/lib/libsqlite3.so.0.8.6",
+			want: []lockfile.PackageDetails{{Name: "https://github.com/sqlite/sqlite", Version: "version-3.45.1"}},
+		},
+		{
+			name: "expat without version string",
+			path: "fixtures/c-library/usr/local/lib/libexpat.so.1.8.10",
+			want: []lockfile.PackageDetails{},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			packages, err := lockfile.ParseCLibrary(tt.path)
+
+			if err != nil {
+				t.Errorf("Got unexpected error: %v", err)
+			}
+
+			for i := range tt.want {
+				tt.want[i].Ecosystem = lockfile.GitEcosystem
+				tt.want[i].CompareAs = lockfile.GitEcosystem
+			}
+
+			expectPackages(t, packages, tt.want)
+		})
+	}
+}
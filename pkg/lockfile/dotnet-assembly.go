@@ -0,0 +1,251 @@
+package lockfile
+
+import (
+	"bytes"
+	"debug/pe"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf16"
+)
+
+var errNotDotnetAssembly = errors.New("file is not a .NET assembly")
+
+// the indexes of the data directories of a PE file that have its resources, and its CLR header,
+// which only .NET assemblies have
+const (
+	peResourceDirectory      = 2
+	dotnetCLRHeaderDirectory = 14
+)
+
+// dataDirectory returns the data directory of a PE file at the index, which is empty if it does not have it
+func dataDirectory(f *pe.File, index uint32) pe.DataDirectory {
+	switch header := f.OptionalHeader.(type) {
+	case *pe.OptionalHeader32:
+		if index < header.NumberOfRvaAndSizes {
+			return header.DataDirectory[index]
+		}
+	case *pe.OptionalHeader64:
+		if index < header.NumberOfRvaAndSizes {
+			return header.DataDirectory[index]
+		}
+	}
+
+	return pe.DataDirectory{}
+}
+
+// isDotnetAssembly returns whether the PE file is a .NET assembly rather than a native library
+func isDotnetAssembly(f *pe.File) bool {
+	return dataDirectory(f, dotnetCLRHeaderDirectory).VirtualAddress != 0
+}
+
+// resourceData returns the resources of a PE file, which are looked for by their data directory as
+// they are not in a section of their own in some assemblies, or nil if the file does not have any
+func resourceData(f *pe.File) ([]byte, error) {
+	directory := dataDirectory(f, peResourceDirectory)
+	if directory.VirtualAddress == 0 {
+		return nil, nil
+	}
+
+	for _, section := range f.Sections {
+		if directory.VirtualAddress < section.VirtualAddress || directory.VirtualAddress >= section.VirtualAddress+section.VirtualSize {
+			continue
+		}
+
+		data, err := section.Data()
+		if err != nil {
+			return nil, err
+		}
+
+		start := directory.VirtualAddress - section.VirtualAddress
+		end := min(start+directory.Size, uint32(len(data)))
+
+		if start >= end {
+			return nil, nil
+		}
+
+		return data[start:end], nil
+	}
+
+	return nil, nil
+}
+
+// readyToRunMachineOverrides are what the machines of ReadyToRun assemblies are XORed with when they
+// are compiled for operating systems other than Windows, which debug/pe does not recognise
+var readyToRunMachineOverrides = []uint16{0x4644, 0x7B79, 0xADC4, 0x1993, 0x1992}
+
+// machineReaderAt reads a PE file as if it had the given machine
+type machineReaderAt struct {
+	io.ReaderAt
+	offset  int64
+	machine [2]byte
+}
+
+func (r machineReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	n, err := r.ReaderAt.ReadAt(p, off)
+
+	for i, b := range r.machine {
+		if at := r.offset + int64(i) - off; at >= 0 && at < int64(n) {
+			p[at] = b
+		}
+	}
+
+	return n, err
+}
+
+// openDotnetAssembly opens a PE file, including those of ReadyToRun assemblies that have been
+// compiled for operating systems other than Windows, whose machines are restored first
+func openDotnetAssembly(r io.ReaderAt) (*pe.File, error) {
+	var b [4]byte
+
+	// the offset of the PE signature, which the machine comes after
+	if _, err := r.ReadAt(b[:], 0x3c); err != nil {
+		return nil, err
+	}
+
+	offset := int64(binary.LittleEndian.Uint32(b[:])) + 4
+
+	if _, err := r.ReadAt(b[:2], offset); err != nil {
+		return nil, err
+	}
+
+	machine := binary.LittleEndian.Uint16(b[:2])
+
+	for _, override := range readyToRunMachineOverrides {
+		switch machine ^ override {
+		case pe.IMAGE_FILE_MACHINE_AMD64, pe.IMAGE_FILE_MACHINE_ARM64, pe.IMAGE_FILE_MACHINE_ARMNT, pe.IMAGE_FILE_MACHINE_I386:
+			restored := machineReaderAt{ReaderAt: r, offset: offset}
+			binary.LittleEndian.PutUint16(restored.machine[:], machine^override)
+
+			return pe.NewFile(restored)
+		}
+	}
+
+	return pe.NewFile(r)
+}
+
+// versionInfoString returns the value of a string in the version information of the resources of a
+// PE file, like its "ProductVersion", or an empty string if it does not have it. The version information
+// is looked for by its key rather than by walking the resources, as each string is stored as a struct of
+// its length, the length of its value, its type, its key, and then its value aligned to 32 bits
+func versionInfoString(resources []byte, key string) string {
+	encodedKey := make([]byte, 0, (len(key)+1)*2)
+	for _, c := range utf16.Encode([]rune(key + "\x00")) {
+		encodedKey = binary.LittleEndian.AppendUint16(encodedKey, c)
+	}
+
+	for offset := 0; ; {
+		i := bytes.Index(resources[offset:], encodedKey)
+		if i < 0 {
+			return ""
+		}
+
+		start := offset + i - 6
+		offset += i + len(encodedKey)
+
+		// strings are aligned to 32 bits and have a type of 1 as their values are text
+		if start < 0 || start%4 != 0 || binary.LittleEndian.Uint16(resources[start+4:]) != 1 {
+			continue
+		}
+
+		valueLength := int(binary.LittleEndian.Uint16(resources[start+2:]))
+		valueStart := start + (offset-start+3)/4*4
+
+		if valueStart+valueLength*2 > len(resources) {
+			return ""
+		}
+
+		value := make([]uint16, valueLength)
+		for j := range value {
+			value[j] = binary.LittleEndian.Uint16(resources[valueStart+j*2:])
+		}
+
+		return strings.TrimRight(string(utf16.Decode(value)), "\x00")
+	}
+}
+
+type DotnetAssemblyExtractor struct{}
+
+// ShouldExtract returns whether the file is a .NET assembly that is not part of an application
+// with a deps.json, which lists the packages of its assemblies already
+func (e DotnetAssemblyExtractor) ShouldExtract(path string) bool {
+	if !strings.EqualFold(filepath.Ext(path), ".dll") {
+		return false
+	}
+
+	if deps, err := filepath.Glob(filepath.Join(filepath.Dir(path), "*.deps.json")); err != nil || len(deps) > 0 {
+		return false
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	assembly, err := openDotnetAssembly(f)
+
+	return err == nil && isDotnetAssembly(assembly)
+}
+
+// Extract extracts the NuGet package that a .NET assembly is from, which is identified by the name
+// of the assembly and the version that it was built as. The version is the informational version of
+// the assembly without its build metadata, which is the version of its package when it is built by
+// the .NET SDK, and the file version is used instead for assemblies that do not have one
+func (e DotnetAssemblyExtractor) Extract(f DepFile) ([]PackageDetails, error) {
+	b, err := io.ReadAll(f)
+	if err != nil {
+		return []PackageDetails{}, fmt.Errorf("could not extract from %s: %w", f.Path(), err)
+	}
+
+	file, err := openDotnetAssembly(bytes.NewReader(b))
+	if err == nil && !isDotnetAssembly(file) {
+		err = errNotDotnetAssembly
+	}
+	if err != nil {
+		return []PackageDetails{}, fmt.Errorf("could not extract from %s: %w", f.Path(), err)
+	}
+
+	resources, err := resourceData(file)
+	if err != nil {
+		return []PackageDetails{}, fmt.Errorf("could not extract from %s: %w", f.Path(), err)
+	}
+
+	version, _, _ := strings.Cut(versionInfoString(resources, "ProductVersion"), "+")
+	if version == "" {
+		version = versionInfoString(resources, "FileVersion")
+	}
+
+	if version == "" {
+		return []PackageDetails{}, nil
+	}
+
+	name := versionInfoString(resources, "OriginalFilename")
+	if name == "" {
+		name = filepath.Base(f.Path())
+	}
+
+	return []PackageDetails{{
+		Name:      strings.TrimSuffix(name, filepath.Ext(name)),
+		Version:   strings.TrimSpace(version),
+		Ecosystem: NuGetEcosystem,
+		CompareAs: NuGetEcosystem,
+	}}, nil
+}
+
+var _ Extractor = DotnetAssemblyExtractor{}
+
+func ParseDotnetAssembly(pathToAssembly string) ([]PackageDetails, error) {
+	return extractFromFile(pathToAssembly, DotnetAssemblyExtractor{})
+}
+
+// FromDotnetAssembly attempts to parse the given file as a .NET assembly
+func FromDotnetAssembly(pathToAssembly string) (Lockfile, error) {
+	packages, err := ParseDotnetAssembly(pathToAssembly)
+
+	return artifactLockfile(pathToAssembly, "dotnet-assembly", packages), err
+}
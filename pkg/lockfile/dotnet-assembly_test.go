@@ -0,0 +1,97 @@
+package lockfile_test
+
+import (
+	"io/fs"
+	"testing"
+
+	"github.com/google/osv-scanner/pkg/lockfile"
+)
+
+func TestDotnetAssemblyExtractor_ShouldExtract(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{
+			name: "",
+			path: "",
+			want: false,
+		},
+		{
+			name: "",
+			path: "fixtures/dotnet-assembly/Example.Library.dll",
+			want: true,
+		},
+		{
+			name: "",
+			path: "fixtures/dotnet-assembly/published/Example.Library.dll",
+			want: false,
+		},
+		{
+			name: "",
+			path: "fixtures/dotnet-assembly/not-an-assembly.dll",
+			want: false,
+		},
+		{
+			name: "",
+			path: "fixtures/dotnet-assembly/does-not-exist.dll",
+			want: false,
+		},
+		{
+			name: "",
+			path: "fixtures/dotnet-assembly/published/Example.Library.deps.json",
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e := lockfile.DotnetAssemblyExtractor{}
+			got := e.ShouldExtract(tt.path)
+			if got != tt.want {
+				t.Errorf("Extract() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDotnetAssembly_FileDoesNotExist(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseDotnetAssembly("fixtures/dotnet-assembly/does-not-exist.dll")
+
+	expectErrIs(t, err, fs.ErrNotExist)
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseDotnetAssembly_NotAnAssembly(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseDotnetAssembly("fixtures/dotnet-assembly/not-an-assembly.dll")
+
+	expectErrContaining(t, err, "could not extract from")
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseDotnetAssembly(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseDotnetAssembly("fixtures/dotnet-assembly/Example.Library.dll")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "Example.Library",
+			Version:   "1.2.3",
+			Ecosystem: lockfile.NuGetEcosystem,
+			CompareAs: lockfile.NuGetEcosystem,
+		},
+	})
+}
@@ -0,0 +1,83 @@
+package lockfile
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// dotnetDepsJSONLibrary is a library that an application was published with, which is keyed by its name and version
+type dotnetDepsJSONLibrary struct {
+	// Type is "package" for NuGet packages, "runtimepack" for the runtimes of self-contained
+	// applications, and "project" or "reference" for what was built with the application
+	Type string `json:"type"`
+}
+
+type dotnetDepsJSON struct {
+	Libraries map[string]dotnetDepsJSONLibrary `json:"libraries"`
+}
+
+type DotnetDepsJSONExtractor struct{}
+
+// ShouldExtract returns whether the file is the dependencies of a published .NET application,
+// which is named after the application, like "MyApp.deps.json"
+func (e DotnetDepsJSONExtractor) ShouldExtract(path string) bool {
+	base := filepath.Base(path)
+
+	return strings.HasSuffix(base, ".deps.json") && base != ".deps.json"
+}
+
+// Extract extracts the NuGet packages that a .NET application was published with from its deps.json,
+// which the runtime uses to load their assemblies, so it is there even when the project files are not.
+// The runtime packs of self-contained applications are reported as the NuGet packages of their runtime
+func (e DotnetDepsJSONExtractor) Extract(f DepFile) ([]PackageDetails, error) {
+	var deps *dotnetDepsJSON
+
+	err := json.NewDecoder(f).Decode(&deps)
+
+	if err != nil {
+		return []PackageDetails{}, fmt.Errorf("could not extract from %s: %w", f.Path(), err)
+	}
+
+	if deps == nil {
+		return []PackageDetails{}, nil
+	}
+
+	details := map[string]PackageDetails{}
+
+	for key, library := range deps.Libraries {
+		if library.Type != "package" && library.Type != "runtimepack" {
+			continue
+		}
+
+		name, version, ok := strings.Cut(key, "/")
+		if !ok {
+			continue
+		}
+
+		name = strings.TrimPrefix(name, "runtimepack.")
+
+		details[name+"@"+version] = PackageDetails{
+			Name:      name,
+			Version:   version,
+			Ecosystem: NuGetEcosystem,
+			CompareAs: NuGetEcosystem,
+		}
+	}
+
+	return pkgDetailsMapToSlice(details), nil
+}
+
+var _ Extractor = DotnetDepsJSONExtractor{}
+
+func ParseDotnetDepsJSON(pathToDepsJSON string) ([]PackageDetails, error) {
+	return extractFromFile(pathToDepsJSON, DotnetDepsJSONExtractor{})
+}
+
+// FromDotnetDepsJSON attempts to parse the given file as the deps.json of a published .NET application
+func FromDotnetDepsJSON(pathToDepsJSON string) (Lockfile, error) {
+	packages, err := ParseDotnetDepsJSON(pathToDepsJSON)
+
+	return artifactLockfile(pathToDepsJSON, "dotnet-deps-json", packages), err
+}
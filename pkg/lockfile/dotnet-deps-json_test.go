@@ -0,0 +1,151 @@
+package lockfile_test
+
+import (
+	"io/fs"
+	"testing"
+
+	"github.com/google/osv-scanner/pkg/lockfile"
+)
+
+func TestDotnetDepsJSONExtractor_ShouldExtract(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{
+			name: "",
+			path: "",
+			want: false,
+		},
+		{
+			name: "",
+			path: "WebApp.deps.json",
+			want: true,
+		},
+		{
+			name: "",
+			path: "app/WebApp.deps.json",
+			want: true,
+		},
+		{
+			name: "",
+			path: "/usr/share/dotnet/shared/Microsoft.NETCore.App/8.0.0/Microsoft.NETCore.App.deps.json",
+			want: true,
+		},
+		{
+			name: "",
+			path: "app/.deps.json",
+			want: false,
+		},
+		{
+			name: "",
+			path: "app/WebApp.runtimeconfig.json",
+			want: false,
+		},
+		{
+			name: "",
+			path: "app/deps.json",
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e := lockfile.DotnetDepsJSONExtractor{}
+			got := e.ShouldExtract(tt.path)
+			if got != tt.want {
+				t.Errorf("Extract() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDotnetDepsJSON_FileDoesNotExist(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseDotnetDepsJSON("fixtures/dotnet-deps-json/does-not-exist")
+
+	expectErrIs(t, err, fs.ErrNotExist)
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseDotnetDepsJSON_InvalidJSON(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseDotnetDepsJSON("fixtures/dotnet-deps-json/not-json.txt")
+
+	expectErrContaining(t, err, "could not extract from")
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseDotnetDepsJSON_NoLibraries(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseDotnetDepsJSON("fixtures/dotnet-deps-json/empty.deps.json")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseDotnetDepsJSON_Packages(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseDotnetDepsJSON("fixtures/dotnet-deps-json/WebApp.deps.json")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "Newtonsoft.Json",
+			Version:   "13.0.1",
+			Ecosystem: lockfile.NuGetEcosystem,
+			CompareAs: lockfile.NuGetEcosystem,
+		},
+		{
+			Name:      "Serilog",
+			Version:   "3.1.1",
+			Ecosystem: lockfile.NuGetEcosystem,
+			CompareAs: lockfile.NuGetEcosystem,
+		},
+	})
+}
+
+func TestParseDotnetDepsJSON_SelfContained(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseDotnetDepsJSON("fixtures/dotnet-deps-json/SelfContained.deps.json")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "Microsoft.AspNetCore.App.Runtime.linux-x64",
+			Version:   "8.0.0",
+			Ecosystem: lockfile.NuGetEcosystem,
+			CompareAs: lockfile.NuGetEcosystem,
+		},
+		{
+			Name:      "Microsoft.NETCore.App.Runtime.linux-x64",
+			Version:   "8.0.0",
+			Ecosystem: lockfile.NuGetEcosystem,
+			CompareAs: lockfile.NuGetEcosystem,
+		},
+		{
+			Name:      "System.Text.Json",
+			Version:   "8.0.4",
+			Ecosystem: lockfile.NuGetEcosystem,
+			CompareAs: lockfile.NuGetEcosystem,
+		},
+	})
+}
@@ -3,6 +3,8 @@ package lockfile
 import (
 	"bufio"
 	"fmt"
+	"io"
+	"path/filepath"
 	"sort"
 	"strings"
 
@@ -11,6 +13,12 @@ import (
 
 const DebianEcosystem Ecosystem = "Debian"
 
+const dpkgStatusPath = "var/lib/dpkg/status"
+
+// dpkgStatusDir is where distroless images have a status file for each package, as
+// they are built without dpkg by unpacking packages rather than installing them
+const dpkgStatusDir = "var/lib/dpkg/status.d"
+
 func groupDpkgPackageLines(scanner *bufio.Scanner) [][]string {
 	var groups [][]string
 	var group []string
@@ -101,6 +109,66 @@ func parseDpkgPackageGroup(group []string) PackageDetails {
 	return pkg
 }
 
+// isDistrolessDpkgStatus returns whether the path is the status file of a package in a distroless
+// image, which are named after the package, skipping the checksums of the files of the package
+func isDistrolessDpkgStatus(path string) bool {
+	dir := filepath.ToSlash(filepath.Dir(path))
+
+	return (dir == dpkgStatusDir || strings.HasSuffix(dir, "/"+dpkgStatusDir)) && !strings.HasSuffix(path, ".md5sums")
+}
+
+// debianReleaseEcosystem returns the ecosystem of the release of Debian that the dpkg status
+// file of f is for, such as "Debian:12", based on the os-release of the filesystem that it is in,
+// or DebianEcosystem if the release cannot be determined or the distribution is not Debian.
+// Filesystems without an os-release fall back to the version in /etc/debian_version
+func debianReleaseEcosystem(f DepFile) Ecosystem {
+	var root string
+
+	switch {
+	case strings.HasSuffix(filepath.ToSlash(f.Path()), "/"+dpkgStatusPath):
+		root = "../../../"
+	case isDistrolessDpkgStatus(f.Path()):
+		root = "../../../../"
+	default:
+		return DebianEcosystem
+	}
+
+	for _, osReleasePath := range []string{"etc/os-release", "usr/lib/os-release"} {
+		osReleaseFile, err := f.Open(root + osReleasePath)
+		if err != nil {
+			continue
+		}
+
+		osRelease := parseOSRelease(osReleaseFile)
+		_ = osReleaseFile.Close()
+
+		// testing and unstable do not have a VERSION_ID, and are not a release that advisories are published for
+		if osRelease["ID"] != "debian" || !cachedregexp.MustCompile(`^\d+$`).MatchString(osRelease["VERSION_ID"]) {
+			return DebianEcosystem
+		}
+
+		return Ecosystem(fmt.Sprintf("%s:%s", DebianEcosystem, osRelease["VERSION_ID"]))
+	}
+
+	versionFile, err := f.Open(root + "etc/debian_version")
+	if err != nil {
+		return DebianEcosystem
+	}
+	defer versionFile.Close()
+
+	version, err := io.ReadAll(versionFile)
+	if err != nil {
+		return DebianEcosystem
+	}
+
+	// releases have a version like "12.5", while testing and unstable have a codename like "trixie/sid"
+	if match := cachedregexp.MustCompile(`^(\d+)\.`).FindStringSubmatch(strings.TrimSpace(string(version))); match != nil {
+		return Ecosystem(fmt.Sprintf("%s:%s", DebianEcosystem, match[1]))
+	}
+
+	return DebianEcosystem
+}
+
 func ParseDpkgStatus(pathToLockfile string) ([]PackageDetails, error) {
 	return extractFromFile(pathToLockfile, DpkgStatusExtractor{})
 }
@@ -108,12 +176,18 @@ func ParseDpkgStatus(pathToLockfile string) ([]PackageDetails, error) {
 type DpkgStatusExtractor struct{}
 
 func (e DpkgStatusExtractor) ShouldExtract(path string) bool {
-	return path == "/var/lib/dpkg/status"
+	path = filepath.ToSlash(path)
+
+	return path == dpkgStatusPath || strings.HasSuffix(path, "/"+dpkgStatusPath) || isDistrolessDpkgStatus(path)
 }
 
+// Extract extracts the packages that are installed according to the status file of dpkg, or one
+// of the status files of the packages of a distroless image, which are reported using the ecosystem
+// of the Debian release that the filesystem it is in is running when it can be determined
 func (e DpkgStatusExtractor) Extract(f DepFile) ([]PackageDetails, error) {
 	scanner := bufio.NewScanner(f)
 	packageGroups := groupDpkgPackageLines(scanner)
+	ecosystem := debianReleaseEcosystem(f)
 
 	packages := make([]PackageDetails, 0, len(packageGroups))
 
@@ -130,6 +204,7 @@ func (e DpkgStatusExtractor) Extract(f DepFile) ([]PackageDetails, error) {
 			continue
 		}
 
+		pkg.Ecosystem = ecosystem
 		packages = append(packages, pkg)
 	}
 
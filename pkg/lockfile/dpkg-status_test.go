@@ -152,3 +152,137 @@ func TestParseDpkgStatus_Source_Ver_Override(t *testing.T) {
 		},
 	})
 }
+
+func TestParseDpkgStatus_WithDebianRelease(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseDpkgStatus("fixtures/dpkg/rootfs/var/lib/dpkg/status")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "curl",
+			Version:   "7.88.1-10+deb12u4",
+			Ecosystem: "Debian:12",
+			CompareAs: lockfile.DebianEcosystem,
+		},
+	})
+}
+
+func TestParseDpkgStatus_WithOtherDistribution(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseDpkgStatus("fixtures/dpkg/ubuntu-rootfs/var/lib/dpkg/status")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "curl",
+			Version:   "7.81.0-1ubuntu1.15",
+			Ecosystem: lockfile.DebianEcosystem,
+			CompareAs: lockfile.DebianEcosystem,
+		},
+	})
+}
+
+func TestParseDpkgStatus_Distroless(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseDpkgStatus("fixtures/dpkg/distroless-rootfs/var/lib/dpkg/status.d/libssl3")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "openssl",
+			Version:   "3.0.11-1~deb12u2",
+			Ecosystem: "Debian:12",
+			CompareAs: lockfile.DebianEcosystem,
+		},
+	})
+}
+
+func TestDpkgStatusExtractor_ShouldExtract(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{
+			name: "",
+			path: "",
+			want: false,
+		},
+		{
+			name: "",
+			path: "status",
+			want: false,
+		},
+		{
+			name: "",
+			path: "/var/lib/dpkg/status",
+			want: true,
+		},
+		{
+			name: "",
+			path: "var/lib/dpkg/status",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/rootfs/var/lib/dpkg/status",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/rootfs/var/lib/dpkg/status/file",
+			want: false,
+		},
+		{
+			name: "",
+			path: "path/to/rootfs/myvar/lib/dpkg/status",
+			want: false,
+		},
+		{
+			name: "",
+			path: "path/to/rootfs/var/lib/dpkg/status.d/libssl3",
+			want: true,
+		},
+		{
+			name: "",
+			path: "var/lib/dpkg/status.d/base-files",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/rootfs/var/lib/dpkg/status.d/libssl3.md5sums",
+			want: false,
+		},
+		{
+			name: "",
+			path: "path/to/rootfs/var/lib/dpkg/status.d",
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e := lockfile.DpkgStatusExtractor{}
+			got := e.ShouldExtract(tt.path)
+			if got != tt.want {
+				t.Errorf("ShouldExtract() - got %v, expected %v", got, tt.want)
+			}
+		})
+	}
+}
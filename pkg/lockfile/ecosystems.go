@@ -16,6 +16,15 @@ func KnownEcosystems() []Ecosystem {
 		PubEcosystem,
 		ConanEcosystem,
 		CRANEcosystem,
+		BioconductorEcosystem,
+		VcpkgEcosystem,
+		HackageEcosystem,
+		BazelEcosystem,
+		TerraformEcosystem,
+		HelmEcosystem,
+		JSREcosystem,
+		DenoLandEcosystem,
+		YoctoEcosystem,
 		// Disabled temporarily,
 		// see https://github.com/google/osv-scanner/pull/128 discussion for additional context
 		// AlpineEcosystem,
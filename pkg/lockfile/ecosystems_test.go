@@ -34,11 +34,27 @@ func TestKnownEcosystems(t *testing.T) {
 
 	expectedCount := numberOfLockfileParsers(t)
 
-	// - npm, yarn, and pnpm,
-	// - pip, poetry, pdm and pipenv,
-	// - maven and gradle,
+	// - npm, yarn, pnpm, and bun,
+	// - pip, poetry, pdm, pipenv, uv, pyproject.toml, conda environments and conda-lock,
+	// - maven, gradle, gradle verification metadata and gradle version catalogs,
+	// - cabal and stack,
+	// - Gemfile and Gemfile.lock,
+	// - helm charts and chart lockfiles,
 	// all use the same ecosystem so "ignore" those parsers in the count
-	expectedCount -= 6
+	expectedCount -= 16
+
+	// renv reports packages from both CRAN and Bioconductor
+	expectedCount++
+
+	// deno reports packages from JSR and deno.land, as well as from npm
+	expectedCount++
+
+	// flake.lock only reports commits, which do not have an ecosystem
+	expectedCount--
+
+	// Dockerfiles report the packages of the distribution of their base image and
+	// the packages that they pin, which are all of ecosystems that are already known
+	expectedCount--
 
 	ecosystems := lockfile.KnownEcosystems()
 
@@ -0,0 +1,146 @@
+package lockfile
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// ExecExtractor extracts packages by running an external command, which allows formats that
+// are not supported by the scanner to be scanned by a plugin that is written in any language.
+//
+// The command is run with the path of the file as its last argument and the contents of
+// the file as its standard input, and must write an inventory of the packages in the file
+// to its standard output, exiting with a non-zero status if it cannot:
+//
+//	{
+//	  "packages": [
+//	    { "name": "left-pad", "version": "1.3.0", "ecosystem": "npm", "groups": ["dev"] },
+//	    { "commit": "9a1a5b6a5c83e3c7e1f7a4f0fbd5f2dd2d2d9a3b" }
+//	  ]
+//	}
+type ExecExtractor struct {
+	// Command is the command to run followed by its arguments
+	Command []string
+	// Patterns are the glob patterns of the names of the files that should be extracted,
+	// such as "*.deps", or none if the plugin is only used for files that it is chosen for
+	Patterns []string
+}
+
+func (e ExecExtractor) ShouldExtract(path string) bool {
+	for _, pattern := range e.Patterns {
+		if ok, _ := filepath.Match(pattern, filepath.Base(path)); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+type execInventoryPackage struct {
+	Name      string   `json:"name"`
+	Version   string   `json:"version"`
+	Ecosystem string   `json:"ecosystem"`
+	Commit    string   `json:"commit"`
+	Groups    []string `json:"groups"`
+}
+
+type execInventory struct {
+	Packages []execInventoryPackage `json:"packages"`
+}
+
+func (e ExecExtractor) Extract(f DepFile) ([]PackageDetails, error) {
+	if len(e.Command) == 0 {
+		return []PackageDetails{}, fmt.Errorf("could not extract from %s: no command to run", f.Path())
+	}
+
+	var stdout, stderr bytes.Buffer
+
+	args := append(append([]string{}, e.Command[1:]...), f.Path())
+
+	//nolint:gosec // the command is what the user has configured the plugin to run
+	cmd := exec.Command(e.Command[0], args...)
+	cmd.Stdin = f
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			err = fmt.Errorf("%w: %s", err, msg)
+		}
+
+		return []PackageDetails{}, fmt.Errorf("could not extract from %s: %w", f.Path(), err)
+	}
+
+	var inventory execInventory
+
+	if err := json.Unmarshal(stdout.Bytes(), &inventory); err != nil {
+		return []PackageDetails{}, fmt.Errorf("could not extract from %s: %w", f.Path(), err)
+	}
+
+	packages := make([]PackageDetails, 0, len(inventory.Packages))
+
+	for _, pkg := range inventory.Packages {
+		packages = append(packages, PackageDetails{
+			Name:      pkg.Name,
+			Version:   pkg.Version,
+			Commit:    pkg.Commit,
+			Ecosystem: Ecosystem(pkg.Ecosystem),
+			CompareAs: Ecosystem(pkg.Ecosystem),
+			DepGroups: pkg.Groups,
+		})
+	}
+
+	return packages, nil
+}
+
+var _ Extractor = ExecExtractor{}
+
+var ErrInvalidExecExtractor = errors.New("invalid extractor plugin")
+
+// LoadExecExtractors loads the extractor plugins that are configured in the TOML file
+// at path, keyed by the name that each of them should be registered with
+func LoadExecExtractors(path string) (map[string]ExecExtractor, error) {
+	var config struct {
+		Extractors []struct {
+			Name     string   `toml:"name"`
+			Command  []string `toml:"command"`
+			Patterns []string `toml:"patterns"`
+		} `toml:"extractor"`
+	}
+
+	if _, err := toml.DecodeFile(path, &config); err != nil {
+		return nil, fmt.Errorf("could not load extractor plugins from %s: %w", path, err)
+	}
+
+	plugins := make(map[string]ExecExtractor, len(config.Extractors))
+
+	for i, plugin := range config.Extractors {
+		switch {
+		case plugin.Name == "":
+			return nil, fmt.Errorf("%w: extractor %d of %s does not have a name", ErrInvalidExecExtractor, i+1, path)
+		case len(plugin.Command) == 0:
+			return nil, fmt.Errorf("%w: %s does not have a command", ErrInvalidExecExtractor, plugin.Name)
+		}
+
+		if _, ok := plugins[plugin.Name]; ok {
+			return nil, fmt.Errorf("%w: %s is configured more than once", ErrInvalidExecExtractor, plugin.Name)
+		}
+
+		for _, pattern := range plugin.Patterns {
+			if _, err := filepath.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("%w: %s has an invalid pattern %q", ErrInvalidExecExtractor, plugin.Name, pattern)
+			}
+		}
+
+		plugins[plugin.Name] = ExecExtractor{Command: plugin.Command, Patterns: plugin.Patterns}
+	}
+
+	return plugins, nil
+}
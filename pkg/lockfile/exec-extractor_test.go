@@ -0,0 +1,226 @@
+package lockfile_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/lockfile"
+)
+
+// TestExecExtractor_HelperProcess is not a real test, but is run by the other tests
+// as the command of a plugin, which behaves based on the argument after "--"
+func TestExecExtractor_HelperProcess(t *testing.T) {
+	t.Parallel()
+
+	args := os.Args
+	for len(args) > 0 && args[0] != "--" {
+		args = args[1:]
+	}
+
+	// the test is being run normally, rather than as a plugin
+	if len(args) < 3 {
+		return
+	}
+
+	switch args[1] {
+	case "cat":
+		_, _ = io.Copy(os.Stdout, os.Stdin)
+	case "path":
+		fmt.Printf(`{"packages": [{"name": %q, "version": "1.0.0", "ecosystem": "npm"}]}`, filepath.Base(args[len(args)-1]))
+	case "fail":
+		fmt.Fprintln(os.Stderr, "something went wrong")
+		os.Exit(1)
+	}
+
+	os.Exit(0)
+}
+
+func helperPlugin(mode string) lockfile.ExecExtractor {
+	return lockfile.ExecExtractor{
+		Command:  []string{os.Args[0], "-test.run=^TestExecExtractor_HelperProcess$", "--", mode},
+		Patterns: []string{"*.deps", "internal.lock"},
+	}
+}
+
+func TestExecExtractor_ShouldExtract(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{
+			name: "",
+			path: "",
+			want: false,
+		},
+		{
+			name: "",
+			path: "inventory.deps",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/my/inventory.deps",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/my/internal.lock",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/my/inventory.deps.json",
+			want: false,
+		},
+		{
+			name: "",
+			path: "path/to/my.deps/file",
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e := helperPlugin("cat")
+			got := e.ShouldExtract(tt.path)
+			if got != tt.want {
+				t.Errorf("ShouldExtract() - got %v, expected %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExecExtractor_Extract(t *testing.T) {
+	t.Parallel()
+
+	f, err := lockfile.OpenLocalDepFile("fixtures/exec/inventory.deps")
+	if err != nil {
+		t.Fatalf("could not open file %v", err)
+	}
+	defer f.Close()
+
+	packages, err := helperPlugin("cat").Extract(f)
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "left-pad",
+			Version:   "1.3.0",
+			Ecosystem: lockfile.NpmEcosystem,
+			CompareAs: lockfile.NpmEcosystem,
+		},
+		{
+			Name:      "jest",
+			Version:   "29.7.0",
+			Ecosystem: lockfile.NpmEcosystem,
+			CompareAs: lockfile.NpmEcosystem,
+			DepGroups: []string{"dev"},
+		},
+		{
+			Commit: "9a1a5b6a5c83e3c7e1f7a4f0fbd5f2dd2d2d9a3b",
+		},
+	})
+}
+
+func TestExecExtractor_Extract_PassesPath(t *testing.T) {
+	t.Parallel()
+
+	f, err := lockfile.OpenLocalDepFile("fixtures/exec/inventory.deps")
+	if err != nil {
+		t.Fatalf("could not open file %v", err)
+	}
+	defer f.Close()
+
+	packages, err := helperPlugin("path").Extract(f)
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "inventory.deps",
+			Version:   "1.0.0",
+			Ecosystem: lockfile.NpmEcosystem,
+			CompareAs: lockfile.NpmEcosystem,
+		},
+	})
+}
+
+func TestExecExtractor_Extract_InvalidJson(t *testing.T) {
+	t.Parallel()
+
+	f, err := lockfile.OpenLocalDepFile("fixtures/exec/not-json.deps")
+	if err != nil {
+		t.Fatalf("could not open file %v", err)
+	}
+	defer f.Close()
+
+	packages, err := helperPlugin("cat").Extract(f)
+
+	expectErrContaining(t, err, "could not extract from")
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestExecExtractor_Extract_CommandFails(t *testing.T) {
+	t.Parallel()
+
+	f, err := lockfile.OpenLocalDepFile("fixtures/exec/inventory.deps")
+	if err != nil {
+		t.Fatalf("could not open file %v", err)
+	}
+	defer f.Close()
+
+	packages, err := helperPlugin("fail").Extract(f)
+
+	expectErrContaining(t, err, "something went wrong")
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestLoadExecExtractors(t *testing.T) {
+	t.Parallel()
+
+	plugins, err := lockfile.LoadExecExtractors("fixtures/exec/plugins.toml")
+
+	if err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+
+	want := map[string]lockfile.ExecExtractor{
+		"internal-deps": {
+			Command:  []string{"/usr/local/bin/osv-internal-deps", "--format", "osv"},
+			Patterns: []string{"*.deps", "internal.lock"},
+		},
+		"vendored-manifest": {
+			Command: []string{"osv-vendored-manifest"},
+		},
+	}
+
+	if diff := cmp.Diff(want, plugins); diff != "" {
+		t.Errorf("LoadExecExtractors() (-want +got):\n%s", diff)
+	}
+}
+
+func TestLoadExecExtractors_Invalid(t *testing.T) {
+	t.Parallel()
+
+	for _, path := range []string{"fixtures/exec/no-command.toml", "fixtures/exec/duplicate.toml"} {
+		_, err := lockfile.LoadExecExtractors(path)
+
+		if !errors.Is(err, lockfile.ErrInvalidExecExtractor) {
+			t.Errorf("Did not get the expected ErrInvalidExecExtractor error for %s - got %v instead", path, err)
+		}
+	}
+}
@@ -9,12 +9,28 @@ import (
 
 var extractors = map[string]Extractor{}
 
-func registerExtractor(name string, extractor Extractor) {
+var ErrExtractorAlreadyRegistered = errors.New("an extractor is already registered")
+
+// RegisterExtractor registers an extractor with the given name, so that it is used for the
+// files that it should extract and can be chosen explicitly by name, which allows formats
+// that are internal to an organization to be scanned without forking the scanner.
+//
+// Extractors are not safe to register while files are being extracted, so this should be
+// called before scanning, such as from an init function.
+func RegisterExtractor(name string, extractor Extractor) error {
 	if _, ok := extractors[name]; ok {
-		panic(fmt.Sprintf("an extractor is already registered as %s", name))
+		return fmt.Errorf("%w as %s", ErrExtractorAlreadyRegistered, name)
 	}
 
 	extractors[name] = extractor
+
+	return nil
+}
+
+func registerExtractor(name string, extractor Extractor) {
+	if err := RegisterExtractor(name, extractor); err != nil {
+		panic(err.Error())
+	}
 }
 
 func FindExtractor(path, extractAs string) (Extractor, string) {
@@ -34,24 +34,45 @@ func TestFindExtractor(t *testing.T) {
 	t.Parallel()
 
 	lockfiles := map[string]string{
-		"buildscript-gradle.lockfile": "gradle.lockfile",
-		"Cargo.lock":                  "Cargo.lock",
-		"composer.lock":               "composer.lock",
-		"Gemfile.lock":                "Gemfile.lock",
-		"go.mod":                      "go.mod",
-		"gradle.lockfile":             "gradle.lockfile",
-		"mix.lock":                    "mix.lock",
-		"pdm.lock":                    "pdm.lock",
-		"Pipfile.lock":                "Pipfile.lock",
-		"package-lock.json":           "package-lock.json",
-		"packages.lock.json":          "packages.lock.json",
-		"pnpm-lock.yaml":              "pnpm-lock.yaml",
-		"poetry.lock":                 "poetry.lock",
-		"pom.xml":                     "pom.xml",
-		"pubspec.lock":                "pubspec.lock",
-		"renv.lock":                   "renv.lock",
-		"requirements.txt":            "requirements.txt",
-		"yarn.lock":                   "yarn.lock",
+		".terraform.lock.hcl":              ".terraform.lock.hcl",
+		"buildscript-gradle.lockfile":      "gradle.lockfile",
+		"bun.lock":                         "bun.lock",
+		"bun.lockb":                        "bun.lockb",
+		"cabal.project.freeze":             "cabal.project.freeze",
+		"Cargo.lock":                       "Cargo.lock",
+		"Chart.lock":                       "Chart.lock",
+		"Chart.yaml":                       "Chart.yaml",
+		"composer.lock":                    "composer.lock",
+		"conda-lock.yml":                   "conda-lock.yml",
+		"deno.lock":                        "deno.lock",
+		"Dockerfile":                       "Dockerfile",
+		"environment.yml":                  "environment.yml",
+		"environment.yaml":                 "environment.yml",
+		"flake.lock":                       "flake.lock",
+		"Gemfile":                          "Gemfile",
+		"Gemfile.lock":                     "Gemfile.lock",
+		"go.mod":                           "go.mod",
+		"gradle.lockfile":                  "gradle.lockfile",
+		"gradle/libs.versions.toml":        "libs.versions.toml",
+		"license.manifest":                 "license.manifest",
+		"mix.lock":                         "mix.lock",
+		"MODULE.bazel.lock":                "MODULE.bazel.lock",
+		"pdm.lock":                         "pdm.lock",
+		"Pipfile.lock":                     "Pipfile.lock",
+		"package-lock.json":                "package-lock.json",
+		"packages.lock.json":               "packages.lock.json",
+		"pnpm-lock.yaml":                   "pnpm-lock.yaml",
+		"poetry.lock":                      "poetry.lock",
+		"pom.xml":                          "pom.xml",
+		"pubspec.lock":                     "pubspec.lock",
+		"pyproject.toml":                   "pyproject.toml",
+		"renv.lock":                        "renv.lock",
+		"requirements.txt":                 "requirements.txt",
+		"stack.yaml.lock":                  "stack.yaml.lock",
+		"uv.lock":                          "uv.lock",
+		"vcpkg.json":                       "vcpkg.json",
+		"gradle/verification-metadata.xml": "verification-metadata.xml",
+		"yarn.lock":                        "yarn.lock",
 	}
 
 	for file, extractAs := range lockfiles {
@@ -85,14 +106,29 @@ func TestExtractDeps_FindsExpectedExtractor(t *testing.T) {
 	t.Parallel()
 
 	lockfiles := []string{
+		".terraform.lock.hcl",
 		"buildscript-gradle.lockfile",
+		"bun.lock",
+		"bun.lockb",
+		"cabal.project.freeze",
 		"Cargo.lock",
+		"Chart.lock",
+		"Chart.yaml",
 		"composer.lock",
 		"conan.lock",
+		"conda-lock.yml",
+		"deno.lock",
+		"Dockerfile",
+		"environment.yml",
+		"flake.lock",
+		"Gemfile",
 		"Gemfile.lock",
 		"go.mod",
 		"gradle.lockfile",
+		"gradle/libs.versions.toml",
+		"license.manifest",
 		"mix.lock",
+		"MODULE.bazel.lock",
 		"pdm.lock",
 		"Pipfile.lock",
 		"package-lock.json",
@@ -101,8 +137,13 @@ func TestExtractDeps_FindsExpectedExtractor(t *testing.T) {
 		"poetry.lock",
 		"pom.xml",
 		"pubspec.lock",
+		"pyproject.toml",
 		"renv.lock",
 		"requirements.txt",
+		"stack.yaml.lock",
+		"uv.lock",
+		"vcpkg.json",
+		"gradle/verification-metadata.xml",
 		"yarn.lock",
 	}
 
@@ -120,6 +161,8 @@ func TestExtractDeps_FindsExpectedExtractor(t *testing.T) {
 
 	// gradle.lockfile and buildscript-gradle.lockfile use the same parser
 	count -= 1
+	// bun.lock and bun.lockb are handled in the same file
+	count -= 1
 
 	expectNumberOfParsersCalled(t, count)
 }
@@ -157,7 +200,7 @@ func TestListExtractors(t *testing.T) {
 
 	extractors := lockfile.ListExtractors()
 
-	firstExpected := "Cargo.lock"
+	firstExpected := ".terraform.lock.hcl"
 	//nolint:ifshort
 	lastExpected := "yarn.lock"
 
@@ -169,3 +212,13 @@ func TestListExtractors(t *testing.T) {
 		t.Errorf("Expected last element to be %s, but got %s", lastExpected, last)
 	}
 }
+
+func TestRegisterExtractor_AlreadyRegistered(t *testing.T) {
+	t.Parallel()
+
+	err := lockfile.RegisterExtractor("package-lock.json", lockfile.NpmLockExtractor{})
+
+	if !errors.Is(err, lockfile.ErrExtractorAlreadyRegistered) {
+		t.Errorf("Did not get the expected ErrExtractorAlreadyRegistered error - got %v instead", err)
+	}
+}
@@ -0,0 +1,218 @@
+package lockfile
+
+import (
+	"bytes"
+	"debug/buildinfo"
+	"debug/elf"
+	"debug/macho"
+	"fmt"
+	"io"
+	"runtime/debug"
+	"strings"
+
+	"github.com/google/osv-scanner/internal/cachedregexp"
+)
+
+// goModInfoStart and goModInfoEnd are what the linker wraps the information about the modules
+// that a binary was built with in, which are kept in the data of binaries that have been stripped
+const (
+	goModInfoStart = "0w\xaf\f\x92t\b\x02A\xe1\xc1\a\xe6\xd6\x18\xe6"
+	goModInfoEnd   = "\xf92C1\x86\x18 r\x00\x82B\x10A\x16\xd8\xf2"
+)
+
+type GoBinaryExtractor struct{}
+
+// hasGoSections returns whether the file is an ELF or Mach-O binary with the sections that Go adds
+// to binaries, which are kept by strip and "-ldflags=-s" as the runtime needs them for stack traces
+func hasGoSections(path string) bool {
+	if f, err := elf.Open(path); err == nil {
+		defer f.Close()
+
+		return f.Section(".gopclntab") != nil || f.Section(".note.go.buildid") != nil
+	}
+
+	if f, err := macho.Open(path); err == nil {
+		defer f.Close()
+
+		return f.Section("__gopclntab") != nil
+	}
+
+	return false
+}
+
+// ShouldExtract returns whether the file is a binary that was built by Go, which has to be read to tell
+// as binaries can be named anything. Binaries whose information about how they were built cannot be read,
+// like those that have had it removed to harden them, are identified by the sections that Go adds instead
+func (e GoBinaryExtractor) ShouldExtract(path string) bool {
+	if _, err := buildinfo.ReadFile(path); err == nil {
+		return true
+	}
+
+	return hasGoSections(path)
+}
+
+// strippedGoModInfo finds the information about the modules that a binary was built with in its data,
+// for binaries whose build information section has been removed, or returns nil if it is not there
+func strippedGoModInfo(b []byte) *buildinfo.BuildInfo {
+	start := bytes.Index(b, []byte(goModInfoStart))
+	if start < 0 {
+		return nil
+	}
+	start += len(goModInfoStart)
+
+	end := bytes.Index(b[start:], []byte(goModInfoEnd))
+	if end < 0 {
+		return nil
+	}
+
+	info, err := debug.ParseBuildInfo(string(b[start : start+end]))
+	if err != nil {
+		return nil
+	}
+
+	return info
+}
+
+// unescapeGoModulePath reverses how the module cache escapes the capital letters of the paths of
+// modules, so that they can be stored on filesystems that are not case-sensitive, like "!burnt!sushi"
+func unescapeGoModulePath(path string) string {
+	var sb strings.Builder
+
+	for i := 0; i < len(path); i++ {
+		if path[i] == '!' && i+1 < len(path) {
+			i++
+			sb.WriteString(strings.ToUpper(path[i : i+1]))
+
+			continue
+		}
+
+		sb.WriteByte(path[i])
+	}
+
+	return sb.String()
+}
+
+// strippedGoModules finds the modules that a binary was built with from the paths of their source files,
+// which are kept in the table that the runtime uses for stack traces, like "github.com/pkg/errors@v0.9.1/errors.go"
+// when built with -trimpath, or the same in the module cache otherwise. The version of Go is only known if it
+// was built with a toolchain that was downloaded as a module, and the main module is never known
+func strippedGoModules(b []byte) *buildinfo.BuildInfo {
+	info := &buildinfo.BuildInfo{}
+	seen := make(map[string]bool)
+
+	re := cachedregexp.MustCompile(`[A-Za-z0-9.!_~+\-]+\.[A-Za-z0-9!_~\-]+(?:/[A-Za-z0-9.!_~+\-]+)*@v\d+\.\d+\.\d+(?:-[0-9A-Za-z.\-]+)?(?:\+incompatible)?/`)
+
+	for _, match := range re.FindAll(b, -1) {
+		path, version, _ := strings.Cut(strings.TrimSuffix(string(match), "/"), "@")
+
+		// the directories that the module cache is in are matched too if any of them have a dot in their name
+		if i := strings.LastIndex(path, "pkg/mod/"); i >= 0 {
+			path = path[i+len("pkg/mod/"):]
+		}
+		path = unescapeGoModulePath(path)
+
+		if path == "golang.org/toolchain" {
+			// toolchains have a version like "v0.0.1-go1.22.1.linux-amd64"
+			if m := cachedregexp.MustCompile(`-go(1\.\d+(?:\.\d+)?)`).FindStringSubmatch(version); m != nil {
+				info.GoVersion = "go" + m[1]
+			}
+
+			continue
+		}
+
+		if seen[path+"@"+version] {
+			continue
+		}
+		seen[path+"@"+version] = true
+
+		info.Deps = append(info.Deps, &debug.Module{Path: path, Version: version})
+	}
+
+	if len(info.Deps) == 0 && info.GoVersion == "" {
+		return nil
+	}
+
+	return info
+}
+
+// Extract extracts the modules that a Go binary was built with from the information that the
+// linker embeds in it, along with the version of Go as the "stdlib" module. The main module is
+// only reported if it was built from a version of it rather than from a checkout of it.
+//
+// Binaries that the information cannot be read from, such as those whose section of it has been
+// removed, fall back to the list of modules that it has in their data, or failing that, to the
+// modules that the paths of the source files of the binary are in
+func (e GoBinaryExtractor) Extract(f DepFile) ([]PackageDetails, error) {
+	b, err := io.ReadAll(f)
+	if err != nil {
+		return []PackageDetails{}, fmt.Errorf("could not extract from %s: %w", f.Path(), err)
+	}
+
+	info, err := buildinfo.Read(bytes.NewReader(b))
+	if err != nil {
+		info = strippedGoModInfo(b)
+		if info == nil {
+			info = strippedGoModules(b)
+		} else if stripped := strippedGoModules(b); stripped != nil {
+			info.GoVersion = stripped.GoVersion
+		}
+	}
+
+	if info == nil {
+		return []PackageDetails{}, fmt.Errorf("could not extract from %s: %w", f.Path(), err)
+	}
+
+	packages := make([]PackageDetails, 0, len(info.Deps)+2)
+
+	if info.Main.Path != "" && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		packages = append(packages, PackageDetails{
+			Name:      info.Main.Path,
+			Version:   strings.TrimPrefix(info.Main.Version, "v"),
+			Ecosystem: GoEcosystem,
+			CompareAs: GoEcosystem,
+		})
+	}
+
+	for _, dep := range info.Deps {
+		if dep.Replace != nil {
+			dep = dep.Replace
+		}
+
+		// modules that are replaced with a directory do not have a version
+		if dep.Version == "" {
+			continue
+		}
+
+		packages = append(packages, PackageDetails{
+			Name:      dep.Path,
+			Version:   strings.TrimPrefix(dep.Version, "v"),
+			Ecosystem: GoEcosystem,
+			CompareAs: GoEcosystem,
+		})
+	}
+
+	// the version can have the experiments that were enabled, like "go1.22.1 X:loopvar"
+	if version, _, _ := strings.Cut(strings.TrimPrefix(info.GoVersion, "go"), " "); version != "" {
+		packages = append(packages, PackageDetails{
+			Name:      "stdlib",
+			Version:   version,
+			Ecosystem: GoEcosystem,
+			CompareAs: GoEcosystem,
+		})
+	}
+
+	return packages, nil
+}
+
+var _ Extractor = GoBinaryExtractor{}
+
+func ParseGoBinary(pathToBinary string) ([]PackageDetails, error) {
+	return extractFromFile(pathToBinary, GoBinaryExtractor{})
+}
+
+// FromGoBinary attempts to parse the given file as a binary built by Go
+func FromGoBinary(pathToBinary string) (Lockfile, error) {
+	packages, err := ParseGoBinary(pathToBinary)
+
+	return artifactLockfile(pathToBinary, "go-binary", packages), err
+}
@@ -0,0 +1,170 @@
+package lockfile_test
+
+import (
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/google/osv-scanner/pkg/lockfile"
+)
+
+func TestGoBinaryExtractor_ShouldExtract(t *testing.T) {
+	t.Parallel()
+
+	// the binary of the tests is built by Go, so it has the information about how it was built
+	executable, err := os.Executable()
+	if err != nil {
+		t.Fatalf("could not find the test binary: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{
+			name: "",
+			path: "",
+			want: false,
+		},
+		{
+			name: "",
+			path: executable,
+			want: true,
+		},
+		{
+			name: "",
+			path: "fixtures/go-binary/stripped-modinfo",
+			want: true,
+		},
+		{
+			name: "",
+			path: "fixtures/go-binary/stripped-paths",
+			want: true,
+		},
+		{
+			name: "",
+			path: "fixtures/go-binary/not-a-binary.txt",
+			want: false,
+		},
+		{
+			name: "",
+			path: "fixtures/go-binary/does-not-exist",
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e := lockfile.GoBinaryExtractor{}
+			got := e.ShouldExtract(tt.path)
+			if got != tt.want {
+				t.Errorf("Extract() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseGoBinary_NotABinary(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseGoBinary("fixtures/go-binary/not-a-binary.txt")
+
+	expectErrContaining(t, err, "could not extract from")
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseGoBinary_TestBinary(t *testing.T) {
+	t.Parallel()
+
+	executable, err := os.Executable()
+	if err != nil {
+		t.Fatalf("could not find the test binary: %v", err)
+	}
+
+	packages, err := lockfile.ParseGoBinary(executable)
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	version, _, _ := strings.Cut(strings.TrimPrefix(runtime.Version(), "go"), " ")
+
+	expectPackage(t, packages, lockfile.PackageDetails{
+		Name:      "stdlib",
+		Version:   version,
+		Ecosystem: lockfile.GoEcosystem,
+		CompareAs: lockfile.GoEcosystem,
+	})
+}
+
+func TestParseGoBinary_StrippedWithModInfo(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseGoBinary("fixtures/go-binary/stripped-modinfo")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "example.com/app",
+			Version:   "1.2.0",
+			Ecosystem: lockfile.GoEcosystem,
+			CompareAs: lockfile.GoEcosystem,
+		},
+		{
+			Name:      "github.com/gin-gonic/gin",
+			Version:   "1.9.1",
+			Ecosystem: lockfile.GoEcosystem,
+			CompareAs: lockfile.GoEcosystem,
+		},
+		{
+			Name:      "golang.org/x/net",
+			Version:   "0.19.0",
+			Ecosystem: lockfile.GoEcosystem,
+			CompareAs: lockfile.GoEcosystem,
+		},
+		{
+			Name:      "stdlib",
+			Version:   "1.22.1",
+			Ecosystem: lockfile.GoEcosystem,
+			CompareAs: lockfile.GoEcosystem,
+		},
+	})
+}
+
+func TestParseGoBinary_StrippedWithSourcePaths(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseGoBinary("fixtures/go-binary/stripped-paths")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "github.com/BurntSushi/toml",
+			Version:   "1.3.2",
+			Ecosystem: lockfile.GoEcosystem,
+			CompareAs: lockfile.GoEcosystem,
+		},
+		{
+			Name:      "github.com/gin-gonic/gin",
+			Version:   "1.9.1",
+			Ecosystem: lockfile.GoEcosystem,
+			CompareAs: lockfile.GoEcosystem,
+		},
+		{
+			Name:      "gopkg.in/yaml.v3",
+			Version:   "3.0.1",
+			Ecosystem: lockfile.GoEcosystem,
+			CompareAs: lockfile.GoEcosystem,
+		},
+	})
+}
@@ -0,0 +1,282 @@
+package lockfile
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/osv-scanner/internal/cachedregexp"
+)
+
+type JavaArchiveExtractor struct{}
+
+func (e JavaArchiveExtractor) ShouldExtract(path string) bool {
+	return isJavaArchive(filepath.ToSlash(path))
+}
+
+// parsePomProperties returns the package of the pom.properties that Maven writes into the
+// archives that it builds, which says what the coordinates of the artifact are
+func parsePomProperties(r io.Reader) PackageDetails {
+	properties := map[string]string{}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if ok {
+			properties[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+
+	if properties["groupId"] == "" || properties["artifactId"] == "" || properties["version"] == "" {
+		return PackageDetails{}
+	}
+
+	return PackageDetails{
+		Name:      properties["groupId"] + ":" + properties["artifactId"],
+		Version:   properties["version"],
+		Ecosystem: MavenEcosystem,
+		CompareAs: MavenEcosystem,
+	}
+}
+
+// maxJavaArchiveDepth is how deeply archives that are nested in one another are opened, like the jars
+// in the WEB-INF/lib of a war in an ear, so that archives that are crafted to nest forever are not
+const maxJavaArchiveDepth = 8
+
+// knownMavenGroupIDs are the groups of artifacts that are often embedded in applications without their
+// pom.properties, and whose manifests do not say what their group is, so that they can still be identified
+// from the name of their archive
+var knownMavenGroupIDs = map[string]string{
+	"commons-collections": "commons-collections",
+	"commons-text":        "org.apache.commons",
+	"jackson-databind":    "com.fasterxml.jackson.core",
+	"log4j":               "log4j",
+	"log4j-api":           "org.apache.logging.log4j",
+	"log4j-core":          "org.apache.logging.log4j",
+	"snakeyaml":           "org.yaml",
+	"spring-beans":        "org.springframework",
+	"spring-core":         "org.springframework",
+	"spring-webmvc":       "org.springframework",
+	"struts2-core":        "org.apache.struts",
+	"xstream":             "com.thoughtworks.xstream",
+}
+
+// isJavaArchive returns whether the name is of a jar, war, or ear
+func isJavaArchive(name string) bool {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".jar", ".war", ".ear":
+		return true
+	}
+
+	return false
+}
+
+// parseJavaManifest returns the attributes of the main section of the MANIFEST.MF of a Java archive,
+// whose values are continued on the lines after them that start with a space
+func parseJavaManifest(r io.Reader) map[string]string {
+	attributes := map[string]string{}
+	key := ""
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+
+		// the main section ends at the first blank line, after which are the sections of each entry
+		if line == "" {
+			break
+		}
+
+		if strings.HasPrefix(line, " ") {
+			if key != "" {
+				attributes[key] += line[1:]
+			}
+
+			continue
+		}
+
+		k, value, ok := strings.Cut(line, ":")
+		if !ok {
+			key = ""
+			continue
+		}
+
+		key = strings.TrimSpace(k)
+		attributes[key] = strings.TrimSpace(value)
+	}
+
+	return attributes
+}
+
+// javaArchiveFilename splits the name of a Java archive into the artifact and the version that Maven
+// and Gradle name archives after, like "log4j-core-2.14.1.jar", where the version is empty if it has none
+func javaArchiveFilename(name string) (string, string) {
+	name = filepath.ToSlash(name)
+	name = strings.TrimSuffix(path.Base(name), path.Ext(name))
+
+	match := cachedregexp.MustCompile(`^(.+?)-(\d+(?:\.\d+)*(?:[.-][A-Za-z0-9]+)*)$`).FindStringSubmatch(name)
+	if match == nil {
+		return name, ""
+	}
+
+	return match[1], match[2]
+}
+
+// identifyJavaArchive identifies the artifact that a Java archive without a pom.properties is of from
+// the attributes of its manifest and its name, returning an empty package if its group is not known
+func identifyJavaArchive(name string, manifest map[string]string) PackageDetails {
+	artifactID, version := javaArchiveFilename(name)
+
+	if v := manifest["Implementation-Version"]; v != "" {
+		version = v
+	} else if v := manifest["Bundle-Version"]; v != "" {
+		version = v
+	}
+
+	groupID := manifest["Implementation-Vendor-Id"]
+	if groupID == "" {
+		// bundles are often named after the group and artifact, like "com.fasterxml.jackson.core.jackson-databind"
+		if symbolicName, _, _ := strings.Cut(manifest["Bundle-SymbolicName"], ";"); strings.HasSuffix(symbolicName, "."+artifactID) {
+			groupID = strings.TrimSuffix(symbolicName, "."+artifactID)
+		}
+	}
+	if groupID == "" {
+		groupID = knownMavenGroupIDs[artifactID]
+	}
+
+	if groupID == "" || artifactID == "" || version == "" {
+		return PackageDetails{}
+	}
+
+	return PackageDetails{
+		Name:      groupID + ":" + artifactID,
+		Version:   version,
+		Ecosystem: MavenEcosystem,
+		CompareAs: MavenEcosystem,
+	}
+}
+
+// readZipFile reads all of a file in a Java archive
+func readZipFile(file *zip.File) ([]byte, error) {
+	r, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	return io.ReadAll(r)
+}
+
+// extractJavaArchive extracts the Maven artifacts of a Java archive and of the archives that are
+// nested in it, such as the libraries in the WEB-INF/lib of a war or the BOOT-INF/lib of a Spring Boot
+// jar. Archives that do not have a pom.properties are identified from their manifest and name instead
+func extractJavaArchive(name string, archive *zip.Reader, depth int) ([]PackageDetails, error) {
+	var packages []PackageDetails
+	var manifest map[string]string
+	hasPomProperties := false
+
+	for _, file := range archive.File {
+		switch {
+		case path.Base(file.Name) == "pom.properties" && strings.HasPrefix(file.Name, "META-INF/maven/"):
+			hasPomProperties = true
+
+			content, err := readZipFile(file)
+			if err != nil {
+				return nil, fmt.Errorf("could not extract %s from %s: %w", file.Name, name, err)
+			}
+
+			if pkg := parsePomProperties(bytes.NewReader(content)); pkg.Name != "" {
+				packages = append(packages, pkg)
+			}
+		case file.Name == "META-INF/MANIFEST.MF":
+			content, err := readZipFile(file)
+			if err != nil {
+				return nil, fmt.Errorf("could not extract %s from %s: %w", file.Name, name, err)
+			}
+
+			manifest = parseJavaManifest(bytes.NewReader(content))
+		case isJavaArchive(file.Name) && depth < maxJavaArchiveDepth:
+			content, err := readZipFile(file)
+			if err != nil {
+				return nil, fmt.Errorf("could not extract %s from %s: %w", file.Name, name, err)
+			}
+
+			// archives that are nested can be named like a Java archive without being a zip, so they are skipped
+			nested, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+			if err != nil {
+				continue
+			}
+
+			pkgs, err := extractJavaArchive(name+"!/"+file.Name, nested, depth+1)
+			if err != nil {
+				return nil, err
+			}
+
+			packages = append(packages, pkgs...)
+		}
+	}
+
+	if !hasPomProperties {
+		if pkg := identifyJavaArchive(name, manifest); pkg.Name != "" {
+			packages = append(packages, pkg)
+		}
+	}
+
+	return packages, nil
+}
+
+// Extract extracts the Maven artifacts that a Java archive is made of, based on the pom.properties that
+// Maven writes into it for the artifact itself and for those that are shaded into it, along with those of
+// the archives that are nested in it. Each artifact is only reported once, even if it is nested more than once
+func (e JavaArchiveExtractor) Extract(f DepFile) ([]PackageDetails, error) {
+	b, err := io.ReadAll(f)
+	if err != nil {
+		return []PackageDetails{}, fmt.Errorf("could not extract from %s: %w", f.Path(), err)
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		return []PackageDetails{}, fmt.Errorf("could not extract from %s: %w", f.Path(), err)
+	}
+
+	found, err := extractJavaArchive(f.Path(), archive, 0)
+	if err != nil {
+		return []PackageDetails{}, err
+	}
+
+	packages := make([]PackageDetails, 0, len(found))
+	seen := make(map[string]bool)
+
+	for _, pkg := range found {
+		if seen[pkg.Name+"@"+pkg.Version] {
+			continue
+		}
+		seen[pkg.Name+"@"+pkg.Version] = true
+
+		packages = append(packages, pkg)
+	}
+
+	return packages, nil
+}
+
+var _ Extractor = JavaArchiveExtractor{}
+
+func ParseJavaArchive(pathToArchive string) ([]PackageDetails, error) {
+	return extractFromFile(pathToArchive, JavaArchiveExtractor{})
+}
+
+// FromJavaArchive attempts to parse the given file as a jar, war, or ear
+func FromJavaArchive(pathToArchive string) (Lockfile, error) {
+	packages, err := ParseJavaArchive(pathToArchive)
+
+	return artifactLockfile(pathToArchive, "java-archive", packages), err
+}
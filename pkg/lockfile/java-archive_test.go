@@ -0,0 +1,202 @@
+package lockfile_test
+
+import (
+	"io/fs"
+	"testing"
+
+	"github.com/google/osv-scanner/pkg/lockfile"
+)
+
+func TestJavaArchiveExtractor_ShouldExtract(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{
+			name: "",
+			path: "",
+			want: false,
+		},
+		{
+			name: "",
+			path: "app.jar",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/my/app.jar",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/my/app.war",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/my/app.EAR",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/my/app.jar/file",
+			want: false,
+		},
+		{
+			name: "",
+			path: "path/to/my/app.jar.sha1",
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e := lockfile.JavaArchiveExtractor{}
+			got := e.ShouldExtract(tt.path)
+			if got != tt.want {
+				t.Errorf("Extract() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseJavaArchive_FileDoesNotExist(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseJavaArchive("fixtures/java-archive/does-not-exist")
+
+	expectErrIs(t, err, fs.ErrNotExist)
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseJavaArchive_NotAZip(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseJavaArchive("fixtures/java-archive/not-a-zip.jar")
+
+	expectErrContaining(t, err, "could not extract from")
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseJavaArchive_NoPomProperties(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseJavaArchive("fixtures/java-archive/no-pom-properties.jar")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseJavaArchive_ShadedArtifacts(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseJavaArchive("fixtures/java-archive/app.jar")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "com.example:app",
+			Version:   "1.0.0",
+			Ecosystem: lockfile.MavenEcosystem,
+			CompareAs: lockfile.MavenEcosystem,
+		},
+		{
+			Name:      "org.apache.logging.log4j:log4j-core",
+			Version:   "2.14.1",
+			Ecosystem: lockfile.MavenEcosystem,
+			CompareAs: lockfile.MavenEcosystem,
+		},
+	})
+}
+
+func TestParseJavaArchive_NestedInWar(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseJavaArchive("fixtures/java-archive/webapp.war")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "com.example:webapp",
+			Version:   "1.0.0",
+			Ecosystem: lockfile.MavenEcosystem,
+			CompareAs: lockfile.MavenEcosystem,
+		},
+		{
+			Name:      "org.apache.logging.log4j:log4j-core",
+			Version:   "2.14.1",
+			Ecosystem: lockfile.MavenEcosystem,
+			CompareAs: lockfile.MavenEcosystem,
+		},
+		{
+			Name:      "com.fasterxml.jackson.core:jackson-databind",
+			Version:   "2.9.10.8",
+			Ecosystem: lockfile.MavenEcosystem,
+			CompareAs: lockfile.MavenEcosystem,
+		},
+		{
+			Name:      "org.apache.commons:commons-lang3",
+			Version:   "3.12.0",
+			Ecosystem: lockfile.MavenEcosystem,
+			CompareAs: lockfile.MavenEcosystem,
+		},
+	})
+}
+
+func TestParseJavaArchive_NestedInEar(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseJavaArchive("fixtures/java-archive/app.ear")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "com.example:webapp",
+			Version:   "1.0.0",
+			Ecosystem: lockfile.MavenEcosystem,
+			CompareAs: lockfile.MavenEcosystem,
+		},
+		{
+			Name:      "org.apache.logging.log4j:log4j-core",
+			Version:   "2.14.1",
+			Ecosystem: lockfile.MavenEcosystem,
+			CompareAs: lockfile.MavenEcosystem,
+		},
+		{
+			Name:      "com.fasterxml.jackson.core:jackson-databind",
+			Version:   "2.9.10.8",
+			Ecosystem: lockfile.MavenEcosystem,
+			CompareAs: lockfile.MavenEcosystem,
+		},
+		{
+			Name:      "org.apache.commons:commons-lang3",
+			Version:   "3.12.0",
+			Ecosystem: lockfile.MavenEcosystem,
+			CompareAs: lockfile.MavenEcosystem,
+		},
+		{
+			Name:      "com.example:my-lib",
+			Version:   "2.0.1",
+			Ecosystem: lockfile.MavenEcosystem,
+			CompareAs: lockfile.MavenEcosystem,
+		},
+	})
+}
@@ -0,0 +1,76 @@
+package lockfile
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+type NodeModulesExtractor struct{}
+
+// ShouldExtract returns whether the file is the package.json of a package that is installed in a
+// node_modules directory, either directly like "node_modules/lodash/package.json" or under its
+// scope like "node_modules/@babel/core/package.json"
+func (e NodeModulesExtractor) ShouldExtract(path string) bool {
+	parts := strings.Split(filepath.ToSlash(path), "/")
+	n := len(parts)
+
+	if n < 3 || parts[n-1] != "package.json" {
+		return false
+	}
+
+	if parts[n-3] == "node_modules" {
+		return !strings.HasPrefix(parts[n-2], "@")
+	}
+
+	return n >= 4 && parts[n-4] == "node_modules" && strings.HasPrefix(parts[n-3], "@")
+}
+
+// parseNpmPackageJSON parses the package that a package.json is of
+func parseNpmPackageJSON(r io.Reader) ([]PackageDetails, error) {
+	var manifest struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	}
+
+	if err := json.NewDecoder(r).Decode(&manifest); err != nil {
+		return []PackageDetails{}, err
+	}
+
+	// the package.json of packages installed from git or a path might not have a version
+	if manifest.Name == "" || manifest.Version == "" {
+		return []PackageDetails{}, nil
+	}
+
+	return []PackageDetails{{
+		Name:      manifest.Name,
+		Version:   manifest.Version,
+		Ecosystem: NpmEcosystem,
+		CompareAs: NpmEcosystem,
+	}}, nil
+}
+
+// Extract extracts the package that is installed in a node_modules directory from its package.json
+func (e NodeModulesExtractor) Extract(f DepFile) ([]PackageDetails, error) {
+	packages, err := parseNpmPackageJSON(f)
+	if err != nil {
+		return []PackageDetails{}, fmt.Errorf("could not extract from %s: %w", f.Path(), err)
+	}
+
+	return packages, nil
+}
+
+var _ Extractor = NodeModulesExtractor{}
+
+func ParseNodeModules(pathToPackageJSON string) ([]PackageDetails, error) {
+	return extractFromFile(pathToPackageJSON, NodeModulesExtractor{})
+}
+
+// FromNodeModules attempts to parse the given file as the package.json of an installed npm package
+func FromNodeModules(pathToPackageJSON string) (Lockfile, error) {
+	packages, err := ParseNodeModules(pathToPackageJSON)
+
+	return artifactLockfile(pathToPackageJSON, "node-modules", packages), err
+}
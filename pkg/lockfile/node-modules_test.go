@@ -0,0 +1,145 @@
+package lockfile_test
+
+import (
+	"io/fs"
+	"testing"
+
+	"github.com/google/osv-scanner/pkg/lockfile"
+)
+
+func TestNodeModulesExtractor_ShouldExtract(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{
+			name: "",
+			path: "",
+			want: false,
+		},
+		{
+			name: "",
+			path: "package.json",
+			want: false,
+		},
+		{
+			name: "",
+			path: "path/to/my/package.json",
+			want: false,
+		},
+		{
+			name: "",
+			path: "path/to/my/node_modules/lodash/package.json",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/my/node_modules/@babel/core/package.json",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/my/node_modules/a/node_modules/b/package.json",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/my/node_modules/@babel/package.json",
+			want: false,
+		},
+		{
+			name: "",
+			path: "path/to/my/node_modules/lodash/fp/package.json",
+			want: false,
+		},
+		{
+			name: "",
+			path: "path/to/my/node_modules/lodash/package.json/file",
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e := lockfile.NodeModulesExtractor{}
+			got := e.ShouldExtract(tt.path)
+			if got != tt.want {
+				t.Errorf("Extract() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseNodeModules_FileDoesNotExist(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseNodeModules("fixtures/node-modules/does-not-exist")
+
+	expectErrIs(t, err, fs.ErrNotExist)
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseNodeModules_InvalidJSON(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseNodeModules("fixtures/node-modules/not-json.txt")
+
+	expectErrContaining(t, err, "could not extract from")
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseNodeModules_NoVersion(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseNodeModules("fixtures/node-modules/node_modules/from-git/package.json")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseNodeModules_Package(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseNodeModules("fixtures/node-modules/node_modules/lodash/package.json")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "lodash",
+			Version:   "4.17.20",
+			Ecosystem: lockfile.NpmEcosystem,
+			CompareAs: lockfile.NpmEcosystem,
+		},
+	})
+}
+
+func TestParseNodeModules_ScopedPackage(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseNodeModules("fixtures/node-modules/node_modules/@babel/core/package.json")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "@babel/core",
+			Version:   "7.22.5",
+			Ecosystem: lockfile.NpmEcosystem,
+			CompareAs: lockfile.NpmEcosystem,
+		},
+	})
+}
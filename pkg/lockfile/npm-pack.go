@@ -0,0 +1,116 @@
+package lockfile
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/google/osv-scanner/internal/cachedregexp"
+)
+
+type NpmPackExtractor struct{}
+
+// ShouldExtract returns whether the file is the tarball of an npm package, as made by `npm pack` or
+// downloaded from a registry, which are named after the package and its version like "lodash-4.17.21.tgz",
+// with the scope of scoped packages as a prefix like "babel-core-7.24.0.tgz"
+func (e NpmPackExtractor) ShouldExtract(path string) bool {
+	return cachedregexp.MustCompile(`^[a-z0-9][a-z0-9._-]*-\d+\.\d+\.\d+[0-9A-Za-z.+-]*\.tgz$`).MatchString(filepath.Base(path))
+}
+
+// npmShrinkwrapPackages returns the packages that an npm-shrinkwrap.json locks, which are installed
+// at exactly those versions along with the package, except for its dev dependencies
+func npmShrinkwrapPackages(r io.Reader) (map[string]PackageDetails, error) {
+	var shrinkwrap *NpmLockfile
+
+	if err := json.NewDecoder(r).Decode(&shrinkwrap); err != nil {
+		return nil, err
+	}
+
+	details := map[string]PackageDetails{}
+
+	if shrinkwrap == nil {
+		return details, nil
+	}
+
+	for key, pkg := range parseNpmLock(*shrinkwrap) {
+		if !slices.Contains(pkg.DepGroups, "dev") {
+			details[key] = pkg
+		}
+	}
+
+	return details, nil
+}
+
+// Extract extracts the package that an npm tarball is of from the package.json in the directory that it
+// is a tarball of, which is "package" for those made by `npm pack`, along with its bundled dependencies in
+// the node_modules of that directory and the packages that its npm-shrinkwrap.json locks, so that packages
+// can be checked before they are installed
+func (e NpmPackExtractor) Extract(f DepFile) ([]PackageDetails, error) {
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return []PackageDetails{}, fmt.Errorf("could not extract from %s: %w", f.Path(), err)
+	}
+	defer gz.Close()
+
+	details := map[string]PackageDetails{}
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return []PackageDetails{}, fmt.Errorf("could not extract from %s: %w", f.Path(), err)
+		}
+
+		name := path.Clean(header.Name)
+		isTopLevel := path.Dir(name) != "." && !strings.Contains(path.Dir(name), "/")
+
+		var pkgs []PackageDetails
+		var locked map[string]PackageDetails
+
+		switch {
+		case header.Typeflag != tar.TypeReg:
+			continue
+		case isTopLevel && path.Base(name) == "package.json", NodeModulesExtractor{}.ShouldExtract(name):
+			pkgs, err = parseNpmPackageJSON(tr)
+		case isTopLevel && path.Base(name) == "npm-shrinkwrap.json":
+			locked, err = npmShrinkwrapPackages(tr)
+		}
+
+		if err != nil {
+			return []PackageDetails{}, fmt.Errorf("could not extract %s from %s: %w", name, f.Path(), err)
+		}
+
+		for _, pkg := range pkgs {
+			details[pkg.Name+"@"+pkg.Version] = pkg
+		}
+
+		for key, pkg := range locked {
+			details[key] = pkg
+		}
+	}
+
+	return pkgDetailsMapToSlice(details), nil
+}
+
+var _ Extractor = NpmPackExtractor{}
+
+func ParseNpmPack(pathToTarball string) ([]PackageDetails, error) {
+	return extractFromFile(pathToTarball, NpmPackExtractor{})
+}
+
+// FromNpmPack attempts to parse the given file as the tarball of an npm package
+func FromNpmPack(pathToTarball string) (Lockfile, error) {
+	packages, err := ParseNpmPack(pathToTarball)
+
+	return artifactLockfile(pathToTarball, "npm-pack", packages), err
+}
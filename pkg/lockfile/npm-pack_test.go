@@ -0,0 +1,198 @@
+package lockfile_test
+
+import (
+	"io/fs"
+	"testing"
+
+	"github.com/google/osv-scanner/pkg/lockfile"
+)
+
+func TestNpmPackExtractor_ShouldExtract(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{
+			name: "",
+			path: "",
+			want: false,
+		},
+		{
+			name: "",
+			path: "left-pad-1.3.0.tgz",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/my/babel-core-7.24.0.tgz",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/my/typescript-5.5.0-beta.tgz",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/my/package.tgz",
+			want: false,
+		},
+		{
+			name: "",
+			path: "path/to/my/left-pad-1.3.0.tar.gz",
+			want: false,
+		},
+		{
+			name: "",
+			path: "path/to/my/package.json",
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e := lockfile.NpmPackExtractor{}
+			got := e.ShouldExtract(tt.path)
+			if got != tt.want {
+				t.Errorf("Extract() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseNpmPack_FileDoesNotExist(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseNpmPack("fixtures/npm-pack/does-not-exist-1.0.0.tgz")
+
+	expectErrIs(t, err, fs.ErrNotExist)
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseNpmPack_NotGzip(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseNpmPack("fixtures/npm-pack/not-gzip-1.0.0.tgz")
+
+	expectErrContaining(t, err, "could not extract from")
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseNpmPack_InvalidJSON(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseNpmPack("fixtures/npm-pack/invalid-json-1.0.0.tgz")
+
+	expectErrContaining(t, err, "could not extract package/package.json from")
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseNpmPack(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseNpmPack("fixtures/npm-pack/left-pad-1.3.0.tgz")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "left-pad",
+			Version:   "1.3.0",
+			Ecosystem: lockfile.NpmEcosystem,
+			CompareAs: lockfile.NpmEcosystem,
+		},
+	})
+}
+
+func TestParseNpmPack_NotInPackageDirectory(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseNpmPack("fixtures/npm-pack/node-uuid-1.4.0.tgz")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "node-uuid",
+			Version:   "1.4.0",
+			Ecosystem: lockfile.NpmEcosystem,
+			CompareAs: lockfile.NpmEcosystem,
+		},
+	})
+}
+
+func TestParseNpmPack_BundledDependencies(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseNpmPack("fixtures/npm-pack/with-bundled-1.0.0.tgz")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "with-bundled",
+			Version:   "1.0.0",
+			Ecosystem: lockfile.NpmEcosystem,
+			CompareAs: lockfile.NpmEcosystem,
+		},
+		{
+			Name:      "lodash",
+			Version:   "4.17.20",
+			Ecosystem: lockfile.NpmEcosystem,
+			CompareAs: lockfile.NpmEcosystem,
+		},
+		{
+			Name:      "@babel/code-frame",
+			Version:   "7.0.0",
+			Ecosystem: lockfile.NpmEcosystem,
+			CompareAs: lockfile.NpmEcosystem,
+		},
+		{
+			Name:      "debug",
+			Version:   "2.6.8",
+			Ecosystem: lockfile.NpmEcosystem,
+			CompareAs: lockfile.NpmEcosystem,
+		},
+		{
+			Name:      "ms",
+			Version:   "2.0.0",
+			Ecosystem: lockfile.NpmEcosystem,
+			CompareAs: lockfile.NpmEcosystem,
+		},
+	})
+}
+
+func TestParseNpmPack_Shrinkwrap(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseNpmPack("fixtures/npm-pack/with-shrinkwrap-2.0.0.tgz")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "with-shrinkwrap",
+			Version:   "2.0.0",
+			Ecosystem: lockfile.NpmEcosystem,
+			CompareAs: lockfile.NpmEcosystem,
+		},
+		{
+			Name:      "minimist",
+			Version:   "1.2.5",
+			Ecosystem: lockfile.NpmEcosystem,
+			CompareAs: lockfile.NpmEcosystem,
+		},
+	})
+}
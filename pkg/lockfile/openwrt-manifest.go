@@ -0,0 +1,75 @@
+package lockfile
+
+import (
+	"bufio"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/osv-scanner/internal/cachedregexp"
+)
+
+const OpenWrtEcosystem Ecosystem = "OpenWrt"
+
+func ParseOpenWrtManifest(pathToLockfile string) ([]PackageDetails, error) {
+	return extractFromFile(pathToLockfile, OpenWrtManifestExtractor{})
+}
+
+type OpenWrtManifestExtractor struct{}
+
+func (e OpenWrtManifestExtractor) ShouldExtract(path string) bool {
+	return strings.HasSuffix(path, ".manifest")
+}
+
+// Extract extracts the packages of an OpenWrt image from its manifest, or from the output
+// of "opkg list-installed", which both have a line like "busybox - 1.36.1-1" for each package
+func (e OpenWrtManifestExtractor) Extract(f DepFile) ([]PackageDetails, error) {
+	re := cachedregexp.MustCompile(`^(\S+) - (\S+)`)
+
+	scanner := bufio.NewScanner(f)
+
+	packages := make([]PackageDetails, 0)
+
+	for scanner.Scan() {
+		match := re.FindStringSubmatch(strings.TrimSpace(scanner.Text()))
+
+		if match == nil {
+			continue
+		}
+
+		packages = append(packages, PackageDetails{
+			Name:      match[1],
+			Version:   match[2],
+			Ecosystem: OpenWrtEcosystem,
+			CompareAs: OpenWrtEcosystem,
+		})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return packages, fmt.Errorf("error while scanning %s: %w", f.Path(), err)
+	}
+
+	return packages, nil
+}
+
+var _ Extractor = OpenWrtManifestExtractor{}
+
+// FromOpenWrtManifest attempts to parse the given file as an "openwrt-manifest" lockfile,
+// which lists the packages that are installed in an OpenWrt image.
+func FromOpenWrtManifest(pathToManifest string) (Lockfile, error) {
+	packages, err := ParseOpenWrtManifest(pathToManifest)
+
+	sort.Slice(packages, func(i, j int) bool {
+		if packages[i].Name == packages[j].Name {
+			return packages[i].Version < packages[j].Version
+		}
+
+		return packages[i].Name < packages[j].Name
+	})
+
+	return Lockfile{
+		FilePath: pathToManifest,
+		ParsedAs: "openwrt-manifest",
+		Packages: packages,
+	}, err
+}
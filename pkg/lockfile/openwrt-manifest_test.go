@@ -0,0 +1,138 @@
+package lockfile_test
+
+import (
+	"io/fs"
+	"testing"
+
+	"github.com/google/osv-scanner/pkg/lockfile"
+)
+
+func TestParseOpenWrtManifest_FileDoesNotExist(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseOpenWrtManifest("fixtures/openwrt/does-not-exist")
+
+	expectErrIs(t, err, fs.ErrNotExist)
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseOpenWrtManifest_Empty(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseOpenWrtManifest("fixtures/openwrt/empty.manifest")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseOpenWrtManifest_NotAManifest(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseOpenWrtManifest("fixtures/openwrt/not-a-manifest.manifest")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseOpenWrtManifest_OnePackage(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseOpenWrtManifest("fixtures/openwrt/one-package.manifest")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "busybox",
+			Version:   "1.36.1-1",
+			Ecosystem: lockfile.OpenWrtEcosystem,
+			CompareAs: lockfile.OpenWrtEcosystem,
+		},
+	})
+}
+
+func TestParseOpenWrtManifest_MultiplePackages(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseOpenWrtManifest("fixtures/openwrt/multiple-packages.manifest")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "base-files",
+			Version:   "1562-r24106-10cc5fcd00",
+			Ecosystem: lockfile.OpenWrtEcosystem,
+			CompareAs: lockfile.OpenWrtEcosystem,
+		},
+		{
+			Name:      "busybox",
+			Version:   "1.36.1-1",
+			Ecosystem: lockfile.OpenWrtEcosystem,
+			CompareAs: lockfile.OpenWrtEcosystem,
+		},
+		{
+			Name:      "dropbear",
+			Version:   "2022.82-6",
+			Ecosystem: lockfile.OpenWrtEcosystem,
+			CompareAs: lockfile.OpenWrtEcosystem,
+		},
+		{
+			Name:      "libustream-mbedtls20201210",
+			Version:   "2023.02.28~498f6e26-1",
+			Ecosystem: lockfile.OpenWrtEcosystem,
+			CompareAs: lockfile.OpenWrtEcosystem,
+		},
+	})
+}
+
+func TestFromOpenWrtManifest_MultiplePackages(t *testing.T) {
+	t.Parallel()
+
+	lockf, err := lockfile.FromOpenWrtManifest("fixtures/openwrt/multiple-packages.manifest")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	if lockf.ParsedAs != "openwrt-manifest" {
+		t.Errorf("Expected ParsedAs to be \"openwrt-manifest\" but got \"%s\"", lockf.ParsedAs)
+	}
+
+	expectPackages(t, lockf.Packages, []lockfile.PackageDetails{
+		{
+			Name:      "base-files",
+			Version:   "1562-r24106-10cc5fcd00",
+			Ecosystem: lockfile.OpenWrtEcosystem,
+			CompareAs: lockfile.OpenWrtEcosystem,
+		},
+		{
+			Name:      "busybox",
+			Version:   "1.36.1-1",
+			Ecosystem: lockfile.OpenWrtEcosystem,
+			CompareAs: lockfile.OpenWrtEcosystem,
+		},
+		{
+			Name:      "dropbear",
+			Version:   "2022.82-6",
+			Ecosystem: lockfile.OpenWrtEcosystem,
+			CompareAs: lockfile.OpenWrtEcosystem,
+		},
+		{
+			Name:      "libustream-mbedtls20201210",
+			Version:   "2023.02.28~498f6e26-1",
+			Ecosystem: lockfile.OpenWrtEcosystem,
+			CompareAs: lockfile.OpenWrtEcosystem,
+		},
+	})
+}
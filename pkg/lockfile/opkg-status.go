@@ -0,0 +1,98 @@
+package lockfile
+
+import (
+	"bufio"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// opkgStatusPaths are where opkg keeps the status of the packages that it has installed, which is
+// under /usr/lib on OpenWrt as /var is not persisted, and under /var/lib on other distributions
+var opkgStatusPaths = []string{"usr/lib/opkg/status", "var/lib/opkg/status"}
+
+func ParseOpkgStatus(pathToStatus string) ([]PackageDetails, error) {
+	return extractFromFile(pathToStatus, OpkgStatusExtractor{})
+}
+
+type OpkgStatusExtractor struct{}
+
+func (e OpkgStatusExtractor) ShouldExtract(path string) bool {
+	path = filepath.ToSlash(path)
+
+	for _, statusPath := range opkgStatusPaths {
+		if path == statusPath || strings.HasSuffix(path, "/"+statusPath) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Extract extracts the packages that are installed according to the status file of opkg, which has
+// the same format as the status file of dpkg, and are reported using the OpenWrt ecosystem
+func (e OpkgStatusExtractor) Extract(f DepFile) ([]PackageDetails, error) {
+	scanner := bufio.NewScanner(f)
+	packageGroups := groupDpkgPackageLines(scanner)
+
+	packages := make([]PackageDetails, 0, len(packageGroups))
+
+	for _, group := range packageGroups {
+		var name, version string
+		installed := true
+
+		for _, line := range group {
+			key, value, _ := strings.Cut(line, ":")
+			value = strings.TrimSpace(value)
+
+			switch key {
+			case "Package":
+				name = value
+			case "Version":
+				version = value
+			case "Status":
+				installed = strings.HasSuffix(value, " installed")
+			}
+		}
+
+		if name == "" || version == "" || !installed {
+			continue
+		}
+
+		packages = append(packages, PackageDetails{
+			Name:      name,
+			Version:   version,
+			Ecosystem: OpenWrtEcosystem,
+			CompareAs: OpenWrtEcosystem,
+		})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return packages, fmt.Errorf("error while scanning %s: %w", f.Path(), err)
+	}
+
+	return packages, nil
+}
+
+var _ Extractor = OpkgStatusExtractor{}
+
+// FromOpkgStatus attempts to parse the given file as an "opkg-status" lockfile,
+// which is used by opkg to record the packages that are installed
+func FromOpkgStatus(pathToStatus string) (Lockfile, error) {
+	packages, err := ParseOpkgStatus(pathToStatus)
+
+	sort.Slice(packages, func(i, j int) bool {
+		if packages[i].Name == packages[j].Name {
+			return packages[i].Version < packages[j].Version
+		}
+
+		return packages[i].Name < packages[j].Name
+	})
+
+	return Lockfile{
+		FilePath: pathToStatus,
+		ParsedAs: "opkg-status",
+		Packages: packages,
+	}, err
+}
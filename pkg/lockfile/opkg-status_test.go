@@ -0,0 +1,112 @@
+package lockfile_test
+
+import (
+	"io/fs"
+	"testing"
+
+	"github.com/google/osv-scanner/pkg/lockfile"
+)
+
+func TestOpkgStatusExtractor_ShouldExtract(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{
+			name: "",
+			path: "",
+			want: false,
+		},
+		{
+			name: "",
+			path: "usr/lib/opkg/status",
+			want: true,
+		},
+		{
+			name: "",
+			path: "/tmp/osv-scanner-firmware-1/usr/lib/opkg/status",
+			want: true,
+		},
+		{
+			name: "",
+			path: "var/lib/opkg/status",
+			want: true,
+		},
+		{
+			name: "",
+			path: "usr/lib/opkg/info/busybox.control",
+			want: false,
+		},
+		{
+			name: "",
+			path: "var/lib/dpkg/status",
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e := lockfile.OpkgStatusExtractor{}
+			got := e.ShouldExtract(tt.path)
+			if got != tt.want {
+				t.Errorf("Extract() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseOpkgStatus_FileDoesNotExist(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseOpkgStatus("fixtures/opkg/does-not-exist")
+
+	expectErrIs(t, err, fs.ErrNotExist)
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseOpkgStatus_Empty(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseOpkgStatus("fixtures/opkg/empty")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseOpkgStatus(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseOpkgStatus("fixtures/opkg/status")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "busybox",
+			Version:   "1.36.1-1",
+			Ecosystem: lockfile.OpenWrtEcosystem,
+			CompareAs: lockfile.OpenWrtEcosystem,
+		},
+		{
+			Name:      "dropbear",
+			Version:   "2022.82-5",
+			Ecosystem: lockfile.OpenWrtEcosystem,
+			CompareAs: lockfile.OpenWrtEcosystem,
+		},
+		{
+			Name:      "kernel",
+			Version:   "5.15.137-1-b7aed3b8d5a3c4f3f7c9e6a6a7ba9b86",
+			Ecosystem: lockfile.OpenWrtEcosystem,
+			CompareAs: lockfile.OpenWrtEcosystem,
+		},
+	})
+}
@@ -0,0 +1,184 @@
+package lockfile
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/osv-scanner/internal/cachedregexp"
+	"github.com/google/osv-scanner/internal/semantic"
+)
+
+const BazelEcosystem Ecosystem = "Bazel"
+
+type BazelModuleLockModule struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type BazelModuleLockRepoSpec struct {
+	// older lockfiles describe the rule of a repository with a file and a class name,
+	// while newer ones use a single identifier like "@@rules_go+//go:deps.bzl%go_repository"
+	RuleClassName string         `json:"ruleClassName"`
+	RepoRuleID    string         `json:"repoRuleId"`
+	Attributes    map[string]any `json:"attributes"`
+}
+
+func (spec BazelModuleLockRepoSpec) rule() string {
+	if spec.RuleClassName != "" {
+		return spec.RuleClassName
+	}
+
+	_, rule, _ := strings.Cut(spec.RepoRuleID, "%")
+
+	return rule
+}
+
+func (spec BazelModuleLockRepoSpec) attribute(name string) string {
+	str, _ := spec.Attributes[name].(string)
+
+	return str
+}
+
+type BazelModuleLockExtension struct {
+	GeneratedRepoSpecs map[string]BazelModuleLockRepoSpec `json:"generatedRepoSpecs"`
+}
+
+type BazelModuleLockFile struct {
+	LockFileVersion    int                                            `json:"lockFileVersion"`
+	RegistryFileHashes map[string]any                                 `json:"registryFileHashes"`
+	ModuleDepGraph     map[string]BazelModuleLockModule               `json:"moduleDepGraph"`
+	ModuleExtensions   map[string]map[string]BazelModuleLockExtension `json:"moduleExtensions"`
+}
+
+// bazelModulesFromRegistryFileHashes determines the modules that were resolved from the
+// MODULE.bazel files fetched from the registry, which newer lockfiles record in place
+// of the dependency graph.
+//
+// Every version of a module that is depended on gets fetched, and since Bazel selects
+// the highest of those versions this is what is reported for each module
+func bazelModulesFromRegistryFileHashes(hashes map[string]any) map[string]string {
+	re := cachedregexp.MustCompile(`/modules/([^/]+)/([^/]+)/MODULE\.bazel$`)
+
+	modules := map[string]string{}
+
+	for url := range hashes {
+		match := re.FindStringSubmatch(url)
+
+		if match == nil {
+			continue
+		}
+
+		name, version := match[1], match[2]
+
+		if current, ok := modules[name]; !ok || semantic.MustParse(current, "Bazel").CompareStr(version) < 0 {
+			modules[name] = version
+		}
+	}
+
+	return modules
+}
+
+// bazelExtensionPackage returns the package that a repository generated by a module
+// extension provides, if it is one from a language ecosystem that we know about
+func bazelExtensionPackage(spec BazelModuleLockRepoSpec) (PackageDetails, bool) {
+	switch spec.rule() {
+	// from the "go_deps" extension of gazelle
+	case "go_repository":
+		importPath, version := spec.attribute("importpath"), spec.attribute("version")
+
+		if importPath == "" || version == "" {
+			return PackageDetails{}, false
+		}
+
+		return PackageDetails{
+			Name:      importPath,
+			Version:   strings.TrimPrefix(version, "v"),
+			Ecosystem: GoEcosystem,
+			CompareAs: GoEcosystem,
+		}, true
+	// from the "pip" extension of rules_python
+	case "whl_library":
+		requirement := spec.attribute("requirement")
+
+		if isNotRequirementLine(requirement) {
+			return PackageDetails{}, false
+		}
+
+		return parseLine(requirement), true
+	}
+
+	return PackageDetails{}, false
+}
+
+type BazelModuleLockExtractor struct{}
+
+func (e BazelModuleLockExtractor) ShouldExtract(path string) bool {
+	return filepath.Base(path) == "MODULE.bazel.lock"
+}
+
+func (e BazelModuleLockExtractor) Extract(f DepFile) ([]PackageDetails, error) {
+	var parsedLockfile *BazelModuleLockFile
+
+	err := json.NewDecoder(f).Decode(&parsedLockfile)
+
+	if err != nil {
+		return []PackageDetails{}, fmt.Errorf("could not extract from %s: %w", f.Path(), err)
+	}
+
+	details := map[string]PackageDetails{}
+
+	// the same name could be used by packages from different ecosystems
+	addPackage := func(detail PackageDetails) {
+		details[string(detail.Ecosystem)+":"+detail.Name+"@"+detail.Version] = detail
+	}
+
+	modules := map[string]string{}
+
+	if len(parsedLockfile.ModuleDepGraph) > 0 {
+		for key, module := range parsedLockfile.ModuleDepGraph {
+			// modules that are overridden with a local path or a repository don't have a version
+			if key == "<root>" || module.Version == "" {
+				continue
+			}
+
+			modules[module.Name] = module.Version
+		}
+	} else {
+		modules = bazelModulesFromRegistryFileHashes(parsedLockfile.RegistryFileHashes)
+	}
+
+	for name, version := range modules {
+		addPackage(PackageDetails{
+			Name:      name,
+			Version:   version,
+			Ecosystem: BazelEcosystem,
+			CompareAs: BazelEcosystem,
+		})
+	}
+
+	for _, extension := range parsedLockfile.ModuleExtensions {
+		// extensions can be evaluated separately for different platforms
+		for _, evaluation := range extension {
+			for _, spec := range evaluation.GeneratedRepoSpecs {
+				if detail, ok := bazelExtensionPackage(spec); ok {
+					addPackage(detail)
+				}
+			}
+		}
+	}
+
+	return pkgDetailsMapToSlice(details), nil
+}
+
+var _ Extractor = BazelModuleLockExtractor{}
+
+//nolint:gochecknoinits
+func init() {
+	registerExtractor("MODULE.bazel.lock", BazelModuleLockExtractor{})
+}
+
+func ParseBazelModuleLock(pathToLockfile string) ([]PackageDetails, error) {
+	return extractFromFile(pathToLockfile, BazelModuleLockExtractor{})
+}
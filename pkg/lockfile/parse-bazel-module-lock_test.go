@@ -0,0 +1,154 @@
+package lockfile_test
+
+import (
+	"io/fs"
+	"testing"
+
+	"github.com/google/osv-scanner/pkg/lockfile"
+)
+
+func TestBazelModuleLockExtractor_ShouldExtract(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{
+			name: "",
+			path: "",
+			want: false,
+		},
+		{
+			name: "",
+			path: "MODULE.bazel.lock",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/my/MODULE.bazel.lock",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/my/MODULE.bazel.lock/file",
+			want: false,
+		},
+		{
+			name: "",
+			path: "path/to/my/MODULE.bazel.lock.file",
+			want: false,
+		},
+		{
+			name: "",
+			path: "path.to.my.MODULE.bazel.lock",
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e := lockfile.BazelModuleLockExtractor{}
+			got := e.ShouldExtract(tt.path)
+			if got != tt.want {
+				t.Errorf("Extract() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseBazelModuleLock_FileDoesNotExist(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseBazelModuleLock("fixtures/bazel/does-not-exist")
+
+	expectErrIs(t, err, fs.ErrNotExist)
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseBazelModuleLock_InvalidJson(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseBazelModuleLock("fixtures/bazel/not-json.txt")
+
+	expectErrContaining(t, err, "could not extract from")
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseBazelModuleLock_NoPackages(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseBazelModuleLock("fixtures/bazel/empty.lock")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseBazelModuleLock_ModuleDepGraph(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseBazelModuleLock("fixtures/bazel/module-dep-graph.lock")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "rules_go",
+			Version:   "0.41.0",
+			Ecosystem: lockfile.BazelEcosystem,
+			CompareAs: lockfile.BazelEcosystem,
+		},
+		{
+			Name:      "abseil-cpp",
+			Version:   "20230802.0",
+			Ecosystem: lockfile.BazelEcosystem,
+			CompareAs: lockfile.BazelEcosystem,
+		},
+		{
+			Name:      "github.com/google/uuid",
+			Version:   "1.3.0",
+			Ecosystem: lockfile.GoEcosystem,
+			CompareAs: lockfile.GoEcosystem,
+		},
+	})
+}
+
+func TestParseBazelModuleLock_RegistryFileHashes(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseBazelModuleLock("fixtures/bazel/registry-file-hashes.lock")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "abseil-cpp",
+			Version:   "20230802.0",
+			Ecosystem: lockfile.BazelEcosystem,
+			CompareAs: lockfile.BazelEcosystem,
+		},
+		{
+			Name:      "rules_python",
+			Version:   "0.31.0",
+			Ecosystem: lockfile.BazelEcosystem,
+			CompareAs: lockfile.BazelEcosystem,
+		},
+		{
+			Name:      "requests",
+			Version:   "2.31.0",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+		},
+	})
+}
@@ -0,0 +1,336 @@
+package lockfile
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+type BunLockWorkspace struct {
+	Name                 string            `json:"name"`
+	Dependencies         map[string]string `json:"dependencies"`
+	DevDependencies      map[string]string `json:"devDependencies"`
+	OptionalDependencies map[string]string `json:"optionalDependencies"`
+	PeerDependencies     map[string]string `json:"peerDependencies"`
+}
+
+// BunLockPackageInfo is the metadata of a package in a bun.lock, which is the first object in its entry
+type BunLockPackageInfo struct {
+	Dependencies         map[string]string `json:"dependencies"`
+	OptionalDependencies map[string]string `json:"optionalDependencies"`
+	PeerDependencies     map[string]string `json:"peerDependencies"`
+}
+
+type BunLockfile struct {
+	Version    int                          `json:"lockfileVersion"`
+	Workspaces map[string]BunLockWorkspace  `json:"workspaces"`
+	Packages   map[string][]json.RawMessage `json:"packages"`
+}
+
+const BunEcosystem = NpmEcosystem
+
+// bunBinaryLockfileHeader is the start of the binary bun.lockb format
+const bunBinaryLockfileHeader = "#!/usr/bin/env bun\n"
+
+// removeTrailingCommas makes the JSONC of bun.lock valid JSON, by removing the commas before closing brackets
+func removeTrailingCommas(data []byte) []byte {
+	out := make([]byte, 0, len(data))
+	inString := false
+	escaped := false
+
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			out = append(out, c)
+
+			continue
+		}
+
+		if c == '"' {
+			inString = true
+		}
+
+		if c == ',' {
+			rest := bytes.TrimLeft(data[i+1:], " \t\r\n")
+
+			if len(rest) > 0 && (rest[0] == '}' || rest[0] == ']') {
+				continue
+			}
+		}
+
+		out = append(out, c)
+	}
+
+	return out
+}
+
+// splitBunResolution splits a resolution like "@scope/name@1.2.3" into the name and the version or source
+func splitBunResolution(resolution string) (string, string) {
+	idx := strings.Index(resolution[min(1, len(resolution)):], "@")
+
+	if idx < 0 {
+		return resolution, ""
+	}
+
+	idx++
+
+	return resolution[:idx], resolution[idx+1:]
+}
+
+type bunLockPackage struct {
+	name    string
+	version string
+	commit  string
+	info    BunLockPackageInfo
+}
+
+func parseBunLockPackage(entry []json.RawMessage) (bunLockPackage, bool) {
+	if len(entry) == 0 {
+		return bunLockPackage{}, false
+	}
+
+	var resolution string
+
+	if err := json.Unmarshal(entry[0], &resolution); err != nil {
+		return bunLockPackage{}, false
+	}
+
+	var pkg bunLockPackage
+	var source string
+
+	pkg.name, source = splitBunResolution(resolution)
+
+	for _, e := range entry[1:] {
+		if bytes.HasPrefix(bytes.TrimSpace(e), []byte("{")) {
+			_ = json.Unmarshal(e, &pkg.info)
+
+			break
+		}
+	}
+
+	switch {
+	case strings.HasPrefix(source, "workspace:"),
+		strings.HasPrefix(source, "file:"),
+		strings.HasPrefix(source, "link:"),
+		strings.HasPrefix(source, "root:"):
+		// local packages are not published, so cannot have known vulnerabilities
+		return bunLockPackage{}, false
+	case strings.HasPrefix(source, "github:"),
+		strings.HasPrefix(source, "git+"),
+		strings.HasPrefix(source, "git@"):
+		_, pkg.commit, _ = strings.Cut(source, "#")
+	case strings.Contains(source, "://"):
+		// tarballs do not have a known version
+		return bunLockPackage{}, false
+	default:
+		pkg.version = strings.TrimPrefix(source, "npm:")
+	}
+
+	return pkg, pkg.version != "" || pkg.commit != ""
+}
+
+// bunLockKeyParts splits the key of a package into the names of the packages it is nested under,
+// e.g. "@scope/parent/dep" into ["@scope/parent", "dep"]
+func bunLockKeyParts(key string) []string {
+	if key == "" {
+		return nil
+	}
+
+	var parts []string
+
+	segments := strings.Split(key, "/")
+
+	for i := 0; i < len(segments); i++ {
+		if strings.HasPrefix(segments[i], "@") && i+1 < len(segments) {
+			parts = append(parts, segments[i]+"/"+segments[i+1])
+			i++
+
+			continue
+		}
+
+		parts = append(parts, segments[i])
+	}
+
+	return parts
+}
+
+// findBunLockDependency finds the key of the package a dependency of the package at key resolves to,
+// checking for a version of the dependency nested under the package (e.g. "parent/dep") before its parents
+func findBunLockDependency(packages map[string][]json.RawMessage, key string, dep string) (string, bool) {
+	parts := bunLockKeyParts(key)
+
+	for i := len(parts); i >= 0; i-- {
+		candidate := strings.Join(append(parts[:i:i], dep), "/")
+
+		if _, ok := packages[candidate]; ok {
+			return candidate, true
+		}
+	}
+
+	return "", false
+}
+
+// reachableBunLockPackages finds the keys of all the packages depended on (directly or transitively) by the roots
+func reachableBunLockPackages(packages map[string][]json.RawMessage, parsed map[string]bunLockPackage, roots map[string]string) map[string]struct{} {
+	reachable := make(map[string]struct{})
+	var todo []string
+
+	visit := func(key string, deps ...map[string]string) {
+		for _, ds := range deps {
+			for dep := range ds {
+				if depKey, ok := findBunLockDependency(packages, key, dep); ok {
+					if _, seen := reachable[depKey]; !seen {
+						reachable[depKey] = struct{}{}
+						todo = append(todo, depKey)
+					}
+				}
+			}
+		}
+	}
+
+	visit("", roots)
+
+	for len(todo) > 0 {
+		key := todo[0]
+		todo = todo[1:]
+		info := parsed[key].info
+		visit(key, info.Dependencies, info.OptionalDependencies, info.PeerDependencies)
+	}
+
+	return reachable
+}
+
+func parseBunLock(lockfile BunLockfile) []PackageDetails {
+	parsed := make(map[string]bunLockPackage, len(lockfile.Packages))
+
+	for key, entry := range lockfile.Packages {
+		if pkg, ok := parseBunLockPackage(entry); ok {
+			parsed[key] = pkg
+		}
+	}
+
+	// bun.lock does not record which packages are only needed for development,
+	// so find which packages are only reachable from the workspaces' devDependencies
+	prodRoots := make(map[string]string)
+	devRoots := make(map[string]string)
+
+	for _, ws := range lockfile.Workspaces {
+		for _, ds := range []map[string]string{ws.Dependencies, ws.OptionalDependencies, ws.PeerDependencies} {
+			for name, req := range ds {
+				prodRoots[name] = req
+			}
+		}
+
+		for name, req := range ws.DevDependencies {
+			devRoots[name] = req
+		}
+	}
+
+	prod := reachableBunLockPackages(lockfile.Packages, parsed, prodRoots)
+	dev := reachableBunLockPackages(lockfile.Packages, parsed, devRoots)
+
+	packages := make([]PackageDetails, 0, len(parsed))
+
+	for key, pkg := range parsed {
+		var depGroups []string
+
+		_, isProd := prod[key]
+		_, isDev := dev[key]
+
+		if isDev && !isProd {
+			depGroups = append(depGroups, "dev")
+		}
+
+		packages = append(packages, PackageDetails{
+			Name:      pkg.name,
+			Version:   pkg.version,
+			Commit:    pkg.commit,
+			Ecosystem: BunEcosystem,
+			CompareAs: BunEcosystem,
+			DepGroups: depGroups,
+		})
+	}
+
+	return packages
+}
+
+type BunLockExtractor struct{}
+
+func (e BunLockExtractor) ShouldExtract(path string) bool {
+	return filepath.Base(path) == "bun.lock"
+}
+
+func (e BunLockExtractor) Extract(f DepFile) ([]PackageDetails, error) {
+	data, err := io.ReadAll(f)
+
+	if err != nil {
+		return []PackageDetails{}, fmt.Errorf("could not extract from %s: %w", f.Path(), err)
+	}
+
+	var parsedLockfile *BunLockfile
+
+	err = json.Unmarshal(removeTrailingCommas(data), &parsedLockfile)
+
+	if err != nil {
+		return []PackageDetails{}, fmt.Errorf("could not extract from %s: %w", f.Path(), err)
+	}
+
+	// this will happen if the file is "null"
+	if parsedLockfile == nil {
+		parsedLockfile = &BunLockfile{}
+	}
+
+	return parseBunLock(*parsedLockfile), nil
+}
+
+var _ Extractor = BunLockExtractor{}
+
+var ErrBunBinaryLockfile = errors.New("the binary bun.lockb format is not supported, run \"bun install --save-text-lockfile\" to also create a bun.lock")
+
+// BunBinaryLockExtractor handles the binary bun.lockb lockfiles of Bun before v1.2.
+// Their layout is an internal detail of Bun that changes between versions,
+// so rather than silently skipping them, it reports how to create the equivalent text bun.lock.
+type BunBinaryLockExtractor struct{}
+
+func (e BunBinaryLockExtractor) ShouldExtract(path string) bool {
+	return filepath.Base(path) == "bun.lockb"
+}
+
+func (e BunBinaryLockExtractor) Extract(f DepFile) ([]PackageDetails, error) {
+	header := make([]byte, len(bunBinaryLockfileHeader))
+
+	if _, err := io.ReadFull(f, header); err != nil || string(header) != bunBinaryLockfileHeader {
+		return []PackageDetails{}, fmt.Errorf("could not extract from %s: not a bun.lockb file", f.Path())
+	}
+
+	return []PackageDetails{}, fmt.Errorf("could not extract from %s: %w", f.Path(), ErrBunBinaryLockfile)
+}
+
+var _ Extractor = BunBinaryLockExtractor{}
+
+//nolint:gochecknoinits
+func init() {
+	registerExtractor("bun.lock", BunLockExtractor{})
+	registerExtractor("bun.lockb", BunBinaryLockExtractor{})
+}
+
+func ParseBunLock(pathToLockfile string) ([]PackageDetails, error) {
+	return extractFromFile(pathToLockfile, BunLockExtractor{})
+}
+
+func ParseBunBinaryLock(pathToLockfile string) ([]PackageDetails, error) {
+	return extractFromFile(pathToLockfile, BunBinaryLockExtractor{})
+}
@@ -0,0 +1,337 @@
+package lockfile_test
+
+import (
+	"io/fs"
+	"testing"
+
+	"github.com/google/osv-scanner/pkg/lockfile"
+)
+
+func TestBunLockExtractor_ShouldExtract(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{
+			name: "",
+			path: "",
+			want: false,
+		},
+		{
+			name: "",
+			path: "bun.lock",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/my/bun.lock",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/my/bun.lock/file",
+			want: false,
+		},
+		{
+			name: "",
+			path: "path/to/my/bun.lock.file",
+			want: false,
+		},
+		{
+			name: "",
+			path: "path.to.my.bun.lock",
+			want: false,
+		},
+		{
+			name: "",
+			path: "path/to/my/bun.lockb",
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e := lockfile.BunLockExtractor{}
+			got := e.ShouldExtract(tt.path)
+			if got != tt.want {
+				t.Errorf("Extract() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseBunLock_FileDoesNotExist(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseBunLock("fixtures/bun/does-not-exist")
+
+	expectErrIs(t, err, fs.ErrNotExist)
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseBunLock_InvalidJson(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseBunLock("fixtures/bun/not-json.txt")
+
+	expectErrContaining(t, err, "could not extract from")
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseBunLock_NoPackages(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseBunLock("fixtures/bun/empty.lock")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseBunLock_OnePackage(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseBunLock("fixtures/bun/one-package.lock")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "wrappy",
+			Version:   "1.0.2",
+			Ecosystem: lockfile.BunEcosystem,
+			CompareAs: lockfile.BunEcosystem,
+		},
+	})
+}
+
+func TestParseBunLock_OnePackageDev(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseBunLock("fixtures/bun/one-package-dev.lock")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "wrappy",
+			Version:   "1.0.2",
+			Ecosystem: lockfile.BunEcosystem,
+			CompareAs: lockfile.BunEcosystem,
+			DepGroups: []string{"dev"},
+		},
+	})
+}
+
+func TestParseBunLock_ScopedPackages(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseBunLock("fixtures/bun/scoped-packages.lock")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "@babel/code-frame",
+			Version:   "7.24.7",
+			Ecosystem: lockfile.BunEcosystem,
+			CompareAs: lockfile.BunEcosystem,
+		},
+		{
+			Name:      "@babel/highlight",
+			Version:   "7.24.7",
+			Ecosystem: lockfile.BunEcosystem,
+			CompareAs: lockfile.BunEcosystem,
+		},
+		{
+			Name:      "picocolors",
+			Version:   "1.0.1",
+			Ecosystem: lockfile.BunEcosystem,
+			CompareAs: lockfile.BunEcosystem,
+		},
+	})
+}
+
+func TestParseBunLock_NestedDependencies(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseBunLock("fixtures/bun/nested-dependencies.lock")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "debug",
+			Version:   "4.3.4",
+			Ecosystem: lockfile.BunEcosystem,
+			CompareAs: lockfile.BunEcosystem,
+		},
+		{
+			Name:      "ms",
+			Version:   "2.1.2",
+			Ecosystem: lockfile.BunEcosystem,
+			CompareAs: lockfile.BunEcosystem,
+		},
+		{
+			Name:      "mocha",
+			Version:   "3.5.3",
+			Ecosystem: lockfile.BunEcosystem,
+			CompareAs: lockfile.BunEcosystem,
+			DepGroups: []string{"dev"},
+		},
+		{
+			Name:      "debug",
+			Version:   "2.6.8",
+			Ecosystem: lockfile.BunEcosystem,
+			CompareAs: lockfile.BunEcosystem,
+			DepGroups: []string{"dev"},
+		},
+		{
+			Name:      "ms",
+			Version:   "2.0.0",
+			Ecosystem: lockfile.BunEcosystem,
+			CompareAs: lockfile.BunEcosystem,
+			DepGroups: []string{"dev"},
+		},
+	})
+}
+
+func TestParseBunLock_GitDependencies(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseBunLock("fixtures/bun/git-dependencies.lock")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "is-number",
+			Version:   "",
+			Ecosystem: lockfile.BunEcosystem,
+			CompareAs: lockfile.BunEcosystem,
+			Commit:    "98e8ff1",
+		},
+		{
+			Name:      "is-odd",
+			Version:   "",
+			Ecosystem: lockfile.BunEcosystem,
+			CompareAs: lockfile.BunEcosystem,
+			Commit:    "f2e4e4b",
+		},
+		{
+			Name:      "is-number",
+			Version:   "3.0.0",
+			Ecosystem: lockfile.BunEcosystem,
+			CompareAs: lockfile.BunEcosystem,
+		},
+	})
+}
+
+func TestParseBunLock_LocalPackages(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseBunLock("fixtures/bun/local-packages.lock")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "wrappy",
+			Version:   "1.0.2",
+			Ecosystem: lockfile.BunEcosystem,
+			CompareAs: lockfile.BunEcosystem,
+		},
+	})
+}
+
+func TestBunBinaryLockExtractor_ShouldExtract(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{
+			name: "",
+			path: "",
+			want: false,
+		},
+		{
+			name: "",
+			path: "bun.lockb",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/my/bun.lockb",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/my/bun.lockb/file",
+			want: false,
+		},
+		{
+			name: "",
+			path: "path/to/my/bun.lock",
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e := lockfile.BunBinaryLockExtractor{}
+			got := e.ShouldExtract(tt.path)
+			if got != tt.want {
+				t.Errorf("Extract() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseBunBinaryLock_FileDoesNotExist(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseBunBinaryLock("fixtures/bun/does-not-exist")
+
+	expectErrIs(t, err, fs.ErrNotExist)
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseBunBinaryLock_NotBinaryLockfile(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseBunBinaryLock("fixtures/bun/not-json.txt")
+
+	expectErrContaining(t, err, "not a bun.lockb file")
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseBunBinaryLock_Unsupported(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseBunBinaryLock("fixtures/bun/binary.lockb")
+
+	expectErrIs(t, err, lockfile.ErrBunBinaryLockfile)
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
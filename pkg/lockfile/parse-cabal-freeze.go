@@ -0,0 +1,97 @@
+package lockfile
+
+import (
+	"bufio"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/osv-scanner/internal/cachedregexp"
+)
+
+const HackageEcosystem Ecosystem = "Hackage"
+
+// cabalFreezeConstraints returns the value of the "constraints" field of a cabal.project.freeze,
+// which can be continued over multiple lines by indenting them
+func cabalFreezeConstraints(f DepFile) (string, error) {
+	scanner := bufio.NewScanner(f)
+
+	var constraints strings.Builder
+	inConstraints := false
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if strings.HasPrefix(strings.TrimSpace(line), "--") {
+			continue
+		}
+
+		if !strings.HasPrefix(line, " ") && !strings.HasPrefix(line, "\t") {
+			field, value, ok := strings.Cut(line, ":")
+			inConstraints = ok && strings.TrimSpace(field) == "constraints"
+
+			if inConstraints {
+				line = value
+			}
+		}
+
+		if inConstraints {
+			constraints.WriteString(line)
+			constraints.WriteString("\n")
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("error while scanning %s: %w", f.Path(), err)
+	}
+
+	return constraints.String(), nil
+}
+
+type CabalFreezeExtractor struct{}
+
+func (e CabalFreezeExtractor) ShouldExtract(path string) bool {
+	return filepath.Base(path) == "cabal.project.freeze"
+}
+
+func (e CabalFreezeExtractor) Extract(f DepFile) ([]PackageDetails, error) {
+	// constraints are either on the version, or on the flags of the package, which are skipped;
+	// packages can be qualified with "any." or by what they are used to set up, e.g. "setup."
+	re := cachedregexp.MustCompile(`^(?:[\w-]+(?::setup)?\.)?([\w-]+)\s*==\s*(\S+)$`)
+
+	constraints, err := cabalFreezeConstraints(f)
+
+	if err != nil {
+		return []PackageDetails{}, err
+	}
+
+	details := map[string]PackageDetails{}
+
+	for _, constraint := range strings.Split(constraints, ",") {
+		match := re.FindStringSubmatch(strings.TrimSpace(constraint))
+
+		if match == nil {
+			continue
+		}
+
+		details[match[1]+"@"+match[2]] = PackageDetails{
+			Name:      match[1],
+			Version:   match[2],
+			Ecosystem: HackageEcosystem,
+			CompareAs: HackageEcosystem,
+		}
+	}
+
+	return pkgDetailsMapToSlice(details), nil
+}
+
+var _ Extractor = CabalFreezeExtractor{}
+
+//nolint:gochecknoinits
+func init() {
+	registerExtractor("cabal.project.freeze", CabalFreezeExtractor{})
+}
+
+func ParseCabalFreeze(pathToLockfile string) ([]PackageDetails, error) {
+	return extractFromFile(pathToLockfile, CabalFreezeExtractor{})
+}
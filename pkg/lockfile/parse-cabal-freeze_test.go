@@ -0,0 +1,168 @@
+package lockfile_test
+
+import (
+	"io/fs"
+	"testing"
+
+	"github.com/google/osv-scanner/pkg/lockfile"
+)
+
+func TestCabalFreezeExtractor_ShouldExtract(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{
+			name: "",
+			path: "",
+			want: false,
+		},
+		{
+			name: "",
+			path: "cabal.project.freeze",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/my/cabal.project.freeze",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/my/cabal.project.freeze/file",
+			want: false,
+		},
+		{
+			name: "",
+			path: "path/to/my/cabal.project.freeze.file",
+			want: false,
+		},
+		{
+			name: "",
+			path: "path.to.my.cabal.project.freeze",
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e := lockfile.CabalFreezeExtractor{}
+			got := e.ShouldExtract(tt.path)
+			if got != tt.want {
+				t.Errorf("Extract() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCabalFreeze_FileDoesNotExist(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseCabalFreeze("fixtures/cabal/does-not-exist")
+
+	expectErrIs(t, err, fs.ErrNotExist)
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseCabalFreeze_NotAFreezeFile(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseCabalFreeze("fixtures/cabal/not-freeze.txt")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseCabalFreeze_NoPackages(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseCabalFreeze("fixtures/cabal/empty.freeze")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseCabalFreeze_OnePackage(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseCabalFreeze("fixtures/cabal/one-package.freeze")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "aeson",
+			Version:   "2.1.2.1",
+			Ecosystem: lockfile.HackageEcosystem,
+			CompareAs: lockfile.HackageEcosystem,
+		},
+	})
+}
+
+func TestParseCabalFreeze_MultiplePackages(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseCabalFreeze("fixtures/cabal/multiple-packages.freeze")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "Cabal",
+			Version:   "3.10.1.0",
+			Ecosystem: lockfile.HackageEcosystem,
+			CompareAs: lockfile.HackageEcosystem,
+		},
+		{
+			Name:      "OneTuple",
+			Version:   "0.4.1.1",
+			Ecosystem: lockfile.HackageEcosystem,
+			CompareAs: lockfile.HackageEcosystem,
+		},
+		{
+			Name:      "aeson",
+			Version:   "2.1.2.1",
+			Ecosystem: lockfile.HackageEcosystem,
+			CompareAs: lockfile.HackageEcosystem,
+		},
+		{
+			Name:      "base",
+			Version:   "4.17.2.0",
+			Ecosystem: lockfile.HackageEcosystem,
+			CompareAs: lockfile.HackageEcosystem,
+		},
+		{
+			Name:      "text",
+			Version:   "2.0.2",
+			Ecosystem: lockfile.HackageEcosystem,
+			CompareAs: lockfile.HackageEcosystem,
+		},
+		{
+			Name:      "directory",
+			Version:   "1.3.7.1",
+			Ecosystem: lockfile.HackageEcosystem,
+			CompareAs: lockfile.HackageEcosystem,
+		},
+		{
+			Name:      "unordered-containers",
+			Version:   "0.2.19.1",
+			Ecosystem: lockfile.HackageEcosystem,
+			CompareAs: lockfile.HackageEcosystem,
+		},
+	})
+}
@@ -171,3 +171,44 @@ func TestParseConanLock_v2_OnePackageDev(t *testing.T) {
 		},
 	})
 }
+
+func TestParseConanLock_v2_AllRequires(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseConanLock("fixtures/conan/all-requires.v2.json")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "zlib",
+			Version:   "1.2.13",
+			Ecosystem: lockfile.ConanEcosystem,
+			CompareAs: lockfile.ConanEcosystem,
+			DepGroups: []string{"requires"},
+		},
+		{
+			Name:      "cmake",
+			Version:   "3.27.7",
+			Ecosystem: lockfile.ConanEcosystem,
+			CompareAs: lockfile.ConanEcosystem,
+			DepGroups: []string{"build-requires"},
+		},
+		{
+			Name:      "pyreq",
+			Version:   "1.0.0",
+			Ecosystem: lockfile.ConanEcosystem,
+			CompareAs: lockfile.ConanEcosystem,
+			DepGroups: []string{"python-requires"},
+		},
+		{
+			Name:      "myconf",
+			Version:   "0.2.0",
+			Ecosystem: lockfile.ConanEcosystem,
+			CompareAs: lockfile.ConanEcosystem,
+			DepGroups: []string{"config-requires"},
+		},
+	})
+}
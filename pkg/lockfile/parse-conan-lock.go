@@ -42,6 +42,8 @@ type ConanLockFile struct {
 	Requires       []string `json:"requires,omitempty"`
 	BuildRequires  []string `json:"build_requires,omitempty"`
 	PythonRequires []string `json:"python_requires,omitempty"`
+	// newer conan v2 lockfiles also have "config_requires"
+	ConfigRequires []string `json:"config_requires,omitempty"`
 }
 
 // TODO this is tentative and subject to change depending on the OSV schema
@@ -155,12 +157,13 @@ func parseConanV2Lock(lockfile ConanLockFile) []PackageDetails {
 	packages := make(
 		[]PackageDetails,
 		0,
-		uint64(len(lockfile.Requires))+uint64(len(lockfile.BuildRequires))+uint64(len(lockfile.PythonRequires)),
+		uint64(len(lockfile.Requires))+uint64(len(lockfile.BuildRequires))+uint64(len(lockfile.PythonRequires))+uint64(len(lockfile.ConfigRequires)),
 	)
 
 	parseConanRequires(&packages, lockfile.Requires, "requires")
 	parseConanRequires(&packages, lockfile.BuildRequires, "build-requires")
 	parseConanRequires(&packages, lockfile.PythonRequires, "python-requires")
+	parseConanRequires(&packages, lockfile.ConfigRequires, "config-requires")
 
 	return packages
 }
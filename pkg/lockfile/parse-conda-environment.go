@@ -0,0 +1,100 @@
+package lockfile
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// CondaEnvironmentDependency is an entry in the "dependencies" of a conda
+// environment, which is either a conda match spec or a list of pip requirements
+type CondaEnvironmentDependency struct {
+	Spec string
+	Pip  []string
+}
+
+var _ yaml.Unmarshaler = &CondaEnvironmentDependency{}
+
+func (ced *CondaEnvironmentDependency) UnmarshalYAML(value *yaml.Node) error {
+	var m struct {
+		Pip []string `yaml:"pip"`
+	}
+
+	if value.Kind == yaml.MappingNode {
+		if err := value.Decode(&m); err != nil {
+			return err
+		}
+
+		ced.Pip = m.Pip
+
+		return nil
+	}
+
+	return value.Decode(&ced.Spec)
+}
+
+type CondaEnvironmentFile struct {
+	Dependencies []CondaEnvironmentDependency `yaml:"dependencies"`
+}
+
+const CondaEnvironmentEcosystem = PipEcosystem
+
+type CondaEnvironmentExtractor struct{}
+
+func (e CondaEnvironmentExtractor) ShouldExtract(path string) bool {
+	base := filepath.Base(path)
+
+	return base == "environment.yml" || base == "environment.yaml"
+}
+
+// Extract extracts the pip requirements of a conda environment; OSV does not
+// have any advisories for packages from conda channels, so the conda
+// dependencies themselves are not reported
+func (e CondaEnvironmentExtractor) Extract(f DepFile) ([]PackageDetails, error) {
+	var parsedEnvironment *CondaEnvironmentFile
+
+	err := yaml.NewDecoder(f).Decode(&parsedEnvironment)
+
+	if err != nil && !errors.Is(err, io.EOF) {
+		return []PackageDetails{}, fmt.Errorf("could not extract from %s: %w", f.Path(), err)
+	}
+
+	// this will happen if the file is empty
+	if parsedEnvironment == nil {
+		return []PackageDetails{}, nil
+	}
+
+	details := map[string]PackageDetails{}
+
+	for _, dependency := range parsedEnvironment.Dependencies {
+		for _, line := range dependency.Pip {
+			line = removeComments(line)
+
+			if isNotRequirementLine(line) {
+				continue
+			}
+
+			detail := parseLine(line)
+			detail.Ecosystem = CondaEnvironmentEcosystem
+			detail.CompareAs = CondaEnvironmentEcosystem
+
+			details[detail.Name+"@"+detail.Version] = detail
+		}
+	}
+
+	return pkgDetailsMapToSlice(details), nil
+}
+
+var _ Extractor = CondaEnvironmentExtractor{}
+
+//nolint:gochecknoinits
+func init() {
+	registerExtractor("environment.yml", CondaEnvironmentExtractor{})
+}
+
+func ParseCondaEnvironment(pathToLockfile string) ([]PackageDetails, error) {
+	return extractFromFile(pathToLockfile, CondaEnvironmentExtractor{})
+}
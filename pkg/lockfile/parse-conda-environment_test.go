@@ -0,0 +1,159 @@
+package lockfile_test
+
+import (
+	"io/fs"
+	"testing"
+
+	"github.com/google/osv-scanner/pkg/lockfile"
+)
+
+func TestCondaEnvironmentExtractor_ShouldExtract(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{
+			name: "",
+			path: "",
+			want: false,
+		},
+		{
+			name: "",
+			path: "environment.yml",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/my/environment.yml",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/my/environment.yml/file",
+			want: false,
+		},
+		{
+			name: "",
+			path: "path/to/my/environment.yml.file",
+			want: false,
+		},
+		{
+			name: "",
+			path: "path.to.my.environment.yml",
+			want: false,
+		},
+		{
+			name: "",
+			path: "path/to/my/environment.yaml",
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e := lockfile.CondaEnvironmentExtractor{}
+			got := e.ShouldExtract(tt.path)
+			if got != tt.want {
+				t.Errorf("Extract() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCondaEnvironment_FileDoesNotExist(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseCondaEnvironment("fixtures/conda/does-not-exist")
+
+	expectErrIs(t, err, fs.ErrNotExist)
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseCondaEnvironment_InvalidYaml(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseCondaEnvironment("fixtures/conda/not-yaml.txt")
+
+	expectErrContaining(t, err, "could not extract from")
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseCondaEnvironment_EmptyFile(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseCondaEnvironment("fixtures/conda/empty.yml")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseCondaEnvironment_NoPipDependencies(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseCondaEnvironment("fixtures/conda/no-pip.yml")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseCondaEnvironment_OnePipPackage(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseCondaEnvironment("fixtures/conda/one-pip-package.yml")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "requests",
+			Version:   "2.31.0",
+			Ecosystem: lockfile.CondaEnvironmentEcosystem,
+			CompareAs: lockfile.CondaEnvironmentEcosystem,
+		},
+	})
+}
+
+func TestParseCondaEnvironment_MultiplePipPackages(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseCondaEnvironment("fixtures/conda/multiple-pip-packages.yml")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "requests",
+			Version:   "2.31.0",
+			Ecosystem: lockfile.CondaEnvironmentEcosystem,
+			CompareAs: lockfile.CondaEnvironmentEcosystem,
+		},
+		{
+			Name:      "flask-cors",
+			Version:   "4.0.0",
+			Ecosystem: lockfile.CondaEnvironmentEcosystem,
+			CompareAs: lockfile.CondaEnvironmentEcosystem,
+		},
+		{
+			Name:      "pyyaml",
+			Version:   "0.0.0",
+			Ecosystem: lockfile.CondaEnvironmentEcosystem,
+			CompareAs: lockfile.CondaEnvironmentEcosystem,
+		},
+	})
+}
@@ -0,0 +1,85 @@
+package lockfile
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+type CondaLockPackage struct {
+	Name     string `yaml:"name"`
+	Version  string `yaml:"version"`
+	Manager  string `yaml:"manager"`
+	Category string `yaml:"category"`
+}
+
+type CondaLockFile struct {
+	Version  int                `yaml:"version"`
+	Packages []CondaLockPackage `yaml:"package"`
+}
+
+const CondaLockEcosystem = PipEcosystem
+
+type CondaLockExtractor struct{}
+
+func (e CondaLockExtractor) ShouldExtract(path string) bool {
+	return filepath.Base(path) == "conda-lock.yml"
+}
+
+// Extract extracts the packages installed by pip from a conda-lock.yml; OSV does
+// not have any advisories for packages from conda channels, so those are skipped
+func (e CondaLockExtractor) Extract(f DepFile) ([]PackageDetails, error) {
+	var parsedLockfile *CondaLockFile
+
+	err := yaml.NewDecoder(f).Decode(&parsedLockfile)
+
+	if err != nil && !errors.Is(err, io.EOF) {
+		return []PackageDetails{}, fmt.Errorf("could not extract from %s: %w", f.Path(), err)
+	}
+
+	// this will happen if the file is empty
+	if parsedLockfile == nil {
+		return []PackageDetails{}, nil
+	}
+
+	details := map[string]PackageDetails{}
+
+	// packages are locked once per platform, so the same package
+	// can be listed a number of times
+	for _, pkg := range parsedLockfile.Packages {
+		if pkg.Manager != "pip" {
+			continue
+		}
+
+		detail := PackageDetails{
+			Name:      pkg.Name,
+			Version:   pkg.Version,
+			Ecosystem: CondaLockEcosystem,
+			CompareAs: CondaLockEcosystem,
+		}
+
+		// packages that are not in the "main" category are either
+		// development dependencies or from an optional extra
+		if pkg.Category != "" && pkg.Category != "main" {
+			detail.DepGroups = append(detail.DepGroups, pkg.Category)
+		}
+
+		details[detail.Name+"@"+detail.Version] = detail
+	}
+
+	return pkgDetailsMapToSlice(details), nil
+}
+
+var _ Extractor = CondaLockExtractor{}
+
+//nolint:gochecknoinits
+func init() {
+	registerExtractor("conda-lock.yml", CondaLockExtractor{})
+}
+
+func ParseCondaLock(pathToLockfile string) ([]PackageDetails, error) {
+	return extractFromFile(pathToLockfile, CondaLockExtractor{})
+}
@@ -0,0 +1,136 @@
+package lockfile_test
+
+import (
+	"io/fs"
+	"testing"
+
+	"github.com/google/osv-scanner/pkg/lockfile"
+)
+
+func TestCondaLockExtractor_ShouldExtract(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{
+			name: "",
+			path: "",
+			want: false,
+		},
+		{
+			name: "",
+			path: "conda-lock.yml",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/my/conda-lock.yml",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/my/conda-lock.yml/file",
+			want: false,
+		},
+		{
+			name: "",
+			path: "path/to/my/conda-lock.yml.file",
+			want: false,
+		},
+		{
+			name: "",
+			path: "path.to.my.conda-lock.yml",
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e := lockfile.CondaLockExtractor{}
+			got := e.ShouldExtract(tt.path)
+			if got != tt.want {
+				t.Errorf("Extract() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCondaLock_FileDoesNotExist(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseCondaLock("fixtures/conda/does-not-exist")
+
+	expectErrIs(t, err, fs.ErrNotExist)
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseCondaLock_InvalidYaml(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseCondaLock("fixtures/conda/not-yaml.txt")
+
+	expectErrContaining(t, err, "could not extract from")
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseCondaLock_EmptyFile(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseCondaLock("fixtures/conda/empty.conda-lock.yml")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseCondaLock_NoPipPackages(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseCondaLock("fixtures/conda/no-pip-packages.conda-lock.yml")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseCondaLock_MultiplePipPackages(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseCondaLock("fixtures/conda/multiple-pip-packages.conda-lock.yml")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "requests",
+			Version:   "2.31.0",
+			Ecosystem: lockfile.CondaLockEcosystem,
+			CompareAs: lockfile.CondaLockEcosystem,
+		},
+		{
+			Name:      "urllib3",
+			Version:   "2.0.7",
+			Ecosystem: lockfile.CondaLockEcosystem,
+			CompareAs: lockfile.CondaLockEcosystem,
+		},
+		{
+			Name:      "pytest",
+			Version:   "7.4.3",
+			Ecosystem: lockfile.CondaLockEcosystem,
+			CompareAs: lockfile.CondaLockEcosystem,
+			DepGroups: []string{"dev"},
+		},
+	})
+}
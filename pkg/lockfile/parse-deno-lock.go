@@ -0,0 +1,121 @@
+package lockfile
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/osv-scanner/internal/cachedregexp"
+)
+
+const JSREcosystem Ecosystem = "JSR"
+const DenoLandEcosystem Ecosystem = "deno.land"
+
+type DenoLockPackages struct {
+	JSR map[string]any `json:"jsr"`
+	NPM map[string]any `json:"npm"`
+}
+
+type DenoLockFile struct {
+	Version string `json:"version"`
+	// version 3 of the lockfile nests the packages under a "packages" field,
+	// whereas they are at the top-level from version 4
+	Packages *DenoLockPackages `json:"packages"`
+	DenoLockPackages
+	Remote map[string]string `json:"remote"`
+}
+
+// parseDenoLockPackage parses the name and version out of a key like
+// "@std/assert@1.0.6", with npm packages being suffixed by the versions
+// of their peer dependencies like "react-dom@18.2.0_react@18.2.0"
+func parseDenoLockPackage(key string) (string, string, bool) {
+	// skip over the "@" that scoped packages start with
+	i := strings.Index(key[min(1, len(key)):], "@") + 1
+	if i <= 0 {
+		return "", "", false
+	}
+
+	version, _, _ := strings.Cut(key[i+1:], "_")
+
+	return key[:i], version, true
+}
+
+// parseDenoLandModule parses the name and version out of the url of a module
+// from deno.land, such as "https://deno.land/x/oak@v12.6.1/mod.ts"
+func parseDenoLandModule(url string) (string, string, bool) {
+	re := cachedregexp.MustCompile(`^https://deno\.land/((?:x/)?[^/@]+)@([^/]+)/`)
+
+	match := re.FindStringSubmatch(url)
+
+	if match == nil {
+		return "", "", false
+	}
+
+	return "deno.land/" + match[1], strings.TrimPrefix(match[2], "v"), true
+}
+
+type DenoLockExtractor struct{}
+
+func (e DenoLockExtractor) ShouldExtract(path string) bool {
+	return filepath.Base(path) == "deno.lock"
+}
+
+func (e DenoLockExtractor) Extract(f DepFile) ([]PackageDetails, error) {
+	var parsedLockfile *DenoLockFile
+
+	err := json.NewDecoder(f).Decode(&parsedLockfile)
+
+	if err != nil {
+		return []PackageDetails{}, fmt.Errorf("could not extract from %s: %w", f.Path(), err)
+	}
+
+	packages := parsedLockfile.DenoLockPackages
+
+	if parsedLockfile.Packages != nil {
+		packages = *parsedLockfile.Packages
+	}
+
+	details := map[string]PackageDetails{}
+
+	// the same name could be used by packages from different ecosystems
+	addPackage := func(name, version string, ecosystem Ecosystem) {
+		details[string(ecosystem)+":"+name+"@"+version] = PackageDetails{
+			Name:      name,
+			Version:   version,
+			Ecosystem: ecosystem,
+			CompareAs: ecosystem,
+		}
+	}
+
+	for key := range packages.NPM {
+		if name, version, ok := parseDenoLockPackage(key); ok {
+			addPackage(name, version, NpmEcosystem)
+		}
+	}
+
+	for key := range packages.JSR {
+		if name, version, ok := parseDenoLockPackage(key); ok {
+			addPackage(name, version, JSREcosystem)
+		}
+	}
+
+	for url := range parsedLockfile.Remote {
+		if name, version, ok := parseDenoLandModule(url); ok {
+			addPackage(name, version, DenoLandEcosystem)
+		}
+	}
+
+	return pkgDetailsMapToSlice(details), nil
+}
+
+var _ Extractor = DenoLockExtractor{}
+
+//nolint:gochecknoinits
+func init() {
+	registerExtractor("deno.lock", DenoLockExtractor{})
+}
+
+func ParseDenoLock(pathToLockfile string) ([]PackageDetails, error) {
+	return extractFromFile(pathToLockfile, DenoLockExtractor{})
+}
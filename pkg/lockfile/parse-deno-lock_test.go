@@ -0,0 +1,214 @@
+package lockfile_test
+
+import (
+	"io/fs"
+	"testing"
+
+	"github.com/google/osv-scanner/pkg/lockfile"
+)
+
+func TestDenoLockExtractor_ShouldExtract(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{
+			name: "",
+			path: "",
+			want: false,
+		},
+		{
+			name: "",
+			path: "deno.lock",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/my/deno.lock",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/my/deno.lock/file",
+			want: false,
+		},
+		{
+			name: "",
+			path: "path/to/my/deno.lock.file",
+			want: false,
+		},
+		{
+			name: "",
+			path: "path.to.my.deno.lock",
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e := lockfile.DenoLockExtractor{}
+			got := e.ShouldExtract(tt.path)
+			if got != tt.want {
+				t.Errorf("Extract() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDenoLock_FileDoesNotExist(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseDenoLock("fixtures/deno/does-not-exist")
+
+	expectErrIs(t, err, fs.ErrNotExist)
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseDenoLock_InvalidJson(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseDenoLock("fixtures/deno/not-json.txt")
+
+	expectErrContaining(t, err, "could not extract from")
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseDenoLock_NoPackages(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseDenoLock("fixtures/deno/empty.v4.lock")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseDenoLock_V3(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseDenoLock("fixtures/deno/packages.v3.lock")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "chalk",
+			Version:   "5.3.0",
+			Ecosystem: lockfile.NpmEcosystem,
+			CompareAs: lockfile.NpmEcosystem,
+		},
+		{
+			Name:      "js-tokens",
+			Version:   "4.0.0",
+			Ecosystem: lockfile.NpmEcosystem,
+			CompareAs: lockfile.NpmEcosystem,
+		},
+		{
+			Name:      "loose-envify",
+			Version:   "1.4.0",
+			Ecosystem: lockfile.NpmEcosystem,
+			CompareAs: lockfile.NpmEcosystem,
+		},
+		{
+			Name:      "react-dom",
+			Version:   "18.2.0",
+			Ecosystem: lockfile.NpmEcosystem,
+			CompareAs: lockfile.NpmEcosystem,
+		},
+		{
+			Name:      "react",
+			Version:   "18.2.0",
+			Ecosystem: lockfile.NpmEcosystem,
+			CompareAs: lockfile.NpmEcosystem,
+		},
+		{
+			Name:      "scheduler",
+			Version:   "0.23.0",
+			Ecosystem: lockfile.NpmEcosystem,
+			CompareAs: lockfile.NpmEcosystem,
+		},
+		{
+			Name:      "@std/assert",
+			Version:   "0.218.2",
+			Ecosystem: lockfile.JSREcosystem,
+			CompareAs: lockfile.JSREcosystem,
+		},
+		{
+			Name:      "@std/fmt",
+			Version:   "0.218.2",
+			Ecosystem: lockfile.JSREcosystem,
+			CompareAs: lockfile.JSREcosystem,
+		},
+		{
+			Name:      "deno.land/std",
+			Version:   "0.200.0",
+			Ecosystem: lockfile.DenoLandEcosystem,
+			CompareAs: lockfile.DenoLandEcosystem,
+		},
+		{
+			Name:      "deno.land/x/oak",
+			Version:   "12.6.1",
+			Ecosystem: lockfile.DenoLandEcosystem,
+			CompareAs: lockfile.DenoLandEcosystem,
+		},
+	})
+}
+
+func TestParseDenoLock_V4(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseDenoLock("fixtures/deno/packages.v4.lock")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "@types/node",
+			Version:   "22.5.4",
+			Ecosystem: lockfile.NpmEcosystem,
+			CompareAs: lockfile.NpmEcosystem,
+		},
+		{
+			Name:      "string_decoder",
+			Version:   "1.3.0",
+			Ecosystem: lockfile.NpmEcosystem,
+			CompareAs: lockfile.NpmEcosystem,
+		},
+		{
+			Name:      "undici-types",
+			Version:   "6.19.8",
+			Ecosystem: lockfile.NpmEcosystem,
+			CompareAs: lockfile.NpmEcosystem,
+		},
+		{
+			Name:      "safe-buffer",
+			Version:   "5.2.1",
+			Ecosystem: lockfile.NpmEcosystem,
+			CompareAs: lockfile.NpmEcosystem,
+		},
+		{
+			Name:      "@std/assert",
+			Version:   "1.0.6",
+			Ecosystem: lockfile.JSREcosystem,
+			CompareAs: lockfile.JSREcosystem,
+		},
+		{
+			Name:      "@std/internal",
+			Version:   "1.0.4",
+			Ecosystem: lockfile.JSREcosystem,
+			CompareAs: lockfile.JSREcosystem,
+		},
+	})
+}
@@ -0,0 +1,413 @@
+package lockfile
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/osv-scanner/internal/cachedregexp"
+)
+
+// DockerfileStage is a stage of a Dockerfile, which starts from either an image or an earlier stage
+type DockerfileStage struct {
+	// Name is what later stages refer to the stage as, which is empty if it is not named
+	Name string
+	// From is the image that the stage is built from, or the name of the stage that it continues
+	From string
+	// IsStage is whether From is an earlier stage rather than an image
+	IsStage bool
+
+	ecosystem Ecosystem
+	// runs are the commands of the RUN instructions of the stage
+	runs []string
+}
+
+// debianCodenames are the releases of Debian that image tags commonly use the codename of
+var debianCodenames = map[string]string{
+	"stretch":  "9",
+	"buster":   "10",
+	"bullseye": "11",
+	"bookworm": "12",
+	"trixie":   "13",
+}
+
+// imageEcosystem returns the ecosystem of the operating system packages of an image, based on the
+// conventions that official images follow for their names and tags, like "debian:12-slim",
+// "python:3.12-bookworm", or "node:20-alpine3.19". The release is not included if the tag does not
+// say what it is, and an empty ecosystem is returned if the distribution cannot be guessed at all
+func imageEcosystem(ref string) Ecosystem {
+	// the digest does not say anything about what the image is
+	ref, _, _ = strings.Cut(ref, "@")
+
+	repository, tag := ref, ""
+	if i := strings.LastIndex(ref, ":"); i > strings.LastIndex(ref, "/") {
+		repository, tag = ref[:i], ref[i+1:]
+	}
+
+	name := repository[strings.LastIndex(repository, "/")+1:]
+
+	if match := cachedregexp.MustCompile(`alpine(\d+)\.(\d+)`).FindStringSubmatch(tag); match != nil {
+		return Ecosystem(fmt.Sprintf("%s:v%s.%s", AlpineEcosystem, match[1], match[2]))
+	}
+
+	for codename, release := range debianCodenames {
+		if strings.Contains(tag, codename) {
+			return Ecosystem(fmt.Sprintf("%s:%s", DebianEcosystem, release))
+		}
+	}
+
+	switch {
+	case name == "alpine":
+		if match := cachedregexp.MustCompile(`^(\d+)\.(\d+)`).FindStringSubmatch(tag); match != nil {
+			return Ecosystem(fmt.Sprintf("%s:v%s.%s", AlpineEcosystem, match[1], match[2]))
+		}
+
+		return AlpineEcosystem
+	case name == "debian":
+		if match := cachedregexp.MustCompile(`^(\d+)`).FindStringSubmatch(tag); match != nil {
+			return Ecosystem(fmt.Sprintf("%s:%s", DebianEcosystem, match[1]))
+		}
+
+		return DebianEcosystem
+	case strings.Contains(tag, "alpine"):
+		return AlpineEcosystem
+	}
+
+	return ""
+}
+
+// dockerfileInstructions splits a Dockerfile into its instructions, joining the lines that
+// are continued with the escape character, and dropping comments and empty lines
+func dockerfileInstructions(f DepFile) ([]string, error) {
+	escape := `\`
+	directives := true
+
+	var instructions []string
+	current := ""
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		// parser directives can only be at the very top of the file
+		if directives {
+			if match := cachedregexp.MustCompile(`^#\s*escape\s*=\s*(\S)$`).FindStringSubmatch(line); match != nil {
+				escape = match[1]
+
+				continue
+			}
+
+			directives = strings.HasPrefix(line, "#") && strings.Contains(line, "=")
+		}
+
+		// comments are removed even from the middle of an instruction that is continued over lines
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if strings.HasSuffix(line, escape) {
+			current += strings.TrimSuffix(line, escape) + " "
+
+			continue
+		}
+
+		instructions = append(instructions, current+line)
+		current = ""
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if current != "" {
+		instructions = append(instructions, strings.TrimSpace(current))
+	}
+
+	return instructions, nil
+}
+
+// expandDockerfileArgs replaces the references to the arguments in s with their value,
+// returning false if any of them do not have one, as it is only known when building
+func expandDockerfileArgs(s string, args map[string]string) (string, bool) {
+	known := true
+
+	expanded := cachedregexp.MustCompile(`\$\{(\w+)(?::-([^}]*))?\}|\$(\w+)`).ReplaceAllStringFunc(s, func(ref string) string {
+		match := cachedregexp.MustCompile(`^\$\{(\w+)(?::-([^}]*))?\}$|^\$(\w+)$`).FindStringSubmatch(ref)
+		name := match[1] + match[3]
+
+		if value, ok := args[name]; ok && value != "" {
+			return value
+		}
+
+		if match[2] != "" {
+			return match[2]
+		}
+
+		known = false
+
+		return ref
+	})
+
+	return expanded, known
+}
+
+// parseDockerfile returns the stages of the Dockerfile, along with the commands that each of them runs.
+// Stages that are built from an image that depends on an argument without a default are skipped
+func parseDockerfile(f DepFile) ([]DockerfileStage, error) {
+	instructions, err := dockerfileInstructions(f)
+	if err != nil {
+		return nil, err
+	}
+
+	// only the arguments that are declared before the first stage can be used in FROM
+	args := map[string]string{}
+	var stages []DockerfileStage
+	var stage *DockerfileStage
+
+	for _, instruction := range instructions {
+		keyword, rest, _ := strings.Cut(instruction, " ")
+		rest = strings.TrimSpace(rest)
+
+		switch strings.ToUpper(keyword) {
+		case "ARG":
+			if len(stages) > 0 {
+				continue
+			}
+
+			for _, arg := range strings.Fields(rest) {
+				name, value, _ := strings.Cut(arg, "=")
+				args[name] = strings.Trim(value, `"'`)
+			}
+		case "FROM":
+			from := DockerfileStage{}
+			fields := strings.Fields(rest)
+
+			// options of the instruction, like --platform=linux/amd64
+			for len(fields) > 0 && strings.HasPrefix(fields[0], "--") {
+				fields = fields[1:]
+			}
+
+			if len(fields) == 0 {
+				return nil, fmt.Errorf("FROM without an image: %s", instruction)
+			}
+
+			image, known := expandDockerfileArgs(fields[0], args)
+
+			if len(fields) >= 3 && strings.EqualFold(fields[1], "AS") {
+				from.Name = fields[2]
+			}
+
+			if !known {
+				stage = nil
+
+				continue
+			}
+
+			from.From = image
+
+			for _, s := range stages {
+				if s.Name != "" && strings.EqualFold(s.Name, image) {
+					from.IsStage = true
+					from.ecosystem = s.ecosystem
+				}
+			}
+
+			if !from.IsStage {
+				from.ecosystem = imageEcosystem(image)
+			}
+
+			stages = append(stages, from)
+			stage = &stages[len(stages)-1]
+		case "RUN":
+			if stage != nil {
+				stage.runs = append(stage.runs, rest)
+			}
+		}
+	}
+
+	return stages, nil
+}
+
+// shellCommands splits the command of a RUN instruction into the words of each of the
+// commands that it runs, which is only an approximation of how a shell would do it
+func shellCommands(run string) [][]string {
+	// options of the instruction itself, like --mount=type=cache,target=/root/.cache
+	for strings.HasPrefix(run, "--") {
+		_, run, _ = strings.Cut(run, " ")
+		run = strings.TrimSpace(run)
+	}
+
+	// the exec form is a single command that is not run by a shell
+	if strings.HasPrefix(run, "[") {
+		var words []string
+		if err := json.Unmarshal([]byte(run), &words); err == nil {
+			return [][]string{words}
+		}
+	}
+
+	var commands [][]string
+	for _, command := range cachedregexp.MustCompile(`&&|\|\||[;|&\n()]`).Split(run, -1) {
+		var words []string
+
+		for _, word := range strings.Fields(command) {
+			words = append(words, strings.Trim(word, `"'`))
+		}
+
+		// environment variables and sudo that the command is run with
+		for len(words) > 0 && (words[0] == "sudo" || cachedregexp.MustCompile(`^\w+=`).MatchString(words[0])) {
+			words = words[1:]
+		}
+
+		if len(words) > 0 {
+			commands = append(commands, words)
+		}
+	}
+
+	return commands
+}
+
+// installedPackages returns the packages that a command installs at an exact version, which are those
+// installed with apt, apk, or pip, using the ecosystem of the image that the command is run in if it is for
+// the same distribution as the package manager, as that is what says which release the package is from
+func installedPackages(words []string, ecosystem Ecosystem) []PackageDetails {
+	var separator, subcommand string
+	var compareAs Ecosystem
+
+	switch filepath.Base(words[0]) {
+	case "apt-get", "apt":
+		separator, subcommand, compareAs = "=", "install", DebianEcosystem
+	case "apk":
+		separator, subcommand, compareAs = "=", "add", AlpineEcosystem
+	case "pip", "pip3":
+		separator, subcommand, compareAs = "==", "install", PipEcosystem
+	case "python", "python3":
+		if len(words) > 2 && words[1] == "-m" && (words[2] == "pip" || words[2] == "pip3") {
+			return installedPackages(words[2:], ecosystem)
+		}
+
+		return nil
+	default:
+		return nil
+	}
+
+	pkgEcosystem := compareAs
+	if strings.HasPrefix(string(ecosystem), string(compareAs)+":") {
+		pkgEcosystem = ecosystem
+	}
+
+	var packages []PackageDetails
+	installing := false
+
+	for _, word := range words[1:] {
+		if !installing {
+			installing = word == subcommand
+
+			continue
+		}
+
+		if strings.HasPrefix(word, "-") {
+			continue
+		}
+
+		// versions can be wildcards, or come from variables that are only known when building
+		name, version, ok := strings.Cut(word, separator)
+		if !ok || version == "" || strings.ContainsAny(version, "*$") {
+			continue
+		}
+
+		switch compareAs {
+		case PipEcosystem:
+			name = normalizedRequirementName(name)
+		case DebianEcosystem:
+			// the architecture that the package is for, like "libc6:amd64"
+			name, _, _ = strings.Cut(name, ":")
+		}
+
+		packages = append(packages, PackageDetails{
+			Name:      name,
+			Version:   version,
+			Ecosystem: pkgEcosystem,
+			CompareAs: compareAs,
+		})
+	}
+
+	return packages
+}
+
+type DockerfileExtractor struct{}
+
+// ShouldExtract returns whether the file is a Dockerfile, which is named either "Dockerfile" or
+// "Containerfile", or has one of them as a prefix or suffix like "Dockerfile.dev" or "app.Dockerfile"
+func (e DockerfileExtractor) ShouldExtract(path string) bool {
+	base := strings.ToLower(filepath.Base(path))
+
+	for _, name := range []string{"dockerfile", "containerfile"} {
+		if base == name || strings.HasPrefix(base, name+".") || strings.HasSuffix(base, "."+name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Extract extracts the packages that the RUN instructions of a Dockerfile install at an exact version
+// with apt, apk, or pip, as they are what a build of the image will install, using the release of the
+// distribution that the image of their stage is for if the tag of the image says what it is
+func (e DockerfileExtractor) Extract(f DepFile) ([]PackageDetails, error) {
+	stages, err := parseDockerfile(f)
+	if err != nil {
+		return []PackageDetails{}, fmt.Errorf("could not extract from %s: %w", f.Path(), err)
+	}
+
+	packages := []PackageDetails{}
+	seen := map[string]bool{}
+
+	for _, stage := range stages {
+		for _, run := range stage.runs {
+			for _, words := range shellCommands(run) {
+				for _, pkg := range installedPackages(words, stage.ecosystem) {
+					// the same package can be installed by more than one stage
+					key := string(pkg.Ecosystem) + "/" + pkg.Name + "@" + pkg.Version
+					if seen[key] {
+						continue
+					}
+
+					seen[key] = true
+					packages = append(packages, pkg)
+				}
+			}
+		}
+	}
+
+	return packages, nil
+}
+
+// DockerfileStages returns the stages of the Dockerfile at the path, which are what
+// images a build of it pulls, skipping those that depend on an argument without a default
+func DockerfileStages(pathToDockerfile string) ([]DockerfileStage, error) {
+	f, err := OpenLocalDepFile(pathToDockerfile)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	stages, err := parseDockerfile(f)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse %s: %w", pathToDockerfile, err)
+	}
+
+	return stages, nil
+}
+
+var _ Extractor = DockerfileExtractor{}
+
+//nolint:gochecknoinits
+func init() {
+	registerExtractor("Dockerfile", DockerfileExtractor{})
+}
+
+func ParseDockerfile(pathToLockfile string) ([]PackageDetails, error) {
+	return extractFromFile(pathToLockfile, DockerfileExtractor{})
+}
@@ -0,0 +1,255 @@
+package lockfile_test
+
+import (
+	"io/fs"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/lockfile"
+)
+
+func TestDockerfileExtractor_ShouldExtract(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{
+			name: "",
+			path: "",
+			want: false,
+		},
+		{
+			name: "",
+			path: "Dockerfile",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/my/Dockerfile",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/my/Containerfile",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/my/Dockerfile.dev",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/my/app.dockerfile",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/my/Dockerfile/file",
+			want: false,
+		},
+		{
+			name: "",
+			path: "path/to/my/.dockerignore",
+			want: false,
+		},
+		{
+			name: "",
+			path: "path/to/my/Dockerfiles",
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e := lockfile.DockerfileExtractor{}
+			got := e.ShouldExtract(tt.path)
+			if got != tt.want {
+				t.Errorf("Extract() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDockerfile_FileDoesNotExist(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseDockerfile("fixtures/dockerfile/does-not-exist")
+
+	expectErrIs(t, err, fs.ErrNotExist)
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseDockerfile_Empty(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseDockerfile("fixtures/dockerfile/empty.Dockerfile")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseDockerfile_NoPinnedPackages(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseDockerfile("fixtures/dockerfile/no-pins.Dockerfile")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseDockerfile_AptPackages(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseDockerfile("fixtures/dockerfile/apt.Dockerfile")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "curl",
+			Version:   "7.88.1-10+deb12u5",
+			Ecosystem: "Debian:12",
+			CompareAs: lockfile.DebianEcosystem,
+		},
+		{
+			Name:      "libssl3",
+			Version:   "3.0.11-1~deb12u2",
+			Ecosystem: "Debian:12",
+			CompareAs: lockfile.DebianEcosystem,
+		},
+	})
+}
+
+func TestParseDockerfile_PipPackages(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseDockerfile("fixtures/dockerfile/pip.Dockerfile")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "flask",
+			Version:   "2.2.2",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+		},
+		{
+			Name:      "requests",
+			Version:   "2.31.0",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+		},
+		{
+			Name:      "pyyaml",
+			Version:   "5.4.1",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+		},
+		{
+			Name:      "jinja2",
+			Version:   "3.1.2",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+		},
+	})
+}
+
+func TestParseDockerfile_MultiStage(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseDockerfile("fixtures/dockerfile/multi-stage.Dockerfile")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "git",
+			Version:   "1:2.39.2-1.1",
+			Ecosystem: "Debian:12",
+			CompareAs: lockfile.DebianEcosystem,
+		},
+		{
+			Name:      "make",
+			Version:   "4.3-4.1",
+			Ecosystem: "Debian:12",
+			CompareAs: lockfile.DebianEcosystem,
+		},
+		{
+			Name:      "ca-certificates",
+			Version:   "20240226-r0",
+			Ecosystem: "Alpine:v3.19",
+			CompareAs: lockfile.AlpineEcosystem,
+		},
+	})
+}
+
+func TestParseDockerfile_EscapeDirective(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseDockerfile("fixtures/dockerfile/windows.Dockerfile")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "flask",
+			Version:   "2.2.2",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+		},
+	})
+}
+
+func TestDockerfileStages(t *testing.T) {
+	t.Parallel()
+
+	stages, err := lockfile.DockerfileStages("fixtures/dockerfile/multi-stage.Dockerfile")
+
+	if err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+
+	type stage struct {
+		Name    string
+		From    string
+		IsStage bool
+	}
+
+	got := make([]stage, 0, len(stages))
+	for _, s := range stages {
+		got = append(got, stage{Name: s.Name, From: s.From, IsStage: s.IsStage})
+	}
+
+	want := []stage{
+		{Name: "builder", From: "golang:1.22-bookworm"},
+		{Name: "tester", From: "builder", IsStage: true},
+		{Name: "assets", From: "node:20-alpine3.19"},
+		{From: "alpine:3.19"},
+		{From: "scratch"},
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("DockerfileStages() (-want +got):\n%s", diff)
+	}
+}
@@ -47,6 +47,14 @@ type gemfileLockfileParser struct {
 }
 
 func (parser *gemfileLockfileParser) addDependency(name string, version string) {
+	// gems with native extensions can be locked for each of the PLATFORMS
+	// of the lockfile, which all have the same version
+	for _, dep := range parser.dependencies {
+		if dep.Name == name && dep.Version == version && dep.Commit == parser.currentGemCommit {
+			return
+		}
+	}
+
 	parser.dependencies = append(parser.dependencies, PackageDetails{
 		Name:      name,
 		Version:   version,
@@ -72,6 +80,7 @@ func (parser *gemfileLockfileParser) parseSpec(line string) {
 		log.Fatal("Weird error when parsing spec in Gemfile.lock (unexpectedly had no spaces) - please report this")
 	}
 
+	// the platform of a gem, if any, is suffixed to its version like "1.15.4-x86_64-linux"
 	if len(spaces) == 4 {
 		parser.addDependency(results[2], results[3])
 	}
@@ -805,3 +805,40 @@ func TestParseGemfileLock_HasGitGem(t *testing.T) {
 		},
 	})
 }
+
+func TestParseGemfileLock_PlatformSpecificGems(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseGemfileLock("fixtures/bundler/platform-specific-gems.lock")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "mini_portile2",
+			Version:   "2.8.5",
+			Ecosystem: lockfile.BundlerEcosystem,
+			CompareAs: lockfile.BundlerEcosystem,
+		},
+		{
+			Name:      "nokogiri",
+			Version:   "1.15.4",
+			Ecosystem: lockfile.BundlerEcosystem,
+			CompareAs: lockfile.BundlerEcosystem,
+		},
+		{
+			Name:      "racc",
+			Version:   "1.7.3",
+			Ecosystem: lockfile.BundlerEcosystem,
+			CompareAs: lockfile.BundlerEcosystem,
+		},
+		{
+			Name:      "sqlite3",
+			Version:   "1.6.9",
+			Ecosystem: lockfile.BundlerEcosystem,
+			CompareAs: lockfile.BundlerEcosystem,
+		},
+	})
+}
@@ -0,0 +1,286 @@
+package lockfile
+
+import (
+	"bufio"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/google/osv-scanner/internal/cachedregexp"
+	"github.com/google/osv-scanner/internal/semantic"
+)
+
+// rubyConstraint is a single requirement of a gem, like "~> 1.2"
+type rubyConstraint struct {
+	op      string
+	version string
+}
+
+func (c rubyConstraint) satisfiedBy(version string) bool {
+	cmp := semantic.MustParse(version, "RubyGems").CompareStr(c.version)
+
+	switch c.op {
+	case "=":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case ">=":
+		return cmp >= 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case "<":
+		return cmp < 0
+	// the pessimistic operator, e.g. "~> 1.2.3" is ">= 1.2.3, < 1.3"
+	case "~>":
+		segments := strings.Split(c.version, ".")
+		upper := segments[:max(1, len(segments)-1)]
+
+		var n int
+		_, _ = fmt.Sscanf(upper[len(upper)-1], "%d", &n)
+		upper[len(upper)-1] = fmt.Sprint(n + 1)
+
+		return cmp >= 0 && semantic.MustParse(version, "RubyGems").CompareStr(strings.Join(upper, ".")) < 0
+	}
+
+	return false
+}
+
+// isRubyPreRelease reports if the version is a pre-release, which RubyGems
+// considers to be any version that contains a letter
+func isRubyPreRelease(version string) bool {
+	return cachedregexp.MustCompile(`[a-zA-Z]`).MatchString(version)
+}
+
+// parseRubyRequirement parses a requirement of a gem like ">= 1.2.3", which has
+// the "=" operator if it only has a version
+func parseRubyRequirement(requirement string) (rubyConstraint, bool) {
+	re := cachedregexp.MustCompile(`^(=|!=|>=|<=|>|<|~>)?\s*([0-9][0-9a-zA-Z.]*)$`)
+
+	match := re.FindStringSubmatch(strings.TrimSpace(requirement))
+
+	if match == nil {
+		return rubyConstraint{}, false
+	}
+
+	if match[1] == "" {
+		match[1] = "="
+	}
+
+	return rubyConstraint{op: match[1], version: match[2]}, true
+}
+
+// RubyGemsVersionsFetcher fetches the versions of a gem that have been published to
+// rubygems.org, returning no versions if the gem does not exist
+type RubyGemsVersionsFetcher func(name string) ([]string, error)
+
+// gemfileDevGroups are the groups of a Gemfile that are only needed for development
+var gemfileDevGroups = map[string]bool{"development": true, "test": true}
+
+// gemfileBlock is a block of a Gemfile, which gems that are declared within are part of
+type gemfileBlock struct {
+	groups []string
+	// gems that are declared within a git or path block are not published to rubygems.org
+	unpublished bool
+}
+
+// GemfileExtractor extracts the gems that are declared in a Gemfile, as a fallback for
+// when the Gemfile does not have a Gemfile.lock.
+//
+// Only the versions of gems that are pinned to an exact version are known, unless
+// FetchVersions is set to resolve their requirements to the best version on rubygems.org.
+type GemfileExtractor struct {
+	FetchVersions RubyGemsVersionsFetcher
+}
+
+func (e GemfileExtractor) ShouldExtract(path string) bool {
+	return filepath.Base(path) == "Gemfile"
+}
+
+// resolve returns the version that the requirements of the gem resolve to,
+// or an empty string if it can't be resolved
+func (e GemfileExtractor) resolve(name string, requirements []rubyConstraint) (string, error) {
+	if len(requirements) == 1 && requirements[0].op == "=" {
+		return requirements[0].version, nil
+	}
+
+	if e.FetchVersions == nil {
+		return "", nil
+	}
+
+	versions, err := e.FetchVersions(name)
+
+	if err != nil {
+		return "", fmt.Errorf("could not fetch the versions of %s: %w", name, err)
+	}
+
+	satisfies := func(version string) bool {
+		for _, c := range requirements {
+			if !c.satisfiedBy(version) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	return highestVersion("RubyGems", versions, satisfies, isRubyPreRelease), nil
+}
+
+// parseGemfileSymbols parses the names of the symbols or strings in the value
+// of an option of a gem, like ":test" or "[:development, :test]"
+func parseGemfileSymbols(value string) []string {
+	re := cachedregexp.MustCompile(`:(\w+)|["']([^"']+)["']`)
+
+	var names []string
+
+	for _, match := range re.FindAllStringSubmatch(value, -1) {
+		names = append(names, match[1]+match[2])
+	}
+
+	return names
+}
+
+// gemfileGroups returns the groups that a gem belongs to, which is "dev" if all of
+// them are only needed for development and none otherwise
+func gemfileGroups(groups []string) []string {
+	if len(groups) == 0 {
+		return nil
+	}
+
+	for _, group := range groups {
+		if !gemfileDevGroups[group] {
+			return nil
+		}
+	}
+
+	return []string{"dev"}
+}
+
+// Extract extracts the gems declared in a Gemfile, which is Ruby code that is parsed
+// a line at a time for the common ways of declaring gems, such as in group blocks
+func (e GemfileExtractor) Extract(f DepFile) ([]PackageDetails, error) {
+	if lock, err := f.Open("Gemfile.lock"); err == nil {
+		_ = lock.Close()
+
+		return []PackageDetails{}, nil
+	}
+
+	gemRe := cachedregexp.MustCompile(`^gem\s*\(?\s*["']([^"']+)["']\s*(.*?)\)?$`)
+	requirementRe := cachedregexp.MustCompile(`^,\s*["']([^"']*)["']\s*`)
+	groupRe := cachedregexp.MustCompile(`^group\s*\(?(.*?)\)?\s+do(\s*\|.*\|)?$`)
+	groupOptionRe := cachedregexp.MustCompile(`\b(?:group|groups)\s*(?::|=>)\s*(\[[^\]]*\]|:\w+|["'][^"']*["'])`)
+	unpublishedOptionRe := cachedregexp.MustCompile(`\b(?:git|github|gitlab|bitbucket|path)\s*(?::|=>)`)
+	unpublishedBlockRe := cachedregexp.MustCompile(`^(?:git|github|path)\b.*\bdo(\s*\|.*\|)?$`)
+	blockRe := cachedregexp.MustCompile(`(^(?:if|unless|case|begin|while|until)\b)|\bdo(\s*\|.*\|)?$`)
+
+	scanner := bufio.NewScanner(f)
+
+	blocks := []gemfileBlock{{}}
+	details := map[string]PackageDetails{}
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(removeComments(scanner.Text()))
+		current := blocks[len(blocks)-1]
+
+		if line == "end" {
+			if len(blocks) > 1 {
+				blocks = blocks[:len(blocks)-1]
+			}
+
+			continue
+		}
+
+		if match := groupRe.FindStringSubmatch(line); match != nil {
+			blocks = append(blocks, gemfileBlock{
+				groups:      append(slices.Clone(current.groups), parseGemfileSymbols(match[1])...),
+				unpublished: current.unpublished,
+			})
+
+			continue
+		}
+
+		if unpublishedBlockRe.MatchString(line) {
+			blocks = append(blocks, gemfileBlock{groups: current.groups, unpublished: true})
+
+			continue
+		}
+
+		match := gemRe.FindStringSubmatch(line)
+
+		if match == nil {
+			// blocks such as "platforms" or conditionals don't change the gems within them
+			if blockRe.MatchString(line) {
+				blocks = append(blocks, current)
+			}
+
+			continue
+		}
+
+		name, rest := match[1], match[2]
+
+		if current.unpublished || unpublishedOptionRe.MatchString(rest) {
+			continue
+		}
+
+		var requirements []rubyConstraint
+
+		for {
+			req := requirementRe.FindStringSubmatch(rest)
+
+			if req == nil {
+				break
+			}
+
+			rest = rest[len(req[0]):]
+
+			if c, ok := parseRubyRequirement(req[1]); ok {
+				requirements = append(requirements, c)
+			}
+		}
+
+		groups := slices.Clone(current.groups)
+
+		if option := groupOptionRe.FindStringSubmatch(rest); option != nil {
+			groups = append(groups, parseGemfileSymbols(option[1])...)
+		}
+
+		version, err := e.resolve(name, requirements)
+
+		if err != nil {
+			return []PackageDetails{}, fmt.Errorf("could not extract from %s: %w", f.Path(), err)
+		}
+
+		// gems that are always required take precedence over those that are only in a group
+		if existing, ok := details[name+"@"+version]; ok && len(existing.DepGroups) == 0 {
+			continue
+		}
+
+		details[name+"@"+version] = PackageDetails{
+			Name:      name,
+			Version:   version,
+			Ecosystem: BundlerEcosystem,
+			CompareAs: BundlerEcosystem,
+			DepGroups: gemfileGroups(groups),
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return []PackageDetails{}, fmt.Errorf("error while scanning %s: %w", f.Path(), err)
+	}
+
+	return pkgDetailsMapToSlice(details), nil
+}
+
+var _ Extractor = GemfileExtractor{}
+
+//nolint:gochecknoinits
+func init() {
+	registerExtractor("Gemfile", GemfileExtractor{})
+}
+
+func ParseGemfile(pathToLockfile string) ([]PackageDetails, error) {
+	return extractFromFile(pathToLockfile, GemfileExtractor{})
+}
@@ -0,0 +1,365 @@
+package lockfile_test
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+
+	"github.com/google/osv-scanner/pkg/lockfile"
+)
+
+func TestGemfileExtractor_ShouldExtract(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{
+			name: "",
+			path: "",
+			want: false,
+		},
+		{
+			name: "",
+			path: "Gemfile",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/my/Gemfile",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/my/Gemfile/file",
+			want: false,
+		},
+		{
+			name: "",
+			path: "path/to/my/Gemfile.file",
+			want: false,
+		},
+		{
+			name: "",
+			path: "path.to.my.Gemfile",
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e := lockfile.GemfileExtractor{}
+			got := e.ShouldExtract(tt.path)
+			if got != tt.want {
+				t.Errorf("Extract() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// extractGemfileWithVersions extracts the Gemfile at path, using
+// versions as the versions that have been published to rubygems.org
+func extractGemfileWithVersions(t *testing.T, path string, versions map[string][]string) ([]lockfile.PackageDetails, error) {
+	t.Helper()
+
+	f, err := lockfile.OpenLocalDepFile(path)
+	if err != nil {
+		t.Fatalf("could not open %s: %v", path, err)
+	}
+	defer f.Close()
+
+	return lockfile.GemfileExtractor{
+		FetchVersions: func(name string) ([]string, error) {
+			return versions[name], nil
+		},
+	}.Extract(f)
+}
+
+func TestParseGemfile_FileDoesNotExist(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseGemfile("fixtures/gemfile/does-not-exist")
+
+	expectErrIs(t, err, fs.ErrNotExist)
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseGemfile_NoGems(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseGemfile("fixtures/gemfile/empty.Gemfile")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseGemfile_OneGem(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseGemfile("fixtures/gemfile/one-gem.Gemfile")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "rack",
+			Version:   "2.2.8",
+			Ecosystem: lockfile.BundlerEcosystem,
+			CompareAs: lockfile.BundlerEcosystem,
+		},
+	})
+}
+
+func TestParseGemfile_MultipleGems(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseGemfile("fixtures/gemfile/multiple-gems.Gemfile")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "rails",
+			Version:   "",
+			Ecosystem: lockfile.BundlerEcosystem,
+			CompareAs: lockfile.BundlerEcosystem,
+		},
+		{
+			Name:      "pg",
+			Version:   "",
+			Ecosystem: lockfile.BundlerEcosystem,
+			CompareAs: lockfile.BundlerEcosystem,
+		},
+		{
+			Name:      "puma",
+			Version:   "6.4.0",
+			Ecosystem: lockfile.BundlerEcosystem,
+			CompareAs: lockfile.BundlerEcosystem,
+		},
+		{
+			Name:      "bootsnap",
+			Version:   "",
+			Ecosystem: lockfile.BundlerEcosystem,
+			CompareAs: lockfile.BundlerEcosystem,
+		},
+		{
+			Name:      "tzinfo-data",
+			Version:   "",
+			Ecosystem: lockfile.BundlerEcosystem,
+			CompareAs: lockfile.BundlerEcosystem,
+		},
+		{
+			Name:      "nokogiri",
+			Version:   "1.15.4",
+			Ecosystem: lockfile.BundlerEcosystem,
+			CompareAs: lockfile.BundlerEcosystem,
+		},
+		{
+			Name:      "activerecord-jdbcpostgresql-adapter",
+			Version:   "70.1",
+			Ecosystem: lockfile.BundlerEcosystem,
+			CompareAs: lockfile.BundlerEcosystem,
+		},
+		{
+			Name:      "debug",
+			Version:   "1.9.0",
+			Ecosystem: lockfile.BundlerEcosystem,
+			CompareAs: lockfile.BundlerEcosystem,
+			DepGroups: []string{"dev"},
+		},
+		{
+			Name:      "rspec-rails",
+			Version:   "",
+			Ecosystem: lockfile.BundlerEcosystem,
+			CompareAs: lockfile.BundlerEcosystem,
+			DepGroups: []string{"dev"},
+		},
+		{
+			Name:      "web-console",
+			Version:   "4.2.1",
+			Ecosystem: lockfile.BundlerEcosystem,
+			CompareAs: lockfile.BundlerEcosystem,
+			DepGroups: []string{"dev"},
+		},
+		{
+			Name:      "rack-mini-profiler",
+			Version:   "3.1.1",
+			Ecosystem: lockfile.BundlerEcosystem,
+			CompareAs: lockfile.BundlerEcosystem,
+			DepGroups: []string{"dev"},
+		},
+		{
+			Name:      "lograge",
+			Version:   "0.14.0",
+			Ecosystem: lockfile.BundlerEcosystem,
+			CompareAs: lockfile.BundlerEcosystem,
+		},
+		{
+			Name:      "capybara",
+			Version:   "3.39.2",
+			Ecosystem: lockfile.BundlerEcosystem,
+			CompareAs: lockfile.BundlerEcosystem,
+			DepGroups: []string{"dev"},
+		},
+		{
+			Name:      "rubocop",
+			Version:   "1.57.2",
+			Ecosystem: lockfile.BundlerEcosystem,
+			CompareAs: lockfile.BundlerEcosystem,
+		},
+	})
+}
+
+func TestParseGemfile_WithLockfile(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseGemfile("fixtures/gemfile/with-lock/Gemfile")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestGemfileExtractor_Extract_ResolvesVersions(t *testing.T) {
+	t.Parallel()
+
+	packages, err := extractGemfileWithVersions(
+		t,
+		"fixtures/gemfile/multiple-gems.Gemfile",
+		map[string][]string{
+			"rails":       {"6.1.7", "7.0.8", "7.0.8.1", "7.1.0", "7.1.0.beta1"},
+			"pg":          {"0.17.0", "1.5.4", "2.0.0"},
+			"bootsnap":    {"1.17.0", "1.18.0.rc1"},
+			"rspec-rails": {"6.0.0", "6.1.0", "7.0.0"},
+		},
+	)
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "rails",
+			Version:   "7.0.8.1",
+			Ecosystem: lockfile.BundlerEcosystem,
+			CompareAs: lockfile.BundlerEcosystem,
+		},
+		{
+			Name:      "pg",
+			Version:   "1.5.4",
+			Ecosystem: lockfile.BundlerEcosystem,
+			CompareAs: lockfile.BundlerEcosystem,
+		},
+		{
+			Name:      "puma",
+			Version:   "6.4.0",
+			Ecosystem: lockfile.BundlerEcosystem,
+			CompareAs: lockfile.BundlerEcosystem,
+		},
+		{
+			Name:      "bootsnap",
+			Version:   "1.17.0",
+			Ecosystem: lockfile.BundlerEcosystem,
+			CompareAs: lockfile.BundlerEcosystem,
+		},
+		{
+			Name:      "tzinfo-data",
+			Version:   "",
+			Ecosystem: lockfile.BundlerEcosystem,
+			CompareAs: lockfile.BundlerEcosystem,
+		},
+		{
+			Name:      "nokogiri",
+			Version:   "1.15.4",
+			Ecosystem: lockfile.BundlerEcosystem,
+			CompareAs: lockfile.BundlerEcosystem,
+		},
+		{
+			Name:      "activerecord-jdbcpostgresql-adapter",
+			Version:   "70.1",
+			Ecosystem: lockfile.BundlerEcosystem,
+			CompareAs: lockfile.BundlerEcosystem,
+		},
+		{
+			Name:      "debug",
+			Version:   "1.9.0",
+			Ecosystem: lockfile.BundlerEcosystem,
+			CompareAs: lockfile.BundlerEcosystem,
+			DepGroups: []string{"dev"},
+		},
+		{
+			Name:      "rspec-rails",
+			Version:   "6.1.0",
+			Ecosystem: lockfile.BundlerEcosystem,
+			CompareAs: lockfile.BundlerEcosystem,
+			DepGroups: []string{"dev"},
+		},
+		{
+			Name:      "web-console",
+			Version:   "4.2.1",
+			Ecosystem: lockfile.BundlerEcosystem,
+			CompareAs: lockfile.BundlerEcosystem,
+			DepGroups: []string{"dev"},
+		},
+		{
+			Name:      "rack-mini-profiler",
+			Version:   "3.1.1",
+			Ecosystem: lockfile.BundlerEcosystem,
+			CompareAs: lockfile.BundlerEcosystem,
+			DepGroups: []string{"dev"},
+		},
+		{
+			Name:      "lograge",
+			Version:   "0.14.0",
+			Ecosystem: lockfile.BundlerEcosystem,
+			CompareAs: lockfile.BundlerEcosystem,
+		},
+		{
+			Name:      "capybara",
+			Version:   "3.39.2",
+			Ecosystem: lockfile.BundlerEcosystem,
+			CompareAs: lockfile.BundlerEcosystem,
+			DepGroups: []string{"dev"},
+		},
+		{
+			Name:      "rubocop",
+			Version:   "1.57.2",
+			Ecosystem: lockfile.BundlerEcosystem,
+			CompareAs: lockfile.BundlerEcosystem,
+		},
+	})
+}
+
+func TestGemfileExtractor_Extract_FetchError(t *testing.T) {
+	t.Parallel()
+
+	f, err := lockfile.OpenLocalDepFile("fixtures/gemfile/multiple-gems.Gemfile")
+	if err != nil {
+		t.Fatalf("could not open fixture: %v", err)
+	}
+	defer f.Close()
+
+	errFetch := errors.New("500 Internal Server Error")
+	packages, err := lockfile.GemfileExtractor{
+		FetchVersions: func(string) ([]string, error) {
+			return nil, errFetch
+		},
+	}.Extract(f)
+
+	expectErrIs(t, err, errFetch)
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
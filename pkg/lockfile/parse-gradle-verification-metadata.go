@@ -0,0 +1,67 @@
+package lockfile
+
+import (
+	"encoding/xml"
+	"fmt"
+	"path/filepath"
+)
+
+type GradleVerificationMetadataComponent struct {
+	Group   string `xml:"group,attr"`
+	Name    string `xml:"name,attr"`
+	Version string `xml:"version,attr"`
+}
+
+// GradleVerificationMetadataFile is Gradle's dependency verification metadata,
+// which has the checksums of every artifact resolved by the build
+type GradleVerificationMetadataFile struct {
+	XMLName    xml.Name                              `xml:"verification-metadata"`
+	Components []GradleVerificationMetadataComponent `xml:"components>component"`
+}
+
+type GradleVerificationMetadataExtractor struct{}
+
+func (e GradleVerificationMetadataExtractor) ShouldExtract(path string) bool {
+	return filepath.Base(filepath.Dir(path)) == "gradle" && filepath.Base(path) == "verification-metadata.xml"
+}
+
+func (e GradleVerificationMetadataExtractor) Extract(f DepFile) ([]PackageDetails, error) {
+	var parsedLockfile *GradleVerificationMetadataFile
+
+	err := xml.NewDecoder(f).Decode(&parsedLockfile)
+
+	if err != nil {
+		return []PackageDetails{}, fmt.Errorf("could not extract from %s: %w", f.Path(), err)
+	}
+
+	details := map[string]PackageDetails{}
+
+	for _, component := range parsedLockfile.Components {
+		if component.Group == "" || component.Name == "" || component.Version == "" {
+			continue
+		}
+
+		name := component.Group + ":" + component.Name
+
+		// the same component can be listed more than once, e.g. for different artifacts
+		details[name+"@"+component.Version] = PackageDetails{
+			Name:      name,
+			Version:   component.Version,
+			Ecosystem: MavenEcosystem,
+			CompareAs: MavenEcosystem,
+		}
+	}
+
+	return pkgDetailsMapToSlice(details), nil
+}
+
+var _ Extractor = GradleVerificationMetadataExtractor{}
+
+//nolint:gochecknoinits
+func init() {
+	registerExtractor("verification-metadata.xml", GradleVerificationMetadataExtractor{})
+}
+
+func ParseGradleVerificationMetadata(pathToLockfile string) ([]PackageDetails, error) {
+	return extractFromFile(pathToLockfile, GradleVerificationMetadataExtractor{})
+}
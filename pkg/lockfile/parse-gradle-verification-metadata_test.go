@@ -0,0 +1,156 @@
+package lockfile_test
+
+import (
+	"io/fs"
+	"testing"
+
+	"github.com/google/osv-scanner/pkg/lockfile"
+)
+
+func TestGradleVerificationMetadataExtractor_ShouldExtract(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{
+			name: "",
+			path: "",
+			want: false,
+		},
+		{
+			name: "",
+			path: "verification-metadata.xml",
+			want: false,
+		},
+		{
+			name: "",
+			path: "gradle/verification-metadata.xml",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/my/gradle/verification-metadata.xml",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/my/verification-metadata.xml",
+			want: false,
+		},
+		{
+			name: "",
+			path: "path/to/my/gradle/verification-metadata.xml/file",
+			want: false,
+		},
+		{
+			name: "",
+			path: "path/to/my/gradle/verification-metadata.xml.file",
+			want: false,
+		},
+		{
+			name: "",
+			path: "path.to.my.gradle.verification-metadata.xml",
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e := lockfile.GradleVerificationMetadataExtractor{}
+			got := e.ShouldExtract(tt.path)
+			if got != tt.want {
+				t.Errorf("Extract() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseGradleVerificationMetadata_FileDoesNotExist(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseGradleVerificationMetadata("fixtures/gradle-verification-metadata/does-not-exist")
+
+	expectErrIs(t, err, fs.ErrNotExist)
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseGradleVerificationMetadata_InvalidXml(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseGradleVerificationMetadata("fixtures/gradle-verification-metadata/not-xml.txt")
+
+	expectErrContaining(t, err, "could not extract from")
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseGradleVerificationMetadata_NoComponents(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseGradleVerificationMetadata("fixtures/gradle-verification-metadata/empty.xml")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseGradleVerificationMetadata_OneComponent(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseGradleVerificationMetadata("fixtures/gradle-verification-metadata/one-component.xml")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "org.apache.commons:commons-lang3",
+			Version:   "3.12.0",
+			Ecosystem: lockfile.MavenEcosystem,
+			CompareAs: lockfile.MavenEcosystem,
+		},
+	})
+}
+
+func TestParseGradleVerificationMetadata_MultipleComponents(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseGradleVerificationMetadata("fixtures/gradle-verification-metadata/multiple-components.xml")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "com.google.guava:guava",
+			Version:   "32.1.2-jre",
+			Ecosystem: lockfile.MavenEcosystem,
+			CompareAs: lockfile.MavenEcosystem,
+		},
+		{
+			Name:      "com.google.guava:guava-parent",
+			Version:   "32.1.2-jre",
+			Ecosystem: lockfile.MavenEcosystem,
+			CompareAs: lockfile.MavenEcosystem,
+		},
+		{
+			Name:      "org.springframework.security:spring-security-crypto",
+			Version:   "5.7.3",
+			Ecosystem: lockfile.MavenEcosystem,
+			CompareAs: lockfile.MavenEcosystem,
+		},
+		{
+			Name:      "org.springframework.security:spring-security-crypto",
+			Version:   "5.7.5",
+			Ecosystem: lockfile.MavenEcosystem,
+			CompareAs: lockfile.MavenEcosystem,
+		},
+	})
+}
@@ -0,0 +1,130 @@
+package lockfile
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// GradleVersionCatalogFile is a Gradle version catalog, where versions can
+// either be strings or rich versions, and libraries can either be
+// "group:name:version" strings or tables
+type GradleVersionCatalogFile struct {
+	Versions  map[string]any `toml:"versions"`
+	Libraries map[string]any `toml:"libraries"`
+}
+
+// resolveGradleCatalogVersion returns the single version that a rich version
+// will resolve to, if it has one, preferring the strictest constraint
+func resolveGradleCatalogVersion(version any) string {
+	switch v := version.(type) {
+	case string:
+		return v
+	case map[string]any:
+		for _, key := range []string{"strictly", "require", "prefer"} {
+			if s, ok := v[key].(string); ok && isGradleSingleVersion(s) {
+				return s
+			}
+		}
+	}
+
+	return ""
+}
+
+// isGradleSingleVersion checks if the given version is a concrete version,
+// rather than a range or dynamic version like "1.+" or "latest.release"
+func isGradleSingleVersion(version string) bool {
+	return version != "" &&
+		!strings.ContainsAny(version, "[](),+") &&
+		!strings.HasPrefix(version, "latest.")
+}
+
+func parseGradleCatalogLibrary(library any, versions map[string]any) (string, string) {
+	switch l := library.(type) {
+	case string:
+		group, rest, _ := strings.Cut(l, ":")
+		name, version, _ := strings.Cut(rest, ":")
+
+		if group == "" || name == "" {
+			return "", ""
+		}
+
+		return group + ":" + name, version
+	case map[string]any:
+		name, _ := l["module"].(string)
+
+		if name == "" {
+			group, _ := l["group"].(string)
+			artifact, _ := l["name"].(string)
+
+			if group == "" || artifact == "" {
+				return "", ""
+			}
+
+			name = group + ":" + artifact
+		}
+
+		version := l["version"]
+
+		// "version.ref" is a dotted key, so references are decoded as a table
+		if v, ok := version.(map[string]any); ok {
+			if ref, ok := v["ref"].(string); ok {
+				version = versions[ref]
+			}
+		}
+
+		return name, resolveGradleCatalogVersion(version)
+	}
+
+	return "", ""
+}
+
+type GradleVersionCatalogExtractor struct{}
+
+func (e GradleVersionCatalogExtractor) ShouldExtract(path string) bool {
+	return filepath.Base(path) == "libs.versions.toml"
+}
+
+func (e GradleVersionCatalogExtractor) Extract(f DepFile) ([]PackageDetails, error) {
+	var parsedCatalog *GradleVersionCatalogFile
+
+	_, err := toml.NewDecoder(f).Decode(&parsedCatalog)
+
+	if err != nil {
+		return []PackageDetails{}, fmt.Errorf("could not extract from %s: %w", f.Path(), err)
+	}
+
+	details := map[string]PackageDetails{}
+
+	for _, library := range parsedCatalog.Libraries {
+		name, version := parseGradleCatalogLibrary(library, parsedCatalog.Versions)
+
+		// libraries without a single version are resolved by Gradle at build
+		// time, e.g. from a platform, so there is nothing that can be reported
+		if name == "" || !isGradleSingleVersion(version) {
+			continue
+		}
+
+		details[name+"@"+version] = PackageDetails{
+			Name:      name,
+			Version:   version,
+			Ecosystem: MavenEcosystem,
+			CompareAs: MavenEcosystem,
+		}
+	}
+
+	return pkgDetailsMapToSlice(details), nil
+}
+
+var _ Extractor = GradleVersionCatalogExtractor{}
+
+//nolint:gochecknoinits
+func init() {
+	registerExtractor("libs.versions.toml", GradleVersionCatalogExtractor{})
+}
+
+func ParseGradleVersionCatalog(pathToLockfile string) ([]PackageDetails, error) {
+	return extractFromFile(pathToLockfile, GradleVersionCatalogExtractor{})
+}
@@ -0,0 +1,183 @@
+package lockfile_test
+
+import (
+	"io/fs"
+	"testing"
+
+	"github.com/google/osv-scanner/pkg/lockfile"
+)
+
+func TestGradleVersionCatalogExtractor_ShouldExtract(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{
+			name: "",
+			path: "",
+			want: false,
+		},
+		{
+			name: "",
+			path: "libs.versions.toml",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/my/gradle/libs.versions.toml",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/my/libs.versions.toml/file",
+			want: false,
+		},
+		{
+			name: "",
+			path: "path/to/my/libs.versions.toml.file",
+			want: false,
+		},
+		{
+			name: "",
+			path: "path.to.my.libs.versions.toml",
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e := lockfile.GradleVersionCatalogExtractor{}
+			got := e.ShouldExtract(tt.path)
+			if got != tt.want {
+				t.Errorf("Extract() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseGradleVersionCatalog_FileDoesNotExist(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseGradleVersionCatalog("fixtures/gradle-version-catalog/does-not-exist")
+
+	expectErrIs(t, err, fs.ErrNotExist)
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseGradleVersionCatalog_InvalidToml(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseGradleVersionCatalog("fixtures/gradle-version-catalog/not-toml.txt")
+
+	expectErrContaining(t, err, "could not extract from")
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseGradleVersionCatalog_NoLibraries(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseGradleVersionCatalog("fixtures/gradle-version-catalog/empty.toml")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseGradleVersionCatalog_OneLibrary(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseGradleVersionCatalog("fixtures/gradle-version-catalog/one-library.toml")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "org.apache.commons:commons-lang3",
+			Version:   "3.12.0",
+			Ecosystem: lockfile.MavenEcosystem,
+			CompareAs: lockfile.MavenEcosystem,
+		},
+	})
+}
+
+func TestParseGradleVersionCatalog_Notations(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseGradleVersionCatalog("fixtures/gradle-version-catalog/notations.toml")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "org.codehaus.groovy:groovy",
+			Version:   "3.0.5",
+			Ecosystem: lockfile.MavenEcosystem,
+			CompareAs: lockfile.MavenEcosystem,
+		},
+		{
+			Name:      "org.codehaus.groovy:groovy-json",
+			Version:   "3.0.5",
+			Ecosystem: lockfile.MavenEcosystem,
+			CompareAs: lockfile.MavenEcosystem,
+		},
+		{
+			Name:      "org.junit.jupiter:junit-jupiter",
+			Version:   "5.10.0",
+			Ecosystem: lockfile.MavenEcosystem,
+			CompareAs: lockfile.MavenEcosystem,
+		},
+		{
+			Name:      "com.squareup.okhttp3:okhttp",
+			Version:   "4.11.0",
+			Ecosystem: lockfile.MavenEcosystem,
+			CompareAs: lockfile.MavenEcosystem,
+		},
+		{
+			Name:      "com.google.guava:guava",
+			Version:   "32.1.2-jre",
+			Ecosystem: lockfile.MavenEcosystem,
+			CompareAs: lockfile.MavenEcosystem,
+		},
+		{
+			Name:      "org.apache.commons:commons-text",
+			Version:   "1.10.0",
+			Ecosystem: lockfile.MavenEcosystem,
+			CompareAs: lockfile.MavenEcosystem,
+		},
+		{
+			Name:      "org.slf4j:slf4j-api",
+			Version:   "1.7.25",
+			Ecosystem: lockfile.MavenEcosystem,
+			CompareAs: lockfile.MavenEcosystem,
+		},
+	})
+}
+
+func TestParseGradleVersionCatalog_WithoutSingleVersions(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseGradleVersionCatalog("fixtures/gradle-version-catalog/without-versions.toml")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "androidx.compose:compose-bom",
+			Version:   "2023.08.00",
+			Ecosystem: lockfile.MavenEcosystem,
+			CompareAs: lockfile.MavenEcosystem,
+		},
+	})
+}
@@ -0,0 +1,78 @@
+package lockfile
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const HelmEcosystem Ecosystem = "Helm"
+
+type HelmChartDependency struct {
+	Name       string `yaml:"name"`
+	Version    string `yaml:"version"`
+	Repository string `yaml:"repository"`
+}
+
+// isLocal reports if the dependency is a chart that lives alongside this one,
+// rather than one that has been published to a repository
+func (dep HelmChartDependency) isLocal() bool {
+	return strings.HasPrefix(dep.Repository, "file://")
+}
+
+type HelmChartLockFile struct {
+	Dependencies []HelmChartDependency `yaml:"dependencies"`
+}
+
+type HelmChartLockExtractor struct{}
+
+func (e HelmChartLockExtractor) ShouldExtract(path string) bool {
+	return filepath.Base(path) == "Chart.lock"
+}
+
+func (e HelmChartLockExtractor) Extract(f DepFile) ([]PackageDetails, error) {
+	var parsedLockfile *HelmChartLockFile
+
+	err := yaml.NewDecoder(f).Decode(&parsedLockfile)
+
+	if err != nil && !errors.Is(err, io.EOF) {
+		return []PackageDetails{}, fmt.Errorf("could not extract from %s: %w", f.Path(), err)
+	}
+
+	// this will happen if the file is empty
+	if parsedLockfile == nil {
+		return []PackageDetails{}, nil
+	}
+
+	packages := make([]PackageDetails, 0, len(parsedLockfile.Dependencies))
+
+	for _, dep := range parsedLockfile.Dependencies {
+		if dep.isLocal() {
+			continue
+		}
+
+		packages = append(packages, PackageDetails{
+			Name:      dep.Name,
+			Version:   dep.Version,
+			Ecosystem: HelmEcosystem,
+			CompareAs: HelmEcosystem,
+		})
+	}
+
+	return packages, nil
+}
+
+var _ Extractor = HelmChartLockExtractor{}
+
+//nolint:gochecknoinits
+func init() {
+	registerExtractor("Chart.lock", HelmChartLockExtractor{})
+}
+
+func ParseHelmChartLock(pathToLockfile string) ([]PackageDetails, error) {
+	return extractFromFile(pathToLockfile, HelmChartLockExtractor{})
+}
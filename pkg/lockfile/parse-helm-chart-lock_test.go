@@ -0,0 +1,129 @@
+package lockfile_test
+
+import (
+	"io/fs"
+	"testing"
+
+	"github.com/google/osv-scanner/pkg/lockfile"
+)
+
+func TestHelmChartLockExtractor_ShouldExtract(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{
+			name: "",
+			path: "",
+			want: false,
+		},
+		{
+			name: "",
+			path: "Chart.lock",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/my/Chart.lock",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/my/Chart.lock/file",
+			want: false,
+		},
+		{
+			name: "",
+			path: "path/to/my/Chart.lock.file",
+			want: false,
+		},
+		{
+			name: "",
+			path: "path.to.my.Chart.lock",
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e := lockfile.HelmChartLockExtractor{}
+			got := e.ShouldExtract(tt.path)
+			if got != tt.want {
+				t.Errorf("Extract() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseHelmChartLock_FileDoesNotExist(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseHelmChartLock("fixtures/helm/does-not-exist")
+
+	expectErrIs(t, err, fs.ErrNotExist)
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseHelmChartLock_InvalidYaml(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseHelmChartLock("fixtures/helm/not-yaml.txt")
+
+	expectErrContaining(t, err, "could not extract from")
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseHelmChartLock_EmptyFile(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseHelmChartLock("fixtures/helm/empty.lock")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseHelmChartLock_NoDependencies(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseHelmChartLock("fixtures/helm/no-dependencies.lock")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseHelmChartLock_MultipleDependencies(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseHelmChartLock("fixtures/helm/multiple-dependencies.lock")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "postgresql",
+			Version:   "12.1.6",
+			Ecosystem: lockfile.HelmEcosystem,
+			CompareAs: lockfile.HelmEcosystem,
+		},
+		{
+			Name:      "redis",
+			Version:   "18.6.1",
+			Ecosystem: lockfile.HelmEcosystem,
+			CompareAs: lockfile.HelmEcosystem,
+		},
+	})
+}
@@ -0,0 +1,83 @@
+package lockfile
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/osv-scanner/internal/cachedregexp"
+	"gopkg.in/yaml.v3"
+)
+
+type HelmChartFile struct {
+	Dependencies []HelmChartDependency `yaml:"dependencies"`
+}
+
+type HelmChartExtractor struct{}
+
+func (e HelmChartExtractor) ShouldExtract(path string) bool {
+	return filepath.Base(path) == "Chart.yaml"
+}
+
+// Extract extracts the dependencies of a Helm chart that are pinned to an exact
+// version; if the chart has a Chart.lock then nothing is extracted, as the
+// dependencies will be reported from that instead
+func (e HelmChartExtractor) Extract(f DepFile) ([]PackageDetails, error) {
+	if lock, err := f.Open("Chart.lock"); err == nil {
+		_ = lock.Close()
+
+		return []PackageDetails{}, nil
+	}
+
+	var parsedChart *HelmChartFile
+
+	err := yaml.NewDecoder(f).Decode(&parsedChart)
+
+	if err != nil && !errors.Is(err, io.EOF) {
+		return []PackageDetails{}, fmt.Errorf("could not extract from %s: %w", f.Path(), err)
+	}
+
+	// this will happen if the file is empty
+	if parsedChart == nil {
+		return []PackageDetails{}, nil
+	}
+
+	re := cachedregexp.MustCompile(`^=?\s*v?(\d+\.\d+\.\d+(?:[-+][\w.-]+)?)$`)
+
+	packages := make([]PackageDetails, 0, len(parsedChart.Dependencies))
+
+	for _, dep := range parsedChart.Dependencies {
+		if dep.isLocal() {
+			continue
+		}
+
+		match := re.FindStringSubmatch(strings.TrimSpace(dep.Version))
+
+		// ranges can't be resolved without fetching the index of the repository
+		if match == nil {
+			continue
+		}
+
+		packages = append(packages, PackageDetails{
+			Name:      dep.Name,
+			Version:   match[1],
+			Ecosystem: HelmEcosystem,
+			CompareAs: HelmEcosystem,
+		})
+	}
+
+	return packages, nil
+}
+
+var _ Extractor = HelmChartExtractor{}
+
+//nolint:gochecknoinits
+func init() {
+	registerExtractor("Chart.yaml", HelmChartExtractor{})
+}
+
+func ParseHelmChart(pathToLockfile string) ([]PackageDetails, error) {
+	return extractFromFile(pathToLockfile, HelmChartExtractor{})
+}
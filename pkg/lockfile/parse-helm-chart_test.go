@@ -0,0 +1,129 @@
+package lockfile_test
+
+import (
+	"io/fs"
+	"testing"
+
+	"github.com/google/osv-scanner/pkg/lockfile"
+)
+
+func TestHelmChartExtractor_ShouldExtract(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{
+			name: "",
+			path: "",
+			want: false,
+		},
+		{
+			name: "",
+			path: "Chart.yaml",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/my/Chart.yaml",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/my/Chart.yaml/file",
+			want: false,
+		},
+		{
+			name: "",
+			path: "path/to/my/Chart.yaml.file",
+			want: false,
+		},
+		{
+			name: "",
+			path: "path.to.my.Chart.yaml",
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e := lockfile.HelmChartExtractor{}
+			got := e.ShouldExtract(tt.path)
+			if got != tt.want {
+				t.Errorf("Extract() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseHelmChart_FileDoesNotExist(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseHelmChart("fixtures/helm/does-not-exist")
+
+	expectErrIs(t, err, fs.ErrNotExist)
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseHelmChart_InvalidYaml(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseHelmChart("fixtures/helm/not-yaml.txt")
+
+	expectErrContaining(t, err, "could not extract from")
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseHelmChart_NoDependencies(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseHelmChart("fixtures/helm/no-dependencies.yaml")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseHelmChart_MultipleDependencies(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseHelmChart("fixtures/helm/multiple-dependencies.yaml")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "postgresql",
+			Version:   "12.1.6",
+			Ecosystem: lockfile.HelmEcosystem,
+			CompareAs: lockfile.HelmEcosystem,
+		},
+		{
+			Name:      "redis",
+			Version:   "18.6.1",
+			Ecosystem: lockfile.HelmEcosystem,
+			CompareAs: lockfile.HelmEcosystem,
+		},
+	})
+}
+
+func TestParseHelmChart_WithChartLock(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseHelmChart("fixtures/helm/with-lock/Chart.yaml")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
@@ -0,0 +1,108 @@
+package lockfile
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+)
+
+type NixFlakeLockSource struct {
+	Type  string `json:"type"`
+	Owner string `json:"owner"`
+	Repo  string `json:"repo"`
+	Host  string `json:"host"`
+	URL   string `json:"url"`
+	Rev   string `json:"rev"`
+}
+
+// nixFlakeDefaultHosts are the hosts used by the types of flake inputs that
+// reference a repository on a forge, unless the input overrides it
+var nixFlakeDefaultHosts = map[string]string{
+	"github":    "github.com",
+	"gitlab":    "gitlab.com",
+	"sourcehut": "git.sr.ht",
+}
+
+// name returns a name for the repository that the flake input was locked
+// to, or an empty string if the input is not locked to a repository
+func (source NixFlakeLockSource) name() string {
+	if source.Type == "git" || source.Type == "mercurial" {
+		return source.URL
+	}
+
+	host, ok := nixFlakeDefaultHosts[source.Type]
+
+	if !ok {
+		return ""
+	}
+
+	if source.Host != "" {
+		host = source.Host
+	}
+
+	return host + "/" + source.Owner + "/" + source.Repo
+}
+
+type NixFlakeLockNode struct {
+	Locked *NixFlakeLockSource `json:"locked"`
+}
+
+type NixFlakeLockFile struct {
+	Version int                         `json:"version"`
+	Root    string                      `json:"root"`
+	Nodes   map[string]NixFlakeLockNode `json:"nodes"`
+}
+
+type NixFlakeLockExtractor struct{}
+
+func (e NixFlakeLockExtractor) ShouldExtract(path string) bool {
+	return filepath.Base(path) == "flake.lock"
+}
+
+// Extract extracts the inputs of a flake that are locked to a commit of a repository,
+// such as nixpkgs; as Nix does not have an ecosystem in OSV, the inputs are reported
+// without one so that they are matched against the commit ranges of advisories
+func (e NixFlakeLockExtractor) Extract(f DepFile) ([]PackageDetails, error) {
+	var parsedLockfile *NixFlakeLockFile
+
+	err := json.NewDecoder(f).Decode(&parsedLockfile)
+
+	if err != nil {
+		return []PackageDetails{}, fmt.Errorf("could not extract from %s: %w", f.Path(), err)
+	}
+
+	details := map[string]PackageDetails{}
+
+	for key, node := range parsedLockfile.Nodes {
+		// the root node is the flake itself
+		if key == parsedLockfile.Root || node.Locked == nil {
+			continue
+		}
+
+		name := node.Locked.name()
+
+		// inputs such as paths and tarballs are not locked to a commit
+		if name == "" || node.Locked.Rev == "" {
+			continue
+		}
+
+		// the same repository can be used by a number of inputs
+		details[name+"@"+node.Locked.Rev] = PackageDetails{
+			Name:   name,
+			Commit: node.Locked.Rev,
+		}
+	}
+
+	return pkgDetailsMapToSlice(details), nil
+}
+
+var _ Extractor = NixFlakeLockExtractor{}
+
+//nolint:gochecknoinits
+func init() {
+	registerExtractor("flake.lock", NixFlakeLockExtractor{})
+}
+
+func ParseNixFlakeLock(pathToLockfile string) ([]PackageDetails, error) {
+	return extractFromFile(pathToLockfile, NixFlakeLockExtractor{})
+}
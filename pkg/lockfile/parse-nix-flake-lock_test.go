@@ -0,0 +1,142 @@
+package lockfile_test
+
+import (
+	"io/fs"
+	"testing"
+
+	"github.com/google/osv-scanner/pkg/lockfile"
+)
+
+func TestNixFlakeLockExtractor_ShouldExtract(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{
+			name: "",
+			path: "",
+			want: false,
+		},
+		{
+			name: "",
+			path: "flake.lock",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/my/flake.lock",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/my/flake.lock/file",
+			want: false,
+		},
+		{
+			name: "",
+			path: "path/to/my/flake.lock.file",
+			want: false,
+		},
+		{
+			name: "",
+			path: "path.to.my.flake.lock",
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e := lockfile.NixFlakeLockExtractor{}
+			got := e.ShouldExtract(tt.path)
+			if got != tt.want {
+				t.Errorf("Extract() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseNixFlakeLock_FileDoesNotExist(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseNixFlakeLock("fixtures/nix/does-not-exist")
+
+	expectErrIs(t, err, fs.ErrNotExist)
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseNixFlakeLock_InvalidJson(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseNixFlakeLock("fixtures/nix/not-json.txt")
+
+	expectErrContaining(t, err, "could not extract from")
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseNixFlakeLock_NoInputs(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseNixFlakeLock("fixtures/nix/no-inputs.lock")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseNixFlakeLock_OneInput(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseNixFlakeLock("fixtures/nix/one-input.lock")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:   "github.com/NixOS/nixpkgs",
+			Commit: "317484b1ead87b9c1b8ac5261a8d2dd748a0492d",
+		},
+	})
+}
+
+func TestParseNixFlakeLock_MultipleInputs(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseNixFlakeLock("fixtures/nix/multiple-inputs.lock")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:   "github.com/numtide/flake-utils",
+			Commit: "4022d587cbbfd70fe950c1e2083a02621806a725",
+		},
+		{
+			Name:   "github.com/nix-community/home-manager",
+			Commit: "d5824a76bc6bb93d1dce9ebbbcb09a9b6abcc224",
+		},
+		{
+			Name:   "https://example.com/my-tools.git",
+			Commit: "9b0c7b5bcc9f8c1e3e2f3f0b0d7a3e1f6a2c4b5d",
+		},
+		{
+			Name:   "github.com/NixOS/nixpkgs",
+			Commit: "317484b1ead87b9c1b8ac5261a8d2dd748a0492d",
+		},
+		{
+			Name:   "gitlab.com/nix-systems/default",
+			Commit: "da67096a3b9bf56a91d16901293e51ba5b49a27e",
+		},
+	})
+}
@@ -11,10 +11,12 @@ import (
 type NpmLockDependency struct {
 	// For an aliased package, Version is like "npm:[name]@[version]"
 	Version      string                       `json:"version"`
+	Integrity    string                       `json:"integrity,omitempty"`
 	Dependencies map[string]NpmLockDependency `json:"dependencies,omitempty"`
 
 	Dev      bool `json:"dev,omitempty"`
 	Optional bool `json:"optional,omitempty"`
+	Bundled  bool `json:"bundled,omitempty"`
 
 	Requires map[string]string `json:"requires,omitempty"`
 }
@@ -24,6 +26,8 @@ type NpmLockPackage struct {
 	Name     string `json:"name"`
 	Version  string `json:"version"`
 	Resolved string `json:"resolved"`
+	// The Subresource Integrity hash of the package's tarball e.g. "sha512-..."
+	Integrity string `json:"integrity,omitempty"`
 
 	Dependencies         map[string]string `json:"dependencies,omitempty"`
 	DevDependencies      map[string]string `json:"devDependencies,omitempty"`
@@ -33,6 +37,11 @@ type NpmLockPackage struct {
 	Dev         bool `json:"dev,omitempty"`
 	DevOptional bool `json:"devOptional,omitempty"`
 	Optional    bool `json:"optional,omitempty"`
+	InBundle    bool `json:"inBundle,omitempty"`
+
+	// The platforms the package can be installed on, e.g. for native binaries
+	OS  []string `json:"os,omitempty"`
+	CPU []string `json:"cpu,omitempty"`
 
 	Link bool `json:"link,omitempty"`
 }
@@ -7,7 +7,10 @@ import (
 )
 
 type NuGetLockPackage struct {
-	Resolved string `json:"resolved"`
+	Type         string            `json:"type"` // "Direct", "Transitive" or "Project"
+	Requested    string            `json:"requested,omitempty"`
+	Resolved     string            `json:"resolved"`
+	Dependencies map[string]string `json:"dependencies,omitempty"`
 }
 
 // NuGetLockfile contains the required dependency information as defined in
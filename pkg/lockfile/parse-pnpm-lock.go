@@ -26,14 +26,38 @@ type PnpmLockPackage struct {
 	Dev        bool                      `yaml:"dev"`
 }
 
+// PnpmImporterDependency is a direct dependency of a project in a pnpm v9 lockfile.
+type PnpmImporterDependency struct {
+	Specifier string `yaml:"specifier"`
+	Version   string `yaml:"version"`
+}
+
+// PnpmImporter is a project (i.e. the root or a workspace package) in a pnpm v9 lockfile.
+type PnpmImporter struct {
+	Dependencies         map[string]PnpmImporterDependency `yaml:"dependencies,omitempty"`
+	OptionalDependencies map[string]PnpmImporterDependency `yaml:"optionalDependencies,omitempty"`
+	DevDependencies      map[string]PnpmImporterDependency `yaml:"devDependencies,omitempty"`
+}
+
+// PnpmSnapshot is the dependencies of an installed package in a pnpm v9 lockfile,
+// which replace the "dev" and "optional" flags of the packages of earlier versions.
+type PnpmSnapshot struct {
+	Dependencies         map[string]string `yaml:"dependencies,omitempty"`
+	OptionalDependencies map[string]string `yaml:"optionalDependencies,omitempty"`
+}
+
 type PnpmLockfile struct {
-	Version  float64                    `yaml:"lockfileVersion"`
-	Packages map[string]PnpmLockPackage `yaml:"packages,omitempty"`
+	Version   float64                    `yaml:"lockfileVersion"`
+	Packages  map[string]PnpmLockPackage `yaml:"packages,omitempty"`
+	Importers map[string]PnpmImporter    `yaml:"importers,omitempty"`
+	Snapshots map[string]PnpmSnapshot    `yaml:"snapshots,omitempty"`
 }
 
 type pnpmLockfileV6 struct {
-	Version  string                     `yaml:"lockfileVersion"`
-	Packages map[string]PnpmLockPackage `yaml:"packages,omitempty"`
+	Version   string                     `yaml:"lockfileVersion"`
+	Packages  map[string]PnpmLockPackage `yaml:"packages,omitempty"`
+	Importers map[string]PnpmImporter    `yaml:"importers,omitempty"`
+	Snapshots map[string]PnpmSnapshot    `yaml:"snapshots,omitempty"`
 }
 
 func (l *PnpmLockfile) UnmarshalYAML(unmarshal func(interface{}) error) error {
@@ -51,6 +75,8 @@ func (l *PnpmLockfile) UnmarshalYAML(unmarshal func(interface{}) error) error {
 
 	l.Version = parsedVersion
 	l.Packages = lockfileV6.Packages
+	l.Importers = lockfileV6.Importers
+	l.Snapshots = lockfileV6.Snapshots
 
 	return nil
 }
@@ -120,7 +146,25 @@ func parseNameAtVersion(value string) (name string, version string) {
 	return matches[1], matches[2]
 }
 
+// pnpmCommit returns the commit of a package installed from a git repository, if any
+func pnpmCommit(resolution PnpmLockPackageResolution) string {
+	if strings.HasPrefix(resolution.Tarball, "https://codeload.github.com") {
+		re := cachedregexp.MustCompile(`https://codeload\.github\.com(?:/[\w-.]+){2}/tar\.gz/(\w+)$`)
+		matched := re.FindStringSubmatch(resolution.Tarball)
+
+		if matched != nil {
+			return matched[1]
+		}
+	}
+
+	return resolution.Commit
+}
+
 func parsePnpmLock(lockfile PnpmLockfile) []PackageDetails {
+	if lockfile.Version >= 9 {
+		return parsePnpmLockV9(lockfile)
+	}
+
 	packages := make([]PackageDetails, 0, len(lockfile.Packages))
 
 	for s, pkg := range lockfile.Packages {
@@ -142,16 +186,7 @@ func parsePnpmLock(lockfile PnpmLockfile) []PackageDetails {
 			continue
 		}
 
-		commit := pkg.Resolution.Commit
-
-		if strings.HasPrefix(pkg.Resolution.Tarball, "https://codeload.github.com") {
-			re := cachedregexp.MustCompile(`https://codeload\.github\.com(?:/[\w-.]+){2}/tar\.gz/(\w+)$`)
-			matched := re.FindStringSubmatch(pkg.Resolution.Tarball)
-
-			if matched != nil {
-				commit = matched[1]
-			}
-		}
+		commit := pnpmCommit(pkg.Resolution)
 
 		var depGroups []string
 		if pkg.Dev {
@@ -171,6 +206,143 @@ func parsePnpmLock(lockfile PnpmLockfile) []PackageDetails {
 	return packages
 }
 
+// pnpmV9Key splits a package or snapshot key of a pnpm v9 lockfile e.g. "@scope/name@1.0.0(peer@2.0.0)"
+// into the package's name and version, without the peer dependency suffix
+func pnpmV9Key(key string) (string, string) {
+	key, _, _ = strings.Cut(key, "(")
+	// skip the @ of a scope, versions can also contain @ e.g. "git+ssh://git@github.com/..."
+	idx := strings.Index(key[min(1, len(key)):], "@")
+	if idx < 0 {
+		return key, ""
+	}
+	idx++
+
+	return key[:idx], key[idx+1:]
+}
+
+// pnpmV9SnapshotKey returns the snapshot key of a dependency in a pnpm v9 lockfile.
+// The version is either the version of the named package e.g. "1.0.0(peer@2.0.0)",
+// or the key of another package for aliases e.g. "other@1.0.0".
+func pnpmV9SnapshotKey(name, version string) string {
+	base, _, _ := strings.Cut(version, "(")
+	if strings.Contains(base[min(1, len(base)):], "@") {
+		return version
+	}
+
+	return name + "@" + version
+}
+
+// pnpmV9DepGroups works out the dependency groups of each package in a pnpm v9 lockfile,
+// by walking the snapshots from each importer's dependencies.
+// Packages that are only required by dev dependencies are in the "dev" group,
+// and packages that are only required through optional dependencies are in the "optional" group.
+func pnpmV9DepGroups(lockfile PnpmLockfile) map[string][]string {
+	type state struct {
+		key      string
+		dev      bool
+		optional bool
+	}
+	seen := make(map[state]bool)
+	var queue []state
+	push := func(s state) {
+		if !seen[s] {
+			seen[s] = true
+			queue = append(queue, s)
+		}
+	}
+
+	for _, importer := range lockfile.Importers {
+		for name, dep := range importer.Dependencies {
+			push(state{key: pnpmV9SnapshotKey(name, dep.Version)})
+		}
+		for name, dep := range importer.OptionalDependencies {
+			push(state{key: pnpmV9SnapshotKey(name, dep.Version), optional: true})
+		}
+		for name, dep := range importer.DevDependencies {
+			push(state{key: pnpmV9SnapshotKey(name, dep.Version), dev: true})
+		}
+	}
+
+	for len(queue) > 0 {
+		s := queue[0]
+		queue = queue[1:]
+		snapshot := lockfile.Snapshots[s.key]
+		for name, version := range snapshot.Dependencies {
+			push(state{key: pnpmV9SnapshotKey(name, version), dev: s.dev, optional: s.optional})
+		}
+		for name, version := range snapshot.OptionalDependencies {
+			push(state{key: pnpmV9SnapshotKey(name, version), dev: s.dev, optional: true})
+		}
+	}
+
+	// a package is in a group only if every way it is required is in that group
+	type reached struct{ prod, dev, optional bool }
+	reachedBy := make(map[string]reached)
+	for s := range seen {
+		name, version := pnpmV9Key(s.key)
+		key := name + "@" + version
+		r := reachedBy[key]
+		switch {
+		case s.dev:
+			r.dev = true
+		case s.optional:
+			r.optional = true
+		default:
+			r.prod = true
+		}
+		reachedBy[key] = r
+	}
+
+	groups := make(map[string][]string)
+	for key, r := range reachedBy {
+		if r.prod {
+			continue
+		}
+		if r.dev {
+			groups[key] = append(groups[key], "dev")
+		}
+		if r.optional {
+			groups[key] = append(groups[key], "optional")
+		}
+	}
+
+	return groups
+}
+
+func parsePnpmLockV9(lockfile PnpmLockfile) []PackageDetails {
+	depGroups := pnpmV9DepGroups(lockfile)
+	packages := make([]PackageDetails, 0, len(lockfile.Packages))
+
+	for key, pkg := range lockfile.Packages {
+		name, version := pnpmV9Key(key)
+		groups := depGroups[name+"@"+version]
+
+		if pkg.Name != "" {
+			name = pkg.Name
+		}
+
+		if pkg.Version != "" {
+			version = pkg.Version
+		}
+
+		// packages that are not from a registry (e.g. "file:" or tarball URLs) without a version cannot be matched
+		if name == "" || version == "" || !startsWithNumber(version) {
+			continue
+		}
+
+		packages = append(packages, PackageDetails{
+			Name:      name,
+			Version:   version,
+			Ecosystem: PnpmEcosystem,
+			CompareAs: PnpmEcosystem,
+			Commit:    pnpmCommit(pkg.Resolution),
+			DepGroups: groups,
+		})
+	}
+
+	return packages
+}
+
 type PnpmLockExtractor struct{}
 
 func (e PnpmLockExtractor) ShouldExtract(path string) bool {
@@ -196,6 +196,72 @@ func TestParsePnpmLock_ScopedPackagesV6Lockfile(t *testing.T) {
 	})
 }
 
+func TestParsePnpmLock_V9Lockfile(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParsePnpmLock("fixtures/pnpm/v9-lockfile.yaml")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "@scope/ui",
+			Version:   "2.1.0",
+			Ecosystem: lockfile.PnpmEcosystem,
+			CompareAs: lockfile.PnpmEcosystem,
+		},
+		{
+			Name:      "aliased",
+			Version:   "3.0.0",
+			Ecosystem: lockfile.PnpmEcosystem,
+			CompareAs: lockfile.PnpmEcosystem,
+		},
+		{
+			Name:      "bar",
+			Version:   "1.5.0",
+			Ecosystem: lockfile.PnpmEcosystem,
+			CompareAs: lockfile.PnpmEcosystem,
+			DepGroups: []string{"dev"},
+		},
+		{
+			// also required by the dev dependency bar
+			Name:      "baz",
+			Version:   "3.0.0",
+			Ecosystem: lockfile.PnpmEcosystem,
+			CompareAs: lockfile.PnpmEcosystem,
+		},
+		{
+			Name:      "foo",
+			Version:   "1.0.0",
+			Ecosystem: lockfile.PnpmEcosystem,
+			CompareAs: lockfile.PnpmEcosystem,
+		},
+		{
+			Name:      "fsevents",
+			Version:   "2.3.3",
+			Ecosystem: lockfile.PnpmEcosystem,
+			CompareAs: lockfile.PnpmEcosystem,
+			DepGroups: []string{"optional"},
+		},
+		{
+			Name:      "qux",
+			Version:   "0.1.0",
+			Ecosystem: lockfile.PnpmEcosystem,
+			CompareAs: lockfile.PnpmEcosystem,
+			DepGroups: []string{"dev"},
+		},
+		{
+			// a dev dependency of a workspace, but also a dependency of @scope/ui
+			Name:      "react",
+			Version:   "18.2.0",
+			Ecosystem: lockfile.PnpmEcosystem,
+			CompareAs: lockfile.PnpmEcosystem,
+		},
+	})
+}
+
 func TestParsePnpmLock_PeerDependencies(t *testing.T) {
 	t.Parallel()
 
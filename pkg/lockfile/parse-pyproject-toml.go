@@ -0,0 +1,501 @@
+package lockfile
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/google/osv-scanner/internal/cachedregexp"
+	"github.com/google/osv-scanner/internal/semantic"
+)
+
+type PyprojectProject struct {
+	Dependencies         []string            `toml:"dependencies"`
+	OptionalDependencies map[string][]string `toml:"optional-dependencies"`
+}
+
+type PyprojectPoetryGroup struct {
+	Dependencies map[string]any `toml:"dependencies"`
+}
+
+type PyprojectPoetry struct {
+	Dependencies    map[string]any                  `toml:"dependencies"`
+	DevDependencies map[string]any                  `toml:"dev-dependencies"`
+	Groups          map[string]PyprojectPoetryGroup `toml:"group"`
+}
+
+type PyprojectPdm struct {
+	DevDependencies map[string][]string `toml:"dev-dependencies"`
+}
+
+type PyprojectUv struct {
+	DevDependencies []string `toml:"dev-dependencies"`
+}
+
+type PyprojectTool struct {
+	Poetry PyprojectPoetry `toml:"poetry"`
+	Pdm    PyprojectPdm    `toml:"pdm"`
+	Uv     PyprojectUv     `toml:"uv"`
+}
+
+const PyprojectTomlEcosystem = PipEcosystem
+
+type PyprojectToml struct {
+	Project PyprojectProject `toml:"project"`
+	// the groups can also include other groups, which are not strings
+	DependencyGroups map[string][]any `toml:"dependency-groups"`
+	Tool             PyprojectTool    `toml:"tool"`
+}
+
+// pythonConstraint is a single clause of a version specifier, like ">=1.2"
+type pythonConstraint struct {
+	op      string
+	version string
+}
+
+// isPythonWildcard reports if the version is a prefix match like "1.2.*"
+func isPythonWildcard(version string) bool {
+	return strings.HasSuffix(version, ".*")
+}
+
+// pythonPreRelease returns the release that a version is a pre-release of,
+// if it is a pre-release or a development release
+func pythonPreRelease(version string) (string, bool) {
+	re := cachedregexp.MustCompile(`(?i)^v?(\d+(?:\.\d+)*)[._-]?(?:a|b|c|rc|alpha|beta|pre|preview|dev)`)
+
+	// the local segment of a version can contain anything
+	public, _, _ := strings.Cut(version, "+")
+
+	match := re.FindStringSubmatch(public)
+
+	if match == nil {
+		return "", false
+	}
+
+	return match[1], true
+}
+
+func (c pythonConstraint) satisfiedBy(version string) bool {
+	if c.op == "===" {
+		return version == c.version
+	}
+
+	if isPythonWildcard(c.version) {
+		prefix := strings.TrimSuffix(c.version, ".*")
+		matches := version == prefix || strings.HasPrefix(version, prefix+".")
+
+		if c.op == "!=" {
+			return !matches
+		}
+
+		return matches
+	}
+
+	cmp := semantic.MustParse(version, "PyPI").CompareStr(c.version)
+
+	switch c.op {
+	case "==":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case ">=":
+		return cmp >= 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case "<":
+		// "<V" does not allow the pre-releases of V, unless V is a pre-release itself
+		if release, ok := pythonPreRelease(version); ok {
+			if _, pre := pythonPreRelease(c.version); !pre && semantic.MustParse(release, "PyPI").CompareStr(c.version) == 0 {
+				return false
+			}
+		}
+
+		return cmp < 0
+	}
+
+	return false
+}
+
+// pythonSpecifier is a set of alternatives, at least one of which a version
+// needs to satisfy all the constraints of in order to satisfy the specifier
+type pythonSpecifier [][]pythonConstraint
+
+func (s pythonSpecifier) satisfiedBy(version string) bool {
+	for _, constraints := range s {
+		satisfied := true
+
+		for _, c := range constraints {
+			if !c.satisfiedBy(version) {
+				satisfied = false
+
+				break
+			}
+		}
+
+		if satisfied {
+			return true
+		}
+	}
+
+	return false
+}
+
+// pinned returns the version that the specifier pins to, if it only allows one version
+func (s pythonSpecifier) pinned() (string, bool) {
+	if len(s) != 1 || len(s[0]) != 1 {
+		return "", false
+	}
+
+	c := s[0][0]
+
+	if (c.op != "==" && c.op != "===") || isPythonWildcard(c.version) {
+		return "", false
+	}
+
+	return c.version, true
+}
+
+// bumpPythonVersion returns the version that is one more than the release segment at
+// index i of version, which is used as the upper bound of compatible releases
+func bumpPythonVersion(version string, i int) string {
+	parts := strings.Split(version, ".")
+
+	for len(parts) <= i {
+		parts = append(parts, "0")
+	}
+
+	parts = parts[:i+1]
+
+	var n int
+	_, _ = fmt.Sscanf(parts[i], "%d", &n)
+	parts[i] = fmt.Sprint(n + 1)
+
+	return strings.Join(parts, ".")
+}
+
+// parsePythonConstraint parses a clause of a specifier, expanding the operators
+// that don't have a direct comparison into the constraints that they are made of
+func parsePythonConstraint(op, version string) ([]pythonConstraint, bool) {
+	release := strings.Split(version, ".")
+
+	switch op {
+	// compatible release, e.g. "~=1.4.5" is ">=1.4.5, ==1.4.*"
+	case "~=":
+		if len(release) < 2 {
+			return nil, false
+		}
+
+		return []pythonConstraint{
+			{">=", version},
+			{"==", strings.Join(release[:len(release)-1], ".") + ".*"},
+		}, true
+	// poetry's caret requirement, e.g. "^1.2.3" is ">=1.2.3, <2.0.0"
+	case "^":
+		i := 0
+		for i < len(release)-1 && release[i] == "0" {
+			i++
+		}
+
+		return []pythonConstraint{{">=", version}, {"<", bumpPythonVersion(version, i)}}, true
+	// poetry's tilde requirement, e.g. "~1.2.3" is ">=1.2.3, <1.3.0"
+	case "~":
+		i := min(1, len(release)-1)
+
+		return []pythonConstraint{{">=", version}, {"<", bumpPythonVersion(version, i)}}, true
+	// poetry uses a version without an operator to require that version exactly
+	case "", "=":
+		if version == "*" {
+			return []pythonConstraint{}, true
+		}
+
+		return []pythonConstraint{{"==", version}}, true
+	case "==", "===", "!=", ">=", "<=", ">", "<":
+		return []pythonConstraint{{op, version}}, true
+	}
+
+	return nil, false
+}
+
+// parsePythonSpecifier parses a version specifier, which can use the syntax of both
+// PEP 440 and poetry, e.g. ">=1.2,<2" or "^1.2 || ^2.0"
+func parsePythonSpecifier(specifier string) (pythonSpecifier, bool) {
+	re := cachedregexp.MustCompile(`^(~=|===|==|!=|<=|>=|<|>|\^|~|=)?\s*([\w.*+!-]+)$`)
+
+	var spec pythonSpecifier
+
+	for _, alternative := range strings.Split(specifier, "||") {
+		constraints := []pythonConstraint{}
+
+		for _, clause := range strings.FieldsFunc(alternative, func(r rune) bool { return r == ',' }) {
+			clause = strings.TrimSpace(clause)
+
+			match := re.FindStringSubmatch(clause)
+
+			if match == nil {
+				return nil, false
+			}
+
+			cs, ok := parsePythonConstraint(match[1], match[2])
+
+			if !ok {
+				return nil, false
+			}
+
+			constraints = append(constraints, cs...)
+		}
+
+		spec = append(spec, constraints)
+	}
+
+	return spec, true
+}
+
+// parsePEP508Requirement parses the name and specifier of a requirement like
+// "requests[security] >=2.8.1, <3 ; python_version < '3.8'"
+func parsePEP508Requirement(requirement string) (string, string, bool) {
+	re := cachedregexp.MustCompile(`^([A-Za-z0-9][\w.-]*)\s*(?:\[[^\]]*\])?\s*\(?([^()]*)\)?$`)
+
+	requirement, _, _ = strings.Cut(requirement, ";")
+
+	// requirements on a url, like "pip @ https://github.com/pypa/pip/archive/22.0.2.zip",
+	// don't have a version that can be known
+	if strings.Contains(requirement, "@") {
+		return "", "", false
+	}
+
+	match := re.FindStringSubmatch(strings.TrimSpace(requirement))
+
+	if match == nil {
+		return "", "", false
+	}
+
+	return match[1], strings.TrimSpace(match[2]), true
+}
+
+// parsePoetryDependency parses the specifier of a dependency in a poetry section, which
+// can either be a string, a table, or a list of tables with different markers
+func parsePoetryDependency(dependency any) (string, bool, bool) {
+	switch dep := dependency.(type) {
+	case string:
+		return dep, false, true
+	case map[string]any:
+		// dependencies on a git repository, path, or url don't have a version that can be known
+		version, ok := dep["version"].(string)
+		optional, _ := dep["optional"].(bool)
+
+		return version, optional, ok
+	case []any:
+		versions := make([]string, 0, len(dep))
+		optional := true
+
+		for _, d := range dep {
+			version, opt, ok := parsePoetryDependency(d)
+
+			if !ok {
+				return "", false, false
+			}
+
+			versions = append(versions, version)
+			optional = optional && opt
+		}
+
+		return strings.Join(versions, " || "), optional, len(versions) > 0
+	}
+
+	return "", false, false
+}
+
+// resolvePythonSpecifier returns the best version that satisfies the specifier
+func resolvePythonSpecifier(spec pythonSpecifier, versions []string) string {
+	return highestVersion("PyPI", versions, spec.satisfiedBy, func(version string) bool {
+		_, ok := pythonPreRelease(version)
+
+		return ok
+	})
+}
+
+// PyPIVersionsFetcher fetches the versions of a package that have been published to PyPI,
+// returning no versions if the package does not exist
+type PyPIVersionsFetcher func(name string) ([]string, error)
+
+// pyprojectLockfiles are the lockfiles of tools that use pyproject.toml, which
+// are extracted instead of the pyproject.toml if they are present
+var pyprojectLockfiles = []string{"pdm.lock", "poetry.lock", "uv.lock"}
+
+// PyprojectTomlExtractor extracts the direct dependencies of a pyproject.toml from
+// the standard [project] table, the sections of poetry, pdm, and uv, and dependency groups.
+//
+// Only the versions of dependencies that are pinned to an exact version are known, unless
+// FetchVersions is set to resolve their specifiers to the best version published to PyPI.
+type PyprojectTomlExtractor struct {
+	FetchVersions PyPIVersionsFetcher
+}
+
+func (e PyprojectTomlExtractor) ShouldExtract(path string) bool {
+	return filepath.Base(path) == "pyproject.toml"
+}
+
+// resolve returns the version that the specifier of the dependency resolves to,
+// or an empty string if it can't be resolved
+func (e PyprojectTomlExtractor) resolve(name string, specifier string) (string, error) {
+	spec, ok := parsePythonSpecifier(specifier)
+
+	if !ok {
+		return "", nil
+	}
+
+	if version, ok := spec.pinned(); ok {
+		return version, nil
+	}
+
+	if e.FetchVersions == nil {
+		return "", nil
+	}
+
+	versions, err := e.FetchVersions(name)
+
+	if err != nil {
+		return "", fmt.Errorf("could not fetch the versions of %s: %w", name, err)
+	}
+
+	return resolvePythonSpecifier(spec, versions), nil
+}
+
+func (e PyprojectTomlExtractor) Extract(f DepFile) ([]PackageDetails, error) {
+	for _, lockfile := range pyprojectLockfiles {
+		if lock, err := f.Open(lockfile); err == nil {
+			_ = lock.Close()
+
+			return []PackageDetails{}, nil
+		}
+	}
+
+	var parsedPyproject *PyprojectToml
+
+	_, err := toml.NewDecoder(f).Decode(&parsedPyproject)
+
+	if err != nil {
+		return []PackageDetails{}, fmt.Errorf("could not extract from %s: %w", f.Path(), err)
+	}
+
+	details := map[string]PackageDetails{}
+
+	addPackage := func(name string, specifier string, group string) error {
+		name = normalizedRequirementName(name)
+		version, err := e.resolve(name, specifier)
+
+		if err != nil {
+			return fmt.Errorf("could not extract from %s: %w", f.Path(), err)
+		}
+
+		key := name + "@" + version
+		existing, exists := details[key]
+
+		// dependencies that are always required take precedence over those in a group
+		if exists && len(existing.DepGroups) == 0 {
+			return nil
+		}
+
+		pkg := PackageDetails{
+			Name:      name,
+			Version:   version,
+			Ecosystem: PyprojectTomlEcosystem,
+			CompareAs: PyprojectTomlEcosystem,
+		}
+
+		if group != "" {
+			pkg.DepGroups = append(pkg.DepGroups, group)
+		}
+
+		details[key] = pkg
+
+		return nil
+	}
+
+	addRequirements := func(requirements []string, group string) error {
+		for _, requirement := range requirements {
+			if name, specifier, ok := parsePEP508Requirement(requirement); ok {
+				if err := addPackage(name, specifier, group); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	}
+
+	addPoetryDependencies := func(dependencies map[string]any, group string) error {
+		for name, dependency := range dependencies {
+			// this is the version of python that the project supports
+			if name == "python" {
+				continue
+			}
+
+			if specifier, optional, ok := parsePoetryDependency(dependency); ok {
+				g := group
+				if optional && g == "" {
+					g = "optional"
+				}
+
+				if err := addPackage(name, specifier, g); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	}
+
+	errs := []error{
+		addRequirements(parsedPyproject.Project.Dependencies, ""),
+		addPoetryDependencies(parsedPyproject.Tool.Poetry.Dependencies, ""),
+		addRequirements(parsedPyproject.Tool.Uv.DevDependencies, "dev"),
+		addPoetryDependencies(parsedPyproject.Tool.Poetry.DevDependencies, "dev"),
+	}
+
+	for _, requirements := range parsedPyproject.Project.OptionalDependencies {
+		errs = append(errs, addRequirements(requirements, "optional"))
+	}
+
+	for _, group := range parsedPyproject.Tool.Poetry.Groups {
+		errs = append(errs, addPoetryDependencies(group.Dependencies, "dev"))
+	}
+
+	for _, requirements := range parsedPyproject.Tool.Pdm.DevDependencies {
+		errs = append(errs, addRequirements(requirements, "dev"))
+	}
+
+	for _, entries := range parsedPyproject.DependencyGroups {
+		requirements := make([]string, 0, len(entries))
+
+		for _, entry := range entries {
+			if requirement, ok := entry.(string); ok {
+				requirements = append(requirements, requirement)
+			}
+		}
+
+		errs = append(errs, addRequirements(requirements, "dev"))
+	}
+
+	for _, err := range errs {
+		if err != nil {
+			return []PackageDetails{}, err
+		}
+	}
+
+	return pkgDetailsMapToSlice(details), nil
+}
+
+var _ Extractor = PyprojectTomlExtractor{}
+
+//nolint:gochecknoinits
+func init() {
+	registerExtractor("pyproject.toml", PyprojectTomlExtractor{})
+}
+
+func ParsePyprojectToml(pathToLockfile string) ([]PackageDetails, error) {
+	return extractFromFile(pathToLockfile, PyprojectTomlExtractor{})
+}
@@ -0,0 +1,454 @@
+package lockfile_test
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+
+	"github.com/google/osv-scanner/pkg/lockfile"
+)
+
+func TestPyprojectTomlExtractor_ShouldExtract(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{
+			name: "",
+			path: "",
+			want: false,
+		},
+		{
+			name: "",
+			path: "pyproject.toml",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/my/pyproject.toml",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/my/pyproject.toml/file",
+			want: false,
+		},
+		{
+			name: "",
+			path: "path/to/my/pyproject.toml.file",
+			want: false,
+		},
+		{
+			name: "",
+			path: "path.to.my.pyproject.toml",
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e := lockfile.PyprojectTomlExtractor{}
+			got := e.ShouldExtract(tt.path)
+			if got != tt.want {
+				t.Errorf("Extract() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// extractPyprojectWithVersions extracts the pyproject.toml at path, using
+// versions as the versions that have been published to PyPI
+func extractPyprojectWithVersions(t *testing.T, path string, versions map[string][]string) ([]lockfile.PackageDetails, error) {
+	t.Helper()
+
+	f, err := lockfile.OpenLocalDepFile(path)
+	if err != nil {
+		t.Fatalf("could not open %s: %v", path, err)
+	}
+	defer f.Close()
+
+	return lockfile.PyprojectTomlExtractor{
+		FetchVersions: func(name string) ([]string, error) {
+			return versions[name], nil
+		},
+	}.Extract(f)
+}
+
+func TestParsePyprojectToml_FileDoesNotExist(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParsePyprojectToml("fixtures/pyproject/does-not-exist")
+
+	expectErrIs(t, err, fs.ErrNotExist)
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParsePyprojectToml_InvalidToml(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParsePyprojectToml("fixtures/pyproject/not-toml.txt")
+
+	expectErrContaining(t, err, "could not extract from")
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParsePyprojectToml_NoDependencies(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParsePyprojectToml("fixtures/pyproject/no-dependencies.toml")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParsePyprojectToml_Project(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParsePyprojectToml("fixtures/pyproject/pep621.toml")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "requests",
+			Version:   "",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+		},
+		{
+			Name:      "django",
+			Version:   "4.2.7",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+		},
+		{
+			Name:      "urllib3",
+			Version:   "2.0.7",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+		},
+		{
+			Name:      "click",
+			Version:   "",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+		},
+		{
+			Name:      "six",
+			Version:   "",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+		},
+		{
+			Name:      "pytest",
+			Version:   "7.4.3",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+			DepGroups: []string{"optional"},
+		},
+	})
+}
+
+func TestParsePyprojectToml_Poetry(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParsePyprojectToml("fixtures/pyproject/poetry.toml")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "requests",
+			Version:   "",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+		},
+		{
+			Name:      "flask",
+			Version:   "2.3.3",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+		},
+		{
+			Name:      "numpy",
+			Version:   "1.26.2",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+			DepGroups: []string{"optional"},
+		},
+		{
+			Name:      "pandas",
+			Version:   "",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+		},
+		{
+			Name:      "black",
+			Version:   "23.11.0",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+			DepGroups: []string{"dev"},
+		},
+		{
+			Name:      "pytest",
+			Version:   "",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+			DepGroups: []string{"dev"},
+		},
+	})
+}
+
+func TestParsePyprojectToml_Groups(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParsePyprojectToml("fixtures/pyproject/groups.toml")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "ruff",
+			Version:   "0.1.6",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+			DepGroups: []string{"dev"},
+		},
+		{
+			Name:      "mypy",
+			Version:   "1.7.1",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+			DepGroups: []string{"dev"},
+		},
+		{
+			Name:      "pytest",
+			Version:   "7.4.3",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+			DepGroups: []string{"dev"},
+		},
+	})
+}
+
+func TestParsePyprojectToml_WithLockfile(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParsePyprojectToml("fixtures/pyproject/with-lock/pyproject.toml")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestPyprojectTomlExtractor_Extract_ResolvesProject(t *testing.T) {
+	t.Parallel()
+
+	packages, err := extractPyprojectWithVersions(
+		t,
+		"fixtures/pyproject/pep621.toml",
+		map[string][]string{
+			"requests": {"2.27.1", "2.28.0", "2.31.0", "3.0.0b1"},
+			"click":    {"8.0.4", "8.1.0", "8.1.7", "8.2.0"},
+			"six":      {"1.16.0", "2.0.0rc1"},
+		},
+	)
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "requests",
+			Version:   "2.31.0",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+		},
+		{
+			Name:      "django",
+			Version:   "4.2.7",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+		},
+		{
+			Name:      "urllib3",
+			Version:   "2.0.7",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+		},
+		{
+			Name:      "click",
+			Version:   "8.1.7",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+		},
+		{
+			Name:      "six",
+			Version:   "1.16.0",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+		},
+		{
+			Name:      "pytest",
+			Version:   "7.4.3",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+			DepGroups: []string{"optional"},
+		},
+	})
+}
+
+func TestPyprojectTomlExtractor_Extract_ResolvesPoetry(t *testing.T) {
+	t.Parallel()
+
+	packages, err := extractPyprojectWithVersions(
+		t,
+		"fixtures/pyproject/poetry.toml",
+		map[string][]string{
+			"requests": {"2.27.1", "2.28.0", "2.31.0", "3.0.0"},
+			"pandas":   {"1.5.3", "2.1.3", "2.2.0rc0"},
+			"pytest":   {"7.3.2", "7.4.3", "8.0.0"},
+		},
+	)
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "requests",
+			Version:   "2.31.0",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+		},
+		{
+			Name:      "flask",
+			Version:   "2.3.3",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+		},
+		{
+			Name:      "numpy",
+			Version:   "1.26.2",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+			DepGroups: []string{"optional"},
+		},
+		{
+			Name:      "pandas",
+			Version:   "2.1.3",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+		},
+		{
+			Name:      "black",
+			Version:   "23.11.0",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+			DepGroups: []string{"dev"},
+		},
+		{
+			Name:      "pytest",
+			Version:   "7.4.3",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+			DepGroups: []string{"dev"},
+		},
+	})
+}
+
+func TestPyprojectTomlExtractor_Extract_ResolvesPreReleases(t *testing.T) {
+	t.Parallel()
+
+	packages, err := extractPyprojectWithVersions(
+		t,
+		"fixtures/pyproject/pep621.toml",
+		map[string][]string{
+			"requests": {"2.27.1", "3.0.0b1"},
+			"six":      {"2.0.0rc1"},
+		},
+	)
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "requests",
+			Version:   "",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+		},
+		{
+			Name:      "django",
+			Version:   "4.2.7",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+		},
+		{
+			Name:      "urllib3",
+			Version:   "2.0.7",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+		},
+		{
+			Name:      "click",
+			Version:   "",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+		},
+		{
+			Name:      "six",
+			Version:   "2.0.0rc1",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+		},
+		{
+			Name:      "pytest",
+			Version:   "7.4.3",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+			DepGroups: []string{"optional"},
+		},
+	})
+}
+
+func TestPyprojectTomlExtractor_Extract_FetchError(t *testing.T) {
+	t.Parallel()
+
+	f, err := lockfile.OpenLocalDepFile("fixtures/pyproject/pep621.toml")
+	if err != nil {
+		t.Fatalf("could not open fixture: %v", err)
+	}
+	defer f.Close()
+
+	errFetch := errors.New("500 Internal Server Error")
+	packages, err := lockfile.PyprojectTomlExtractor{
+		FetchVersions: func(string) ([]string, error) {
+			return nil, errFetch
+		},
+	}.Extract(f)
+
+	expectErrIs(t, err, errFetch)
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
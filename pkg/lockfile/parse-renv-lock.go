@@ -9,6 +9,7 @@ import (
 type RenvPackage struct {
 	Package    string `json:"Package"`
 	Version    string `json:"Version"`
+	Source     string `json:"Source"`
 	Repository string `json:"Repository"`
 }
 
@@ -17,6 +18,21 @@ type RenvLockfile struct {
 }
 
 const CRANEcosystem Ecosystem = "CRAN"
+const BioconductorEcosystem Ecosystem = "Bioconductor"
+
+// renvPackageEcosystem determines the ecosystem a package in a renv.lock
+// was sourced from, returning an empty string if it's not one we support
+func renvPackageEcosystem(pkg RenvPackage) Ecosystem {
+	if pkg.Source == string(BioconductorEcosystem) {
+		return BioconductorEcosystem
+	}
+
+	if pkg.Repository == string(CRANEcosystem) {
+		return CRANEcosystem
+	}
+
+	return ""
+}
 
 type RenvLockExtractor struct{}
 
@@ -36,16 +52,18 @@ func (e RenvLockExtractor) Extract(f DepFile) ([]PackageDetails, error) {
 	packages := make([]PackageDetails, 0, len(parsedLockfile.Packages))
 
 	for _, pkg := range parsedLockfile.Packages {
-		// currently we only support CRAN
-		if pkg.Repository != string(CRANEcosystem) {
+		ecosystem := renvPackageEcosystem(pkg)
+
+		// currently we only support CRAN and Bioconductor
+		if ecosystem == "" {
 			continue
 		}
 
 		packages = append(packages, PackageDetails{
 			Name:      pkg.Package,
 			Version:   pkg.Version,
-			Ecosystem: CRANEcosystem,
-			CompareAs: CRANEcosystem,
+			Ecosystem: ecosystem,
+			CompareAs: ecosystem,
 		})
 	}
 
@@ -109,7 +109,6 @@ func TestParseRenvLock_WithBioconductor(t *testing.T) {
 		t.Errorf("Got unexpected error: %v", err)
 	}
 
-	// currently Bioconductor is not supported
 	expectPackages(t, packages, []lockfile.PackageDetails{
 		{
 			Name:      "BH",
@@ -117,6 +116,12 @@ func TestParseRenvLock_WithBioconductor(t *testing.T) {
 			Ecosystem: lockfile.CRANEcosystem,
 			CompareAs: lockfile.CRANEcosystem,
 		},
+		{
+			Name:      "BSgenome",
+			Version:   "1.60.0",
+			Ecosystem: lockfile.BioconductorEcosystem,
+			CompareAs: lockfile.BioconductorEcosystem,
+		},
 	})
 }
 
@@ -0,0 +1,103 @@
+package lockfile
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+type StackLockCompleted struct {
+	Hackage string `yaml:"hackage"`
+	// git and archive packages are described by their name and version
+	Name    string `yaml:"name"`
+	Version string `yaml:"version"`
+	Commit  string `yaml:"commit"`
+}
+
+type StackLockPackage struct {
+	Completed StackLockCompleted `yaml:"completed"`
+}
+
+type StackLockFile struct {
+	Packages []StackLockPackage `yaml:"packages"`
+}
+
+// parseStackHackageIdentifier parses the name and version out of an identifier like
+// "aeson-2.1.2.1@sha256:<hash>,<size>", where the name can contain hyphens too
+func parseStackHackageIdentifier(identifier string) (string, string, bool) {
+	identifier, _, _ = strings.Cut(identifier, "@")
+
+	i := strings.LastIndex(identifier, "-")
+	if i <= 0 {
+		return "", "", false
+	}
+
+	return identifier[:i], identifier[i+1:], true
+}
+
+type StackLockExtractor struct{}
+
+func (e StackLockExtractor) ShouldExtract(path string) bool {
+	return filepath.Base(path) == "stack.yaml.lock"
+}
+
+// Extract extracts the extra-deps locked in a stack.yaml.lock; the packages that
+// come from the resolver's snapshot are not listed in the lockfile
+func (e StackLockExtractor) Extract(f DepFile) ([]PackageDetails, error) {
+	var parsedLockfile *StackLockFile
+
+	err := yaml.NewDecoder(f).Decode(&parsedLockfile)
+
+	if err != nil && !errors.Is(err, io.EOF) {
+		return []PackageDetails{}, fmt.Errorf("could not extract from %s: %w", f.Path(), err)
+	}
+
+	// this will happen if the file is empty
+	if parsedLockfile == nil {
+		return []PackageDetails{}, nil
+	}
+
+	packages := make([]PackageDetails, 0, len(parsedLockfile.Packages))
+
+	for _, pkg := range parsedLockfile.Packages {
+		name, version := pkg.Completed.Name, pkg.Completed.Version
+
+		if pkg.Completed.Hackage != "" {
+			var ok bool
+			name, version, ok = parseStackHackageIdentifier(pkg.Completed.Hackage)
+
+			if !ok {
+				continue
+			}
+		}
+
+		if name == "" {
+			continue
+		}
+
+		packages = append(packages, PackageDetails{
+			Name:      name,
+			Version:   version,
+			Commit:    pkg.Completed.Commit,
+			Ecosystem: HackageEcosystem,
+			CompareAs: HackageEcosystem,
+		})
+	}
+
+	return packages, nil
+}
+
+var _ Extractor = StackLockExtractor{}
+
+//nolint:gochecknoinits
+func init() {
+	registerExtractor("stack.yaml.lock", StackLockExtractor{})
+}
+
+func ParseStackLock(pathToLockfile string) ([]PackageDetails, error) {
+	return extractFromFile(pathToLockfile, StackLockExtractor{})
+}
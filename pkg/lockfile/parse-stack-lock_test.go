@@ -0,0 +1,155 @@
+package lockfile_test
+
+import (
+	"io/fs"
+	"testing"
+
+	"github.com/google/osv-scanner/pkg/lockfile"
+)
+
+func TestStackLockExtractor_ShouldExtract(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{
+			name: "",
+			path: "",
+			want: false,
+		},
+		{
+			name: "",
+			path: "stack.yaml.lock",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/my/stack.yaml.lock",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/my/stack.yaml.lock/file",
+			want: false,
+		},
+		{
+			name: "",
+			path: "path/to/my/stack.yaml.lock.file",
+			want: false,
+		},
+		{
+			name: "",
+			path: "path.to.my.stack.yaml.lock",
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e := lockfile.StackLockExtractor{}
+			got := e.ShouldExtract(tt.path)
+			if got != tt.want {
+				t.Errorf("Extract() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseStackLock_FileDoesNotExist(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseStackLock("fixtures/stack/does-not-exist")
+
+	expectErrIs(t, err, fs.ErrNotExist)
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseStackLock_InvalidYaml(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseStackLock("fixtures/stack/not-yaml.txt")
+
+	expectErrContaining(t, err, "could not extract from")
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseStackLock_EmptyFile(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseStackLock("fixtures/stack/empty-file.yaml.lock")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseStackLock_NoPackages(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseStackLock("fixtures/stack/empty.yaml.lock")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseStackLock_OnePackage(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseStackLock("fixtures/stack/one-package.yaml.lock")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "acme-missiles",
+			Version:   "0.3",
+			Ecosystem: lockfile.HackageEcosystem,
+			CompareAs: lockfile.HackageEcosystem,
+		},
+	})
+}
+
+func TestParseStackLock_MultiplePackages(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseStackLock("fixtures/stack/multiple-packages.yaml.lock")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "acme-missiles",
+			Version:   "0.3",
+			Ecosystem: lockfile.HackageEcosystem,
+			CompareAs: lockfile.HackageEcosystem,
+		},
+		{
+			Name:      "unordered-containers",
+			Version:   "0.2.19.1",
+			Ecosystem: lockfile.HackageEcosystem,
+			CompareAs: lockfile.HackageEcosystem,
+		},
+		{
+			Name:      "network",
+			Version:   "3.1.4.0",
+			Ecosystem: lockfile.HackageEcosystem,
+			CompareAs: lockfile.HackageEcosystem,
+			Commit:    "0dc2a71c4a6f4b11c2e8ec3ccf5ef8ea9c1bbd9f",
+		},
+	})
+}
@@ -0,0 +1,77 @@
+package lockfile
+
+import (
+	"bufio"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/osv-scanner/internal/cachedregexp"
+)
+
+const TerraformEcosystem Ecosystem = "Terraform"
+
+type TerraformLockExtractor struct{}
+
+func (e TerraformLockExtractor) ShouldExtract(path string) bool {
+	return filepath.Base(path) == ".terraform.lock.hcl"
+}
+
+// Extract extracts the providers from a .terraform.lock.hcl, which Terraform generates
+// with one top-level "provider" block per provider, so it's parsed a line at a time
+// rather than as full HCL
+func (e TerraformLockExtractor) Extract(f DepFile) ([]PackageDetails, error) {
+	providerRe := cachedregexp.MustCompile(`^provider\s+"([^"]+)"\s*\{$`)
+	versionRe := cachedregexp.MustCompile(`^version\s*=\s*"([^"]+)"$`)
+
+	scanner := bufio.NewScanner(f)
+
+	var packages []PackageDetails
+	var provider string
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if match := providerRe.FindStringSubmatch(line); match != nil {
+			provider = match[1]
+
+			continue
+		}
+
+		if line == "}" {
+			provider = ""
+
+			continue
+		}
+
+		if provider == "" {
+			continue
+		}
+
+		if match := versionRe.FindStringSubmatch(line); match != nil {
+			packages = append(packages, PackageDetails{
+				Name:      provider,
+				Version:   match[1],
+				Ecosystem: TerraformEcosystem,
+				CompareAs: TerraformEcosystem,
+			})
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return []PackageDetails{}, fmt.Errorf("error while scanning %s: %w", f.Path(), err)
+	}
+
+	return packages, nil
+}
+
+var _ Extractor = TerraformLockExtractor{}
+
+//nolint:gochecknoinits
+func init() {
+	registerExtractor(".terraform.lock.hcl", TerraformLockExtractor{})
+}
+
+func ParseTerraformLock(pathToLockfile string) ([]PackageDetails, error) {
+	return extractFromFile(pathToLockfile, TerraformLockExtractor{})
+}
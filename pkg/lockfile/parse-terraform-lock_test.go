@@ -0,0 +1,144 @@
+package lockfile_test
+
+import (
+	"io/fs"
+	"testing"
+
+	"github.com/google/osv-scanner/pkg/lockfile"
+)
+
+func TestTerraformLockExtractor_ShouldExtract(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{
+			name: "",
+			path: "",
+			want: false,
+		},
+		{
+			name: "",
+			path: ".terraform.lock.hcl",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/my/.terraform.lock.hcl",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/my/.terraform.lock.hcl/file",
+			want: false,
+		},
+		{
+			name: "",
+			path: "path/to/my/.terraform.lock.hcl.file",
+			want: false,
+		},
+		{
+			name: "",
+			path: "path.to.my..terraform.lock.hcl",
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e := lockfile.TerraformLockExtractor{}
+			got := e.ShouldExtract(tt.path)
+			if got != tt.want {
+				t.Errorf("Extract() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTerraformLock_FileDoesNotExist(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseTerraformLock("fixtures/terraform/does-not-exist")
+
+	expectErrIs(t, err, fs.ErrNotExist)
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseTerraformLock_NotALockfile(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseTerraformLock("fixtures/terraform/not-hcl.txt")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseTerraformLock_NoProviders(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseTerraformLock("fixtures/terraform/empty.hcl")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseTerraformLock_OneProvider(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseTerraformLock("fixtures/terraform/one-provider.hcl")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "registry.terraform.io/hashicorp/aws",
+			Version:   "5.31.0",
+			Ecosystem: lockfile.TerraformEcosystem,
+			CompareAs: lockfile.TerraformEcosystem,
+		},
+	})
+}
+
+func TestParseTerraformLock_MultipleProviders(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseTerraformLock("fixtures/terraform/multiple-providers.hcl")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "registry.terraform.io/hashicorp/aws",
+			Version:   "5.31.0",
+			Ecosystem: lockfile.TerraformEcosystem,
+			CompareAs: lockfile.TerraformEcosystem,
+		},
+		{
+			Name:      "registry.terraform.io/hashicorp/random",
+			Version:   "3.6.0",
+			Ecosystem: lockfile.TerraformEcosystem,
+			CompareAs: lockfile.TerraformEcosystem,
+		},
+		{
+			Name:      "registry.opentofu.org/integrations/github",
+			Version:   "5.42.0",
+			Ecosystem: lockfile.TerraformEcosystem,
+			CompareAs: lockfile.TerraformEcosystem,
+		},
+	})
+}
@@ -0,0 +1,195 @@
+package lockfile
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+type UvLockDependency struct {
+	Name    string   `toml:"name"`
+	Version string   `toml:"version"`
+	Extra   []string `toml:"extra"`
+}
+
+type UvLockPackageSource struct {
+	Git      string `toml:"git"`
+	Editable string `toml:"editable"`
+	Virtual  string `toml:"virtual"`
+}
+
+type UvLockPackage struct {
+	Name                 string                        `toml:"name"`
+	Version              string                        `toml:"version"`
+	Source               UvLockPackageSource           `toml:"source"`
+	Dependencies         []UvLockDependency            `toml:"dependencies"`
+	OptionalDependencies map[string][]UvLockDependency `toml:"optional-dependencies"`
+	DevDependencies      map[string][]UvLockDependency `toml:"dev-dependencies"`
+}
+
+// isProject reports if the package is the project itself, or a member of its workspace
+func (pkg UvLockPackage) isProject() bool {
+	return pkg.Source.Editable != "" || pkg.Source.Virtual != ""
+}
+
+// commit returns the commit that a package from git was locked to,
+// which uv records as the fragment of the url
+func (pkg UvLockPackage) commit() string {
+	_, commit, _ := strings.Cut(pkg.Source.Git, "#")
+
+	return commit
+}
+
+type UvLockFile struct {
+	Version  int             `toml:"version"`
+	Packages []UvLockPackage `toml:"package"`
+}
+
+const UvEcosystem = PipEcosystem
+
+// uvLockGroups determines which groups each package in the lockfile belongs to by walking the
+// dependencies of the project, as uv does not record this on the packages themselves; packages
+// that are required by the project regardless of which groups are installed have no groups.
+//
+// The same package can be locked at different versions depending on markers such as the
+// version of Python, in which case all of those versions are considered to be required
+func uvLockGroups(packages []UvLockPackage) map[int][]string {
+	byName := make(map[string][]int)
+
+	for i, pkg := range packages {
+		byName[pkg.Name] = append(byName[pkg.Name], i)
+	}
+
+	required := make(map[int]bool)
+	groups := make(map[int]map[string]bool)
+	extras := make(map[string]bool)
+
+	var visit func(dep UvLockDependency, group string)
+	visit = func(dep UvLockDependency, group string) {
+		for _, i := range byName[dep.Name] {
+			pkg := packages[i]
+
+			if dep.Version != "" && dep.Version != pkg.Version {
+				continue
+			}
+
+			if !required[i] && !groups[i][group] {
+				if group == "" {
+					required[i] = true
+				} else {
+					if groups[i] == nil {
+						groups[i] = make(map[string]bool)
+					}
+					groups[i][group] = true
+				}
+
+				for _, d := range pkg.Dependencies {
+					visit(d, group)
+				}
+			}
+
+			// the package might have already been visited without these extras
+			for _, extra := range dep.Extra {
+				key := fmt.Sprintf("%d/%s/%s", i, group, extra)
+
+				if extras[key] {
+					continue
+				}
+
+				extras[key] = true
+
+				for _, d := range pkg.OptionalDependencies[extra] {
+					visit(d, group)
+				}
+			}
+		}
+	}
+
+	for _, pkg := range packages {
+		if !pkg.isProject() {
+			continue
+		}
+
+		for _, d := range pkg.Dependencies {
+			visit(d, "")
+		}
+
+		for _, deps := range pkg.OptionalDependencies {
+			for _, d := range deps {
+				visit(d, "optional")
+			}
+		}
+
+		for _, deps := range pkg.DevDependencies {
+			for _, d := range deps {
+				visit(d, "dev")
+			}
+		}
+	}
+
+	depGroups := make(map[int][]string)
+
+	for i, gs := range groups {
+		if required[i] {
+			continue
+		}
+
+		for _, group := range []string{"dev", "optional"} {
+			if gs[group] {
+				depGroups[i] = append(depGroups[i], group)
+			}
+		}
+	}
+
+	return depGroups
+}
+
+type UvLockExtractor struct{}
+
+func (e UvLockExtractor) ShouldExtract(path string) bool {
+	return filepath.Base(path) == "uv.lock"
+}
+
+func (e UvLockExtractor) Extract(f DepFile) ([]PackageDetails, error) {
+	var parsedLockfile *UvLockFile
+
+	_, err := toml.NewDecoder(f).Decode(&parsedLockfile)
+
+	if err != nil {
+		return []PackageDetails{}, fmt.Errorf("could not extract from %s: %w", f.Path(), err)
+	}
+
+	groups := uvLockGroups(parsedLockfile.Packages)
+
+	packages := make([]PackageDetails, 0, len(parsedLockfile.Packages))
+
+	for i, pkg := range parsedLockfile.Packages {
+		if pkg.isProject() {
+			continue
+		}
+
+		packages = append(packages, PackageDetails{
+			Name:      pkg.Name,
+			Version:   pkg.Version,
+			Commit:    pkg.commit(),
+			Ecosystem: UvEcosystem,
+			CompareAs: UvEcosystem,
+			DepGroups: groups[i],
+		})
+	}
+
+	return packages, nil
+}
+
+var _ Extractor = UvLockExtractor{}
+
+//nolint:gochecknoinits
+func init() {
+	registerExtractor("uv.lock", UvLockExtractor{})
+}
+
+func ParseUvLock(pathToLockfile string) ([]PackageDetails, error) {
+	return extractFromFile(pathToLockfile, UvLockExtractor{})
+}
@@ -0,0 +1,195 @@
+package lockfile_test
+
+import (
+	"io/fs"
+	"testing"
+
+	"github.com/google/osv-scanner/pkg/lockfile"
+)
+
+func TestUvLockExtractor_ShouldExtract(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{
+			name: "",
+			path: "",
+			want: false,
+		},
+		{
+			name: "",
+			path: "uv.lock",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/my/uv.lock",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/my/uv.lock/file",
+			want: false,
+		},
+		{
+			name: "",
+			path: "path/to/my/uv.lock.file",
+			want: false,
+		},
+		{
+			name: "",
+			path: "path.to.my.uv.lock",
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e := lockfile.UvLockExtractor{}
+			got := e.ShouldExtract(tt.path)
+			if got != tt.want {
+				t.Errorf("Extract() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseUvLock_FileDoesNotExist(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseUvLock("fixtures/uv/does-not-exist")
+
+	expectErrIs(t, err, fs.ErrNotExist)
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseUvLock_InvalidToml(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseUvLock("fixtures/uv/not-toml.txt")
+
+	expectErrContaining(t, err, "could not extract from")
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseUvLock_NoPackages(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseUvLock("fixtures/uv/empty.lock")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseUvLock_OnePackage(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseUvLock("fixtures/uv/one-package.lock")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "idna",
+			Version:   "3.7",
+			Ecosystem: lockfile.UvEcosystem,
+			CompareAs: lockfile.UvEcosystem,
+		},
+	})
+}
+
+func TestParseUvLock_Groups(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseUvLock("fixtures/uv/groups.lock")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "anyio",
+			Version:   "4.4.0",
+			Ecosystem: lockfile.UvEcosystem,
+			CompareAs: lockfile.UvEcosystem,
+		},
+		{
+			Name:      "click",
+			Version:   "8.1.7",
+			Ecosystem: lockfile.UvEcosystem,
+			CompareAs: lockfile.UvEcosystem,
+			DepGroups: []string{"optional"},
+		},
+		{
+			Name:      "exceptiongroup",
+			Version:   "1.2.2",
+			Ecosystem: lockfile.UvEcosystem,
+			CompareAs: lockfile.UvEcosystem,
+		},
+		{
+			Name:      "flask",
+			Version:   "3.0.3",
+			Ecosystem: lockfile.UvEcosystem,
+			CompareAs: lockfile.UvEcosystem,
+			Commit:    "0b3de2f1c9e0a3b5c5d8e7f6a1b2c3d4e5f6a7b8",
+			DepGroups: []string{"optional"},
+		},
+		{
+			Name:      "h2",
+			Version:   "4.1.0",
+			Ecosystem: lockfile.UvEcosystem,
+			CompareAs: lockfile.UvEcosystem,
+			DepGroups: []string{"dev"},
+		},
+		{
+			Name:      "httpx",
+			Version:   "0.27.0",
+			Ecosystem: lockfile.UvEcosystem,
+			CompareAs: lockfile.UvEcosystem,
+		},
+		{
+			Name:      "idna",
+			Version:   "3.7",
+			Ecosystem: lockfile.UvEcosystem,
+			CompareAs: lockfile.UvEcosystem,
+		},
+		{
+			Name:      "numpy",
+			Version:   "2.0.2",
+			Ecosystem: lockfile.UvEcosystem,
+			CompareAs: lockfile.UvEcosystem,
+		},
+		{
+			Name:      "numpy",
+			Version:   "2.1.1",
+			Ecosystem: lockfile.UvEcosystem,
+			CompareAs: lockfile.UvEcosystem,
+		},
+		{
+			Name:      "pytest",
+			Version:   "8.3.2",
+			Ecosystem: lockfile.UvEcosystem,
+			CompareAs: lockfile.UvEcosystem,
+			DepGroups: []string{"dev"},
+		},
+		{
+			Name:      "sniffio",
+			Version:   "1.3.1",
+			Ecosystem: lockfile.UvEcosystem,
+			CompareAs: lockfile.UvEcosystem,
+		},
+	})
+}
@@ -0,0 +1,245 @@
+package lockfile
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/google/osv-scanner/internal/semantic"
+)
+
+// TODO this is tentative and subject to change depending on the OSV schema
+const VcpkgEcosystem Ecosystem = "vcpkg"
+
+// vcpkgRegistryURL is the repository of the builtin vcpkg registry
+const vcpkgRegistryURL = "https://github.com/microsoft/vcpkg"
+
+type VcpkgDependency struct {
+	Name       string `json:"name"`
+	MinVersion string `json:"version>="`
+	Host       bool   `json:"host"`
+}
+
+// UnmarshalJSON handles dependencies being either the name of the port,
+// or an object with the name and other details of the dependency
+func (d *VcpkgDependency) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		*d = VcpkgDependency{Name: name}
+
+		return nil
+	}
+
+	type dependency VcpkgDependency
+
+	return json.Unmarshal(data, (*dependency)(d))
+}
+
+type VcpkgOverride struct {
+	Name          string `json:"name"`
+	Version       string `json:"version"`
+	VersionSemver string `json:"version-semver"`
+	VersionDate   string `json:"version-date"`
+	VersionString string `json:"version-string"`
+}
+
+func (o VcpkgOverride) version() string {
+	for _, v := range []string{o.Version, o.VersionSemver, o.VersionDate, o.VersionString} {
+		if v != "" {
+			return v
+		}
+	}
+
+	return ""
+}
+
+type VcpkgRegistry struct {
+	Kind       string `json:"kind"`
+	Repository string `json:"repository"`
+	Baseline   string `json:"baseline"`
+}
+
+type VcpkgConfiguration struct {
+	DefaultRegistry *VcpkgRegistry `json:"default-registry"`
+}
+
+type VcpkgManifest struct {
+	Dependencies    []VcpkgDependency   `json:"dependencies"`
+	Overrides       []VcpkgOverride     `json:"overrides"`
+	BuiltinBaseline string              `json:"builtin-baseline"`
+	Configuration   *VcpkgConfiguration `json:"vcpkg-configuration"`
+}
+
+// VcpkgBaseline is the baseline version of each port in a registry,
+// as listed in the "default" baseline of its versions/baseline.json
+type VcpkgBaseline map[string]struct {
+	Baseline    string `json:"baseline"`
+	PortVersion int    `json:"port-version"`
+}
+
+// VcpkgBaselineFetcher fetches the baseline of the builtin vcpkg registry at the given commit
+type VcpkgBaselineFetcher func(commit string) (VcpkgBaseline, error)
+
+// VcpkgManifestExtractor extracts the dependencies of a vcpkg.json manifest.
+//
+// Only the versions set in the manifest are known, unless FetchBaseline is set
+// to get the versions that the baseline of the builtin registry resolves to.
+type VcpkgManifestExtractor struct {
+	FetchBaseline VcpkgBaselineFetcher
+}
+
+func (e VcpkgManifestExtractor) ShouldExtract(path string) bool {
+	return filepath.Base(path) == "vcpkg.json"
+}
+
+// readVcpkgJSON decodes the file at path, relative to f, returning false if it does not exist
+func readVcpkgJSON(f DepFile, path string, v any) (bool, error) {
+	file, err := f.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	if err := json.NewDecoder(file).Decode(v); err != nil {
+		return false, fmt.Errorf("could not parse %s: %w", file.Path(), err)
+	}
+
+	return true, nil
+}
+
+// vcpkgBaselineCommit finds the commit of the builtin registry that the manifest uses as its baseline,
+// which is set either in the manifest, in its configuration, or locked in vcpkg-lock.json
+func vcpkgBaselineCommit(f DepFile, manifest VcpkgManifest) (string, error) {
+	if manifest.BuiltinBaseline != "" {
+		return manifest.BuiltinBaseline, nil
+	}
+
+	config := manifest.Configuration
+	if config == nil {
+		config = &VcpkgConfiguration{}
+		if _, err := readVcpkgJSON(f, "vcpkg-configuration.json", config); err != nil {
+			return "", err
+		}
+	}
+
+	if reg := config.DefaultRegistry; reg != nil {
+		builtin := reg.Kind == "builtin" ||
+			(reg.Kind == "git" && strings.TrimSuffix(reg.Repository, ".git") == vcpkgRegistryURL)
+		if !builtin {
+			// the versions in other registries can't be known
+			return "", nil
+		}
+
+		if reg.Baseline != "" {
+			return reg.Baseline, nil
+		}
+	}
+
+	var lock map[string]map[string]string
+	if _, err := readVcpkgJSON(f, "vcpkg-lock.json", &lock); err != nil {
+		return "", err
+	}
+
+	refs := lock[vcpkgRegistryURL]
+	if commit, ok := refs["HEAD"]; ok {
+		return commit, nil
+	}
+
+	keys := make([]string, 0, len(refs))
+	for ref := range refs {
+		keys = append(keys, ref)
+	}
+	sort.Strings(keys)
+
+	if len(keys) > 0 {
+		return refs[keys[0]], nil
+	}
+
+	return "", nil
+}
+
+// vcpkgVersion removes the port version from a vcpkg version, which is only used
+// to version changes to the port itself rather than to the upstream package
+func vcpkgVersion(version string) string {
+	version, _, _ = strings.Cut(version, "#")
+
+	return version
+}
+
+func (e VcpkgManifestExtractor) Extract(f DepFile) ([]PackageDetails, error) {
+	var manifest VcpkgManifest
+
+	err := json.NewDecoder(f).Decode(&manifest)
+	if err != nil {
+		return []PackageDetails{}, fmt.Errorf("could not extract from %s: %w", f.Path(), err)
+	}
+
+	var baseline VcpkgBaseline
+	if e.FetchBaseline != nil {
+		commit, err := vcpkgBaselineCommit(f, manifest)
+		if err != nil {
+			return []PackageDetails{}, fmt.Errorf("could not extract from %s: %w", f.Path(), err)
+		}
+
+		if commit != "" {
+			baseline, err = e.FetchBaseline(commit)
+			if err != nil {
+				return []PackageDetails{}, fmt.Errorf("could not fetch the vcpkg baseline %s: %w", commit, err)
+			}
+		}
+	}
+
+	overrides := make(map[string]string, len(manifest.Overrides))
+	for _, override := range manifest.Overrides {
+		overrides[override.Name] = vcpkgVersion(override.version())
+	}
+
+	packages := make([]PackageDetails, 0, len(manifest.Dependencies))
+
+	for _, dependency := range manifest.Dependencies {
+		version, overridden := overrides[dependency.Name]
+
+		if !overridden {
+			// vcpkg uses the greater of the baseline and minimum versions
+			version = vcpkgVersion(dependency.MinVersion)
+
+			if b, ok := baseline[dependency.Name]; ok {
+				if version == "" || semantic.MustParse(version, "vcpkg").CompareStr(b.Baseline) < 0 {
+					version = b.Baseline
+				}
+			}
+		}
+
+		pkg := PackageDetails{
+			Name:      dependency.Name,
+			Version:   version,
+			Ecosystem: VcpkgEcosystem,
+			CompareAs: VcpkgEcosystem,
+		}
+		if dependency.Host {
+			pkg.DepGroups = append(pkg.DepGroups, "host")
+		}
+
+		packages = append(packages, pkg)
+	}
+
+	return packages, nil
+}
+
+var _ Extractor = VcpkgManifestExtractor{}
+
+//nolint:gochecknoinits
+func init() {
+	registerExtractor("vcpkg.json", VcpkgManifestExtractor{})
+}
+
+func ParseVcpkgManifest(pathToLockfile string) ([]PackageDetails, error) {
+	return extractFromFile(pathToLockfile, VcpkgManifestExtractor{})
+}
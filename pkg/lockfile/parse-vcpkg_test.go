@@ -0,0 +1,301 @@
+package lockfile_test
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+
+	"github.com/google/osv-scanner/pkg/lockfile"
+)
+
+func TestVcpkgManifestExtractor_ShouldExtract(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{
+			name: "",
+			path: "",
+			want: false,
+		},
+		{
+			name: "",
+			path: "vcpkg.json",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/my/vcpkg.json",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/my/vcpkg.json/file",
+			want: false,
+		},
+		{
+			name: "",
+			path: "path/to/my/vcpkg.json.file",
+			want: false,
+		},
+		{
+			name: "",
+			path: "path/to/my/vcpkg-configuration.json",
+			want: false,
+		},
+		{
+			name: "",
+			path: "path/to/my/vcpkg-lock.json",
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e := lockfile.VcpkgManifestExtractor{}
+			got := e.ShouldExtract(tt.path)
+			if got != tt.want {
+				t.Errorf("Extract() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// extractVcpkgWithBaseline extracts the vcpkg.json at path, using baseline
+// as the baseline of the builtin registry at the commit wantCommit
+func extractVcpkgWithBaseline(t *testing.T, path string, wantCommit string, baseline lockfile.VcpkgBaseline) ([]lockfile.PackageDetails, error) {
+	t.Helper()
+
+	f, err := lockfile.OpenLocalDepFile(path)
+	if err != nil {
+		t.Fatalf("could not open %s: %v", path, err)
+	}
+	defer f.Close()
+
+	return lockfile.VcpkgManifestExtractor{
+		FetchBaseline: func(commit string) (lockfile.VcpkgBaseline, error) {
+			if commit != wantCommit {
+				t.Errorf("fetched baseline %s, expected %s", commit, wantCommit)
+			}
+
+			return baseline, nil
+		},
+	}.Extract(f)
+}
+
+func TestParseVcpkgManifest_FileDoesNotExist(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseVcpkgManifest("fixtures/vcpkg/does-not-exist")
+
+	expectErrIs(t, err, fs.ErrNotExist)
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseVcpkgManifest_InvalidJson(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseVcpkgManifest("fixtures/vcpkg/not-json.txt")
+
+	expectErrContaining(t, err, "could not extract from")
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseVcpkgManifest_NoDependencies(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseVcpkgManifest("fixtures/vcpkg/empty/vcpkg.json")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseVcpkgManifest_Dependencies(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseVcpkgManifest("fixtures/vcpkg/dependencies/vcpkg.json")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "boost-asio",
+			Version:   "",
+			Ecosystem: lockfile.VcpkgEcosystem,
+			CompareAs: lockfile.VcpkgEcosystem,
+		},
+		{
+			Name:      "fmt",
+			Version:   "10.1.1",
+			Ecosystem: lockfile.VcpkgEcosystem,
+			CompareAs: lockfile.VcpkgEcosystem,
+		},
+		{
+			Name:      "openssl",
+			Version:   "3.0.7",
+			Ecosystem: lockfile.VcpkgEcosystem,
+			CompareAs: lockfile.VcpkgEcosystem,
+		},
+		{
+			Name:      "vcpkg-cmake",
+			Version:   "",
+			Ecosystem: lockfile.VcpkgEcosystem,
+			CompareAs: lockfile.VcpkgEcosystem,
+			DepGroups: []string{"host"},
+		},
+	})
+}
+
+func TestVcpkgManifestExtractor_Extract_BuiltinBaseline(t *testing.T) {
+	t.Parallel()
+
+	packages, err := extractVcpkgWithBaseline(
+		t,
+		"fixtures/vcpkg/builtin-baseline/vcpkg.json",
+		"3265c187c74914aa5569b75355badebfdbab7987",
+		lockfile.VcpkgBaseline{
+			"boost-asio": {Baseline: "1.83.0"},
+			"fmt":        {Baseline: "10.0.0", PortVersion: 3},
+			"zlib":       {Baseline: "1.3", PortVersion: 1},
+		},
+	)
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "boost-asio",
+			Version:   "1.82.0",
+			Ecosystem: lockfile.VcpkgEcosystem,
+			CompareAs: lockfile.VcpkgEcosystem,
+		},
+		{
+			Name:      "fmt",
+			Version:   "10.1.1",
+			Ecosystem: lockfile.VcpkgEcosystem,
+			CompareAs: lockfile.VcpkgEcosystem,
+		},
+		{
+			Name:      "zlib",
+			Version:   "1.3",
+			Ecosystem: lockfile.VcpkgEcosystem,
+			CompareAs: lockfile.VcpkgEcosystem,
+		},
+		{
+			Name:      "not-in-baseline",
+			Version:   "",
+			Ecosystem: lockfile.VcpkgEcosystem,
+			CompareAs: lockfile.VcpkgEcosystem,
+		},
+	})
+}
+
+func TestVcpkgManifestExtractor_Extract_ConfigurationBaseline(t *testing.T) {
+	t.Parallel()
+
+	packages, err := extractVcpkgWithBaseline(
+		t,
+		"fixtures/vcpkg/configuration/vcpkg.json",
+		"a42af01b72c28a8e1d7b48107b33e4f286a55ef6",
+		lockfile.VcpkgBaseline{"zlib": {Baseline: "1.3"}},
+	)
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "zlib",
+			Version:   "1.3",
+			Ecosystem: lockfile.VcpkgEcosystem,
+			CompareAs: lockfile.VcpkgEcosystem,
+		},
+	})
+}
+
+func TestVcpkgManifestExtractor_Extract_LockedBaseline(t *testing.T) {
+	t.Parallel()
+
+	packages, err := extractVcpkgWithBaseline(
+		t,
+		"fixtures/vcpkg/lock/vcpkg.json",
+		"c8696863d371ab7f46e213d8f5ca923c4aef2a00",
+		lockfile.VcpkgBaseline{"zlib": {Baseline: "1.2.13"}},
+	)
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "zlib",
+			Version:   "1.2.13",
+			Ecosystem: lockfile.VcpkgEcosystem,
+			CompareAs: lockfile.VcpkgEcosystem,
+		},
+	})
+}
+
+func TestVcpkgManifestExtractor_Extract_CustomRegistry(t *testing.T) {
+	t.Parallel()
+
+	f, err := lockfile.OpenLocalDepFile("fixtures/vcpkg/custom-registry/vcpkg.json")
+	if err != nil {
+		t.Fatalf("could not open fixture: %v", err)
+	}
+	defer f.Close()
+
+	packages, err := lockfile.VcpkgManifestExtractor{
+		FetchBaseline: func(commit string) (lockfile.VcpkgBaseline, error) {
+			t.Errorf("fetched baseline %s of a registry that is not the builtin registry", commit)
+
+			return nil, nil
+		},
+	}.Extract(f)
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "zlib",
+			Version:   "",
+			Ecosystem: lockfile.VcpkgEcosystem,
+			CompareAs: lockfile.VcpkgEcosystem,
+		},
+	})
+}
+
+func TestVcpkgManifestExtractor_Extract_FetchError(t *testing.T) {
+	t.Parallel()
+
+	f, err := lockfile.OpenLocalDepFile("fixtures/vcpkg/builtin-baseline/vcpkg.json")
+	if err != nil {
+		t.Fatalf("could not open fixture: %v", err)
+	}
+	defer f.Close()
+
+	errFetch := errors.New("404 Not Found")
+	packages, err := lockfile.VcpkgManifestExtractor{
+		FetchBaseline: func(string) (lockfile.VcpkgBaseline, error) {
+			return nil, errFetch
+		},
+	}.Extract(f)
+
+	expectErrIs(t, err, errFetch)
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
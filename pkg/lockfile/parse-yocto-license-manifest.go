@@ -0,0 +1,87 @@
+package lockfile
+
+import (
+	"bufio"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+const YoctoEcosystem Ecosystem = "Yocto"
+
+type YoctoLicenseManifestExtractor struct{}
+
+func (e YoctoLicenseManifestExtractor) ShouldExtract(path string) bool {
+	return filepath.Base(path) == "license.manifest"
+}
+
+// Extract extracts the packages of an image from the license.manifest that Yocto writes
+// alongside it, which has a block of "KEY: value" lines for each package.
+//
+// Packages are reported using the name of the recipe that built them, as it is the name of
+// the upstream project, whereas a recipe can build a number of packages such as "libssl3"
+func (e YoctoLicenseManifestExtractor) Extract(f DepFile) ([]PackageDetails, error) {
+	scanner := bufio.NewScanner(f)
+
+	details := map[string]PackageDetails{}
+
+	var name, version, recipe string
+
+	addPackage := func() {
+		if recipe == "" {
+			recipe = name
+		}
+
+		if recipe != "" && version != "" {
+			details[recipe+"@"+version] = PackageDetails{
+				Name:      recipe,
+				Version:   version,
+				Ecosystem: YoctoEcosystem,
+				CompareAs: YoctoEcosystem,
+			}
+		}
+
+		name, version, recipe = "", "", ""
+	}
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if line == "" {
+			addPackage()
+
+			continue
+		}
+
+		key, value, _ := strings.Cut(line, ":")
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "PACKAGE NAME":
+			name = value
+		case "PACKAGE VERSION":
+			version = value
+		case "RECIPE NAME":
+			recipe = value
+		}
+	}
+
+	addPackage()
+
+	if err := scanner.Err(); err != nil {
+		return []PackageDetails{}, fmt.Errorf("error while scanning %s: %w", f.Path(), err)
+	}
+
+	return pkgDetailsMapToSlice(details), nil
+}
+
+var _ Extractor = YoctoLicenseManifestExtractor{}
+
+//nolint:gochecknoinits
+func init() {
+	registerExtractor("license.manifest", YoctoLicenseManifestExtractor{})
+}
+
+func ParseYoctoLicenseManifest(pathToLockfile string) ([]PackageDetails, error) {
+	return extractFromFile(pathToLockfile, YoctoLicenseManifestExtractor{})
+}
@@ -0,0 +1,131 @@
+package lockfile_test
+
+import (
+	"io/fs"
+	"testing"
+
+	"github.com/google/osv-scanner/pkg/lockfile"
+)
+
+func TestYoctoLicenseManifestExtractor_ShouldExtract(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{
+			name: "",
+			path: "",
+			want: false,
+		},
+		{
+			name: "",
+			path: "license.manifest",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/my/license.manifest",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/my/license.manifest/file",
+			want: false,
+		},
+		{
+			name: "",
+			path: "path/to/my/license.manifest.file",
+			want: false,
+		},
+		{
+			name: "",
+			path: "path/to/my/image.manifest",
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e := lockfile.YoctoLicenseManifestExtractor{}
+			got := e.ShouldExtract(tt.path)
+			if got != tt.want {
+				t.Errorf("ShouldExtract() - got %v, expected %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseYoctoLicenseManifest_FileDoesNotExist(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseYoctoLicenseManifest("fixtures/yocto/does-not-exist/license.manifest")
+
+	expectErrIs(t, err, fs.ErrNotExist)
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseYoctoLicenseManifest_Empty(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseYoctoLicenseManifest("fixtures/yocto/empty/license.manifest")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseYoctoLicenseManifest_OnePackage(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseYoctoLicenseManifest("fixtures/yocto/one-package/license.manifest")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "busybox",
+			Version:   "1.36.1",
+			Ecosystem: lockfile.YoctoEcosystem,
+			CompareAs: lockfile.YoctoEcosystem,
+		},
+	})
+}
+
+func TestParseYoctoLicenseManifest_MultiplePackages(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseYoctoLicenseManifest("fixtures/yocto/multiple-packages/license.manifest")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "base-files",
+			Version:   "3.0.14",
+			Ecosystem: lockfile.YoctoEcosystem,
+			CompareAs: lockfile.YoctoEcosystem,
+		},
+		{
+			Name:      "openssl",
+			Version:   "3.1.4",
+			Ecosystem: lockfile.YoctoEcosystem,
+			CompareAs: lockfile.YoctoEcosystem,
+		},
+		{
+			Name:      "zlib",
+			Version:   "1.3",
+			Ecosystem: lockfile.YoctoEcosystem,
+			CompareAs: lockfile.YoctoEcosystem,
+		},
+	})
+}
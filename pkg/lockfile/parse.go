@@ -18,14 +18,29 @@ func FindParser(pathToLockfile string, parseAs string) (PackageDetailsParser, st
 
 // this is an optimisation and read-only
 var parsers = map[string]PackageDetailsParser{
+	".terraform.lock.hcl":         ParseTerraformLock,
 	"buildscript-gradle.lockfile": ParseGradleLock,
+	"bun.lock":                    ParseBunLock,
+	"bun.lockb":                   ParseBunBinaryLock,
+	"cabal.project.freeze":        ParseCabalFreeze,
 	"Cargo.lock":                  ParseCargoLock,
+	"Chart.lock":                  ParseHelmChartLock,
+	"Chart.yaml":                  ParseHelmChart,
 	"composer.lock":               ParseComposerLock,
 	"conan.lock":                  ParseConanLock,
+	"conda-lock.yml":              ParseCondaLock,
+	"deno.lock":                   ParseDenoLock,
+	"Dockerfile":                  ParseDockerfile,
+	"environment.yml":             ParseCondaEnvironment,
+	"flake.lock":                  ParseNixFlakeLock,
+	"Gemfile":                     ParseGemfile,
 	"Gemfile.lock":                ParseGemfileLock,
 	"go.mod":                      ParseGoLock,
 	"gradle.lockfile":             ParseGradleLock,
+	"libs.versions.toml":          ParseGradleVersionCatalog,
+	"license.manifest":            ParseYoctoLicenseManifest,
 	"mix.lock":                    ParseMixLock,
+	"MODULE.bazel.lock":           ParseBazelModuleLock,
 	"Pipfile.lock":                ParsePipenvLock,
 	"package-lock.json":           ParseNpmLock,
 	"packages.lock.json":          ParseNuGetLock,
@@ -34,8 +49,13 @@ var parsers = map[string]PackageDetailsParser{
 	"poetry.lock":                 ParsePoetryLock,
 	"pom.xml":                     ParseMavenLock,
 	"pubspec.lock":                ParsePubspecLock,
+	"pyproject.toml":              ParsePyprojectToml,
 	"renv.lock":                   ParseRenvLock,
 	"requirements.txt":            ParseRequirementsTxt,
+	"stack.yaml.lock":             ParseStackLock,
+	"uv.lock":                     ParseUvLock,
+	"vcpkg.json":                  ParseVcpkgManifest,
+	"verification-metadata.xml":   ParseGradleVerificationMetadata,
 	"yarn.lock":                   ParseYarnLock,
 }
 
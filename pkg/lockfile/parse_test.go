@@ -43,13 +43,28 @@ func TestFindParser(t *testing.T) {
 	t.Parallel()
 
 	lockfiles := []string{
+		".terraform.lock.hcl",
 		"buildscript-gradle.lockfile",
+		"bun.lock",
+		"bun.lockb",
+		"cabal.project.freeze",
 		"Cargo.lock",
+		"Chart.lock",
+		"Chart.yaml",
 		"composer.lock",
+		"conda-lock.yml",
+		"deno.lock",
+		"Dockerfile",
+		"environment.yml",
+		"flake.lock",
+		"Gemfile",
 		"Gemfile.lock",
 		"go.mod",
 		"gradle.lockfile",
+		"libs.versions.toml",
+		"license.manifest",
 		"mix.lock",
+		"MODULE.bazel.lock",
 		"pdm.lock",
 		"Pipfile.lock",
 		"package-lock.json",
@@ -58,8 +73,13 @@ func TestFindParser(t *testing.T) {
 		"poetry.lock",
 		"pom.xml",
 		"pubspec.lock",
+		"pyproject.toml",
 		"renv.lock",
 		"requirements.txt",
+		"stack.yaml.lock",
+		"uv.lock",
+		"vcpkg.json",
+		"verification-metadata.xml",
 		"yarn.lock",
 	}
 
@@ -94,14 +114,29 @@ func TestParse_FindsExpectedParsers(t *testing.T) {
 	t.Parallel()
 
 	lockfiles := []string{
+		".terraform.lock.hcl",
 		"buildscript-gradle.lockfile",
+		"bun.lock",
+		"bun.lockb",
+		"cabal.project.freeze",
 		"Cargo.lock",
+		"Chart.lock",
+		"Chart.yaml",
 		"composer.lock",
 		"conan.lock",
+		"conda-lock.yml",
+		"deno.lock",
+		"Dockerfile",
+		"environment.yml",
+		"flake.lock",
+		"Gemfile",
 		"Gemfile.lock",
 		"go.mod",
 		"gradle.lockfile",
+		"libs.versions.toml",
+		"license.manifest",
 		"mix.lock",
+		"MODULE.bazel.lock",
 		"Pipfile.lock",
 		"pdm.lock",
 		"package-lock.json",
@@ -110,8 +145,13 @@ func TestParse_FindsExpectedParsers(t *testing.T) {
 		"poetry.lock",
 		"pom.xml",
 		"pubspec.lock",
+		"pyproject.toml",
 		"renv.lock",
 		"requirements.txt",
+		"stack.yaml.lock",
+		"uv.lock",
+		"vcpkg.json",
+		"verification-metadata.xml",
 		"yarn.lock",
 	}
 
@@ -129,6 +169,8 @@ func TestParse_FindsExpectedParsers(t *testing.T) {
 
 	// gradle.lockfile and buildscript-gradle.lockfile use the same parser
 	count -= 1
+	// bun.lock and bun.lockb are handled in the same file
+	count -= 1
 
 	expectNumberOfParsersCalled(t, count)
 }
@@ -160,7 +202,7 @@ func TestListParsers(t *testing.T) {
 
 	parsers := lockfile.ListParsers()
 
-	firstExpected := "buildscript-gradle.lockfile"
+	firstExpected := ".terraform.lock.hcl"
 	//nolint:ifshort
 	lastExpected := "yarn.lock"
 
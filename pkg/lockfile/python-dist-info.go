@@ -0,0 +1,135 @@
+package lockfile
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+type PythonDistInfoExtractor struct{}
+
+// pythonMetadata is the headers of the metadata of a Python package, which is the METADATA of
+// a wheel or an installed .dist-info directory, or the PKG-INFO of an sdist or an .egg-info
+type pythonMetadata struct {
+	Name    string
+	Version string
+	// RequiresDist are the requirements of the package, like "urllib3 (==1.26.5)"
+	RequiresDist []string
+}
+
+// parsePythonMetadata parses the headers that the metadata of a Python package starts
+// with, which end at the first empty line where the description of the package starts
+func parsePythonMetadata(r io.Reader) (pythonMetadata, error) {
+	var metadata pythonMetadata
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			break
+		}
+
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+
+		switch key {
+		case "Name":
+			metadata.Name = strings.TrimSpace(value)
+		case "Version":
+			metadata.Version = strings.TrimSpace(value)
+		case "Requires-Dist":
+			metadata.RequiresDist = append(metadata.RequiresDist, strings.TrimSpace(value))
+		}
+	}
+
+	return metadata, scanner.Err()
+}
+
+// parsePythonVendorTxt returns the packages that are vendored into a Python package, which pip and
+// setuptools list in the vendor.txt of their _vendor directory with their exact version, like "idna==3.4"
+func parsePythonVendorTxt(r io.Reader) ([]PackageDetails, error) {
+	packages := []PackageDetails{}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		// the environment markers of a requirement are not needed to know what version is vendored
+		line, _, _ := strings.Cut(removeComments(scanner.Text()), ";")
+		line = strings.TrimSpace(line)
+
+		if isNotRequirementLine(line) || !strings.Contains(line, "==") {
+			continue
+		}
+
+		packages = append(packages, parseLine(line))
+	}
+
+	return packages, scanner.Err()
+}
+
+// isPythonVendorTxt returns whether the path is of the list of the packages that are vendored into a Python package
+func isPythonVendorTxt(path string) bool {
+	return filepath.Base(path) == "vendor.txt" && filepath.Base(filepath.Dir(path)) == "_vendor"
+}
+
+// ShouldExtract returns whether the file has the metadata of an installed Python package, which is the
+// METADATA of a .dist-info directory for those installed from wheels, or the PKG-INFO of an .egg-info,
+// or is the list of the packages that are vendored into an installed package, like pip
+func (e PythonDistInfoExtractor) ShouldExtract(path string) bool {
+	dir := filepath.Base(filepath.Dir(path))
+
+	switch filepath.Base(path) {
+	case "METADATA":
+		return strings.HasSuffix(dir, ".dist-info")
+	case "PKG-INFO":
+		return strings.HasSuffix(dir, ".egg-info")
+	}
+
+	return isPythonVendorTxt(path)
+}
+
+// Extract extracts the package that the metadata of an installed Python package is for, or the
+// packages that are vendored into an installed package according to its vendor.txt
+func (e PythonDistInfoExtractor) Extract(f DepFile) ([]PackageDetails, error) {
+	if isPythonVendorTxt(f.Path()) {
+		packages, err := parsePythonVendorTxt(f)
+		if err != nil {
+			return []PackageDetails{}, fmt.Errorf("error while scanning %s: %w", f.Path(), err)
+		}
+
+		return packages, nil
+	}
+
+	metadata, err := parsePythonMetadata(f)
+	if err != nil {
+		return []PackageDetails{}, fmt.Errorf("error while scanning %s: %w", f.Path(), err)
+	}
+
+	if metadata.Name == "" || metadata.Version == "" {
+		return []PackageDetails{}, nil
+	}
+
+	return []PackageDetails{{
+		Name:      normalizedRequirementName(metadata.Name),
+		Version:   metadata.Version,
+		Ecosystem: PipEcosystem,
+		CompareAs: PipEcosystem,
+	}}, nil
+}
+
+var _ Extractor = PythonDistInfoExtractor{}
+
+func ParsePythonDistInfo(pathToMetadata string) ([]PackageDetails, error) {
+	return extractFromFile(pathToMetadata, PythonDistInfoExtractor{})
+}
+
+// FromPythonDistInfo attempts to parse the given file as the metadata of an installed Python package,
+// or as the list of the packages that are vendored into one
+func FromPythonDistInfo(pathToMetadata string) (Lockfile, error) {
+	packages, err := ParsePythonDistInfo(pathToMetadata)
+
+	return artifactLockfile(pathToMetadata, "python-dist-info", packages), err
+}
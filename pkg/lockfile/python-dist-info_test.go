@@ -0,0 +1,160 @@
+package lockfile_test
+
+import (
+	"io/fs"
+	"testing"
+
+	"github.com/google/osv-scanner/pkg/lockfile"
+)
+
+func TestPythonDistInfoExtractor_ShouldExtract(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{
+			name: "",
+			path: "",
+			want: false,
+		},
+		{
+			name: "",
+			path: "METADATA",
+			want: false,
+		},
+		{
+			name: "",
+			path: "path/to/site-packages/Flask-2.2.2.dist-info/METADATA",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/site-packages/PyYAML-5.4.1.egg-info/PKG-INFO",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/site-packages/PyYAML-5.4.1.egg-info/METADATA",
+			want: false,
+		},
+		{
+			name: "",
+			path: "path/to/site-packages/Flask-2.2.2.dist-info/RECORD",
+			want: false,
+		},
+		{
+			name: "",
+			path: "path/to/site-packages/flask/METADATA",
+			want: false,
+		},
+		{
+			name: "",
+			path: "path/to/site-packages/pip/_vendor/vendor.txt",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/site-packages/pip/vendor.txt",
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e := lockfile.PythonDistInfoExtractor{}
+			got := e.ShouldExtract(tt.path)
+			if got != tt.want {
+				t.Errorf("Extract() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePythonDistInfo_FileDoesNotExist(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParsePythonDistInfo("fixtures/python-dist-info/does-not-exist")
+
+	expectErrIs(t, err, fs.ErrNotExist)
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParsePythonDistInfo_Empty(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParsePythonDistInfo("fixtures/python-dist-info/empty.dist-info/METADATA")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParsePythonDistInfo_DistInfo(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParsePythonDistInfo("fixtures/python-dist-info/Flask-2.2.2.dist-info/METADATA")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "flask",
+			Version:   "2.2.2",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+		},
+	})
+}
+
+func TestParsePythonDistInfo_EggInfo(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParsePythonDistInfo("fixtures/python-dist-info/PyYAML-5.4.1.egg-info/PKG-INFO")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "pyyaml",
+			Version:   "5.4.1",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+		},
+	})
+}
+
+func TestParsePythonDistInfo_VendorTxt(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParsePythonDistInfo("fixtures/python-dist-info/pip/_vendor/vendor.txt")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "idna",
+			Version:   "3.4",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+		},
+		{
+			Name:      "tomli",
+			Version:   "2.0.1",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+		},
+	})
+}
@@ -0,0 +1,195 @@
+package lockfile
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/osv-scanner/internal/cachedregexp"
+)
+
+type PythonDistributionExtractor struct{}
+
+// isPythonSdist returns whether the name is of an sdist, which are named after the package and its
+// version, like "requests-2.31.0.tar.gz", to tell them apart from other tarballs
+func isPythonSdist(name string) bool {
+	return cachedregexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*-\d[A-Za-z0-9.!+_]*\.tar\.gz$`).MatchString(name)
+}
+
+// ShouldExtract returns whether the file is a distribution of a Python package, which is either a wheel or an sdist
+func (e PythonDistributionExtractor) ShouldExtract(path string) bool {
+	name := filepath.Base(path)
+
+	return strings.HasSuffix(strings.ToLower(name), ".whl") || isPythonSdist(name)
+}
+
+// pinnedPythonRequirements returns the requirements of a Python package that are pinned to an exact
+// version, like "urllib3 (==1.26.5)", which are as good as vendored into it as no other version can be
+// installed alongside it. Requirements that are only for extras are skipped, as they are not always installed
+func pinnedPythonRequirements(requirements []string) []PackageDetails {
+	var packages []PackageDetails
+
+	re := cachedregexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9._-]*)\s*(?:\[[^\]]*\])?\s*\(?\s*===?\s*([^\s,;()]+)\s*\)?\s*(?:;(.*))?$`)
+
+	for _, requirement := range requirements {
+		match := re.FindStringSubmatch(requirement)
+		if match == nil || strings.Contains(match[3], "extra") {
+			continue
+		}
+
+		packages = append(packages, PackageDetails{
+			Name:      normalizedRequirementName(match[1]),
+			Version:   match[2],
+			Ecosystem: PipEcosystem,
+			CompareAs: PipEcosystem,
+		})
+	}
+
+	return packages
+}
+
+// pythonDistributionPackages returns the package that the metadata of a distribution is for, along with
+// the requirements that it pins to an exact version and the packages that its vendor.txt lists
+func pythonDistributionPackages(metadata pythonMetadata, vendored []PackageDetails) []PackageDetails {
+	packages := []PackageDetails{}
+
+	if metadata.Name != "" && metadata.Version != "" {
+		packages = append(packages, PackageDetails{
+			Name:      normalizedRequirementName(metadata.Name),
+			Version:   metadata.Version,
+			Ecosystem: PipEcosystem,
+			CompareAs: PipEcosystem,
+		})
+	}
+
+	packages = append(packages, pinnedPythonRequirements(metadata.RequiresDist)...)
+
+	return append(packages, vendored...)
+}
+
+// extractPythonWheel extracts the packages of a wheel from the METADATA of its .dist-info directory
+func extractPythonWheel(b []byte) ([]PackageDetails, error) {
+	archive, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		return nil, err
+	}
+
+	var metadata pythonMetadata
+	var vendored []PackageDetails
+
+	for _, file := range archive.File {
+		dir := path.Dir(file.Name)
+		isMetadata := path.Base(file.Name) == "METADATA" && strings.HasSuffix(dir, ".dist-info") && !strings.Contains(dir, "/")
+
+		if !isMetadata && !isPythonVendorTxt(file.Name) {
+			continue
+		}
+
+		r, err := file.Open()
+		if err != nil {
+			return nil, err
+		}
+
+		if isMetadata {
+			metadata, err = parsePythonMetadata(r)
+		} else {
+			var pkgs []PackageDetails
+			pkgs, err = parsePythonVendorTxt(r)
+			vendored = append(vendored, pkgs...)
+		}
+		r.Close()
+
+		if err != nil {
+			return nil, fmt.Errorf("could not extract %s: %w", file.Name, err)
+		}
+	}
+
+	return pythonDistributionPackages(metadata, vendored), nil
+}
+
+// extractPythonSdist extracts the packages of an sdist from the PKG-INFO in the directory that it is
+// a tarball of, which is named after the package and its version like the sdist itself
+func extractPythonSdist(r io.Reader) ([]PackageDetails, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	var metadata pythonMetadata
+	var vendored []PackageDetails
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		name := path.Clean(header.Name)
+
+		switch {
+		case header.Typeflag != tar.TypeReg:
+			continue
+		case path.Base(name) == "PKG-INFO" && !strings.Contains(path.Dir(name), "/") && path.Dir(name) != ".":
+			metadata, err = parsePythonMetadata(tr)
+		case isPythonVendorTxt(name):
+			var pkgs []PackageDetails
+			pkgs, err = parsePythonVendorTxt(tr)
+			vendored = append(vendored, pkgs...)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("could not extract %s: %w", name, err)
+		}
+	}
+
+	return pythonDistributionPackages(metadata, vendored), nil
+}
+
+// Extract extracts the package that a distribution of a Python package is of, either a wheel or an
+// sdist, along with the packages that it pins or vendors, so that packages can be checked before they
+// are installed, such as in an artifact repository
+func (e PythonDistributionExtractor) Extract(f DepFile) ([]PackageDetails, error) {
+	var packages []PackageDetails
+	var err error
+
+	if strings.HasSuffix(strings.ToLower(f.Path()), ".whl") {
+		var b []byte
+		b, err = io.ReadAll(f)
+		if err == nil {
+			packages, err = extractPythonWheel(b)
+		}
+	} else {
+		packages, err = extractPythonSdist(f)
+	}
+
+	if err != nil {
+		return []PackageDetails{}, fmt.Errorf("could not extract from %s: %w", f.Path(), err)
+	}
+
+	return packages, nil
+}
+
+var _ Extractor = PythonDistributionExtractor{}
+
+func ParsePythonDistribution(pathToDistribution string) ([]PackageDetails, error) {
+	return extractFromFile(pathToDistribution, PythonDistributionExtractor{})
+}
+
+// FromPythonDistribution attempts to parse the given file as a wheel or an sdist of a Python package
+func FromPythonDistribution(pathToDistribution string) (Lockfile, error) {
+	packages, err := ParsePythonDistribution(pathToDistribution)
+
+	return artifactLockfile(pathToDistribution, "python-distribution", packages), err
+}
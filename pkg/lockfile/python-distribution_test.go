@@ -0,0 +1,188 @@
+package lockfile_test
+
+import (
+	"io/fs"
+	"testing"
+
+	"github.com/google/osv-scanner/pkg/lockfile"
+)
+
+func TestPythonDistributionExtractor_ShouldExtract(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{
+			name: "",
+			path: "",
+			want: false,
+		},
+		{
+			name: "",
+			path: "path/to/requests-2.31.0-py3-none-any.whl",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/requests-2.31.0.tar.gz",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/python-dateutil-2.8.2.tar.gz",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/backup.tar.gz",
+			want: false,
+		},
+		{
+			name: "",
+			path: "path/to/requests-2.31.0.zip",
+			want: false,
+		},
+		{
+			name: "",
+			path: "path/to/requests-2.31.0-py3-none-any.whl/file",
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e := lockfile.PythonDistributionExtractor{}
+			got := e.ShouldExtract(tt.path)
+			if got != tt.want {
+				t.Errorf("Extract() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePythonDistribution_FileDoesNotExist(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParsePythonDistribution("fixtures/python-distribution/does-not-exist.whl")
+
+	expectErrIs(t, err, fs.ErrNotExist)
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParsePythonDistribution_NotAWheel(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParsePythonDistribution("fixtures/python-distribution/not-a-wheel.whl")
+
+	expectErrContaining(t, err, "could not extract from")
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParsePythonDistribution_NotAnSdist(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParsePythonDistribution("fixtures/python-distribution/not-an-sdist-1.0.tar.gz")
+
+	expectErrContaining(t, err, "could not extract from")
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParsePythonDistribution_Wheel(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParsePythonDistribution("fixtures/python-distribution/requests-2.31.0-py3-none-any.whl")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "requests",
+			Version:   "2.31.0",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+		},
+		{
+			Name:      "idna",
+			Version:   "3.4",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+		},
+		{
+			Name:      "certifi",
+			Version:   "2023.7.22",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+		},
+	})
+}
+
+func TestParsePythonDistribution_WheelWithVendoredPackages(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParsePythonDistribution("fixtures/python-distribution/pip-23.3.1-py3-none-any.whl")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "pip",
+			Version:   "23.3.1",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+		},
+		{
+			Name:      "cachecontrol",
+			Version:   "0.13.1",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+		},
+		{
+			Name:      "colorama",
+			Version:   "0.4.6",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+		},
+		{
+			Name:      "urllib3",
+			Version:   "1.26.17",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+		},
+		{
+			Name:      "tomli",
+			Version:   "2.0.1",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+		},
+	})
+}
+
+func TestParsePythonDistribution_Sdist(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParsePythonDistribution("fixtures/python-distribution/MarkupSafe-2.1.3.tar.gz")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "markupsafe",
+			Version:   "2.1.3",
+			Ecosystem: lockfile.PipEcosystem,
+			CompareAs: lockfile.PipEcosystem,
+		},
+	})
+}
@@ -0,0 +1,37 @@
+package lockfile
+
+import (
+	"github.com/google/osv-scanner/internal/semantic"
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+// highestVersion returns the best of the versions that satisfy a requirement when resolving it
+// to a version that has been published, which is the highest version that is not a pre-release
+// unless only pre-releases satisfy it, or an empty string if none of the versions satisfy it
+func highestVersion(ecosystem models.Ecosystem, versions []string, satisfies func(string) bool, isPreRelease func(string) bool) string {
+	var best, bestPreRelease string
+
+	for _, version := range versions {
+		if !satisfies(version) {
+			continue
+		}
+
+		if isPreRelease(version) {
+			if bestPreRelease == "" || semantic.MustParse(bestPreRelease, ecosystem).CompareStr(version) < 0 {
+				bestPreRelease = version
+			}
+
+			continue
+		}
+
+		if best == "" || semantic.MustParse(best, ecosystem).CompareStr(version) < 0 {
+			best = version
+		}
+	}
+
+	if best == "" {
+		return bestPreRelease
+	}
+
+	return best
+}
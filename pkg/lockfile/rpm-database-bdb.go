@@ -0,0 +1,153 @@
+package lockfile
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// bdbHashMagic is the magic number of a Berkeley DB hash database, which is what the
+// "Packages" database of rpm is
+const bdbHashMagic = 0x061561
+
+const (
+	bdbPageHeaderSize = 26
+
+	bdbPageTypeHashUnsorted = 2
+	bdbPageTypeOverflow     = 7
+	bdbPageTypeHash         = 13
+
+	bdbItemKeyData = 1
+	bdbItemOffPage = 3
+)
+
+var errBdbNotHashDatabase = errors.New("not a Berkeley DB hash database")
+
+// bdbByteOrder returns the byte order of a Berkeley DB hash database, which is that
+// of the machine that created it
+func bdbByteOrder(data []byte) (binary.ByteOrder, error) {
+	if len(data) < 512 {
+		return nil, errBdbNotHashDatabase
+	}
+
+	if binary.LittleEndian.Uint32(data[12:]) == bdbHashMagic {
+		return binary.LittleEndian, nil
+	}
+
+	if binary.BigEndian.Uint32(data[12:]) == bdbHashMagic {
+		return binary.BigEndian, nil
+	}
+
+	return nil, errBdbNotHashDatabase
+}
+
+// readBdbOverflow reads the value that is stored across the chain of overflow pages
+// starting at pgno, which is how values that are too large for a hash page are stored
+func readBdbOverflow(data []byte, order binary.ByteOrder, pageSize int, pgno uint32, length uint32) ([]byte, error) {
+	if uint64(length) > uint64(len(data)) {
+		return nil, fmt.Errorf("overflow value of %d bytes is out of bounds", length)
+	}
+
+	value := make([]byte, 0, length)
+	seen := make(map[uint32]bool)
+
+	for pgno != 0 && uint32(len(value)) < length {
+		if seen[pgno] {
+			return nil, fmt.Errorf("overflow page %d is part of a cycle", pgno)
+		}
+
+		seen[pgno] = true
+
+		start := int(pgno) * pageSize
+		if start+pageSize > len(data) {
+			return nil, fmt.Errorf("overflow page %d is out of bounds", pgno)
+		}
+
+		page := data[start : start+pageSize]
+
+		if page[25] != bdbPageTypeOverflow {
+			return nil, fmt.Errorf("page %d is not an overflow page", pgno)
+		}
+
+		// the offset of the free area of an overflow page is the length of its data instead
+		n := int(order.Uint16(page[22:]))
+		if bdbPageHeaderSize+n > pageSize {
+			return nil, fmt.Errorf("overflow page %d has too much data", pgno)
+		}
+
+		value = append(value, page[bdbPageHeaderSize:bdbPageHeaderSize+n]...)
+		pgno = order.Uint32(page[16:])
+	}
+
+	if uint32(len(value)) < length {
+		return nil, fmt.Errorf("overflow value is missing %d bytes", length-uint32(len(value)))
+	}
+
+	return value[:length], nil
+}
+
+// readBdbHashValues reads the values of every key in a Berkeley DB hash database,
+// without needing to know the keys by reading every page of the database
+func readBdbHashValues(data []byte) ([][]byte, error) {
+	order, err := bdbByteOrder(data)
+	if err != nil {
+		return nil, err
+	}
+
+	pageSize := int(order.Uint32(data[20:]))
+	if pageSize < 512 || pageSize > 65536 {
+		return nil, fmt.Errorf("invalid page size %d", pageSize)
+	}
+
+	lastPage := int(order.Uint32(data[32:]))
+
+	var values [][]byte
+
+	for pgno := 1; pgno <= lastPage && (pgno+1)*pageSize <= len(data); pgno++ {
+		page := data[pgno*pageSize : (pgno+1)*pageSize]
+
+		if page[25] != bdbPageTypeHash && page[25] != bdbPageTypeHashUnsorted {
+			continue
+		}
+
+		entries := int(order.Uint16(page[20:]))
+
+		// the entries of a hash page are pairs of a key and its value
+		for i := 1; i < entries; i += 2 {
+			indexOffset := bdbPageHeaderSize + i*2
+			if indexOffset+2 > pageSize {
+				break
+			}
+
+			offset := int(order.Uint16(page[indexOffset:]))
+			if offset >= pageSize {
+				return nil, fmt.Errorf("entry %d of page %d is out of bounds", i, pgno)
+			}
+
+			switch page[offset] {
+			case bdbItemOffPage:
+				if offset+12 > pageSize {
+					return nil, fmt.Errorf("entry %d of page %d is out of bounds", i, pgno)
+				}
+
+				value, err := readBdbOverflow(data, order, pageSize, order.Uint32(page[offset+4:]), order.Uint32(page[offset+8:]))
+				if err != nil {
+					return nil, err
+				}
+
+				values = append(values, value)
+			case bdbItemKeyData:
+				// the data of an item ends where the item before it in the page starts
+				end := int(order.Uint16(page[indexOffset-2:]))
+
+				if end <= offset || end > pageSize {
+					return nil, fmt.Errorf("entry %d of page %d is out of bounds", i, pgno)
+				}
+
+				values = append(values, page[offset+1:end])
+			}
+		}
+	}
+
+	return values, nil
+}
@@ -0,0 +1,330 @@
+package lockfile
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// sqliteMagic is the string that every SQLite database starts with
+const sqliteMagic = "SQLite format 3\x00"
+
+const (
+	sqlitePageTypeInteriorTable = 0x05
+	sqlitePageTypeLeafTable     = 0x0d
+)
+
+var errNotSqliteDatabase = errors.New("not a SQLite database")
+
+// sqliteDatabase is a read-only SQLite database that has been read into memory,
+// which supports reading the rows of tables that have a rowid
+type sqliteDatabase struct {
+	data       []byte
+	pageSize   int
+	usableSize int
+}
+
+func newSqliteDatabase(data []byte) (*sqliteDatabase, error) {
+	if len(data) < 100 || !bytes.HasPrefix(data, []byte(sqliteMagic)) {
+		return nil, errNotSqliteDatabase
+	}
+
+	pageSize := int(binary.BigEndian.Uint16(data[16:]))
+
+	// a page size of 65536 does not fit, so it is stored as 1 instead
+	if pageSize == 1 {
+		pageSize = 65536
+	}
+
+	// the usable size of a page must be at least 480 bytes
+	if pageSize < 512 || pageSize-int(data[20]) < 480 {
+		return nil, fmt.Errorf("invalid page size %d", pageSize)
+	}
+
+	return &sqliteDatabase{
+		data:       data,
+		pageSize:   pageSize,
+		usableSize: pageSize - int(data[20]),
+	}, nil
+}
+
+func (db *sqliteDatabase) page(pgno uint32) ([]byte, error) {
+	start := int(pgno-1) * db.pageSize
+
+	if pgno == 0 || start+db.pageSize > len(db.data) {
+		return nil, fmt.Errorf("page %d is out of bounds", pgno)
+	}
+
+	return db.data[start : start+db.pageSize], nil
+}
+
+// readSqliteVarint reads a variable-length integer, returning it with the number
+// of bytes it took up, which is zero if b is too short to hold it
+func readSqliteVarint(b []byte) (uint64, int) {
+	var v uint64
+
+	for i := 0; i < 9 && i < len(b); i++ {
+		// the ninth byte of a varint contributes all eight of its bits
+		if i == 8 {
+			return v<<8 | uint64(b[i]), 9
+		}
+
+		v = v<<7 | uint64(b[i]&0x7f)
+
+		if b[i]&0x80 == 0 {
+			return v, i + 1
+		}
+	}
+
+	return 0, 0
+}
+
+// payload reads the payload of a cell of a leaf table page, which continues on
+// a chain of overflow pages if it is too large to fit on the page
+func (db *sqliteDatabase) payload(cell []byte, size int) ([]byte, error) {
+	if size < 0 || size > len(db.data) {
+		return nil, fmt.Errorf("payload size %d is out of bounds", size)
+	}
+
+	maxLocal := db.usableSize - 35
+
+	if size <= maxLocal {
+		if size > len(cell) {
+			return nil, errors.New("cell is out of bounds")
+		}
+
+		return cell[:size], nil
+	}
+
+	minLocal := (db.usableSize-12)*32/255 - 23
+	local := minLocal + (size-minLocal)%(db.usableSize-4)
+
+	if local > maxLocal {
+		local = minLocal
+	}
+
+	if local+4 > len(cell) {
+		return nil, errors.New("cell is out of bounds")
+	}
+
+	payload := make([]byte, 0, size)
+	payload = append(payload, cell[:local]...)
+
+	next := binary.BigEndian.Uint32(cell[local:])
+	seen := make(map[uint32]bool)
+
+	for next != 0 && len(payload) < size {
+		if seen[next] {
+			return nil, fmt.Errorf("overflow page %d is part of a cycle", next)
+		}
+
+		seen[next] = true
+
+		page, err := db.page(next)
+		if err != nil {
+			return nil, err
+		}
+
+		n := min(size-len(payload), db.usableSize-4)
+		payload = append(payload, page[4:4+n]...)
+		next = binary.BigEndian.Uint32(page)
+	}
+
+	if len(payload) < size {
+		return nil, fmt.Errorf("payload is missing %d bytes", size-len(payload))
+	}
+
+	return payload, nil
+}
+
+// readSqliteRecord reads the values of the columns of a record, which are either nil,
+// an int64, or a []byte for text and blobs; floats are not supported
+func readSqliteRecord(record []byte) ([]any, error) {
+	headerSize, n := readSqliteVarint(record)
+	if n == 0 || headerSize < uint64(n) || headerSize > uint64(len(record)) {
+		return nil, errors.New("record header is out of bounds")
+	}
+
+	header := record[n:headerSize]
+	body := record[headerSize:]
+
+	var values []any
+
+	for len(header) > 0 {
+		serialType, n := readSqliteVarint(header)
+		if n == 0 {
+			return nil, errors.New("record header is truncated")
+		}
+
+		header = header[n:]
+
+		var size int
+
+		switch {
+		case serialType == 0, serialType == 8, serialType == 9:
+			size = 0
+		case serialType <= 4:
+			size = int(serialType)
+		case serialType == 5:
+			size = 6
+		case serialType == 6, serialType == 7:
+			size = 8
+		case serialType >= 12:
+			size = int(serialType-12) / 2
+		default:
+			return nil, fmt.Errorf("unsupported serial type %d", serialType)
+		}
+
+		if size > len(body) {
+			return nil, errors.New("record body is out of bounds")
+		}
+
+		value := body[:size]
+		body = body[size:]
+
+		switch {
+		case serialType == 0:
+			values = append(values, nil)
+		case serialType == 8, serialType == 9:
+			values = append(values, int64(serialType-8))
+		case serialType <= 6:
+			// integers are big-endian two's complement, so sign extend them
+			var v int64
+			if value[0]&0x80 != 0 {
+				v = -1
+			}
+
+			for _, b := range value {
+				v = v<<8 | int64(b)
+			}
+
+			values = append(values, v)
+		case serialType == 7:
+			values = append(values, nil)
+		default:
+			values = append(values, value)
+		}
+	}
+
+	return values, nil
+}
+
+// rows reads the records of every row of the table whose b-tree starts at the given page
+func (db *sqliteDatabase) rows(root uint32) ([][]any, error) {
+	var rows [][]any
+
+	seen := make(map[uint32]bool)
+
+	var visit func(pgno uint32) error
+	visit = func(pgno uint32) error {
+		if seen[pgno] {
+			return fmt.Errorf("page %d is part of a cycle", pgno)
+		}
+
+		seen[pgno] = true
+
+		page, err := db.page(pgno)
+		if err != nil {
+			return err
+		}
+
+		// the first page has the header of the database before the header of the page
+		offset := 0
+		if pgno == 1 {
+			offset = 100
+		}
+
+		cells := int(binary.BigEndian.Uint16(page[offset+3:]))
+
+		if offset+12+cells*2 > len(page) {
+			return fmt.Errorf("cells of page %d are out of bounds", pgno)
+		}
+
+		switch page[offset] {
+		case sqlitePageTypeInteriorTable:
+			pointers := page[offset+12:]
+
+			for i := 0; i < cells; i++ {
+				cell := int(binary.BigEndian.Uint16(pointers[i*2:]))
+				if cell+4 > len(page) {
+					return fmt.Errorf("cell %d of page %d is out of bounds", i, pgno)
+				}
+
+				if err := visit(binary.BigEndian.Uint32(page[cell:])); err != nil {
+					return err
+				}
+			}
+
+			return visit(binary.BigEndian.Uint32(page[offset+8:]))
+		case sqlitePageTypeLeafTable:
+			pointers := page[offset+8:]
+
+			for i := 0; i < cells; i++ {
+				start := int(binary.BigEndian.Uint16(pointers[i*2:]))
+				if start >= len(page) {
+					return fmt.Errorf("cell %d of page %d is out of bounds", i, pgno)
+				}
+
+				cell := page[start:]
+
+				size, n := readSqliteVarint(cell)
+				if n == 0 {
+					return fmt.Errorf("cell %d of page %d is out of bounds", i, pgno)
+				}
+
+				cell = cell[n:]
+
+				// the rowid of the row is not needed
+				_, n = readSqliteVarint(cell)
+				if n == 0 {
+					return fmt.Errorf("cell %d of page %d is out of bounds", i, pgno)
+				}
+
+				payload, err := db.payload(cell[n:], int(size))
+				if err != nil {
+					return fmt.Errorf("cell %d of page %d: %w", i, pgno, err)
+				}
+
+				record, err := readSqliteRecord(payload)
+				if err != nil {
+					return fmt.Errorf("cell %d of page %d: %w", i, pgno, err)
+				}
+
+				rows = append(rows, record)
+			}
+
+			return nil
+		}
+
+		return fmt.Errorf("page %d is not a table page", pgno)
+	}
+
+	return rows, visit(root)
+}
+
+// tableRows reads the records of every row of the table with the given name
+func (db *sqliteDatabase) tableRows(name string) ([][]any, error) {
+	// the schema of the database is a table on the first page, with columns for the type,
+	// name, table name, root page, and sql of each table and index in the database
+	schema, err := db.rows(1)
+	if err != nil {
+		return nil, fmt.Errorf("could not read schema: %w", err)
+	}
+
+	for _, row := range schema {
+		if len(row) < 4 {
+			continue
+		}
+
+		typ, _ := row[0].([]byte)
+		tbl, _ := row[1].([]byte)
+		root, _ := row[3].(int64)
+
+		if string(typ) == "table" && string(tbl) == name && root > 0 {
+			return db.rows(uint32(root))
+		}
+	}
+
+	return nil, fmt.Errorf("could not find table %s", name)
+}
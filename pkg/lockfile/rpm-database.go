@@ -0,0 +1,321 @@
+package lockfile
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	RedHatEcosystem     Ecosystem = "Red Hat"
+	AlmaLinuxEcosystem  Ecosystem = "AlmaLinux"
+	RockyLinuxEcosystem Ecosystem = "Rocky Linux"
+	FedoraEcosystem     Ecosystem = "Fedora"
+	OpenSUSEEcosystem   Ecosystem = "openSUSE"
+	SUSEEcosystem       Ecosystem = "SUSE"
+)
+
+// rpmDatabasePaths are where rpm keeps its database relative to the root of the filesystem,
+// which is either a Berkeley DB or, since rpm 4.16, a SQLite database
+var rpmDatabasePaths = []string{
+	"var/lib/rpm/Packages",
+	"var/lib/rpm/rpmdb.sqlite",
+	"usr/lib/sysimage/rpm/Packages",
+	"usr/lib/sysimage/rpm/rpmdb.sqlite",
+}
+
+// rpmDistributionEcosystems are the ecosystems of the distributions that use rpm,
+// based on the ID in their os-release
+var rpmDistributionEcosystems = map[string]Ecosystem{
+	"almalinux":           AlmaLinuxEcosystem,
+	"centos":              RedHatEcosystem,
+	"fedora":              FedoraEcosystem,
+	"opensuse":            OpenSUSEEcosystem,
+	"opensuse-leap":       OpenSUSEEcosystem,
+	"opensuse-tumbleweed": OpenSUSEEcosystem,
+	"rhel":                RedHatEcosystem,
+	"rocky":               RockyLinuxEcosystem,
+	"sled":                SUSEEcosystem,
+	"sles":                SUSEEcosystem,
+	"sles_sap":            SUSEEcosystem,
+}
+
+const (
+	rpmTagName    = 1000
+	rpmTagVersion = 1001
+	rpmTagRelease = 1002
+	rpmTagEpoch   = 1003
+
+	rpmTypeInt32      = 4
+	rpmTypeString     = 6
+	rpmTypeI18NString = 9
+)
+
+// rpmHeader is the name and version of a package from its header in the rpm database
+type rpmHeader struct {
+	name    string
+	version string
+	release string
+	epoch   uint32
+}
+
+// parseRpmHeader parses the header of a package as it is stored in the rpm database, which
+// is an index of tags followed by the data that they point to, without the usual magic
+func parseRpmHeader(blob []byte) (rpmHeader, error) {
+	var header rpmHeader
+
+	if len(blob) < 8 {
+		return header, errors.New("header is too short")
+	}
+
+	il := uint64(binary.BigEndian.Uint32(blob[0:]))
+	dl := uint64(binary.BigEndian.Uint32(blob[4:]))
+
+	if 8+il*16+dl > uint64(len(blob)) {
+		return header, errors.New("header is out of bounds")
+	}
+
+	store := blob[8+il*16 : 8+il*16+dl]
+
+	readString := func(offset int) string {
+		s, _, _ := bytes.Cut(store[offset:], []byte{0})
+
+		return string(s)
+	}
+
+	for i := uint64(0); i < il; i++ {
+		entry := blob[8+i*16 : 8+(i+1)*16]
+
+		tag := binary.BigEndian.Uint32(entry[0:])
+		typ := binary.BigEndian.Uint32(entry[4:])
+		offset := int(int32(binary.BigEndian.Uint32(entry[8:])))
+
+		if offset < 0 || offset >= len(store) {
+			continue
+		}
+
+		isString := typ == rpmTypeString || typ == rpmTypeI18NString
+
+		switch {
+		case tag == rpmTagName && isString:
+			header.name = readString(offset)
+		case tag == rpmTagVersion && isString:
+			header.version = readString(offset)
+		case tag == rpmTagRelease && isString:
+			header.release = readString(offset)
+		case tag == rpmTagEpoch && typ == rpmTypeInt32 && offset+4 <= len(store):
+			header.epoch = binary.BigEndian.Uint32(store[offset:])
+		}
+	}
+
+	return header, nil
+}
+
+// readRpmDatabase reads the headers of the packages in an rpm database,
+// which is either a SQLite database or a Berkeley DB hash database
+func readRpmDatabase(data []byte) ([][]byte, error) {
+	if !bytes.HasPrefix(data, []byte(sqliteMagic)) {
+		return readBdbHashValues(data)
+	}
+
+	db, err := newSqliteDatabase(data)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.tableRows("Packages")
+	if err != nil {
+		return nil, err
+	}
+
+	blobs := make([][]byte, 0, len(rows))
+
+	// the table has a column for the number of the header, and one for the header itself
+	for _, row := range rows {
+		if len(row) < 2 {
+			continue
+		}
+
+		if blob, ok := row[1].([]byte); ok {
+			blobs = append(blobs, blob)
+		}
+	}
+
+	return blobs, nil
+}
+
+// parseOSRelease parses the variables of an os-release file
+func parseOSRelease(r io.Reader) map[string]string {
+	variables := make(map[string]string)
+	scanner := bufio.NewScanner(r)
+
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(strings.TrimSpace(scanner.Text()), "=")
+
+		if !ok || strings.HasPrefix(key, "#") {
+			continue
+		}
+
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			value = unquoted
+		} else {
+			value = strings.Trim(value, `'"`)
+		}
+
+		variables[key] = value
+	}
+
+	return variables
+}
+
+// rpmReleaseEcosystem returns the ecosystem of the distribution that the rpm database of f
+// is for, such as "AlmaLinux:9", based on the os-release of the filesystem that it is in,
+// or RedHatEcosystem if the distribution cannot be determined
+func rpmReleaseEcosystem(f DepFile) Ecosystem {
+	path := filepath.ToSlash(f.Path())
+
+	for _, dbPath := range rpmDatabasePaths {
+		if !strings.HasSuffix(path, "/"+dbPath) {
+			continue
+		}
+
+		root := strings.Repeat("../", strings.Count(dbPath, "/"))
+
+		for _, osReleasePath := range []string{"etc/os-release", "usr/lib/os-release"} {
+			osReleaseFile, err := f.Open(root + osReleasePath)
+			if err != nil {
+				continue
+			}
+
+			osRelease := parseOSRelease(osReleaseFile)
+			_ = osReleaseFile.Close()
+
+			ecosystem, ok := rpmDistributionEcosystems[osRelease["ID"]]
+
+			if !ok {
+				return RedHatEcosystem
+			}
+
+			major, _, _ := strings.Cut(osRelease["VERSION_ID"], ".")
+
+			switch {
+			case (ecosystem == AlmaLinuxEcosystem || ecosystem == RockyLinuxEcosystem) && major != "":
+				return Ecosystem(fmt.Sprintf("%s:%s", ecosystem, major))
+			case osRelease["ID"] == "opensuse-leap" && osRelease["VERSION_ID"] != "":
+				return Ecosystem(fmt.Sprintf("%s:Leap %s", ecosystem, osRelease["VERSION_ID"]))
+			case osRelease["ID"] == "opensuse-tumbleweed":
+				return Ecosystem(fmt.Sprintf("%s:Tumbleweed", ecosystem))
+			}
+
+			return ecosystem
+		}
+	}
+
+	return RedHatEcosystem
+}
+
+func ParseRpmDatabase(pathToLockfile string) ([]PackageDetails, error) {
+	return extractFromFile(pathToLockfile, RpmDatabaseExtractor{})
+}
+
+type RpmDatabaseExtractor struct{}
+
+func (e RpmDatabaseExtractor) ShouldExtract(path string) bool {
+	path = filepath.ToSlash(path)
+
+	for _, dbPath := range rpmDatabasePaths {
+		if path == dbPath || strings.HasSuffix(path, "/"+dbPath) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Extract extracts the packages that are installed according to the rpm database, which
+// are reported using the ecosystem of the distribution that the filesystem it is in is
+// running when it can be determined
+func (e RpmDatabaseExtractor) Extract(f DepFile) ([]PackageDetails, error) {
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return []PackageDetails{}, fmt.Errorf("could not extract from %s: %w", f.Path(), err)
+	}
+
+	// an empty database has not had any packages installed into it yet
+	if len(data) == 0 {
+		return []PackageDetails{}, nil
+	}
+
+	blobs, err := readRpmDatabase(data)
+	if err != nil {
+		return []PackageDetails{}, fmt.Errorf("could not extract from %s: %w", f.Path(), err)
+	}
+
+	ecosystem := rpmReleaseEcosystem(f)
+	compareAs := ecosystem
+
+	if base, _, ok := strings.Cut(string(ecosystem), ":"); ok {
+		compareAs = Ecosystem(base)
+	}
+
+	details := map[string]PackageDetails{}
+
+	for _, blob := range blobs {
+		header, err := parseRpmHeader(blob)
+		if err != nil {
+			return []PackageDetails{}, fmt.Errorf("could not extract from %s: %w", f.Path(), err)
+		}
+
+		// the public keys that rpm trusts are stored as packages too
+		if header.name == "" || header.name == "gpg-pubkey" {
+			continue
+		}
+
+		version := header.version
+		if header.release != "" {
+			version += "-" + header.release
+		}
+		if header.epoch != 0 {
+			version = fmt.Sprintf("%d:%s", header.epoch, version)
+		}
+
+		// packages installed for more than one architecture are only reported once
+		details[header.name+"@"+version] = PackageDetails{
+			Name:      header.name,
+			Version:   version,
+			Ecosystem: ecosystem,
+			CompareAs: compareAs,
+		}
+	}
+
+	return pkgDetailsMapToSlice(details), nil
+}
+
+var _ Extractor = RpmDatabaseExtractor{}
+
+// FromRpmDatabase attempts to parse the given file as an "rpm-db" lockfile,
+// which is the database that rpm uses to record installed packages.
+func FromRpmDatabase(pathToDatabase string) (Lockfile, error) {
+	packages, err := ParseRpmDatabase(pathToDatabase)
+
+	sort.Slice(packages, func(i, j int) bool {
+		if packages[i].Name == packages[j].Name {
+			return packages[i].Version < packages[j].Version
+		}
+
+		return packages[i].Name < packages[j].Name
+	})
+
+	return Lockfile{
+		FilePath: pathToDatabase,
+		ParsedAs: "rpm-db",
+		Packages: packages,
+	}, err
+}
@@ -0,0 +1,316 @@
+package lockfile_test
+
+import (
+	"io/fs"
+	"testing"
+
+	"github.com/google/osv-scanner/pkg/lockfile"
+)
+
+func TestRpmDatabaseExtractor_ShouldExtract(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{
+			name: "",
+			path: "",
+			want: false,
+		},
+		{
+			name: "",
+			path: "Packages",
+			want: false,
+		},
+		{
+			name: "",
+			path: "rpmdb.sqlite",
+			want: false,
+		},
+		{
+			name: "",
+			path: "/var/lib/rpm/Packages",
+			want: true,
+		},
+		{
+			name: "",
+			path: "/var/lib/rpm/rpmdb.sqlite",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/rootfs/var/lib/rpm/rpmdb.sqlite",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/rootfs/usr/lib/sysimage/rpm/Packages",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/rootfs/usr/lib/sysimage/rpm/rpmdb.sqlite",
+			want: true,
+		},
+		{
+			name: "",
+			path: "path/to/rootfs/var/lib/rpm/rpmdb.sqlite-shm",
+			want: false,
+		},
+		{
+			name: "",
+			path: "path/to/rootfs/var/lib/myrpm/Packages",
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e := lockfile.RpmDatabaseExtractor{}
+			got := e.ShouldExtract(tt.path)
+			if got != tt.want {
+				t.Errorf("ShouldExtract() - got %v, expected %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRpmDatabase_FileDoesNotExist(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseRpmDatabase("fixtures/rpm/does-not-exist")
+
+	expectErrIs(t, err, fs.ErrNotExist)
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseRpmDatabase_Empty(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseRpmDatabase("fixtures/rpm/empty")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseRpmDatabase_NotADatabase(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseRpmDatabase("fixtures/rpm/not-a-database")
+
+	expectErrContaining(t, err, "could not extract from")
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseRpmDatabase_Sqlite(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseRpmDatabase("fixtures/rpm/rpmdb.sqlite")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "bash",
+			Version:   "5.1.8-6.el9_1",
+			Ecosystem: lockfile.RedHatEcosystem,
+			CompareAs: lockfile.RedHatEcosystem,
+		},
+		{
+			Name:      "glibc",
+			Version:   "2.34-83.el9.7",
+			Ecosystem: lockfile.RedHatEcosystem,
+			CompareAs: lockfile.RedHatEcosystem,
+		},
+		{
+			Name:      "openssl-libs",
+			Version:   "1:3.0.7-24.el9",
+			Ecosystem: lockfile.RedHatEcosystem,
+			CompareAs: lockfile.RedHatEcosystem,
+		},
+	})
+}
+
+func TestParseRpmDatabase_SqliteWithManyPackages(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseRpmDatabase("fixtures/rpm/many-packages.sqlite")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	if len(packages) != 300 {
+		t.Errorf("Expected to get 300 packages, but got %d", len(packages))
+	}
+}
+
+func TestParseRpmDatabase_BerkeleyDB(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseRpmDatabase("fixtures/rpm/Packages")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "bash",
+			Version:   "5.1.8-6.el9_1",
+			Ecosystem: lockfile.RedHatEcosystem,
+			CompareAs: lockfile.RedHatEcosystem,
+		},
+		{
+			Name:      "glibc",
+			Version:   "2.34-83.el9.7",
+			Ecosystem: lockfile.RedHatEcosystem,
+			CompareAs: lockfile.RedHatEcosystem,
+		},
+		{
+			Name:      "openssl-libs",
+			Version:   "1:3.0.7-24.el9",
+			Ecosystem: lockfile.RedHatEcosystem,
+			CompareAs: lockfile.RedHatEcosystem,
+		},
+	})
+}
+
+func TestParseRpmDatabase_BerkeleyDBWithInlineHeader(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseRpmDatabase("fixtures/rpm/inline/Packages")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "bash",
+			Version:   "5.1.8-6.el9_1",
+			Ecosystem: lockfile.RedHatEcosystem,
+			CompareAs: lockfile.RedHatEcosystem,
+		},
+	})
+}
+
+func TestParseRpmDatabase_WithRockyLinuxRelease(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseRpmDatabase("fixtures/rpm/rocky/var/lib/rpm/rpmdb.sqlite")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "bash",
+			Version:   "5.1.8-6.el9_1",
+			Ecosystem: "Rocky Linux:9",
+			CompareAs: lockfile.RockyLinuxEcosystem,
+		},
+		{
+			Name:      "glibc",
+			Version:   "2.34-83.el9.7",
+			Ecosystem: "Rocky Linux:9",
+			CompareAs: lockfile.RockyLinuxEcosystem,
+		},
+		{
+			Name:      "openssl-libs",
+			Version:   "1:3.0.7-24.el9",
+			Ecosystem: "Rocky Linux:9",
+			CompareAs: lockfile.RockyLinuxEcosystem,
+		},
+	})
+}
+
+func TestParseRpmDatabase_WithCentOSRelease(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseRpmDatabase("fixtures/rpm/centos/var/lib/rpm/Packages")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "bash",
+			Version:   "5.1.8-6.el9_1",
+			Ecosystem: lockfile.RedHatEcosystem,
+			CompareAs: lockfile.RedHatEcosystem,
+		},
+		{
+			Name:      "glibc",
+			Version:   "2.34-83.el9.7",
+			Ecosystem: lockfile.RedHatEcosystem,
+			CompareAs: lockfile.RedHatEcosystem,
+		},
+		{
+			Name:      "openssl-libs",
+			Version:   "1:3.0.7-24.el9",
+			Ecosystem: lockfile.RedHatEcosystem,
+			CompareAs: lockfile.RedHatEcosystem,
+		},
+	})
+}
+
+func TestParseRpmDatabase_WithOpenSUSERelease(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseRpmDatabase("fixtures/rpm/opensuse/usr/lib/sysimage/rpm/rpmdb.sqlite")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "bash",
+			Version:   "5.1.8-6.el9_1",
+			Ecosystem: "openSUSE:Leap 15.5",
+			CompareAs: lockfile.OpenSUSEEcosystem,
+		},
+		{
+			Name:      "glibc",
+			Version:   "2.34-83.el9.7",
+			Ecosystem: "openSUSE:Leap 15.5",
+			CompareAs: lockfile.OpenSUSEEcosystem,
+		},
+		{
+			Name:      "openssl-libs",
+			Version:   "1:3.0.7-24.el9",
+			Ecosystem: "openSUSE:Leap 15.5",
+			CompareAs: lockfile.OpenSUSEEcosystem,
+		},
+	})
+}
+
+func TestFromRpmDatabase(t *testing.T) {
+	t.Parallel()
+
+	lockf, err := lockfile.FromRpmDatabase("fixtures/rpm/rpmdb.sqlite")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	if lockf.ParsedAs != "rpm-db" {
+		t.Errorf("Expected ParsedAs to be \"rpm-db\" but got \"%s\"", lockf.ParsedAs)
+	}
+
+	if len(lockf.Packages) != 3 || lockf.Packages[0].Name != "bash" || lockf.Packages[2].Name != "openssl-libs" {
+		t.Errorf("Expected packages to be sorted by name, but got %v", lockf.Packages)
+	}
+}
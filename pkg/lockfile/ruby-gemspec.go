@@ -0,0 +1,53 @@
+package lockfile
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/google/osv-scanner/internal/cachedregexp"
+)
+
+type GemspecExtractor struct{}
+
+// ShouldExtract returns whether the file is the specification that RubyGems writes
+// for each gem that it installs, which are in the "specifications" directory of a gem home
+func (e GemspecExtractor) ShouldExtract(path string) bool {
+	return filepath.Ext(path) == ".gemspec" && filepath.Base(filepath.Dir(path)) == "specifications"
+}
+
+// Extract extracts the gem that an installed specification is for, which RubyGems writes with
+// the name and version set as strings, like `s.name = "rack".freeze`
+func (e GemspecExtractor) Extract(f DepFile) ([]PackageDetails, error) {
+	b, err := io.ReadAll(f)
+	if err != nil {
+		return []PackageDetails{}, fmt.Errorf("could not extract from %s: %w", f.Path(), err)
+	}
+
+	name := cachedregexp.MustCompile(`(?m)^\s*\w+\.name\s*=\s*["']([^"']+)["']`).FindSubmatch(b)
+	version := cachedregexp.MustCompile(`(?m)^\s*\w+\.version\s*=\s*["']([^"']+)["']`).FindSubmatch(b)
+
+	if name == nil || version == nil {
+		return []PackageDetails{}, nil
+	}
+
+	return []PackageDetails{{
+		Name:      string(name[1]),
+		Version:   string(version[1]),
+		Ecosystem: BundlerEcosystem,
+		CompareAs: BundlerEcosystem,
+	}}, nil
+}
+
+var _ Extractor = GemspecExtractor{}
+
+func ParseGemspec(pathToGemspec string) ([]PackageDetails, error) {
+	return extractFromFile(pathToGemspec, GemspecExtractor{})
+}
+
+// FromGemspec attempts to parse the given file as the specification of an installed gem
+func FromGemspec(pathToGemspec string) (Lockfile, error) {
+	packages, err := ParseGemspec(pathToGemspec)
+
+	return artifactLockfile(pathToGemspec, "gemspec", packages), err
+}
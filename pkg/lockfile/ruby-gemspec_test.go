@@ -0,0 +1,115 @@
+package lockfile_test
+
+import (
+	"io/fs"
+	"testing"
+
+	"github.com/google/osv-scanner/pkg/lockfile"
+)
+
+func TestGemspecExtractor_ShouldExtract(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{
+			name: "",
+			path: "",
+			want: false,
+		},
+		{
+			name: "",
+			path: "rack.gemspec",
+			want: false,
+		},
+		{
+			name: "",
+			path: "usr/local/bundle/specifications/rack-2.2.3.gemspec",
+			want: true,
+		},
+		{
+			name: "",
+			path: "usr/local/bundle/gems/rack-2.2.3/rack.gemspec",
+			want: false,
+		},
+		{
+			name: "",
+			path: "usr/local/bundle/specifications/rack-2.2.3.gemspec/file",
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e := lockfile.GemspecExtractor{}
+			got := e.ShouldExtract(tt.path)
+			if got != tt.want {
+				t.Errorf("Extract() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseGemspec_FileDoesNotExist(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseGemspec("fixtures/gemspec/does-not-exist")
+
+	expectErrIs(t, err, fs.ErrNotExist)
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseGemspec_NotStrings(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseGemspec("fixtures/gemspec/specifications/invalid.gemspec")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseGemspec_Gem(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseGemspec("fixtures/gemspec/specifications/rack-2.2.3.gemspec")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "rack",
+			Version:   "2.2.3",
+			Ecosystem: lockfile.BundlerEcosystem,
+			CompareAs: lockfile.BundlerEcosystem,
+		},
+	})
+}
+
+func TestParseGemspec_PlatformGem(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseGemspec("fixtures/gemspec/specifications/nokogiri-1.15.4-x86_64-linux.gemspec")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "nokogiri",
+			Version:   "1.15.4",
+			Ecosystem: lockfile.BundlerEcosystem,
+			CompareAs: lockfile.BundlerEcosystem,
+		},
+	})
+}
@@ -0,0 +1,133 @@
+package lockfile
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/google/osv-scanner/internal/cachedregexp"
+)
+
+// BitnamiEcosystem has the advisories of the runtimes of languages, like Node.js and Python,
+// that Bitnami packages, which are not tracked by the ecosystems of the packages of the languages
+const BitnamiEcosystem Ecosystem = "Bitnami"
+
+// runtimeInstallDirs are where runtimes are installed when they are not installed by the package
+// manager of the distribution, like by the official images of languages, which build them from source
+// or install the releases of their upstream. Runtimes in other places are skipped, as distributions
+// patch them without changing their version, so they are scanned as the packages of the distribution
+var runtimeInstallDirs = []string{"usr/local/", "opt/"}
+
+// runtimeNameOf returns which runtime the file is where its version can be read from, which is
+// the binary of node, the binary or library of python, or the release file of a JDK
+func runtimeNameOf(path string) string {
+	path = filepath.ToSlash(path)
+
+	installed := false
+	for _, dir := range runtimeInstallDirs {
+		if strings.HasPrefix(path, dir) || strings.Contains(path, "/"+dir) {
+			installed = true
+		}
+	}
+
+	if !installed {
+		return ""
+	}
+
+	base := filepath.Base(path)
+	dir := filepath.Base(filepath.Dir(path))
+
+	switch {
+	case base == "node" && dir == "bin":
+		return "node"
+	case cachedregexp.MustCompile(`^(?:python|libpython)3\.\d+(?:\.so(?:\.\d+)*)?$`).MatchString(base):
+		return "python"
+	case base == "release" && cachedregexp.MustCompile(`(?i)java|jdk|jre`).MatchString(dir):
+		return "java"
+	}
+
+	return ""
+}
+
+type RuntimeExtractor struct{}
+
+// ShouldExtract returns whether the file is what the version of a runtime of a language can be read
+// from, which is only the case for those installed outside the package manager of the distribution.
+// Symlinks are skipped, as libraries are linked to by the names of their versions, like "libpython3.12.so",
+// so that each runtime is only reported once
+func (e RuntimeExtractor) ShouldExtract(path string) bool {
+	if runtimeNameOf(path) == "" {
+		return false
+	}
+
+	info, err := os.Lstat(path)
+
+	return err != nil || info.Mode()&os.ModeSymlink == 0
+}
+
+// runtimeVersion returns the version of the runtime that the file is of, or an empty string if it is not known
+func runtimeVersion(name string, path string, content []byte) string {
+	switch name {
+	case "node":
+		// the URL of the release that the binary was built for, which process.release reports
+		match := cachedregexp.MustCompile(`nodejs\.org/download/release/v(\d+\.\d+\.\d+)/`).FindSubmatch(content)
+		if match != nil {
+			return string(match[1])
+		}
+	case "python":
+		// the version is stored on its own, which is only told apart from others by starting with the minor version
+		minor := cachedregexp.MustCompile(`3\.\d+`).FindString(filepath.Base(path))
+		match := cachedregexp.MustCompile(`\x00(` + regexp.QuoteMeta(minor) + `\.\d+)\x00`).FindSubmatch(content)
+		if match != nil {
+			return string(match[1])
+		}
+	case "java":
+		// the release file of a JDK has the same format as os-release
+		return parseOSRelease(strings.NewReader(string(content)))["JAVA_VERSION"]
+	}
+
+	return ""
+}
+
+// Extract extracts the version of the runtime of a language that the file is of, which is read from
+// the URL of the release that node was built from, the version string that python has embedded, or the
+// release file of a JDK. Runtimes are reported as their package in the Bitnami ecosystem
+func (e RuntimeExtractor) Extract(f DepFile) ([]PackageDetails, error) {
+	name := runtimeNameOf(f.Path())
+	if name == "" {
+		return []PackageDetails{}, nil
+	}
+
+	content, err := io.ReadAll(f)
+	if err != nil {
+		return []PackageDetails{}, fmt.Errorf("could not extract from %s: %w", f.Path(), err)
+	}
+
+	version := runtimeVersion(name, f.Path(), content)
+	if version == "" {
+		return []PackageDetails{}, nil
+	}
+
+	return []PackageDetails{{
+		Name:      name,
+		Version:   version,
+		Ecosystem: BitnamiEcosystem,
+		CompareAs: BitnamiEcosystem,
+	}}, nil
+}
+
+var _ Extractor = RuntimeExtractor{}
+
+func ParseRuntime(pathToRuntime string) ([]PackageDetails, error) {
+	return extractFromFile(pathToRuntime, RuntimeExtractor{})
+}
+
+// FromRuntime attempts to parse the given file as what the version of a runtime of a language can be read from
+func FromRuntime(pathToRuntime string) (Lockfile, error) {
+	packages, err := ParseRuntime(pathToRuntime)
+
+	return artifactLockfile(pathToRuntime, "runtime", packages), err
+}
@@ -0,0 +1,179 @@
+package lockfile_test
+
+import (
+	"io/fs"
+	"testing"
+
+	"github.com/google/osv-scanner/pkg/lockfile"
+)
+
+func TestRuntimeExtractor_ShouldExtract(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{
+			name: "",
+			path: "",
+			want: false,
+		},
+		{
+			name: "",
+			path: "usr/local/bin/node",
+			want: true,
+		},
+		{
+			name: "",
+			path: "/tmp/osv-scanner-image-1/usr/local/bin/node",
+			want: true,
+		},
+		{
+			name: "",
+			path: "opt/bitnami/node/bin/node",
+			want: true,
+		},
+		{
+			name: "",
+			path: "usr/bin/node",
+			want: false,
+		},
+		{
+			name: "",
+			path: "usr/local/lib/node_modules/node",
+			want: false,
+		},
+		{
+			name: "",
+			path: "usr/local/bin/python3.12",
+			want: true,
+		},
+		{
+			name: "",
+			path: "usr/local/lib/libpython3.12.so.1.0",
+			want: true,
+		},
+		{
+			name: "",
+			path: "usr/local/bin/python3",
+			want: false,
+		},
+		{
+			name: "",
+			path: "usr/local/bin/python3.12-config",
+			want: false,
+		},
+		{
+			name: "",
+			path: "usr/lib/libpython3.11.so.1.0",
+			want: false,
+		},
+		{
+			name: "",
+			path: "opt/java/openjdk/release",
+			want: true,
+		},
+		{
+			name: "",
+			path: "usr/local/app/release",
+			want: false,
+		},
+		{
+			name: "",
+			path: "fixtures/runtime/usr/local/lib/libpython3.12.so",
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e := lockfile.RuntimeExtractor{}
+			got := e.ShouldExtract(tt.path)
+			if got != tt.want {
+				t.Errorf("Extract() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRuntime_FileDoesNotExist(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseRuntime("fixtures/runtime/usr/local/bin/does-not-exist")
+
+	expectErrIs(t, err, fs.ErrNotExist)
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseRuntime_Node(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseRuntime("fixtures/runtime/usr/local/bin/node")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "node",
+			Version:   "20.11.0",
+			Ecosystem: lockfile.BitnamiEcosystem,
+			CompareAs: lockfile.BitnamiEcosystem,
+		},
+	})
+}
+
+func TestParseRuntime_Python(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseRuntime("fixtures/runtime/usr/local/lib/libpython3.12.so.1.0")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "python",
+			Version:   "3.12.1",
+			Ecosystem: lockfile.BitnamiEcosystem,
+			CompareAs: lockfile.BitnamiEcosystem,
+		},
+	})
+}
+
+func TestParseRuntime_PythonWithoutVersion(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseRuntime("fixtures/runtime/usr/local/bin/python3.12")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseRuntime_Java(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseRuntime("fixtures/runtime/opt/java/openjdk/release")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "java",
+			Version:   "17.0.10",
+			Ecosystem: lockfile.BitnamiEcosystem,
+			CompareAs: lockfile.BitnamiEcosystem,
+		},
+	})
+}
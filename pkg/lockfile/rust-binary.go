@@ -0,0 +1,135 @@
+package lockfile
+
+import (
+	"bytes"
+	"compress/zlib"
+	"debug/elf"
+	"debug/macho"
+	"debug/pe"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+var errNoCargoAuditableSection = errors.New("binary does not have the dependencies that cargo-auditable embeds")
+
+// cargoAuditablePackage is a crate in the dependencies that cargo-auditable embeds in a binary
+type cargoAuditablePackage struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	// Source is where the crate is from, which is "local" for the crates of the
+	// workspace of the binary, and "crates.io", "git", or "registry" otherwise
+	Source string `json:"source"`
+}
+
+type cargoAuditableDependencies struct {
+	Packages []cargoAuditablePackage `json:"packages"`
+}
+
+// cargoAuditableSection returns the compressed dependencies that cargo-auditable embeds in an ELF,
+// Mach-O, or PE binary in a section of their own, or nil if the binary does not have the section
+func cargoAuditableSection(r io.ReaderAt) ([]byte, error) {
+	if f, err := elf.NewFile(r); err == nil {
+		if section := f.Section(".dep-v0"); section != nil {
+			return section.Data()
+		}
+
+		return nil, nil
+	}
+
+	if f, err := macho.NewFile(r); err == nil {
+		if section := f.Section("__dep_v0"); section != nil {
+			return section.Data()
+		}
+
+		return nil, nil
+	}
+
+	if f, err := pe.NewFile(r); err == nil {
+		if section := f.Section(".dep-v0"); section != nil {
+			return section.Data()
+		}
+
+		return nil, nil
+	}
+
+	return nil, nil
+}
+
+type RustBinaryExtractor struct{}
+
+// ShouldExtract returns whether the file is a binary that was built by Rust with cargo-auditable,
+// which has to be read to tell as binaries can be named anything
+func (e RustBinaryExtractor) ShouldExtract(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	section, err := cargoAuditableSection(f)
+
+	return err == nil && section != nil
+}
+
+// Extract extracts the crates that a Rust binary was built with from the dependencies that
+// cargo-auditable embeds in it, which are compressed JSON. The crates of the workspace of the binary
+// are not reported, though the crate of the binary itself is if it was installed from a registry,
+// like by `cargo install`
+func (e RustBinaryExtractor) Extract(f DepFile) ([]PackageDetails, error) {
+	b, err := io.ReadAll(f)
+	if err != nil {
+		return []PackageDetails{}, fmt.Errorf("could not extract from %s: %w", f.Path(), err)
+	}
+
+	section, err := cargoAuditableSection(bytes.NewReader(b))
+	if err == nil && section == nil {
+		err = errNoCargoAuditableSection
+	}
+	if err != nil {
+		return []PackageDetails{}, fmt.Errorf("could not extract from %s: %w", f.Path(), err)
+	}
+
+	zr, err := zlib.NewReader(bytes.NewReader(section))
+	if err != nil {
+		return []PackageDetails{}, fmt.Errorf("could not extract from %s: %w", f.Path(), err)
+	}
+	defer zr.Close()
+
+	var dependencies cargoAuditableDependencies
+	if err := json.NewDecoder(zr).Decode(&dependencies); err != nil {
+		return []PackageDetails{}, fmt.Errorf("could not extract from %s: %w", f.Path(), err)
+	}
+
+	packages := make([]PackageDetails, 0, len(dependencies.Packages))
+
+	for _, pkg := range dependencies.Packages {
+		if pkg.Source == "local" {
+			continue
+		}
+
+		packages = append(packages, PackageDetails{
+			Name:      pkg.Name,
+			Version:   pkg.Version,
+			Ecosystem: CargoEcosystem,
+			CompareAs: CargoEcosystem,
+		})
+	}
+
+	return packages, nil
+}
+
+var _ Extractor = RustBinaryExtractor{}
+
+func ParseRustBinary(pathToBinary string) ([]PackageDetails, error) {
+	return extractFromFile(pathToBinary, RustBinaryExtractor{})
+}
+
+// FromRustBinary attempts to parse the given file as a binary built by Rust with cargo-auditable
+func FromRustBinary(pathToBinary string) (Lockfile, error) {
+	packages, err := ParseRustBinary(pathToBinary)
+
+	return artifactLockfile(pathToBinary, "rust-binary", packages), err
+}
@@ -0,0 +1,151 @@
+package lockfile_test
+
+import (
+	"io/fs"
+	"testing"
+
+	"github.com/google/osv-scanner/pkg/lockfile"
+)
+
+func TestRustBinaryExtractor_ShouldExtract(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{
+			name: "",
+			path: "",
+			want: false,
+		},
+		{
+			name: "",
+			path: "fixtures/rust-binary/auditable",
+			want: true,
+		},
+		{
+			name: "",
+			path: "fixtures/rust-binary/not-auditable",
+			want: false,
+		},
+		{
+			name: "",
+			path: "fixtures/rust-binary/not-a-binary.txt",
+			want: false,
+		},
+		{
+			name: "",
+			path: "fixtures/rust-binary/does-not-exist",
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e := lockfile.RustBinaryExtractor{}
+			got := e.ShouldExtract(tt.path)
+			if got != tt.want {
+				t.Errorf("Extract() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRustBinary_FileDoesNotExist(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseRustBinary("fixtures/rust-binary/does-not-exist")
+
+	expectErrIs(t, err, fs.ErrNotExist)
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseRustBinary_NotABinary(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseRustBinary("fixtures/rust-binary/not-a-binary.txt")
+
+	expectErrContaining(t, err, "could not extract from")
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseRustBinary_NotAuditable(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseRustBinary("fixtures/rust-binary/not-auditable")
+
+	expectErrContaining(t, err, "does not have the dependencies that cargo-auditable embeds")
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseRustBinary_Corrupt(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseRustBinary("fixtures/rust-binary/corrupt")
+
+	expectErrContaining(t, err, "could not extract from")
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseRustBinary_Auditable(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseRustBinary("fixtures/rust-binary/auditable")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "regex",
+			Version:   "1.10.2",
+			Ecosystem: lockfile.CargoEcosystem,
+			CompareAs: lockfile.CargoEcosystem,
+		},
+		{
+			Name:      "time",
+			Version:   "0.1.45",
+			Ecosystem: lockfile.CargoEcosystem,
+			CompareAs: lockfile.CargoEcosystem,
+		},
+		{
+			Name:      "cc",
+			Version:   "1.0.83",
+			Ecosystem: lockfile.CargoEcosystem,
+			CompareAs: lockfile.CargoEcosystem,
+		},
+	})
+}
+
+func TestParseRustBinary_Installed(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseRustBinary("fixtures/rust-binary/installed")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "ripgrep",
+			Version:   "14.1.0",
+			Ecosystem: lockfile.CargoEcosystem,
+			CompareAs: lockfile.CargoEcosystem,
+		},
+		{
+			Name:      "grep",
+			Version:   "0.3.1",
+			Ecosystem: lockfile.CargoEcosystem,
+			CompareAs: lockfile.CargoEcosystem,
+		},
+	})
+}
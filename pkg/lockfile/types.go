@@ -17,15 +17,20 @@ type PackageDetailsParser = func(pathToLockfile string) ([]PackageDetails, error
 func (sys Ecosystem) IsDevGroup(groups []string) bool {
 	dev := ""
 	switch sys {
-	case ComposerEcosystem, NpmEcosystem, PipEcosystem, PubEcosystem:
+	case BundlerEcosystem, ComposerEcosystem, NpmEcosystem, PipEcosystem, PubEcosystem:
 		// Also PnpmEcosystem(=NpmEcosystem) and PipenvEcosystem(=PipEcosystem).
 		dev = "dev"
 	case ConanEcosystem:
 		dev = "build-requires"
+	case VcpkgEcosystem:
+		dev = "host"
 	case MavenEcosystem:
 		dev = "test"
-	case AlpineEcosystem, BundlerEcosystem, CargoEcosystem, CRANEcosystem,
-		DebianEcosystem, GoEcosystem, MixEcosystem, NuGetEcosystem:
+	case AlmaLinuxEcosystem, AlpineEcosystem, BazelEcosystem, BioconductorEcosystem, BitnamiEcosystem,
+		CargoEcosystem, CRANEcosystem, DebianEcosystem, DenoLandEcosystem, FedoraEcosystem,
+		GitEcosystem, GoEcosystem, HackageEcosystem, HelmEcosystem, JSREcosystem, MixEcosystem,
+		NuGetEcosystem, OpenSUSEEcosystem, OpenWrtEcosystem, RedHatEcosystem,
+		RockyLinuxEcosystem, SUSEEcosystem, TerraformEcosystem, YoctoEcosystem:
 		// We are not able to report development dependencies for these ecosystems.
 		return false
 	}
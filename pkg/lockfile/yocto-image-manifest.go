@@ -0,0 +1,74 @@
+package lockfile
+
+import (
+	"bufio"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/osv-scanner/internal/cachedregexp"
+)
+
+func ParseYoctoImageManifest(pathToLockfile string) ([]PackageDetails, error) {
+	return extractFromFile(pathToLockfile, YoctoImageManifestExtractor{})
+}
+
+type YoctoImageManifestExtractor struct{}
+
+func (e YoctoImageManifestExtractor) ShouldExtract(path string) bool {
+	return strings.HasSuffix(path, ".manifest")
+}
+
+// Extract extracts the packages of an image from the manifest that Yocto writes alongside it,
+// which has a line with the name, architecture, and version of each package
+func (e YoctoImageManifestExtractor) Extract(f DepFile) ([]PackageDetails, error) {
+	// the version of the package includes the revision of its recipe, e.g. "1.36.1-r0"
+	re := cachedregexp.MustCompile(`^(\S+)\s+\S+\s+(\S+?)(?:-r\d+(?:\.\d+)*)?$`)
+
+	scanner := bufio.NewScanner(f)
+
+	packages := make([]PackageDetails, 0)
+
+	for scanner.Scan() {
+		match := re.FindStringSubmatch(strings.TrimSpace(scanner.Text()))
+
+		if match == nil {
+			continue
+		}
+
+		packages = append(packages, PackageDetails{
+			Name:      match[1],
+			Version:   match[2],
+			Ecosystem: YoctoEcosystem,
+			CompareAs: YoctoEcosystem,
+		})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return packages, fmt.Errorf("error while scanning %s: %w", f.Path(), err)
+	}
+
+	return packages, nil
+}
+
+var _ Extractor = YoctoImageManifestExtractor{}
+
+// FromYoctoImageManifest attempts to parse the given file as a "yocto-image-manifest"
+// lockfile, which Yocto writes alongside an image to record the packages in it.
+func FromYoctoImageManifest(pathToManifest string) (Lockfile, error) {
+	packages, err := ParseYoctoImageManifest(pathToManifest)
+
+	sort.Slice(packages, func(i, j int) bool {
+		if packages[i].Name == packages[j].Name {
+			return packages[i].Version < packages[j].Version
+		}
+
+		return packages[i].Name < packages[j].Name
+	})
+
+	return Lockfile{
+		FilePath: pathToManifest,
+		ParsedAs: "yocto-image-manifest",
+		Packages: packages,
+	}, err
+}
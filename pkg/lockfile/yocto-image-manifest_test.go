@@ -0,0 +1,138 @@
+package lockfile_test
+
+import (
+	"io/fs"
+	"testing"
+
+	"github.com/google/osv-scanner/pkg/lockfile"
+)
+
+func TestParseYoctoImageManifest_FileDoesNotExist(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseYoctoImageManifest("fixtures/yocto/does-not-exist")
+
+	expectErrIs(t, err, fs.ErrNotExist)
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseYoctoImageManifest_Empty(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseYoctoImageManifest("fixtures/yocto/empty.manifest")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseYoctoImageManifest_NotAManifest(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseYoctoImageManifest("fixtures/yocto/not-a-manifest.manifest")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{})
+}
+
+func TestParseYoctoImageManifest_OnePackage(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseYoctoImageManifest("fixtures/yocto/one-package.manifest")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "busybox",
+			Version:   "1.36.1",
+			Ecosystem: lockfile.YoctoEcosystem,
+			CompareAs: lockfile.YoctoEcosystem,
+		},
+	})
+}
+
+func TestParseYoctoImageManifest_MultiplePackages(t *testing.T) {
+	t.Parallel()
+
+	packages, err := lockfile.ParseYoctoImageManifest("fixtures/yocto/multiple-packages.manifest")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	expectPackages(t, packages, []lockfile.PackageDetails{
+		{
+			Name:      "base-files",
+			Version:   "3.0.14",
+			Ecosystem: lockfile.YoctoEcosystem,
+			CompareAs: lockfile.YoctoEcosystem,
+		},
+		{
+			Name:      "libcrypto3",
+			Version:   "3.1.4",
+			Ecosystem: lockfile.YoctoEcosystem,
+			CompareAs: lockfile.YoctoEcosystem,
+		},
+		{
+			Name:      "libssl3",
+			Version:   "3.1.4",
+			Ecosystem: lockfile.YoctoEcosystem,
+			CompareAs: lockfile.YoctoEcosystem,
+		},
+		{
+			Name:      "zlib",
+			Version:   "1.3",
+			Ecosystem: lockfile.YoctoEcosystem,
+			CompareAs: lockfile.YoctoEcosystem,
+		},
+	})
+}
+
+func TestFromYoctoImageManifest_MultiplePackages(t *testing.T) {
+	t.Parallel()
+
+	lockf, err := lockfile.FromYoctoImageManifest("fixtures/yocto/multiple-packages.manifest")
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %v", err)
+	}
+
+	if lockf.ParsedAs != "yocto-image-manifest" {
+		t.Errorf("Expected ParsedAs to be \"yocto-image-manifest\" but got \"%s\"", lockf.ParsedAs)
+	}
+
+	expectPackages(t, lockf.Packages, []lockfile.PackageDetails{
+		{
+			Name:      "base-files",
+			Version:   "3.0.14",
+			Ecosystem: lockfile.YoctoEcosystem,
+			CompareAs: lockfile.YoctoEcosystem,
+		},
+		{
+			Name:      "libcrypto3",
+			Version:   "3.1.4",
+			Ecosystem: lockfile.YoctoEcosystem,
+			CompareAs: lockfile.YoctoEcosystem,
+		},
+		{
+			Name:      "libssl3",
+			Version:   "3.1.4",
+			Ecosystem: lockfile.YoctoEcosystem,
+			CompareAs: lockfile.YoctoEcosystem,
+		},
+		{
+			Name:      "zlib",
+			Version:   "1.3",
+			Ecosystem: lockfile.YoctoEcosystem,
+			CompareAs: lockfile.YoctoEcosystem,
+		},
+	})
+}
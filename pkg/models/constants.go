@@ -16,6 +16,7 @@ const (
 	EcosystemDebian        Ecosystem = "Debian"
 	EcosystemAlpine        Ecosystem = "Alpine"
 	EcosystemHex           Ecosystem = "Hex"
+	EcosystemHackage       Ecosystem = "Hackage"
 	EcosystemAndroid       Ecosystem = "Android"
 	EcosystemGitHubActions Ecosystem = "GitHub Actions"
 	EcosystemPub           Ecosystem = "Pub"
@@ -43,6 +44,7 @@ var Ecosystems = []Ecosystem{
 	EcosystemDebian,
 	EcosystemAlpine,
 	EcosystemHex,
+	EcosystemHackage,
 	EcosystemAndroid,
 	EcosystemGitHubActions,
 	EcosystemPub,
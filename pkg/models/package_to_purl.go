@@ -0,0 +1,80 @@
+package models
+
+import (
+	"strings"
+
+	"github.com/package-url/packageurl-go"
+)
+
+// ecosystemPURLTypes are the types of Package URLs for the packages of each ecosystem,
+// used like so: ecosystemPURLTypes[Ecosystem]
+var ecosystemPURLTypes = map[Ecosystem]string{
+	EcosystemAlmaLinux:   "rpm",
+	EcosystemAlpine:      "apk",
+	EcosystemCRAN:        "cran",
+	EcosystemConanCenter: "conan",
+	EcosystemCratesIO:    "cargo",
+	EcosystemDebian:      "deb",
+	EcosystemGo:          "golang",
+	EcosystemHackage:     "hackage",
+	EcosystemHex:         "hex",
+	EcosystemMaven:       "maven",
+	EcosystemNPM:         "npm",
+	EcosystemNuGet:       "nuget",
+	EcosystemPackagist:   "composer",
+	EcosystemPub:         "pub",
+	EcosystemPyPI:        "pypi",
+	EcosystemRockyLinux:  "rpm",
+	EcosystemRubyGems:    "gem",
+}
+
+// ecosystemPURLNamespaces are the namespaces of the Package URLs of the packages
+// of Linux distributions, which is the name of the distribution
+var ecosystemPURLNamespaces = map[Ecosystem]string{
+	EcosystemAlmaLinux:  "almalinux",
+	EcosystemAlpine:     "alpine",
+	EcosystemDebian:     "debian",
+	EcosystemRockyLinux: "rocky",
+}
+
+// PackageToPURL converts a package to its Package URL, which has a distro qualifier
+// for packages of a release of a Linux distribution, such as "Debian:12", or returns
+// an empty string if the ecosystem of the package does not have a Package URL type
+func PackageToPURL(pkg PackageInfo) string {
+	ecosystem, release, _ := strings.Cut(pkg.Ecosystem, ":")
+
+	purlType, ok := ecosystemPURLTypes[Ecosystem(ecosystem)]
+	if !ok || pkg.Name == "" {
+		return ""
+	}
+
+	namespace := ecosystemPURLNamespaces[Ecosystem(ecosystem)]
+	name := pkg.Name
+	version := pkg.Version
+	var qualifiers packageurl.Qualifiers
+
+	switch Ecosystem(ecosystem) { //nolint:exhaustive
+	case EcosystemMaven:
+		// Maven uses : to separate namespace and package
+		namespace, name, _ = strings.Cut(pkg.Name, ":")
+	case EcosystemGo, EcosystemNPM, EcosystemPackagist:
+		if i := strings.LastIndex(pkg.Name, "/"); i >= 0 {
+			namespace, name = pkg.Name[:i], pkg.Name[i+1:]
+		}
+	case EcosystemAlmaLinux, EcosystemRockyLinux:
+		// rpm versions include their epoch, which is a qualifier in PURL
+		if epoch, rest, ok := strings.Cut(version, ":"); ok {
+			version = rest
+			qualifiers = append(qualifiers, packageurl.Qualifier{Key: "epoch", Value: epoch})
+		}
+	}
+
+	if release != "" && namespace != "" {
+		qualifiers = append(qualifiers, packageurl.Qualifier{
+			Key:   "distro",
+			Value: namespace + "-" + strings.TrimPrefix(release, "v"),
+		})
+	}
+
+	return packageurl.NewPackageURL(purlType, namespace, name, version, qualifiers, "").ToString()
+}
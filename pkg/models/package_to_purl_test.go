@@ -0,0 +1,83 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+func TestPackageToPURL(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		pkg  models.PackageInfo
+		want string
+	}{
+		{
+			name: "crates.io",
+			pkg:  models.PackageInfo{Name: "regex", Version: "1.5.1", Ecosystem: "crates.io"},
+			want: "pkg:cargo/regex@1.5.1",
+		},
+		{
+			name: "Go",
+			pkg:  models.PackageInfo{Name: "github.com/gogo/protobuf", Version: "1.3.1", Ecosystem: "Go"},
+			want: "pkg:golang/github.com/gogo/protobuf@1.3.1",
+		},
+		{
+			name: "npm scoped",
+			pkg:  models.PackageInfo{Name: "@babel/core", Version: "7.24.0", Ecosystem: "npm"},
+			want: "pkg:npm/%40babel/core@7.24.0",
+		},
+		{
+			name: "Maven",
+			pkg:  models.PackageInfo{Name: "org.hdrhistogram:HdrHistogram", Version: "2.1.12", Ecosystem: "Maven"},
+			want: "pkg:maven/org.hdrhistogram/HdrHistogram@2.1.12",
+		},
+		{
+			name: "Debian release",
+			pkg:  models.PackageInfo{Name: "openssl", Version: "3.0.11-1~deb12u2", Ecosystem: "Debian:12"},
+			want: "pkg:deb/debian/openssl@3.0.11-1~deb12u2?distro=debian-12",
+		},
+		{
+			name: "Alpine release",
+			pkg:  models.PackageInfo{Name: "zlib", Version: "1.2.13-r0", Ecosystem: "Alpine:v3.17"},
+			want: "pkg:apk/alpine/zlib@1.2.13-r0?distro=alpine-3.17",
+		},
+		{
+			name: "Rocky Linux with epoch",
+			pkg:  models.PackageInfo{Name: "openssl-libs", Version: "1:3.0.7-24.el9", Ecosystem: "Rocky Linux:9"},
+			want: "pkg:rpm/rocky/openssl-libs@3.0.7-24.el9?epoch=1&distro=rocky-9",
+		},
+		{
+			name: "unsupported ecosystem",
+			pkg:  models.PackageInfo{Name: "actions/checkout", Version: "v4", Ecosystem: "GitHub Actions"},
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := models.PackageToPURL(tt.pkg)
+
+			if got != tt.want {
+				t.Errorf("PackageToPURL() = %v, want %v", got, tt.want)
+			}
+
+			// packages that have a PURL should be able to be converted back
+			if got == "" {
+				return
+			}
+
+			pkg, err := models.PURLToPackage(got)
+			if err != nil {
+				t.Fatalf("PURLToPackage() returned an error: %v", err)
+			}
+			if pkg != tt.pkg {
+				t.Errorf("PURLToPackage() = %v, want %v", pkg, tt.pkg)
+			}
+		})
+	}
+}
@@ -1,6 +1,9 @@
 package models
 
 import (
+	"regexp"
+	"strings"
+
 	"github.com/package-url/packageurl-go"
 )
 
@@ -8,6 +11,7 @@ import (
 // * means it should match any namespace string
 var purlEcosystems = map[string]map[string]Ecosystem{
 	"apk":      {"alpine": EcosystemAlpine},
+	"rpm":      {"almalinux": EcosystemAlmaLinux, "rocky": EcosystemRockyLinux},
 	"cargo":    {"*": EcosystemCratesIO},
 	"deb":      {"debian": EcosystemDebian},
 	"hex":      {"*": EcosystemHex},
@@ -40,6 +44,51 @@ func getPURLEcosystem(pkgURL packageurl.PackageURL) Ecosystem {
 	return ecosystem
 }
 
+// debianCodenames are the major versions of the releases of Debian that have a codename
+// which can be used as the distro qualifier instead of the version
+var debianCodenames = map[string]string{
+	"buster":   "10",
+	"bullseye": "11",
+	"bookworm": "12",
+	"trixie":   "13",
+}
+
+var purlDistroVersionRe = regexp.MustCompile(`^(\d+)(?:\.(\d+))?`)
+
+// getPURLReleaseEcosystem returns the ecosystem for the release of the distribution
+// that the distro qualifier of the Package URL is for, such as "Alpine:v3.17" for
+// "alpine-3.17.2", or the ecosystem itself if there is no distro qualifier
+func getPURLReleaseEcosystem(ecosystem Ecosystem, pkgURL packageurl.PackageURL) Ecosystem {
+	distro := pkgURL.Qualifiers.Map()["distro"]
+
+	// the distro can be prefixed with the name of the distribution, like "debian-12"
+	if name, version, ok := strings.Cut(distro, "-"); ok && strings.EqualFold(name, pkgURL.Namespace) {
+		distro = version
+	}
+
+	if codename, ok := debianCodenames[distro]; ok && ecosystem == EcosystemDebian {
+		distro = codename
+	}
+
+	matches := purlDistroVersionRe.FindStringSubmatch(distro)
+	if matches == nil {
+		return ecosystem
+	}
+
+	switch ecosystem { //nolint:exhaustive
+	case EcosystemAlpine:
+		if matches[2] == "" {
+			return ecosystem
+		}
+
+		return Ecosystem(string(ecosystem) + ":v" + matches[1] + "." + matches[2])
+	case EcosystemDebian, EcosystemAlmaLinux, EcosystemRockyLinux:
+		return Ecosystem(string(ecosystem) + ":" + matches[1])
+	}
+
+	return ecosystem
+}
+
 // PURLToPackage converts a Package URL string to models.PackageInfo
 func PURLToPackage(purl string) (PackageInfo, error) {
 	parsedPURL, err := packageurl.FromString(purl)
@@ -55,17 +104,24 @@ func PURLToPackage(purl string) (PackageInfo, error) {
 		case EcosystemMaven:
 			// Maven uses : to separate namespace and package
 			name = parsedPURL.Namespace + ":" + parsedPURL.Name
-		case EcosystemDebian, EcosystemAlpine:
-			// Debian and Alpine repeats their namespace in PURL, so don't add it to the name
+		case EcosystemDebian, EcosystemAlpine, EcosystemAlmaLinux, EcosystemRockyLinux:
+			// Linux distributions repeat their namespace in PURL, so don't add it to the name
 			name = parsedPURL.Name
 		default:
 			name = parsedPURL.Namespace + "/" + parsedPURL.Name
 		}
 	}
 
+	version := parsedPURL.Version
+
+	// rpm versions include their epoch, which is a qualifier in PURL
+	if epoch := parsedPURL.Qualifiers.Map()["epoch"]; parsedPURL.Type == "rpm" && epoch != "" && epoch != "0" {
+		version = epoch + ":" + version
+	}
+
 	return PackageInfo{
 		Name:      name,
-		Ecosystem: string(ecosystem),
-		Version:   parsedPURL.Version,
+		Ecosystem: string(getPURLReleaseEcosystem(ecosystem, parsedPURL)),
+		Version:   version,
 	}, nil
 }
@@ -67,12 +67,56 @@ func TestPURLToPackage(t *testing.T) {
 			args: args{
 				purl: "pkg:apk/alpine/zlib@1.2.13-r0?arch=x86_64upstream=zlib&distro=alpine-3.17.2",
 			},
+			want: models.PackageInfo{
+				Name:      "zlib",
+				Version:   "1.2.13-r0",
+				Ecosystem: "Alpine:v3.17",
+			},
+		},
+		{
+			name: "valid PURL alpine without distro",
+			args: args{
+				purl: "pkg:apk/alpine/zlib@1.2.13-r0?arch=x86_64",
+			},
 			want: models.PackageInfo{
 				Name:      "zlib",
 				Version:   "1.2.13-r0",
 				Ecosystem: string(models.EcosystemAlpine),
 			},
 		},
+		{
+			name: "valid PURL Debian with distro",
+			args: args{
+				purl: "pkg:deb/debian/openssl@3.0.11-1~deb12u2?arch=amd64&distro=debian-12",
+			},
+			want: models.PackageInfo{
+				Name:      "openssl",
+				Version:   "3.0.11-1~deb12u2",
+				Ecosystem: "Debian:12",
+			},
+		},
+		{
+			name: "valid PURL Debian with distro codename",
+			args: args{
+				purl: "pkg:deb/debian/openssl@3.0.11-1~deb12u2?distro=bookworm",
+			},
+			want: models.PackageInfo{
+				Name:      "openssl",
+				Version:   "3.0.11-1~deb12u2",
+				Ecosystem: "Debian:12",
+			},
+		},
+		{
+			name: "valid PURL Rocky Linux",
+			args: args{
+				purl: "pkg:rpm/rocky/openssl-libs@3.0.7-24.el9?arch=x86_64&epoch=1&distro=rocky-9.3",
+			},
+			want: models.PackageInfo{
+				Name:      "openssl-libs",
+				Version:   "1:3.0.7-24.el9",
+				Ecosystem: "Rocky Linux:9",
+			},
+		},
 		{
 			name: "invalid PURL",
 			args: args{
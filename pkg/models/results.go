@@ -9,6 +9,85 @@ import (
 type VulnerabilityResults struct {
 	Results                    []PackageSource            `json:"results"`
 	ExperimentalAnalysisConfig ExperimentalAnalysisConfig `json:"experimental_config"`
+	// Images are the distributions and the runtimes of languages that were detected in the container images that were scanned
+	Images []ImageDetails `json:"images,omitempty"`
+	// BaseImages are the base images of the container images that were scanned, when they could be detected
+	BaseImages []BaseImage `json:"base_images,omitempty"`
+	// PlatformDifferences are the differences between the platforms of the container images
+	// that were scanned for more than one of the platforms that they are built for
+	PlatformDifferences []PlatformDifferences `json:"platform_differences,omitempty"`
+	// Workloads are the workloads of the Kubernetes cluster that was scanned, along with the images that they run
+	Workloads []Workload `json:"workloads,omitempty"`
+}
+
+// Workload is what runs pods in a Kubernetes cluster, such as a Deployment
+type Workload struct {
+	Namespace string          `json:"namespace"`
+	Kind      string          `json:"kind"`
+	Name      string          `json:"name"`
+	Images    []WorkloadImage `json:"images"`
+}
+
+// WorkloadImage is an image that the pods of a workload are running
+type WorkloadImage struct {
+	Image string `json:"image"`
+	// Vulnerabilities are the IDs of the vulnerabilities of the packages of the image
+	Vulnerabilities []string `json:"vulnerabilities"`
+}
+
+// PlatformDifferences are the vulnerabilities that only some of the platforms that a container
+// image was scanned for have, as the packages of each platform often differ
+type PlatformDifferences struct {
+	Image     string   `json:"image"`
+	Platforms []string `json:"platforms"`
+	// Vulnerabilities are the vulnerabilities of packages that not every platform has
+	Vulnerabilities []PlatformVulnerability `json:"vulnerabilities"`
+}
+
+// PlatformVulnerability is a vulnerability of a package of a container image that only some of its platforms have
+type PlatformVulnerability struct {
+	ID        string   `json:"id"`
+	Package   string   `json:"package"`
+	Platforms []string `json:"platforms"`
+}
+
+// ImageDetails are what a scanned container image was detected to be made up of
+type ImageDetails struct {
+	Image string `json:"image"`
+	// OS is the release of the distribution of the image, like "Debian GNU/Linux 12 (bookworm)",
+	// which is empty if it could not be detected, such as for images that are built from scratch
+	OS       string         `json:"os,omitempty"`
+	Runtimes []ImageRuntime `json:"runtimes,omitempty"`
+}
+
+// ImageRuntime is a runtime of a language that was installed in a container image
+// without the package manager of its distribution, like by the official image of the language
+type ImageRuntime struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	// Path is where the version of the runtime was read from
+	Path string `json:"path"`
+}
+
+// BaseImage is the image that a scanned container image was built on
+type BaseImage struct {
+	// Image is the reference of the container image that was scanned
+	Image string `json:"image"`
+	Name  string `json:"name"`
+	// Layers is how many of the layers of the container image are from the base image
+	Layers int `json:"layers"`
+	// Vulnerabilities are the IDs of the vulnerabilities of the packages introduced by the base image
+	Vulnerabilities []string `json:"vulnerabilities"`
+	// Recommendation is the newer tag of the base image that removes the most of its vulnerabilities,
+	// which is not set if none of them remove any
+	Recommendation *BaseImageRecommendation `json:"recommendation,omitempty"`
+}
+
+// BaseImageRecommendation is a newer tag of a base image to rebuild a container image on
+type BaseImageRecommendation struct {
+	Name string `json:"name"`
+	// Fixed are the IDs of the vulnerabilities of the current base image that the newer one does not have
+	Fixed []string `json:"fixed"`
 }
 
 // ExperimentalAnalysisConfig is an experimental type intended to contain the
@@ -73,6 +152,8 @@ type VulnerabilityFlattened struct {
 type SourceInfo struct {
 	Path string `json:"path"`
 	Type string `json:"type"`
+	// Attestation is "verified" if the source is an SBOM whose attestation was verified before it was scanned
+	Attestation string `json:"attestation,omitempty"`
 }
 
 type Metadata struct {
@@ -102,6 +183,20 @@ type PackageVulns struct {
 	Groups            []GroupInfo     `json:"groups,omitempty"`
 	Licenses          []License       `json:"licenses,omitempty"`
 	LicenseViolations []License       `json:"license_violations,omitempty"`
+	// ImageOrigin is the layer that introduced the package, when it was found in a container image
+	ImageOrigin *ImageOrigin `json:"image_origin,omitempty"`
+}
+
+// ImageOrigin is the layer of a container image that a package was introduced in,
+// which tells packages of the base image apart from those added on top of it
+type ImageOrigin struct {
+	// Layer is the number of the layer in the image, counting from 1 for its bottom layer
+	Layer       int    `json:"layer"`
+	LayerDigest string `json:"layer_digest"`
+	// Command is the Dockerfile instruction that created the layer, if the image recorded it
+	Command string `json:"command,omitempty"`
+	// InBaseImage is true if the layer is from the base image that the container image was built on
+	InBaseImage bool `json:"in_base_image,omitempty"`
 }
 
 type GroupInfo struct {
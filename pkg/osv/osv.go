@@ -41,11 +41,13 @@ type Package struct {
 
 // Query represents a query to OSV.
 type Query struct {
-	Commit   string            `json:"commit,omitempty"`
-	Package  Package           `json:"package,omitempty"`
-	Version  string            `json:"version,omitempty"`
-	Source   models.SourceInfo `json:"-"` // TODO: Move this into Info struct in v2
-	Metadata models.Metadata   `json:"-"`
+	Commit  string  `json:"commit,omitempty"`
+	Package Package `json:"package,omitempty"`
+	Version string  `json:"version,omitempty"`
+	// PageToken requests the next page of results of a previous query with too many results for one response
+	PageToken string            `json:"page_token,omitempty"`
+	Source    models.SourceInfo `json:"-"` // TODO: Move this into Info struct in v2
+	Metadata  models.Metadata   `json:"-"`
 }
 
 // BatchedQuery represents a batched query to OSV.
@@ -66,6 +68,8 @@ type Response struct {
 // MinimalResponse represents an unhydrated response from OSV.
 type MinimalResponse struct {
 	Vulns []MinimalVulnerability `json:"vulns"`
+	// NextPageToken is set if there are more results, which can be requested by repeating the query with the token
+	NextPageToken string `json:"next_page_token,omitempty"`
 }
 
 // BatchedResponse represents an unhydrated batched response from OSV.
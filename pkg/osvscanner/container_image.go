@@ -0,0 +1,736 @@
+package osvscanner
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/google/osv-scanner/internal/image"
+	"github.com/google/osv-scanner/internal/output"
+	"github.com/google/osv-scanner/internal/sbom"
+	"github.com/google/osv-scanner/pkg/lockfile"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/reporter"
+)
+
+// imagePath returns the path in the filesystem of an image of a file from where it has been extracted to dir
+func imagePath(dir string, path string) string {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return path
+	}
+
+	return "/" + filepath.ToSlash(rel)
+}
+
+// imageSourcePath returns the path of a file in the filesystem of an image that has been
+// extracted to dir, in the form that `docker cp` uses for a path in a container
+func imageSourcePath(imageRef string, dir string, path string) string {
+	return imageRef + ":" + imagePath(dir, path)
+}
+
+// packageKey identifies a package of a lockfile across the layers of an image
+func packageKey(pkg scannedPackage) string {
+	return pkg.Name + "@" + pkg.Version + "@" + pkg.Commit
+}
+
+// scanLayerFile scans a file written by a layer of an image if it is a lockfile or the database
+// of a system package manager, without reporting or resolving anything, as it is only scanned
+// to find the packages that the file has as of the layer
+func scanLayerFile(path string) []scannedPackage {
+	r := &reporter.VoidReporter{}
+	var pkgs []scannedPackage
+
+	if extractor, _ := lockfile.FindExtractor(path, ""); extractor != nil {
+		scanned, _ := scanLockfile(r, path, "", resolutionFetchers{})
+		pkgs = append(pkgs, scanned...)
+	}
+
+	for parseAs, extractor := range systemPackageDatabases {
+		if extractor.ShouldExtract(path) {
+			scanned, _ := scanLockfile(r, path, parseAs, resolutionFetchers{})
+			pkgs = append(pkgs, scanned...)
+		}
+	}
+
+	return pkgs
+}
+
+// layerOrigins tracks the layer of an image that introduced each of the packages in its
+// lockfiles, which is the first layer that wrote the package to the file since it was last
+// removed, as well as the layer that last wrote each file for packages found in other ways
+type layerOrigins struct {
+	// packages are keyed by the path of the lockfile in the image, and then by packageKey
+	packages map[string]map[string]*models.ImageOrigin
+	files    map[string]*models.ImageOrigin
+}
+
+func newLayerOrigins() *layerOrigins {
+	return &layerOrigins{
+		packages: make(map[string]map[string]*models.ImageOrigin),
+		files:    make(map[string]*models.ImageOrigin),
+	}
+}
+
+// record returns a function that records the origins of the packages of the files
+// written by each layer of an image as it is extracted to dir
+func (o *layerOrigins) record(dir string) image.LayerFunc {
+	return func(index int, layer image.Layer, files []string) error {
+		origin := &models.ImageOrigin{
+			Layer:       index + 1,
+			LayerDigest: layer.Digest,
+			Command:     layer.Command,
+		}
+
+		for _, name := range files {
+			o.files[name] = origin
+
+			previous := o.packages[name]
+			current := make(map[string]*models.ImageOrigin)
+
+			for _, pkg := range scanLayerFile(filepath.Join(dir, filepath.FromSlash(name))) {
+				key := packageKey(pkg)
+
+				if earlier, ok := previous[key]; ok {
+					current[key] = earlier
+				} else {
+					current[key] = origin
+				}
+			}
+
+			if len(current) == 0 {
+				delete(o.packages, name)
+			} else {
+				o.packages[name] = current
+			}
+		}
+
+		return nil
+	}
+}
+
+// lookup returns the layer that introduced a package that was found in the file at the path in the image
+func (o *layerOrigins) lookup(name string, pkg scannedPackage) *models.ImageOrigin {
+	if origin, ok := o.packages[name][packageKey(pkg)]; ok {
+		return origin
+	}
+
+	return o.files[name]
+}
+
+// maxBaseImageCandidates is how many of the newest tags of a base image are checked for which to recommend
+// rebuilding on, as each of them has to be pulled and scanned like the image itself
+const maxBaseImageCandidates = 3
+
+// imageBase is the image that a scanned image was built on
+type imageBase struct {
+	imageRef string
+	ref      image.Reference
+	platform image.Platform
+	// layers is how many of the layers of the scanned image are from the base image
+	layers int
+}
+
+// detectBaseImage finds the image that an image was built on from its annotations, and checks
+// that the layers of the image start with the layers of the base image, which they would not
+// if the image has been rebuilt on a different base image without updating its annotations
+func detectBaseImage(ctx context.Context, r reporter.Reporter, client *image.Client, imageRef string, img *image.Image) *imageBase {
+	ref, ok := img.BaseImage()
+	if !ok {
+		r.Infof("Could not determine the base image of %s, as it was built without recording it\n", imageRef)
+		return nil
+	}
+
+	base, err := client.Pull(ctx, ref, img.Platform)
+	if err != nil {
+		r.Warnf("Failed to pull the base image of %s: %v\n", imageRef, err)
+		return nil
+	}
+
+	if len(base.Layers) > len(img.Layers) {
+		r.Warnf("%s is not built on %s, which has more layers than it\n", imageRef, ref)
+		return nil
+	}
+
+	for i, layer := range base.Layers {
+		if img.Layers[i].Digest != layer.Digest {
+			r.Warnf("%s is not built on %s, as layer %d of them differs\n", imageRef, ref, i+1)
+			return nil
+		}
+	}
+
+	return &imageBase{imageRef: imageRef, ref: ref, platform: img.Platform, layers: len(base.Layers)}
+}
+
+// extractAndScanImage extracts the filesystem of an image to a temporary directory and scans it like
+// a directory, recording which layer introduced each of the packages if origins is not nil. The
+// release of the distribution of the image is returned too, which is empty if it cannot be detected
+func extractAndScanImage(
+	ctx context.Context,
+	r reporter.Reporter,
+	client *image.Client,
+	imageRef string,
+	img *image.Image,
+	compareOffline bool,
+	fetchers resolutionFetchers,
+	origins *layerOrigins,
+) ([]scannedPackage, string, error) {
+	dir, err := os.MkdirTemp("", "osv-scanner-image-")
+	if err != nil {
+		return nil, "", err
+	}
+	defer os.RemoveAll(dir)
+
+	var onLayer image.LayerFunc
+	if origins != nil {
+		onLayer = origins.record(dir)
+	}
+
+	if err := client.Extract(ctx, img, dir, onLayer); err != nil {
+		return nil, "", err
+	}
+
+	// the filesystem of an image is not a repository, and has nothing that is ignored by git
+	pkgs, err := scanDir(r, dir, true, true, false, true, compareOffline, fetchers)
+	if err != nil {
+		return nil, "", err
+	}
+
+	for i := range pkgs {
+		if origins != nil {
+			pkgs[i].ImageOrigin = origins.lookup(imagePath(dir, pkgs[i].Source.Path), pkgs[i])
+		}
+		pkgs[i].Source.Path = imageSourcePath(imageRef, dir, pkgs[i].Source.Path)
+	}
+
+	return pkgs, image.OSRelease(dir), nil
+}
+
+// imageDetails returns the distribution and the runtimes of languages that were detected in an image,
+// which are the packages of the Bitnami ecosystem that were found by lockfile.RuntimeExtractor, or
+// nil if neither were detected, such as when an SBOM attested to for the image was scanned instead
+func imageDetails(label string, osRelease string, pkgs []scannedPackage) *models.ImageDetails {
+	details := models.ImageDetails{Image: label, OS: osRelease}
+
+	for _, pkg := range pkgs {
+		if pkg.Ecosystem != lockfile.BitnamiEcosystem {
+			continue
+		}
+
+		details.Runtimes = append(details.Runtimes, models.ImageRuntime{
+			Name:    pkg.Name,
+			Version: pkg.Version,
+			Path:    strings.TrimPrefix(pkg.Source.Path, label+":"),
+		})
+	}
+
+	if details.OS == "" && len(details.Runtimes) == 0 {
+		return nil
+	}
+
+	return &details
+}
+
+// scannedImage is an image that has been scanned for each of the platforms that were selected
+type scannedImage struct {
+	imageRef string
+	// platforms are the platforms that the image was scanned for, which is nil
+	// if it was only scanned for the platform of the machine running the scanner
+	platforms []image.Platform
+	packages  []scannedPackage
+	bases     []imageBase
+	details   []models.ImageDetails
+}
+
+// platformLabel is what the findings of an image that is scanned for more than
+// one platform are reported under, so that the platforms can be told apart
+func platformLabel(imageRef string, platform image.Platform) string {
+	return imageRef + " (" + platform.String() + ")"
+}
+
+// selectPlatforms returns the platforms to scan an image for, which are either those that were
+// selected or all of those that the image is built for, or nil if the image is only built for one
+func selectPlatforms(ctx context.Context, client *image.Client, ref image.Reference, selected []string) ([]image.Platform, error) {
+	if len(selected) == 0 {
+		return nil, nil
+	}
+
+	available, err := client.Platforms(ctx, ref)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the platforms of %s: %w", ref, err)
+	}
+
+	if available == nil || slices.Contains(selected, "all") {
+		return available, nil
+	}
+
+	platforms := make([]image.Platform, 0, len(selected))
+	for _, s := range selected {
+		platform, err := image.ParsePlatform(s)
+		if err != nil {
+			return nil, err
+		}
+
+		platforms = append(platforms, platform)
+	}
+
+	return platforms, nil
+}
+
+// scanImageAttestation scans the SBOM of an attestation that is attached to an image in its registry and
+// is signed by the key, which is either of the manifest of the image for its platform or of its index.
+// It returns false if there is no such attestation, so that the filesystem of the image is scanned instead
+func scanImageAttestation(
+	ctx context.Context,
+	r reporter.Reporter,
+	client *image.Client,
+	label string,
+	img *image.Image,
+	key crypto.PublicKey,
+) ([]scannedPackage, bool) {
+	for _, digest := range []string{img.Digest, img.IndexDigest} {
+		if digest == "" {
+			continue
+		}
+
+		envelopes, err := client.Attestations(ctx, img.Reference, digest)
+		if err != nil {
+			r.Warnf("Failed to fetch the attestations of %s: %v\n", label, err)
+			return nil, false
+		}
+
+		for _, envelope := range envelopes {
+			// images can have attestations that are signed by others, or that are not of an SBOM
+			attestation, err := sbom.ReadAttestation(bytes.NewReader(envelope), key)
+			if err != nil {
+				continue
+			}
+
+			if !slices.Contains(attestation.Subjects, digest) {
+				r.Warnf("Skipping attestation of %s as it is not of %s\n", label, digest)
+				continue
+			}
+
+			// the SBOM is of the whole filesystem of the image, rather than of any one file in it
+			source := models.SourceInfo{Path: label + ":/", Type: "sbom", Attestation: "verified"}
+
+			pkgs, err := scanSBOM(r, source, bytes.NewReader(attestation.SBOM), false)
+			if err != nil {
+				r.Warnf("Failed to scan the %s SBOM attested to for %s: %v\n", attestation.PredicateType, label, err)
+				continue
+			}
+
+			r.Infof("Scanned the %s SBOM attested to for %s instead of its filesystem\n", attestation.PredicateType, label)
+
+			return pkgs, true
+		}
+	}
+
+	r.Infof("Found no SBOM attested to for %s that is signed by the attestation key\n", label)
+
+	return nil, false
+}
+
+// scanImagePlatform pulls the filesystem of an image for a platform from its registry without a Docker
+// daemon, and scans it like a directory for the packages installed by its operating system and the
+// artifacts of languages, along with the base image that it was built on if that can be detected.
+// If there is an attestation key, an SBOM that is attested to for the image is scanned in place of
+// the filesystem, so that the image only has to be pulled if it does not have one
+func scanImagePlatform(
+	ctx context.Context,
+	r reporter.Reporter,
+	client *image.Client,
+	ref image.Reference,
+	label string,
+	platform image.Platform,
+	attestationKey crypto.PublicKey,
+	compareOffline bool,
+	fetchers resolutionFetchers,
+) ([]scannedPackage, *imageBase, *models.ImageDetails, error) {
+	r.Infof("Pulling image %s for %s\n", ref, platform)
+
+	img, err := client.Pull(ctx, ref, platform)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to pull %s: %w", label, err)
+	}
+
+	var pkgs []scannedPackage
+	var osRelease string
+	attested := false
+
+	if attestationKey != nil {
+		pkgs, attested = scanImageAttestation(ctx, r, client, label, img, attestationKey)
+	}
+
+	if !attested {
+		pkgs, osRelease, err = extractAndScanImage(ctx, r, client, label, img, compareOffline, fetchers, newLayerOrigins())
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	base := detectBaseImage(ctx, r, client, label, img)
+	if base != nil {
+		for _, pkg := range pkgs {
+			if pkg.ImageOrigin != nil && pkg.ImageOrigin.Layer <= base.layers {
+				pkg.ImageOrigin.InBaseImage = true
+			}
+		}
+	}
+
+	r.Infof(
+		"Scanned image %s (%s) with %d %s and found %d %s\n",
+		ref,
+		img.Platform,
+		len(img.Layers),
+		output.Form(len(img.Layers), "layer", "layers"),
+		len(pkgs),
+		output.Form(len(pkgs), "package", "packages"),
+	)
+
+	return pkgs, base, imageDetails(label, osRelease, pkgs), nil
+}
+
+// scanImage scans an image for each of the selected platforms, which can include "all" to scan it
+// for every platform that it is built for, or for the platform of the machine running the scanner
+// if none are selected. The findings of each platform are reported under the platform when
+// platforms are selected, like "ghcr.io/org/app:tag (linux/arm64):/var/lib/dpkg/status"
+func scanImage(
+	r reporter.Reporter,
+	imageRef string,
+	selectedPlatforms []string,
+	attestationKey crypto.PublicKey,
+	compareOffline bool,
+	fetchers resolutionFetchers,
+) (scannedImage, error) {
+	ref, err := image.ParseReference(imageRef)
+	if err != nil {
+		return scannedImage{}, err
+	}
+
+	ctx := context.Background()
+	client := image.NewClient()
+
+	platforms, err := selectPlatforms(ctx, client, ref, selectedPlatforms)
+	if err != nil {
+		return scannedImage{}, err
+	}
+
+	scanned := scannedImage{imageRef: imageRef, platforms: platforms}
+
+	if platforms == nil {
+		pkgs, base, details, err := scanImagePlatform(ctx, r, client, ref, imageRef, image.DefaultPlatform(), attestationKey, compareOffline, fetchers)
+		if err != nil {
+			return scannedImage{}, err
+		}
+
+		scanned.packages = pkgs
+		if base != nil {
+			scanned.bases = append(scanned.bases, *base)
+		}
+		if details != nil {
+			scanned.details = append(scanned.details, *details)
+		}
+
+		return scanned, nil
+	}
+
+	for _, platform := range platforms {
+		pkgs, base, details, err := scanImagePlatform(ctx, r, client, ref, platformLabel(imageRef, platform), platform, attestationKey, compareOffline, fetchers)
+		if err != nil {
+			return scannedImage{}, err
+		}
+
+		scanned.packages = append(scanned.packages, pkgs...)
+		if base != nil {
+			scanned.bases = append(scanned.bases, *base)
+		}
+		if details != nil {
+			scanned.details = append(scanned.details, *details)
+		}
+	}
+
+	return scanned, nil
+}
+
+// scanImageArchive scans the images that have been exported to the path, such as by `docker save`, without
+// a registry or a Docker daemon. Findings are reported under the path of the archive, along with the name
+// that each image was exported with if it has more than one, like "app.tar (app:1.0):/var/lib/dpkg/status"
+func scanImageArchive(r reporter.Reporter, path string, compareOffline bool, fetchers resolutionFetchers) ([]scannedPackage, []models.ImageDetails, error) {
+	platform := image.DefaultPlatform()
+
+	images, err := image.ReadArchive(path, platform)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read image archive: %w", err)
+	}
+
+	if len(images) == 0 {
+		r.Warnf("%s does not have any images for %s\n", path, platform)
+		return nil, nil, nil
+	}
+
+	ctx := context.Background()
+	// the layers are read from the archive, so the client never has to access a registry
+	client := image.NewClient()
+
+	var pkgs []scannedPackage
+	var details []models.ImageDetails
+
+	for i, img := range images {
+		label := path
+		if len(images) > 1 {
+			name := img.Name
+			if name == "" {
+				name = fmt.Sprintf("image %d", i+1)
+			}
+
+			label = path + " (" + name + ")"
+		}
+
+		scanned, osRelease, err := extractAndScanImage(ctx, r, client, label, img.Image, compareOffline, fetchers, newLayerOrigins())
+		if err != nil {
+			return nil, nil, err
+		}
+
+		r.Infof(
+			"Scanned image %s (%s) with %d %s and found %d %s\n",
+			label,
+			img.Platform,
+			len(img.Layers),
+			output.Form(len(img.Layers), "layer", "layers"),
+			len(scanned),
+			output.Form(len(scanned), "package", "packages"),
+		)
+
+		pkgs = append(pkgs, scanned...)
+		if d := imageDetails(label, osRelease, scanned); d != nil {
+			details = append(details, *d)
+		}
+	}
+
+	return pkgs, details, nil
+}
+
+// comparePlatforms finds the vulnerabilities that only some of the platforms that an image was scanned for have
+func comparePlatforms(results *models.VulnerabilityResults, scanned scannedImage) models.PlatformDifferences {
+	type finding struct {
+		pkg string
+		id  string
+	}
+
+	differences := models.PlatformDifferences{
+		Image:           scanned.imageRef,
+		Vulnerabilities: []models.PlatformVulnerability{},
+	}
+
+	found := make(map[finding][]string)
+
+	for _, platform := range scanned.platforms {
+		differences.Platforms = append(differences.Platforms, platform.String())
+
+		prefix := platformLabel(scanned.imageRef, platform) + ":/"
+		seen := make(map[finding]bool)
+
+		for _, source := range results.Results {
+			if !strings.HasPrefix(source.Source.Path, prefix) {
+				continue
+			}
+
+			for _, pkg := range source.Packages {
+				for _, vuln := range pkg.Vulnerabilities {
+					f := finding{pkg: pkg.Package.Name, id: vuln.ID}
+					if seen[f] {
+						continue
+					}
+
+					seen[f] = true
+					found[f] = append(found[f], platform.String())
+				}
+			}
+		}
+	}
+
+	for f, platforms := range found {
+		if len(platforms) == len(scanned.platforms) {
+			continue
+		}
+
+		differences.Vulnerabilities = append(differences.Vulnerabilities, models.PlatformVulnerability{
+			ID:        f.id,
+			Package:   f.pkg,
+			Platforms: platforms,
+		})
+	}
+
+	sort.Slice(differences.Vulnerabilities, func(i, j int) bool {
+		a, b := differences.Vulnerabilities[i], differences.Vulnerabilities[j]
+		if a.Package != b.Package {
+			return a.Package < b.Package
+		}
+
+		return a.ID < b.ID
+	})
+
+	return differences
+}
+
+// baseImageVulnerabilities returns the IDs of the vulnerabilities of the packages
+// of an image that were introduced by the image that it was built on
+func baseImageVulnerabilities(results *models.VulnerabilityResults, imageRef string) []string {
+	seen := make(map[string]bool)
+	var ids []string
+
+	for _, source := range results.Results {
+		if !strings.HasPrefix(source.Source.Path, imageRef+":/") {
+			continue
+		}
+
+		for _, pkg := range source.Packages {
+			if pkg.ImageOrigin == nil || !pkg.ImageOrigin.InBaseImage {
+				continue
+			}
+
+			for _, vuln := range pkg.Vulnerabilities {
+				if !seen[vuln.ID] {
+					seen[vuln.ID] = true
+					ids = append(ids, vuln.ID)
+				}
+			}
+		}
+	}
+
+	sort.Strings(ids)
+
+	return ids
+}
+
+// filterBaseImageVulns removes the vulnerabilities of the packages of images that were introduced by their
+// base image, returning how many were removed. Packages that are upgraded by a layer of the image itself are
+// attributed to that layer, so their vulnerabilities are kept. The packages of the base image are still
+// kept if all packages are being shown, as they are in the image even if their vulnerabilities are hidden
+func filterBaseImageVulns(results *models.VulnerabilityResults, allPackages bool) int {
+	removedCount := 0
+	newResults := []models.PackageSource{}
+
+	for _, pkgSrc := range results.Results {
+		var newPackages []models.PackageVulns
+
+		for _, pkgVulns := range pkgSrc.Packages {
+			if pkgVulns.ImageOrigin != nil && pkgVulns.ImageOrigin.InBaseImage {
+				removedCount += len(pkgVulns.Vulnerabilities)
+				pkgVulns.Vulnerabilities = nil
+				pkgVulns.Groups = nil
+			}
+
+			if allPackages || len(pkgVulns.Vulnerabilities) > 0 || len(pkgVulns.LicenseViolations) > 0 {
+				newPackages = append(newPackages, pkgVulns)
+			}
+		}
+
+		if len(newPackages) > 0 {
+			pkgSrc.Packages = newPackages
+			newResults = append(newResults, pkgSrc)
+		}
+	}
+
+	results.Results = newResults
+
+	return removedCount
+}
+
+// scanImageVulnerabilities returns the IDs of the vulnerabilities of the packages of an image
+func scanImageVulnerabilities(ctx context.Context, client *image.Client, ref image.Reference, platform image.Platform, actions ScannerActions, fetchers resolutionFetchers) (map[string]bool, error) {
+	r := &reporter.VoidReporter{}
+
+	img, err := client.Pull(ctx, ref, platform)
+	if err != nil {
+		return nil, err
+	}
+
+	pkgs, _, err := extractAndScanImage(ctx, r, client, ref.String(), img, actions.CompareOffline, fetchers, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make(map[string]bool)
+
+	pkgs = filterUnscannablePackages(pkgs)
+	if len(pkgs) == 0 {
+		return ids, nil
+	}
+
+	resp, err := makeRequest(r, pkgs, actions.CompareLocally, actions.CompareOffline, actions.LocalDBPath)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, result := range resp.Results {
+		for _, vuln := range result.Vulns {
+			ids[vuln.ID] = true
+		}
+	}
+
+	return ids, nil
+}
+
+// recommendBaseImage scans the newest tags of the same variant as the base image of an image,
+// and recommends rebuilding the image on the oldest of them that removes the most of the
+// vulnerabilities that the current base image introduced
+func recommendBaseImage(r reporter.Reporter, base imageBase, results *models.VulnerabilityResults, actions ScannerActions, fetchers resolutionFetchers) models.BaseImage {
+	out := models.BaseImage{
+		Image:           base.imageRef,
+		Name:            base.ref.String(),
+		Layers:          base.layers,
+		Vulnerabilities: baseImageVulnerabilities(results, base.imageRef),
+	}
+
+	if len(out.Vulnerabilities) == 0 || base.ref.Tag == "" {
+		return out
+	}
+
+	ctx := context.Background()
+	client := image.NewClient()
+
+	tags, err := client.Tags(ctx, base.ref)
+	if err != nil {
+		r.Warnf("Failed to list the tags of base image %s: %v\n", base.ref, err)
+		return out
+	}
+
+	candidates := image.NewerTags(base.ref.Tag, tags)
+	if len(candidates) > maxBaseImageCandidates {
+		candidates = candidates[len(candidates)-maxBaseImageCandidates:]
+	}
+
+	for _, tag := range candidates {
+		ref := base.ref
+		ref.Tag, ref.Digest = tag, ""
+
+		r.Infof("Scanning %s to check if rebuilding %s on it removes vulnerabilities\n", ref, base.imageRef)
+
+		remaining, err := scanImageVulnerabilities(ctx, client, ref, base.platform, actions, fetchers)
+		if err != nil {
+			r.Warnf("Failed to scan %s: %v\n", ref, err)
+			continue
+		}
+
+		var fixed []string
+		for _, id := range out.Vulnerabilities {
+			if !remaining[id] {
+				fixed = append(fixed, id)
+			}
+		}
+
+		if len(fixed) > 0 && (out.Recommendation == nil || len(fixed) > len(out.Recommendation.Fixed)) {
+			out.Recommendation = &models.BaseImageRecommendation{Name: ref.String(), Fixed: fixed}
+		}
+	}
+
+	return out
+}
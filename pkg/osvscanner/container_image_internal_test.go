@@ -0,0 +1,335 @@
+package osvscanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/internal/image"
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+func writeLayerFile(t *testing.T, dir string, name string, content string) {
+	t.Helper()
+
+	path := filepath.Join(dir, filepath.FromSlash(name))
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("failed to create directory for %s: %v", name, err)
+	}
+
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write %s: %v", name, err)
+	}
+}
+
+func dpkgStatus(pkgs ...string) string {
+	status := ""
+
+	for i := 0; i < len(pkgs); i += 2 {
+		status += "Package: " + pkgs[i] + "\nStatus: install ok installed\nVersion: " + pkgs[i+1] + "\n\n"
+	}
+
+	return status
+}
+
+func Test_layerOrigins(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	origins := newLayerOrigins()
+	record := origins.record(dir)
+
+	layers := []struct {
+		layer image.Layer
+		files map[string]string
+	}{
+		{
+			layer: image.Layer{Digest: "sha256:base", Command: "ADD file:1234 in /"},
+			files: map[string]string{
+				"/var/lib/dpkg/status": dpkgStatus("base-files", "12.4", "libssl3", "3.0.11-1"),
+			},
+		},
+		{
+			layer: image.Layer{Digest: "sha256:curl", Command: "RUN apt-get install -y curl"},
+			files: map[string]string{
+				"/var/lib/dpkg/status": dpkgStatus("base-files", "12.4", "libssl3", "3.0.11-1", "curl", "7.88.1-10"),
+			},
+		},
+		{
+			layer: image.Layer{Digest: "sha256:upgrade", Command: "RUN apt-get upgrade -y"},
+			files: map[string]string{
+				"/var/lib/dpkg/status": dpkgStatus("base-files", "12.4", "libssl3", "3.0.13-1", "curl", "7.88.1-10"),
+				"/app/bom.json":        "{}",
+			},
+		},
+	}
+
+	for i, layer := range layers {
+		files := make([]string, 0, len(layer.files))
+		for name, content := range layer.files {
+			writeLayerFile(t, dir, name, content)
+			files = append(files, name)
+		}
+
+		if err := record(i, layer.layer, files); err != nil {
+			t.Fatalf("record() error = %v", err)
+		}
+	}
+
+	base := &models.ImageOrigin{Layer: 1, LayerDigest: "sha256:base", Command: "ADD file:1234 in /"}
+	curl := &models.ImageOrigin{Layer: 2, LayerDigest: "sha256:curl", Command: "RUN apt-get install -y curl"}
+	upgrade := &models.ImageOrigin{Layer: 3, LayerDigest: "sha256:upgrade", Command: "RUN apt-get upgrade -y"}
+
+	tests := []struct {
+		name string
+		path string
+		pkg  scannedPackage
+		want *models.ImageOrigin
+	}{
+		{
+			name: "package of the base image",
+			path: "/var/lib/dpkg/status",
+			pkg:  scannedPackage{Name: "base-files", Version: "12.4"},
+			want: base,
+		},
+		{
+			name: "package added on top of the base image",
+			path: "/var/lib/dpkg/status",
+			pkg:  scannedPackage{Name: "curl", Version: "7.88.1-10"},
+			want: curl,
+		},
+		{
+			name: "package upgraded by a later layer",
+			path: "/var/lib/dpkg/status",
+			pkg:  scannedPackage{Name: "libssl3", Version: "3.0.13-1"},
+			want: upgrade,
+		},
+		{
+			name: "file that is not a lockfile",
+			path: "/app/bom.json",
+			pkg:  scannedPackage{Name: "lodash", Version: "4.17.20"},
+			want: upgrade,
+		},
+		{
+			name: "file that no layer wrote",
+			path: "/app/package-lock.json",
+			pkg:  scannedPackage{Name: "lodash", Version: "4.17.20"},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt // Reinitialize for t.Parallel()
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := origins.lookup(tt.path, tt.pkg)
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("lookup() (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_baseImageVulnerabilities(t *testing.T) {
+	t.Parallel()
+
+	base := &models.ImageOrigin{Layer: 1, LayerDigest: "sha256:base", InBaseImage: true}
+	app := &models.ImageOrigin{Layer: 2, LayerDigest: "sha256:app"}
+
+	results := &models.VulnerabilityResults{
+		Results: []models.PackageSource{
+			{
+				Source: models.SourceInfo{Path: "ghcr.io/org/app:1.0:/var/lib/dpkg/status", Type: "lockfile"},
+				Packages: []models.PackageVulns{
+					{
+						Package:         models.PackageInfo{Name: "libssl3", Version: "3.0.11-1", Ecosystem: "Debian:12"},
+						Vulnerabilities: []models.Vulnerability{{ID: "DEBIAN-CVE-2023-5678"}, {ID: "DEBIAN-CVE-2023-3817"}},
+						ImageOrigin:     base,
+					},
+					{
+						Package:         models.PackageInfo{Name: "curl", Version: "7.88.1-10", Ecosystem: "Debian:12"},
+						Vulnerabilities: []models.Vulnerability{{ID: "DEBIAN-CVE-2023-38545"}},
+						ImageOrigin:     app,
+					},
+				},
+			},
+			{
+				Source: models.SourceInfo{Path: "ghcr.io/org/app:1.0:/usr/lib/x86_64-linux-gnu/bom.json", Type: "sbom"},
+				Packages: []models.PackageVulns{
+					{
+						Package:         models.PackageInfo{Name: "openssl", Version: "3.0.11", Ecosystem: "Debian:12"},
+						Vulnerabilities: []models.Vulnerability{{ID: "DEBIAN-CVE-2023-5678"}},
+						ImageOrigin:     base,
+					},
+				},
+			},
+			{
+				Source: models.SourceInfo{Path: "ghcr.io/org/other:1.0:/var/lib/dpkg/status", Type: "lockfile"},
+				Packages: []models.PackageVulns{
+					{
+						Package:         models.PackageInfo{Name: "libssl3", Version: "3.0.9-1", Ecosystem: "Debian:12"},
+						Vulnerabilities: []models.Vulnerability{{ID: "DEBIAN-CVE-2023-2650"}},
+						ImageOrigin:     base,
+					},
+				},
+			},
+		},
+	}
+
+	got := baseImageVulnerabilities(results, "ghcr.io/org/app:1.0")
+	want := []string{"DEBIAN-CVE-2023-3817", "DEBIAN-CVE-2023-5678"}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("baseImageVulnerabilities() (-want +got):\n%s", diff)
+	}
+}
+
+func Test_comparePlatforms(t *testing.T) {
+	t.Parallel()
+
+	amd64 := image.Platform{OS: "linux", Architecture: "amd64"}
+	arm64 := image.Platform{OS: "linux", Architecture: "arm64", Variant: "v8"}
+
+	results := &models.VulnerabilityResults{
+		Results: []models.PackageSource{
+			{
+				Source: models.SourceInfo{Path: "ghcr.io/org/app:1.0 (linux/amd64):/var/lib/dpkg/status", Type: "lockfile"},
+				Packages: []models.PackageVulns{
+					{
+						Package:         models.PackageInfo{Name: "libssl3", Version: "3.0.11-1", Ecosystem: "Debian:12"},
+						Vulnerabilities: []models.Vulnerability{{ID: "DEBIAN-CVE-2023-5678"}},
+					},
+				},
+			},
+			{
+				Source: models.SourceInfo{Path: "ghcr.io/org/app:1.0 (linux/arm64/v8):/var/lib/dpkg/status", Type: "lockfile"},
+				Packages: []models.PackageVulns{
+					{
+						Package:         models.PackageInfo{Name: "libssl3", Version: "3.0.11-1", Ecosystem: "Debian:12"},
+						Vulnerabilities: []models.Vulnerability{{ID: "DEBIAN-CVE-2023-5678"}},
+					},
+					{
+						Package:         models.PackageInfo{Name: "libc6", Version: "2.36-9", Ecosystem: "Debian:12"},
+						Vulnerabilities: []models.Vulnerability{{ID: "DEBIAN-CVE-2023-4911"}},
+					},
+				},
+			},
+			{
+				Source: models.SourceInfo{Path: "ghcr.io/org/app:1.0 (linux/amd64):/app/go.mod", Type: "lockfile"},
+				Packages: []models.PackageVulns{
+					{
+						Package:         models.PackageInfo{Name: "golang.org/x/net", Version: "0.7.0", Ecosystem: "Go"},
+						Vulnerabilities: []models.Vulnerability{{ID: "GO-2023-1988"}},
+					},
+				},
+			},
+		},
+	}
+
+	got := comparePlatforms(results, scannedImage{imageRef: "ghcr.io/org/app:1.0", platforms: []image.Platform{amd64, arm64}})
+	want := models.PlatformDifferences{
+		Image:     "ghcr.io/org/app:1.0",
+		Platforms: []string{"linux/amd64", "linux/arm64/v8"},
+		Vulnerabilities: []models.PlatformVulnerability{
+			{ID: "GO-2023-1988", Package: "golang.org/x/net", Platforms: []string{"linux/amd64"}},
+			{ID: "DEBIAN-CVE-2023-4911", Package: "libc6", Platforms: []string{"linux/arm64/v8"}},
+		},
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("comparePlatforms() (-want +got):\n%s", diff)
+	}
+}
+
+func Test_filterBaseImageVulns(t *testing.T) {
+	t.Parallel()
+
+	base := &models.ImageOrigin{Layer: 1, LayerDigest: "sha256:base", InBaseImage: true}
+	app := &models.ImageOrigin{Layer: 2, LayerDigest: "sha256:app"}
+
+	newResults := func() *models.VulnerabilityResults {
+		return &models.VulnerabilityResults{
+			Results: []models.PackageSource{
+				{
+					Source: models.SourceInfo{Path: "ghcr.io/org/app:1.0:/var/lib/dpkg/status", Type: "lockfile"},
+					Packages: []models.PackageVulns{
+						{
+							Package:         models.PackageInfo{Name: "libssl3", Version: "3.0.11-1", Ecosystem: "Debian:12"},
+							Vulnerabilities: []models.Vulnerability{{ID: "DEBIAN-CVE-2023-5678"}, {ID: "DEBIAN-CVE-2023-3817"}},
+							Groups:          []models.GroupInfo{{IDs: []string{"DEBIAN-CVE-2023-5678"}}, {IDs: []string{"DEBIAN-CVE-2023-3817"}}},
+							ImageOrigin:     base,
+						},
+						{
+							Package:         models.PackageInfo{Name: "curl", Version: "7.88.1-10", Ecosystem: "Debian:12"},
+							Vulnerabilities: []models.Vulnerability{{ID: "DEBIAN-CVE-2023-38545"}},
+							Groups:          []models.GroupInfo{{IDs: []string{"DEBIAN-CVE-2023-38545"}}},
+							ImageOrigin:     app,
+						},
+					},
+				},
+				{
+					Source: models.SourceInfo{Path: "ghcr.io/org/app:1.0:/usr/lib/node_modules/npm/package-lock.json", Type: "lockfile"},
+					Packages: []models.PackageVulns{
+						{
+							Package:         models.PackageInfo{Name: "semver", Version: "7.5.1", Ecosystem: "npm"},
+							Vulnerabilities: []models.Vulnerability{{ID: "GHSA-c2qf-rxjj-qqgw"}},
+							Groups:          []models.GroupInfo{{IDs: []string{"GHSA-c2qf-rxjj-qqgw"}}},
+							ImageOrigin:     base,
+						},
+					},
+				},
+			},
+		}
+	}
+
+	t.Run("vulnerable packages only", func(t *testing.T) {
+		t.Parallel()
+
+		results := newResults()
+
+		if got := filterBaseImageVulns(results, false); got != 3 {
+			t.Errorf("filterBaseImageVulns() = %d, want 3", got)
+		}
+
+		want := []models.PackageSource{
+			{
+				Source: models.SourceInfo{Path: "ghcr.io/org/app:1.0:/var/lib/dpkg/status", Type: "lockfile"},
+				Packages: []models.PackageVulns{
+					{
+						Package:         models.PackageInfo{Name: "curl", Version: "7.88.1-10", Ecosystem: "Debian:12"},
+						Vulnerabilities: []models.Vulnerability{{ID: "DEBIAN-CVE-2023-38545"}},
+						Groups:          []models.GroupInfo{{IDs: []string{"DEBIAN-CVE-2023-38545"}}},
+						ImageOrigin:     app,
+					},
+				},
+			},
+		}
+
+		if diff := cmp.Diff(want, results.Results); diff != "" {
+			t.Errorf("filterBaseImageVulns() (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("all packages", func(t *testing.T) {
+		t.Parallel()
+
+		results := newResults()
+
+		if got := filterBaseImageVulns(results, true); got != 3 {
+			t.Errorf("filterBaseImageVulns() = %d, want 3", got)
+		}
+
+		if got := len(results.Results); got != 2 {
+			t.Fatalf("filterBaseImageVulns() kept %d sources, want 2", got)
+		}
+
+		for _, pkg := range results.Results[0].Packages {
+			if pkg.ImageOrigin.InBaseImage && (len(pkg.Vulnerabilities) > 0 || len(pkg.Groups) > 0) {
+				t.Errorf("filterBaseImageVulns() kept the vulnerabilities of %s", pkg.Package.Name)
+			}
+		}
+	})
+}
@@ -0,0 +1,27 @@
+package osvscanner
+
+import (
+	"slices"
+
+	"github.com/google/osv-scanner/pkg/lockfile"
+)
+
+// dockerfileBaseImages returns the images that the stages of the Dockerfile are built from, which are
+// what a build of it starts from, skipping the stages that continue from an earlier one and "scratch"
+func dockerfileBaseImages(path string) ([]string, error) {
+	stages, err := lockfile.DockerfileStages(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var images []string
+	for _, stage := range stages {
+		if stage.IsStage || stage.From == "scratch" || slices.Contains(images, stage.From) {
+			continue
+		}
+
+		images = append(images, stage.From)
+	}
+
+	return images, nil
+}
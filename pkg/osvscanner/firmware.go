@@ -0,0 +1,46 @@
+package osvscanner
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/google/osv-scanner/internal/image"
+	"github.com/google/osv-scanner/internal/output"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/reporter"
+)
+
+// scanFirmware extracts the root filesystem of a firmware image, like a squashfs filesystem or an
+// initramfs, to a temporary directory and scans it like the filesystem of an image, for the packages of
+// its operating system and the artifacts of applications. Findings are reported under the path of the
+// firmware, like "/path/to/openwrt.bin:/usr/lib/opkg/status"
+func scanFirmware(r reporter.Reporter, path string, compareOffline bool, fetchers resolutionFetchers) ([]scannedPackage, *models.ImageDetails, error) {
+	dir, err := os.MkdirTemp("", "osv-scanner-firmware-")
+	if err != nil {
+		return nil, nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	if err := image.ExtractFirmware(path, dir); err != nil {
+		return nil, nil, fmt.Errorf("failed to extract firmware %s: %w", path, err)
+	}
+
+	// the filesystem of firmware is not a repository, and has nothing that is ignored by git
+	pkgs, err := scanDir(r, dir, true, true, false, true, compareOffline, fetchers)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for i := range pkgs {
+		pkgs[i].Source.Path = imageSourcePath(path, dir, pkgs[i].Source.Path)
+	}
+
+	r.Infof(
+		"Scanned firmware %s and found %d %s\n",
+		path,
+		len(pkgs),
+		output.Form(len(pkgs), "package", "packages"),
+	)
+
+	return pkgs, imageDetails(path, image.OSRelease(dir), pkgs), nil
+}
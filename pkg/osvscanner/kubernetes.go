@@ -0,0 +1,105 @@
+package osvscanner
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/google/osv-scanner/internal/kubernetes"
+	"github.com/google/osv-scanner/internal/output"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/google/osv-scanner/pkg/reporter"
+)
+
+// listWorkloads lists the workloads of the cluster of the kubeconfig, in the namespace if it is set
+func listWorkloads(r reporter.Reporter, kubeconfigPath string, contextName string, namespace string) ([]kubernetes.Workload, error) {
+	if kubeconfigPath == "" {
+		kubeconfigPath = kubernetes.KubeconfigPath()
+	}
+
+	client, _, err := kubernetes.LoadConfig(kubeconfigPath, contextName)
+	if err != nil {
+		return nil, err
+	}
+
+	workloads, err := client.Workloads(context.Background(), namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	r.Infof(
+		"Found %d %s in the cluster\n",
+		len(workloads),
+		output.Form(len(workloads), "workload", "workloads"),
+	)
+
+	return workloads, nil
+}
+
+// workloadImages returns each of the images that the workloads run, only once
+// even if more than one workload runs it, in the order that they are first run in
+func workloadImages(workloads []kubernetes.Workload) []string {
+	seen := make(map[string]bool)
+	var images []string
+
+	for _, workload := range workloads {
+		for _, image := range workload.Images {
+			if !seen[image] {
+				seen[image] = true
+				images = append(images, image)
+			}
+		}
+	}
+
+	return images
+}
+
+// imageVulnerabilities returns the IDs of the vulnerabilities of the packages of an image,
+// across each of the platforms that it was scanned for
+func imageVulnerabilities(results *models.VulnerabilityResults, imageRef string) []string {
+	seen := make(map[string]bool)
+	ids := []string{}
+
+	for _, source := range results.Results {
+		if !strings.HasPrefix(source.Source.Path, imageRef+":/") && !strings.HasPrefix(source.Source.Path, imageRef+" (") {
+			continue
+		}
+
+		for _, pkg := range source.Packages {
+			for _, vuln := range pkg.Vulnerabilities {
+				if !seen[vuln.ID] {
+					seen[vuln.ID] = true
+					ids = append(ids, vuln.ID)
+				}
+			}
+		}
+	}
+
+	sort.Strings(ids)
+
+	return ids
+}
+
+// aggregateWorkloads groups the vulnerabilities of the images that were scanned by the workloads that run them
+func aggregateWorkloads(results *models.VulnerabilityResults, workloads []kubernetes.Workload) []models.Workload {
+	aggregated := make([]models.Workload, 0, len(workloads))
+
+	for _, workload := range workloads {
+		w := models.Workload{
+			Namespace: workload.Namespace,
+			Kind:      workload.Kind,
+			Name:      workload.Name,
+		}
+
+		for _, image := range workload.Images {
+			w.Images = append(w.Images, models.WorkloadImage{
+				Image:           image,
+				Vulnerabilities: imageVulnerabilities(results, image),
+			})
+		}
+
+		aggregated = append(aggregated, w)
+	}
+
+	return aggregated
+}
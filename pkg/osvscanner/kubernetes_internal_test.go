@@ -0,0 +1,74 @@
+package osvscanner
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/internal/kubernetes"
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+func Test_aggregateWorkloads(t *testing.T) {
+	t.Parallel()
+
+	workloads := []kubernetes.Workload{
+		{Namespace: "shop", Kind: "Deployment", Name: "api", Images: []string{"envoyproxy/envoy:v1.28.0", "ghcr.io/org/api:2.1"}},
+		{Namespace: "shop", Kind: "StatefulSet", Name: "redis", Images: []string{"redis:7.2"}},
+		{Namespace: "web", Kind: "Deployment", Name: "gateway", Images: []string{"envoyproxy/envoy:v1.28.0"}},
+	}
+
+	if diff := cmp.Diff([]string{"envoyproxy/envoy:v1.28.0", "ghcr.io/org/api:2.1", "redis:7.2"}, workloadImages(workloads)); diff != "" {
+		t.Errorf("workloadImages() (-want +got):\n%s", diff)
+	}
+
+	results := &models.VulnerabilityResults{
+		Results: []models.PackageSource{
+			{
+				Source: models.SourceInfo{Path: "ghcr.io/org/api:2.1:/app/go.mod", Type: "lockfile"},
+				Packages: []models.PackageVulns{
+					{
+						Package:         models.PackageInfo{Name: "golang.org/x/net", Version: "0.7.0", Ecosystem: "Go"},
+						Vulnerabilities: []models.Vulnerability{{ID: "GO-2023-1988"}, {ID: "GO-2023-1571"}},
+					},
+				},
+			},
+			{
+				Source: models.SourceInfo{Path: "envoyproxy/envoy:v1.28.0 (linux/arm64):/var/lib/dpkg/status", Type: "lockfile"},
+				Packages: []models.PackageVulns{
+					{
+						Package:         models.PackageInfo{Name: "libssl3", Version: "3.0.2-0ubuntu1.12", Ecosystem: "Ubuntu:22.04"},
+						Vulnerabilities: []models.Vulnerability{{ID: "USN-6435-1"}},
+					},
+				},
+			},
+		},
+	}
+
+	want := []models.Workload{
+		{
+			Namespace: "shop",
+			Kind:      "Deployment",
+			Name:      "api",
+			Images: []models.WorkloadImage{
+				{Image: "envoyproxy/envoy:v1.28.0", Vulnerabilities: []string{"USN-6435-1"}},
+				{Image: "ghcr.io/org/api:2.1", Vulnerabilities: []string{"GO-2023-1571", "GO-2023-1988"}},
+			},
+		},
+		{
+			Namespace: "shop",
+			Kind:      "StatefulSet",
+			Name:      "redis",
+			Images:    []models.WorkloadImage{{Image: "redis:7.2", Vulnerabilities: []string{}}},
+		},
+		{
+			Namespace: "web",
+			Kind:      "Deployment",
+			Name:      "gateway",
+			Images:    []models.WorkloadImage{{Image: "envoyproxy/envoy:v1.28.0", Vulnerabilities: []string{"USN-6435-1"}}},
+		},
+	}
+
+	if diff := cmp.Diff(want, aggregateWorkloads(results, workloads)); diff != "" {
+		t.Errorf("aggregateWorkloads() (-want +got):\n%s", diff)
+	}
+}
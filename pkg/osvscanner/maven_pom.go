@@ -0,0 +1,77 @@
+package osvscanner
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/google/osv-scanner/internal/resolution/manifest"
+	"github.com/google/osv-scanner/pkg/lockfile"
+)
+
+// extractMavenEffectivePOM extracts the packages of the pom.xml at path from its effective pom,
+// so that versions inherited from parent poms and imported BOMs are known.
+//
+// The dependencyManagement of the pom.xml itself is also reported, as is done when extracting
+// a pom.xml without resolving it, but what is only managed by parents and BOMs is not.
+func extractMavenEffectivePOM(ctx context.Context, path string, fetch manifest.POMFetcher) ([]lockfile.PackageDetails, error) {
+	body, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var pom manifest.PomXML
+	if err := xml.Unmarshal(body, &pom); err != nil {
+		return nil, fmt.Errorf("could not extract from %s: %w", path, err)
+	}
+	// only the dependencyManagement of the pom.xml itself is reported
+	managed := pom.DependencyManagement
+
+	if err := pom.MergeEffective(ctx, path, fetch); err != nil {
+		return nil, fmt.Errorf("could not compute the effective pom of %s: %w", path, err)
+	}
+
+	details := make(map[string]lockfile.PackageDetails)
+	add := func(groupID, artifactID, version, scope string) {
+		name := pom.Interpolate(groupID) + ":" + pom.Interpolate(artifactID)
+		version = pom.Interpolate(version)
+		if version == "" {
+			version = pom.ManagedVersion(pom.Interpolate(groupID), pom.Interpolate(artifactID))
+		}
+		// properties that are not set anywhere are reported as an unknown version
+		if strings.Contains(version, "${") {
+			version = ""
+		}
+
+		pkg := lockfile.PackageDetails{
+			Name:      name,
+			Version:   lockfile.MavenLockDependency{Version: version}.ResolveVersion(lockfile.MavenLockFile{}),
+			Ecosystem: lockfile.MavenEcosystem,
+			CompareAs: lockfile.MavenEcosystem,
+		}
+		if scope = strings.TrimSpace(scope); scope != "" {
+			pkg.DepGroups = append(pkg.DepGroups, scope)
+		}
+		details[name] = pkg
+	}
+
+	for _, d := range managed {
+		if strings.TrimSpace(d.Scope) == "import" {
+			continue
+		}
+		add(d.GroupID, d.ArtifactID, d.Version, d.Scope)
+	}
+	// the versions of dependencies take precedence over their managed versions
+	for _, d := range pom.Dependencies {
+		add(d.GroupID, d.ArtifactID, d.Version, d.Scope)
+	}
+
+	packages := make([]lockfile.PackageDetails, 0, len(details))
+	for _, pkg := range details {
+		packages = append(packages, pkg)
+	}
+
+	return packages, nil
+}
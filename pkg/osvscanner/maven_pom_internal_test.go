@@ -0,0 +1,97 @@
+package osvscanner
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/lockfile"
+)
+
+const jacksonBOM = `<project>
+  <groupId>com.fasterxml.jackson</groupId>
+  <artifactId>jackson-bom</artifactId>
+  <version>2.15.2</version>
+  <dependencyManagement>
+    <dependencies>
+      <dependency>
+        <groupId>com.fasterxml.jackson.core</groupId>
+        <artifactId>jackson-databind</artifactId>
+        <version>${project.version}</version>
+      </dependency>
+    </dependencies>
+  </dependencyManagement>
+</project>`
+
+func Test_extractMavenEffectivePOM(t *testing.T) {
+	t.Parallel()
+
+	fetch := func(_ context.Context, name, version string) ([]byte, error) {
+		if name == "com.fasterxml.jackson:jackson-bom" && version == "2.15.2" {
+			return []byte(jacksonBOM), nil
+		}
+
+		return nil, errors.New("404 Not Found")
+	}
+
+	got, err := extractMavenEffectivePOM(context.Background(), "fixtures/maven-effective-pom/app/pom.xml", fetch)
+	if err != nil {
+		t.Fatalf("extractMavenEffectivePOM() error: %v", err)
+	}
+
+	want := []lockfile.PackageDetails{
+		{
+			Name:      "com.fasterxml.jackson.core:jackson-databind",
+			Version:   "2.15.2",
+			Ecosystem: lockfile.MavenEcosystem,
+			CompareAs: lockfile.MavenEcosystem,
+		},
+		{
+			Name:      "com.google.guava:guava",
+			Version:   "32.1.2-jre",
+			Ecosystem: lockfile.MavenEcosystem,
+			CompareAs: lockfile.MavenEcosystem,
+		},
+		{
+			Name:      "junit:junit",
+			Version:   "4.13.2",
+			Ecosystem: lockfile.MavenEcosystem,
+			CompareAs: lockfile.MavenEcosystem,
+			DepGroups: []string{"test"},
+		},
+		{
+			// the property is not set by any of the parents
+			Name:      "org.apache.commons:commons-lang3",
+			Version:   "0",
+			Ecosystem: lockfile.MavenEcosystem,
+			CompareAs: lockfile.MavenEcosystem,
+		},
+		{
+			Name:      "org.apache.commons:commons-text",
+			Version:   "1.10.0",
+			Ecosystem: lockfile.MavenEcosystem,
+			CompareAs: lockfile.MavenEcosystem,
+		},
+	}
+
+	slices.SortFunc(got, func(a, b lockfile.PackageDetails) int { return strings.Compare(a.Name, b.Name) })
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("extractMavenEffectivePOM() (-want +got):\n%s", diff)
+	}
+}
+
+func Test_extractMavenEffectivePOM_FetchError(t *testing.T) {
+	t.Parallel()
+
+	fetch := func(context.Context, string, string) ([]byte, error) {
+		return nil, errors.New("404 Not Found")
+	}
+
+	_, err := extractMavenEffectivePOM(context.Background(), "fixtures/maven-effective-pom/app/pom.xml", fetch)
+	if err == nil {
+		t.Errorf("extractMavenEffectivePOM() expected an error when the BOM can't be fetched")
+	}
+}
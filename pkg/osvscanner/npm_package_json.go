@@ -0,0 +1,146 @@
+package osvscanner
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"deps.dev/util/resolve"
+	"github.com/google/osv-scanner/internal/resolution"
+	"github.com/google/osv-scanner/internal/resolution/client"
+	"github.com/google/osv-scanner/internal/resolution/datasource"
+	"github.com/google/osv-scanner/internal/resolution/manifest"
+	"github.com/google/osv-scanner/pkg/lockfile"
+)
+
+// npmLockfiles are the lockfiles that record what is installed for a package.json,
+// which are scanned instead of resolving the package.json if any of them are present
+var npmLockfiles = []string{"bun.lock", "bun.lockb", "npm-shrinkwrap.json", "package-lock.json", "pnpm-lock.yaml", "yarn.lock"}
+
+// hasNpmLockfile reports if the package.json at path has a lockfile, either next to it
+// or in a parent directory as is the case for the packages of a workspace
+func hasNpmLockfile(path string) bool {
+	dir := filepath.Dir(path)
+
+	for {
+		for _, name := range npmLockfiles {
+			if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+				return true
+			}
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return false
+		}
+		dir = parent
+	}
+}
+
+// isInNodeModules reports if path is of a package that has been installed into node_modules,
+// rather than of the project itself
+func isInNodeModules(path string) bool {
+	for _, dir := range strings.Split(filepath.ToSlash(filepath.Dir(path)), "/") {
+		if dir == "node_modules" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// resolvePackageJSON resolves the requirements of the package.json at path to the
+// versions published to the npm registry that they would be installed at, as configured
+// by the .npmrc files of the project
+func resolvePackageJSON(ctx context.Context, path string) ([]lockfile.PackageDetails, error) {
+	cl, err := client.NewNpmRegistryClient(filepath.Dir(path), datasource.RegistryConfig{})
+	if err != nil {
+		return nil, err
+	}
+
+	return extractResolvedPackageJSON(ctx, path, cl)
+}
+
+// extractResolvedPackageJSON extracts the packages of the package.json at path
+// from the dependency graph that cl resolves its requirements to
+func extractResolvedPackageJSON(ctx context.Context, path string, cl client.DependencyClient) ([]lockfile.PackageDetails, error) {
+	f, err := lockfile.OpenLocalDepFile(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	m, err := manifest.NpmManifestIO{}.Read(f)
+	if err != nil {
+		return nil, fmt.Errorf("could not extract from %s: %w", path, err)
+	}
+
+	g, err := resolution.ResolveDependencies(ctx, cl, m)
+	if err != nil {
+		return nil, fmt.Errorf("could not resolve %s: %w", path, err)
+	}
+
+	// the package.json and the packages of its workspaces are in the graph themselves
+	groups := map[resolve.PackageKey]map[resolve.PackageKey][]string{
+		m.Root.PackageKey: m.Groups,
+	}
+	for _, loc := range m.LocalManifests {
+		groups[loc.Root.PackageKey] = loc.Groups
+	}
+
+	children := make(map[resolve.NodeID][]resolve.Edge)
+	for _, e := range g.Edges {
+		children[e.From] = append(children[e.From], e)
+	}
+
+	// packages are only needed for development if every path to them is through a dev dependency
+	reached := make([]bool, len(g.Nodes))
+	required := make([]bool, len(g.Nodes))
+
+	var visit func(id resolve.NodeID, dev bool)
+	visit = func(id resolve.NodeID, dev bool) {
+		if required[id] || (dev && reached[id]) {
+			return
+		}
+		reached[id] = true
+		required[id] = !dev
+
+		local, isLocal := groups[g.Nodes[id].Version.PackageKey]
+		for _, e := range children[id] {
+			to := g.Nodes[e.To].Version.PackageKey
+			visit(e.To, dev || (isLocal && manifest.IsDevGroup(resolve.NPM, local[to])))
+		}
+	}
+	visit(0, false)
+
+	details := make(map[string]lockfile.PackageDetails)
+	for i, n := range g.Nodes {
+		if _, isLocal := groups[n.Version.PackageKey]; isLocal || !reached[i] {
+			continue
+		}
+
+		pkg := lockfile.PackageDetails{
+			Name:      n.Version.Name,
+			Version:   n.Version.Version,
+			Ecosystem: lockfile.NpmEcosystem,
+			CompareAs: lockfile.NpmEcosystem,
+		}
+		if !required[i] {
+			pkg.DepGroups = []string{"dev"}
+		}
+		// the same version of a package can be in the graph more than once
+		if existing, ok := details[pkg.Name+"@"+pkg.Version]; ok && len(existing.DepGroups) == 0 {
+			continue
+		}
+		details[pkg.Name+"@"+pkg.Version] = pkg
+	}
+
+	packages := make([]lockfile.PackageDetails, 0, len(details))
+	for _, pkg := range details {
+		packages = append(packages, pkg)
+	}
+
+	return packages, nil
+}
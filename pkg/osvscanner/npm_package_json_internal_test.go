@@ -0,0 +1,96 @@
+package osvscanner
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"deps.dev/util/resolve"
+	"deps.dev/util/resolve/dep"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/lockfile"
+)
+
+// npmTestClient is a DependencyClient serving the versions of a resolve.LocalClient.
+type npmTestClient struct {
+	*resolve.LocalClient
+}
+
+func (c npmTestClient) WriteCache(string) error                                        { return nil }
+func (c npmTestClient) LoadCache(string) error                                         { return nil }
+func (c npmTestClient) PreFetch(context.Context, []resolve.RequirementVersion, string) {}
+
+// addNpmVersion adds a version of a package to the registry of cl, which requires
+// each of reqs given as a name and a range e.g. "debug", "^2.6.0".
+func (c npmTestClient) addNpmVersion(name, version string, reqs ...string) {
+	var rvs []resolve.RequirementVersion
+	for i := 0; i+1 < len(reqs); i += 2 {
+		rvs = append(rvs, resolve.RequirementVersion{
+			VersionKey: resolve.VersionKey{
+				PackageKey:  resolve.PackageKey{System: resolve.NPM, Name: reqs[i]},
+				Version:     reqs[i+1],
+				VersionType: resolve.Requirement,
+			},
+			Type: dep.NewType(),
+		})
+	}
+
+	c.AddVersion(resolve.Version{
+		VersionKey: resolve.VersionKey{
+			PackageKey:  resolve.PackageKey{System: resolve.NPM, Name: name},
+			Version:     version,
+			VersionType: resolve.Concrete,
+		},
+	}, rvs)
+}
+
+func Test_extractResolvedPackageJSON(t *testing.T) {
+	t.Parallel()
+
+	cl := npmTestClient{resolve.NewLocalClient()}
+	cl.addNpmVersion("express", "4.17.0", "debug", "2.6.9")
+	cl.addNpmVersion("express", "4.18.2", "debug", "2.6.9")
+	cl.addNpmVersion("express", "5.0.0", "debug", "4.3.4")
+	cl.addNpmVersion("debug", "2.6.9")
+	cl.addNpmVersion("debug", "4.3.4", "ms", "2.1.2")
+	cl.addNpmVersion("mocha", "10.2.0", "debug", "^2.6.0", "ms", "^2.1.0")
+	cl.addNpmVersion("ms", "2.1.2")
+	cl.addNpmVersion("ms", "2.1.3")
+
+	got, err := extractResolvedPackageJSON(context.Background(), "fixtures/package-json/app/package.json", cl)
+	if err != nil {
+		t.Fatalf("extractResolvedPackageJSON() error: %v", err)
+	}
+
+	want := []lockfile.PackageDetails{
+		{Name: "debug", Version: "2.6.9", Ecosystem: lockfile.NpmEcosystem, CompareAs: lockfile.NpmEcosystem},
+		{Name: "express", Version: "4.18.2", Ecosystem: lockfile.NpmEcosystem, CompareAs: lockfile.NpmEcosystem},
+		{Name: "mocha", Version: "10.2.0", Ecosystem: lockfile.NpmEcosystem, CompareAs: lockfile.NpmEcosystem, DepGroups: []string{"dev"}},
+		{Name: "ms", Version: "2.1.3", Ecosystem: lockfile.NpmEcosystem, CompareAs: lockfile.NpmEcosystem, DepGroups: []string{"dev"}},
+	}
+
+	slices.SortFunc(got, func(a, b lockfile.PackageDetails) int { return strings.Compare(a.Name, b.Name) })
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("extractResolvedPackageJSON() (-want +got):\n%s", diff)
+	}
+}
+
+func Test_hasNpmLockfile(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{path: "fixtures/package-json/app/package.json", want: false},
+		{path: "fixtures/package-json/with-lock/package.json", want: true},
+		{path: "fixtures/package-json/with-lock/packages/web/package.json", want: true},
+	}
+	for _, tt := range tests {
+		if got := hasNpmLockfile(tt.path); got != tt.want {
+			t.Errorf("hasNpmLockfile(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
@@ -2,18 +2,26 @@ package osvscanner
 
 import (
 	"bufio"
+	"bytes"
+	"context"
+	"crypto"
 	"crypto/md5" //nolint:gosec
 	"errors"
 	"fmt"
+	"io"
 	"io/fs"
 	"os"
 	"os/exec"
 	"path"
 	"path/filepath"
+	"slices"
 	"strings"
 
+	"github.com/google/osv-scanner/internal/kubernetes"
 	"github.com/google/osv-scanner/internal/local"
 	"github.com/google/osv-scanner/internal/output"
+	"github.com/google/osv-scanner/internal/resolution/datasource"
+	"github.com/google/osv-scanner/internal/resolution/manifest"
 	"github.com/google/osv-scanner/internal/sbom"
 	"github.com/google/osv-scanner/internal/semantic"
 	"github.com/google/osv-scanner/internal/version"
@@ -34,6 +42,7 @@ import (
 type ScannerActions struct {
 	LockfilePaths        []string
 	SBOMPaths            []string
+	PURLFilePaths        []string
 	DirectoryPaths       []string
 	GitCommits           []string
 	Recursive            bool
@@ -43,6 +52,11 @@ type ScannerActions struct {
 	ConfigOverridePath   string
 	CallAnalysisStates   map[string]bool
 
+	// SBOMAttestationKeyPath is the path to the public key that the SBOMs are in-toto attestations
+	// signed by, which are verified before the SBOMs that they attest to are scanned. Images that
+	// have an SBOM attested to by the key in their registry are scanned with it instead of being pulled
+	SBOMAttestationKeyPath string
+
 	ExperimentalScannerActions
 }
 
@@ -54,6 +68,40 @@ type ExperimentalScannerActions struct {
 	ScanLicensesAllowlist []string
 
 	LocalDBPath string
+	// MavenRegistry is the URL of the Maven repository to fetch parent poms and BOMs from,
+	// or empty to use Maven Central
+	MavenRegistry string
+	// ResolvePackageJSON scans package.json files that do not have a lockfile by resolving
+	// their dependencies from the npm registry, which is only an approximation of what is installed
+	ResolvePackageJSON bool
+	// ImageReferences are images in registries, such as "ghcr.io/org/app:tag", whose
+	// filesystems are pulled without a Docker daemon and scanned like a directory
+	ImageReferences []string
+	// ImagePlatforms are the platforms to scan images for, like "linux/arm64" or "all" for every
+	// platform that they are built for, which is the platform of this machine if it is empty
+	ImagePlatforms []string
+	// Dockerfiles are scanned for the packages that they install at an exact version, and
+	// the images that their stages are built from are scanned like ImageReferences
+	Dockerfiles []string
+	// HideBaseImageVulns hides the vulnerabilities of the packages of images that were introduced by
+	// the image that they were built on, which can only be fixed by rebuilding on a newer base image
+	HideBaseImageVulns bool
+	// ImageArchives are images that have been exported to the filesystem, either as tarballs
+	// created by `docker save` or as OCI image layouts, which are scanned like ImageReferences
+	ImageArchives []string
+	// FirmwareImages are the images of the firmware of embedded devices, like squashfs filesystems and
+	// initramfs, whose root filesystems are extracted and scanned like the filesystems of images
+	FirmwareImages []string
+	// ContainerRuntimes are the runtimes whose running containers are scanned, see ContainerRuntimes
+	ContainerRuntimes []string
+
+	// ScanKubernetes scans the images that the pods of the cluster of a kubeconfig are running,
+	// which is read from KubeconfigPath, or where kubectl reads it from if that is empty
+	ScanKubernetes    bool
+	KubeconfigPath    string
+	KubernetesContext string
+	// KubernetesNamespace is the namespace whose pods are scanned, or empty for every namespace
+	KubernetesNamespace string
 }
 
 // NoPackagesFoundErr for when no packages are found during a scan.
@@ -86,6 +134,32 @@ var (
 		"vendor":      {},
 		"vendored":    {},
 	}
+
+	// systemPackageDatabases are the databases of installed packages that are scanned
+	// by where they live in a filesystem, keyed by what they are parsed as
+	systemPackageDatabases = map[string]lockfile.Extractor{
+		"apk-installed": lockfile.ApkInstalledExtractor{},
+		"dpkg-status":   lockfile.DpkgStatusExtractor{},
+		"opkg-status":   lockfile.OpkgStatusExtractor{},
+		"rpm-db":        lockfile.RpmDatabaseExtractor{},
+	}
+
+	// artifactExtractors are the artifacts of applications, like binaries and the packages installed by
+	// their package managers, which are scanned in the filesystems of images as they do not have lockfiles
+	artifactExtractors = map[string]lockfile.Extractor{
+		"c-library":           lockfile.CLibraryExtractor{},
+		"dotnet-assembly":     lockfile.DotnetAssemblyExtractor{},
+		"dotnet-deps-json":    lockfile.DotnetDepsJSONExtractor{},
+		"gemspec":             lockfile.GemspecExtractor{},
+		"go-binary":           lockfile.GoBinaryExtractor{},
+		"java-archive":        lockfile.JavaArchiveExtractor{},
+		"node-modules":        lockfile.NodeModulesExtractor{},
+		"npm-pack":            lockfile.NpmPackExtractor{},
+		"python-dist-info":    lockfile.PythonDistInfoExtractor{},
+		"python-distribution": lockfile.PythonDistributionExtractor{},
+		"runtime":             lockfile.RuntimeExtractor{},
+		"rust-binary":         lockfile.RustBinaryExtractor{},
+	}
 )
 
 const (
@@ -99,7 +173,8 @@ const (
 //   - Any lockfiles with scanLockfile
 //   - Any SBOM files with scanSBOMFile
 //   - Any git repositories with scanGit
-func scanDir(r reporter.Reporter, dir string, skipGit bool, recursive bool, useGitIgnore bool, compareOffline bool) ([]scannedPackage, error) {
+//   - Any artifacts of applications if scanArtifacts is set, see artifactExtractors
+func scanDir(r reporter.Reporter, dir string, skipGit bool, recursive bool, useGitIgnore bool, scanArtifacts bool, compareOffline bool, fetchers resolutionFetchers) ([]scannedPackage, error) {
 	var ignoreMatcher *gitIgnoreMatcher
 	if useGitIgnore {
 		var err error
@@ -156,12 +231,45 @@ func scanDir(r reporter.Reporter, dir string, skipGit bool, recursive bool, useG
 
 		if !info.IsDir() {
 			if extractor, _ := lockfile.FindExtractor(path, ""); extractor != nil {
-				pkgs, err := scanLockfile(r, path, "")
+				pkgs, err := scanLockfile(r, path, "", fetchers)
+				if err != nil {
+					r.Errorf("Attempted to scan lockfile but failed: %s\n", path)
+				}
+				scannedPackages = append(scannedPackages, pkgs...)
+			}
+			// the databases of system package managers have too generic a name to be found by
+			// FindExtractor, so they are scanned based on where they live in a filesystem
+			for parseAs, extractor := range systemPackageDatabases {
+				if !extractor.ShouldExtract(path) {
+					continue
+				}
+
+				pkgs, err := scanLockfile(r, path, parseAs, fetchers)
 				if err != nil {
 					r.Errorf("Attempted to scan lockfile but failed: %s\n", path)
 				}
 				scannedPackages = append(scannedPackages, pkgs...)
 			}
+			if scanArtifacts {
+				for parseAs, extractor := range artifactExtractors {
+					if !extractor.ShouldExtract(path) {
+						continue
+					}
+
+					pkgs, err := scanLockfile(r, path, parseAs, fetchers)
+					if err != nil {
+						r.Errorf("Attempted to scan artifact but failed: %s\n", path)
+					}
+					scannedPackages = append(scannedPackages, pkgs...)
+				}
+			}
+			if fetchers.packageJSON != nil && info.Name() == "package.json" && !isInNodeModules(path) && !hasNpmLockfile(path) {
+				pkgs, err := scanPackageJSON(r, path, fetchers.packageJSON)
+				if err != nil {
+					r.Errorf("Attempted to resolve package.json but failed: %s: %v\n", path, err)
+				}
+				scannedPackages = append(scannedPackages, pkgs...)
+			}
 			// No need to check for error
 			// If scan fails, it means it isn't a valid SBOM file,
 			// so just move onto the next file
@@ -188,6 +296,17 @@ func scanDir(r reporter.Reporter, dir string, skipGit bool, recursive bool, useG
 	})
 }
 
+// resolutionFetchers fetch what is needed to resolve the versions of packages in manifests,
+// and are nil when scanning offline
+type resolutionFetchers struct {
+	pom              manifest.POMFetcher
+	vcpkgBaseline    lockfile.VcpkgBaselineFetcher
+	pypiVersions     lockfile.PyPIVersionsFetcher
+	rubyGemsVersions lockfile.RubyGemsVersionsFetcher
+	// packageJSON is only set when resolving package.json files without a lockfile is enabled
+	packageJSON func(ctx context.Context, path string) ([]lockfile.PackageDetails, error)
+}
+
 type gitIgnoreMatcher struct {
 	matcher  gitignore.Matcher
 	repoPath string
@@ -332,33 +451,125 @@ func (m *gitIgnoreMatcher) match(absPath string, isDir bool) (bool, error) {
 }
 
 // scanLockfile will load, identify, and parse the lockfile path passed in, and add the dependencies specified
-// within to `query`
-func scanLockfile(r reporter.Reporter, path string, parseAs string) ([]scannedPackage, error) {
+// within to `query`.
+// The fetchers that are set are used to resolve the versions of packages in manifests.
+func scanLockfile(r reporter.Reporter, path string, parseAs string, fetchers resolutionFetchers) ([]scannedPackage, error) {
 	var err error
 	var parsedLockfile lockfile.Lockfile
 
 	f, err := lockfile.OpenLocalDepFile(path)
 
 	if err == nil {
-		// special case for the APK and DPKG parsers because they have a very generic name while
-		// living at a specific location, so they are not included in the map of parsers
-		// used by lockfile.Parse to avoid false-positives when scanning projects
+		// special case for the APK, DPKG, OPKG, and image manifest parsers because they have a very
+		// generic name while living at a specific location, so they are not included in the map
+		// of parsers used by lockfile.Parse to avoid false-positives when scanning projects, and
+		// likewise for the artifacts of applications, which are only looked for in images
 		switch parseAs {
 		case "apk-installed":
 			parsedLockfile, err = lockfile.FromApkInstalled(path)
 		case "dpkg-status":
 			parsedLockfile, err = lockfile.FromDpkgStatus(path)
+		case "opkg-status":
+			parsedLockfile, err = lockfile.FromOpkgStatus(path)
+		case "rpm-db":
+			parsedLockfile, err = lockfile.FromRpmDatabase(path)
+		case "openwrt-manifest":
+			parsedLockfile, err = lockfile.FromOpenWrtManifest(path)
+		case "yocto-image-manifest":
+			parsedLockfile, err = lockfile.FromYoctoImageManifest(path)
 		case "osv-scanner":
 			parsedLockfile, err = lockfile.FromOSVScannerResults(path)
+		case "c-library":
+			parsedLockfile, err = lockfile.FromCLibrary(path)
+		case "dotnet-assembly":
+			parsedLockfile, err = lockfile.FromDotnetAssembly(path)
+		case "dotnet-deps-json":
+			parsedLockfile, err = lockfile.FromDotnetDepsJSON(path)
+		case "gemspec":
+			parsedLockfile, err = lockfile.FromGemspec(path)
+		case "go-binary":
+			parsedLockfile, err = lockfile.FromGoBinary(path)
+		case "java-archive":
+			parsedLockfile, err = lockfile.FromJavaArchive(path)
+		case "node-modules":
+			parsedLockfile, err = lockfile.FromNodeModules(path)
+		case "npm-pack":
+			parsedLockfile, err = lockfile.FromNpmPack(path)
+		case "python-dist-info":
+			parsedLockfile, err = lockfile.FromPythonDistInfo(path)
+		case "python-distribution":
+			parsedLockfile, err = lockfile.FromPythonDistribution(path)
+		case "runtime":
+			parsedLockfile, err = lockfile.FromRuntime(path)
+		case "rust-binary":
+			parsedLockfile, err = lockfile.FromRustBinary(path)
 		default:
 			parsedLockfile, err = lockfile.ExtractDeps(f, parseAs)
 		}
 	}
 
+	// binary bun.lockb files cannot be parsed, which is not a failure of the scan as
+	// Bun can be told to also write the dependencies into a text bun.lock
+	if errors.Is(err, lockfile.ErrBunBinaryLockfile) {
+		r.Warnf("Skipping %s as %v\n", path, lockfile.ErrBunBinaryLockfile)
+
+		return []scannedPackage{}, nil
+	}
+
 	if err != nil {
 		return nil, err
 	}
 
+	if parsedLockfile.ParsedAs == "pom.xml" && fetchers.pom != nil {
+		pkgs, err := extractMavenEffectivePOM(context.Background(), path, fetchers.pom)
+		if err != nil {
+			r.Warnf("Failed to resolve the effective pom of %s, so versions inherited from parents and BOMs are missing: %v\n", path, err)
+		} else {
+			parsedLockfile.Packages = pkgs
+		}
+	}
+
+	if parsedLockfile.ParsedAs == "vcpkg.json" && fetchers.vcpkgBaseline != nil {
+		pkgs, err := extractVcpkgManifest(path, fetchers.vcpkgBaseline)
+		if err != nil {
+			r.Warnf("Failed to resolve the vcpkg baseline of %s, so only versions set in the manifest are known: %v\n", path, err)
+		} else {
+			parsedLockfile.Packages = pkgs
+		}
+	}
+
+	// a pyproject.toml or Gemfile is only scanned when it does not have a lockfile, so the versions
+	// of its dependencies are what their requirements resolve to rather than what is installed
+	sourceType := "lockfile"
+	if _, ok := artifactExtractors[parsedLockfile.ParsedAs]; ok {
+		sourceType = "artifact"
+	}
+
+	switch parsedLockfile.ParsedAs {
+	case "pyproject.toml":
+		sourceType = "resolved"
+
+		if fetchers.pypiVersions != nil {
+			pkgs, err := extractPyprojectToml(path, fetchers.pypiVersions)
+			if err != nil {
+				r.Warnf("Failed to resolve the dependencies of %s, so only dependencies pinned to an exact version are known: %v\n", path, err)
+			} else {
+				parsedLockfile.Packages = pkgs
+			}
+		}
+	case "Gemfile":
+		sourceType = "resolved"
+
+		if fetchers.rubyGemsVersions != nil {
+			pkgs, err := extractGemfile(path, fetchers.rubyGemsVersions)
+			if err != nil {
+				r.Warnf("Failed to resolve the gems of %s, so only gems pinned to an exact version are known: %v\n", path, err)
+			} else {
+				parsedLockfile.Packages = pkgs
+			}
+		}
+	}
+
 	parsedAsComment := ""
 
 	if parseAs != "" {
@@ -383,7 +594,39 @@ func scanLockfile(r reporter.Reporter, path string, parseAs string) ([]scannedPa
 			DepGroups: pkgDetail.DepGroups,
 			Source: models.SourceInfo{
 				Path: path,
-				Type: "lockfile",
+				Type: sourceType,
+			},
+		}
+	}
+
+	return packages, nil
+}
+
+// scanPackageJSON resolves the dependencies of a package.json that does not have a lockfile, which
+// are reported as "resolved" rather than "lockfile" as they might not be the versions that are installed
+func scanPackageJSON(r reporter.Reporter, path string, resolve func(ctx context.Context, path string) ([]lockfile.PackageDetails, error)) ([]scannedPackage, error) {
+	pkgs, err := resolve(context.Background(), path)
+	if err != nil {
+		return nil, err
+	}
+
+	r.Infof(
+		"Resolved %s without a lockfile and found %d %s\n",
+		path,
+		len(pkgs),
+		output.Form(len(pkgs), "package", "packages"),
+	)
+
+	packages := make([]scannedPackage, len(pkgs))
+	for i, pkgDetail := range pkgs {
+		packages[i] = scannedPackage{
+			Name:      pkgDetail.Name,
+			Version:   pkgDetail.Version,
+			Ecosystem: pkgDetail.Ecosystem,
+			DepGroups: pkgDetail.DepGroups,
+			Source: models.SourceInfo{
+				Path: path,
+				Type: "resolved",
 			},
 		}
 	}
@@ -394,41 +637,101 @@ func scanLockfile(r reporter.Reporter, path string, parseAs string) ([]scannedPa
 // scanSBOMFile will load, identify, and parse the SBOM path passed in, and add the dependencies specified
 // within to `query`
 func scanSBOMFile(r reporter.Reporter, path string, fromFSScan bool) ([]scannedPackage, error) {
+	// Skip if filename is not usually a sbom file of any format.
+	// Only do this if this is being done in a filesystem scanning context, where we need to be
+	// careful about spending too much time attempting to parse unrelated files.
+	// If this is coming from an explicit scan argument, be more relaxed here since it's common for
+	// filenames to not conform to expected filename standards.
+	if fromFSScan && !slices.ContainsFunc(sbom.Providers, func(provider sbom.Reader) bool {
+		return provider.MatchesRecognizedFileNames(path)
+	}) {
+		return nil, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return scanSBOM(r, models.SourceInfo{Path: path, Type: "sbom"}, file, fromFSScan)
+}
+
+// scanSBOMAttestation will verify the signature of the in-toto attestation at the path passed in
+// with the key, and then identify and parse the SBOM that it attests to like scanSBOMFile
+func scanSBOMAttestation(r reporter.Reporter, path string, key crypto.PublicKey) ([]scannedPackage, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	attestation, err := sbom.ReadAttestation(file, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify attestation %s: %w", path, err)
+	}
+
+	r.Infof("Verified the signature of the attestation of the %s SBOM in %s\n", attestation.PredicateType, path)
+
+	source := models.SourceInfo{Path: path, Type: "sbom", Attestation: "verified"}
+
+	return scanSBOM(r, source, bytes.NewReader(attestation.SBOM), false)
+}
+
+// scanSBOM will identify and parse the content of the SBOM from the source passed in
+func scanSBOM(r reporter.Reporter, source models.SourceInfo, content io.ReadSeeker, fromFSScan bool) ([]scannedPackage, error) {
+	path := source.Path
+
 	var errs []error
 	var packages []scannedPackage
 	for _, provider := range sbom.Providers {
 		if fromFSScan && !provider.MatchesRecognizedFileNames(path) {
-			// Skip if filename is not usually a sbom file of this format.
-			// Only do this if this is being done in a filesystem scanning context, where we need to be
-			// careful about spending too much time attempting to parse unrelated files.
-			// If this is coming from an explicit scan argument, be more relaxed here since it's common for
-			// filenames to not conform to expected filename standards.
 			continue
 		}
 
-		// Opening file inside loop is OK, since providers is not very long,
-		// and it is unlikely that multiple providers accept the same file name
-		file, err := os.Open(path)
-		if err != nil {
-			return nil, err
-		}
-		defer file.Close()
-
 		ignoredCount := 0
-		err = provider.GetPackages(file, func(id sbom.Identifier) error {
-			_, err := models.PURLToPackage(id.PURL)
+		cpeOnlyCount := 0
+		seen := make(map[string]bool)
+		err := provider.GetPackages(content, func(id sbom.Identifier) error {
+			if id.PURL == "" && id.CPE != "" {
+				cpeOnlyCount++
+				return nil
+			}
+
+			pkg, err := models.PURLToPackage(id.PURL)
 			if err != nil {
 				ignoredCount++
 				//nolint:nilerr
 				return nil
 			}
-			packages = append(packages, scannedPackage{
-				PURL: id.PURL,
-				Source: models.SourceInfo{
-					Path: path,
-					Type: "sbom",
-				},
-			})
+
+			// The same package can be listed once for each architecture that it is
+			// installed for, which does not change the vulnerabilities it has
+			key := pkg.Ecosystem + "/" + pkg.Name + "@" + pkg.Version + "#" + id.Scope
+			if seen[key] {
+				return nil
+			}
+			seen[key] = true
+
+			scanned := scannedPackage{
+				PURL:   id.PURL,
+				Source: source,
+			}
+
+			// Packages of a specific release of a distribution, based on their distro qualifier,
+			// are queried by their ecosystem as the OSV API does not use the qualifiers of PURLs
+			if strings.Contains(pkg.Ecosystem, ":") {
+				scanned.Name = pkg.Name
+				scanned.Version = pkg.Version
+				scanned.Ecosystem = lockfile.Ecosystem(pkg.Ecosystem)
+			}
+
+			// Required is the default scope, so only the other scopes are worth reporting
+			if id.Scope != "" && id.Scope != "required" {
+				scanned.DepGroups = []string{id.Scope}
+			}
+
+			packages = append(packages, scanned)
 
 			return nil
 		})
@@ -459,6 +762,13 @@ func scanSBOMFile(r reporter.Reporter, path string, fromFSScan bool) ([]scannedP
 					output.Form(ignoredCount, "package", "packages"),
 				)
 			}
+			if cpeOnlyCount > 0 {
+				r.Infof(
+					"Ignored %d %s that only have a CPE, which cannot be scanned\n",
+					cpeOnlyCount,
+					output.Form(cpeOnlyCount, "package", "packages"),
+				)
+			}
 
 			return packages, nil
 		}
@@ -483,6 +793,75 @@ func scanSBOMFile(r reporter.Reporter, path string, fromFSScan bool) ([]scannedP
 	return packages, nil
 }
 
+// scanPURLFile will load the Package URLs listed one per line in the file at path, or standard input
+// if path is "-", and add them as the ecosystem/name/version of the packages that they are for
+func scanPURLFile(r reporter.Reporter, path string) ([]scannedPackage, error) {
+	source := models.SourceInfo{Path: path, Type: "purl"}
+
+	var in io.Reader = os.Stdin
+
+	if path == "-" {
+		source.Path = "stdin"
+	} else {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+
+		in = file
+	}
+
+	var packages []scannedPackage
+	ignoredCount := 0
+	scanner := bufio.NewScanner(in)
+
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+
+		// Blank lines and comments are allowed, so that lists can be annotated
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		pkg, err := models.PURLToPackage(line)
+		if err != nil {
+			r.Verbosef("Ignoring invalid PURL on line %d of %s: %s\n", lineNumber, source.Path, err)
+			ignoredCount++
+
+			continue
+		}
+
+		packages = append(packages, scannedPackage{
+			PURL:      line,
+			Name:      pkg.Name,
+			Version:   pkg.Version,
+			Ecosystem: lockfile.Ecosystem(pkg.Ecosystem),
+			Source:    source,
+		})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", source.Path, err)
+	}
+
+	r.Infof(
+		"Scanned %s as a list of PURLs and found %d %s\n",
+		source.Path,
+		len(packages),
+		output.Form(len(packages), "package", "packages"),
+	)
+	if ignoredCount > 0 {
+		r.Infof(
+			"Ignored %d %s with invalid PURLs\n",
+			ignoredCount,
+			output.Form(ignoredCount, "package", "packages"),
+		)
+	}
+
+	return packages, nil
+}
+
 func getCommitSHA(repoDir string) (string, error) {
 	repo, err := git.PlainOpen(repoDir)
 	if err != nil {
@@ -694,6 +1073,8 @@ type scannedPackage struct {
 	Version   string
 	Source    models.SourceInfo
 	DepGroups []string
+	// ImageOrigin is the layer that introduced the package, if it was scanned in a container image
+	ImageOrigin *models.ImageOrigin
 }
 
 // Perform osv scanner action, with optional reporter to output information
@@ -737,6 +1118,24 @@ func DoScan(actions ScannerActions, r reporter.Reporter) (models.VulnerabilityRe
 		scannedPackages = append(scannedPackages, pkgs...)
 	}
 
+	// Parent poms and BOMs are fetched from the Maven repository to compute the effective pom,
+	// vcpkg baselines from GitHub, and the versions of Python packages and gems from
+	// PyPI and rubygems.org, which can't be done offline
+	var fetchers resolutionFetchers
+	if !actions.CompareOffline {
+		mavenAPI, err := datasource.NewMavenRegistryAPIClient(datasource.RegistryConfig{URL: actions.MavenRegistry})
+		if err != nil {
+			return models.VulnerabilityResults{}, err
+		}
+		fetchers.pom = mavenAPI.POM
+		fetchers.vcpkgBaseline = newVcpkgBaselineClient(vcpkgBaselineURL).fetch
+		fetchers.pypiVersions = newPypiVersionsClient(pypiURL).fetch
+		fetchers.rubyGemsVersions = newRubyGemsVersionsClient(rubyGemsURL).fetch
+		if actions.ResolvePackageJSON {
+			fetchers.packageJSON = resolvePackageJSON
+		}
+	}
+
 	for _, lockfileElem := range actions.LockfilePaths {
 		parseAs, lockfilePath := parseLockfilePath(lockfileElem)
 		lockfilePath, err := filepath.Abs(lockfilePath)
@@ -744,19 +1143,48 @@ func DoScan(actions ScannerActions, r reporter.Reporter) (models.VulnerabilityRe
 			r.Errorf("Failed to resolved path with error %s\n", err)
 			return models.VulnerabilityResults{}, err
 		}
-		pkgs, err := scanLockfile(r, lockfilePath, parseAs)
+		pkgs, err := scanLockfile(r, lockfilePath, parseAs, fetchers)
 		if err != nil {
 			return models.VulnerabilityResults{}, err
 		}
 		scannedPackages = append(scannedPackages, pkgs...)
 	}
 
+	var attestationKey crypto.PublicKey
+	if actions.SBOMAttestationKeyPath != "" {
+		var err error
+		attestationKey, err = sbom.LoadPublicKey(actions.SBOMAttestationKeyPath)
+		if err != nil {
+			return models.VulnerabilityResults{}, fmt.Errorf("failed to load attestation key: %w", err)
+		}
+	}
+
 	for _, sbomElem := range actions.SBOMPaths {
 		sbomElem, err := filepath.Abs(sbomElem)
 		if err != nil {
 			return models.VulnerabilityResults{}, fmt.Errorf("failed to resolved path with error %w", err)
 		}
-		pkgs, err := scanSBOMFile(r, sbomElem, false)
+		var pkgs []scannedPackage
+		if attestationKey != nil {
+			pkgs, err = scanSBOMAttestation(r, sbomElem, attestationKey)
+		} else {
+			pkgs, err = scanSBOMFile(r, sbomElem, false)
+		}
+		if err != nil {
+			return models.VulnerabilityResults{}, err
+		}
+		scannedPackages = append(scannedPackages, pkgs...)
+	}
+
+	for _, purlFile := range actions.PURLFilePaths {
+		if purlFile != "-" {
+			var err error
+			purlFile, err = filepath.Abs(purlFile)
+			if err != nil {
+				return models.VulnerabilityResults{}, fmt.Errorf("failed to resolved path with error %w", err)
+			}
+		}
+		pkgs, err := scanPURLFile(r, purlFile)
 		if err != nil {
 			return models.VulnerabilityResults{}, err
 		}
@@ -769,7 +1197,100 @@ func DoScan(actions ScannerActions, r reporter.Reporter) (models.VulnerabilityRe
 
 	for _, dir := range actions.DirectoryPaths {
 		r.Infof("Scanning dir %s\n", dir)
-		pkgs, err := scanDir(r, dir, actions.SkipGit, actions.Recursive, !actions.NoIgnore, actions.CompareOffline)
+		pkgs, err := scanDir(r, dir, actions.SkipGit, actions.Recursive, !actions.NoIgnore, false, actions.CompareOffline, fetchers)
+		if err != nil {
+			return models.VulnerabilityResults{}, err
+		}
+		scannedPackages = append(scannedPackages, pkgs...)
+	}
+
+	imageRefs := slices.Clone(actions.ImageReferences)
+	for _, dockerfile := range actions.Dockerfiles {
+		dockerfile, err := filepath.Abs(dockerfile)
+		if err != nil {
+			return models.VulnerabilityResults{}, fmt.Errorf("failed to resolved path with error %w", err)
+		}
+		pkgs, err := scanLockfile(r, dockerfile, "Dockerfile", fetchers)
+		if err != nil {
+			return models.VulnerabilityResults{}, err
+		}
+		scannedPackages = append(scannedPackages, pkgs...)
+
+		bases, err := dockerfileBaseImages(dockerfile)
+		if err != nil {
+			return models.VulnerabilityResults{}, err
+		}
+		for _, base := range bases {
+			if !slices.Contains(imageRefs, base) {
+				imageRefs = append(imageRefs, base)
+			}
+		}
+	}
+
+	var detectedImages []models.ImageDetails
+
+	scannedImages := make([]scannedImage, 0, len(imageRefs))
+	for _, imageRef := range imageRefs {
+		scanned, err := scanImage(r, imageRef, actions.ImagePlatforms, attestationKey, actions.CompareOffline, fetchers)
+		if err != nil {
+			return models.VulnerabilityResults{}, err
+		}
+		scannedPackages = append(scannedPackages, scanned.packages...)
+		scannedImages = append(scannedImages, scanned)
+		detectedImages = append(detectedImages, scanned.details...)
+	}
+
+	for _, archive := range actions.ImageArchives {
+		archive, err := filepath.Abs(archive)
+		if err != nil {
+			return models.VulnerabilityResults{}, fmt.Errorf("failed to resolved path with error %w", err)
+		}
+		pkgs, details, err := scanImageArchive(r, archive, actions.CompareOffline, fetchers)
+		if err != nil {
+			return models.VulnerabilityResults{}, err
+		}
+		scannedPackages = append(scannedPackages, pkgs...)
+		detectedImages = append(detectedImages, details...)
+	}
+
+	for _, firmware := range actions.FirmwareImages {
+		firmware, err := filepath.Abs(firmware)
+		if err != nil {
+			return models.VulnerabilityResults{}, fmt.Errorf("failed to resolved path with error %w", err)
+		}
+		pkgs, details, err := scanFirmware(r, firmware, actions.CompareOffline, fetchers)
+		if err != nil {
+			return models.VulnerabilityResults{}, err
+		}
+		scannedPackages = append(scannedPackages, pkgs...)
+		if details != nil {
+			detectedImages = append(detectedImages, *details)
+		}
+	}
+
+	var workloads []kubernetes.Workload
+	if actions.ScanKubernetes {
+		var err error
+		workloads, err = listWorkloads(r, actions.KubeconfigPath, actions.KubernetesContext, actions.KubernetesNamespace)
+		if err != nil {
+			return models.VulnerabilityResults{}, err
+		}
+
+		// an image that cannot be pulled should not stop the rest of the cluster from being scanned
+		for _, imageRef := range workloadImages(workloads) {
+			scanned, err := scanImage(r, imageRef, actions.ImagePlatforms, attestationKey, actions.CompareOffline, fetchers)
+			if err != nil {
+				r.Errorf("Failed to scan image %s: %v\n", imageRef, err)
+				continue
+			}
+			scannedPackages = append(scannedPackages, scanned.packages...)
+			scannedImages = append(scannedImages, scanned)
+			detectedImages = append(detectedImages, scanned.details...)
+		}
+	}
+
+	if len(actions.ContainerRuntimes) > 0 {
+		pkgs, err := scanRunningContainers(r, actions.ContainerRuntimes, actions.CompareOffline, fetchers)
 		if err != nil {
 			return models.VulnerabilityResults{}, err
 		}
@@ -799,6 +1320,7 @@ func DoScan(actions ScannerActions, r reporter.Reporter) (models.VulnerabilityRe
 		}
 	}
 	results := buildVulnerabilityResults(r, filteredScannedPackages, vulnsResp, licensesResp, actions)
+	results.Images = detectedImages
 
 	filtered := filterResults(r, &results, &configManager, actions.ShowAllPackages)
 	if filtered > 0 {
@@ -809,6 +1331,39 @@ func DoScan(actions ScannerActions, r reporter.Reporter) (models.VulnerabilityRe
 		)
 	}
 
+	// images are compared after filtering, so that ignored vulnerabilities do not count towards anything
+	for _, scanned := range scannedImages {
+		for _, base := range scanned.bases {
+			results.BaseImages = append(results.BaseImages, recommendBaseImage(r, base, &results, actions, fetchers))
+		}
+
+		if actions.HideBaseImageVulns && len(scanned.bases) == 0 {
+			r.Warnf("The base image of %s is not known, so none of its vulnerabilities are hidden\n", scanned.imageRef)
+		}
+	}
+
+	// base images have to be recommended before the vulnerabilities that they introduced are hidden
+	if actions.HideBaseImageVulns {
+		hidden := filterBaseImageVulns(&results, actions.ShowAllPackages)
+		if hidden > 0 {
+			r.Infof(
+				"Filtered %d %s introduced by base images from output\n",
+				hidden,
+				output.Form(hidden, "vulnerability", "vulnerabilities"),
+			)
+		}
+	}
+
+	for _, scanned := range scannedImages {
+		if len(scanned.platforms) > 1 {
+			results.PlatformDifferences = append(results.PlatformDifferences, comparePlatforms(&results, scanned))
+		}
+	}
+
+	if actions.ScanKubernetes {
+		results.Workloads = aggregateWorkloads(&results, workloads)
+	}
+
 	if len(results.Results) > 0 {
 		// Determine the correct error to return.
 		// TODO: in the next breaking release of osv-scanner, consider
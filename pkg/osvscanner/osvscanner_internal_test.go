@@ -112,3 +112,42 @@ func Test_scanGit(t *testing.T) {
 		t.Errorf("can't find .git folder")
 	}
 }
+
+func Test_scanPURLFile(t *testing.T) {
+	t.Parallel()
+
+	source := models.SourceInfo{Path: "fixtures/purls.txt", Type: "purl"}
+
+	want := []scannedPackage{
+		{
+			PURL:      "pkg:npm/lodash@4.17.20",
+			Name:      "lodash",
+			Version:   "4.17.20",
+			Ecosystem: "npm",
+			Source:    source,
+		},
+		{
+			PURL:      "pkg:pypi/django@3.2.0",
+			Name:      "django",
+			Version:   "3.2.0",
+			Ecosystem: "PyPI",
+			Source:    source,
+		},
+		{
+			PURL:      "pkg:deb/debian/curl@7.74.0-1.3?distro=debian-11",
+			Name:      "curl",
+			Version:   "7.74.0-1.3",
+			Ecosystem: "Debian:11",
+			Source:    source,
+		},
+	}
+
+	got, err := scanPURLFile(&reporter.VoidReporter{}, "fixtures/purls.txt")
+	if err != nil {
+		t.Fatalf("scanPURLFile() error = %v", err)
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("scanPURLFile() (-want +got):\n%s", diff)
+	}
+}
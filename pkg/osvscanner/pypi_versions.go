@@ -0,0 +1,115 @@
+package osvscanner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/google/osv-scanner/internal/cachedregexp"
+	"github.com/google/osv-scanner/pkg/lockfile"
+)
+
+// pypiURL is where the versions of packages published to PyPI are fetched from,
+// using the JSON API at <name>/json
+const pypiURL = "https://pypi.org/pypi"
+
+// pypiVersionsClient fetches the versions of packages that have been published to PyPI,
+// caching them as the same dependencies are often used by many pyproject.toml files
+type pypiVersionsClient struct {
+	baseURL string
+
+	mu       sync.Mutex
+	versions map[string][]string
+}
+
+func newPypiVersionsClient(baseURL string) *pypiVersionsClient {
+	return &pypiVersionsClient{
+		baseURL:  baseURL,
+		versions: make(map[string][]string),
+	}
+}
+
+type pypiReleaseFile struct {
+	Yanked bool `json:"yanked"`
+}
+
+func (c *pypiVersionsClient) fetch(name string) ([]string, error) {
+	// the name is used in the URL, so make sure it is only a name
+	if !cachedregexp.MustCompile(`^[A-Za-z0-9._-]+$`).MatchString(name) {
+		return nil, fmt.Errorf("invalid PyPI package name %q", name)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if versions, ok := c.versions[name]; ok {
+		return versions, nil
+	}
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, c.baseURL+"/"+name+"/json", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// the package might only be available from another index
+	if resp.StatusCode == http.StatusNotFound {
+		c.versions[name] = nil
+
+		return nil, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.New(resp.Status)
+	}
+
+	var project struct {
+		Releases map[string][]pypiReleaseFile `json:"releases"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&project); err != nil {
+		return nil, fmt.Errorf("could not parse the versions of %s: %w", name, err)
+	}
+
+	versions := make([]string, 0, len(project.Releases))
+
+	for version, files := range project.Releases {
+		// versions without any files that have not been yanked are
+		// not installed when resolving a range
+		installable := false
+
+		for _, file := range files {
+			installable = installable || !file.Yanked
+		}
+
+		if installable {
+			versions = append(versions, version)
+		}
+	}
+
+	sort.Strings(versions)
+
+	c.versions[name] = versions
+
+	return versions, nil
+}
+
+// extractPyprojectToml extracts the dependencies of the pyproject.toml at path,
+// with the best versions published to PyPI that their specifiers resolve to
+func extractPyprojectToml(path string, fetch lockfile.PyPIVersionsFetcher) ([]lockfile.PackageDetails, error) {
+	f, err := lockfile.OpenLocalDepFile(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return lockfile.PyprojectTomlExtractor{FetchVersions: fetch}.Extract(f)
+}
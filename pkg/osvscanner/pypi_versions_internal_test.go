@@ -0,0 +1,68 @@
+package osvscanner
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_pypiVersionsClient_fetch(t *testing.T) {
+	t.Parallel()
+
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch r.URL.Path {
+		case "/requests/json":
+			_, _ = w.Write([]byte(`{
+  "info": { "name": "requests" },
+  "releases": {
+    "2.31.0": [{ "filename": "requests-2.31.0.tar.gz", "yanked": false }],
+    "2.32.0": [{ "filename": "requests-2.32.0.tar.gz", "yanked": true }],
+    "2.32.3": [
+      { "filename": "requests-2.32.3-py3-none-any.whl", "yanked": false },
+      { "filename": "requests-2.32.3.tar.gz", "yanked": false }
+    ],
+    "3.0.0b1": []
+  }
+}`))
+		case "/broken/json":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	c := newPypiVersionsClient(srv.URL)
+
+	want := []string{"2.31.0", "2.32.3"}
+
+	for i := 0; i < 2; i++ {
+		got, err := c.fetch("requests")
+		if err != nil {
+			t.Fatalf("fetch() error: %v", err)
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("fetch() (-want +got):\n%s", diff)
+		}
+	}
+
+	if requests != 1 {
+		t.Errorf("expected the versions to be requested once, but were requested %d times", requests)
+	}
+
+	if got, err := c.fetch("does-not-exist"); err != nil || len(got) != 0 {
+		t.Errorf("fetch() expected no versions for a package that does not exist, got %v, %v", got, err)
+	}
+
+	if _, err := c.fetch("broken"); err == nil {
+		t.Errorf("fetch() expected an error when the versions could not be fetched")
+	}
+
+	if _, err := c.fetch("../../simple/requests"); err == nil {
+		t.Errorf("fetch() expected an error for a name that is not a package name")
+	}
+}
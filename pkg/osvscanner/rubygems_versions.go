@@ -0,0 +1,102 @@
+package osvscanner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+
+	"github.com/google/osv-scanner/internal/cachedregexp"
+	"github.com/google/osv-scanner/pkg/lockfile"
+)
+
+// rubyGemsURL is where the versions of gems published to rubygems.org are fetched from,
+// using the API at versions/<name>.json
+const rubyGemsURL = "https://rubygems.org/api/v1"
+
+// rubyGemsVersionsClient fetches the versions of gems that have been published to rubygems.org,
+// caching them as the same gems are often used by many Gemfiles
+type rubyGemsVersionsClient struct {
+	baseURL string
+
+	mu       sync.Mutex
+	versions map[string][]string
+}
+
+func newRubyGemsVersionsClient(baseURL string) *rubyGemsVersionsClient {
+	return &rubyGemsVersionsClient{
+		baseURL:  baseURL,
+		versions: make(map[string][]string),
+	}
+}
+
+func (c *rubyGemsVersionsClient) fetch(name string) ([]string, error) {
+	// the name is used in the URL, so make sure it is only a name
+	if !cachedregexp.MustCompile(`^[A-Za-z0-9._-]+$`).MatchString(name) {
+		return nil, fmt.Errorf("invalid gem name %q", name)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if versions, ok := c.versions[name]; ok {
+		return versions, nil
+	}
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, c.baseURL+"/versions/"+name+".json", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// the gem might only be available from another source
+	if resp.StatusCode == http.StatusNotFound {
+		c.versions[name] = nil
+
+		return nil, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.New(resp.Status)
+	}
+
+	var gems []struct {
+		Number string `json:"number"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&gems); err != nil {
+		return nil, fmt.Errorf("could not parse the versions of %s: %w", name, err)
+	}
+
+	// each platform that a version was published for is listed separately
+	versions := make([]string, 0, len(gems))
+	for _, gem := range gems {
+		versions = append(versions, gem.Number)
+	}
+
+	slices.Sort(versions)
+	versions = slices.Compact(versions)
+
+	c.versions[name] = versions
+
+	return versions, nil
+}
+
+// extractGemfile extracts the gems of the Gemfile at path, with the best
+// versions published to rubygems.org that their requirements resolve to
+func extractGemfile(path string, fetch lockfile.RubyGemsVersionsFetcher) ([]lockfile.PackageDetails, error) {
+	f, err := lockfile.OpenLocalDepFile(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return lockfile.GemfileExtractor{FetchVersions: fetch}.Extract(f)
+}
@@ -0,0 +1,62 @@
+package osvscanner
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_rubyGemsVersionsClient_fetch(t *testing.T) {
+	t.Parallel()
+
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch r.URL.Path {
+		case "/versions/nokogiri.json":
+			_, _ = w.Write([]byte(`[
+  { "number": "1.15.5", "platform": "x86_64-linux", "prerelease": false },
+  { "number": "1.15.5", "platform": "ruby", "prerelease": false },
+  { "number": "1.16.0.rc1", "platform": "ruby", "prerelease": true },
+  { "number": "1.15.4", "platform": "ruby", "prerelease": false }
+]`))
+		case "/versions/broken.json":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	c := newRubyGemsVersionsClient(srv.URL)
+
+	want := []string{"1.15.4", "1.15.5", "1.16.0.rc1"}
+
+	for i := 0; i < 2; i++ {
+		got, err := c.fetch("nokogiri")
+		if err != nil {
+			t.Fatalf("fetch() error: %v", err)
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("fetch() (-want +got):\n%s", diff)
+		}
+	}
+
+	if requests != 1 {
+		t.Errorf("expected the versions to be requested once, but were requested %d times", requests)
+	}
+
+	if got, err := c.fetch("does-not-exist"); err != nil || len(got) != 0 {
+		t.Errorf("fetch() expected no versions for a gem that does not exist, got %v, %v", got, err)
+	}
+
+	if _, err := c.fetch("broken"); err == nil {
+		t.Errorf("fetch() expected an error when the versions could not be fetched")
+	}
+
+	if _, err := c.fetch("../../gems/nokogiri"); err == nil {
+		t.Errorf("fetch() expected an error for a name that is not a gem name")
+	}
+}
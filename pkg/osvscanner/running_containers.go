@@ -0,0 +1,123 @@
+package osvscanner
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/google/osv-scanner/internal/image"
+	"github.com/google/osv-scanner/internal/output"
+	"github.com/google/osv-scanner/pkg/reporter"
+)
+
+// ContainerRuntimes are the runtimes whose running containers can be scanned
+func ContainerRuntimes() []string {
+	return []string{"docker", "containerd"}
+}
+
+// listContainers lists the containers that the runtime is running, along with
+// the client of the Docker daemon that containers run by Docker are exported with
+func listContainers(ctx context.Context, runtime string) ([]image.Container, *image.DockerClient, error) {
+	switch runtime {
+	case "docker":
+		client, err := image.NewDockerClient(image.DockerHost())
+		if err != nil {
+			return nil, nil, err
+		}
+
+		containers, err := client.Containers(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return containers, client, nil
+	case "containerd":
+		containers, err := image.ContainerdContainers(image.DefaultContainerdStateDir)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to list the containers of containerd: %w", err)
+		}
+
+		return containers, nil, nil
+	}
+
+	return nil, nil, fmt.Errorf("unsupported container runtime %q", runtime)
+}
+
+// scanContainer scans the filesystem of a running container, which is exported through
+// the API of Docker for the containers that it runs, and is read from where containerd
+// has mounted it for the containers that it runs
+func scanContainer(ctx context.Context, r reporter.Reporter, docker *image.DockerClient, container image.Container, compareOffline bool, fetchers resolutionFetchers) ([]scannedPackage, error) {
+	dir := container.Rootfs
+
+	if docker != nil {
+		var err error
+		dir, err = os.MkdirTemp("", "osv-scanner-container-")
+		if err != nil {
+			return nil, err
+		}
+		defer os.RemoveAll(dir)
+
+		if err := docker.Export(ctx, container, dir); err != nil {
+			return nil, err
+		}
+	}
+
+	// the filesystem of a container is not a repository, and has nothing that is ignored by git
+	pkgs, err := scanDir(r, dir, true, true, false, true, compareOffline, fetchers)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range pkgs {
+		pkgs[i].Source.Path = imageSourcePath(container.Name, dir, pkgs[i].Source.Path)
+	}
+
+	return pkgs, nil
+}
+
+// scanRunningContainers scans each of the containers that the runtimes are running, with the packages
+// of each of them reported under the name of the container, in the form that `docker cp` uses
+func scanRunningContainers(r reporter.Reporter, runtimes []string, compareOffline bool, fetchers resolutionFetchers) ([]scannedPackage, error) {
+	ctx := context.Background()
+
+	var scannedPackages []scannedPackage
+
+	for _, runtime := range runtimes {
+		containers, docker, err := listContainers(ctx, runtime)
+		if err != nil {
+			return nil, err
+		}
+
+		r.Infof(
+			"Found %d running %s in %s\n",
+			len(containers),
+			output.Form(len(containers), "container", "containers"),
+			runtime,
+		)
+
+		for _, container := range containers {
+			pkgs, err := scanContainer(ctx, r, docker, container, compareOffline, fetchers)
+			if err != nil {
+				r.Errorf("Failed to scan container %s: %v\n", container.Name, err)
+				continue
+			}
+
+			from := container.Image
+			if from == "" {
+				from = "unknown image"
+			}
+
+			r.Infof(
+				"Scanned container %s (%s) and found %d %s\n",
+				container.Name,
+				from,
+				len(pkgs),
+				output.Form(len(pkgs), "package", "packages"),
+			)
+
+			scannedPackages = append(scannedPackages, pkgs...)
+		}
+	}
+
+	return scannedPackages, nil
+}
@@ -0,0 +1,85 @@
+package osvscanner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/google/osv-scanner/internal/cachedregexp"
+	"github.com/google/osv-scanner/pkg/lockfile"
+)
+
+// vcpkgBaselineURL is where the baselines of the builtin vcpkg registry are fetched from,
+// as the contents of versions/baseline.json at the baseline's commit
+const vcpkgBaselineURL = "https://raw.githubusercontent.com/microsoft/vcpkg"
+
+// vcpkgBaselineClient fetches baselines of the builtin vcpkg registry,
+// caching them as they are large and often shared by every vcpkg.json in a project
+type vcpkgBaselineClient struct {
+	baseURL string
+
+	mu        sync.Mutex
+	baselines map[string]lockfile.VcpkgBaseline
+}
+
+func newVcpkgBaselineClient(baseURL string) *vcpkgBaselineClient {
+	return &vcpkgBaselineClient{
+		baseURL:   baseURL,
+		baselines: make(map[string]lockfile.VcpkgBaseline),
+	}
+}
+
+func (c *vcpkgBaselineClient) fetch(commit string) (lockfile.VcpkgBaseline, error) {
+	// the commit is used in the URL, so make sure it is only a commit
+	if !cachedregexp.MustCompile(`^[0-9a-fA-F]{7,40}$`).MatchString(commit) {
+		return nil, fmt.Errorf("invalid vcpkg baseline %q", commit)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if baseline, ok := c.baselines[commit]; ok {
+		return baseline, nil
+	}
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, c.baseURL+"/"+commit+"/versions/baseline.json", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.New(resp.Status)
+	}
+
+	var baselines struct {
+		Default lockfile.VcpkgBaseline `json:"default"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&baselines); err != nil {
+		return nil, fmt.Errorf("could not parse the vcpkg baseline %s: %w", commit, err)
+	}
+
+	c.baselines[commit] = baselines.Default
+
+	return baselines.Default, nil
+}
+
+// extractVcpkgManifest extracts the packages of the vcpkg.json at path,
+// with the versions that the baseline of the builtin registry resolves to
+func extractVcpkgManifest(path string, fetch lockfile.VcpkgBaselineFetcher) ([]lockfile.PackageDetails, error) {
+	f, err := lockfile.OpenLocalDepFile(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return lockfile.VcpkgManifestExtractor{FetchBaseline: fetch}.Extract(f)
+}
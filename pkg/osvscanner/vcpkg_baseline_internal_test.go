@@ -0,0 +1,61 @@
+package osvscanner
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/lockfile"
+)
+
+func Test_vcpkgBaselineClient_fetch(t *testing.T) {
+	t.Parallel()
+
+	const commit = "3265c187c74914aa5569b75355badebfdbab7987"
+
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path != "/"+commit+"/versions/baseline.json" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`{
+  "default": {
+    "fmt": { "baseline": "10.1.1", "port-version": 1 },
+    "zlib": { "baseline": "1.3", "port-version": 0 }
+  }
+}`))
+	}))
+	defer srv.Close()
+
+	c := newVcpkgBaselineClient(srv.URL)
+
+	want := lockfile.VcpkgBaseline{
+		"fmt":  {Baseline: "10.1.1", PortVersion: 1},
+		"zlib": {Baseline: "1.3", PortVersion: 0},
+	}
+
+	for i := 0; i < 2; i++ {
+		got, err := c.fetch(commit)
+		if err != nil {
+			t.Fatalf("fetch() error: %v", err)
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("fetch() (-want +got):\n%s", diff)
+		}
+	}
+
+	if requests != 1 {
+		t.Errorf("expected the baseline to be requested once, but was requested %d times", requests)
+	}
+
+	if _, err := c.fetch("a42af01b72c28a8e1d7b48107b33e4f286a55ef6"); err == nil {
+		t.Errorf("fetch() expected an error for a baseline that does not exist")
+	}
+
+	if _, err := c.fetch("../../other-repo/main"); err == nil {
+		t.Errorf("fetch() expected an error for a baseline that is not a commit")
+	}
+}
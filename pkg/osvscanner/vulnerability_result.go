@@ -52,6 +52,7 @@ func buildVulnerabilityResults(
 		}
 
 		pkg.DepGroups = rawPkg.DepGroups
+		pkg.ImageOrigin = rawPkg.ImageOrigin
 
 		if len(vulnsResp.Results[i].Vulns) > 0 {
 			includePackage = true
@@ -0,0 +1,58 @@
+package reporter
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/google/osv-scanner/internal/output"
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+// CycloneDXReporter prints the scanned packages and their vulnerabilities as a CycloneDX BOM
+// to stdout. Runtime information will be written to stderr.
+type CycloneDXReporter struct {
+	hasErrored bool
+	stdout     io.Writer
+	stderr     io.Writer
+	level      VerbosityLevel
+}
+
+func NewCycloneDXReporter(stdout io.Writer, stderr io.Writer, level VerbosityLevel) *CycloneDXReporter {
+	return &CycloneDXReporter{
+		stdout:     stdout,
+		stderr:     stderr,
+		level:      level,
+		hasErrored: false,
+	}
+}
+
+func (r *CycloneDXReporter) Errorf(format string, a ...any) {
+	fmt.Fprintf(r.stderr, format, a...)
+	r.hasErrored = true
+}
+
+func (r *CycloneDXReporter) HasErrored() bool {
+	return r.hasErrored
+}
+
+func (r *CycloneDXReporter) Warnf(format string, a ...any) {
+	if WarnLevel <= r.level {
+		fmt.Fprintf(r.stderr, format, a...)
+	}
+}
+
+func (r *CycloneDXReporter) Infof(format string, a ...any) {
+	if InfoLevel <= r.level {
+		fmt.Fprintf(r.stderr, format, a...)
+	}
+}
+
+func (r *CycloneDXReporter) Verbosef(format string, a ...any) {
+	if VerboseLevel <= r.level {
+		fmt.Fprintf(r.stderr, format, a...)
+	}
+}
+
+func (r *CycloneDXReporter) PrintResult(vulnResult *models.VulnerabilityResults) error {
+	return output.PrintCycloneDXReport(vulnResult, r.stdout)
+}
@@ -0,0 +1,58 @@
+package reporter
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/google/osv-scanner/internal/output"
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+// CycloneDXVDRReporter prints the vulnerabilities that were found as a CycloneDX Vulnerability
+// Disclosure Report to stdout. Runtime information will be written to stderr.
+type CycloneDXVDRReporter struct {
+	hasErrored bool
+	stdout     io.Writer
+	stderr     io.Writer
+	level      VerbosityLevel
+}
+
+func NewCycloneDXVDRReporter(stdout io.Writer, stderr io.Writer, level VerbosityLevel) *CycloneDXVDRReporter {
+	return &CycloneDXVDRReporter{
+		stdout:     stdout,
+		stderr:     stderr,
+		level:      level,
+		hasErrored: false,
+	}
+}
+
+func (r *CycloneDXVDRReporter) Errorf(format string, a ...any) {
+	fmt.Fprintf(r.stderr, format, a...)
+	r.hasErrored = true
+}
+
+func (r *CycloneDXVDRReporter) HasErrored() bool {
+	return r.hasErrored
+}
+
+func (r *CycloneDXVDRReporter) Warnf(format string, a ...any) {
+	if WarnLevel <= r.level {
+		fmt.Fprintf(r.stderr, format, a...)
+	}
+}
+
+func (r *CycloneDXVDRReporter) Infof(format string, a ...any) {
+	if InfoLevel <= r.level {
+		fmt.Fprintf(r.stderr, format, a...)
+	}
+}
+
+func (r *CycloneDXVDRReporter) Verbosef(format string, a ...any) {
+	if VerboseLevel <= r.level {
+		fmt.Fprintf(r.stderr, format, a...)
+	}
+}
+
+func (r *CycloneDXVDRReporter) PrintResult(vulnResult *models.VulnerabilityResults) error {
+	return output.PrintCycloneDXVDRReport(vulnResult, r.stdout)
+}
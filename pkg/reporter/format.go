@@ -5,7 +5,7 @@ import (
 	"io"
 )
 
-var format = []string{"table", "json", "markdown", "sarif", "gh-annotations"}
+var format = []string{"table", "json", "markdown", "sarif", "gh-annotations", "cyclonedx", "cyclonedx-vdr", "spdx-json", "spdx-tag-value"}
 
 func Format() []string {
 	return format
@@ -25,6 +25,14 @@ func New(format string, stdout, stderr io.Writer, level VerbosityLevel, terminal
 		return NewSarifReporter(stdout, stderr, level), nil
 	case "gh-annotations":
 		return NewGHAnnotationsReporter(stdout, stderr, level), nil
+	case "cyclonedx":
+		return NewCycloneDXReporter(stdout, stderr, level), nil
+	case "cyclonedx-vdr":
+		return NewCycloneDXVDRReporter(stdout, stderr, level), nil
+	case "spdx-json":
+		return NewSPDXReporter(stdout, stderr, level, false), nil
+	case "spdx-tag-value":
+		return NewSPDXReporter(stdout, stderr, level, true), nil
 	default:
 		return nil, fmt.Errorf("%v is not a valid format", format)
 	}
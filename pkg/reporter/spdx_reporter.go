@@ -0,0 +1,64 @@
+package reporter
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/google/osv-scanner/internal/output"
+	"github.com/google/osv-scanner/pkg/models"
+)
+
+// SPDXReporter prints the scanned packages and their vulnerabilities as an SPDX document
+// to stdout, in either JSON or the tag-value format. Runtime information will be written to stderr.
+type SPDXReporter struct {
+	hasErrored bool
+	stdout     io.Writer
+	stderr     io.Writer
+	level      VerbosityLevel
+	tagValue   bool
+}
+
+func NewSPDXReporter(stdout io.Writer, stderr io.Writer, level VerbosityLevel, tagValue bool) *SPDXReporter {
+	return &SPDXReporter{
+		stdout:     stdout,
+		stderr:     stderr,
+		level:      level,
+		tagValue:   tagValue,
+		hasErrored: false,
+	}
+}
+
+func (r *SPDXReporter) Errorf(format string, a ...any) {
+	fmt.Fprintf(r.stderr, format, a...)
+	r.hasErrored = true
+}
+
+func (r *SPDXReporter) HasErrored() bool {
+	return r.hasErrored
+}
+
+func (r *SPDXReporter) Warnf(format string, a ...any) {
+	if WarnLevel <= r.level {
+		fmt.Fprintf(r.stderr, format, a...)
+	}
+}
+
+func (r *SPDXReporter) Infof(format string, a ...any) {
+	if InfoLevel <= r.level {
+		fmt.Fprintf(r.stderr, format, a...)
+	}
+}
+
+func (r *SPDXReporter) Verbosef(format string, a ...any) {
+	if VerboseLevel <= r.level {
+		fmt.Fprintf(r.stderr, format, a...)
+	}
+}
+
+func (r *SPDXReporter) PrintResult(vulnResult *models.VulnerabilityResults) error {
+	if r.tagValue {
+		return output.PrintSPDXTagValueReport(vulnResult, r.stdout)
+	}
+
+	return output.PrintSPDXJSONReport(vulnResult, r.stdout)
+}